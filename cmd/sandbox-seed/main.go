@@ -0,0 +1,107 @@
+// Command sandbox-seed copies the core tables of production into a staging or
+// sandbox database with the personal data of every user anonymized.
+//
+// The snapshot is read from SOURCE_DB_CONN and loaded into DB_CONN, which must
+// be empty. Use -out to only write the anonymized snapshot to a file and -in to
+// load a previously written one.
+package main
+
+import (
+	"encoding/gob"
+	"flag"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/joho/godotenv"
+	config "github.com/punchanabu/redrice-backend-go/config"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func main() {
+	out := flag.String("out", "", "write the anonymized snapshot to this file instead of loading it")
+	in := flag.String("in", "", "load the snapshot from this file instead of reading SOURCE_DB_CONN")
+	flag.Parse()
+
+	if err := godotenv.Load(".env"); err != nil {
+		log.Println("Error loading .env file")
+	}
+
+	var snapshot *models.Snapshot
+	if *in != "" {
+		snapshot = readSnapshot(*in)
+	} else {
+		snapshot = takeSnapshot()
+	}
+
+	if *out != "" {
+		writeSnapshot(*out, snapshot)
+		return
+	}
+
+	if strings.ToLower(os.Getenv("APP_ENV")) == "production" {
+		log.Fatal("Refusing to load a snapshot with APP_ENV=production")
+	}
+	if os.Getenv("DB_CONN") == os.Getenv("SOURCE_DB_CONN") {
+		log.Fatal("DB_CONN and SOURCE_DB_CONN point to the same database")
+	}
+
+	db := config.SetupDBConnection()
+	if err := models.LoadSnapshot(db, snapshot); err != nil {
+		log.Fatalf("Failed to load the snapshot: %v", err)
+	}
+	log.Printf("Loaded %d users, %d restaurants, %d reservations, %d comments and %d blackouts",
+		len(snapshot.Users), len(snapshot.Restaurants), len(snapshot.Reservations), len(snapshot.Comments), len(snapshot.Blackouts))
+}
+
+// takeSnapshot reads and anonymizes the source database. The source is never migrated.
+func takeSnapshot() *models.Snapshot {
+	source := os.Getenv("SOURCE_DB_CONN")
+	if source == "" {
+		log.Fatal("SOURCE_DB_CONN is not set")
+	}
+	db, err := gorm.Open(postgres.Open(source), &gorm.Config{})
+	if err != nil {
+		log.Fatal("Failed to connect to the source database!")
+	}
+
+	snapshot, err := models.TakeSnapshot(db)
+	if err != nil {
+		log.Fatalf("Failed to read the source database: %v", err)
+	}
+	if err := snapshot.Anonymize(); err != nil {
+		log.Fatalf("Failed to anonymize the snapshot: %v", err)
+	}
+	return snapshot
+}
+
+func readSnapshot(path string) *models.Snapshot {
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("Failed to read %s: %v", path, err)
+	}
+	defer file.Close()
+
+	var snapshot models.Snapshot
+	if err := gob.NewDecoder(file).Decode(&snapshot); err != nil {
+		log.Fatalf("Failed to parse %s: %v", path, err)
+	}
+	return &snapshot
+}
+
+// writeSnapshot encodes the snapshot with gob, which unlike JSON keeps the
+// fields hidden from API responses such as the timestamps of the rows.
+func writeSnapshot(path string, snapshot *models.Snapshot) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		log.Fatalf("Failed to write %s: %v", path, err)
+	}
+	defer file.Close()
+
+	if err := gob.NewEncoder(file).Encode(snapshot); err != nil {
+		log.Fatalf("Failed to encode the snapshot: %v", err)
+	}
+	log.Printf("Wrote the anonymized snapshot to %s", path)
+}
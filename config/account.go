@@ -0,0 +1,21 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// DefaultAccountDeletionGraceDays is how long a user can undo the deletion of
+// their account when ACCOUNT_DELETION_GRACE_DAYS is not set.
+const DefaultAccountDeletionGraceDays = 14
+
+// AccountDeletionGracePeriod returns how long after DELETE /me the account is
+// actually deleted, read from ACCOUNT_DELETION_GRACE_DAYS.
+func AccountDeletionGracePeriod() time.Duration {
+	days, err := strconv.Atoi(os.Getenv("ACCOUNT_DELETION_GRACE_DAYS"))
+	if err != nil || days < 0 {
+		days = DefaultAccountDeletionGraceDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"os"
+
+	"github.com/punchanabu/redrice-backend-go/models"
+	"gorm.io/gorm"
+)
+
+// DefaultAnalyticsBucket is where the s3 sink writes when ANALYTICS_BUCKET is not set.
+const DefaultAnalyticsBucket = "redrice"
+
+// AnalyticsSink returns where client analytics events are stored, read from
+// ANALYTICS_SINK: db for the analytics_events table, the default, or s3 for
+// the ANALYTICS_BUCKET bucket.
+func AnalyticsSink(db *gorm.DB) models.AnalyticsSink {
+	if os.Getenv("ANALYTICS_SINK") == "s3" {
+		bucket := os.Getenv("ANALYTICS_BUCKET")
+		if bucket == "" {
+			bucket = DefaultAnalyticsBucket
+		}
+		return models.NewS3AnalyticsSink(bucket)
+	}
+	return models.NewDBAnalyticsSink(db)
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ChaosConfig tells how often the fault injection middleware slows down or
+// fails requests. Rates are probabilities between 0 and 1.
+type ChaosConfig struct {
+	Enabled        bool
+	LatencyRate    float64
+	MaxLatency     time.Duration
+	ErrorRate      float64
+	UploadDropRate float64
+}
+
+// Chaos reads the fault injection settings. CHAOS_ENABLED turns it on, the
+// CHAOS_*_RATE variables set how often each fault happens and
+// CHAOS_MAX_LATENCY_MS bounds the added latency. It is always off in
+// production.
+func Chaos() ChaosConfig {
+	enabled, _ := strconv.ParseBool(os.Getenv("CHAOS_ENABLED"))
+	if enabled && strings.ToLower(os.Getenv("APP_ENV")) == "production" {
+		log.Println("CHAOS_ENABLED is ignored in production")
+		enabled = false
+	}
+
+	maxLatency, err := strconv.Atoi(os.Getenv("CHAOS_MAX_LATENCY_MS"))
+	if err != nil || maxLatency <= 0 {
+		maxLatency = 3000
+	}
+
+	return ChaosConfig{
+		Enabled:        enabled,
+		LatencyRate:    chaosRate("CHAOS_LATENCY_RATE", 0.1),
+		MaxLatency:     time.Duration(maxLatency) * time.Millisecond,
+		ErrorRate:      chaosRate("CHAOS_ERROR_RATE", 0.05),
+		UploadDropRate: chaosRate("CHAOS_UPLOAD_DROP_RATE", 0.1),
+	}
+}
+
+func chaosRate(key string, fallback float64) float64 {
+	rate, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil || rate < 0 || rate > 1 {
+		return fallback
+	}
+	return rate
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/punchanabu/redrice-backend-go/models"
+	"github.com/punchanabu/redrice-backend-go/utils"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -15,7 +16,25 @@ func SetupDBConnection() *gorm.DB {
 		log.Fatal("Failed to connect to database!")
 	}
 
-	db.AutoMigrate(&models.User{}, &models.Restaurant{}, &models.Reservation{}, &models.Comment{})
+	// Runs before the unique index on the reviewed reservation is created
+	if err := models.MigrateCommentReservations(db); err != nil {
+		log.Printf("Failed to unlink repeated reviews of reservations: %v", err)
+	}
+	db.AutoMigrate(&models.User{}, &models.Restaurant{}, &models.Reservation{}, &models.Comment{}, &models.RevokedToken{}, &models.Blackout{}, &models.PasswordResetToken{}, &models.QueueEntry{}, &models.CommentTag{}, &models.RestaurantImage{}, &models.CommentPhoto{}, &models.TwoFactorBackupCode{}, &models.PhoneVerification{}, &models.Invitation{}, &models.RestaurantVersion{}, &models.ScheduledChange{}, &models.Session{}, &models.APIKey{}, &models.MagicLinkToken{}, &models.RestaurantTheme{}, &models.SigningKey{}, &models.AuditEntry{}, &models.Incident{}, &models.DataExport{}, &models.UserPreferences{}, &models.Activity{}, &models.Favorite{}, &models.Follow{}, &models.FeedItem{}, &models.ConsentEvent{}, &models.EmailChange{}, &models.EmailTemplate{}, &models.Notification{}, &models.NotificationClaim{}, &models.SLAAlert{}, &models.SearchBoost{}, &models.ExperimentExposure{}, &models.AnalyticsEvent{}, &models.Category{}, &models.WarehouseExport{}, &models.BackfillRun{}, &models.SpecialHours{}, &models.MenuCategory{}, &models.MenuItem{}, &models.AvailabilitySnapshot{}, &models.RestaurantClaim{}, &models.DepositRule{}, &models.UnusedImage{}, &utils.JobRun{})
+	if err := models.MigrateRoles(db); err != nil {
+		log.Printf("Failed to migrate user roles: %v", err)
+	}
+	if err := models.MigrateCuisines(db); err != nil {
+		log.Printf("Failed to move restaurant cuisines to categories: %v", err)
+	}
+	if err := models.MigrateSearch(db); err != nil {
+		log.Printf("Failed to create the restaurant search indexes: %v", err)
+	}
+	if skipped, err := models.MigrateTelephones(db); err != nil {
+		log.Printf("Failed to normalize user telephones: %v", err)
+	} else if skipped > 0 {
+		log.Printf("Left %d user telephones that are not valid or belong to another user unchanged", skipped)
+	}
 
 	return db
 }
\ No newline at end of file
@@ -0,0 +1,20 @@
+package config
+
+import (
+	"os"
+	"strconv"
+)
+
+// DefaultCommissionRate is the share of deposits kept by the platform when
+// COMMISSION_RATE is not set.
+const DefaultCommissionRate = 0.10
+
+// CommissionRate returns the platform commission on deposits as a fraction
+// between 0 and 1, read from COMMISSION_RATE.
+func CommissionRate() float64 {
+	rate, err := strconv.ParseFloat(os.Getenv("COMMISSION_RATE"), 64)
+	if err != nil || rate < 0 || rate > 1 {
+		return DefaultCommissionRate
+	}
+	return rate
+}
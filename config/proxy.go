@@ -0,0 +1,21 @@
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+// TrustedProxies lists the addresses or CIDR ranges of the reverse proxies in
+// front of the API, comma separated in TRUSTED_PROXIES. Only their
+// X-Forwarded-For headers are believed when telling the IP address of a
+// client, e.g. for the rate limiter and the audit trail. When unset no proxy
+// is trusted and the address of the connection is used.
+func TrustedProxies() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// RateLimitConfig sets how many requests a client can make per window. A
+// limit of 0 leaves that kind of client unlimited.
+type RateLimitConfig struct {
+	Enabled        bool
+	Window         time.Duration
+	UserLimit      int
+	APIKeyLimit    int
+	AnonymousLimit int
+}
+
+// RateLimit reads the request limits. Signed in users are counted per
+// account, partner integrations per API key and everyone else per IP address.
+// RATE_LIMIT_ENABLED=false turns the limiter off.
+func RateLimit() RateLimitConfig {
+	enabled, err := strconv.ParseBool(os.Getenv("RATE_LIMIT_ENABLED"))
+	if err != nil {
+		enabled = true
+	}
+
+	return RateLimitConfig{
+		Enabled:        enabled,
+		Window:         time.Duration(rateLimitValue("RATE_LIMIT_WINDOW_SECONDS", 60)) * time.Second,
+		UserLimit:      rateLimitValue("RATE_LIMIT_USER", 300),
+		APIKeyLimit:    rateLimitValue("RATE_LIMIT_API_KEY", 600),
+		AnonymousLimit: rateLimitValue("RATE_LIMIT_ANONYMOUS", 60),
+	}
+}
+
+func rateLimitValue(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value < 0 {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/punchanabu/redrice-backend-go/models"
+)
+
+// Limits restaurants are held to when the SLA_* variables are not set.
+const (
+	DefaultSLAWindowDays             = 7
+	DefaultSLAMaxConfirmationMinutes = 60
+	DefaultSLAMaxDeclineRate         = 0.3
+)
+
+// SLAThresholds returns the rolling window and limits of the restaurant
+// performance alerts, read from SLA_WINDOW_DAYS, SLA_MAX_CONFIRMATION_MINUTES
+// and SLA_MAX_DECLINE_RATE.
+func SLAThresholds() models.SLAThresholds {
+	days, err := strconv.Atoi(os.Getenv("SLA_WINDOW_DAYS"))
+	if err != nil || days < 1 {
+		days = DefaultSLAWindowDays
+	}
+	minutes, err := strconv.Atoi(os.Getenv("SLA_MAX_CONFIRMATION_MINUTES"))
+	if err != nil || minutes < 1 {
+		minutes = DefaultSLAMaxConfirmationMinutes
+	}
+	rate, err := strconv.ParseFloat(os.Getenv("SLA_MAX_DECLINE_RATE"), 64)
+	if err != nil || rate < 0 || rate > 1 {
+		rate = DefaultSLAMaxDeclineRate
+	}
+	return models.SLAThresholds{
+		Window:                 time.Duration(days) * 24 * time.Hour,
+		MaxConfirmationLatency: time.Duration(minutes) * time.Minute,
+		MaxDeclineRate:         rate,
+	}
+}
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+const (
+	SwaggerPublic   = "public"
+	SwaggerAdmin    = "admin"
+	SwaggerDisabled = "off"
+)
+
+// SwaggerMode tells how the API documentation is served. SWAGGER_MODE can be
+// "public", "admin" (admin token required) or "off". When unset the docs are
+// public everywhere except in production, where they are disabled.
+func SwaggerMode() string {
+	switch mode := strings.ToLower(os.Getenv("SWAGGER_MODE")); mode {
+	case SwaggerPublic, SwaggerAdmin, SwaggerDisabled:
+		return mode
+	}
+
+	if strings.ToLower(os.Getenv("APP_ENV")) == "production" {
+		return SwaggerDisabled
+	}
+	return SwaggerPublic
+}
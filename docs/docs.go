@@ -15,55 +15,46 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/register": {
-            "post": {
-                "description": "Creates a new user account with the provided details. Upon successful creation, the user can log in with their credentials.",
-                "consumes": [
-                    "application/json"
+        "/admin/api-keys": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Lists every API key, including revoked and expired ones. Raw keys are never returned.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "authentication"
-                ],
-                "summary": "Register a new user",
-                "parameters": [
-                    {
-                        "description": "Register Credentials",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api.RegisterDetails"
-                        }
-                    }
+                    "admin"
                 ],
+                "summary": "Get API Keys",
                 "responses": {
                     "200": {
-                        "description": "Confirmation of successful registration.",
-                        "schema": {
-                            "$ref": "#/definitions/api.RegisterResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "The request was formatted incorrectly or missing required fields.",
+                        "description": "The API keys, newest first.",
                         "schema": {
-                            "$ref": "#/definitions/api.ErrorResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.APIKey"
+                            }
                         }
                     },
                     "500": {
-                        "description": "Internal server error, unable to process the request.",
+                        "description": "Internal server error while fetching the keys.",
                         "schema": {
-                            "$ref": "#/definitions/api.ErrorResponse"
+                            "$ref": "#/definitions/v1.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/auth/signin": {
+            },
             "post": {
-                "description": "Authenticates a user by their email and password, returning a JWT token for authorized access to protected endpoints if successful.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Creates an API key for a partner integration. The key is sent in the X-API-Key header and only grants the listed scopes: restaurants:read, comments:read and widget:book. A restaurant restricts the key to that restaurant and allowed origins restrict the websites browsers may use it from. Keys with the widget:book scope are embedded in public pages and need both. The raw key is only returned once.",
                 "consumes": [
                     "application/json"
                 ],
@@ -71,130 +62,142 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "authentication"
+                    "admin"
                 ],
-                "summary": "User Login",
+                "summary": "Issue an API Key",
                 "parameters": [
                     {
-                        "description": "Login Credentials",
-                        "name": "credentials",
+                        "description": "Name, scopes and optional expiry",
+                        "name": "key",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api.LoginDetails"
+                            "$ref": "#/definitions/v1.CreateAPIKeyRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "An object containing a JWT token for authentication and a message indicating successful login.",
+                    "201": {
+                        "description": "The raw key and its details.",
                         "schema": {
-                            "$ref": "#/definitions/api.LoginResponse"
+                            "$ref": "#/definitions/v1.CreateAPIKeyResponse"
                         }
                     },
                     "400": {
-                        "description": "The request was formatted incorrectly or missing required fields.",
-                        "schema": {
-                            "$ref": "#/definitions/api.ErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Authentication failed due to invalid login credentials.",
-                        "schema": {
-                            "$ref": "#/definitions/api.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "The specified user was not found in the system.",
-                        "schema": {
-                            "$ref": "#/definitions/api.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error, unable to process the request.",
+                        "description": "Invalid input or unknown scope.",
                         "schema": {
-                            "$ref": "#/definitions/api.ErrorResponse"
+                            "$ref": "#/definitions/v1.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/comments": {
-            "get": {
+        "/admin/api-keys/{id}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves a list of all comments in the system.",
+                "description": "Revokes an API key immediately.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "comments"
+                    "admin"
+                ],
+                "summary": "Revoke an API Key",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "API key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get All Comments",
                 "responses": {
-                    "200": {
-                        "description": "An array of comment objects.",
+                    "204": {
+                        "description": "API key revoked."
+                    },
+                    "400": {
+                        "description": "Invalid API key ID.",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Comment"
-                            }
+                            "$ref": "#/definitions/v1.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error while fetching comments.",
+                    "404": {
+                        "description": "No active API key with this ID.",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/admin/audit-log": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Adds a new comment to the system with customer's opinion. This endpoint requires authentication.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Lists recorded actions, newest first. Impersonation tokens issued to admins and every request made with them are recorded with the admin as actor and the impersonated user.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "reservations"
+                    "admin"
                 ],
-                "summary": "Create a New Comment",
+                "summary": "Get Audit Trail",
                 "parameters": [
                     {
-                        "description": "Your Comment",
-                        "name": "commnet",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Comment"
-                        }
+                        "type": "integer",
+                        "description": "Only actions by this admin or user",
+                        "name": "actorId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only actions taken as this user",
+                        "name": "userId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only actions taken while impersonating",
+                        "name": "impersonated",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, at most 100",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "The created comment's details, including its unique identifier.",
+                    "200": {
+                        "description": "A page of audit entries.",
                         "schema": {
-                            "$ref": "#/definitions/models.Comment"
+                            "$ref": "#/definitions/v1.AuditPage"
                         }
                     },
                     "400": {
-                        "description": "Invalid input format for reservation details.",
+                        "description": "Invalid filter.",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error while creating the reservation.",
+                        "description": "Internal server error while fetching the audit trail.",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
@@ -202,176 +205,162 @@ const docTemplate = `{
                 }
             }
         },
-        "/comments/{id}": {
+        "/admin/backfill-runs": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves details of a single commnet by its unique identifier.",
+                "description": "Lists the runs of every backfill, newest first, with their progress.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "comments"
+                    "admin"
                 ],
-                "summary": "Get a Single Comment",
+                "summary": "Get Backfill Runs",
                 "parameters": [
                     {
                         "type": "integer",
-                        "format": "int64",
-                        "description": "Comment ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Runs per page, at most 100",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "The details of the comment including ID, DateTime, Detail, UserID, User, RestaurantID, and Restaurant.",
-                        "schema": {
-                            "$ref": "#/definitions/models.Comment"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid comment ID format.",
+                        "description": "One page of runs.",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/v1.BackfillRunPage"
                         }
                     },
-                    "404": {
-                        "description": "Comment not found with the specified ID.",
+                    "500": {
+                        "description": "Internal server error while fetching the runs.",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/admin/backfill-runs/{id}/pause": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Updates the details of an existing comment identified by its ID. This endpoint requires authentication.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Stops a running backfill after its current batch. It can be resumed later.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "comments"
+                    "admin"
                 ],
-                "summary": "Update a Comment",
+                "summary": "Pause a Backfill Run",
                 "parameters": [
                     {
                         "type": "integer",
                         "format": "int64",
-                        "description": "Comment ID",
+                        "description": "Run ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Updated comment Details",
-                        "name": "comment",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Comment"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "The updated comment's details.",
+                        "description": "The paused run.",
                         "schema": {
-                            "$ref": "#/definitions/models.Comment"
+                            "$ref": "#/definitions/models.BackfillRun"
                         }
                     },
                     "400": {
-                        "description": "Invalid input format for comment details or invalid comment ID.",
+                        "description": "Invalid run ID.",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Comment not found with the specified ID.",
+                        "description": "Run not found.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The run is not running.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while pausing the run.",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/admin/backfill-runs/{id}/resume": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Removes a comment from the system. This endpoint requires authentication.",
+                "description": "Continues a paused or failed backfill run from its last completed batch.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "comments"
+                    "admin"
                 ],
-                "summary": "Delete a Comment",
+                "summary": "Resume a Backfill Run",
                 "parameters": [
                     {
                         "type": "integer",
                         "format": "int64",
-                        "description": "Comment ID",
+                        "description": "Run ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Comment successfully deleted, no content to return."
+                    "200": {
+                        "description": "The resumed run.",
+                        "schema": {
+                            "$ref": "#/definitions/models.BackfillRun"
+                        }
                     },
                     "400": {
-                        "description": "Invalid comment ID format.",
+                        "description": "Invalid run ID.",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Comment not found with the specified ID.",
+                        "description": "Run not found.",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/me": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Retrieves the details of the currently authenticated user.",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "user"
-                ],
-                "summary": "Get my profile",
-                "responses": {
-                    "200": {
-                        "description": "The details of the currently authenticated user.",
+                    },
+                    "409": {
+                        "description": "The run is neither paused nor failed.",
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "$ref": "#/definitions/v1.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "User not found.",
+                    "500": {
+                        "description": "Internal server error while resuming the run.",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
@@ -379,46 +368,42 @@ const docTemplate = `{
                 }
             }
         },
-        "/reservations": {
+        "/admin/backfills": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves a list of all reservations in the system.",
+                "description": "Lists the backfills that compute a field on the existing rows of a table.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "reservations"
+                    "admin"
                 ],
-                "summary": "Get All Reservations",
+                "summary": "Get Backfills",
                 "responses": {
                     "200": {
-                        "description": "An array of reservation objects.",
+                        "description": "The backfills.",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Reservation"
+                                "$ref": "#/definitions/models.Backfill"
                             }
                         }
-                    },
-                    "500": {
-                        "description": "Internal server error while fetching reservations.",
-                        "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
-                        }
                     }
                 }
-            },
+            }
+        },
+        "/admin/backfills/{key}/runs": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Adds a new reservation to the system with the provided details. This endpoint requires authentication.",
+                "description": "Starts a run of the backfill, processed in the background in batches of rows by ID with a pause between them. The run starts within a minute and resumes from its last batch after a restart. A backfill has at most one running or paused run.",
                 "consumes": [
                     "application/json"
                 ],
@@ -426,35 +411,53 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "reservations"
+                    "admin"
                 ],
-                "summary": "Create a New Reservation",
+                "summary": "Start a Backfill",
                 "parameters": [
                     {
-                        "description": "Reservation Details",
-                        "name": "reservation",
+                        "type": "string",
+                        "description": "Backfill key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Batch size and pause",
+                        "name": "run",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Reservation"
+                            "$ref": "#/definitions/v1.BackfillRunRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "The created reservation's details, including its unique identifier.",
+                    "202": {
+                        "description": "The started run.",
                         "schema": {
-                            "$ref": "#/definitions/models.Reservation"
+                            "$ref": "#/definitions/models.BackfillRun"
                         }
                     },
                     "400": {
-                        "description": "Invalid input format for reservation details.",
+                        "description": "Invalid input format.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown backfill.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The backfill already has a running or paused run.",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error while creating the reservation.",
+                        "description": "Internal server error while starting the run.",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
@@ -462,59 +465,71 @@ const docTemplate = `{
                 }
             }
         },
-        "/reservations/{id}": {
-            "get": {
+        "/admin/categories": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves details of a single reservation by its unique identifier.",
+                "description": "Adds a cuisine or tag restaurants can be listed under.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "reservations"
+                    "admin"
                 ],
-                "summary": "Get a Single Reservation",
+                "summary": "Create Category",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "format": "int64",
-                        "description": "Reservation ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Category",
+                        "name": "category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.CategoryRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "The details of the reservation including ID, DateTime, UserID, User, RestaurantID, and Restaurant.",
+                    "201": {
+                        "description": "The created category.",
                         "schema": {
-                            "$ref": "#/definitions/models.Reservation"
+                            "$ref": "#/definitions/models.Category"
                         }
                     },
                     "400": {
-                        "description": "Invalid reservation ID format.",
+                        "description": "Invalid input format or slug.",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Reservation not found with the specified ID.",
+                    "409": {
+                        "description": "A category with this slug already exists.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while creating the category.",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/admin/categories/{id}": {
             "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Updates the details of an existing reservation identified by its ID. This endpoint requires authentication.",
+                "description": "Renames a category. Restaurants listed under it stay listed under it.",
                 "consumes": [
                     "application/json"
                 ],
@@ -522,43 +537,55 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "reservations"
+                    "admin"
                 ],
-                "summary": "Update a Reservation",
+                "summary": "Update Category",
                 "parameters": [
                     {
                         "type": "integer",
                         "format": "int64",
-                        "description": "Reservation ID",
+                        "description": "Category ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated Reservation Details",
-                        "name": "reservation",
+                        "description": "Category",
+                        "name": "category",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Reservation"
+                            "$ref": "#/definitions/v1.CategoryRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "The updated reservation's details.",
+                        "description": "The updated category.",
                         "schema": {
-                            "$ref": "#/definitions/models.Reservation"
+                            "$ref": "#/definitions/models.Category"
                         }
                     },
                     "400": {
-                        "description": "Invalid input format for reservation details or invalid reservation ID.",
+                        "description": "Invalid input format, slug or category ID.",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Reservation not found with the specified ID.",
+                        "description": "Category not found.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "A category with this slug already exists.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while updating the category.",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
@@ -571,19 +598,16 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Removes a reservation from the system by its unique identifier. This endpoint requires authentication.",
-                "produces": [
-                    "application/json"
-                ],
+                "description": "Deletes a category and removes it from every restaurant listed under it.",
                 "tags": [
-                    "reservations"
+                    "admin"
                 ],
-                "summary": "Delete a Reservation",
+                "summary": "Delete Category",
                 "parameters": [
                     {
                         "type": "integer",
                         "format": "int64",
-                        "description": "Reservation ID",
+                        "description": "Category ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -591,16 +615,22 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "204": {
-                        "description": "Reservation successfully deleted, no content to return."
+                        "description": "Category deleted."
                     },
                     "400": {
-                        "description": "Invalid reservation ID format.",
+                        "description": "Invalid category ID.",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Reservation not found with the specified ID.",
+                        "description": "Category not found.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while deleting the category.",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
@@ -608,82 +638,120 @@ const docTemplate = `{
                 }
             }
         },
-        "/restaurants": {
+        "/admin/claims": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves a list of all restaurants in the system.",
+                "description": "Lists the restaurant claims oldest first, only the ones with the status when given, with links to their proof documents valid for 15 minutes.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "restaurants"
+                    "admin"
+                ],
+                "summary": "Get Restaurant Claims",
+                "parameters": [
+                    {
+                        "enum": [
+                            "pending",
+                            "approved",
+                            "rejected"
+                        ],
+                        "type": "string",
+                        "description": "Review status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Claims per page, at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get All Restaurants",
                 "responses": {
                     "200": {
-                        "description": "An array of restaurant objects.",
+                        "description": "One page of claims.",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Restaurant"
-                            }
+                            "$ref": "#/definitions/v1.RestaurantClaimPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid status.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error while fetching restaurants.",
+                        "description": "Internal server error while fetching the claims.",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/admin/claims/{id}/approve": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Adds a new restaurant to the system with the provided details.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Makes the claimant the owner of the restaurant and gives the restaurant the verified badge. Claimants already managing another restaurant cannot be approved. The accounts managing the restaurant before become customers and the other pending claims for the restaurant are rejected. The claimant and the former owners have to log in again for their new roles to apply.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "restaurants"
+                    "admin"
                 ],
-                "summary": "Create a New Restaurant",
+                "summary": "Approve a Restaurant Claim",
                 "parameters": [
                     {
-                        "description": "Restaurant Registration Details",
-                        "name": "restaurant",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Restaurant"
-                        }
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Claim ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "The created restaurant's details, including its unique identifier.",
+                    "200": {
+                        "description": "The approved claim.",
                         "schema": {
-                            "$ref": "#/definitions/models.Restaurant"
+                            "$ref": "#/definitions/models.RestaurantClaim"
                         }
                     },
                     "400": {
-                        "description": "Invalid input format for restaurant details.",
+                        "description": "Invalid claim ID or the claimant is an admin.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Claim not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The claim was already reviewed or the claimant manages another restaurant.",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error while creating the restaurant.",
+                        "description": "Internal server error while approving the claim.",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
@@ -691,59 +759,119 @@ const docTemplate = `{
                 }
             }
         },
-        "/restaurants/{id}": {
-            "get": {
+        "/admin/claims/{id}/reject": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves details of a single restaurant by its unique identifier.",
+                "description": "Closes a claim without changing the restaurant. The reason is shown to the claimant.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "restaurants"
+                    "admin"
                 ],
-                "summary": "Get a Single Restaurant",
+                "summary": "Reject a Restaurant Claim",
                 "parameters": [
                     {
                         "type": "integer",
                         "format": "int64",
-                        "description": "Restaurant ID",
+                        "description": "Claim ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Reason of the rejection",
+                        "name": "rejection",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.ClaimRejectionRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "The details of the restaurant including ID, name, location, and other relevant information.",
+                        "description": "The rejected claim.",
                         "schema": {
-                            "$ref": "#/definitions/models.Restaurant"
+                            "$ref": "#/definitions/models.RestaurantClaim"
                         }
                     },
                     "400": {
-                        "description": "Invalid restaurant ID format.",
+                        "description": "Invalid claim ID or input format.",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Restaurant not found with the specified ID.",
+                        "description": "Claim not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The claim was already reviewed.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while rejecting the claim.",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/admin/email-templates": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the subject and body of every email the platform sends, with the variables they can use. Templates that were never edited show the built-in copy.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get the Email Templates",
+                "responses": {
+                    "200": {
+                        "description": "The email templates.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.EmailTemplate"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while fetching the templates.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/email-templates/{key}": {
             "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Updates the details of an existing restaurant identified by its ID.",
+                "description": "Replaces the subject and body of an email. Both are Go templates, e.g. \"Hi {{.name}}\", and may only use the variables of the template. The new copy is used for the next email sent, no deploy needed.",
                 "consumes": [
                     "application/json"
                 ],
@@ -751,43 +879,61 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "restaurants"
+                    "admin"
                 ],
-                "summary": "Update a Restaurant",
+                "summary": "Update an Email Template",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "format": "int64",
-                        "description": "Restaurant ID",
-                        "name": "id",
+                        "enum": [
+                            "daily_digest",
+                            "data_export_ready",
+                            "email_change_confirm",
+                            "email_changed",
+                            "magic_link",
+                            "password_reset",
+                            "receipt",
+                            "reservation_status",
+                            "restaurant_announcement",
+                            "sla_alert",
+                            "staff_invitation"
+                        ],
+                        "type": "string",
+                        "description": "Template key",
+                        "name": "key",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated Restaurant Details",
-                        "name": "restaurant",
+                        "description": "Subject and body",
+                        "name": "template",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.Restaurant"
+                            "$ref": "#/definitions/v1.EmailTemplateRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "The updated restaurant's details.",
+                        "description": "The updated template.",
                         "schema": {
-                            "$ref": "#/definitions/models.Restaurant"
+                            "$ref": "#/definitions/models.EmailTemplate"
                         }
                     },
                     "400": {
-                        "description": "Invalid input format for restaurant details or invalid restaurant ID.",
+                        "description": "Invalid input format or the template does not render.",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Restaurant not found with the specified ID.",
+                        "description": "Email template not found.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while saving the template.",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
@@ -800,36 +946,51 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Removes a restaurant from the system by its unique identifier.",
+                "description": "Drops the edited copy of an email so the built-in one is sent again.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "restaurants"
+                    "admin"
                 ],
-                "summary": "Delete a Restaurant",
+                "summary": "Reset an Email Template",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "format": "int64",
-                        "description": "Restaurant ID",
-                        "name": "id",
+                        "enum": [
+                            "daily_digest",
+                            "data_export_ready",
+                            "email_change_confirm",
+                            "email_changed",
+                            "magic_link",
+                            "password_reset",
+                            "receipt",
+                            "reservation_status",
+                            "restaurant_announcement",
+                            "sla_alert",
+                            "staff_invitation"
+                        ],
+                        "type": "string",
+                        "description": "Template key",
+                        "name": "key",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "Restaurant successfully deleted, no content to return."
+                    "200": {
+                        "description": "The built-in template.",
+                        "schema": {
+                            "$ref": "#/definitions/models.EmailTemplate"
+                        }
                     },
-                    "400": {
-                        "description": "Invalid restaurant ID format.",
+                    "404": {
+                        "description": "Email template not found.",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Restaurant not found with the specified ID.",
+                    "500": {
+                        "description": "Internal server error while resetting the template.",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
@@ -837,48 +998,69 @@ const docTemplate = `{
                 }
             }
         },
-        "/restaurants/{restaurantID}/comments": {
-            "get": {
+        "/admin/email-templates/{key}/preview": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves a list of comments associated with a specific restaurant.",
+                "description": "Renders an email with sample values for its variables. Pass a subject or body to preview a draft before saving it, and data to try other values.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "comments"
+                    "admin"
                 ],
-                "summary": "Get Reataurant's Comments",
+                "summary": "Preview an Email Template",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Reataurant ID",
-                        "name": "restaurantId",
+                        "enum": [
+                            "daily_digest",
+                            "data_export_ready",
+                            "email_change_confirm",
+                            "email_changed",
+                            "magic_link",
+                            "password_reset",
+                            "receipt",
+                            "reservation_status",
+                            "restaurant_announcement",
+                            "sla_alert",
+                            "staff_invitation"
+                        ],
+                        "type": "string",
+                        "description": "Template key",
+                        "name": "key",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Draft and values",
+                        "name": "preview",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/v1.EmailTemplatePreviewRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "An array of comment objects for the restaurant.",
+                        "description": "The rendered email.",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Comment"
-                            }
+                            "$ref": "#/definitions/models.RenderedEmail"
                         }
                     },
                     "400": {
-                        "description": "Invalid reataurant ID format.",
+                        "description": "Invalid input format or the template does not render.",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Comments not found for the specified restaurant ID.",
+                        "description": "Email template not found.",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
@@ -886,46 +1068,72 @@ const docTemplate = `{
                 }
             }
         },
-        "/users": {
-            "get": {
+        "/admin/impersonate/{userId}": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves a list of all users in the system.",
-                "produces": [
+                "description": "Issues a token acting as the user so support staff can reproduce a reported problem. The token lasts 30 minutes by default and at most 60, and every request made with it is recorded in the audit trail under the admin. Admin accounts cannot be impersonated.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "admin"
+                ],
+                "summary": "Impersonate a User",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "How many minutes the token lasts",
+                        "name": "details",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/api.ImpersonateDetails"
+                        }
+                    }
                 ],
-                "summary": "Get All Users",
                 "responses": {
                     "200": {
-                        "description": "An array of user objects.",
+                        "description": "The impersonation token.",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.User"
-                            }
+                            "$ref": "#/definitions/api.ImpersonateResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal server error while fetching users.",
+                    "400": {
+                        "description": "Invalid user ID or duration, or the user cannot be impersonated.",
                         "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/admin/incidents": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Adds a new user to the system with the provided details.",
+                "description": "Posts an incident to the public status page. Setting the status to resolved records when it was resolved.",
                 "consumes": [
                     "application/json"
                 ],
@@ -933,35 +1141,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "admin"
                 ],
-                "summary": "Create a New User",
+                "summary": "Create an Incident",
                 "parameters": [
                     {
-                        "description": "User Registration Details",
-                        "name": "user",
+                        "description": "Incident details",
+                        "name": "incident",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "$ref": "#/definitions/v1.IncidentRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "The created user's details, including their unique identifier.",
+                        "description": "The created incident.",
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "$ref": "#/definitions/models.Incident"
                         }
                     },
                     "400": {
-                        "description": "Invalid input format for user details.",
-                        "schema": {
-                            "$ref": "#/definitions/v1.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error while creating the user.",
+                        "description": "Invalid input, status or impact.",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
@@ -969,145 +1171,202 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{id}": {
-            "get": {
+        "/admin/incidents/{id}": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves details of a single user by their unique identifier.",
+                "description": "Replaces the details of an incident, e.g. to post a new status. Setting the status to resolved records when it was resolved.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "admin"
                 ],
-                "summary": "Get a Single User",
+                "summary": "Update an Incident",
                 "parameters": [
                     {
                         "type": "integer",
                         "format": "int64",
-                        "description": "User ID",
+                        "description": "Incident ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Incident details",
+                        "name": "incident",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.IncidentRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "The details of the user including ID, name, email, telephone, and role.",
+                        "description": "The updated incident.",
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "$ref": "#/definitions/models.Incident"
                         }
                     },
                     "400": {
-                        "description": "Invalid user ID format.",
+                        "description": "Invalid input, incident ID, status or impact.",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "User not found with the specified ID.",
+                        "description": "Incident not found.",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
                     }
                 }
             },
-            "put": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Updates the details of an existing user identified by their ID.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Removes an incident from the status page, e.g. one posted by mistake.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "admin"
                 ],
-                "summary": "Update a User",
+                "summary": "Delete an Incident",
                 "parameters": [
                     {
                         "type": "integer",
                         "format": "int64",
-                        "description": "User ID",
+                        "description": "Incident ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Incident deleted."
                     },
-                    {
-                        "description": "Updated User Details",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
+                    "400": {
+                        "description": "Invalid incident ID.",
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "$ref": "#/definitions/v1.ErrorResponse"
                         }
                     }
+                }
+            }
+        },
+        "/admin/notifications/stats": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Counts per channel the notifications created in the period: sent, waiting for a digest, and suppressed as repeats of an identical notification sent to the same user within the last hour. The period defaults to the current month.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get Notification Stats",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First day of the period in YYYY-MM-DD format",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day of the period in YYYY-MM-DD format",
+                        "name": "to",
+                        "in": "query"
+                    }
                 ],
                 "responses": {
                     "200": {
-                        "description": "The updated user's details.",
+                        "description": "The counts of every channel.",
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.NotificationStats"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Invalid input format for user details or invalid user ID.",
+                        "description": "Invalid period.",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error while updating the user.",
+                        "description": "Internal server error while counting the notifications.",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/admin/payouts": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Removes a user from the system by their unique identifier.",
+                "description": "Lists, per restaurant and currency, the deposits of completed reservations in the period and splits them into the platform commission and the payout owed to the owner. The period defaults to the current month.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "admin"
                 ],
-                "summary": "Delete a User",
+                "summary": "Get Payouts",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "format": "int64",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "string",
+                        "description": "First day of the period in YYYY-MM-DD format",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day of the period in YYYY-MM-DD format",
+                        "name": "to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "User successfully deleted, no content to return."
+                    "200": {
+                        "description": "The payout summary of every restaurant with deposits.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PayoutSummary"
+                            }
+                        }
                     },
                     "400": {
-                        "description": "Invalid user ID format.",
+                        "description": "Invalid period.",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Internal server error while deleting the user.",
+                        "description": "Internal server error while computing the payouts.",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
@@ -1115,214 +1374,12780 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{userId}/reservations": {
+        "/admin/restaurants": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieves a list of reservations associated with a specific user.",
+                "description": "Lists the restaurants with the status, e.g. the ones pending approval, the longest waiting first.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "reservations"
+                    "admin"
                 ],
-                "summary": "Get User's Reservations",
+                "summary": "Get Restaurants by Status",
                 "parameters": [
+                    {
+                        "enum": [
+                            "draft",
+                            "pending",
+                            "active",
+                            "hidden",
+                            "suspended"
+                        ],
+                        "type": "string",
+                        "description": "Status of the restaurants, defaults to pending",
+                        "name": "status",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "User ID",
-                        "name": "userId",
-                        "in": "path",
-                        "required": true
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Restaurants per page, at most 100",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "An array of reservation objects for the user.",
+                        "description": "One page of restaurants.",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Reservation"
-                            }
+                            "$ref": "#/definitions/v1.RestaurantPage"
                         }
                     },
                     "400": {
-                        "description": "Invalid user ID format.",
+                        "description": "Invalid status.",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Reservations not found for the specified user ID.",
+                    "500": {
+                        "description": "Internal server error while fetching the restaurants.",
                         "schema": {
                             "$ref": "#/definitions/v1.ErrorResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "api.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "error": {
-                    "type": "string",
-                    "example": "Error message"
+        },
+        "/admin/restaurants/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Uploads a CSV file of restaurants with a header row naming its columns: name, address, telephone, description, facebook, instagram, openTime, closeTime, priceRange, latitude, longitude, timezone, categories as comma-separated slugs and imageUrl. Only name is required, telephone numbers are stored in the E.164 format. Every row is validated and the valid ones are saved together, the response reports the result of every row with the data quality warnings of the imported ones. Imported restaurants start as drafts unless another status is given. With dry_run=true the rows are validated the same way but nothing is saved and the results carry no restaurant IDs.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Import Restaurants from CSV",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV file of restaurants",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "draft",
+                            "pending",
+                            "active"
+                        ],
+                        "type": "string",
+                        "description": "Status of the imported restaurants, draft by default",
+                        "name": "status",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only validate the CSV file",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The per-row import results.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.RestaurantImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid CSV file, header row or status.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while saving the restaurants, none of them were saved.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
                 }
             }
         },
-        "api.LoginDetails": {
-            "type": "object",
-            "properties": {
-                "email": {
-                    "type": "string",
-                    "example": "user@example.com"
-                },
-                "password": {
-                    "type": "string",
-                    "example": "password123"
+        "/admin/restaurants/performance": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Measures, per restaurant, how many reservations made in the period it confirmed or declined, the share it declined and the average time it took to answer. The period defaults to the current month.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get Restaurant Performance",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First day of the period in YYYY-MM-DD format",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day of the period in YYYY-MM-DD format",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The performance of every restaurant that answered a reservation.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.RestaurantPerformance"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid period.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while measuring the restaurants.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
                 }
             }
         },
-        "api.LoginResponse": {
-            "type": "object",
+        "/admin/restaurants/quality": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the restaurants whose listing scores at most maxScore, lowest first, with the checks they fail.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get Low Quality Restaurants",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Highest score listed, from 0 to 100, 60 by default",
+                        "name": "maxScore",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Restaurants per page, at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "One page of restaurants with their score.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.RestaurantQualityPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid maxScore.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while scoring the restaurants.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/restaurants/{id}/status": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Moves a restaurant through its approval lifecycle: draft to pending, pending to active or back to draft, active to hidden or suspended, hidden to active or suspended, and suspended to active. Only active restaurants are listed, searched and bookable. The reason is shown to the owner.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Change the Status of a Restaurant",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New status and its reason",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.RestaurantStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The restaurant with its new status.",
+                        "schema": {
+                            "$ref": "#/definitions/models.RestaurantResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid restaurant ID, input format or status.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The restaurant cannot move from its current status to the new one.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while changing the status.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/routes": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists every endpoint together with the role it requires: public, user, restaurant_owner or admin. Restaurant owners can only use the restaurant_owner routes on their own restaurant. Routes that also accept partner API keys list the required scope.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get Route Access Table",
+                "responses": {
+                    "200": {
+                        "description": "The route access table.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/routers.RouteAccess"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/search-boosts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the boosts added to the rank of the restaurants that match their condition in GET /restaurants/search. For scale, the text rank plus the name similarity of a match lies between 0 and 2.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get the Search Boosts",
+                "responses": {
+                    "200": {
+                        "description": "The search boosts.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SearchBoost"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while fetching the boosts.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/search-boosts/{key}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sets the weight of a boost, between -10 and 10. The next search uses it, no deploy needed. A weight of 0 turns the boost off, a negative one buries the restaurants.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Update a Search Boost",
+                "parameters": [
+                    {
+                        "enum": [
+                            "photos",
+                            "recent_activity",
+                            "verified"
+                        ],
+                        "type": "string",
+                        "description": "Boost key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Weight",
+                        "name": "boost",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.SearchBoostRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The search boosts after the change.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SearchBoost"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input format or weight.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Search boost not found.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while saving the boost.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/sla-alerts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the alerts raised when a restaurant took too long on average to confirm or decline reservations, or declined too many of them, over the rolling window. Only open alerts are listed unless all is true. Admins are emailed when an alert opens.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get Restaurant SLA Alerts",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Include the resolved alerts",
+                        "name": "all",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Alerts per page, at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "One page of alerts, newest first.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.SLAAlertPage"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while fetching the alerts.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{id}/ban": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Suspends the account until the given time or indefinitely. Requests and logins of the user are rejected with code ACCOUNT_SUSPENDED and the reason, and their pending reservations are cancelled. The suspension is recorded in the audit trail.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Ban a User",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason and optional end of the suspension",
+                        "name": "ban",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.BanRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The suspended user and how many reservations were cancelled.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.BanResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID, missing reason or an end in the past.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while suspending the user.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{id}/unban": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lifts the suspension of the account. Reservations cancelled by the ban are not restored. The change is recorded in the audit trail.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Unban a User",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The user, no longer suspended.",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID format.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while lifting the suspension.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/warehouse/manifest": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Describes the datasets exported every night for the analytics team, with the columns of the current schema version, and lists the CSV files exported for the days of the period. Days are in UTC and files are partitioned by date under warehouse/v\u003cschemaVersion\u003e/\u003cdataset\u003e/date=\u003cday\u003e/. The period defaults to the current month.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get the Data Warehouse Manifest",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First day of the period in YYYY-MM-DD format",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day of the period in YYYY-MM-DD format",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The datasets and the files exported in the period.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.WarehouseManifest"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid period.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while fetching the exports.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/2fa": {
+            "post": {
+                "description": "Second login step for users with two-factor authentication. Exchanges the challenge token returned by the login together with a TOTP or backup code for a session token. A TOTP code is only accepted once, and after 5 wrong codes in a row the second factor of the account is locked for 15 minutes.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "authentication"
+                ],
+                "summary": "Complete Two-Factor Login",
+                "parameters": [
+                    {
+                        "description": "Challenge token and code",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.TwoFactorLoginDetails"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "An object containing a JWT token for authentication.",
+                        "schema": {
+                            "$ref": "#/definitions/api.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "The request was formatted incorrectly or missing required fields.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "The challenge token is invalid or expired, or the code is wrong.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many wrong codes, the second factor is locked.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/accept-invitation": {
+            "post": {
+                "description": "Creates the account of an invited staff member using the token received by email. The account gets the email and restaurant of the invitation.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "authentication"
+                ],
+                "summary": "Accept a Staff Invitation",
+                "parameters": [
+                    {
+                        "description": "Invitation token and account details",
+                        "name": "invitation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.AcceptInvitationDetails"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "An object containing a JWT token for authentication.",
+                        "schema": {
+                            "$ref": "#/definitions/api.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "The token is invalid, expired or already used, the input is malformed, or the password does not meet the requirements.",
+                        "schema": {
+                            "$ref": "#/definitions/api.PasswordPolicyErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The email or telephone belongs to another user.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/apple": {
+            "post": {
+                "description": "Verifies the identity token returned by Sign in with Apple and exchanges it for our JWT. Users are identified by their Apple subject identifier and linked by email to existing accounts.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "authentication"
+                ],
+                "summary": "Sign in with Apple",
+                "parameters": [
+                    {
+                        "description": "Apple identity token",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.AppleLoginDetails"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "An object containing a JWT token for authentication.",
+                        "schema": {
+                            "$ref": "#/definitions/api.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "The request was formatted incorrectly or missing required fields.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "The identity token is invalid, expired or was issued for another app.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error, unable to process the request.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/confirm-email": {
+            "post": {
+                "description": "Applies the email change of the confirmation link sent to the new address. The previous address is told about the change and every existing session of the user is signed out.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "authentication"
+                ],
+                "summary": "Confirm an Email Change",
+                "parameters": [
+                    {
+                        "description": "Token from the confirmation link",
+                        "name": "token",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.EmailChangeConfirmDetails"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The email has been changed.",
+                        "schema": {
+                            "$ref": "#/definitions/api.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "The link is invalid, expired or already used.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The email has been taken by another user since the change was requested.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error, unable to process the request.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/facebook": {
+            "post": {
+                "description": "Exchanges a Facebook user access token obtained by the client SDK for our JWT. The user is created on first login. Facebook does not tell whether the email was verified, so an existing account with the same email is not linked and the login is refused with 409.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "authentication"
+                ],
+                "summary": "Sign in with Facebook",
+                "parameters": [
+                    {
+                        "description": "Facebook access token",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.FacebookLoginDetails"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "An object containing a JWT token for authentication.",
+                        "schema": {
+                            "$ref": "#/definitions/api.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "The request was formatted incorrectly or missing required fields.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "The Facebook access token is invalid or was issued for another app.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "An account with the email of the Facebook profile already exists.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error, unable to process the request.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/forgot-password": {
+            "post": {
+                "description": "Sends an email containing a single-use password reset link to the given address. The response is the same whether or not the email is registered.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "authentication"
+                ],
+                "summary": "Request a Password Reset",
+                "parameters": [
+                    {
+                        "description": "Account email",
+                        "name": "email",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.ForgotPasswordDetails"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The reset email has been sent if the account exists.",
+                        "schema": {
+                            "$ref": "#/definitions/api.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "The request was formatted incorrectly or missing required fields.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error, unable to process the request.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/google": {
+            "get": {
+                "description": "Redirects the browser to Google's consent screen. Google redirects back to the callback endpoint afterwards.",
+                "tags": [
+                    "authentication"
+                ],
+                "summary": "Sign in with Google",
+                "responses": {
+                    "307": {
+                        "description": "Redirect to Google."
+                    },
+                    "500": {
+                        "description": "Google login is not configured.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/google/callback": {
+            "get": {
+                "description": "Exchanges the authorization code returned by Google, creates or links the user by email and returns the same JWT as the normal login.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "authentication"
+                ],
+                "summary": "Google Login Callback",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authorization code from Google",
+                        "name": "code",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "State issued by /auth/google",
+                        "name": "state",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "An object containing a JWT token for authentication.",
+                        "schema": {
+                            "$ref": "#/definitions/api.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid state or authorization code.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "The Google account email is not verified.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error, unable to process the request.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/logout": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revokes the JWT used for the request so it cannot be used again, even before it expires.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "authentication"
+                ],
+                "summary": "User Logout",
+                "responses": {
+                    "200": {
+                        "description": "Confirmation that the token has been revoked.",
+                        "schema": {
+                            "$ref": "#/definitions/api.LogoutResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing, invalid or already revoked token.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error, unable to revoke the token.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/magic-link": {
+            "post": {
+                "description": "Sends an email with a single-use link that signs the user in without a password. The response is the same whether or not the email is registered.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "authentication"
+                ],
+                "summary": "Request a Magic Login Link",
+                "parameters": [
+                    {
+                        "description": "Account email",
+                        "name": "email",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.MagicLinkDetails"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The login link has been sent if the account exists.",
+                        "schema": {
+                            "$ref": "#/definitions/api.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "The request was formatted incorrectly or missing required fields.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error, unable to process the request.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/magic-link/verify": {
+            "post": {
+                "description": "Exchanges the token of a magic login link for a JWT token. Users with two-factor authentication receive a challenge token to complete at /auth/2fa instead.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "authentication"
+                ],
+                "summary": "Sign In with a Magic Link",
+                "parameters": [
+                    {
+                        "description": "Token from the login link",
+                        "name": "token",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.MagicLinkVerifyDetails"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "An object containing a JWT token for authentication.",
+                        "schema": {
+                            "$ref": "#/definitions/api.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "The request was formatted incorrectly or missing required fields.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "The link is invalid, expired or already used.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/register": {
+            "post": {
+                "description": "Creates a new user account with the provided details. Upon successful creation, the user can log in with their credentials. The telephone is stored in the E.164 format, numbers without a country code are taken to be Thai.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "authentication"
+                ],
+                "summary": "Register a new user",
+                "parameters": [
+                    {
+                        "description": "Register Credentials",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.RegisterDetails"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "reCAPTCHA or Turnstile token, required when CAPTCHA is enabled",
+                        "name": "X-Captcha-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Confirmation of successful registration.",
+                        "schema": {
+                            "$ref": "#/definitions/api.RegisterResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "The request was formatted incorrectly, missing required fields, the telephone is not valid, or the password does not meet the requirements.",
+                        "schema": {
+                            "$ref": "#/definitions/api.PasswordPolicyErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The email or telephone belongs to another user.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error, unable to process the request.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/reset-password": {
+            "post": {
+                "description": "Sets a new password using the token received by email. Tokens expire after one hour and can only be used once. Every existing session of the user is signed out.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "authentication"
+                ],
+                "summary": "Reset Password",
+                "parameters": [
+                    {
+                        "description": "Reset token and new password",
+                        "name": "reset",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.ResetPasswordDetails"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The password has been changed.",
+                        "schema": {
+                            "$ref": "#/definitions/api.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "The token is invalid, expired or already used, the input is malformed, or the password does not meet the requirements.",
+                        "schema": {
+                            "$ref": "#/definitions/api.PasswordPolicyErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/signin": {
+            "post": {
+                "description": "Authenticates a user by their email and password, returning a JWT token for authorized access to protected endpoints if successful. Users with two-factor authentication receive a challenge token to complete at /auth/2fa instead.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "authentication"
+                ],
+                "summary": "User Login",
+                "parameters": [
+                    {
+                        "description": "Login Credentials",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.LoginDetails"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "reCAPTCHA or Turnstile token, required when CAPTCHA is enabled",
+                        "name": "X-Captcha-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "An object containing a JWT token for authentication and a message indicating successful login.",
+                        "schema": {
+                            "$ref": "#/definitions/api.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "The request was formatted incorrectly or missing required fields.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Authentication failed due to invalid login credentials.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "The specified user was not found in the system.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error, unable to process the request.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories": {
+            "get": {
+                "description": "Lists the cuisines and tags restaurants can be listed under, by name. The slug filters the restaurant list.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Get Categories",
+                "responses": {
+                    "200": {
+                        "description": "The categories.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Category"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while fetching the categories.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/comment-photos/{id}/approval": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Approves a review photo so it shows in the gallery, or hides it again. Only the owner of the restaurant or an admin can moderate photos.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "photos"
+                ],
+                "summary": "Approve or Reject a Comment Photo",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Comment photo ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Approval decision",
+                        "name": "approval",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.PhotoApprovalRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The moderated photo.",
+                        "schema": {
+                            "$ref": "#/definitions/models.CommentPhoto"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input format or photo ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user does not manage this restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Photo not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/comments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves a list of all comments in the system.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comments"
+                ],
+                "summary": "Get All Comments",
+                "responses": {
+                    "200": {
+                        "description": "An array of comment objects.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CommentResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while fetching comments.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds a new comment to the system with customer's opinion. Passing the ID of a completed reservation at the restaurant marks the comment as verified, every reservation can only be reviewed once. Reviews are published under the name of the user, the displayName if given, or anonymously with anonymous set, which defaults to the reviewAnonymously preference of the user. Responses never include the contact details of reviewers. This endpoint requires authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Create a New Comment",
+                "parameters": [
+                    {
+                        "description": "Your Comment",
+                        "name": "commnet",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.CommentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "The created comment's details, including its unique identifier.",
+                        "schema": {
+                            "$ref": "#/definitions/models.CommentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input format for reservation details.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The role of the user cannot comment.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The reservation was already reviewed.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while creating the reservation.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/comments/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves details of a single commnet by its unique identifier.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comments"
+                ],
+                "summary": "Get a Single Comment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Comment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The details of the comment including ID, DateTime, Detail, the public identity of the author, RestaurantID, and Restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/models.CommentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid comment ID format.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Comment not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates the details of an existing comment identified by its ID. Passing anonymous or displayName changes how the review is published. This endpoint requires authentication.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comments"
+                ],
+                "summary": "Update a Comment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Comment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated comment Details",
+                        "name": "comment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.CommentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The updated comment's details.",
+                        "schema": {
+                            "$ref": "#/definitions/models.CommentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input format for comment details or invalid comment ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The role of the user cannot comment.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Comment not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a comment from the system. This endpoint requires authentication.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comments"
+                ],
+                "summary": "Delete a Comment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Comment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Comment successfully deleted, no content to return."
+                    },
+                    "400": {
+                        "description": "Invalid comment ID format.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Comment not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/comments/{id}/photos": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Attaches a photo to one of the authenticated user's comments. The photo appears in the restaurant gallery once approved.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "photos"
+                ],
+                "summary": "Upload a Comment Photo",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Comment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Image file",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "The uploaded photo, pending approval.",
+                        "schema": {
+                            "$ref": "#/definitions/models.CommentPhoto"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid comment ID or missing image.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The comment belongs to another user, or the role of the user cannot comment.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Comment not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Accepts a batch of up to 100 product analytics events from a client app. Every event must match the schema of its name, with no unknown properties, or the whole batch is rejected. Events are stored in the background, and dropped if the user did not agree to analytics.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Send analytics events",
+                "parameters": [
+                    {
+                        "description": "Events",
+                        "name": "events",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.AnalyticsEventsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "The events were accepted."
+                    },
+                    "400": {
+                        "description": "Invalid input format or an event not matching its schema.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while checking the consent.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/exchange-rates": {
+            "get": {
+                "description": "Lists the exchange rates prices can be shown in with the currency query parameter, as the value of one baht. The rates are refreshed daily and are only meant for display, guests always pay in the currency of the restaurant.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Get Exchange Rates",
+                "responses": {
+                    "200": {
+                        "description": "The cached exchange rates, without updatedAt until they are loaded.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ExchangeRatesResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the details of the currently authenticated user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get my profile",
+                "responses": {
+                    "200": {
+                        "description": "The details of the currently authenticated user.",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Schedules the deletion of the currently authenticated account after a grace period. Upcoming reservations are cancelled and every session is logged out. Logging in again before the deadline keeps the account. Once the grace period ends, the name, email, telephone and credentials of the account are erased and the email can be used for a new account.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Delete my account",
+                "responses": {
+                    "200": {
+                        "description": "When the account will be deleted.",
+                        "schema": {
+                            "$ref": "#/definitions/api.DeleteAccountResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while scheduling the deletion.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/2fa": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Turns off two-factor authentication for the authenticated user. Requires a current code.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "authentication"
+                ],
+                "summary": "Disable Two-Factor Authentication",
+                "parameters": [
+                    {
+                        "description": "Current TOTP or backup code",
+                        "name": "code",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.TwoFactorCodeDetails"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Two-factor authentication has been disabled.",
+                        "schema": {
+                            "$ref": "#/definitions/api.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Two-factor authentication is not enabled or the code is wrong.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many wrong codes, the second factor is locked.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/2fa/backup-codes": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces all backup codes of the authenticated user. Requires a current code.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "authentication"
+                ],
+                "summary": "Regenerate Two-Factor Backup Codes",
+                "parameters": [
+                    {
+                        "description": "Current TOTP or backup code",
+                        "name": "code",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.TwoFactorCodeDetails"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The new backup codes.",
+                        "schema": {
+                            "$ref": "#/definitions/api.BackupCodesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Two-factor authentication is not enabled or the code is wrong.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many wrong codes, the second factor is locked.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/2fa/enroll": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Generates a new TOTP secret for the authenticated user. Two-factor authentication is only enabled after confirming a code with /me/2fa/verify.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "authentication"
+                ],
+                "summary": "Enroll in Two-Factor Authentication",
+                "responses": {
+                    "200": {
+                        "description": "The secret and the otpauth URL to show as a QR code.",
+                        "schema": {
+                            "$ref": "#/definitions/api.TwoFactorEnrollResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Two-factor authentication is already enabled.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/2fa/verify": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Confirms the enrollment with a code from the authenticator app and enables two-factor authentication. Returns backup codes, shown only once, and a new token since older tokens stop working.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "authentication"
+                ],
+                "summary": "Verify Two-Factor Enrollment",
+                "parameters": [
+                    {
+                        "description": "Code from the authenticator app",
+                        "name": "code",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.TwoFactorCodeDetails"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "A new token and the backup codes.",
+                        "schema": {
+                            "$ref": "#/definitions/api.TwoFactorEnabledResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "No enrollment in progress or the code is wrong.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/activity": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the history of the currently authenticated user, newest first: logins, profile and password changes, reservations made or cancelled and deletion requests. Each entry has the IP address and user agent of the request and, when someone else acted, the actor.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get my activity",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "A page of activities.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ActivityPage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while fetching the activity.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/avatar": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces the profile picture of the currently authenticated user. The previous picture is deleted from storage.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Upload my profile picture",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Profile picture",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The updated user.",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing image or a file that is not an image.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while uploading the image.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/claims": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the restaurant claims the authenticated user submitted with their review status, newest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Get My Restaurant Claims",
+                "responses": {
+                    "200": {
+                        "description": "The claims of the user.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.RestaurantClaim"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while fetching the claims.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/consents": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves whether the currently authenticated user agreed to marketing emails and analytics, with the log of every consent they gave or withdrew. A consent given to an older version of a purpose counts as not granted until it is given again.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get my consents",
+                "responses": {
+                    "200": {
+                        "description": "The consents of the user.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ConsentsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while fetching the consents.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Gives or withdraws the consent of the currently authenticated user to the listed purposes, marketing_emails or analytics. Every change is logged with the current version of the purpose, the time, IP address and user agent. Purposes left out are unchanged.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Update my consents",
+                "parameters": [
+                    {
+                        "description": "Consents",
+                        "name": "consents",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.ConsentsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The updated consents.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ConsentsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input format or unknown purpose.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while saving the consents.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/email": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Starts changing the email of the authenticated user. The current password is required. A confirmation link is sent to the new address and the email only changes once it is confirmed with /auth/confirm-email. Requesting another change invalidates the earlier link.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "authentication"
+                ],
+                "summary": "Request an Email Change",
+                "parameters": [
+                    {
+                        "description": "New email and current password",
+                        "name": "email",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.EmailChangeDetails"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The confirmation link has been sent to the new address.",
+                        "schema": {
+                            "$ref": "#/definitions/api.MessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is malformed or the email is not a valid address or unchanged.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "The current password is incorrect.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The email belongs to another user.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error, unable to process the request.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/experiments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the variant of every running A/B experiment the currently authenticated user is in. A user always gets the same variant of an experiment. Fetching the assignments logs that the user was exposed to them, once a day, if they agreed to analytics.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get my experiments",
+                "responses": {
+                    "200": {
+                        "description": "The variants of the user.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ExperimentAssignment"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns a download link to a zip archive of all the data held on the currently authenticated user: profile, preferences, reservations, comments, favorites, consents and sessions. The archive is assembled in the background, responses with status 202 carry no link yet and the request should be repeated later. The user is also emailed when the archive is ready.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Export my data",
+                "responses": {
+                    "200": {
+                        "description": "The export is ready, downloadUrl links to the archive.",
+                        "schema": {
+                            "$ref": "#/definitions/models.DataExport"
+                        }
+                    },
+                    "202": {
+                        "description": "The export is being assembled.",
+                        "schema": {
+                            "$ref": "#/definitions/models.DataExport"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while starting the export.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/favorites": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the restaurants bookmarked by the currently authenticated user, most recently added first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get my favorites",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "A page of favorites with their restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.FavoritePage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while fetching the favorites.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/feed": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists what happened at the restaurants the currently authenticated user follows, newest first: photo.added for new gallery photos, listing.updated for changes to the listing and announcement for posts by the owner.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get my feed",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "A page of feed items with their restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.FeedPage"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while fetching the feed.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/limits": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns the limits applying to the currently authenticated user and how much of them is left: API requests in the current rate limit window, reservations that can be held and telephone verification codes per hour. The request quota is the one also sent in the X-RateLimit-* headers, after counting this request.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get my limits",
+                "responses": {
+                    "200": {
+                        "description": "The limits of the user.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.LimitsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while counting usage.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/password": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Changes the password of the authenticated user. The current password is required. Every existing session is signed out and a new token is returned.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "authentication"
+                ],
+                "summary": "Change Password",
+                "parameters": [
+                    {
+                        "description": "Current and new password",
+                        "name": "passwords",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.ChangePasswordDetails"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "A new JWT token replacing the revoked sessions.",
+                        "schema": {
+                            "$ref": "#/definitions/api.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "The input is malformed or the new password does not meet the requirements.",
+                        "schema": {
+                            "$ref": "#/definitions/api.PasswordPolicyErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "The current password is incorrect.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/phone/send-code": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sends a one-time code by SMS to the telephone number of the authenticated user. Codes expire after 10 minutes and can be requested at most once a minute and five times an hour.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Send Telephone Verification Code",
+                "responses": {
+                    "200": {
+                        "description": "The code has been sent.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.PhoneMessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "The user has no telephone number or it is already verified.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many codes requested.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/phone/verify": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Confirms the telephone number of the authenticated user with the code received by SMS. A code is invalidated after five wrong attempts.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Verify Telephone Number",
+                "parameters": [
+                    {
+                        "description": "Verification code",
+                        "name": "code",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.PhoneCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The user with the verified telephone number.",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "The code is wrong or has expired.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many wrong attempts.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/preferences": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the preferred language, dietary restrictions and default party size of the currently authenticated user, used to prefill forms, their privacy settings and how notifications are delivered. Users who never saved preferences get the defaults.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get my preferences",
+                "responses": {
+                    "200": {
+                        "description": "The preferences of the user.",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserPreferences"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while fetching the preferences.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces the preferences of the currently authenticated user. A missing language or party size falls back to the default. Languages are th and en, dietary restrictions are vegetarian, vegan, halal, kosher, gluten_free, lactose_free, nut_allergy, seafood_allergy, no_pork and no_beef, and party sizes go from 1 to 20. With hideContact the email and telephone of the user are not shown in the customer lists of restaurants, with reviewAnonymously new reviews are published anonymously unless they say otherwise. emailDelivery and smsDelivery are immediate or daily_digest, which gathers the notifications of the channel into one message a day, missing ones fall back to immediate.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Update my preferences",
+                "parameters": [
+                    {
+                        "description": "Preferences",
+                        "name": "preferences",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.PreferencesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The updated preferences.",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserPreferences"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input format or unsupported value.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while saving the preferences.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/reservations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the reservations of the currently authenticated user with their restaurant, latest first. The days of from and to are inclusive.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Get my reservations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma separated statuses to keep, e.g. pending,confirmed",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day in YYYY-MM-DD format",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day in YYYY-MM-DD format",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "USD",
+                        "description": "Currency to also show the prices in, e.g. USD",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "th-TH",
+                        "description": "Language to also write dates and amounts in, th or en, optionally with a calendar such as en-u-ca-buddhist",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The reservations of the user.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Reservation"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Unknown status or invalid date.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while fetching the reservations.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the devices currently signed in to the account of the authenticated user, with the user agent, IP address and time of login. The session making the request is marked as current.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "authentication"
+                ],
+                "summary": "List My Sessions",
+                "responses": {
+                    "200": {
+                        "description": "The active sessions, newest first.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Session"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while fetching the sessions.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Signs out every session of the authenticated user except the one making the request.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "authentication"
+                ],
+                "summary": "Revoke My Other Sessions",
+                "responses": {
+                    "200": {
+                        "description": "The number of sessions revoked.",
+                        "schema": {
+                            "$ref": "#/definitions/api.RevokeSessionsResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while revoking the sessions.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/sessions/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Signs out a single session of the authenticated user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "authentication"
+                ],
+                "summary": "Revoke a Session",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Session ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Session revoked."
+                    },
+                    "400": {
+                        "description": "Invalid session ID.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Session not found.",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/owner/summary": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Summarizes the restaurants the authenticated user manages for the owner dashboard: the reservations of the local day, the upcoming reservations waiting for a confirmation and the latest reviews of each restaurant. Admins get every restaurant.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Get Owner Dashboard Summary",
+                "responses": {
+                    "200": {
+                        "description": "The summary of the restaurants.",
+                        "schema": {
+                            "$ref": "#/definitions/models.OwnerSummary"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while building the summary.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/privacy/processing": {
+            "get": {
+                "description": "Lists the purposes RedRice processes personal data for, with the categories of data, the legal basis and how long the data is kept. Purposes with requiresConsent are only carried out for users who agreed to them.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "privacy"
+                ],
+                "summary": "Get the data-processing registry",
+                "responses": {
+                    "200": {
+                        "description": "The registry.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ProcessingActivity"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/queue/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves a queue entry together with its up to date position and estimated wait.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "queue"
+                ],
+                "summary": "Get a Queue Entry",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Queue entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The queue entry with its wait estimate.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.QueueEntryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid queue entry ID format.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Queue entry not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a waiting party from the queue. The party itself, the owner of the restaurant or an admin can do this.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "queue"
+                ],
+                "summary": "Leave a Queue",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Queue entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The party left the queue."
+                    },
+                    "400": {
+                        "description": "Invalid queue entry ID format or the party is no longer waiting.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user cannot remove this queue entry.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Queue entry not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/queue/{id}/seat": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Marks a waiting party as seated, which moves everyone behind it forward. Only the owner of the restaurant or an admin can seat parties.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "queue"
+                ],
+                "summary": "Seat a Queued Party",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Queue entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The seated queue entry.",
+                        "schema": {
+                            "$ref": "#/definitions/models.QueueEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid queue entry ID format or the party is no longer waiting.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user does not manage this restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Queue entry not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reservations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves a list of all reservations in the system. The email and telephone of guests who hide their contact are left out, except on the reservations of the user making the request.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Get All Reservations",
+                "responses": {
+                    "200": {
+                        "description": "An array of reservation objects.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Reservation"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while fetching reservations.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds a new reservation to the system with the provided details. This endpoint requires authentication. With dry_run=true the booking policy, blackouts and limits are checked the same way but nothing is saved.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Create a New Reservation",
+                "parameters": [
+                    {
+                        "description": "Reservation Details",
+                        "name": "reservation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Reservation"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only validate the reservation",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dry run only: the reservation would be accepted, it has no ID.",
+                        "schema": {
+                            "$ref": "#/definitions/models.Reservation"
+                        }
+                    },
+                    "201": {
+                        "description": "The created reservation's details, including its unique identifier.",
+                        "schema": {
+                            "$ref": "#/definitions/models.Reservation"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input format or a reservation time outside the restaurant's booking window (see code).",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The role of the user cannot book.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while creating the reservation.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reservations/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves details of a single reservation by its unique identifier. The email and telephone of a guest who hides their contact are left out for everyone but the guest.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Get a Single Reservation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Reservation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "USD",
+                        "description": "Currency to also show the prices in, e.g. USD",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "th-TH",
+                        "description": "Language to also write dates and amounts in, th or en, optionally with a calendar such as en-u-ca-buddhist",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The details of the reservation including ID, DateTime, UserID, User, RestaurantID, and Restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/models.Reservation"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid reservation ID format.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Reservation not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while fetching the reservation.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates the details of an existing reservation identified by its ID. Only the user who made the reservation, the owner of the restaurant or an admin can update it, and the user of the reservation cannot be changed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Update a Reservation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Reservation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated Reservation Details",
+                        "name": "reservation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Reservation"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The updated reservation's details.",
+                        "schema": {
+                            "$ref": "#/definitions/models.Reservation"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input format for reservation details or invalid reservation ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The role of the user cannot book, or the user neither made the reservation nor manages its restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Reservation not found with the specified ID, or the restaurant to move it to is not found or not listed.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a reservation from the system by its unique identifier. Only the user who made the reservation, the owner of the restaurant or an admin can delete it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Delete a Reservation",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Reservation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Reservation successfully deleted, no content to return."
+                    },
+                    "400": {
+                        "description": "Invalid reservation ID format.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user neither made the reservation nor manages its restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Reservation not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reservations/{id}/receipt": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Redirects to the PDF receipt of a paid reservation. The receipt is generated if it was not issued yet. Only the guest or the staff of the restaurant can get it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Get Reservation Receipt",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Reservation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "302": {
+                        "description": "Redirect to the PDF receipt."
+                    },
+                    "400": {
+                        "description": "Invalid reservation ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user cannot see this receipt.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Reservation not found or without a paid deposit.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reservations/{id}/receipt/send": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Emails the guest a link to the PDF receipt of a paid reservation again. Only the guest or the staff of the restaurant can resend it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Resend Reservation Receipt",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Reservation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The receipt was sent.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ReceiptMessageResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid reservation ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user cannot see this receipt.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Reservation not found or without a paid deposit.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "The receipt could not be sent.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reservations/{id}/status": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Moves a reservation through its lifecycle: pending to confirmed, declined or cancelled, and confirmed to completed or cancelled. The owner of the restaurant or an admin can make any allowed change, the user who made the reservation can only cancel it. The guest is emailed about every change they did not make themselves.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Update a Reservation Status",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Reservation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New status",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.ReservationStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The updated reservation.",
+                        "schema": {
+                            "$ref": "#/definitions/models.Reservation"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input or a transition that is not allowed.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user cannot change this reservation.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Reservation not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reservations/{id}/wait": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Long-polling alternative to the event stream for clients whose proxies break server-sent events. Holds the request until the status of the reservation differs from the given one, or until the timeout. Only the guest or the staff of the restaurant can wait on a reservation.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Wait for a Reservation Status Change",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Reservation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "confirmed",
+                            "declined",
+                            "cancelled",
+                            "completed"
+                        ],
+                        "type": "string",
+                        "description": "Status known to the client, defaults to the current status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Seconds to wait, 30 by default and at most 60",
+                        "name": "timeout",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The reservation, with changed telling whether its status changed.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ReservationWaitResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid reservation ID or timeout.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user cannot see this reservation.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Reservation not found.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves one page of the restaurants in the system matching the filters, ordered by ID unless a sort key is given. Only active restaurants are listed. The X-Total-Count header holds the number of matching restaurants across all pages. Restaurants without a price range are left out when filtering by price and come last when sorting by it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Get All Restaurants",
+                "parameters": [
+                    {
+                        "enum": [
+                            "name",
+                            "newest",
+                            "rating",
+                            "verifiedRating",
+                            "favorites",
+                            "price",
+                            "priceDesc"
+                        ],
+                        "type": "string",
+                        "description": "Sort key, name in alphabetical order, newest first, price cheapest first, the others highest first",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Lowest average rating, from 0 to 5",
+                        "name": "minRating",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "thai",
+                        "description": "Deprecated, name or slug of a cuisine category, ignored when category is set",
+                        "name": "cuisine",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "vegan-friendly",
+                        "description": "Slug of a category the restaurants are listed under",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Lowest price range, from 1 (budget) to 4 (fine dining)",
+                        "name": "minPrice",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Highest price range, from 1 (budget) to 4 (fine dining)",
+                        "name": "maxPrice",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only restaurants open right now in their time zone",
+                        "name": "openNow",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Restaurants per page, at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "USD",
+                        "description": "Currency to also show the prices in, e.g. USD",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "th-TH",
+                        "description": "Language to also write dates and amounts in, th or en, optionally with a calendar such as en-u-ca-buddhist",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "An array of restaurant objects.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.RestaurantResponse"
+                            }
+                        },
+                        "headers": {
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Number of restaurants across all pages"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Unknown sort key or invalid filter.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while fetching restaurants.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds a new restaurant to the system with the provided details. The restaurant starts as a draft and is only listed once submitted and approved. The response lists data quality issues such as a malformed telephone or a very small image in warnings, they do not prevent the creation.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Create a New Restaurant",
+                "parameters": [
+                    {
+                        "description": "Restaurant Registration Details",
+                        "name": "restaurant",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Restaurant"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "The created restaurant's details, including its unique identifier.",
+                        "schema": {
+                            "$ref": "#/definitions/models.RestaurantResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input format for restaurant details.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while creating the restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/nearby": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves one page of the active restaurants within the radius of the point, closest first, with the distance to the point in kilometers. Restaurants without a location are left out. The X-Total-Count header holds the number of restaurants within the radius across all pages.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Get Nearby Restaurants",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "example": 13.7563,
+                        "description": "Latitude of the point, from -90 to 90",
+                        "name": "lat",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "example": 100.5018,
+                        "description": "Longitude of the point, from -180 to 180",
+                        "name": "lng",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Radius in kilometers, 5 by default and at most 50",
+                        "name": "radius",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Restaurants per page, at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The restaurants within the radius, with their distance.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.RestaurantResponse"
+                            }
+                        },
+                        "headers": {
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Number of restaurants within the radius across all pages"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Missing or invalid point or radius.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while fetching restaurants.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/search": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Searches the name, description and address of the active restaurants, best matches first, raised or lowered by the search boosts set by admins. The query supports the web search syntax, e.g. \"thai -buffet\" or a \"quoted phrase\", and names a few typos away still match. The X-Total-Count header holds the number of matches across all pages.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Search Restaurants",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search terms, at most 100 characters",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Restaurants per page, at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The matching restaurants.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.RestaurantResponse"
+                            }
+                        },
+                        "headers": {
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Number of matching restaurants across all pages"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Missing or too long query.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while searching restaurants.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/trending": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the restaurants trending over the last 7 days, highest score first. The score mixes the reservations made and the users viewing the restaurant page a day in the window, each relative to the most popular restaurant, with the rating. A user viewing the page again the same day is not counted twice. The ranking is computed every 15 minutes and only lists restaurants with reservations or views in the window. The X-Total-Count header holds the number of ranked restaurants, at most 100.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Get Trending Restaurants",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Restaurants per page, at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "USD",
+                        "description": "Currency to also show the prices in, e.g. USD",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "th-TH",
+                        "description": "Language to also write dates and amounts in, th or en, optionally with a calendar such as en-u-ca-buddhist",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The trending restaurants with their score.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.TrendingRestaurant"
+                            }
+                        },
+                        "headers": {
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "When the ranking was computed"
+                            },
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Number of ranked restaurants across all pages"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Unsupported currency.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while fetching the restaurants.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves details of a single restaurant by its unique identifier. The menu is only included with include=menu. Restaurants that are not active are only shown to their owner and admins.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Get a Single Restaurant",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "menu"
+                        ],
+                        "type": "string",
+                        "description": "Comma-separated extra details to include",
+                        "name": "include",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "USD",
+                        "description": "Currency to also show the prices in, e.g. USD",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "th-TH",
+                        "description": "Language to also write dates and amounts in, th or en, optionally with a calendar such as en-u-ca-buddhist",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The details of the restaurant including ID, name, location, review tags, and other relevant information.",
+                        "schema": {
+                            "$ref": "#/definitions/models.RestaurantResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid restaurant ID format.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates the details of an existing restaurant identified by its ID. Fields left empty in the form keep their value, use the partial update to clear them. Changes to the listing are recorded in the restaurant history. Data quality issues in the changed fields are listed in warnings.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Update a Restaurant",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated Restaurant Details",
+                        "name": "restaurant",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Restaurant"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The updated restaurant's details.",
+                        "schema": {
+                            "$ref": "#/definitions/models.RestaurantResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input format for restaurant details or invalid restaurant ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a restaurant from the system by its unique identifier. The restaurant is soft deleted: it is hidden from every listing while its reservations and comments are kept, and it can be restored with /restaurants/{id}/restore.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Delete a Restaurant",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Restaurant successfully deleted, no content to return."
+                    },
+                    "400": {
+                        "description": "Invalid restaurant ID format.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates only the fields present in the body of an existing restaurant identified by its ID, leaving the others as they are. Unlike the form update, present fields may be set to an empty value to clear them, and latitude and longitude to null. The telephone is stored in the E.164 format. Changes to the listing are recorded in the restaurant history. Data quality issues of the restaurant are listed in warnings.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Partially Update a Restaurant",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "restaurant",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.RestaurantPatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The updated restaurant's details.",
+                        "schema": {
+                            "$ref": "#/definitions/models.RestaurantResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input format, field value or restaurant ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while updating the restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/analytics/fill-rates": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Describes how the slots of the week of a restaurant usually fill up, from snapshots of every slot taken a week, 3 days, a day, 6 hours and an hour before it starts and at its start. For every weekday and time of the restaurant's time zone it gives the average number of reservations at the start, at each lead time the average reservations, the share of the final bookings already made and the share of slots no longer bookable, and the earliest lead time the slot was usually already full. The period covers the slot start times and defaults to the current month. Only the owner of the restaurant or an admin can see it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Get Restaurant Slot Fill Rates",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day of the period in YYYY-MM-DD format",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day of the period in YYYY-MM-DD format",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The fill rates by weekday and time.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SlotFill"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid restaurant ID or period.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user does not manage this restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while reading the snapshots.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/announcements": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Publishes an announcement to the followers of the restaurant. Followers who agreed to marketing emails are also emailed, right away or in their daily digest. Only the owner of the restaurant or an admin can post announcements.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Post an Announcement",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Announcement",
+                        "name": "announcement",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.AnnouncementRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "The published announcement.",
+                        "schema": {
+                            "$ref": "#/definitions/models.FeedItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid restaurant ID or missing title.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user does not manage this restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while publishing the announcement.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/availability": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the bookable time slots of a restaurant for the given day, taking the owner's booking policy, blackouts and the special hours of the day into account. No slots are listed on days the restaurant is closed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Get Restaurant Availability",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Day to list in YYYY-MM-DD format in the time zone of the restaurant, defaults to today",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The time slots of the day.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Slot"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid restaurant ID or date format.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/blackouts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the upcoming blackout periods of a restaurant.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Get Restaurant Blackouts",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "An array of blackout periods.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Blackout"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid restaurant ID format.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while fetching blackouts.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Blocks a time slot or a date range so that no reservations can be made during it. Only the owner of the restaurant or an admin can create blackouts.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Create a Restaurant Blackout",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Blackout period",
+                        "name": "blackout",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.BlackoutRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "The created blackout.",
+                        "schema": {
+                            "$ref": "#/definitions/models.Blackout"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input format or time range.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user does not manage this restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while creating the blackout.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/blackouts/{blackoutId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a blackout period, making its slots bookable again. Only the owner of the restaurant or an admin can delete blackouts.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Delete a Restaurant Blackout",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Blackout ID",
+                        "name": "blackoutId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Blackout successfully deleted."
+                    },
+                    "400": {
+                        "description": "Invalid restaurant or blackout ID format.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user does not manage this restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Blackout not found for the specified restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/booking-policy": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sets the minimum notice and the maximum advance booking window of a restaurant, whether guests need a verified telephone number to book, and the deposit charged per reservation in minor units of its currency, e.g. 20000 THB for 200 baht. Service charge and VAT are applied to the deposit according to the tax settings. Zero disables the corresponding rule. Only the owner of the restaurant or an admin can change it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Update Restaurant Booking Policy",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Booking policy",
+                        "name": "policy",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.BookingPolicyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The updated restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/models.RestaurantResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input format or restaurant ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user does not manage this restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/claims": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Asks admins to make the authenticated user the owner of an existing restaurant. Up to 5 proof documents such as a business registration, as PDF, JPEG or PNG, are kept privately for the review. Once an admin approves the claim the user manages the restaurant, which gets the verified badge, and has to log in again.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Claim a Restaurant",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Proof documents, repeat the field for every document",
+                        "name": "documents",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Message to the admins",
+                        "name": "message",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "The submitted claim.",
+                        "schema": {
+                            "$ref": "#/definitions/models.RestaurantClaim"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid restaurant ID, missing, unsupported or too many documents, or the user is an admin.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The user already has a pending claim for the restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while uploading the documents or saving the claim.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/customers": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the guests of the restaurant built from its completed reservations, with their number of visits and last visit, most frequent first, so staff can recognize regulars. The email and telephone of guests who hide their contact in their preferences are left out. Only the owner of the restaurant or an admin can see it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Get Restaurant Customers",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "A page of customers.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.CustomerPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid restaurant ID format.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user does not manage this restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while fetching the customers.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/deposit-rules": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the rules setting the deposit of the reservations by time of the week and demand, instead of the deposit of the booking policy. Only the owner of the restaurant or an admin can see them.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Get Restaurant Deposit Rules",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The deposit rules of the restaurant.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.DepositRule"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid restaurant ID format.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user does not manage this restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while fetching the rules.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds a rule setting the deposit of the reservations starting in a window of the week, in the time zone of the restaurant. The window may run past midnight and belongs to the weekday it starts on, no weekdays means every day. With a minimum of reservations the rule only applies once the slot is that busy. When several rules match a reservation the highest deposit is charged, when none does the deposit of the booking policy is. The deposit is in minor units of its currency, which defaults to THB. Only the owner of the restaurant or an admin can change the rules.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Create a Deposit Rule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Deposit rule",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.DepositRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "The created rule.",
+                        "schema": {
+                            "$ref": "#/definitions/models.DepositRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input format, window or deposit.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user does not manage this restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while creating the rule.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/deposit-rules/{ruleId}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces a deposit rule of a restaurant. Reservations already made keep their deposit. Only the owner of the restaurant or an admin can change the rules.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Update a Deposit Rule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Deposit rule ID",
+                        "name": "ruleId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Deposit rule",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.DepositRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The updated rule.",
+                        "schema": {
+                            "$ref": "#/definitions/models.DepositRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input format, window or deposit.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user does not manage this restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Rule not found for the restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while saving the rule.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a deposit rule of a restaurant. Reservations already made keep their deposit. Only the owner of the restaurant or an admin can change the rules.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Delete a Deposit Rule",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Deposit rule ID",
+                        "name": "ruleId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Rule deleted."
+                    },
+                    "400": {
+                        "description": "Invalid restaurant or rule ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user does not manage this restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Rule not found for the restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while deleting the rule.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/events": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Opens a server-sent event stream with real-time updates of a restaurant: queue.updated when a party joins, is seated or leaves the queue, and reservation.updated when the status of a reservation changes. Updates reach the stream whichever server instance handled the change. Only the owner of the restaurant or an admin can open the stream.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Stream Restaurant Events",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "A stream of events.",
+                        "schema": {
+                            "$ref": "#/definitions/utils.Event"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid restaurant ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user does not manage the restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/favorite": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Bookmarks the restaurant for the currently authenticated user. Adding a restaurant that is already a favorite has no effect.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Add a Favorite",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The restaurant is a favorite of the user.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.FavoriteStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid restaurant ID format.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while saving the favorite.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes the bookmark of the restaurant for the currently authenticated user. Removing a restaurant that is not a favorite has no effect.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Remove a Favorite",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The restaurant is not a favorite of the user.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.FavoriteStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid restaurant ID format.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while removing the favorite.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/follow": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Subscribes the currently authenticated user to the feed of the restaurant: new photos, listing changes and announcements. Following a restaurant twice has no effect.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Follow a Restaurant",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The user follows the restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.FollowStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid restaurant ID format.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while following the restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stops the feed of the restaurant for the currently authenticated user. Unfollowing a restaurant that is not followed has no effect.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Unfollow a Restaurant",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The user does not follow the restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.FollowStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid restaurant ID format.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while unfollowing the restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the changes made to the listing of a restaurant, newest first, with who made them and the old and new values.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Get Restaurant Change History",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "A page of restaurant versions.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.RestaurantHistoryPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid restaurant ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while fetching the history.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/images": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds up to 10 images at the end of the owner's gallery of a restaurant, in the order sent. The images are uploaded concurrently and the result of every file is reported: 201 when all were added, 207 when some failed to upload, in which case the others were still added. Nothing is added when saving the images fails. The nth caption goes with the nth image. Only the owner of the restaurant or an admin can upload gallery images.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "photos"
+                ],
+                "summary": "Upload Restaurant Gallery Images",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Image files, repeat the field for every image",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Captions, one per image",
+                        "name": "caption",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Every image was added.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v1.GalleryUploadResult"
+                            }
+                        }
+                    },
+                    "207": {
+                        "description": "Some images failed to upload, the others were added.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/v1.GalleryUploadResult"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid restaurant ID, no image or too many images.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user does not manage this restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while uploading or saving the images.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/images/order": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Puts the gallery images of a restaurant in the given order, which must list every image once. Only the owner of the restaurant or an admin can reorder the gallery.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "photos"
+                ],
+                "summary": "Reorder a Restaurant Gallery",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Image IDs in the new order",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.GalleryOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The gallery in its new order.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.RestaurantImage"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid restaurant ID, or the order does not list every image once.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user does not manage this restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while reordering the gallery.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/images/{imageId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes an image from the gallery of a restaurant and from storage. When it was the cover, the next image of the gallery becomes the cover. Only the owner of the restaurant or an admin can delete gallery images.",
+                "tags": [
+                    "photos"
+                ],
+                "summary": "Delete a Restaurant Gallery Image",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Image ID",
+                        "name": "imageId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Image deleted."
+                    },
+                    "400": {
+                        "description": "Invalid restaurant or image ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user does not manage this restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Image not found in the gallery of the restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while deleting the image.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/images/{imageId}/cover": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Makes a gallery image the image of the restaurant, recorded in the restaurant history. Only the owner of the restaurant or an admin can set the cover.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "photos"
+                ],
+                "summary": "Set the Cover Image of a Restaurant",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Image ID",
+                        "name": "imageId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The gallery with the new cover.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.RestaurantImage"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid restaurant or image ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user does not manage this restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Image not found in the gallery of the restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while setting the cover.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/invitations/bulk": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Uploads a CSV file of staff emails, one per row with an optional header row \"email\". Each address gets an invitation email to join the restaurant and the response reports the result of every row. Only the owner of the restaurant or an admin can invite staff.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Invite Restaurant Staff from CSV",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "CSV file of emails",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The per-row invitation results.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.BulkInvitationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid restaurant ID or CSV file.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user does not manage this restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/menu": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the categories of the menu of a restaurant in their order, each with its items in their order. Prices are in minor units of their currency, e.g. 18000 THB for 180 baht, with the currency parameter they are also shown converted for display.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "menu"
+                ],
+                "summary": "Get Restaurant Menu",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "USD",
+                        "description": "Currency to also show the prices in, e.g. USD",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "th-TH",
+                        "description": "Language to also write dates and amounts in, th or en, optionally with a calendar such as en-u-ca-buddhist",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The menu of the restaurant.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.MenuCategory"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid restaurant ID format.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant not found with the specified ID, or not listed and not managed by the user.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while fetching the menu.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/menu/categories": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds a category at the end of the menu of a restaurant. Only the owner of the restaurant or an admin can change the menu.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "menu"
+                ],
+                "summary": "Create a Menu Category",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Menu category, the position is ignored",
+                        "name": "category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.MenuCategoryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "The created category.",
+                        "schema": {
+                            "$ref": "#/definitions/models.MenuCategory"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input format or missing name.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user does not manage this restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while creating the category.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/menu/categories/{categoryId}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Renames a category of the menu of a restaurant and optionally moves it. Only the owner of the restaurant or an admin can change the menu.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "menu"
+                ],
+                "summary": "Update a Menu Category",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Menu category ID",
+                        "name": "categoryId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Menu category",
+                        "name": "category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.MenuCategoryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The updated category, without its items.",
+                        "schema": {
+                            "$ref": "#/definitions/models.MenuCategory"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input format or missing name.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user does not manage this restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Category not found in the menu of the restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while updating the category.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a category from the menu of a restaurant together with its items. Only the owner of the restaurant or an admin can change the menu.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "menu"
+                ],
+                "summary": "Delete a Menu Category",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Menu category ID",
+                        "name": "categoryId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Category deleted."
+                    },
+                    "400": {
+                        "description": "Invalid restaurant or category ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user does not manage this restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Category not found in the menu of the restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while deleting the category.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/menu/items": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds an item at the end of a category of the menu of a restaurant. The price is in minor units of its currency, which defaults to THB. Only the owner of the restaurant or an admin can change the menu.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "menu"
+                ],
+                "summary": "Create a Menu Item",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Menu item, the position is ignored",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.MenuItemRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "The created item.",
+                        "schema": {
+                            "$ref": "#/definitions/models.MenuItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input format, price or category.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user does not manage this restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while creating the item.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/menu/items/{itemId}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replaces the details of an item of the menu of a restaurant, which may move it to another category. Only the owner of the restaurant or an admin can change the menu.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "menu"
+                ],
+                "summary": "Update a Menu Item",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Menu item ID",
+                        "name": "itemId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Menu item",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.MenuItemRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The updated item.",
+                        "schema": {
+                            "$ref": "#/definitions/models.MenuItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input format, price or category.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user does not manage this restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Item not found in the menu of the restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while updating the item.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes an item from the menu of a restaurant. Only the owner of the restaurant or an admin can change the menu.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "menu"
+                ],
+                "summary": "Delete a Menu Item",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Menu item ID",
+                        "name": "itemId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Item deleted."
+                    },
+                    "400": {
+                        "description": "Invalid restaurant or item ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user does not manage this restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Item not found in the menu of the restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while deleting the item.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/menu/items/{itemId}/photo": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sets the photo of an item of the menu of a restaurant, replacing the previous one. Only the owner of the restaurant or an admin can change the menu.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "menu"
+                ],
+                "summary": "Upload a Menu Item Photo",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Menu item ID",
+                        "name": "itemId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Image file",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The item with its new photo.",
+                        "schema": {
+                            "$ref": "#/definitions/models.MenuItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid restaurant or item ID, or missing image.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user does not manage this restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Item not found in the menu of the restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while uploading the photo.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/photos": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the photo gallery of a restaurant, merging the owner's gallery with approved review photos, newest first. Each photo states its source.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "photos"
+                ],
+                "summary": "Get Restaurant Photos",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Photos per page, at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "One page of photos with pagination metadata.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.PhotoPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid restaurant ID format.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant not found with the specified ID, or not listed and not managed by the user.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while fetching photos.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/photos/pending": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the review photos of a restaurant waiting for approval. Only the owner of the restaurant or an admin can see them.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "photos"
+                ],
+                "summary": "Get Pending Comment Photos",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The photos waiting for approval.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CommentPhoto"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid restaurant ID format.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user does not manage this restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/preview": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Shows the details of a restaurant exactly as customers will see them once its pending scheduled changes are published, whatever the status of the restaurant, so owners can check them first. The X-Pending-Changes header holds the number of scheduled changes applied. Only the owner of the restaurant or an admin can preview it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Preview a Restaurant Listing",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "menu"
+                        ],
+                        "type": "string",
+                        "description": "Comma-separated extra details to include",
+                        "name": "include",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "USD",
+                        "description": "Currency to also show the prices in, e.g. USD",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "th-TH",
+                        "description": "Language to also write dates and amounts in, th or en, optionally with a calendar such as en-u-ca-buddhist",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The details of the restaurant with its pending changes.",
+                        "schema": {
+                            "$ref": "#/definitions/models.RestaurantResponse"
+                        },
+                        "headers": {
+                            "X-Pending-Changes": {
+                                "type": "integer",
+                                "description": "Number of scheduled changes applied"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid restaurant ID format or unsupported currency.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user does not manage the restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while fetching the restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/quality": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Scores how complete the listing of a restaurant is, from 0 to 100: gallery photos, opening hours, location, menu and a description of at least 100 characters. Every failed check comes with a hint on how to fix it. Only the owner of the restaurant or an admin can see it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Get Restaurant Quality",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The score of the listing and its checks.",
+                        "schema": {
+                            "$ref": "#/definitions/models.RestaurantQuality"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid restaurant ID format.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user does not manage the restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while scoring the restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/queue": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the parties currently waiting at a restaurant in the order they will be seated. Only the owner of the restaurant or an admin can see the queue.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "queue"
+                ],
+                "summary": "Get Restaurant Queue",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The waiting parties.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.QueueEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid restaurant ID format.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user does not manage this restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds the authenticated user's party to the walk-in queue of a restaurant and returns its position and estimated wait.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "queue"
+                ],
+                "summary": "Join Restaurant Queue",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Party details",
+                        "name": "party",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.JoinQueueRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "The queue entry with its wait estimate.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.QueueEntryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input format or restaurant ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/reservations/batch": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Confirms or declines several pending reservations of the restaurant in one transaction. Either every action is applied or none is: when one is rejected the response has status 409 and the results tell which actions failed and why. The guests of the changed reservations are emailed their new status.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Confirm or Decline Reservations in Batch",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Status changes, at most 100",
+                        "name": "actions",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.BatchReservationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Every action was applied.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.BatchReservationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input format or too many actions.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user does not manage this restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "At least one action was rejected, nothing was changed.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.BatchReservationErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while updating the reservations.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/reservations/timeline": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists, per table, the blocks of time the pending, confirmed and completed reservations occupy the table on a day of the restaurant's time zone, for a Gantt-style view. Reservations from the day before still running after midnight are included and blocks sharing time with another block of the same table are flagged. Table 0 holds the reservations without a table. Only the owner of the restaurant or an admin can see it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Get Restaurant Reservation Timeline",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Day in YYYY-MM-DD format, defaults to today in the time zone of the restaurant",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The occupancy of the tables.",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReservationTimeline"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid restaurant ID or date format.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user does not manage this restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while fetching the reservations.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Brings back a deleted restaurant with its reservations and comments.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Restore a Restaurant",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The restored restaurant's details.",
+                        "schema": {
+                            "$ref": "#/definitions/models.RestaurantResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid restaurant ID format.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The restaurant is not deleted.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while restoring the restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/revert/{versionId}": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Restores the listing of a restaurant to the state it had right after the given version, undoing every later change. The revert is recorded as a new version.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Revert a Restaurant to a Version",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Version ID",
+                        "name": "versionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The restored restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/models.RestaurantResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid restaurant or version ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant or version not found.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/scheduled-changes": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the scheduled changes of a restaurant, including published and cancelled ones. Only the owner of the restaurant or an admin can see them.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Get Scheduled Restaurant Changes",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The scheduled changes ordered by publish time.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ScheduledChange"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid restaurant ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user does not manage this restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stages changes to the listing of a restaurant, such as new opening hours or a seasonal description, to be published together at publishAt. Only the owner of the restaurant or an admin can schedule changes.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Schedule Restaurant Changes",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Changes and publish time",
+                        "name": "change",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.ScheduledChangeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "The scheduled change.",
+                        "schema": {
+                            "$ref": "#/definitions/models.ScheduledChange"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input, no changes, or publish time in the past.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user does not manage this restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/scheduled-changes/{changeId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cancels a scheduled change that has not been published yet.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Cancel a Scheduled Restaurant Change",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Scheduled change ID",
+                        "name": "changeId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Scheduled change cancelled."
+                    },
+                    "400": {
+                        "description": "Invalid ID or the change was already published.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user does not manage this restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Scheduled change not found.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/special-hours": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves the upcoming days on which the restaurant is closed or opens with other hours than its regular ones, by date.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Get Restaurant Special Hours",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The special hours from today on.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SpecialHours"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid restaurant ID format.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while fetching the special hours.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/special-hours/{date}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Overrides the regular opening hours of the restaurant on one day in its time zone, either closing it for the day or opening it with other hours. Reservations cannot be made on closed days or outside the special hours, and the availability and open now filter follow the special hours. Setting the day again replaces its special hours. Only the owner of the restaurant or an admin can set them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Set Restaurant Special Hours",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Day in YYYY-MM-DD format",
+                        "name": "date",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Special hours, the times are ignored on closed days",
+                        "name": "hours",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.SpecialHoursRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The special hours of the day.",
+                        "schema": {
+                            "$ref": "#/definitions/models.SpecialHours"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input format, date or opening hours.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user does not manage this restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while saving the special hours.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Brings back the regular opening hours of the restaurant on the day. Only the owner of the restaurant or an admin can delete special hours.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Delete Restaurant Special Hours",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Day in YYYY-MM-DD format",
+                        "name": "date",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Special hours successfully deleted."
+                    },
+                    "400": {
+                        "description": "Invalid restaurant ID format.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user does not manage this restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "No special hours set on the day.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/statement": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Shows the deposits, commission and payout of a restaurant for the period with every reservation they are made of. Only the owner of the restaurant or an admin can see it. The period defaults to the current month.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Get Restaurant Payout Statement",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day of the period in YYYY-MM-DD format",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day of the period in YYYY-MM-DD format",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency of the deposits, defaults to the current deposit currency of the restaurant",
+                        "name": "currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The payout statement.",
+                        "schema": {
+                            "$ref": "#/definitions/models.PayoutStatement"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid restaurant ID, period or currency.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user does not manage this restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/submit": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Asks admins to approve a draft restaurant. It is listed publicly once an admin makes it active. Only the owner of the restaurant or an admin can submit it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Submit a Restaurant for Approval",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The restaurant pending approval.",
+                        "schema": {
+                            "$ref": "#/definitions/models.RestaurantResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid restaurant ID format.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user does not manage the restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The restaurant is not a draft.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while submitting the restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/tax-settings": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sets whether the restaurant is registered for VAT, its 13-digit Thai tax ID, the service charge rate as a fraction and whether the deposit already includes them. VAT is charged at 7% on the deposit plus service charge and requires a valid tax ID. Only the owner of the restaurant or an admin can change it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Update Restaurant Tax Settings",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tax settings",
+                        "name": "settings",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.TaxSettingsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The updated restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/models.RestaurantResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input format, restaurant ID or tax settings.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user does not manage this restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/theme": {
+            "get": {
+                "description": "Retrieves the branding of a restaurant, its logo and color palette, for the white-label web app. Restaurants without a custom theme get the default palette. No authentication is required.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Get Restaurant Theme",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The theme of the restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/models.RestaurantTheme"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid restaurant ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Sets the color palette of a restaurant. Colors use the #RRGGBB format, missing colors fall back to the default palette. Only the owner of the restaurant or an admin can change it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Update Restaurant Theme",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Color palette",
+                        "name": "theme",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.ThemeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The updated theme.",
+                        "schema": {
+                            "$ref": "#/definitions/models.RestaurantTheme"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input format or color.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user does not manage this restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/theme/logo": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Uploads the logo shown by the white-label web app. Only the owner of the restaurant or an admin can upload it.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "restaurants"
+                ],
+                "summary": "Upload Restaurant Logo",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Logo image",
+                        "name": "image",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The updated theme.",
+                        "schema": {
+                            "$ref": "#/definitions/models.RestaurantTheme"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid restaurant ID or missing image.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user does not manage this restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while uploading the logo.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{id}/wait": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Estimates how long a party joining the walk-in queue now would wait, based on the live queue and historical seating durations.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "queue"
+                ],
+                "summary": "Get Estimated Wait Time",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The current wait estimate of the restaurant.",
+                        "schema": {
+                            "$ref": "#/definitions/models.WaitEstimate"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid restaurant ID format.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while computing the estimate.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/restaurants/{restaurantID}/comments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves a list of comments associated with a specific restaurant.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comments"
+                ],
+                "summary": "Get Reataurant's Comments",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Reataurant ID",
+                        "name": "restaurantId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only return comments with this tag, e.g. slow kitchen",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "positive",
+                            "negative",
+                            "neutral"
+                        ],
+                        "type": "string",
+                        "description": "Only return comments with this sentiment",
+                        "name": "sentiment",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "An array of comment objects for the restaurant.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CommentResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid reataurant ID format.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant not found with the specified ID, or not listed and not managed by the user.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/status": {
+            "get": {
+                "description": "Returns the health of the database, storage, email and payments components from checks run every 30 seconds, together with unresolved incidents and those resolved in the last 14 days. The overall status is the worst component status, and at least degraded while an incident is unresolved.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "status"
+                ],
+                "summary": "Get Service Status",
+                "responses": {
+                    "200": {
+                        "description": "The current status.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.StatusResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while fetching the incidents.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves one page of the users in the system, ordered by ID. Only admins can list the users. The X-Total-Count header holds the number of users matching the search across all pages. Deleted users are left out unless deleted is true, which lists only them.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get All Users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search in name, email and telephone",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "List the deleted users instead",
+                        "name": "deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Users per page, at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "An array of user objects.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.UserResponse"
+                            }
+                        },
+                        "headers": {
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Number of users matching the search"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while fetching users.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adds a new user to the system with the provided details. The telephone is stored in the E.164 format, numbers without a country code are taken to be Thai.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Create a New User",
+                "parameters": [
+                    {
+                        "description": "User Registration Details",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "The created user's details, including their unique identifier.",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input format for user details, an invalid telephone, or the password does not meet the requirements.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The email or telephone belongs to another user.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while creating the user.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/merge": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Moves the reservations, comments, favorites, follows, preferences, consents, activity, restaurant claims and notifications of the source user into the target user and removes the source account. Preferences and consent decisions the target already made are kept. Empty profile fields of the target are filled from the source, and the Google, Facebook and Apple logins of the source sign in to the target unless it is already linked to that provider. When the source has the more privileged role the target is given it like a role change: the target is signed out everywhere and the change is recorded in the audit trail.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Merge Two User Accounts",
+                "parameters": [
+                    {
+                        "description": "Target and source user IDs",
+                        "name": "merge",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.MergeUsersRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The merged user account.",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input format or the users cannot be merged.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "The target or source user was not found.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while merging the users.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves details of a single user by their unique identifier. The email and telephone of a user who hides their contact are left out for everyone but the user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get a Single User",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The details of the user including ID, name, email, telephone, and role.",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID format.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while fetching the user.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updates the details of an existing user identified by their ID. The email cannot be changed here, users change it with POST /me/email and the confirmation sent to the new address. The role and restaurant are changed with PUT /users/{id}/role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Update a User",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated User Details",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The updated user's details.",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input format for user details, invalid telephone or invalid user ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The telephone belongs to another user.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while updating the user.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Removes a user from the system by their unique identifier. The user is soft deleted: their reservations and comments are kept and the account can be restored with /users/{id}/restore.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Delete a User",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "User successfully deleted, no content to return."
+                    },
+                    "400": {
+                        "description": "Invalid user ID format.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while deleting the user.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Changes only the fields present in the body, omitted fields keep their value. Users can change their own name and telephone, a new telephone has to be verified again. Only admins can update other users, the role and restaurant are changed with PUT /users/{id}/role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Partially Update a User",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.UserPatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The updated user's details.",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input format, user ID or telephone.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The user cannot change this user.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The telephone belongs to another user.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/activity": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lists the history of a user, newest first, for support to answer questions such as who cancelled a booking.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get the activity of a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "A page of activities.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ActivityPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID format.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while fetching the activity.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Brings back a deleted user with their reservations and comments. A deletion the user scheduled themselves is cancelled as well.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Restore a User",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The restored user's details.",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID format.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found with the specified ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The user is not deleted.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while restoring the user.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/role": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Promotes or demotes a user. Restaurant owners must be assigned an existing restaurant, users and admins are unlinked from their restaurant. Admins cannot change their own role and the last admin cannot be demoted. The user is signed out everywhere so the new role applies right away, and the change is recorded in the audit trail.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Change the Role of a User",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New role",
+                        "name": "role",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.RoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The user with their new role.",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input, role or transition.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User or restaurant not found.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while changing the role.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userId}/reservations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieves a list of reservations associated with a specific user. The email and telephone of a user who hides their contact are left out for everyone but the user.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reservations"
+                ],
+                "summary": "Get User's Reservations",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "An array of reservation objects for the user.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Reservation"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid user ID format.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Reservations not found for the specified user ID.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error while fetching the reservations.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/widget/restaurants/{id}/availability": {
+            "get": {
+                "description": "Lists the bookable time slots of the day for the \"Book now\" widget embedded on the website of the restaurant. Needs an API key with the widget:book scope issued for the restaurant, sent from one of its allowed origins.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "widget"
+                ],
+                "summary": "Get Restaurant Availability for the Widget",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Day to list in YYYY-MM-DD format, defaults to today",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Widget API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "The time slots of the day.",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Slot"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid restaurant ID or date format.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid API key.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The key is not allowed for this restaurant or website.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/widget/restaurants/{id}/reservations": {
+            "post": {
+                "description": "Books a table from the \"Book now\" widget embedded on the website of the restaurant, without signing in. The reservation is filed under a guest record of the email, never under an account even with the same email, and bookings with the email of a suspended or deleted account are refused. The booking policy and reservation limit of customers apply. Needs an API key with the widget:book scope issued for the restaurant, sent from one of its allowed origins.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "widget"
+                ],
+                "summary": "Book from the Widget",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "Restaurant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reservation and guest details",
+                        "name": "reservation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.WidgetReservationRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Widget API key",
+                        "name": "X-API-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "reCAPTCHA or Turnstile token, required when CAPTCHA is enabled",
+                        "name": "X-Captcha-Token",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "The booked reservation.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.WidgetReservationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input, failed CAPTCHA or a reservation time outside the restaurant's booking window (see code).",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid API key.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "The key is not allowed for this restaurant or website, the email belongs to a suspended or deleted account, or the guest has too many reservations.",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "api.AcceptInvitationDetails": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "John Doe"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "securePassword123"
+                },
+                "telephone": {
+                    "type": "string",
+                    "example": "1234567890"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "3f0c1d..."
+                }
+            }
+        },
+        "api.AppleLoginDetails": {
+            "type": "object",
+            "properties": {
+                "identityToken": {
+                    "type": "string",
+                    "example": "eyJraWQiOi..."
+                },
+                "name": {
+                    "description": "Apple only shares the name with the app on the very first sign in",
+                    "type": "string",
+                    "example": "John Doe"
+                }
+            }
+        },
+        "api.BackupCodesResponse": {
+            "type": "object",
+            "properties": {
+                "backupCodes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "api.ChangePasswordDetails": {
+            "type": "object",
+            "properties": {
+                "currentPassword": {
+                    "type": "string",
+                    "example": "oldPassword123"
+                },
+                "newPassword": {
+                    "type": "string",
+                    "example": "newSecurePassword123"
+                }
+            }
+        },
+        "api.DeleteAccountResponse": {
+            "type": "object",
+            "properties": {
+                "deletionScheduledAt": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Account scheduled for deletion"
+                }
+            }
+        },
+        "api.EmailChangeConfirmDetails": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string",
+                    "example": "3f0c1d..."
+                }
+            }
+        },
+        "api.EmailChangeDetails": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "new.address@example.com"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "password123"
+                }
+            }
+        },
+        "api.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "Error message"
+                }
+            }
+        },
+        "api.FacebookLoginDetails": {
+            "type": "object",
+            "properties": {
+                "accessToken": {
+                    "type": "string",
+                    "example": "EAAB..."
+                }
+            }
+        },
+        "api.ForgotPasswordDetails": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "user@example.com"
+                }
+            }
+        },
+        "api.ImpersonateDetails": {
+            "type": "object",
+            "properties": {
+                "minutes": {
+                    "type": "integer",
+                    "example": 30
+                }
+            }
+        },
+        "api.ImpersonateResponse": {
+            "type": "object",
+            "properties": {
+                "expiresAt": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "eyJhbGciOi..."
+                },
+                "user": {
+                    "$ref": "#/definitions/models.UserResponse"
+                }
+            }
+        },
+        "api.LoginDetails": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "user@example.com"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "password123"
+                }
+            }
+        },
+        "api.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Login successful"
+                },
+                "token": {
+                    "type": "string",
+                    "example": ""
+                }
+            }
+        },
+        "api.LogoutResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Logout successful"
+                }
+            }
+        },
+        "api.MagicLinkDetails": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "user@example.com"
+                }
+            }
+        },
+        "api.MagicLinkVerifyDetails": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string",
+                    "example": "3f0c1d..."
+                }
+            }
+        },
+        "api.MessageResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Operation successful"
+                }
+            }
+        },
+        "api.PasswordPolicyErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "Password does not meet the requirements"
+                },
+                "fields": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "api.RegisterDetails": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "john.doe@example.com"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "John Doe"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "securePassword123"
+                },
+                "telephone": {
+                    "type": "string",
+                    "example": "0812345678"
+                }
+            }
+        },
+        "api.RegisterResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "User registered successfully"
+                }
+            }
+        },
+        "api.ResetPasswordDetails": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "example": "newSecurePassword123"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "3f0c1d..."
+                }
+            }
+        },
+        "api.RevokeSessionsResponse": {
+            "type": "object",
+            "properties": {
+                "revoked": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "api.TwoFactorCodeDetails": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "123456"
+                }
+            }
+        },
+        "api.TwoFactorEnabledResponse": {
+            "type": "object",
+            "properties": {
+                "backupCodes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "token": {
+                    "type": "string",
+                    "example": ""
+                }
+            }
+        },
+        "api.TwoFactorEnrollResponse": {
+            "type": "object",
+            "properties": {
+                "otpauthUrl": {
+                    "type": "string",
+                    "example": "otpauth://totp/RedRice:user@example.com?secret=JBSWY3DPEHPK3PXP"
+                },
+                "secret": {
+                    "type": "string",
+                    "example": "JBSWY3DPEHPK3PXP"
+                }
+            }
+        },
+        "api.TwoFactorLoginDetails": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "123456"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "eyJhbGciOi..."
+                }
+            }
+        },
+        "middleware.Quota": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer",
+                    "example": 300
+                },
+                "remaining": {
+                    "type": "integer",
+                    "example": 297
+                },
+                "reset": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.APIKey": {
+            "type": "object",
+            "properties": {
+                "allowedOrigins": {
+                    "description": "AllowedOrigins are the websites browsers may use the key from, e.g. https://example.com",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "createdBy": {
+                    "type": "integer"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lastUsedAt": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "restaurantId": {
+                    "description": "RestaurantID restricts the key to the routes of one restaurant",
+                    "type": "integer"
+                },
+                "revokedAt": {
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.Activity": {
+            "type": "object",
+            "properties": {
+                "actorId": {
+                    "description": "ActorID is who acted when it is not the user, e.g. an admin or the restaurant",
+                    "type": "integer",
+                    "example": 1
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "details": {
+                    "type": "object"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "impersonatedBy": {
+                    "description": "ImpersonatedBy is set when an admin acted as the user",
+                    "type": "integer"
+                },
+                "ip": {
+                    "type": "string",
+                    "example": "203.0.113.7"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "login",
+                        "profile.updated",
+                        "password.changed",
+                        "email.changed",
+                        "reservation.created",
+                        "reservation.cancelled",
+                        "account.deletion_scheduled"
+                    ],
+                    "example": "reservation.cancelled"
+                },
+                "userAgent": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "models.AnalyticsEvent": {
+            "type": "object",
+            "required": [
+                "name",
+                "occurredAt"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "enum": [
+                        "screen_view",
+                        "restaurant_view",
+                        "search",
+                        "reservation_started",
+                        "share"
+                    ],
+                    "example": "restaurant_view"
+                },
+                "occurredAt": {
+                    "description": "OccurredAt is when the event happened on the device",
+                    "type": "string"
+                },
+                "properties": {
+                    "type": "object"
+                }
+            }
+        },
+        "models.AuditEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "PUT /api/v1/reservations/:id"
+                },
+                "actorId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "details": {
+                    "description": "Details describes what changed, e.g. the previous and new role",
+                    "type": "object"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "impersonated": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "ip": {
+                    "type": "string",
+                    "example": "203.0.113.7"
+                },
+                "path": {
+                    "type": "string",
+                    "example": "/api/v1/reservations/7"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "userId": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "models.Backfill": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string",
+                    "example": "verified_ratings"
+                }
+            }
+        },
+        "models.BackfillRun": {
+            "type": "object",
+            "properties": {
+                "backfill": {
+                    "type": "string",
+                    "example": "verified_ratings"
+                },
+                "batchDelayMs": {
+                    "type": "integer",
+                    "example": 500
+                },
+                "batchSize": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "completedAt": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "cursor": {
+                    "type": "integer",
+                    "example": 4200
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "processed": {
+                    "type": "integer",
+                    "example": 4000
+                },
+                "startedBy": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "running",
+                        "paused",
+                        "completed",
+                        "failed"
+                    ],
+                    "example": "running"
+                },
+                "total": {
+                    "description": "Total is the number of rows when the run started",
+                    "type": "integer",
+                    "example": 12000
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Blackout": {
+            "type": "object",
+            "properties": {
+                "endTime": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "restaurantId": {
+                    "type": "integer"
+                },
+                "startTime": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Category": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Vegan friendly"
+                },
+                "slug": {
+                    "type": "string",
+                    "example": "vegan-friendly"
+                }
+            }
+        },
+        "models.ChargeBreakdown": {
+            "type": "object",
+            "properties": {
+                "serviceCharge": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "subtotal": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "total": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "vat": {
+                    "$ref": "#/definitions/models.Money"
+                }
+            }
+        },
+        "models.CommentPhoto": {
+            "type": "object",
+            "properties": {
+                "approved": {
+                    "type": "boolean"
+                },
+                "commentId": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "imageUrl": {
+                    "type": "string"
+                },
+                "restaurantId": {
+                    "type": "integer"
+                },
+                "thumbnailUrl": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CommentResponse": {
+            "type": "object",
+            "properties": {
+                "ID": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "anonymous": {
+                    "type": "boolean"
+                },
+                "dateTime": {
+                    "type": "string"
+                },
+                "myComment": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "number",
+                    "example": 4.5
+                },
+                "reservationId": {
+                    "type": "integer"
+                },
+                "restaurant": {
+                    "$ref": "#/definitions/models.RestaurantResponse"
+                },
+                "restaurantId": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "sentiment": {
+                    "type": "string",
+                    "example": "positive"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CommentTag"
+                    }
+                },
+                "user": {
+                    "$ref": "#/definitions/models.ReviewAuthor"
+                },
+                "userId": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "verified": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.CommentTag": {
+            "type": "object",
+            "properties": {
+                "commentId": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "restaurantId": {
+                    "type": "integer"
+                },
+                "sentiment": {
+                    "type": "string"
+                },
+                "tag": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Consent": {
+            "type": "object",
+            "properties": {
+                "currentVersion": {
+                    "type": "string",
+                    "example": "2024-05"
+                },
+                "granted": {
+                    "type": "boolean"
+                },
+                "purpose": {
+                    "type": "string",
+                    "enum": [
+                        "marketing_emails",
+                        "analytics"
+                    ],
+                    "example": "marketing_emails"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version is the version the user last answered, granted is false when it is outdated",
+                    "type": "string",
+                    "example": "2024-05"
+                }
+            }
+        },
+        "models.ConsentChange": {
+            "type": "object",
+            "required": [
+                "purpose"
+            ],
+            "properties": {
+                "granted": {
+                    "type": "boolean"
+                },
+                "purpose": {
+                    "type": "string",
+                    "enum": [
+                        "marketing_emails",
+                        "analytics"
+                    ],
+                    "example": "marketing_emails"
+                }
+            }
+        },
+        "models.ConsentEvent": {
+            "type": "object",
+            "properties": {
+                "granted": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "ip": {
+                    "type": "string",
+                    "example": "203.0.113.7"
+                },
+                "purpose": {
+                    "type": "string",
+                    "enum": [
+                        "marketing_emails",
+                        "analytics"
+                    ],
+                    "example": "marketing_emails"
+                },
+                "recordedAt": {
+                    "type": "string"
+                },
+                "userAgent": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string",
+                    "example": "2024-05"
+                }
+            }
+        },
+        "models.Customer": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "somchai@example.com"
+                },
+                "lastVisit": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Somchai"
+                },
+                "telephone": {
+                    "type": "string",
+                    "example": "0812345678"
+                },
+                "userId": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "visits": {
+                    "type": "integer",
+                    "example": 7
+                }
+            }
+        },
+        "models.DataExport": {
+            "type": "object",
+            "properties": {
+                "completedAt": {
+                    "type": "string"
+                },
+                "downloadUrl": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "requestedAt": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "processing",
+                        "ready",
+                        "failed"
+                    ]
+                }
+            }
+        },
+        "models.DepositRule": {
+            "type": "object",
+            "properties": {
+                "deposit": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "endTime": {
+                    "type": "string",
+                    "example": "23:00"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "minReservations": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Weekend dinner"
+                },
+                "restaurantId": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "startTime": {
+                    "description": "StartTime and EndTime bound the window in the time zone of the restaurant, it may run past midnight",
+                    "type": "string",
+                    "example": "18:00"
+                },
+                "weekdays": {
+                    "description": "Weekdays are the ISO days of the week the window starts on, 1 for Monday to 7 for Sunday, every day when empty",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        5,
+                        6
+                    ]
+                }
+            }
+        },
+        "models.DisplayAmount": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer",
+                    "example": 550
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "rate": {
+                    "description": "Rate is the value of one major unit of the stored currency in the display currency",
+                    "type": "number",
+                    "example": 0.0275
+                },
+                "text": {
+                    "type": "string",
+                    "example": "USD 5.50"
+                }
+            }
+        },
+        "models.EmailTemplate": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "example": "Hi {{.name}}, ..."
+                },
+                "customized": {
+                    "description": "Customized is set when the copy was edited and differs from the built-in one",
+                    "type": "boolean"
+                },
+                "key": {
+                    "type": "string",
+                    "example": "password_reset"
+                },
+                "subject": {
+                    "type": "string",
+                    "example": "Reset your RedRice password"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "updatedBy": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "variables": {
+                    "description": "Variables are the names the subject and body can use",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "name",
+                        "link"
+                    ]
+                }
+            }
+        },
+        "models.ExperimentAssignment": {
+            "type": "object",
+            "properties": {
+                "experiment": {
+                    "type": "string",
+                    "example": "search_results_layout"
+                },
+                "variant": {
+                    "type": "string",
+                    "example": "compact"
+                }
+            }
+        },
+        "models.Favorite": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "restaurant": {
+                    "$ref": "#/definitions/models.Restaurant"
+                },
+                "restaurantId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.FeedItem": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "example": "Try our new lunch set, available weekdays from 11:00."
+                },
+                "changedFields": {
+                    "description": "ChangedFields lists the listing fields of a listing.updated item",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "imageUrl": {
+                    "type": "string"
+                },
+                "restaurant": {
+                    "$ref": "#/definitions/models.Restaurant"
+                },
+                "restaurantId": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "New lunch set"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "photo.added",
+                        "listing.updated",
+                        "announcement"
+                    ],
+                    "example": "announcement"
+                }
+            }
+        },
+        "models.FieldChange": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string",
+                    "example": "name"
+                },
+                "new": {
+                    "type": "string",
+                    "example": "RedRice Bistro"
+                },
+                "old": {
+                    "type": "string",
+                    "example": "RedRice"
+                }
+            }
+        },
+        "models.Incident": {
+            "type": "object",
+            "properties": {
+                "components": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "impact": {
+                    "type": "string",
+                    "enum": [
+                        "minor",
+                        "major",
+                        "critical"
+                    ]
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Our email provider is having issues, confirmations may arrive late."
+                },
+                "resolvedAt": {
+                    "type": "string"
+                },
+                "startedAt": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "investigating",
+                        "identified",
+                        "monitoring",
+                        "resolved"
+                    ]
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Emails are delayed"
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.LeadFill": {
+            "type": "object",
+            "properties": {
+                "fillRate": {
+                    "description": "FillRate is the share of the bookings the slot ends up with that were already made",
+                    "type": "number",
+                    "example": 0.75
+                },
+                "leadHours": {
+                    "type": "integer",
+                    "example": 24
+                },
+                "reservations": {
+                    "type": "number",
+                    "example": 4.5
+                },
+                "unavailable": {
+                    "description": "Unavailable is the share of the slots that could not be booked anymore",
+                    "type": "number",
+                    "example": 0.1
+                }
+            }
+        },
+        "models.LocalTimes": {
+            "type": "object",
+            "properties": {
+                "dateTime": {
+                    "description": "DateTime and ExitTime are ISO-8601 with the offset of the restaurant",
+                    "type": "string",
+                    "example": "2024-12-02T19:00:00+07:00"
+                },
+                "display": {
+                    "description": "Display holds a ready to show string per supported language",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "example": {
+                        " 19": "00–21:00",
+                        "en": "Mon 2 Dec 2024",
+                        "th": "จ. 2 ธ.ค. 2567 19:00–21:00"
+                    }
+                },
+                "exitTime": {
+                    "type": "string",
+                    "example": "2024-12-02T21:00:00+07:00"
+                },
+                "formatted": {
+                    "description": "Formatted is set to the visit written for the Accept-Language of the client",
+                    "type": "string",
+                    "example": "Mon 2 Dec 2567, 19:00–21:00"
+                },
+                "timezone": {
+                    "type": "string",
+                    "example": "Asia/Bangkok"
+                }
+            }
+        },
+        "models.MenuCategory": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MenuItem"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Curries"
+                },
+                "position": {
+                    "description": "Position orders the categories of the menu, lowest first",
+                    "type": "integer",
+                    "example": 0
+                },
+                "restaurantId": {
+                    "type": "integer",
+                    "example": 7
+                }
+            }
+        },
+        "models.MenuItem": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "description": "Unavailable items stay on the menu marked as sold out",
+                    "type": "boolean"
+                },
+                "categoryId": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Spicy, with Thai basil and eggplant"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "imageUrl": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Green curry with chicken"
+                },
+                "position": {
+                    "description": "Position orders the items of the category, lowest first",
+                    "type": "integer",
+                    "example": 0
+                },
+                "price": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "restaurantId": {
+                    "type": "integer",
+                    "example": 7
+                }
+            }
+        },
+        "models.Money": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "integer",
+                    "example": 20000
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "THB"
+                },
+                "display": {
+                    "description": "Display is set when the client asked for the amount in another currency",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.DisplayAmount"
+                        }
+                    ]
+                },
+                "formatted": {
+                    "description": "Formatted is set to the amount written for the language of the client",
+                    "type": "string",
+                    "example": "200.00 บาท"
+                }
+            }
+        },
+        "models.NotificationStats": {
+            "type": "object",
+            "properties": {
+                "channel": {
+                    "type": "string",
+                    "example": "email"
+                },
+                "queued": {
+                    "description": "Queued wait for the next digest of the user",
+                    "type": "integer",
+                    "example": 8
+                },
+                "sent": {
+                    "description": "Sent were delivered right away or with a digest",
+                    "type": "integer",
+                    "example": 120
+                },
+                "suppressed": {
+                    "description": "Suppressed were identical to one sent shortly before and dropped",
+                    "type": "integer",
+                    "example": 15
+                }
+            }
+        },
+        "models.OwnerSummary": {
+            "type": "object",
+            "properties": {
+                "pendingConfirmations": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "restaurants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RestaurantSummary"
+                    }
+                },
+                "todayReservations": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "models.PayoutStatement": {
+            "type": "object",
+            "properties": {
+                "commission": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "commissionRate": {
+                    "type": "number",
+                    "example": 0.1
+                },
+                "grossDeposits": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StatementLine"
+                    }
+                },
+                "payout": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "periodEnd": {
+                    "type": "string"
+                },
+                "periodStart": {
+                    "type": "string"
+                },
+                "reservations": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "restaurantId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "restaurantName": {
+                    "type": "string",
+                    "example": "RedRice"
+                }
+            }
+        },
+        "models.PayoutSummary": {
+            "type": "object",
+            "properties": {
+                "commission": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "commissionRate": {
+                    "type": "number",
+                    "example": 0.1
+                },
+                "grossDeposits": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "payout": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "periodEnd": {
+                    "type": "string"
+                },
+                "periodStart": {
+                    "type": "string"
+                },
+                "reservations": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "restaurantId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "restaurantName": {
+                    "type": "string",
+                    "example": "RedRice"
+                }
+            }
+        },
+        "models.Photo": {
+            "type": "object",
+            "properties": {
+                "caption": {
+                    "type": "string"
+                },
+                "commentId": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "imageUrl": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string",
+                    "enum": [
+                        "owner",
+                        "review"
+                    ]
+                },
+                "thumbnailUrl": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "integer"
+                },
+                "userName": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ProcessingActivity": {
+            "type": "object",
+            "properties": {
+                "dataCategories": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "email",
+                        "name"
+                    ]
+                },
+                "description": {
+                    "type": "string"
+                },
+                "legalBasis": {
+                    "type": "string",
+                    "enum": [
+                        "contract",
+                        "consent",
+                        "legitimate_interest",
+                        "legal_obligation"
+                    ],
+                    "example": "consent"
+                },
+                "purpose": {
+                    "type": "string",
+                    "example": "marketing_emails"
+                },
+                "requiresConsent": {
+                    "description": "RequiresConsent is set for the purposes users can opt in to and out of",
+                    "type": "boolean"
+                },
+                "retention": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version is the current wording of the consent, users who agreed to an older one are asked again",
+                    "type": "string",
+                    "example": "2024-05"
+                }
+            }
+        },
+        "models.QualityCheck": {
+            "type": "object",
+            "properties": {
+                "hint": {
+                    "type": "string",
+                    "example": "Upload photos to the gallery, listings with photos get more bookings"
+                },
+                "key": {
+                    "type": "string",
+                    "enum": [
+                        "photos",
+                        "hours",
+                        "location",
+                        "menu",
+                        "description"
+                    ],
+                    "example": "photos"
+                },
+                "passed": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "points": {
+                    "type": "integer",
+                    "example": 25
+                }
+            }
+        },
+        "models.QueueEntry": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "joinedAt": {
+                    "type": "string"
+                },
+                "partySize": {
+                    "type": "integer"
+                },
+                "restaurantId": {
+                    "type": "integer"
+                },
+                "seatedAt": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.RenderedEmail": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "subject": {
+                    "type": "string",
+                    "example": "Reset your RedRice password"
+                }
+            }
+        },
+        "models.Reservation": {
+            "type": "object",
+            "properties": {
+                "dateTime": {
+                    "type": "string"
+                },
+                "deposit": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "depositBreakdown": {
+                    "description": "DepositBreakdown splits the deposit into service charge and VAT as charged at booking",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ChargeBreakdown"
+                        }
+                    ]
+                },
+                "depositRuleId": {
+                    "type": "integer"
+                },
+                "exitTime": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "local": {
+                    "description": "Local is set when the restaurant is loaded with the reservation",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.LocalTimes"
+                        }
+                    ]
+                },
+                "receiptIssuedAt": {
+                    "type": "string"
+                },
+                "receiptUrl": {
+                    "type": "string"
+                },
+                "respondedAt": {
+                    "description": "RespondedAt is when the restaurant confirmed or declined the reservation",
+                    "type": "string"
+                },
+                "restaurant": {
+                    "$ref": "#/definitions/models.Restaurant"
+                },
+                "restaurantId": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "confirmed",
+                        "declined",
+                        "cancelled",
+                        "completed"
+                    ]
+                },
+                "tableNum": {
+                    "type": "integer"
+                },
+                "user": {
+                    "$ref": "#/definitions/models.User"
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ReservationAction": {
+            "type": "object",
+            "properties": {
+                "reservationId": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "confirmed",
+                        "declined"
+                    ],
+                    "example": "confirmed"
+                }
+            }
+        },
+        "models.ReservationActionResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "reservation is not pending"
+                },
+                "ok": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "reservationId": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "status": {
+                    "type": "string",
+                    "example": "confirmed"
+                }
+            }
+        },
+        "models.ReservationTimeline": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string",
+                    "example": "2024-05-01"
+                },
+                "tables": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TableTimeline"
+                    }
+                }
+            }
+        },
+        "models.Restaurant": {
+            "type": "object",
+            "required": [
+                "commentCount",
+                "rating"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Category"
+                    }
+                },
+                "closeTime": {
+                    "type": "string"
+                },
+                "commentCount": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "deposit": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "facebook": {
+                    "type": "string"
+                },
+                "favoriteCount": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "imageUrl": {
+                    "type": "string"
+                },
+                "instagram": {
+                    "type": "string"
+                },
+                "latitude": {
+                    "type": "number",
+                    "example": 13.7563
+                },
+                "longitude": {
+                    "type": "number",
+                    "example": 100.5018
+                },
+                "maxAdvanceDays": {
+                    "type": "integer"
+                },
+                "minNoticeMinutes": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "openTime": {
+                    "type": "string"
+                },
+                "priceRange": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "pricesIncludeTax": {
+                    "type": "boolean"
+                },
+                "rating": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "requireVerifiedPhone": {
+                    "type": "boolean"
+                },
+                "serviceChargeRate": {
+                    "type": "number"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "draft",
+                        "pending",
+                        "active",
+                        "hidden",
+                        "suspended"
+                    ]
+                },
+                "statusChangedAt": {
+                    "type": "string"
+                },
+                "statusReason": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TagCount"
+                    }
+                },
+                "taxId": {
+                    "type": "string"
+                },
+                "telephone": {
+                    "type": "string"
+                },
+                "timezone": {
+                    "type": "string",
+                    "example": "Asia/Bangkok"
+                },
+                "vatRegistered": {
+                    "type": "boolean"
+                },
+                "verified": {
+                    "type": "boolean"
+                },
+                "verifiedCommentCount": {
+                    "type": "integer"
+                },
+                "verifiedRating": {
+                    "type": "number"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.RestaurantClaim": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "documents": {
+                    "description": "Documents are short-lived links to the proof documents, only listed to admins",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "I am the manager of this restaurant"
+                },
+                "rejectionReason": {
+                    "type": "string"
+                },
+                "restaurantId": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "reviewedAt": {
+                    "type": "string"
+                },
+                "reviewedBy": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "approved",
+                        "rejected"
+                    ]
+                },
+                "userId": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "models.RestaurantImage": {
+            "type": "object",
+            "properties": {
+                "caption": {
+                    "type": "string"
+                },
+                "cover": {
+                    "description": "Cover is set on the image shown as the image of the restaurant",
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "imageUrl": {
+                    "type": "string"
+                },
+                "position": {
+                    "description": "Position orders the gallery, lowest first",
+                    "type": "integer"
+                },
+                "restaurantId": {
+                    "type": "integer"
+                },
+                "thumbnailUrl": {
+                    "type": "string"
+                },
+                "uploadedBy": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.RestaurantPerformance": {
+            "type": "object",
+            "properties": {
+                "confirmationMinutes": {
+                    "description": "ConfirmationMinutes is the average time between booking and the answer of the restaurant",
+                    "type": "number",
+                    "example": 42.5
+                },
+                "declineRate": {
+                    "type": "number",
+                    "example": 0.15
+                },
+                "declined": {
+                    "type": "integer",
+                    "example": 6
+                },
+                "responses": {
+                    "description": "Responses counts the reservations the restaurant confirmed or declined",
+                    "type": "integer",
+                    "example": 40
+                },
+                "restaurantId": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "restaurantName": {
+                    "type": "string",
+                    "example": "Baan Khanitha"
+                }
+            }
+        },
+        "models.RestaurantQuality": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.QualityCheck"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Baan Somtum"
+                },
+                "restaurantId": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "score": {
+                    "type": "integer",
+                    "example": 55
+                },
+                "status": {
+                    "type": "string",
+                    "example": "active"
+                }
+            }
+        },
+        "models.RestaurantResponse": {
+            "type": "object",
+            "properties": {
+                "ID": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "address": {
+                    "type": "string"
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Category"
+                    }
+                },
+                "closeTime": {
+                    "type": "string",
+                    "example": "22:00"
+                },
+                "commentCount": {
+                    "type": "number",
+                    "example": 12
+                },
+                "deposit": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "distance": {
+                    "description": "Distance is the distance in kilometers to the point of a nearby search",
+                    "type": "number",
+                    "example": 1.2
+                },
+                "facebook": {
+                    "type": "string"
+                },
+                "favoriteCount": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "gallery": {
+                    "description": "Gallery lists the gallery images in their order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RestaurantImage"
+                    }
+                },
+                "imageUrl": {
+                    "type": "string"
+                },
+                "instagram": {
+                    "type": "string"
+                },
+                "latitude": {
+                    "type": "number",
+                    "example": 13.7563
+                },
+                "longitude": {
+                    "type": "number",
+                    "example": 100.5018
+                },
+                "maxAdvanceDays": {
+                    "type": "integer"
+                },
+                "menu": {
+                    "description": "Menu is only included when requested",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MenuCategory"
+                    }
+                },
+                "minNoticeMinutes": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "openTime": {
+                    "type": "string",
+                    "example": "10:00"
+                },
+                "priceRange": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "pricesIncludeTax": {
+                    "type": "boolean"
+                },
+                "rating": {
+                    "type": "number",
+                    "example": 4.5
+                },
+                "requireVerifiedPhone": {
+                    "type": "boolean"
+                },
+                "serviceChargeRate": {
+                    "type": "number"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "draft",
+                        "pending",
+                        "active",
+                        "hidden",
+                        "suspended"
+                    ],
+                    "example": "active"
+                },
+                "statusReason": {
+                    "type": "string",
+                    "example": "Photos need to show the restaurant"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TagCount"
+                    }
+                },
+                "taxId": {
+                    "type": "string"
+                },
+                "telephone": {
+                    "type": "string"
+                },
+                "timezone": {
+                    "type": "string",
+                    "example": "Asia/Bangkok"
+                },
+                "vatRegistered": {
+                    "type": "boolean"
+                },
+                "verified": {
+                    "type": "boolean"
+                },
+                "verifiedCommentCount": {
+                    "type": "integer",
+                    "example": 8
+                },
+                "verifiedRating": {
+                    "type": "number",
+                    "example": 4.7
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.RestaurantSummary": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "RedRice"
+                },
+                "pendingConfirmations": {
+                    "description": "PendingConfirmations counts the upcoming reservations waiting for a confirmation",
+                    "type": "integer",
+                    "example": 3
+                },
+                "recentReviews": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CommentResponse"
+                    }
+                },
+                "restaurantId": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "todayReservations": {
+                    "description": "TodayReservations counts the pending and confirmed reservations of the local day of the restaurant",
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "models.RestaurantTheme": {
+            "type": "object",
+            "properties": {
+                "accentColor": {
+                    "type": "string",
+                    "example": "#FFB300"
+                },
+                "backgroundColor": {
+                    "type": "string",
+                    "example": "#FAFAFA"
+                },
+                "logoUrl": {
+                    "type": "string"
+                },
+                "primaryColor": {
+                    "type": "string",
+                    "example": "#C62828"
+                },
+                "restaurantId": {
+                    "type": "integer"
+                },
+                "secondaryColor": {
+                    "type": "string",
+                    "example": "#FFFFFF"
+                },
+                "textColor": {
+                    "type": "string",
+                    "example": "#212121"
+                }
+            }
+        },
+        "models.RestaurantVersion": {
+            "type": "object",
+            "properties": {
+                "changedBy": {
+                    "type": "integer"
+                },
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FieldChange"
+                    }
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "restaurantId": {
+                    "type": "integer"
+                },
+                "revertedTo": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ReviewAuthor": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "imageUrl": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Somchai"
+                }
+            }
+        },
+        "models.SLAAlert": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "metric": {
+                    "type": "string",
+                    "enum": [
+                        "confirmation_latency",
+                        "decline_rate"
+                    ],
+                    "example": "decline_rate"
+                },
+                "resolvedAt": {
+                    "type": "string"
+                },
+                "restaurantId": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "restaurantName": {
+                    "type": "string",
+                    "example": "Baan Khanitha"
+                },
+                "threshold": {
+                    "type": "number",
+                    "example": 0.3
+                },
+                "value": {
+                    "type": "number",
+                    "example": 0.45
+                }
+            }
+        },
+        "models.ScheduledChange": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "createdBy": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "publishAt": {
+                    "type": "string"
+                },
+                "publishedAt": {
+                    "type": "string"
+                },
+                "restaurantId": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SearchBoost": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "Restaurants with reviews from completed reservations"
+                },
+                "key": {
+                    "type": "string",
+                    "enum": [
+                        "verified",
+                        "photos",
+                        "recent_activity"
+                    ],
+                    "example": "verified"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "updatedBy": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "weight": {
+                    "type": "number",
+                    "example": 0.2
+                }
+            }
+        },
+        "models.Session": {
+            "type": "object",
+            "properties": {
+                "current": {
+                    "type": "boolean"
+                },
+                "expiresAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "impersonatedBy": {
+                    "description": "ImpersonatedBy is the admin the session was issued to while acting as the user",
+                    "type": "integer"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "issuedAt": {
+                    "type": "string"
+                },
+                "userAgent": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Slot": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "boolean"
+                },
+                "end": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "start": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SlotFill": {
+            "type": "object",
+            "properties": {
+                "byLead": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.LeadFill"
+                    }
+                },
+                "fullAtLeadHours": {
+                    "description": "FullAtLeadHours is the earliest recorded lead time the slot already had\nall its bookings at, on average, nil when it never had reservations",
+                    "type": "integer",
+                    "example": 6
+                },
+                "reservations": {
+                    "description": "Reservations is the average number of reservations at the start of the slot",
+                    "type": "number",
+                    "example": 6
+                },
+                "slots": {
+                    "description": "Slots counts the slots recorded at their start",
+                    "type": "integer",
+                    "example": 4
+                },
+                "time": {
+                    "type": "string",
+                    "example": "19:00"
+                },
+                "weekday": {
+                    "description": "Weekday is the ISO day of the week, 1 for Monday to 7 for Sunday",
+                    "type": "integer",
+                    "example": 5
+                }
+            }
+        },
+        "models.SpecialHours": {
+            "type": "object",
+            "properties": {
+                "closeTime": {
+                    "type": "string",
+                    "example": "02:00"
+                },
+                "closed": {
+                    "description": "Closed days have no opening hours",
+                    "type": "boolean"
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2024-12-31"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "openTime": {
+                    "type": "string",
+                    "example": "10:00"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "New Year's Eve"
+                },
+                "restaurantId": {
+                    "type": "integer",
+                    "example": 7
+                }
+            }
+        },
+        "models.StatementLine": {
+            "type": "object",
+            "properties": {
+                "commission": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "dateTime": {
+                    "type": "string"
+                },
+                "deposit": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "payout": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "reservationId": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "serviceCharge": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "vat": {
+                    "$ref": "#/definitions/models.Money"
+                }
+            }
+        },
+        "models.TableTimeline": {
+            "type": "object",
+            "properties": {
+                "blocks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TimelineBlock"
+                    }
+                },
+                "tableNum": {
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "models.TagCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "sentiment": {
+                    "type": "string"
+                },
+                "tag": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TimelineBlock": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "type": "string"
+                },
+                "overlaps": {
+                    "description": "Overlaps is set when another reservation occupies the table at the same time",
+                    "type": "boolean"
+                },
+                "reservationId": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "start": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "confirmed",
+                        "completed"
+                    ],
+                    "example": "confirmed"
+                },
+                "userId": {
+                    "type": "integer",
+                    "example": 5
+                }
+            }
+        },
+        "models.TrendingRestaurant": {
+            "type": "object",
+            "properties": {
+                "ID": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "address": {
+                    "type": "string"
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Category"
+                    }
+                },
+                "closeTime": {
+                    "type": "string",
+                    "example": "22:00"
+                },
+                "commentCount": {
+                    "type": "number",
+                    "example": 12
+                },
+                "deposit": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "distance": {
+                    "description": "Distance is the distance in kilometers to the point of a nearby search",
+                    "type": "number",
+                    "example": 1.2
+                },
+                "facebook": {
+                    "type": "string"
+                },
+                "favoriteCount": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "gallery": {
+                    "description": "Gallery lists the gallery images in their order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RestaurantImage"
+                    }
+                },
+                "imageUrl": {
+                    "type": "string"
+                },
+                "instagram": {
+                    "type": "string"
+                },
+                "latitude": {
+                    "type": "number",
+                    "example": 13.7563
+                },
+                "longitude": {
+                    "type": "number",
+                    "example": 100.5018
+                },
+                "maxAdvanceDays": {
+                    "type": "integer"
+                },
+                "menu": {
+                    "description": "Menu is only included when requested",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MenuCategory"
+                    }
+                },
+                "minNoticeMinutes": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "openTime": {
+                    "type": "string",
+                    "example": "10:00"
+                },
+                "priceRange": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "pricesIncludeTax": {
+                    "type": "boolean"
+                },
+                "rating": {
+                    "type": "number",
+                    "example": 4.5
+                },
+                "requireVerifiedPhone": {
+                    "type": "boolean"
+                },
+                "serviceChargeRate": {
+                    "type": "number"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "draft",
+                        "pending",
+                        "active",
+                        "hidden",
+                        "suspended"
+                    ],
+                    "example": "active"
+                },
+                "statusReason": {
+                    "type": "string",
+                    "example": "Photos need to show the restaurant"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TagCount"
+                    }
+                },
+                "taxId": {
+                    "type": "string"
+                },
+                "telephone": {
+                    "type": "string"
+                },
+                "timezone": {
+                    "type": "string",
+                    "example": "Asia/Bangkok"
+                },
+                "trending": {
+                    "$ref": "#/definitions/models.TrendingScore"
+                },
+                "vatRegistered": {
+                    "type": "boolean"
+                },
+                "verified": {
+                    "type": "boolean"
+                },
+                "verifiedCommentCount": {
+                    "type": "integer",
+                    "example": 8
+                },
+                "verifiedRating": {
+                    "type": "number",
+                    "example": 4.7
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.TrendingScore": {
+            "type": "object",
+            "properties": {
+                "rating": {
+                    "type": "number",
+                    "example": 4.5
+                },
+                "reservations": {
+                    "description": "Reservations is the number of reservations made in the window, cancelled and declined ones left out",
+                    "type": "integer",
+                    "example": 18
+                },
+                "score": {
+                    "type": "number",
+                    "example": 0.83
+                },
+                "viewsPerDay": {
+                    "description": "ViewsPerDay is the average number of users viewing the restaurant page a day in the window",
+                    "type": "number",
+                    "example": 42.5
+                }
+            }
+        },
+        "models.User": {
+            "type": "object",
+            "properties": {
+                "deletionScheduledAt": {
+                    "description": "DeletionScheduledAt is when the account will be deleted, unless the user logs in before",
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "imageUrl": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "restaurant_id": {
+                    "type": "integer"
+                },
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "admin",
+                        "restaurant_owner",
+                        "user",
+                        "guest"
+                    ]
+                },
+                "suspendedAt": {
+                    "type": "string"
+                },
+                "suspendedUntil": {
+                    "type": "string"
+                },
+                "suspensionReason": {
+                    "type": "string"
+                },
+                "telephone": {
+                    "type": "string"
+                },
+                "telephoneVerifiedAt": {
+                    "type": "string"
+                },
+                "twoFactorEnabled": {
+                    "type": "boolean"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.UserPreferences": {
+            "type": "object",
+            "properties": {
+                "defaultPartySize": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "dietaryRestrictions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "vegetarian",
+                        "nut_allergy"
+                    ]
+                },
+                "emailDelivery": {
+                    "description": "EmailDelivery and SMSDelivery choose between immediate notifications and a daily digest",
+                    "type": "string",
+                    "enum": [
+                        "immediate",
+                        "daily_digest"
+                    ],
+                    "example": "immediate"
+                },
+                "hideContact": {
+                    "description": "HideContact keeps the email and telephone of the user out of the customer lists and reservations shown to restaurants",
+                    "type": "boolean",
+                    "example": false
+                },
+                "language": {
+                    "type": "string",
+                    "enum": [
+                        "th",
+                        "en"
+                    ],
+                    "example": "th"
+                },
+                "reviewAnonymously": {
+                    "description": "ReviewAnonymously is used for new reviews that do not say whether they are anonymous",
+                    "type": "boolean",
+                    "example": false
+                },
+                "smsDelivery": {
+                    "type": "string",
+                    "enum": [
+                        "immediate",
+                        "daily_digest"
+                    ],
+                    "example": "daily_digest"
+                }
+            }
+        },
+        "models.UserResponse": {
+            "type": "object",
+            "properties": {
+                "ID": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "deletionScheduledAt": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "somchai@example.com"
+                },
+                "imageUrl": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Somchai"
+                },
+                "restaurant_id": {
+                    "type": "integer"
+                },
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "admin",
+                        "restaurant_owner",
+                        "user",
+                        "guest"
+                    ]
+                },
+                "suspendedAt": {
+                    "type": "string"
+                },
+                "suspendedUntil": {
+                    "type": "string"
+                },
+                "suspensionReason": {
+                    "type": "string"
+                },
+                "telephone": {
+                    "type": "string",
+                    "example": "0812345678"
+                },
+                "telephoneVerifiedAt": {
+                    "type": "string"
+                },
+                "twoFactorEnabled": {
+                    "type": "boolean"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.WaitEstimate": {
+            "type": "object",
+            "properties": {
+                "averageSeatingMinutes": {
+                    "type": "number"
+                },
+                "estimatedWaitMinutes": {
+                    "type": "integer"
+                },
+                "partiesWaiting": {
+                    "type": "integer"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "restaurantId": {
+                    "type": "integer"
+                },
+                "tablesTurningOver": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.WarehouseDataset": {
+            "type": "object",
+            "properties": {
+                "columns": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "reservations"
+                }
+            }
+        },
+        "models.WarehouseExport": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2024-05-01"
+                },
+                "files": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.WarehouseFile"
+                    }
+                },
+                "schemaVersion": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "models.WarehouseFile": {
+            "type": "object",
+            "properties": {
+                "dataset": {
+                    "type": "string",
+                    "example": "reservations"
+                },
+                "key": {
+                    "type": "string",
+                    "example": "warehouse/v1/reservations/date=2024-05-01/part-0.csv"
+                },
+                "rows": {
+                    "type": "integer",
+                    "example": 120
+                }
+            }
+        },
+        "routers.RouteAccess": {
+            "type": "object",
+            "properties": {
+                "method": {
+                    "type": "string",
+                    "example": "GET"
+                },
+                "path": {
+                    "type": "string",
+                    "example": "/api/v1/restaurants/:id"
+                },
+                "role": {
+                    "type": "string",
+                    "example": "user"
+                },
+                "scope": {
+                    "description": "Scope is set on routes that also accept partner API keys with this scope",
+                    "type": "string",
+                    "example": "restaurants:read"
+                }
+            }
+        },
+        "utils.Event": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "restaurantId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "type": {
+                    "type": "string",
+                    "example": "queue.updated"
+                }
+            }
+        },
+        "v1.ActivityPage": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Activity"
+                    }
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "v1.AnalyticsEventsRequest": {
+            "type": "object",
+            "required": [
+                "events"
+            ],
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/models.AnalyticsEvent"
+                    }
+                }
+            }
+        },
+        "v1.AnnouncementRequest": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "example": "Try our new lunch set, available weekdays from 11:00."
+                },
+                "title": {
+                    "type": "string",
+                    "example": "New lunch set"
+                }
+            }
+        },
+        "v1.AuditPage": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AuditEntry"
+                    }
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "v1.BackfillRunPage": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BackfillRun"
+                    }
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "v1.BackfillRunRequest": {
+            "type": "object",
+            "properties": {
+                "batchDelayMs": {
+                    "description": "BatchDelayMs is the pause between batches, 500 by default",
+                    "type": "integer",
+                    "maximum": 60000,
+                    "minimum": 0,
+                    "example": 500
+                },
+                "batchSize": {
+                    "description": "BatchSize is the number of rows per batch, 200 by default",
+                    "type": "integer",
+                    "maximum": 5000,
+                    "minimum": 1,
+                    "example": 200
+                }
+            }
+        },
+        "v1.BanRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "example": "Repeated no-shows"
+                },
+                "until": {
+                    "description": "Until is when the suspension ends, omit it to suspend indefinitely",
+                    "type": "string",
+                    "example": "2024-12-31T00:00:00+07:00"
+                }
+            }
+        },
+        "v1.BanResponse": {
+            "type": "object",
+            "properties": {
+                "cancelledReservations": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "user": {
+                    "$ref": "#/definitions/models.UserResponse"
+                }
+            }
+        },
+        "v1.BatchReservationErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "at least one action of the batch was rejected"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReservationActionResult"
+                    }
+                }
+            }
+        },
+        "v1.BatchReservationRequest": {
+            "type": "object",
+            "properties": {
+                "actions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReservationAction"
+                    }
+                }
+            }
+        },
+        "v1.BatchReservationResponse": {
+            "type": "object",
+            "properties": {
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReservationActionResult"
+                    }
+                }
+            }
+        },
+        "v1.BlackoutRequest": {
+            "type": "object",
+            "properties": {
+                "endTime": {
+                    "type": "string",
+                    "example": "2024-04-01T17:00:00+07:00"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Kitchen closed"
+                },
+                "startTime": {
+                    "type": "string",
+                    "example": "2024-04-01T14:00:00+07:00"
+                }
+            }
+        },
+        "v1.BookingPolicyRequest": {
+            "type": "object",
+            "properties": {
+                "deposit": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "maxAdvanceDays": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "minNoticeMinutes": {
+                    "type": "integer",
+                    "example": 60
+                },
+                "requireVerifiedPhone": {
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "v1.BulkInvitationResponse": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "invited": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.InvitationResult"
+                    }
+                }
+            }
+        },
+        "v1.CategoryRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "Vegan friendly"
+                },
+                "slug": {
+                    "description": "Slug defaults to the name in lowercase with dashes",
+                    "type": "string",
+                    "example": "vegan-friendly"
+                }
+            }
+        },
+        "v1.ClaimRejectionRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "example": "The business registration does not match the restaurant"
+                }
+            }
+        },
+        "v1.CommentRequest": {
+            "type": "object",
+            "properties": {
+                "anonymous": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "dateTime": {
+                    "type": "string"
+                },
+                "displayName": {
+                    "type": "string",
+                    "example": "Foodie from Bangkok"
+                },
+                "myComment": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "number",
+                    "example": 4.5
+                },
+                "reservationId": {
+                    "type": "integer"
+                },
+                "restaurantId": {
+                    "type": "integer",
+                    "example": 7
+                }
+            }
+        },
+        "v1.ComponentStatus": {
+            "type": "object",
+            "properties": {
+                "checkedAt": {
+                    "type": "string"
+                },
+                "latencyMs": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "name": {
+                    "type": "string",
+                    "example": "database"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "operational",
+                        "degraded",
+                        "down",
+                        "not_configured"
+                    ]
+                }
+            }
+        },
+        "v1.ConsentsRequest": {
+            "type": "object",
+            "required": [
+                "consents"
+            ],
+            "properties": {
+                "consents": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ConsentChange"
+                    }
+                }
+            }
+        },
+        "v1.ConsentsResponse": {
+            "type": "object",
+            "properties": {
+                "consents": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Consent"
+                    }
+                },
+                "history": {
+                    "description": "History is the log of every consent given or withdrawn, oldest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ConsentEvent"
+                    }
+                }
+            }
+        },
+        "v1.CreateAPIKeyRequest": {
+            "type": "object",
+            "properties": {
+                "allowedOrigins": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "https://www.example.com"
+                    ]
+                },
+                "expiresAt": {
+                    "type": "string",
+                    "example": "2025-01-01T00:00:00Z"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Partner integration"
+                },
+                "restaurantId": {
+                    "type": "integer"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "restaurants:read"
+                    ]
+                }
+            }
+        },
+        "v1.CreateAPIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "apiKey": {
+                    "$ref": "#/definitions/models.APIKey"
+                },
+                "key": {
+                    "type": "string",
+                    "example": "rr_3f0c1d..."
+                }
+            }
+        },
+        "v1.CustomerPage": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Customer"
+                    }
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "v1.DepositRuleRequest": {
+            "type": "object",
+            "properties": {
+                "deposit": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "endTime": {
+                    "type": "string",
+                    "example": "23:00"
+                },
+                "minReservations": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Weekend dinner"
+                },
+                "startTime": {
+                    "type": "string",
+                    "example": "18:00"
+                },
+                "weekdays": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        5,
+                        6
+                    ]
+                }
+            }
+        },
+        "v1.EmailTemplatePreviewRequest": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "example": "Hi {{.name}}, ..."
+                },
+                "data": {
+                    "description": "Data overrides the sample values of the variables",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    },
+                    "example": {
+                        "name": "Somchai"
+                    }
+                },
+                "subject": {
+                    "description": "Subject and Body are a draft to render instead of the current copy",
+                    "type": "string",
+                    "example": "Reset your RedRice password"
+                }
+            }
+        },
+        "v1.EmailTemplateRequest": {
+            "type": "object",
+            "required": [
+                "body",
+                "subject"
+            ],
+            "properties": {
+                "body": {
+                    "type": "string",
+                    "example": "Hi {{.name}}, ..."
+                },
+                "subject": {
+                    "type": "string",
+                    "example": "Reset your RedRice password"
+                }
+            }
+        },
+        "v1.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "Description of the error occurred"
+                }
+            }
+        },
+        "v1.ExchangeRatesResponse": {
+            "type": "object",
+            "properties": {
+                "base": {
+                    "type": "string",
+                    "example": "THB"
+                },
+                "rates": {
+                    "description": "Rates is the value of one unit of the base currency by currency",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "updatedAt": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.FavoritePage": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Favorite"
+                    }
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "v1.FavoriteStatus": {
+            "type": "object",
+            "properties": {
+                "favoriteCount": {
+                    "type": "integer",
+                    "example": 128
+                },
+                "favorited": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "restaurantId": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "v1.FeedPage": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FeedItem"
+                    }
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "v1.FollowStatus": {
+            "type": "object",
+            "properties": {
+                "following": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "restaurantId": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "v1.GalleryOrderRequest": {
+            "type": "object",
+            "required": [
+                "imageIds"
+            ],
+            "properties": {
+                "imageIds": {
+                    "description": "ImageIDs lists every image of the gallery in the new order",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        4,
+                        2,
+                        7
+                    ]
+                }
+            }
+        },
+        "v1.GalleryUploadResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "Error uploading image"
+                },
+                "file": {
+                    "type": "string",
+                    "example": "terrace.jpg"
+                },
+                "image": {
+                    "$ref": "#/definitions/models.RestaurantImage"
+                }
+            }
+        },
+        "v1.ImportResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": ""
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Baan Somtum"
+                },
+                "restaurantId": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "row": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "imported",
+                        "failed"
+                    ],
+                    "example": "imported"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "v1.IncidentRequest": {
+            "type": "object",
             "properties": {
+                "components": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "email"
+                    ]
+                },
+                "impact": {
+                    "type": "string",
+                    "example": "minor"
+                },
                 "message": {
                     "type": "string",
-                    "example": "Login successful"
+                    "example": "Our email provider is having issues, confirmations may arrive late."
+                },
+                "startedAt": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "investigating"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Emails are delayed"
+                }
+            }
+        },
+        "v1.InvitationResult": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "staff@example.com"
+                },
+                "error": {
+                    "type": "string",
+                    "example": ""
+                },
+                "row": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "status": {
+                    "type": "string",
+                    "example": "invited"
+                }
+            }
+        },
+        "v1.JoinQueueRequest": {
+            "type": "object",
+            "properties": {
+                "partySize": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "v1.LimitsResponse": {
+            "type": "object",
+            "properties": {
+                "phoneCodes": {
+                    "description": "PhoneCodes counts the verification codes sent during the last hour",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v1.UsageQuota"
+                        }
+                    ]
+                },
+                "requests": {
+                    "$ref": "#/definitions/middleware.Quota"
+                },
+                "reservations": {
+                    "$ref": "#/definitions/v1.UsageQuota"
+                }
+            }
+        },
+        "v1.MenuCategoryRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "Curries"
+                },
+                "position": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "v1.MenuItemRequest": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "description": "Available defaults to true",
+                    "type": "boolean",
+                    "example": true
+                },
+                "categoryId": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Spicy, with Thai basil and eggplant"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Green curry with chicken"
+                },
+                "position": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "price": {
+                    "$ref": "#/definitions/models.Money"
+                }
+            }
+        },
+        "v1.MergeUsersRequest": {
+            "type": "object",
+            "properties": {
+                "sourceId": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "targetId": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "v1.PhoneCodeRequest": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "123456"
+                }
+            }
+        },
+        "v1.PhoneMessageResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Verification code sent"
+                }
+            }
+        },
+        "v1.PhotoApprovalRequest": {
+            "type": "object",
+            "properties": {
+                "approved": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "v1.PhotoPage": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Photo"
+                    }
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "v1.PreferencesRequest": {
+            "type": "object",
+            "properties": {
+                "defaultPartySize": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "dietaryRestrictions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "vegetarian",
+                        "nut_allergy"
+                    ]
+                },
+                "emailDelivery": {
+                    "type": "string",
+                    "enum": [
+                        "immediate",
+                        "daily_digest"
+                    ],
+                    "example": "immediate"
+                },
+                "hideContact": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "language": {
+                    "type": "string",
+                    "enum": [
+                        "th",
+                        "en"
+                    ],
+                    "example": "th"
+                },
+                "reviewAnonymously": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "smsDelivery": {
+                    "type": "string",
+                    "enum": [
+                        "immediate",
+                        "daily_digest"
+                    ],
+                    "example": "daily_digest"
+                }
+            }
+        },
+        "v1.QueueEntryResponse": {
+            "type": "object",
+            "properties": {
+                "entry": {
+                    "$ref": "#/definitions/models.QueueEntry"
+                },
+                "estimate": {
+                    "$ref": "#/definitions/models.WaitEstimate"
+                }
+            }
+        },
+        "v1.ReceiptMessageResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Receipt sent to john@example.com"
+                }
+            }
+        },
+        "v1.ReservationStatusRequest": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "confirmed",
+                        "declined",
+                        "cancelled",
+                        "completed"
+                    ],
+                    "example": "confirmed"
+                }
+            }
+        },
+        "v1.ReservationWaitResponse": {
+            "type": "object",
+            "properties": {
+                "changed": {
+                    "description": "Changed is false when the request timed out without a status change",
+                    "type": "boolean"
+                },
+                "reservation": {
+                    "$ref": "#/definitions/models.Reservation"
+                }
+            }
+        },
+        "v1.RestaurantClaimPage": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RestaurantClaim"
+                    }
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "v1.RestaurantHistoryPage": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RestaurantVersion"
+                    }
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "v1.RestaurantImportResponse": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "imported": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.ImportResult"
+                    }
+                }
+            }
+        },
+        "v1.RestaurantPage": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RestaurantResponse"
+                    }
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 20
                 },
-                "token": {
-                    "type": "string",
-                    "example": ""
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 42
                 }
             }
         },
-        "api.RegisterDetails": {
+        "v1.RestaurantPatchRequest": {
             "type": "object",
             "properties": {
-                "email": {
+                "address": {
                     "type": "string",
-                    "example": "john.doe@example.com"
+                    "example": "123 Sukhumvit Rd, Bangkok"
+                },
+                "closeTime": {
+                    "type": "string",
+                    "example": "22:00"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Thai comfort food"
+                },
+                "facebook": {
+                    "type": "string",
+                    "example": "redrice"
+                },
+                "instagram": {
+                    "type": "string",
+                    "example": "redrice"
+                },
+                "latitude": {
+                    "description": "Latitude and Longitude are set together, null clears both",
+                    "type": "number",
+                    "example": 13.7563
+                },
+                "longitude": {
+                    "type": "number",
+                    "example": 100.5018
                 },
                 "name": {
                     "type": "string",
-                    "example": "John Doe"
+                    "example": "RedRice Bistro"
                 },
-                "password": {
+                "openTime": {
                     "type": "string",
-                    "example": "securePassword123"
+                    "example": "10:00"
                 },
-                "restaurant_id": {
+                "priceRange": {
                     "type": "integer",
-                    "example": 0
+                    "example": 2
                 },
-                "role": {
+                "telephone": {
                     "type": "string",
-                    "example": "user"
+                    "example": "021234567"
                 },
-                "telephone": {
+                "timezone": {
                     "type": "string",
-                    "example": "123-456-7890"
+                    "example": "Asia/Bangkok"
                 }
             }
         },
-        "api.RegisterResponse": {
+        "v1.RestaurantQualityPage": {
             "type": "object",
             "properties": {
-                "message": {
-                    "type": "string",
-                    "example": "User registered successfully"
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RestaurantQuality"
+                    }
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 42
                 }
             }
         },
-        "models.Comment": {
+        "v1.RestaurantStatusRequest": {
             "type": "object",
+            "required": [
+                "status"
+            ],
             "properties": {
-                "dateTime": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "myComment": {
-                    "type": "string"
-                },
-                "rating": {
-                    "type": "number"
-                },
-                "restaurant": {
-                    "$ref": "#/definitions/models.Restaurant"
-                },
-                "restaurantId": {
-                    "type": "integer"
-                },
-                "user": {
-                    "$ref": "#/definitions/models.User"
+                "reason": {
+                    "description": "Reason is shown to the owner, e.g. why the restaurant was suspended",
+                    "type": "string",
+                    "example": "Missing business registration"
                 },
-                "userId": {
-                    "type": "integer"
+                "status": {
+                    "type": "string",
+                    "example": "active"
                 }
             }
         },
-        "models.Reservation": {
+        "v1.RoleRequest": {
             "type": "object",
             "properties": {
-                "dateTime": {
-                    "type": "string"
-                },
-                "exitTime": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "restaurant": {
-                    "$ref": "#/definitions/models.Restaurant"
+                "restaurant_id": {
+                    "type": "integer",
+                    "example": 3
                 },
-                "restaurantId": {
-                    "type": "integer"
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "admin",
+                        "restaurant_owner",
+                        "user"
+                    ],
+                    "example": "restaurant_owner"
+                }
+            }
+        },
+        "v1.SLAAlertPage": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SLAAlert"
+                    }
                 },
-                "tableNum": {
-                    "type": "integer"
+                "limit": {
+                    "type": "integer",
+                    "example": 20
                 },
-                "user": {
-                    "$ref": "#/definitions/models.User"
+                "page": {
+                    "type": "integer",
+                    "example": 1
                 },
-                "userId": {
-                    "type": "integer"
+                "total": {
+                    "type": "integer",
+                    "example": 42
                 }
             }
         },
-        "models.Restaurant": {
+        "v1.ScheduledChangeRequest": {
             "type": "object",
-            "required": [
-                "commentCount",
-                "rating"
-            ],
             "properties": {
                 "address": {
                     "type": "string"
                 },
                 "closeTime": {
-                    "type": "string"
-                },
-                "commentCount": {
-                    "type": "number",
-                    "minimum": 0
+                    "type": "string",
+                    "example": "21:00"
                 },
                 "description": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "Seasonal winter menu"
                 },
                 "facebook": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "integer"
-                },
                 "imageUrl": {
                     "type": "string"
                 },
@@ -1330,52 +14155,235 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "name": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "RedRice Winter"
                 },
                 "openTime": {
+                    "type": "string",
+                    "example": "11:00"
+                },
+                "priceRange": {
+                    "description": "PriceRange goes from 1 (budget) to 4 (fine dining), 0 clears it",
+                    "type": "integer",
+                    "example": 2
+                },
+                "publishAt": {
+                    "type": "string",
+                    "example": "2024-12-01T00:00:00Z"
+                },
+                "telephone": {
                     "type": "string"
+                }
+            }
+        },
+        "v1.SearchBoostRequest": {
+            "type": "object",
+            "required": [
+                "weight"
+            ],
+            "properties": {
+                "weight": {
+                    "type": "number",
+                    "example": 0.2
+                }
+            }
+        },
+        "v1.SpecialHoursRequest": {
+            "type": "object",
+            "properties": {
+                "closeTime": {
+                    "type": "string",
+                    "example": "02:00"
                 },
-                "rating": {
+                "closed": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "openTime": {
+                    "type": "string",
+                    "example": "10:00"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "New Year's Eve"
+                }
+            }
+        },
+        "v1.StatusResponse": {
+            "type": "object",
+            "properties": {
+                "components": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.ComponentStatus"
+                    }
+                },
+                "incidents": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Incident"
+                    }
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "operational",
+                        "degraded",
+                        "down"
+                    ]
+                }
+            }
+        },
+        "v1.TaxSettingsRequest": {
+            "type": "object",
+            "properties": {
+                "pricesIncludeTax": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "serviceChargeRate": {
                     "type": "number",
-                    "minimum": 0
+                    "example": 0.1
+                },
+                "taxId": {
+                    "type": "string",
+                    "example": "0105558012345"
+                },
+                "vatRegistered": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "v1.ThemeRequest": {
+            "type": "object",
+            "properties": {
+                "accentColor": {
+                    "type": "string",
+                    "example": "#FFB300"
+                },
+                "backgroundColor": {
+                    "type": "string",
+                    "example": "#FAFAFA"
+                },
+                "primaryColor": {
+                    "type": "string",
+                    "example": "#C62828"
+                },
+                "secondaryColor": {
+                    "type": "string",
+                    "example": "#FFFFFF"
+                },
+                "textColor": {
+                    "type": "string",
+                    "example": "#212121"
+                }
+            }
+        },
+        "v1.UsageQuota": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "remaining": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "used": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "v1.UserPatchRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "John Doe"
                 },
                 "telephone": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "0812345678"
                 }
             }
         },
-        "models.User": {
+        "v1.WarehouseManifest": {
+            "type": "object",
+            "properties": {
+                "bucket": {
+                    "type": "string",
+                    "example": "redrice"
+                },
+                "datasets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.WarehouseDataset"
+                    }
+                },
+                "exports": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.WarehouseExport"
+                    }
+                },
+                "schemaVersion": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "v1.WidgetReservationRequest": {
             "type": "object",
             "properties": {
+                "dateTime": {
+                    "type": "string",
+                    "example": "2024-12-01T19:00:00+07:00"
+                },
                 "email": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "john.doe@example.com"
                 },
-                "id": {
-                    "type": "integer"
+                "exitTime": {
+                    "type": "string",
+                    "example": "2024-12-01T21:00:00+07:00"
                 },
                 "name": {
-                    "type": "string"
-                },
-                "password": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "John Doe"
                 },
-                "restaurant_id": {
+                "tableNum": {
                     "type": "integer"
                 },
-                "role": {
-                    "type": "string"
-                },
                 "telephone": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "0812345678"
                 }
             }
         },
-        "v1.ErrorResponse": {
+        "v1.WidgetReservationResponse": {
             "type": "object",
             "properties": {
-                "error": {
+                "dateTime": {
+                    "type": "string"
+                },
+                "deposit": {
+                    "$ref": "#/definitions/models.Money"
+                },
+                "exitTime": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "local": {
+                    "$ref": "#/definitions/models.LocalTimes"
+                },
+                "status": {
                     "type": "string",
-                    "example": "Description of the error occurred"
+                    "example": "pending"
                 }
             }
         }
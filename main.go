@@ -12,6 +12,7 @@ import (
 
 	"github.com/joho/godotenv"
 	config "github.com/punchanabu/redrice-backend-go/config"
+	"github.com/punchanabu/redrice-backend-go/middleware"
 	routers "github.com/punchanabu/redrice-backend-go/routers"
 	"github.com/punchanabu/redrice-backend-go/routers/api"
 	v1 "github.com/punchanabu/redrice-backend-go/routers/api/v1"
@@ -38,6 +39,41 @@ func main() {
 	api.InitializedAuthHandler(db)
 	v1.InitializedReservationHandler(db)
 	v1.InitializedCommentHandler(db)
+	v1.InitializedBlackoutHandler(db)
+	v1.InitializedQueueHandler(db)
+	v1.InitializedPhotoHandler(db)
+	v1.InitializedPhoneVerificationHandler(db)
+	v1.InitializedInvitationHandler(db)
+	v1.InitializedScheduledChangeHandler(db)
+	v1.InitializedAPIKeyHandler(db)
+	v1.InitializedEventHandler(db)
+	v1.InitializedThemeHandler(db)
+	v1.InitializedPayoutHandler(db)
+	v1.InitializedAuditHandler(db)
+	v1.InitializedStatusHandler(db)
+	v1.InitializedDataExportHandler(db)
+	v1.InitializedPreferencesHandler(db)
+	v1.InitializedActivityHandler(db)
+	v1.InitializedFavoriteHandler(db)
+	v1.InitializedFeedHandler(db)
+	v1.InitializedConsentHandler(db)
+	v1.InitializedEmailTemplateHandler(db)
+	v1.InitializedNotificationHandler(db)
+	v1.InitializedSLAHandler(db)
+	v1.InitializedSearchBoostHandler(db)
+	v1.InitializedExperimentHandler(db)
+	v1.InitializedAnalyticsHandler(db)
+	v1.InitializedCategoryHandler(db)
+	v1.InitializedWarehouseHandler(db)
+	v1.InitializedBackfillHandler(db)
+	v1.InitializedSpecialHoursHandler(db)
+	v1.InitializedMenuHandler(db)
+	v1.InitializedAvailabilitySnapshotHandler(db)
+	v1.InitializedRestaurantClaimHandler(db)
+	v1.InitializedDepositRuleHandler(db)
+	v1.InitializedExchangeRates()
+	v1.InitializedTrending()
+	middleware.InitializedAuthMiddleware(db)
 
 	// Initialize router
 	// @securityDefinitions.apikey BearerAuth
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"log"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/models"
+)
+
+var activityHandler *models.ActivityHandler
+
+// RecordActivity adds an event to the history of the user with the device of
+// the request. The signed in user, when it is someone else, is recorded as
+// actor. Failures are logged, they never fail the request.
+func RecordActivity(c *gin.Context, userID uint, activityType string, details map[string]interface{}) {
+	if activityHandler == nil {
+		return
+	}
+
+	activity := models.Activity{
+		UserID:    userID,
+		Type:      activityType,
+		Details:   details,
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+	if value, ok := c.Get("claims"); ok {
+		if claims, ok := value.(*Claims); ok {
+			if claims.UserId != userID {
+				actorID := claims.UserId
+				activity.ActorID = &actorID
+			}
+			if claims.ImpersonatorID != 0 {
+				impersonator := claims.ImpersonatorID
+				activity.ImpersonatedBy = &impersonator
+			}
+		}
+	}
+
+	if err := activityHandler.Record(&activity); err != nil {
+		log.Printf("Failed to record %s activity of user %d: %v", activityType, userID, err)
+	}
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"log"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/models"
+)
+
+var auditHandler *models.AuditHandler
+
+// recordImpersonatedAction adds the finished request to the audit trail when
+// it was made by an admin acting as another user.
+func recordImpersonatedAction(c *gin.Context, claims *Claims) {
+	if claims.ImpersonatorID == 0 || auditHandler == nil {
+		return
+	}
+
+	entry := models.AuditEntry{
+		ActorID:      claims.ImpersonatorID,
+		UserID:       claims.UserId,
+		Impersonated: true,
+		Action:       c.Request.Method + " " + c.FullPath(),
+		Path:         c.Request.URL.Path,
+		Status:       c.Writer.Status(),
+		IP:           c.ClientIP(),
+	}
+	if err := auditHandler.Record(&entry); err != nil {
+		log.Printf("Failed to record impersonated action %s by admin %d: %v", entry.Action, entry.ActorID, err)
+	}
+}
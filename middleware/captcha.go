@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/utils"
+)
+
+// Captcha requires a valid CAPTCHA token in the X-Captcha-Token header. It
+// lets every request through when no CAPTCHA provider is configured.
+func Captcha() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !utils.CaptchaEnabled() {
+			c.Next()
+			return
+		}
+
+		if err := utils.VerifyCaptcha(c.GetHeader("X-Captcha-Token"), c.ClientIP()); err != nil {
+			log.Println("Captcha rejected:", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Captcha verification failed, please try again", "code": "CAPTCHA_FAILED"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
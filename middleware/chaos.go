@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/config"
+)
+
+// ChaosHeader lets a client pick the fault of a request: "off" skips fault
+// injection, "latency", "error" and "drop" always inject that fault.
+const ChaosHeader = "X-Chaos"
+
+const ErrorCodeChaos = "CHAOS_INJECTED"
+
+// chaosExempt are paths never failed, so monitoring and the docs stay usable.
+var chaosExempt = []string{"/api/v1/status", "/swagger/", "/openapi.json", "/.well-known/"}
+
+// FaultInjection randomly delays requests, answers them with a 5xx error or
+// drops the connection halfway through a file upload, so clients can test
+// their error handling against realistic failures. It is meant for staging
+// only, see config.Chaos.
+func FaultInjection(chaos config.ChaosConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		forced := strings.ToLower(c.GetHeader(ChaosHeader))
+		if forced == "off" {
+			c.Next()
+			return
+		}
+		for _, prefix := range chaosExempt {
+			if strings.HasPrefix(c.Request.URL.Path, prefix) {
+				c.Next()
+				return
+			}
+		}
+
+		if forced == "latency" || (forced == "" && rand.Float64() < chaos.LatencyRate) {
+			time.Sleep(time.Duration(rand.Int63n(int64(chaos.MaxLatency))))
+		}
+
+		isUpload := strings.HasPrefix(c.ContentType(), "multipart/")
+		if forced == "drop" || (forced == "" && isUpload && rand.Float64() < chaos.UploadDropRate) {
+			dropConnection(c)
+			return
+		}
+
+		if forced == "error" || (forced == "" && rand.Float64() < chaos.ErrorRate) {
+			statuses := []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable}
+			status := statuses[rand.Intn(len(statuses))]
+			if status == http.StatusServiceUnavailable {
+				c.Header("Retry-After", "5")
+			}
+			c.AbortWithStatusJSON(status, gin.H{"error": "Injected fault", "code": ErrorCodeChaos})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// dropConnection reads half of the request body and closes the connection
+// without answering, like a network failure during an upload.
+func dropConnection(c *gin.Context) {
+	if c.Request.ContentLength > 0 {
+		io.CopyN(io.Discard, c.Request.Body, c.Request.ContentLength/2)
+	}
+
+	conn, _, err := c.Writer.Hijack()
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "Injected fault", "code": ErrorCodeChaos})
+		return
+	}
+	conn.Close()
+	c.Abort()
+}
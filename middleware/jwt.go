@@ -1,45 +1,92 @@
 package middleware
 
 import (
+	"errors"
 	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
 )
 
+// jwtKey is the static secret used before signing keys were rotated. It signs
+// tokens while no key is loaded and verifies tokens without a key id until
+// the sunset set in JWT_SECRET_SUNSET, see legacyTokenSunset.
 var jwtKey = []byte(os.Getenv("JWT_SECRET"))
 
+// tokenTTL is how long a session token stays valid.
+const tokenTTL = 24 * time.Hour
+
+// twoFactorChallengeTTL is how long a user has to enter their second factor after the password.
+const twoFactorChallengeTTL = 5 * time.Minute
+
 type Claims struct {
 	UserId uint `json:"id"`
 	Email string `json:"email"`
 	Role string `json:"role"`
+	// MFA is set when the second factor has been verified for this token
+	MFA bool `json:"mfa,omitempty"`
+	// TwoFactorPending tokens are only good for completing the second step of the login
+	TwoFactorPending bool `json:"twoFactorPending,omitempty"`
+	// ImpersonatorID is the admin acting as the user, every request is audited
+	ImpersonatorID uint `json:"impersonator,omitempty"`
 	jwt.StandardClaims
 }
 
 // Generate Token for a given email ✨
 func GenerateToken(email string, userId uint, role string) (string, error) {
+	return signToken(&Claims{Email: email, UserId: userId, Role: role}, tokenTTL)
+}
 
-	exprTime := time.Now().Add(24 * time.Hour)
-	claims := &Claims{
-		Email: email,
-		UserId: userId,
-		Role: role,
-		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: exprTime.Unix(),
-		},
+// GenerateTwoFactorChallengeToken issues a short lived token proving the
+// password step succeeded. It is rejected by the auth middleware.
+func GenerateTwoFactorChallengeToken(email string, userId uint, role string) (string, error) {
+	return signToken(&Claims{Email: email, UserId: userId, Role: role, TwoFactorPending: true}, twoFactorChallengeTTL)
+}
+
+func signToken(claims *Claims, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims.StandardClaims = jwt.StandardClaims{
+		// Unique token id so a single token can be revoked on logout
+		Id:        uuid.New().String(),
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(ttl).Unix(),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(jwtKey)
+	key := keys.active()
+	if key == nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(jwtKey)
+	}
 
-	return tokenString, err
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.id
+	return token.SignedString(key.signKey)
 }
 
 func ValidateToken(tokenString string) (*Claims, error) {
 	claims := &Claims{}
 
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return jwtKey, nil
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" {
+			if token.Method != jwt.SigningMethodHS256 {
+				return nil, errors.New("unexpected signing method")
+			}
+			// Once signing keys are in use, JWT_SECRET only verifies tokens until the sunset
+			if keys.active() != nil && !time.Now().Before(legacyTokenSunset()) {
+				return nil, errors.New("tokens without a key id are no longer accepted")
+			}
+			return jwtKey, nil
+		}
+
+		key := keys.lookup(kid)
+		if key == nil {
+			return nil, errors.New("unknown signing key")
+		}
+		if token.Method.Alg() != key.method.Alg() {
+			return nil, errors.New("unexpected signing method")
+		}
+		return key.verifyKey, nil
 	})
 
 	if err != nil {
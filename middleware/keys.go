@@ -0,0 +1,229 @@
+package middleware
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"log"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"github.com/punchanabu/redrice-backend-go/utils"
+	"gorm.io/gorm"
+)
+
+// keyPublishLead is how long a new key is listed in the JWKS before it signs
+// tokens, services verifying our tokens should refresh their copy more often.
+const keyPublishLead = time.Hour
+
+// keyReloadInterval is how often every replica reloads the signing keys.
+const keyReloadInterval = time.Minute
+
+type signingKey struct {
+	id          string
+	method      jwt.SigningMethod
+	signKey     interface{}
+	verifyKey   interface{}
+	activatesAt time.Time
+}
+
+// keyRing holds the signing keys shared through the database, newest first.
+type keyRing struct {
+	mu       sync.RWMutex
+	keys     []signingKey
+	loadedAt time.Time
+}
+
+var signingKeyHandler *models.SigningKeyHandler
+var keys keyRing
+
+// signingAlgorithm returns JWT_ALGORITHM, HS256 unless set to RS256.
+func signingAlgorithm() string {
+	if strings.ToUpper(os.Getenv("JWT_ALGORITHM")) == models.SigningAlgorithmRS256 {
+		return models.SigningAlgorithmRS256
+	}
+	return models.SigningAlgorithmHS256
+}
+
+// keyRotationInterval returns JWT_KEY_ROTATION_DAYS, 30 days by default. Zero
+// turns rotation off.
+func keyRotationInterval() time.Duration {
+	days, err := strconv.Atoi(os.Getenv("JWT_KEY_ROTATION_DAYS"))
+	if err != nil || days < 0 {
+		days = 30
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// legacyTokenSunset returns JWT_SECRET_SUNSET, the day in YYYY-MM-DD format
+// from which tokens signed with JWT_SECRET, without a key id, are rejected
+// while signing keys are loaded. Unset or invalid, they are rejected at once.
+func legacyTokenSunset() time.Time {
+	sunset, err := time.Parse("2006-01-02", os.Getenv("JWT_SECRET_SUNSET"))
+	if err != nil {
+		return time.Time{}
+	}
+	return sunset
+}
+
+// initSigningKeys makes sure a signing key exists, loads the keys and keeps
+// them rotated and reloaded in the background.
+func initSigningKeys(db *gorm.DB) {
+	signingKeyHandler = models.NewSigningKeyHandler(db)
+
+	rotate := func() error {
+		return signingKeyHandler.Rotate(signingAlgorithm(), keyRotationInterval(), keyPublishLead, tokenTTL, time.Now())
+	}
+
+	// Another replica may be creating the first key, wait for it to show up
+	for attempt := 0; attempt < 5; attempt++ {
+		if err := utils.WithJobLock(db, "jwt-key-rotation", rotate); err != nil {
+			log.Printf("Failed to rotate signing keys: %v", err)
+		}
+		if err := keys.reload(); err != nil {
+			log.Printf("Failed to load signing keys: %v", err)
+		}
+		if keys.active() != nil {
+			break
+		}
+		time.Sleep(time.Second)
+	}
+
+	utils.RunEveryExclusive(db, time.Hour, "jwt-key-rotation", rotate)
+	utils.RunEvery(keyReloadInterval, "jwt-key-reload", keys.reload)
+}
+
+func (r *keyRing) reload() error {
+	stored, err := signingKeyHandler.GetUsableKeys(time.Now())
+	if err != nil {
+		return err
+	}
+
+	loaded := make([]signingKey, 0, len(stored))
+	for _, key := range stored {
+		parsed, err := parseSigningKey(key)
+		if err != nil {
+			log.Printf("Skipping signing key %s: %v", key.KeyID, err)
+			continue
+		}
+		loaded = append(loaded, parsed)
+	}
+
+	r.mu.Lock()
+	r.keys = loaded
+	r.loadedAt = time.Now()
+	r.mu.Unlock()
+	return nil
+}
+
+func parseSigningKey(key models.SigningKey) (signingKey, error) {
+	parsed := signingKey{id: key.KeyID, activatesAt: key.ActivatesAt}
+
+	switch key.Algorithm {
+	case models.SigningAlgorithmHS256:
+		parsed.method = jwt.SigningMethodHS256
+		parsed.signKey = []byte(key.Material)
+		parsed.verifyKey = []byte(key.Material)
+	case models.SigningAlgorithmRS256:
+		private, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(key.Material))
+		if err != nil {
+			return parsed, err
+		}
+		parsed.method = jwt.SigningMethodRS256
+		parsed.signKey = private
+		parsed.verifyKey = &private.PublicKey
+	default:
+		return parsed, errors.New("unsupported algorithm " + key.Algorithm)
+	}
+
+	return parsed, nil
+}
+
+// active returns the newest key that may sign tokens, nil when none is loaded.
+func (r *keyRing) active() *signingKey {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := time.Now()
+	for i := range r.keys {
+		if !r.keys[i].activatesAt.After(now) {
+			return &r.keys[i]
+		}
+	}
+	return nil
+}
+
+// lookup finds the key by id. Keys created by another replica since the last
+// reload are picked up by reloading, at most every few seconds.
+func (r *keyRing) lookup(id string) *signingKey {
+	find := func() *signingKey {
+		r.mu.RLock()
+		defer r.mu.RUnlock()
+		for i := range r.keys {
+			if r.keys[i].id == id {
+				return &r.keys[i]
+			}
+		}
+		return nil
+	}
+
+	if key := find(); key != nil {
+		return key
+	}
+
+	r.mu.RLock()
+	stale := time.Since(r.loadedAt) > 5*time.Second
+	r.mu.RUnlock()
+	if signingKeyHandler == nil || !stale {
+		return nil
+	}
+	if err := r.reload(); err != nil {
+		log.Printf("Failed to load signing keys: %v", err)
+		return nil
+	}
+	return find()
+}
+
+type JWK struct {
+	KeyType   string `json:"kty" example:"RSA"`
+	Use       string `json:"use" example:"sig"`
+	Algorithm string `json:"alg" example:"RS256"`
+	KeyID     string `json:"kid" example:"6f1c2a4e-8d3b-4b8e-9a7f-2c1d5e6f7a8b"`
+	Modulus   string `json:"n"`
+	Exponent  string `json:"e" example:"AQAB"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// PublicKeys returns the RS256 keys that currently sign or verify tokens,
+// including keys published ahead of their activation. HMAC keys are secret
+// and never listed.
+func PublicKeys() JWKSet {
+	keys.mu.RLock()
+	defer keys.mu.RUnlock()
+
+	set := JWKSet{Keys: []JWK{}}
+	for _, key := range keys.keys {
+		public, ok := key.verifyKey.(*rsa.PublicKey)
+		if !ok {
+			continue
+		}
+		set.Keys = append(set.Keys, JWK{
+			KeyType:   "RSA",
+			Use:       "sig",
+			Algorithm: key.method.Alg(),
+			KeyID:     key.id,
+			Modulus:   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+			Exponent:  base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+		})
+	}
+	return set
+}
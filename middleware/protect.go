@@ -1,79 +1,268 @@
 package middleware
 
 import (
+	"log"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"gorm.io/gorm"
+	"strconv"
 	"strings"
+	"time"
 )
 
-func Auth() gin.HandlerFunc {
-	return func(c *gin.Context) {
+var revokedTokenHandler *models.RevokedTokenHandler
+var userHandler *models.UserHandler
+var sessionHandler *models.SessionHandler
+var apiKeyHandler *models.APIKeyHandler
+
+func InitializedAuthMiddleware(db *gorm.DB) {
+	revokedTokenHandler = models.NewRevokedTokenHandler(db)
+	userHandler = models.NewUserHandler(db)
+	sessionHandler = models.NewSessionHandler(db)
+	apiKeyHandler = models.NewAPIKeyHandler(db)
+	auditHandler = models.NewAuditHandler(db)
+	activityHandler = models.NewActivityHandler(db)
+	initSigningKeys(db)
+}
+
+// ErrorCodeAdminRequired marks rejections of authenticated non-admin users so
+// they can be told apart from missing or invalid tokens.
+const ErrorCodeAdminRequired = "ADMIN_REQUIRED"
+
+// ErrorCodePermissionDenied marks rejections of authenticated users whose role
+// lacks the permission required by the route.
+const ErrorCodePermissionDenied = "PERMISSION_DENIED"
 
-		authHeader := c.GetHeader("Authorization")
+// denyPermission aborts the request of a user missing the permission.
+func denyPermission(c *gin.Context, permission string) {
+	if permission == models.PermissionAdminister {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized You are not an admin! 🥹 whahahahaha", "code": ErrorCodeAdminRequired})
+	} else {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to do this", "code": ErrorCodePermissionDenied})
+	}
+	c.Abort()
+}
+
+// authenticate validates the bearer token of the request and aborts with 401
+// if it is missing, malformed, expired or revoked. A non-empty permission is
+// checked against the role in the token right after it is parsed, before any
+// database lookup, and again against the current role of the user.
+func authenticate(c *gin.Context, permission string) (*Claims, bool) {
+	authHeader := c.GetHeader("Authorization")
+
+	if authHeader == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is missing"})
+		c.Abort()
+		return nil, false
+	}
 
-		if authHeader == "" {
-		    c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is missing"})
-		    c.Abort()
-		    return
+	parts := strings.SplitN(authHeader, " ", 2)
+	if !(len(parts) == 2 && parts[0] == "Bearer") {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header format must be Bearer <token>"})
+		c.Abort()
+		return nil, false
+	}
+
+	tokenString := parts[1]
+
+	claims, err := ValidateToken(tokenString)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized 🥹 Please login first!"})
+		c.Abort()
+		return nil, false
+	}
+
+	if claims.TwoFactorPending {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Two-factor authentication required", "code": "TWO_FACTOR_REQUIRED"})
+		c.Abort()
+		return nil, false
+	}
+
+	if permission != "" && !models.HasPermission(claims.Role, permission) {
+		denyPermission(c, permission)
+		return nil, false
+	}
+
+	if revokedTokenHandler != nil && claims.Id != "" {
+		revoked, err := revokedTokenHandler.IsRevoked(claims.Id)
+		if err != nil {
+			log.Println("Error checking token revocation:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking token"})
+			c.Abort()
+			return nil, false
 		}
-		
-		parts := strings.SplitN(authHeader, " ", 2)
-		if !(len(parts) == 2 && parts[0] == "Bearer") {
-		    c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header format must be Bearer <token>"})
-		    c.Abort()
-		    return
+		if revoked {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token has been revoked, please login again"})
+			c.Abort()
+			return nil, false
 		}
+	}
 
-		tokenString := parts[1]
-
-		claims, err := ValidateToken(tokenString)
+	if userHandler != nil {
+		user, err := userHandler.GetUser(claims.UserId)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized 🥹 Please login first!"})
 			c.Abort()
+			return nil, false
+		}
+
+		// Changing the password signs out every session issued before the change
+		if user.TokensValidAfter != nil && claims.IssuedAt < user.TokensValidAfter.Unix() {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Session has expired, please login again"})
+			c.Abort()
+			return nil, false
+		}
+
+		if RejectSuspended(c, user) {
+			return nil, false
+		}
+
+		// Tokens issued before two-factor was enabled do not carry the MFA flag
+		if user.TwoFactorEnabled && !claims.MFA {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Two-factor authentication required", "code": "TWO_FACTOR_REQUIRED"})
+			c.Abort()
+			return nil, false
+		}
+
+		// Role changes apply right away instead of at the next login
+		claims.Role = user.Role
+		if permission != "" && !models.HasPermission(claims.Role, permission) {
+			denyPermission(c, permission)
+			return nil, false
+		}
+	}
+
+	return claims, true
+}
+
+// RevokeToken blacklists the token described by claims until it expires.
+func RevokeToken(claims *Claims) error {
+	return revokedTokenHandler.RevokeToken(claims.Id, claims.UserId, time.Unix(claims.ExpiresAt, 0))
+}
+
+func Auth() gin.HandlerFunc {
+	return RequirePermission("")
+}
+
+func Admin() gin.HandlerFunc {
+	return RequirePermission(models.PermissionAdminister)
+}
+
+// RequirePermission authenticates the request like Auth and only lets users
+// through whose role grants the permission. Route groups declare their
+// requirement with it instead of checking roles in the handlers.
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := authenticate(c, permission)
+		if !ok {
 			return
 		}
 
 		// Set user id to next handler for easy access
 		c.Set("id", claims.UserId)
+		c.Set("claims", claims)
 		c.Next()
+		recordImpersonatedAction(c, claims)
 	}
 }
 
-func Admin() gin.HandlerFunc {
+// Permit lets requests already authenticated by Auth through when the role of
+// the user grants the permission, for single routes of the user group.
+func Permit(permission string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-
-		if authHeader == "" {
-		    c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is missing"})
-		    c.Abort()
-		    return
-		}
-		
-		parts := strings.SplitN(authHeader, " ", 2)
-		if !(len(parts) == 2 && parts[0] == "Bearer") {
-		    c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header format must be Bearer <token>"})
-		    c.Abort()
-		    return
+		claims, ok := c.MustGet("claims").(*Claims)
+		if !ok || !models.HasPermission(claims.Role, permission) {
+			denyPermission(c, permission)
+			return
 		}
+		c.Next()
+	}
+}
 
-		tokenString := parts[1]
+// AuthOrAPIKey accepts either a user token like Auth or a partner API key in
+// the X-API-Key header. API keys must have been granted the scope. Requests
+// authenticated with a key have "apiKey" set instead of "id".
+func AuthOrAPIKey(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		raw := c.GetHeader("X-API-Key")
+		if raw == "" {
+			claims, ok := authenticate(c, "")
+			if !ok {
+				return
+			}
 
-		claims, err := ValidateToken(tokenString)
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized 🥹 Please login first!"})
-			c.Abort()
+			c.Set("id", claims.UserId)
+			c.Set("claims", claims)
+			c.Next()
+			recordImpersonatedAction(c, claims)
 			return
 		}
 
-		if claims.Role != "admin" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized You are not an admin! 🥹 whahahahaha"})
-			c.Abort()
+		key, ok := authenticateAPIKey(c, raw, scope)
+		if !ok {
 			return
 		}
 
-		// Set user id to next handler for easy access
-		c.Set("id", claims.UserId)
+		c.Set("apiKey", key)
 		c.Next()
 	}
-}
\ No newline at end of file
+}
+
+// ErrorCodeAccountSuspended marks requests of users an admin suspended.
+const ErrorCodeAccountSuspended = "ACCOUNT_SUSPENDED"
+
+// RejectSuspended aborts the request with the reason of the suspension when
+// the user is suspended.
+func RejectSuspended(c *gin.Context, user *models.User) bool {
+	if !user.IsSuspended(time.Now()) {
+		return false
+	}
+	response := gin.H{"error": "Your account has been suspended", "code": ErrorCodeAccountSuspended, "reason": user.SuspensionReason}
+	if user.SuspendedUntil != nil {
+		response["until"] = user.SuspendedUntil
+	}
+	c.AbortWithStatusJSON(http.StatusForbidden, response)
+	return true
+}
+
+// ErrorCodeOriginNotAllowed marks API key requests sent from a website the key
+// was not issued for.
+const ErrorCodeOriginNotAllowed = "ORIGIN_NOT_ALLOWED"
+
+// authenticateAPIKey checks the raw key, its scope and the origin and
+// restaurant it is restricted to. The error response is written when the key
+// is rejected.
+func authenticateAPIKey(c *gin.Context, raw string, scope string) (*models.APIKey, bool) {
+	if apiKeyHandler == nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+		return nil, false
+	}
+
+	key, err := apiKeyHandler.Authenticate(raw)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+		return nil, false
+	}
+
+	if !key.HasScope(scope) {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "API key is missing the " + scope + " scope"})
+		return nil, false
+	}
+
+	if !key.AllowsOrigin(c.GetHeader("Origin")) {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "API key cannot be used from this website", "code": ErrorCodeOriginNotAllowed})
+		return nil, false
+	}
+
+	if id := c.Param("id"); id != "" {
+		restaurantID, err := strconv.Atoi(id)
+		if err != nil || !key.AllowsRestaurant(uint(restaurantID)) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "API key cannot be used for this restaurant"})
+			return nil, false
+		}
+	}
+
+	return key, true
+}
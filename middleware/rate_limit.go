@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/config"
+)
+
+const ErrorCodeRateLimited = "RATE_LIMITED"
+
+// Quota is what is left of the request limit of a client in the current window.
+type Quota struct {
+	Limit     int       `json:"limit" example:"300"`
+	Remaining int       `json:"remaining" example:"297"`
+	Reset     time.Time `json:"reset"`
+}
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// rateLimiter counts requests per client in fixed windows. Counters are kept
+// in memory, so every replica enforces the limit on its own.
+type rateLimiter struct {
+	config    config.RateLimitConfig
+	mu        sync.Mutex
+	windows   map[string]*rateWindow
+	lastSweep time.Time
+}
+
+// take counts a request of the client and reports whether it is within the limit.
+func (l *rateLimiter) take(client string, limit int, now time.Time) (Quota, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Forget the clients that were not seen during the last window
+	if now.Sub(l.lastSweep) > l.config.Window {
+		for key, window := range l.windows {
+			if now.Sub(window.start) >= l.config.Window {
+				delete(l.windows, key)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	window, ok := l.windows[client]
+	if !ok || now.Sub(window.start) >= l.config.Window {
+		window = &rateWindow{start: now}
+		l.windows[client] = window
+	}
+
+	allowed := window.count < limit
+	if allowed {
+		window.count++
+	}
+	return Quota{Limit: limit, Remaining: limit - window.count, Reset: window.start.Add(l.config.Window)}, allowed
+}
+
+// identifyClient returns the key requests are counted under and its limit.
+// Only the signature of tokens is checked here, the routes still authenticate.
+func (l *rateLimiter) identifyClient(c *gin.Context) (string, int) {
+	if raw := c.GetHeader("X-API-Key"); raw != "" && apiKeyHandler != nil {
+		if key, err := apiKeyHandler.LookupKey(raw); err == nil {
+			return fmt.Sprintf("api-key:%d", key.ID), l.config.APIKeyLimit
+		}
+	}
+
+	parts := strings.Split(c.GetHeader("Authorization"), " ")
+	if len(parts) == 2 && parts[0] == "Bearer" {
+		if claims, err := ValidateToken(parts[1]); err == nil && !claims.TwoFactorPending {
+			return fmt.Sprintf("user:%d", claims.UserId), l.config.UserLimit
+		}
+	}
+
+	return "ip:" + c.ClientIP(), l.config.AnonymousLimit
+}
+
+// RateLimit rejects clients making more requests than allowed by the config
+// with 429. Every response carries X-RateLimit-Limit, X-RateLimit-Remaining
+// and X-RateLimit-Reset, the Unix time the window ends, so clients can slow
+// down before hitting the limit. The quota is also available to handlers, see
+// RequestQuota.
+func RateLimit(limits config.RateLimitConfig) gin.HandlerFunc {
+	limiter := &rateLimiter{config: limits, windows: map[string]*rateWindow{}}
+
+	return func(c *gin.Context) {
+		client, limit := limiter.identifyClient(c)
+		if limit == 0 {
+			c.Next()
+			return
+		}
+
+		quota, allowed := limiter.take(client, limit, time.Now())
+		c.Set("rateLimit", quota)
+		c.Header("X-RateLimit-Limit", strconv.Itoa(quota.Limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(quota.Remaining))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(quota.Reset.Unix(), 10))
+
+		if !allowed {
+			retryAfter := int(time.Until(quota.Reset).Seconds()) + 1
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "Too many requests, please slow down", "code": ErrorCodeRateLimited})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequestQuota returns the request quota left to the client of the request,
+// or false when its requests are not limited.
+func RequestQuota(c *gin.Context) (Quota, bool) {
+	value, ok := c.Get("rateLimit")
+	if !ok {
+		return Quota{}, false
+	}
+	quota, ok := value.(Quota)
+	return quota, ok
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/models"
+)
+
+// IssueToken signs a session token for the user and records the session with
+// the user agent and IP address of the request. mfa marks tokens issued after
+// the second factor was verified. Logging in keeps an account that was
+// scheduled for deletion.
+func IssueToken(c *gin.Context, email string, userId uint, role string, mfa bool) (string, error) {
+	if userHandler != nil {
+		if err := userHandler.CancelDeletion(userId); err != nil {
+			return "", err
+		}
+	}
+	return issueSession(c, &Claims{Email: email, UserId: userId, Role: role, MFA: mfa}, tokenTTL)
+}
+
+// IssueImpersonationToken signs a token acting as the user for the admin. It
+// counts as having passed two-factor authentication, expires after ttl and
+// every request made with it is recorded in the audit trail.
+func IssueImpersonationToken(c *gin.Context, adminId uint, user *models.User, ttl time.Duration) (string, time.Time, error) {
+	claims := &Claims{Email: user.Email, UserId: user.ID, Role: user.Role, MFA: true, ImpersonatorID: adminId}
+	token, err := issueSession(c, claims, ttl)
+	return token, time.Unix(claims.ExpiresAt, 0), err
+}
+
+// ReissueToken signs a new session token for the signed-in user, e.g. after
+// enabling two-factor authentication. A new token for an impersonation keeps
+// the admin and expires with the current token, so it is audited and never
+// outlives the impersonation.
+func ReissueToken(c *gin.Context, user *models.User, mfa bool) (string, error) {
+	current, _ := c.Get("claims")
+	if claims, ok := current.(*Claims); ok && claims.ImpersonatorID != 0 {
+		impersonation := &Claims{Email: user.Email, UserId: user.ID, Role: user.Role, MFA: true, ImpersonatorID: claims.ImpersonatorID}
+		return issueSession(c, impersonation, time.Until(time.Unix(claims.ExpiresAt, 0)))
+	}
+	return IssueToken(c, user.Email, user.ID, user.Role, mfa)
+}
+
+func issueSession(c *gin.Context, claims *Claims, ttl time.Duration) (string, error) {
+	token, err := signToken(claims, ttl)
+	if err != nil {
+		return "", err
+	}
+
+	if sessionHandler != nil {
+		session := models.Session{
+			TokenID:   claims.Id,
+			UserID:    claims.UserId,
+			UserAgent: c.Request.UserAgent(),
+			IP:        c.ClientIP(),
+			IssuedAt:  time.Unix(claims.IssuedAt, 0),
+			ExpiresAt: time.Unix(claims.ExpiresAt, 0),
+		}
+		if claims.ImpersonatorID != 0 {
+			session.ImpersonatedBy = &claims.ImpersonatorID
+		}
+		if err := sessionHandler.CreateSession(&session); err != nil {
+			return "", err
+		}
+	}
+
+	return token, nil
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/models"
+)
+
+// WidgetAPIKey authenticates the "Book now" widget embedded on the website of
+// a restaurant. It needs an API key with the widget:book scope in the
+// X-API-Key header, sent from one of the origins allowed for the key and for
+// the restaurant of the key. The response allows the calling origin only.
+func WidgetAPIKey() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		raw := c.GetHeader("X-API-Key")
+		if raw == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Missing API key"})
+			return
+		}
+
+		key, ok := authenticateAPIKey(c, raw, models.ScopeWidget)
+		if !ok {
+			return
+		}
+
+		if origin := c.GetHeader("Origin"); origin != "" {
+			c.Header("Access-Control-Allow-Origin", origin)
+			c.Header("Vary", "Origin")
+		}
+
+		c.Set("apiKey", key)
+		c.Next()
+	}
+}
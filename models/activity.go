@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ActivityLogin                = "login"
+	ActivityProfileUpdated       = "profile.updated"
+	ActivityPasswordChanged      = "password.changed"
+	ActivityEmailChanged         = "email.changed"
+	ActivityReservationCreated   = "reservation.created"
+	ActivityReservationCancelled = "reservation.cancelled"
+	ActivityDeletionScheduled    = "account.deletion_scheduled"
+)
+
+// Activity is an event in the history of an account, kept to help support
+// answer questions such as "who cancelled my booking".
+type Activity struct {
+	ID     uint   `gorm:"primaryKey" json:"id"`
+	UserID uint   `gorm:"index:idx_activities_user_created" json:"userId" example:"42"`
+	Type   string `json:"type" example:"reservation.cancelled" enums:"login,profile.updated,password.changed,email.changed,reservation.created,reservation.cancelled,account.deletion_scheduled"`
+	// ActorID is who acted when it is not the user, e.g. an admin or the restaurant
+	ActorID *uint `json:"actorId,omitempty" example:"1"`
+	// ImpersonatedBy is set when an admin acted as the user
+	ImpersonatedBy *uint                  `json:"impersonatedBy,omitempty"`
+	Details        map[string]interface{} `gorm:"serializer:json" json:"details,omitempty" swaggertype:"object"`
+	IP             string                 `json:"ip" example:"203.0.113.7"`
+	UserAgent      string                 `json:"userAgent"`
+	CreatedAt      time.Time              `gorm:"index:idx_activities_user_created" json:"createdAt"`
+}
+
+type ActivityHandler struct {
+	db *gorm.DB
+}
+
+func NewActivityHandler(db *gorm.DB) *ActivityHandler {
+	return &ActivityHandler{db}
+}
+
+func (h *ActivityHandler) Record(activity *Activity) error {
+	return h.db.Create(activity).Error
+}
+
+// GetActivities returns a page of the history of the user, newest first, and
+// the total number of activities.
+func (h *ActivityHandler) GetActivities(userID uint, page, limit int) ([]Activity, int64, error) {
+	query := h.db.Model(&Activity{}).Where("user_id = ?", userID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var activities []Activity
+	result := query.Order("created_at DESC, id DESC").Offset((page - 1) * limit).Limit(limit).Find(&activities)
+	return activities, total, result.Error
+}
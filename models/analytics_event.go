@@ -0,0 +1,213 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/punchanabu/redrice-backend-go/utils"
+	"gorm.io/gorm"
+)
+
+// MaxAnalyticsBatch is the most events a client sends in one batch.
+const MaxAnalyticsBatch = 100
+
+// maxAnalyticsStringLength bounds the string properties of an event.
+const maxAnalyticsStringLength = 500
+
+// analyticsClockSkew is how far in the future of the server an event may be
+// dated, for client clocks running ahead.
+const analyticsClockSkew = time.Hour
+
+// maxAnalyticsID is the largest ID property of an event, the largest whole
+// number JSON numbers decoded as float64 hold exactly.
+const maxAnalyticsID = 1<<53 - 1
+
+// Types of the properties of an analytics event, as decoded from JSON.
+const (
+	AnalyticsString = "string"
+	AnalyticsNumber = "number"
+	AnalyticsBool   = "bool"
+	// AnalyticsID properties are the ID of a record, a whole number from 1 to maxAnalyticsID
+	AnalyticsID = "positive integer"
+)
+
+// AnalyticsProperty describes a property of an analytics event.
+type AnalyticsProperty struct {
+	Type     string
+	Required bool
+}
+
+// AnalyticsEventSchemas lists the analytics events clients may send with the
+// properties each accepts. Events or properties not listed are rejected, so
+// the data stays consistent across app versions.
+var AnalyticsEventSchemas = map[string]map[string]AnalyticsProperty{
+	"screen_view": {
+		"screen": {Type: AnalyticsString, Required: true},
+	},
+	"restaurant_view": {
+		"restaurantId": {Type: AnalyticsID, Required: true},
+		"source":       {Type: AnalyticsString},
+	},
+	"search": {
+		"query":   {Type: AnalyticsString, Required: true},
+		"results": {Type: AnalyticsNumber},
+	},
+	"reservation_started": {
+		"restaurantId": {Type: AnalyticsID, Required: true},
+		"partySize":    {Type: AnalyticsNumber},
+	},
+	"share": {
+		"restaurantId": {Type: AnalyticsID, Required: true},
+		"channel":      {Type: AnalyticsString},
+	},
+}
+
+var ErrInvalidAnalyticsEvent = fmt.Errorf("invalid analytics event")
+
+// AnalyticsEvent is a product analytics event sent by a client app.
+type AnalyticsEvent struct {
+	ID         uint                   `gorm:"primaryKey" json:"-" swaggerignore:"true"`
+	UserID     uint                   `gorm:"index" json:"-" swaggerignore:"true"`
+	Name       string                 `gorm:"index:idx_analytics_events_name_occurred" json:"name" binding:"required" example:"restaurant_view" enums:"screen_view,restaurant_view,search,reservation_started,share"`
+	Properties map[string]interface{} `gorm:"serializer:json" json:"properties" swaggertype:"object"`
+	// OccurredAt is when the event happened on the device
+	OccurredAt time.Time `gorm:"index:idx_analytics_events_name_occurred" json:"occurredAt" binding:"required"`
+	ReceivedAt time.Time `json:"-" swaggerignore:"true"`
+}
+
+// Validate checks the event against its schema.
+func (e *AnalyticsEvent) Validate(now time.Time) error {
+	schema, ok := AnalyticsEventSchemas[e.Name]
+	if !ok {
+		return fmt.Errorf("%w: unknown event %q", ErrInvalidAnalyticsEvent, e.Name)
+	}
+	if e.OccurredAt.After(now.Add(analyticsClockSkew)) {
+		return fmt.Errorf("%w: %s occurred in the future", ErrInvalidAnalyticsEvent, e.Name)
+	}
+
+	for name, value := range e.Properties {
+		property, ok := schema[name]
+		if !ok {
+			return fmt.Errorf("%w: unknown property %q of %s", ErrInvalidAnalyticsEvent, name, e.Name)
+		}
+		valid := false
+		switch value := value.(type) {
+		case string:
+			valid = property.Type == AnalyticsString && len(value) <= maxAnalyticsStringLength
+		case float64:
+			valid = property.Type == AnalyticsNumber ||
+				property.Type == AnalyticsID && value >= 1 && value <= maxAnalyticsID && value == math.Trunc(value)
+		case bool:
+			valid = property.Type == AnalyticsBool
+		}
+		if !valid {
+			return fmt.Errorf("%w: property %q of %s must be a %s", ErrInvalidAnalyticsEvent, name, e.Name, property.Type)
+		}
+	}
+	for name, property := range schema {
+		if _, ok := e.Properties[name]; property.Required && !ok {
+			return fmt.Errorf("%w: %s is missing property %q", ErrInvalidAnalyticsEvent, e.Name, name)
+		}
+	}
+	return nil
+}
+
+// AnalyticsSink stores batches of analytics events.
+type AnalyticsSink interface {
+	Write(events []AnalyticsEvent) error
+}
+
+// DBAnalyticsSink stores the events in the analytics_events table.
+type DBAnalyticsSink struct {
+	db *gorm.DB
+}
+
+func NewDBAnalyticsSink(db *gorm.DB) *DBAnalyticsSink {
+	return &DBAnalyticsSink{db}
+}
+
+func (s *DBAnalyticsSink) Write(events []AnalyticsEvent) error {
+	return s.db.Create(&events).Error
+}
+
+// S3AnalyticsSink stores every batch as a newline-delimited JSON object
+// under analytics/<day>/ in the bucket, the layout data lake tools read.
+type S3AnalyticsSink struct {
+	bucket string
+}
+
+func NewS3AnalyticsSink(bucket string) *S3AnalyticsSink {
+	return &S3AnalyticsSink{bucket}
+}
+
+// s3AnalyticsRecord is an event as written to S3, where the user is part of the record.
+type s3AnalyticsRecord struct {
+	UserID     uint                   `json:"userId"`
+	Name       string                 `json:"name"`
+	Properties map[string]interface{} `json:"properties"`
+	OccurredAt time.Time              `json:"occurredAt"`
+	ReceivedAt time.Time              `json:"receivedAt"`
+}
+
+func (s *S3AnalyticsSink) Write(events []AnalyticsEvent) error {
+	var content bytes.Buffer
+	encoder := json.NewEncoder(&content)
+	for _, event := range events {
+		record := s3AnalyticsRecord{event.UserID, event.Name, event.Properties, event.OccurredAt, event.ReceivedAt}
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	key := fmt.Sprintf("analytics/%s/%s.ndjson", time.Now().UTC().Format("2006-01-02"), uuid.New().String())
+	return utils.UploadFileToS3(s.bucket, key, content.Bytes(), "application/x-ndjson")
+}
+
+type AnalyticsHandler struct {
+	sink    AnalyticsSink
+	batches chan []AnalyticsEvent
+	// dropped counts the events left out because the queue was full
+	dropped atomic.Int64
+}
+
+// NewAnalyticsHandler starts a background worker that writes the batches
+// queued with Enqueue to the sink, so clients never wait on the sink.
+func NewAnalyticsHandler(sink AnalyticsSink) *AnalyticsHandler {
+	h := &AnalyticsHandler{sink: sink, batches: make(chan []AnalyticsEvent, 100)}
+	go h.work()
+	return h
+}
+
+func (h *AnalyticsHandler) work() {
+	for batch := range h.batches {
+		h.write(batch)
+	}
+}
+
+func (h *AnalyticsHandler) write(batch []AnalyticsEvent) {
+	if err := h.sink.Write(batch); err != nil {
+		log.Printf("Error writing %d analytics events: %v", len(batch), err)
+	}
+}
+
+// Enqueue queues the batch for the worker. When the sink falls behind and the
+// queue is full the batch is dropped and counted, so a slow sink never piles
+// up goroutines or memory.
+func (h *AnalyticsHandler) Enqueue(batch []AnalyticsEvent) {
+	select {
+	case h.batches <- batch:
+	default:
+		dropped := h.dropped.Add(int64(len(batch)))
+		log.Printf("Analytics queue is full, dropped %d events (%d since start)", len(batch), dropped)
+	}
+}
+
+// Dropped returns the number of events dropped since the handler started.
+func (h *AnalyticsHandler) Dropped() int64 {
+	return h.dropped.Load()
+}
@@ -0,0 +1,174 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ScopeRestaurantsRead = "restaurants:read"
+	ScopeCommentsRead    = "comments:read"
+	// ScopeWidget lets the "Book now" widget embedded on the website of a
+	// restaurant list availability and book
+	ScopeWidget = "widget:book"
+)
+
+// APIKeyScopes lists every scope an API key can be granted.
+var APIKeyScopes = []string{ScopeRestaurantsRead, ScopeCommentsRead, ScopeWidget}
+
+var ErrInvalidWidgetKey = fmt.Errorf("widget keys need a restaurant and at least one allowed origin")
+
+// apiKeyPrefix makes keys easy to recognise, e.g. in secret scanners.
+const apiKeyPrefix = "rr_"
+
+// APIKey gives a partner integration programmatic access to the routes
+// allowed by its scopes. Only the SHA-256 hash of the key is stored, Prefix
+// keeps the first characters so keys can be told apart.
+type APIKey struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	KeyHash    string     `gorm:"uniqueIndex" json:"-" swaggerignore:"true"`
+	Scopes     []string   `gorm:"serializer:json" json:"scopes"`
+	CreatedBy  uint       `json:"createdBy"`
+	ExpiresAt  *time.Time `json:"expiresAt"`
+	RevokedAt  *time.Time `json:"revokedAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	// RestaurantID restricts the key to the routes of one restaurant
+	RestaurantID *uint `json:"restaurantId,omitempty"`
+	// AllowedOrigins are the websites browsers may use the key from, e.g. https://example.com
+	AllowedOrigins []string `gorm:"serializer:json" json:"allowedOrigins,omitempty"`
+}
+
+// AllowsRestaurant reports whether the key can be used for the restaurant.
+func (k *APIKey) AllowsRestaurant(restaurantID uint) bool {
+	return k.RestaurantID == nil || *k.RestaurantID == restaurantID
+}
+
+// AllowsOrigin reports whether a request sent from origin can use the key.
+// Keys without allowed origins are meant for servers and accept any origin.
+func (k *APIKey) AllowsOrigin(origin string) bool {
+	if len(k.AllowedOrigins) == 0 {
+		return true
+	}
+	for _, allowed := range k.AllowedOrigins {
+		if allowed == origin {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidOrigin reports whether origin is a bare http or https origin, with
+// neither path nor trailing slash, as sent by browsers in the Origin header.
+func IsValidOrigin(origin string) bool {
+	parsed, err := url.Parse(origin)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "https" && parsed.Scheme != "http") {
+		return false
+	}
+	return parsed.Scheme+"://"+parsed.Host == origin
+}
+
+// HasScope reports whether the key was granted the scope.
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func IsValidAPIKeyScope(scope string) bool {
+	for _, s := range APIKeyScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+type APIKeyHandler struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyHandler(db *gorm.DB) *APIKeyHandler {
+	return &APIKeyHandler{db}
+}
+
+// CreateAPIKey stores a new key and returns the raw value. It is shown to the
+// admin once and cannot be recovered afterwards.
+func (h *APIKeyHandler) CreateAPIKey(key *APIKey) (string, error) {
+	for _, scope := range key.Scopes {
+		if !IsValidAPIKeyScope(scope) {
+			return "", fmt.Errorf("unknown scope %s", scope)
+		}
+	}
+	// Widget keys are public on the website of the restaurant
+	if key.HasScope(ScopeWidget) && (key.RestaurantID == nil || len(key.AllowedOrigins) == 0) {
+		return "", ErrInvalidWidgetKey
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		return "", err
+	}
+	raw := apiKeyPrefix + token
+
+	key.Prefix = raw[:len(apiKeyPrefix)+8]
+	key.KeyHash = hashToken(raw)
+	if err := h.db.Create(key).Error; err != nil {
+		return "", err
+	}
+
+	return raw, nil
+}
+
+func (h *APIKeyHandler) GetAPIKeys() ([]APIKey, error) {
+	var keys []APIKey
+	result := h.db.Order("created_at DESC").Find(&keys)
+	return keys, result.Error
+}
+
+func (h *APIKeyHandler) RevokeAPIKey(id uint) error {
+	result := h.db.Model(&APIKey{}).Where("id = ? AND revoked_at IS NULL", id).Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("no active api key found with id %d", id)
+	}
+	return nil
+}
+
+// Authenticate returns the key matching the raw value if it is neither
+// revoked nor expired, and records when it was last used.
+func (h *APIKeyHandler) Authenticate(raw string) (*APIKey, error) {
+	key, err := h.LookupKey(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.db.Model(key).Update("last_used_at", time.Now()).Error; err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// LookupKey returns the active key matching the raw value without recording
+// its use.
+func (h *APIKeyHandler) LookupKey(raw string) (*APIKey, error) {
+	var key APIKey
+	if err := h.db.Where("key_hash = ?", hashToken(raw)).First(&key).Error; err != nil {
+		return nil, fmt.Errorf("invalid api key")
+	}
+
+	if key.RevokedAt != nil || (key.ExpiresAt != nil && time.Now().After(*key.ExpiresAt)) {
+		return nil, fmt.Errorf("api key has expired or was revoked")
+	}
+	return &key, nil
+}
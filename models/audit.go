@@ -0,0 +1,76 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	AuditActionImpersonate      = "impersonate"
+	AuditActionChangeRole       = "role.change"
+	AuditActionSuspend          = "user.suspend"
+	AuditActionUnsuspend        = "user.unsuspend"
+	AuditActionReviewClaim      = "claim.review"
+	AuditActionRestaurantStatus = "restaurant.status"
+)
+
+// AuditEntry records an action taken on the platform. ActorID is the person
+// who acted, UserID the account they acted as, which differs while an admin
+// impersonates a user.
+type AuditEntry struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	ActorID      uint      `gorm:"index" json:"actorId" example:"1"`
+	UserID       uint      `gorm:"index" json:"userId" example:"42"`
+	Impersonated bool      `gorm:"index" json:"impersonated" example:"true"`
+	Action       string    `json:"action" example:"PUT /api/v1/reservations/:id"`
+	Path         string    `json:"path" example:"/api/v1/reservations/7"`
+	Status       int       `json:"status" example:"200"`
+	IP           string    `json:"ip" example:"203.0.113.7"`
+	CreatedAt    time.Time `gorm:"index" json:"createdAt"`
+	// Details describes what changed, e.g. the previous and new role
+	Details map[string]interface{} `gorm:"serializer:json" json:"details,omitempty" swaggertype:"object"`
+}
+
+// AuditFilter narrows down the audit trail, zero values match everything.
+type AuditFilter struct {
+	ActorID      uint
+	UserID       uint
+	Impersonated bool
+}
+
+type AuditHandler struct {
+	db *gorm.DB
+}
+
+func NewAuditHandler(db *gorm.DB) *AuditHandler {
+	return &AuditHandler{db}
+}
+
+func (h *AuditHandler) Record(entry *AuditEntry) error {
+	return h.db.Create(entry).Error
+}
+
+// GetEntries returns a page of the audit trail, newest first, and the total
+// number of matching entries.
+func (h *AuditHandler) GetEntries(filter AuditFilter, page, limit int) ([]AuditEntry, int64, error) {
+	query := h.db.Model(&AuditEntry{})
+	if filter.ActorID != 0 {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.UserID != 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.Impersonated {
+		query = query.Where("impersonated = ?", true)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var entries []AuditEntry
+	result := query.Order("created_at DESC, id DESC").Offset((page - 1) * limit).Limit(limit).Find(&entries)
+	return entries, total, result.Error
+}
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	ErrCodeReservationTooSoon   = "RESERVATION_TOO_SOON"
+	ErrCodeReservationTooFar    = "RESERVATION_TOO_FAR_AHEAD"
+	ErrCodeReservationInThePast = "RESERVATION_IN_THE_PAST"
+	ErrCodeReservationBlackout  = "RESERVATION_BLACKED_OUT"
+	ErrCodePhoneNotVerified     = "PHONE_NOT_VERIFIED"
+)
+
+// SlotLength is the granularity used when listing bookable time slots.
+const SlotLength = 30 * time.Minute
+
+// BookingError is returned when a reservation time violates the booking
+// policy of a restaurant. Code is a stable identifier clients can switch on.
+type BookingError struct {
+	Code    string
+	Message string
+}
+
+func (e *BookingError) Error() string {
+	return e.Message
+}
+
+type Slot struct {
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	Available bool      `json:"available"`
+	Reason    string    `json:"reason,omitempty"`
+}
+
+// CheckBookingWindow verifies that a reservation at the given time respects the
+// minimum notice and maximum advance window configured by the owner.
+func (r *Restaurant) CheckBookingWindow(at, now time.Time) error {
+	if at.Before(now) {
+		return &BookingError{Code: ErrCodeReservationInThePast, Message: "Reservation time is in the past"}
+	}
+
+	if r.MinNoticeMinutes > 0 && at.Before(now.Add(time.Duration(r.MinNoticeMinutes)*time.Minute)) {
+		return &BookingError{
+			Code:    ErrCodeReservationTooSoon,
+			Message: fmt.Sprintf("Reservations must be made at least %d minutes in advance", r.MinNoticeMinutes),
+		}
+	}
+
+	if r.MaxAdvanceDays > 0 && at.After(now.AddDate(0, 0, r.MaxAdvanceDays)) {
+		return &BookingError{
+			Code:    ErrCodeReservationTooFar,
+			Message: fmt.Sprintf("Reservations can be made at most %d days in advance", r.MaxAdvanceDays),
+		}
+	}
+
+	return nil
+}
+
+// CheckBlackouts verifies that [start, end) does not overlap any of the blackouts.
+func CheckBlackouts(blackouts []Blackout, start, end time.Time) error {
+	for _, blackout := range blackouts {
+		if blackout.Overlaps(start, end) {
+			message := "The restaurant does not accept reservations at this time"
+			if blackout.Reason != "" {
+				message += ": " + blackout.Reason
+			}
+			return &BookingError{Code: ErrCodeReservationBlackout, Message: message}
+		}
+	}
+	return nil
+}
+
+// Slots lists the time slots of the given day between the opening and closing
+// time of the restaurant, marking the ones that cannot be booked. The special
+// hours of the day, when set, replace the regular ones and a closed day has
+// no slots.
+func (r *Restaurant) Slots(day, now time.Time, blackouts []Blackout, special *SpecialHours) ([]Slot, error) {
+	openTime, closeTime, opens := r.HoursOn(special)
+	if !opens {
+		return []Slot{}, nil
+	}
+	open, err := time.Parse("15:04", openTime)
+	if err != nil {
+		return nil, fmt.Errorf("invalid open time %q", openTime)
+	}
+	closing, err := time.Parse("15:04", closeTime)
+	if err != nil {
+		return nil, fmt.Errorf("invalid close time %q", closeTime)
+	}
+
+	start := time.Date(day.Year(), day.Month(), day.Day(), open.Hour(), open.Minute(), 0, 0, day.Location())
+	end := time.Date(day.Year(), day.Month(), day.Day(), closing.Hour(), closing.Minute(), 0, 0, day.Location())
+	if !end.After(start) {
+		// Closing after midnight
+		end = end.AddDate(0, 0, 1)
+	}
+
+	var slots []Slot
+	for t := start; t.Add(SlotLength).Compare(end) <= 0; t = t.Add(SlotLength) {
+		slot := Slot{Start: t, End: t.Add(SlotLength), Available: true}
+		if err := r.CheckBookingWindow(t, now); err != nil {
+			slot.Available = false
+			slot.Reason = err.(*BookingError).Code
+		} else if err := CheckBlackouts(blackouts, slot.Start, slot.End); err != nil {
+			slot.Available = false
+			slot.Reason = err.(*BookingError).Code
+		}
+		slots = append(slots, slot)
+	}
+
+	return slots, nil
+}
@@ -0,0 +1,202 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SnapshotLeadHours are the times before its start a slot is recorded at,
+// from a week ahead to its start.
+var SnapshotLeadHours = []int{168, 72, 24, 6, 1, 0}
+
+// AvailabilitySnapshot records how booked a slot of a restaurant was some
+// hours before it started.
+type AvailabilitySnapshot struct {
+	ID           uint      `gorm:"primaryKey" json:"-"`
+	RestaurantID uint      `gorm:"uniqueIndex:idx_availability_snapshots_slot" json:"restaurantId"`
+	SlotStart    time.Time `gorm:"uniqueIndex:idx_availability_snapshots_slot" json:"slotStart"`
+	LeadHours    int       `gorm:"uniqueIndex:idx_availability_snapshots_slot" json:"leadHours"`
+	// Reservations counts the pending, confirmed and completed reservations overlapping the slot
+	Reservations int       `json:"reservations"`
+	Available    bool      `json:"available"`
+	Reason       string    `json:"reason,omitempty"`
+	CapturedAt   time.Time `json:"capturedAt"`
+}
+
+// LeadFill is how booked a slot was on average some hours before it started.
+type LeadFill struct {
+	LeadHours    int     `json:"leadHours" example:"24"`
+	Reservations float64 `json:"reservations" example:"4.5"`
+	// FillRate is the share of the bookings the slot ends up with that were already made
+	FillRate float64 `json:"fillRate" example:"0.75"`
+	// Unavailable is the share of the slots that could not be booked anymore
+	Unavailable float64 `json:"unavailable" example:"0.1"`
+}
+
+// SlotFill describes how a slot of the week, e.g. Friday 19:00, usually
+// fills up.
+type SlotFill struct {
+	// Weekday is the ISO day of the week, 1 for Monday to 7 for Sunday
+	Weekday int    `json:"weekday" example:"5"`
+	Time    string `json:"time" example:"19:00"`
+	// Slots counts the slots recorded at their start
+	Slots int64 `json:"slots" example:"4"`
+	// Reservations is the average number of reservations at the start of the slot
+	Reservations float64 `json:"reservations" example:"6"`
+	// FullAtLeadHours is the earliest recorded lead time the slot already had
+	// all its bookings at, on average, nil when it never had reservations
+	FullAtLeadHours *int       `json:"fullAtLeadHours" example:"6"`
+	ByLead          []LeadFill `json:"byLead"`
+}
+
+type AvailabilitySnapshotHandler struct {
+	db *gorm.DB
+}
+
+func NewAvailabilitySnapshotHandler(db *gorm.DB) *AvailabilitySnapshotHandler {
+	return &AvailabilitySnapshotHandler{db}
+}
+
+// RecordSnapshots records, for every lead time, the slots of every restaurant
+// starting between that lead time and one interval later. Running it every
+// interval records every slot once per lead time, recording the same window
+// twice keeps the first snapshots.
+func (h *AvailabilitySnapshotHandler) RecordSnapshots(now time.Time, interval time.Duration) error {
+	var restaurants []Restaurant
+	return h.db.Model(&Restaurant{}).FindInBatches(&restaurants, 100, func(tx *gorm.DB, batch int) error {
+		for i := range restaurants {
+			for _, lead := range SnapshotLeadHours {
+				start := now.Add(time.Duration(lead) * time.Hour)
+				if err := h.recordWindow(&restaurants[i], now, start, start.Add(interval), lead); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}).Error
+}
+
+// recordWindow records the slots of the restaurant starting in [start, end).
+func (h *AvailabilitySnapshotHandler) recordWindow(restaurant *Restaurant, now, start, end time.Time, lead int) error {
+	location := restaurant.Location()
+	localStart := start.In(location)
+	dayStart := time.Date(localStart.Year(), localStart.Month(), localStart.Day(), 0, 0, 0, 0, location)
+
+	// Slots of the day before may run past midnight into the window
+	blackouts, err := NewBlackoutHandler(h.db).GetBlackoutsByRestaurantID(restaurant.ID, dayStart.AddDate(0, 0, -1), dayStart.AddDate(0, 0, 2))
+	if err != nil {
+		return err
+	}
+	specialHours := NewSpecialHoursHandler(h.db)
+	var slots []Slot
+	for day := dayStart.AddDate(0, 0, -1); day.Before(end); day = day.AddDate(0, 0, 1) {
+		special, err := specialHours.GetSpecialHoursOn(restaurant, day)
+		if err != nil {
+			return err
+		}
+		daySlots, err := restaurant.Slots(day, now, blackouts, special)
+		if err != nil {
+			// Restaurants without valid opening hours have no slots
+			return nil
+		}
+		for _, slot := range daySlots {
+			if !slot.Start.Before(start) && slot.Start.Before(end) {
+				slots = append(slots, slot)
+			}
+		}
+	}
+	if len(slots) == 0 {
+		return nil
+	}
+
+	var reservations []Reservation
+	if err := h.db.Select("date_time", "exit_time").
+		Where("restaurant_id = ? AND status IN ? AND date_time >= ? AND date_time < ?", restaurant.ID,
+			[]string{ReservationStatusPending, ReservationStatusConfirmed, ReservationStatusCompleted},
+			start.AddDate(0, 0, -1), end.Add(SlotLength)).
+		Find(&reservations).Error; err != nil {
+		return err
+	}
+
+	snapshots := make([]AvailabilitySnapshot, len(slots))
+	for i, slot := range slots {
+		snapshots[i] = AvailabilitySnapshot{
+			RestaurantID: restaurant.ID,
+			SlotStart:    slot.Start,
+			LeadHours:    lead,
+			Available:    slot.Available,
+			Reason:       slot.Reason,
+			CapturedAt:   now,
+		}
+		for _, reservation := range reservations {
+			exit := reservation.ExitTime
+			if !exit.After(reservation.DateTime) {
+				exit = reservation.DateTime.Add(SlotLength)
+			}
+			if reservation.DateTime.Before(slot.End) && exit.After(slot.Start) {
+				snapshots[i].Reservations++
+			}
+		}
+	}
+	return h.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&snapshots).Error
+}
+
+type leadFillRow struct {
+	Weekday      int
+	SlotTime     string
+	LeadHours    int
+	Slots        int64
+	Reservations float64
+	Unavailable  float64
+}
+
+// GetSlotFills describes how the slots of the week of the restaurant
+// starting in [from, to) filled up, in its time zone, by weekday and time.
+func (h *AvailabilitySnapshotHandler) GetSlotFills(restaurant *Restaurant, from, to time.Time) ([]SlotFill, error) {
+	local := gorm.Expr("slot_start AT TIME ZONE ?", restaurant.Location().String())
+	var rows []leadFillRow
+	err := h.db.Model(&AvailabilitySnapshot{}).
+		Select(`EXTRACT(ISODOW FROM ?)::int AS weekday, to_char(?, 'HH24:MI') AS slot_time, lead_hours,
+			COUNT(*) AS slots, AVG(reservations) AS reservations,
+			AVG(CASE WHEN available THEN 0 ELSE 1 END) AS unavailable`, local, local).
+		Where("restaurant_id = ? AND slot_start >= ? AND slot_start < ?", restaurant.ID, from, to).
+		Group("weekday, slot_time, lead_hours").
+		Order("weekday, slot_time, lead_hours DESC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	fills := []SlotFill{}
+	for _, row := range rows {
+		last := len(fills) - 1
+		if last < 0 || fills[last].Weekday != row.Weekday || fills[last].Time != row.SlotTime {
+			fills = append(fills, SlotFill{Weekday: row.Weekday, Time: row.SlotTime, ByLead: []LeadFill{}})
+			last++
+		}
+		fills[last].ByLead = append(fills[last].ByLead, LeadFill{LeadHours: row.LeadHours, Reservations: row.Reservations, Unavailable: row.Unavailable})
+		if row.LeadHours == 0 {
+			fills[last].Slots = row.Slots
+			fills[last].Reservations = row.Reservations
+		}
+	}
+
+	for i := range fills {
+		final := fills[i].Reservations
+		if final == 0 {
+			continue
+		}
+		// Leads are ordered from the farthest
+		for j := range fills[i].ByLead {
+			lead := &fills[i].ByLead[j]
+			lead.FillRate = lead.Reservations / final
+			if lead.FillRate >= 1 && fills[i].FullAtLeadHours == nil {
+				hours := lead.LeadHours
+				fills[i].FullAtLeadHours = &hours
+			}
+		}
+	}
+	return fills, nil
+}
@@ -0,0 +1,257 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	BackfillRunning   = "running"
+	BackfillPaused    = "paused"
+	BackfillCompleted = "completed"
+	BackfillFailed    = "failed"
+)
+
+// Batches default to DefaultBackfillBatchSize rows with DefaultBackfillDelay
+// between them, so a backfill does not starve the requests of the database.
+const (
+	DefaultBackfillBatchSize = 200
+	MaxBackfillBatchSize     = 5000
+	DefaultBackfillDelay     = 500 * time.Millisecond
+)
+
+var ErrUnknownBackfill = fmt.Errorf("unknown backfill")
+var ErrBackfillActive = fmt.Errorf("the backfill already has a running or paused run")
+var ErrBackfillRunState = fmt.Errorf("the run cannot change to this status")
+
+// Backfill computes a field on the existing rows of a table, e.g. after the
+// field was introduced. Rows are walked by ID so a run resumes where it
+// stopped.
+type Backfill struct {
+	Key         string `json:"key" example:"verified_ratings"`
+	Description string `json:"description"`
+	// model is the table walked, soft deleted rows are skipped
+	model interface{}
+	// process computes the field of the rows with the IDs
+	process func(tx *gorm.DB, ids []uint) error
+}
+
+// Backfills is the registry of the backfills admins can run.
+var Backfills = []Backfill{
+	{
+		Key:         "verified_ratings",
+		Description: "Recomputes the rating of every restaurant from the reviews of completed reservations.",
+		model:       &Restaurant{},
+		process: func(tx *gorm.DB, ids []uint) error {
+			restaurants := NewRestaurantHandler(tx)
+			for _, id := range ids {
+				if err := restaurants.RecomputeVerifiedRating(id); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Key:         "review_tags",
+		Description: "Recomputes the sentiment and tags of every review, e.g. after the tag rules changed.",
+		model:       &Comment{},
+		process: func(tx *gorm.DB, ids []uint) error {
+			tagger := &CommentTagHandler{db: tx}
+			for _, id := range ids {
+				if err := tagger.TagComment(id); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Key:         "gallery_thumbnails",
+		Description: "Makes the thumbnails of every gallery image again from its original, e.g. after the image processing changed.",
+		model:       &RestaurantImage{},
+		process: func(tx *gorm.DB, ids []uint) error {
+			return refreshThumbnails(tx, &RestaurantImage{}, ids)
+		},
+	},
+	{
+		Key:         "review_photo_thumbnails",
+		Description: "Makes the thumbnails of every review photo again from its original, e.g. after the image processing changed.",
+		model:       &CommentPhoto{},
+		process: func(tx *gorm.DB, ids []uint) error {
+			return refreshThumbnails(tx, &CommentPhoto{}, ids)
+		},
+	},
+}
+
+func findBackfill(key string) (*Backfill, bool) {
+	for i := range Backfills {
+		if Backfills[i].Key == key {
+			return &Backfills[i], true
+		}
+	}
+	return nil, false
+}
+
+// BackfillRun tracks the progress of a backfill. Cursor is the ID of the last
+// row processed, every batch moves it in the same transaction as the rows it
+// computed.
+type BackfillRun struct {
+	ID           uint   `gorm:"primaryKey" json:"id"`
+	Backfill     string `gorm:"index" json:"backfill" example:"verified_ratings"`
+	Status       string `gorm:"index" json:"status" example:"running" enums:"running,paused,completed,failed"`
+	BatchSize    int    `json:"batchSize" example:"200"`
+	BatchDelayMs int    `json:"batchDelayMs" example:"500"`
+	Cursor       uint   `json:"cursor" example:"4200"`
+	Processed    int64  `json:"processed" example:"4000"`
+	// Total is the number of rows when the run started
+	Total       int64      `json:"total" example:"12000"`
+	Error       string     `json:"error,omitempty"`
+	StartedBy   uint       `json:"startedBy" example:"1"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+}
+
+type BackfillHandler struct {
+	db *gorm.DB
+}
+
+func NewBackfillHandler(db *gorm.DB) *BackfillHandler {
+	return &BackfillHandler{db}
+}
+
+// StartRun queues a run of the backfill, picked up by ProcessRuns.
+func (h *BackfillHandler) StartRun(key string, batchSize int, batchDelay time.Duration, startedBy uint) (*BackfillRun, error) {
+	backfill, ok := findBackfill(key)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownBackfill, key)
+	}
+
+	run := BackfillRun{
+		Backfill:     key,
+		Status:       BackfillRunning,
+		BatchSize:    batchSize,
+		BatchDelayMs: int(batchDelay / time.Millisecond),
+		StartedBy:    startedBy,
+	}
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		var active int64
+		if err := tx.Model(&BackfillRun{}).Where("backfill = ? AND status IN ?", key, []string{BackfillRunning, BackfillPaused}).
+			Count(&active).Error; err != nil {
+			return err
+		}
+		if active > 0 {
+			return ErrBackfillActive
+		}
+		if err := tx.Model(backfill.model).Count(&run.Total).Error; err != nil {
+			return err
+		}
+		return tx.Create(&run).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+func (h *BackfillHandler) GetRun(id uint) (*BackfillRun, error) {
+	var run BackfillRun
+	err := h.db.First(&run, id).Error
+	return &run, err
+}
+
+// PauseRun stops a running run after its current batch.
+func (h *BackfillHandler) PauseRun(run *BackfillRun) error {
+	return h.setStatus(run, BackfillPaused, BackfillRunning)
+}
+
+// ResumeRun continues a paused or failed run from its cursor.
+func (h *BackfillHandler) ResumeRun(run *BackfillRun) error {
+	return h.setStatus(run, BackfillRunning, BackfillPaused, BackfillFailed)
+}
+
+func (h *BackfillHandler) setStatus(run *BackfillRun, status string, from ...string) error {
+	result := h.db.Model(run).Where("status IN ?", from).Updates(map[string]interface{}{"status": status, "error": ""})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrBackfillRunState
+	}
+	return nil
+}
+
+// GetRuns returns the runs newest first.
+func (h *BackfillHandler) GetRuns(limit, offset int) ([]BackfillRun, int64, error) {
+	var total int64
+	if err := h.db.Model(&BackfillRun{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var runs []BackfillRun
+	err := h.db.Order("id DESC").Limit(limit).Offset(offset).Find(&runs).Error
+	return runs, total, err
+}
+
+// ProcessRuns works through the running runs batch by batch until they
+// complete, fail or are paused.
+func (h *BackfillHandler) ProcessRuns() error {
+	var runs []BackfillRun
+	if err := h.db.Where("status = ?", BackfillRunning).Order("id").Find(&runs).Error; err != nil {
+		return err
+	}
+	for i := range runs {
+		if err := h.process(&runs[i]); err != nil {
+			failed := map[string]interface{}{"status": BackfillFailed, "error": err.Error()}
+			if err := h.db.Model(&runs[i]).Updates(failed).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (h *BackfillHandler) process(run *BackfillRun) error {
+	backfill, ok := findBackfill(run.Backfill)
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownBackfill, run.Backfill)
+	}
+
+	for {
+		// Pick up a pause from an admin between batches
+		if err := h.db.Select("status").First(run, run.ID).Error; err != nil {
+			return err
+		}
+		if run.Status != BackfillRunning {
+			return nil
+		}
+
+		var ids []uint
+		if err := h.db.Model(backfill.model).Where("id > ?", run.Cursor).Order("id").Limit(run.BatchSize).
+			Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			now := time.Now()
+			return h.db.Model(run).Updates(BackfillRun{Status: BackfillCompleted, CompletedAt: &now}).Error
+		}
+
+		err := h.db.Transaction(func(tx *gorm.DB) error {
+			if err := backfill.process(tx, ids); err != nil {
+				return err
+			}
+			return tx.Model(run).Updates(map[string]interface{}{
+				"cursor":    ids[len(ids)-1],
+				"processed": gorm.Expr("processed + ?", len(ids)),
+			}).Error
+		})
+		if err != nil {
+			return err
+		}
+		run.Cursor = ids[len(ids)-1]
+
+		time.Sleep(time.Duration(run.BatchDelayMs) * time.Millisecond)
+	}
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Blackout is a period during which a restaurant does not accept bookings,
+// e.g. "kitchen closed 14:00-17:00" or a closure spanning several days.
+type Blackout struct {
+	ID           uint      `gorm:"primaryKey"`
+	RestaurantID uint      `json:"restaurantId" gorm:"index"`
+	StartTime    time.Time `json:"startTime"`
+	EndTime      time.Time `json:"endTime"`
+	Reason       string    `json:"reason"`
+	gorm.Model   `json:"-" swaggerignore:"true"`
+}
+
+// Overlaps reports whether the blackout intersects the half-open range [start, end).
+func (b *Blackout) Overlaps(start, end time.Time) bool {
+	return start.Before(b.EndTime) && end.After(b.StartTime)
+}
+
+type BlackoutHandler struct {
+	db *gorm.DB
+}
+
+func NewBlackoutHandler(db *gorm.DB) *BlackoutHandler {
+	return &BlackoutHandler{db}
+}
+
+func (h *BlackoutHandler) CreateBlackout(blackout *Blackout) error {
+	return h.db.Create(blackout).Error
+}
+
+func (h *BlackoutHandler) GetBlackout(id uint) (*Blackout, error) {
+	var blackout Blackout
+	result := h.db.First(&blackout, id)
+	return &blackout, result.Error
+}
+
+// GetBlackoutsByRestaurantID returns the blackouts of a restaurant overlapping
+// [from, to). A zero to returns every blackout ending after from.
+func (h *BlackoutHandler) GetBlackoutsByRestaurantID(restaurantID uint, from, to time.Time) ([]Blackout, error) {
+	var blackouts []Blackout
+	query := h.db.Where("restaurant_id = ? AND end_time > ?", restaurantID, from)
+	if !to.IsZero() {
+		query = query.Where("start_time < ?", to)
+	}
+	result := query.Order("start_time").Find(&blackouts)
+	return blackouts, result.Error
+}
+
+func (h *BlackoutHandler) DeleteBlackout(id uint) error {
+	result := h.db.Delete(&Blackout{}, id)
+	return result.Error
+}
@@ -0,0 +1,165 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidCategorySlug = fmt.Errorf("invalid slug, expected lowercase letters and digits separated by dashes, e.g. vegan-friendly")
+var ErrCategorySlugTaken = fmt.Errorf("a category with this slug already exists")
+var ErrUnknownCategory = fmt.Errorf("unknown category")
+
+var categorySlugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+var categorySlugSeparators = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Category is a cuisine or a tag such as "vegan-friendly" that admins manage
+// and restaurants are listed under.
+type Category struct {
+	ID        uint      `gorm:"primaryKey" json:"id" example:"3"`
+	Slug      string    `gorm:"uniqueIndex" json:"slug" example:"vegan-friendly"`
+	Name      string    `json:"name" example:"Vegan friendly"`
+	CreatedAt time.Time `json:"-" swaggerignore:"true"`
+	UpdatedAt time.Time `json:"-" swaggerignore:"true"`
+}
+
+// CategorySlug returns the slug of a category, derived from the name when
+// the slug is empty.
+func CategorySlug(slug, name string) (string, error) {
+	if slug == "" {
+		slug = strings.Trim(categorySlugSeparators.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	}
+	if !categorySlugPattern.MatchString(slug) {
+		return "", ErrInvalidCategorySlug
+	}
+	return slug, nil
+}
+
+// duplicateCategoryError turns the unique violation of the slug into ErrCategorySlugTaken.
+func duplicateCategoryError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return ErrCategorySlugTaken
+	}
+	return err
+}
+
+type CategoryHandler struct {
+	db *gorm.DB
+}
+
+func NewCategoryHandler(db *gorm.DB) *CategoryHandler {
+	return &CategoryHandler{db}
+}
+
+func (h *CategoryHandler) GetCategories() ([]Category, error) {
+	var categories []Category
+	err := h.db.Order("name, id").Find(&categories).Error
+	return categories, err
+}
+
+// GetCategoriesBySlugs returns the categories with the slugs, failing with
+// ErrUnknownCategory when one does not exist.
+func (h *CategoryHandler) GetCategoriesBySlugs(slugs []string) ([]Category, error) {
+	categories := []Category{}
+	if len(slugs) == 0 {
+		return categories, nil
+	}
+	if err := h.db.Where("slug IN ?", slugs).Order("id").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	found := map[string]bool{}
+	for _, category := range categories {
+		found[category.Slug] = true
+	}
+	for _, slug := range slugs {
+		if !found[slug] {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownCategory, slug)
+		}
+	}
+	return categories, nil
+}
+
+func (h *CategoryHandler) CreateCategory(category *Category) error {
+	return duplicateCategoryError(h.db.Create(category).Error)
+}
+
+func (h *CategoryHandler) GetCategory(id uint) (*Category, error) {
+	var category Category
+	err := h.db.First(&category, id).Error
+	return &category, err
+}
+
+func (h *CategoryHandler) UpdateCategory(category *Category) error {
+	return duplicateCategoryError(h.db.Save(category).Error)
+}
+
+// DeleteCategory removes the category from every restaurant listed under it.
+func (h *CategoryHandler) DeleteCategory(id uint) error {
+	return h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM restaurant_categories WHERE category_id = ?", id).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&Category{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// SetRestaurantCategories replaces the categories the restaurant is listed under.
+func (h *RestaurantHandler) SetRestaurantCategories(id uint, categories []Category) error {
+	return h.db.Model(&Restaurant{ID: id}).Association("Categories").Replace(categories)
+}
+
+// InCategory keeps the restaurants listed under the category with the slug.
+func InCategory(slug string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(`EXISTS (SELECT 1 FROM restaurant_categories
+			JOIN categories ON categories.id = restaurant_categories.category_id
+			WHERE restaurant_categories.restaurant_id = restaurants.id AND categories.slug = ?)`, slug)
+	}
+}
+
+// MigrateCuisines lists the restaurants under a category for the cuisine they
+// were given before cuisines became categories, creating the missing
+// categories, then drops the cuisine column.
+func MigrateCuisines(db *gorm.DB) error {
+	if !db.Migrator().HasColumn(&Restaurant{}, "cuisine") {
+		return nil
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		var cuisines []string
+		if err := tx.Table("restaurants").Distinct("cuisine").Where("cuisine <> ''").Pluck("cuisine", &cuisines).Error; err != nil {
+			return err
+		}
+		for _, cuisine := range cuisines {
+			slug, err := CategorySlug("", cuisine)
+			if err != nil {
+				log.Printf("Dropping cuisine %q, it makes no category slug", cuisine)
+				continue
+			}
+			name := []rune(cuisine)
+			category := Category{Slug: slug}
+			if err := tx.Where(&category).Attrs(Category{Name: strings.ToUpper(string(name[:1])) + string(name[1:])}).
+				FirstOrCreate(&category).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec(`INSERT INTO restaurant_categories (restaurant_id, category_id)
+				SELECT id, ? FROM restaurants WHERE cuisine = ? ON CONFLICT DO NOTHING`, category.ID, cuisine).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Migrator().DropColumn(&Restaurant{}, "cuisine")
+	})
+}
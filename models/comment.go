@@ -1,20 +1,134 @@
 package models
 
 import (
+	"errors"
+	"fmt"
+	"strings"
 	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
 )
 
 type Comment struct {
-	ID           uint       `gorm:"primaryKey"`
-	DateTime     time.Time  `json:"dateTime"`
-	MyComment    string     `json:"myComment"`
-	Rating       float64    `json:"rating"`
-	UserID       uint       `json:"userId"`
-	User         User       `gorm:"foreignKey:UserID" json:"user"`
-	RestaurantID uint       `json:"restaurantId"`
-	Restaurant   Restaurant `gorm:"foreignKey:RestaurantID" json:"restaurant"`
-	gorm.Model   `json:"-" swaggerignore:"true"`
+	ID            uint         `gorm:"primaryKey"`
+	DateTime      time.Time    `json:"dateTime"`
+	MyComment     string       `json:"myComment"`
+	Rating        float64      `json:"rating"`
+	UserID        uint         `json:"userId"`
+	User          User         `gorm:"foreignKey:UserID" json:"user"`
+	RestaurantID  uint         `json:"restaurantId"`
+	Restaurant    Restaurant   `gorm:"foreignKey:RestaurantID" json:"restaurant"`
+	Sentiment     string       `json:"sentiment" gorm:"index"`
+	ReservationID *uint        `json:"reservationId" gorm:"uniqueIndex:idx_comments_reservation_id,where:deleted_at IS NULL"`
+	Verified      bool         `json:"verified" gorm:"default:false"`
+	Tags          []CommentTag `gorm:"foreignKey:CommentID" json:"tags"`
+	Anonymous     bool         `json:"anonymous" gorm:"default:false"`
+	DisplayName   string       `json:"displayName"`
+	gorm.Model    `json:"-" swaggerignore:"true"`
+}
+
+// AnonymousReviewerName is shown as the author of anonymous reviews.
+const AnonymousReviewerName = "Anonymous"
+
+// MaxDisplayNameLength is the longest display name a reviewer can choose.
+const MaxDisplayNameLength = 50
+
+var ErrInvalidDisplayName = fmt.Errorf("invalid display name")
+var ErrReservationReviewed = fmt.Errorf("the reservation was already reviewed")
+
+// ReviewAuthor is the public identity of the author of a review. It never
+// carries contact details, and neither the ID nor the picture of anonymous
+// reviewers.
+type ReviewAuthor struct {
+	ID       *uint  `json:"id,omitempty" example:"42"`
+	Name     string `json:"name" example:"Somchai"`
+	ImageURL string `json:"imageUrl,omitempty"`
+}
+
+// CommentResponse is a comment as returned by the API.
+type CommentResponse struct {
+	ID            uint                `json:"ID" example:"1"`
+	DateTime      time.Time           `json:"dateTime"`
+	MyComment     string              `json:"myComment"`
+	Rating        float64             `json:"rating" example:"4.5"`
+	UserID        *uint               `json:"userId,omitempty" example:"42"`
+	User          ReviewAuthor        `json:"user"`
+	RestaurantID  uint                `json:"restaurantId" example:"7"`
+	Restaurant    *RestaurantResponse `json:"restaurant,omitempty"`
+	Sentiment     string              `json:"sentiment" example:"positive"`
+	ReservationID *uint               `json:"reservationId,omitempty"`
+	Verified      bool                `json:"verified"`
+	Anonymous     bool                `json:"anonymous"`
+	Tags          []CommentTag        `json:"tags"`
+}
+
+// NormalizeDisplayName trims the display name and checks its length. An empty
+// name means the name of the user is shown.
+func NormalizeDisplayName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if len([]rune(name)) > MaxDisplayNameLength {
+		return "", fmt.Errorf("%w: display name must be at most %d characters", ErrInvalidDisplayName, MaxDisplayNameLength)
+	}
+	return name, nil
+}
+
+// Author returns the identity the review is published under. The user must be
+// preloaded for the name and picture to be filled in.
+func (c *Comment) Author() ReviewAuthor {
+	if c.Anonymous {
+		return ReviewAuthor{Name: AnonymousReviewerName}
+	}
+	id := c.UserID
+	author := ReviewAuthor{ID: &id, Name: c.User.Name, ImageURL: c.User.ImageURL}
+	if c.DisplayName != "" {
+		author.Name = c.DisplayName
+	}
+	return author
+}
+
+// Response converts the comment to its public representation.
+func (c *Comment) Response() CommentResponse {
+	response := CommentResponse{
+		ID:           c.ID,
+		DateTime:     c.DateTime,
+		MyComment:    c.MyComment,
+		Rating:       c.Rating,
+		User:         c.Author(),
+		RestaurantID: c.RestaurantID,
+		Sentiment:    c.Sentiment,
+		Verified:     c.Verified,
+		Anonymous:    c.Anonymous,
+		Tags:         c.Tags,
+	}
+	// The reservation would point back to the reviewer as well
+	if !c.Anonymous {
+		response.UserID = response.User.ID
+		response.ReservationID = c.ReservationID
+	}
+	if c.Restaurant.ID != 0 {
+		restaurant := c.Restaurant.Response()
+		response.Restaurant = &restaurant
+	}
+	if response.Tags == nil {
+		response.Tags = []CommentTag{}
+	}
+	return response
+}
+
+// CommentResponses converts a list of comments to their public representation.
+func CommentResponses(comments []Comment) []CommentResponse {
+	responses := make([]CommentResponse, len(comments))
+	for i := range comments {
+		responses[i] = comments[i].Response()
+	}
+	return responses
+}
+
+// CommentFilter narrows down the comments of a restaurant.
+type CommentFilter struct {
+	Tag       string
+	Sentiment string
 }
 
 type CommentHandler struct {
@@ -25,25 +139,31 @@ func NewCommentHandler(db *gorm.DB) *CommentHandler {
 	return &CommentHandler{db}
 }
 
+// CreateComment saves the review of the user. A visit is only reviewed once,
+// ErrReservationReviewed is returned for a second review of the reservation.
 func (h *CommentHandler) CreateComment(userID uint, comment *Comment) error {
 	comment.UserID = userID
 
 	if err := h.db.Create(comment).Error; err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation && strings.Contains(pgErr.ConstraintName, "reservation") {
+			return ErrReservationReviewed
+		}
 		return err
 	}
 
-	return h.db.Preload("User").Preload("Restaurant").First(comment, comment.ID).Error
+	return h.db.Preload("User").Preload("Restaurant").Preload("Tags").First(comment, comment.ID).Error
 }
 
 func (h *CommentHandler) GetComment(id uint) (*Comment, error) {
 	var comment Comment
-	result := h.db.Preload("User").Preload("Restaurant").First(&comment, id)
+	result := h.db.Preload("User").Preload("Restaurant").Preload("Tags").First(&comment, id)
 	return &comment, result.Error
 }
 
 func (h *CommentHandler) GetComments() ([]Comment, error) {
 	var comments []Comment
-	result := h.db.Preload("User").Preload("Restaurant").Find(&comments)
+	result := h.db.Preload("User").Preload("Restaurant").Preload("Tags").Find(&comments)
 	return comments, result.Error
 }
 
@@ -52,17 +172,63 @@ func (h *CommentHandler) UpdateComment(id uint, comment *Comment) error {
 	return result.Error
 }
 
+// SetCommentPrivacy sets whether the review is anonymous and the name it is
+// published under.
+func (h *CommentHandler) SetCommentPrivacy(id uint, anonymous bool, displayName string) error {
+	return h.db.Model(&Comment{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"anonymous": anonymous, "display_name": displayName}).Error
+}
+
 func (h *CommentHandler) DeleteComment(id uint) error {
 	result := h.db.Delete(&Comment{}, id)
 	return result.Error
 }
 
-func (h *CommentHandler) GetCommentsByRestaurantID(restaurantID uint) ([]Comment, error) {
+func (h *CommentHandler) GetCommentsByRestaurantID(restaurantID uint, filter CommentFilter) ([]Comment, error) {
 	var comments []Comment
-	result := h.db.Preload("Restaurant").Preload("User").Where("restaurant_id = ?", restaurantID).Find(&comments)
+	query := h.db.Preload("Restaurant").Preload("User").Preload("Tags").Where("restaurant_id = ?", restaurantID)
+	if filter.Sentiment != "" {
+		query = query.Where("sentiment = ?", filter.Sentiment)
+	}
+	if filter.Tag != "" {
+		query = query.Where("id IN (?)", h.db.Model(&CommentTag{}).Select("comment_id").Where("tag = ?", filter.Tag))
+	}
+	result := query.Find(&comments)
 
 	if result.Error != nil {
 		return nil, result.Error
 	}
 	return comments, nil
 }
+
+// MigrateCommentReservations keeps only the first live review of every
+// reservation verified before a reservation could only be reviewed once, so
+// the unique index on the reservation can be created. The later reviews stay
+// published without the reservation and the verified badge, and the verified
+// rating of their restaurants is computed again.
+func MigrateCommentReservations(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&Comment{}) || !db.Migrator().HasColumn(&Comment{}, "ReservationID") {
+		return nil
+	}
+	first := db.Model(&Comment{}).Select("MIN(id)").Where("reservation_id IS NOT NULL").Group("reservation_id")
+	repeated := db.Model(&Comment{}).Where("reservation_id IS NOT NULL AND id NOT IN (?)", first)
+
+	var restaurantIDs []uint
+	if err := repeated.Session(&gorm.Session{}).Distinct().Pluck("restaurant_id", &restaurantIDs).Error; err != nil {
+		return err
+	}
+	if len(restaurantIDs) == 0 {
+		return nil
+	}
+	if err := repeated.Updates(map[string]interface{}{"reservation_id": nil, "verified": false}).Error; err != nil {
+		return err
+	}
+
+	restaurants := NewRestaurantHandler(db)
+	for _, id := range restaurantIDs {
+		if err := restaurants.RecomputeVerifiedRating(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package models
+
+import (
+	"log"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+const (
+	SentimentPositive = "positive"
+	SentimentNegative = "negative"
+	SentimentNeutral  = "neutral"
+)
+
+// CommentTag is a keyword based aspect extracted from a review, e.g. "great service".
+type CommentTag struct {
+	ID           uint   `gorm:"primaryKey"`
+	CommentID    uint   `json:"commentId" gorm:"index"`
+	RestaurantID uint   `json:"restaurantId" gorm:"index"`
+	Tag          string `json:"tag" gorm:"index"`
+	Sentiment    string `json:"sentiment"`
+}
+
+type TagCount struct {
+	Tag       string `json:"tag"`
+	Sentiment string `json:"sentiment"`
+	Count     int64  `json:"count"`
+}
+
+type tagRule struct {
+	tag       string
+	sentiment string
+	keywords  []string
+}
+
+var tagRules = []tagRule{
+	{"great service", SentimentPositive, []string{"great service", "excellent service", "good service", "friendly staff", "attentive", "helpful staff", "polite"}},
+	{"poor service", SentimentNegative, []string{"bad service", "poor service", "rude", "unfriendly", "ignored us", "impolite"}},
+	{"slow kitchen", SentimentNegative, []string{"slow", "took forever", "long wait", "waited too long", "waiting forever"}},
+	{"quick service", SentimentPositive, []string{"fast service", "quick service", "served quickly", "no wait"}},
+	{"delicious food", SentimentPositive, []string{"delicious", "tasty", "yummy", "amazing food", "great food", "flavorful"}},
+	{"bland food", SentimentNegative, []string{"bland", "tasteless", "no flavor", "flavorless"}},
+	{"good value", SentimentPositive, []string{"good value", "worth it", "affordable", "reasonable price", "cheap"}},
+	{"overpriced", SentimentNegative, []string{"overpriced", "expensive", "pricey", "not worth"}},
+	{"clean", SentimentPositive, []string{"clean", "spotless", "hygienic"}},
+	{"dirty", SentimentNegative, []string{"dirty", "filthy", "unhygienic", "smelly"}},
+	{"cozy atmosphere", SentimentPositive, []string{"cozy", "nice atmosphere", "great atmosphere", "ambience", "ambiance", "relaxing"}},
+	{"noisy", SentimentNegative, []string{"noisy", "too loud", "crowded"}},
+}
+
+var positiveWords = []string{"good", "great", "excellent", "amazing", "love", "loved", "delicious", "friendly", "best", "recommend", "nice", "perfect", "tasty"}
+var negativeWords = []string{"bad", "terrible", "awful", "horrible", "worst", "rude", "slow", "dirty", "bland", "disappointed", "disappointing", "overpriced", "never again"}
+
+// AnalyzeComment extracts aspect tags and an overall sentiment from a review.
+// The star rating breaks ties when the wording is inconclusive.
+func AnalyzeComment(text string, rating float64) (string, []string) {
+	lower := strings.ToLower(text)
+
+	var tags []string
+	for _, rule := range tagRules {
+		for _, keyword := range rule.keywords {
+			if strings.Contains(lower, keyword) {
+				tags = append(tags, rule.tag)
+				break
+			}
+		}
+	}
+
+	score := 0
+	for _, word := range positiveWords {
+		score += strings.Count(lower, word)
+	}
+	for _, word := range negativeWords {
+		score -= strings.Count(lower, word)
+	}
+
+	switch {
+	case score > 0:
+		return SentimentPositive, tags
+	case score < 0:
+		return SentimentNegative, tags
+	case rating >= 4:
+		return SentimentPositive, tags
+	case rating > 0 && rating <= 2:
+		return SentimentNegative, tags
+	default:
+		return SentimentNeutral, tags
+	}
+}
+
+type CommentTagHandler struct {
+	db   *gorm.DB
+	jobs chan uint
+}
+
+// NewCommentTagHandler starts a background worker that tags the comments
+// queued with Enqueue, so creating a review never waits on the analysis.
+func NewCommentTagHandler(db *gorm.DB) *CommentTagHandler {
+	h := &CommentTagHandler{db: db, jobs: make(chan uint, 100)}
+	go h.work()
+	return h
+}
+
+func (h *CommentTagHandler) work() {
+	for commentID := range h.jobs {
+		if err := h.TagComment(commentID); err != nil {
+			log.Printf("Error tagging comment %d: %v", commentID, err)
+		}
+	}
+}
+
+func (h *CommentTagHandler) Enqueue(commentID uint) {
+	select {
+	case h.jobs <- commentID:
+	default:
+		// Queue is full, tag in a goroutine of its own instead of dropping the job
+		go func() {
+			if err := h.TagComment(commentID); err != nil {
+				log.Printf("Error tagging comment %d: %v", commentID, err)
+			}
+		}()
+	}
+}
+
+// TagComment (re)computes the sentiment and tags of a comment.
+func (h *CommentTagHandler) TagComment(commentID uint) error {
+	var comment Comment
+	if err := h.db.First(&comment, commentID).Error; err != nil {
+		return err
+	}
+
+	sentiment, tags := AnalyzeComment(comment.MyComment, comment.Rating)
+
+	return h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&Comment{}).Where("id = ?", comment.ID).Update("sentiment", sentiment).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("comment_id = ?", comment.ID).Delete(&CommentTag{}).Error; err != nil {
+			return err
+		}
+		for _, tag := range tags {
+			commentTag := CommentTag{CommentID: comment.ID, RestaurantID: comment.RestaurantID, Tag: tag, Sentiment: sentiment}
+			if err := tx.Create(&commentTag).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetRestaurantTagCounts aggregates the tags of the live reviews of a restaurant.
+func (h *CommentTagHandler) GetRestaurantTagCounts(restaurantID uint) ([]TagCount, error) {
+	var counts []TagCount
+	result := h.db.Model(&CommentTag{}).
+		Select("comment_tags.tag, comment_tags.sentiment, COUNT(*) AS count").
+		Joins("JOIN comments ON comments.id = comment_tags.comment_id AND comments.deleted_at IS NULL").
+		Where("comment_tags.restaurant_id = ?", restaurantID).
+		Group("comment_tags.tag, comment_tags.sentiment").
+		Order("count DESC").
+		Scan(&counts)
+	return counts, result.Error
+}
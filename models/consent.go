@@ -0,0 +1,259 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	ConsentMarketingEmails = "marketing_emails"
+	ConsentAnalytics       = "analytics"
+)
+
+var ErrUnknownConsentPurpose = fmt.Errorf("unknown consent purpose")
+
+// ProcessingActivity is an entry of the registry of the ways RedRice
+// processes personal data, as required by GDPR and PDPA.
+type ProcessingActivity struct {
+	Purpose        string   `json:"purpose" example:"marketing_emails"`
+	Description    string   `json:"description"`
+	DataCategories []string `json:"dataCategories" example:"email,name"`
+	LegalBasis     string   `json:"legalBasis" example:"consent" enums:"contract,consent,legitimate_interest,legal_obligation"`
+	Retention      string   `json:"retention"`
+	// RequiresConsent is set for the purposes users can opt in to and out of
+	RequiresConsent bool `json:"requiresConsent"`
+	// Version is the current wording of the consent, users who agreed to an older one are asked again
+	Version string `json:"version,omitempty" example:"2024-05"`
+}
+
+// ProcessingActivities is the data-processing registry. Bump the version of a
+// consent purpose whenever what the user agrees to changes.
+var ProcessingActivities = []ProcessingActivity{
+	{
+		Purpose:        "account",
+		Description:    "Creating and securing the account, logging in and support.",
+		DataCategories: []string{"name", "email", "telephone", "password", "sessions", "activity"},
+		LegalBasis:     "contract",
+		Retention:      "Until the account is deleted.",
+	},
+	{
+		Purpose:        "reservations",
+		Description:    "Booking tables and sharing the booking with the restaurant.",
+		DataCategories: []string{"name", "email", "telephone", "reservations"},
+		LegalBasis:     "contract",
+		Retention:      "Until the account is deleted.",
+	},
+	{
+		Purpose:        "reviews",
+		Description:    "Publishing the reviews and photos of the user.",
+		DataCategories: []string{"name", "reviews", "photos"},
+		LegalBasis:     "contract",
+		Retention:      "Until the review or the account is deleted.",
+	},
+	{
+		Purpose:         ConsentMarketingEmails,
+		Description:     "Emails about promotions and new restaurants.",
+		DataCategories:  []string{"name", "email", "preferences"},
+		LegalBasis:      "consent",
+		Retention:       "Until the consent is withdrawn.",
+		RequiresConsent: true,
+		Version:         "2024-05",
+	},
+	{
+		Purpose:         ConsentAnalytics,
+		Description:     "Measuring how the app is used to improve it.",
+		DataCategories:  []string{"activity", "device"},
+		LegalBasis:      "consent",
+		Retention:       "26 months.",
+		RequiresConsent: true,
+		Version:         "2024-05",
+	},
+}
+
+// consentActivity returns the registry entry of a purpose users consent to.
+func consentActivity(purpose string) (*ProcessingActivity, bool) {
+	for i := range ProcessingActivities {
+		if ProcessingActivities[i].Purpose == purpose && ProcessingActivities[i].RequiresConsent {
+			return &ProcessingActivities[i], true
+		}
+	}
+	return nil, false
+}
+
+// ConsentEvent is an entry of the append-only log of the consents a user gave
+// or withdrew.
+type ConsentEvent struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	UserID     uint      `gorm:"index:idx_consent_events_user_purpose" json:"-" swaggerignore:"true"`
+	Purpose    string    `gorm:"index:idx_consent_events_user_purpose" json:"purpose" example:"marketing_emails" enums:"marketing_emails,analytics"`
+	Granted    bool      `json:"granted"`
+	Version    string    `json:"version" example:"2024-05"`
+	IP         string    `json:"ip" example:"203.0.113.7"`
+	UserAgent  string    `json:"userAgent"`
+	RecordedAt time.Time `json:"recordedAt"`
+}
+
+// Consent is the current state of the consent of a user to a purpose.
+type Consent struct {
+	Purpose string `json:"purpose" example:"marketing_emails" enums:"marketing_emails,analytics"`
+	Granted bool   `json:"granted"`
+	// Version is the version the user last answered, granted is false when it is outdated
+	Version        string     `json:"version,omitempty" example:"2024-05"`
+	CurrentVersion string     `json:"currentVersion" example:"2024-05"`
+	UpdatedAt      *time.Time `json:"updatedAt,omitempty"`
+}
+
+// ConsentChange is a consent the user gives or withdraws.
+type ConsentChange struct {
+	Purpose string `json:"purpose" binding:"required" example:"marketing_emails" enums:"marketing_emails,analytics"`
+	Granted bool   `json:"granted"`
+}
+
+type ConsentHandler struct {
+	db *gorm.DB
+}
+
+func NewConsentHandler(db *gorm.DB) *ConsentHandler {
+	return &ConsentHandler{db}
+}
+
+// latestEvents returns the latest event of the user for each purpose.
+func (h *ConsentHandler) latestEvents(userID uint) (map[string]ConsentEvent, error) {
+	var events []ConsentEvent
+	if err := h.db.Where("user_id = ?", userID).Order("recorded_at, id").Find(&events).Error; err != nil {
+		return nil, err
+	}
+	latest := map[string]ConsentEvent{}
+	for _, event := range events {
+		latest[event.Purpose] = event
+	}
+	return latest, nil
+}
+
+// GetConsents returns the state of every consent purpose of the registry for
+// the user. Purposes never answered are not granted.
+func (h *ConsentHandler) GetConsents(userID uint) ([]Consent, error) {
+	latest, err := h.latestEvents(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	consents := []Consent{}
+	for _, activity := range ProcessingActivities {
+		if !activity.RequiresConsent {
+			continue
+		}
+		consent := Consent{Purpose: activity.Purpose, CurrentVersion: activity.Version}
+		if event, ok := latest[activity.Purpose]; ok {
+			recordedAt := event.RecordedAt
+			consent.Version = event.Version
+			consent.UpdatedAt = &recordedAt
+			consent.Granted = event.Granted && event.Version == activity.Version
+		}
+		consents = append(consents, consent)
+	}
+	return consents, nil
+}
+
+// RecordConsents logs the changes against the current versions of the
+// purposes. Answers identical to the current state are not logged again.
+func (h *ConsentHandler) RecordConsents(userID uint, changes []ConsentChange, ip, userAgent string, now time.Time) error {
+	for _, change := range changes {
+		if _, ok := consentActivity(change.Purpose); !ok {
+			return fmt.Errorf("%w: %q", ErrUnknownConsentPurpose, change.Purpose)
+		}
+	}
+
+	return h.db.Transaction(func(tx *gorm.DB) error {
+		latest, err := (&ConsentHandler{tx}).latestEvents(userID)
+		if err != nil {
+			return err
+		}
+		for _, change := range changes {
+			activity, _ := consentActivity(change.Purpose)
+			if event, ok := latest[change.Purpose]; ok && event.Granted == change.Granted && event.Version == activity.Version {
+				continue
+			}
+			event := ConsentEvent{
+				UserID:     userID,
+				Purpose:    change.Purpose,
+				Granted:    change.Granted,
+				Version:    activity.Version,
+				IP:         ip,
+				UserAgent:  userAgent,
+				RecordedAt: now,
+			}
+			if err := tx.Create(&event).Error; err != nil {
+				return err
+			}
+			latest[change.Purpose] = event
+		}
+		return nil
+	})
+}
+
+// HasConsent reports whether the user agreed to the current version of the
+// purpose. Notifications and tracking that need consent must check it first.
+func (h *ConsentHandler) HasConsent(userID uint, purpose string) (bool, error) {
+	activity, ok := consentActivity(purpose)
+	if !ok {
+		return false, fmt.Errorf("%w: %q", ErrUnknownConsentPurpose, purpose)
+	}
+	var events []ConsentEvent
+	if err := h.db.Where("user_id = ? AND purpose = ?", userID, purpose).Order("recorded_at DESC, id DESC").Limit(1).Find(&events).Error; err != nil {
+		return false, err
+	}
+	return len(events) == 1 && events[0].Granted && events[0].Version == activity.Version, nil
+}
+
+// GetConsentHistory returns the whole consent log of the user, oldest first.
+func (h *ConsentHandler) GetConsentHistory(userID uint) ([]ConsentEvent, error) {
+	events := []ConsentEvent{}
+	result := h.db.Where("user_id = ?", userID).Order("recorded_at, id").Find(&events)
+	return events, result.Error
+}
+
+// moveConsents moves the consent log of the source user to the target user of
+// a merge. The target keeps its own decisions: when the source decided on a
+// purpose after the target did, the latest decision of the target is recorded
+// again so it stays the current one.
+func moveConsents(tx *gorm.DB, targetID, sourceID uint) error {
+	latest := func(userID uint) (map[string]ConsentEvent, error) {
+		var events []ConsentEvent
+		if err := tx.Where("user_id = ?", userID).Order("recorded_at DESC, id DESC").Find(&events).Error; err != nil {
+			return nil, err
+		}
+		byPurpose := map[string]ConsentEvent{}
+		for _, event := range events {
+			if _, ok := byPurpose[event.Purpose]; !ok {
+				byPurpose[event.Purpose] = event
+			}
+		}
+		return byPurpose, nil
+	}
+	targetLatest, err := latest(targetID)
+	if err != nil {
+		return err
+	}
+	sourceLatest, err := latest(sourceID)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Model(&ConsentEvent{}).Where("user_id = ?", sourceID).Update("user_id", targetID).Error; err != nil {
+		return err
+	}
+	now := time.Now()
+	for purpose, event := range targetLatest {
+		if source, ok := sourceLatest[purpose]; ok && !source.RecordedAt.Before(event.RecordedAt) {
+			event.ID = 0
+			event.RecordedAt = now
+			if err := tx.Create(&event).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Customer is a guest of a restaurant as seen from its reservation history.
+// Email and telephone are left out for users who hide their contact.
+type Customer struct {
+	UserID    uint      `json:"userId" example:"42"`
+	Name      string    `json:"name" example:"Somchai"`
+	Email     string    `json:"email,omitempty" example:"somchai@example.com"`
+	Telephone string    `json:"telephone,omitempty" example:"0812345678"`
+	Visits    int64     `json:"visits" example:"7"`
+	LastVisit time.Time `json:"lastVisit"`
+}
+
+type customerRow struct {
+	Customer
+	HideContact bool
+}
+
+// GetCustomers returns a page of the users with completed reservations at the
+// restaurant, most frequent first, and the total number of customers.
+func (h *ReservationHandler) GetCustomers(restaurantID uint, page, limit int) ([]Customer, int64, error) {
+	visits := h.db.Model(&Reservation{}).
+		Joins("JOIN users ON users.id = reservations.user_id AND users.deleted_at IS NULL").
+		Where("reservations.restaurant_id = ? AND reservations.status = ?", restaurantID, ReservationStatusCompleted)
+
+	var total int64
+	if err := visits.Session(&gorm.Session{}).Distinct("reservations.user_id").Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var rows []customerRow
+	err := visits.Session(&gorm.Session{}).
+		Select("reservations.user_id, users.name, users.email, users.telephone, COUNT(*) AS visits, MAX(reservations.date_time) AS last_visit, COALESCE(user_preferences.hide_contact, false) AS hide_contact").
+		Joins("LEFT JOIN user_preferences ON user_preferences.user_id = reservations.user_id").
+		Group("reservations.user_id, users.name, users.email, users.telephone, user_preferences.hide_contact").
+		Order("visits DESC, last_visit DESC").
+		Offset((page - 1) * limit).Limit(limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	customers := make([]Customer, len(rows))
+	for i, row := range rows {
+		customers[i] = row.Customer
+		if row.HideContact {
+			customers[i].Email = ""
+			customers[i].Telephone = ""
+		}
+	}
+	return customers, total, nil
+}
+
+// HideGuestContacts blanks the email and telephone of the guests who hide
+// their contact, except on the reservations of the viewer whose own they are.
+func (h *ReservationHandler) HideGuestContacts(reservations []Reservation, viewerID uint) error {
+	var userIDs []uint
+	for _, reservation := range reservations {
+		if reservation.UserID != viewerID {
+			userIDs = append(userIDs, reservation.UserID)
+		}
+	}
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	var hidden []uint
+	if err := h.db.Model(&UserPreferences{}).Where("user_id IN ? AND hide_contact", userIDs).
+		Pluck("user_id", &hidden).Error; err != nil {
+		return err
+	}
+	hides := make(map[uint]bool, len(hidden))
+	for _, userID := range hidden {
+		hides[userID] = true
+	}
+	for i := range reservations {
+		if reservations[i].UserID != viewerID && hides[reservations[i].UserID] {
+			reservations[i].User.Email = ""
+			reservations[i].User.ContactEmail = ""
+			reservations[i].User.Telephone = ""
+		}
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package models
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	DataExportStatusProcessing = "processing"
+	DataExportStatusReady      = "ready"
+	DataExportStatusFailed     = "failed"
+)
+
+// DataExport is an archive of all the data held on a user, assembled in the
+// background when they ask for it.
+type DataExport struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	UserID      uint       `gorm:"index" json:"-" swaggerignore:"true"`
+	Status      string     `json:"status" enums:"processing,ready,failed"`
+	ObjectKey   string     `json:"-" swaggerignore:"true"`
+	RequestedAt time.Time  `json:"requestedAt"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	DownloadURL string     `gorm:"-" json:"downloadUrl,omitempty"`
+}
+
+// UserData is the content of a data export.
+type UserData struct {
+	ExportedAt   time.Time            `json:"exportedAt"`
+	Profile      User                 `json:"profile"`
+	Preferences  *UserPreferences     `json:"preferences"`
+	Reservations []Reservation        `json:"reservations"`
+	Comments     []Comment            `json:"comments"`
+	Favorites    []Favorite           `json:"favorites"`
+	Consents     []ConsentEvent       `json:"consents"`
+	Sessions     []Session            `json:"sessions"`
+	Experiments  []ExperimentExposure `json:"experiments"`
+	// AnalyticsEvents are the events kept in the database, sinks outside it are not exported
+	AnalyticsEvents []AnalyticsEvent  `json:"analyticsEvents"`
+	Claims          []RestaurantClaim `json:"claims"`
+}
+
+type DataExportHandler struct {
+	db *gorm.DB
+}
+
+func NewDataExportHandler(db *gorm.DB) *DataExportHandler {
+	return &DataExportHandler{db}
+}
+
+// DataExportKey is where the archive of the export is stored in S3.
+func DataExportKey(export *DataExport) string {
+	return fmt.Sprintf("exports/user-%d/export-%d.zip", export.UserID, export.ID)
+}
+
+func (h *DataExportHandler) CreateExport(userID uint) (*DataExport, error) {
+	export := DataExport{UserID: userID, Status: DataExportStatusProcessing, RequestedAt: time.Now()}
+	if err := h.db.Create(&export).Error; err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+// GetLatestExport returns the most recent export of the user, or nil when
+// they never asked for one.
+func (h *DataExportHandler) GetLatestExport(userID uint) (*DataExport, error) {
+	var exports []DataExport
+	if err := h.db.Where("user_id = ?", userID).Order("requested_at DESC").Limit(1).Find(&exports).Error; err != nil {
+		return nil, err
+	}
+	if len(exports) == 0 {
+		return nil, nil
+	}
+	return &exports[0], nil
+}
+
+func (h *DataExportHandler) MarkReady(export *DataExport, key string, completedAt time.Time) error {
+	export.Status = DataExportStatusReady
+	export.ObjectKey = key
+	export.CompletedAt = &completedAt
+	return h.db.Save(export).Error
+}
+
+func (h *DataExportHandler) MarkFailed(export *DataExport) error {
+	now := time.Now()
+	export.Status = DataExportStatusFailed
+	export.CompletedAt = &now
+	return h.db.Save(export).Error
+}
+
+// CollectUserData gathers the profile and preferences of the user with their
+// reservations, comments, favorites, consents and sessions. Credentials are left out.
+func (h *DataExportHandler) CollectUserData(userID uint) (*UserData, error) {
+	data := UserData{ExportedAt: time.Now()}
+	if err := h.db.First(&data.Profile, userID).Error; err != nil {
+		return nil, err
+	}
+	data.Profile.Password = ""
+
+	preferences, err := NewPreferencesHandler(h.db).GetPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+	data.Preferences = preferences
+
+	if err := h.db.Preload("Restaurant", withDeletedRestaurant).Where("user_id = ?", userID).Order("date_time").Find(&data.Reservations).Error; err != nil {
+		return nil, err
+	}
+	if err := h.db.Preload("Restaurant", withDeletedRestaurant).Preload("Tags").Where("user_id = ?", userID).Order("date_time").Find(&data.Comments).Error; err != nil {
+		return nil, err
+	}
+	if err := h.db.Preload("Restaurant").Where("user_id = ?", userID).Order("created_at").Find(&data.Favorites).Error; err != nil {
+		return nil, err
+	}
+	if data.Consents, err = NewConsentHandler(h.db).GetConsentHistory(userID); err != nil {
+		return nil, err
+	}
+	if err := h.db.Where("user_id = ?", userID).Order("issued_at").Find(&data.Sessions).Error; err != nil {
+		return nil, err
+	}
+	if err := h.db.Where("user_id = ?", userID).Order("id").Find(&data.Experiments).Error; err != nil {
+		return nil, err
+	}
+	if err := h.db.Where("user_id = ?", userID).Order("occurred_at").Find(&data.AnalyticsEvents).Error; err != nil {
+		return nil, err
+	}
+	if err := h.db.Where("user_id = ?", userID).Order("id").Find(&data.Claims).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// Archive packs the data as data.json inside a zip archive.
+func (d *UserData) Archive() ([]byte, error) {
+	content, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+	file, err := archive.Create("data.json")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := file.Write(content); err != nil {
+		return nil, err
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
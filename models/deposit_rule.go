@@ -0,0 +1,182 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DepositRule sets the deposit of the reservations starting in a window of
+// the week of the restaurant, e.g. Friday and Saturday from 18:00, instead of
+// the deposit of its booking policy. A rule with MinReservations only applies
+// once the slot already has that many reservations.
+type DepositRule struct {
+	ID           uint   `gorm:"primaryKey" json:"id" example:"3"`
+	RestaurantID uint   `gorm:"index" json:"restaurantId" example:"7"`
+	Name         string `json:"name" example:"Weekend dinner"`
+	// Weekdays are the ISO days of the week the window starts on, 1 for Monday to 7 for Sunday, every day when empty
+	Weekdays []int `gorm:"serializer:json" json:"weekdays" example:"5,6"`
+	// StartTime and EndTime bound the window in the time zone of the restaurant, it may run past midnight
+	StartTime       string    `json:"startTime" example:"18:00"`
+	EndTime         string    `json:"endTime" example:"23:00"`
+	MinReservations int       `json:"minReservations" example:"0"`
+	Deposit         Money     `json:"deposit" gorm:"embedded;embeddedPrefix:deposit_"`
+	CreatedAt       time.Time `json:"-" swaggerignore:"true"`
+	UpdatedAt       time.Time `json:"-" swaggerignore:"true"`
+}
+
+// Validate checks the window and the deposit of the rule.
+func (r *DepositRule) Validate() error {
+	for _, day := range r.Weekdays {
+		if day < 1 || day > 7 {
+			return fmt.Errorf("weekdays must be between 1 (Monday) and 7 (Sunday)")
+		}
+	}
+	start, err := time.Parse("15:04", r.StartTime)
+	if err != nil {
+		return fmt.Errorf("start time must be in HH:MM format")
+	}
+	end, err := time.Parse("15:04", r.EndTime)
+	if err != nil {
+		return fmt.Errorf("end time must be in HH:MM format")
+	}
+	if start.Equal(end) {
+		return fmt.Errorf("start and end time cannot be the same")
+	}
+	if r.MinReservations < 0 {
+		return fmt.Errorf("minimum reservations cannot be negative")
+	}
+	return r.Deposit.Validate()
+}
+
+// Matches reports whether a reservation starting at the local time falls in
+// the window of the rule. The part of a window after midnight belongs to the
+// weekday the window started on.
+func (r *DepositRule) Matches(local time.Time) bool {
+	clock := local.Format("15:04")
+	day := local
+	if r.StartTime < r.EndTime {
+		if clock < r.StartTime || clock >= r.EndTime {
+			return false
+		}
+	} else if clock < r.EndTime {
+		day = local.AddDate(0, 0, -1)
+	} else if clock < r.StartTime {
+		return false
+	}
+
+	if len(r.Weekdays) == 0 {
+		return true
+	}
+	weekday := int(day.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+	for _, d := range r.Weekdays {
+		if d == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+type DepositRuleHandler struct {
+	db *gorm.DB
+}
+
+func NewDepositRuleHandler(db *gorm.DB) *DepositRuleHandler {
+	return &DepositRuleHandler{db}
+}
+
+func (h *DepositRuleHandler) GetDepositRules(restaurantID uint) ([]DepositRule, error) {
+	rules := []DepositRule{}
+	err := h.db.Where("restaurant_id = ?", restaurantID).Order("id").Find(&rules).Error
+	return rules, err
+}
+
+func (h *DepositRuleHandler) GetDepositRule(restaurantID, id uint) (*DepositRule, error) {
+	var rule DepositRule
+	err := h.db.Where("restaurant_id = ?", restaurantID).First(&rule, id).Error
+	return &rule, err
+}
+
+func (h *DepositRuleHandler) CreateDepositRule(rule *DepositRule) error {
+	return h.db.Create(rule).Error
+}
+
+func (h *DepositRuleHandler) UpdateDepositRule(rule *DepositRule) error {
+	return h.db.Save(rule).Error
+}
+
+func (h *DepositRuleHandler) DeleteDepositRule(rule *DepositRule) error {
+	return h.db.Delete(rule).Error
+}
+
+// DepositAt returns the deposit of a reservation at the restaurant starting
+// at the time, with the rule it was taken from. When several rules match the
+// highest deposit wins, when none does the deposit of the booking policy
+// applies and the rule is nil.
+func (h *DepositRuleHandler) DepositAt(restaurant *Restaurant, at time.Time) (Money, *DepositRule, error) {
+	rules, err := h.GetDepositRules(restaurant.ID)
+	if err != nil {
+		return Money{}, nil, err
+	}
+
+	local := at.In(restaurant.Location())
+	var matching []DepositRule
+	needsDemand := false
+	for _, rule := range rules {
+		if rule.Matches(local) {
+			matching = append(matching, rule)
+			needsDemand = needsDemand || rule.MinReservations > 0
+		}
+	}
+
+	booked := 0
+	if needsDemand {
+		if booked, err = h.reservationsInSlot(restaurant.ID, at); err != nil {
+			return Money{}, nil, err
+		}
+	}
+
+	deposit := restaurant.Deposit
+	var applied *DepositRule
+	for i := range matching {
+		rule := &matching[i]
+		if booked < rule.MinReservations {
+			continue
+		}
+		if applied == nil || rule.Deposit.Amount > deposit.Amount {
+			deposit = rule.Deposit
+			applied = rule
+		}
+	}
+	return deposit, applied, nil
+}
+
+// reservationsInSlot counts the pending and confirmed reservations of the
+// restaurant overlapping the slot starting at the time.
+func (h *DepositRuleHandler) reservationsInSlot(restaurantID uint, at time.Time) (int, error) {
+	var reservations []Reservation
+	if err := h.db.Select("date_time", "exit_time").
+		Where("restaurant_id = ? AND status IN ? AND date_time >= ? AND date_time < ?", restaurantID,
+			[]string{ReservationStatusPending, ReservationStatusConfirmed},
+			at.AddDate(0, 0, -1), at.Add(SlotLength)).
+		Find(&reservations).Error; err != nil {
+		return 0, err
+	}
+
+	end := at.Add(SlotLength)
+	count := 0
+	for _, reservation := range reservations {
+		exit := reservation.ExitTime
+		if !exit.After(reservation.DateTime) {
+			exit = reservation.DateTime.Add(SlotLength)
+		}
+		if reservation.DateTime.Before(end) && exit.After(at) {
+			count++
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,128 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrEmailTaken = fmt.Errorf("email already exists")
+var ErrEmailUnchanged = fmt.Errorf("email is already the email of the account")
+
+// EmailChange is a pending change of the email of a user. The change is only
+// applied once the token sent to the new address is confirmed. Only the
+// SHA-256 hash of the token is stored.
+type EmailChange struct {
+	ID        uint `gorm:"primaryKey"`
+	UserID    uint `gorm:"index"`
+	NewEmail  string
+	TokenHash string `gorm:"uniqueIndex"`
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+type EmailChangeHandler struct {
+	db *gorm.DB
+}
+
+func NewEmailChangeHandler(db *gorm.DB) *EmailChangeHandler {
+	return &EmailChangeHandler{db}
+}
+
+// emailTaken reports whether another user than the given one has the email.
+func emailTaken(tx *gorm.DB, email string, userID uint) (bool, error) {
+	var count int64
+	err := tx.Unscoped().Model(&User{}).Where("LOWER(email) = LOWER(?) AND id <> ?", email, userID).Count(&count).Error
+	return count > 0, err
+}
+
+// RequestChange issues a confirmation token for changing the email of the
+// user to newEmail and returns the raw value that should be delivered to the
+// new address. Earlier pending changes of the user are invalidated.
+func (h *EmailChangeHandler) RequestChange(user *User, newEmail string, ttl time.Duration) (string, error) {
+	newEmail = strings.TrimSpace(newEmail)
+	if strings.EqualFold(newEmail, user.Email) {
+		return "", ErrEmailUnchanged
+	}
+	taken, err := emailTaken(h.db, newEmail, user.ID)
+	if err != nil {
+		return "", err
+	}
+	if taken {
+		return "", ErrEmailTaken
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		return "", err
+	}
+
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Model(&EmailChange{}).Where("user_id = ? AND used_at IS NULL", user.ID).Update("used_at", &now).Error; err != nil {
+			return err
+		}
+		change := EmailChange{
+			UserID:    user.ID,
+			NewEmail:  newEmail,
+			TokenHash: hashToken(token),
+			ExpiresAt: now.Add(ttl),
+		}
+		return tx.Create(&change).Error
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// ConfirmChange consumes the token and applies the change of email. It
+// signs out every session of the user and returns the updated user and their
+// previous email.
+func (h *EmailChangeHandler) ConfirmChange(token string) (*User, string, error) {
+	var user User
+	var previousEmail string
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		var change EmailChange
+		if err := tx.Where("token_hash = ?", hashToken(token)).First(&change).Error; err != nil {
+			return fmt.Errorf("invalid confirmation link")
+		}
+
+		result := tx.Model(&EmailChange{}).
+			Where("id = ? AND used_at IS NULL AND expires_at > ?", change.ID, time.Now()).
+			Update("used_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("confirmation link has expired")
+		}
+
+		// The address may have been registered since the change was requested
+		taken, err := emailTaken(tx, change.NewEmail, change.UserID)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return ErrEmailTaken
+		}
+
+		if err := tx.First(&user, change.UserID).Error; err != nil {
+			return err
+		}
+		previousEmail = user.Email
+		user.Email = change.NewEmail
+		// Sessions signed in with the previous address are signed out
+		return tx.Model(&User{}).Where("id = ?", user.ID).Updates(map[string]interface{}{
+			"email":              change.NewEmail,
+			"tokens_valid_after": time.Now(),
+		}).Error
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return &user, previousEmail, nil
+}
@@ -0,0 +1,279 @@
+package models
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"text/template"
+	"time"
+
+	"github.com/punchanabu/redrice-backend-go/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	EmailTemplatePasswordReset      = "password_reset"
+	EmailTemplateMagicLink          = "magic_link"
+	EmailTemplateEmailChangeConfirm = "email_change_confirm"
+	EmailTemplateEmailChanged       = "email_changed"
+	EmailTemplateStaffInvitation    = "staff_invitation"
+	EmailTemplateDataExportReady    = "data_export_ready"
+	EmailTemplateReceipt            = "receipt"
+	EmailTemplateAnnouncement       = "restaurant_announcement"
+	EmailTemplateDailyDigest        = "daily_digest"
+	EmailTemplateSLAAlert           = "sla_alert"
+	EmailTemplateReservationStatus  = "reservation_status"
+)
+
+var ErrUnknownEmailTemplate = fmt.Errorf("unknown email template")
+var ErrInvalidEmailTemplate = fmt.Errorf("invalid email template")
+
+// EmailTemplate is the subject and body of an email sent by the platform.
+// Both are Go templates, e.g. "Hi {{.name}}", filled in with the variables of
+// the template. Templates never edited by an admin use the built-in copy.
+type EmailTemplate struct {
+	ID        uint      `gorm:"primaryKey" json:"-" swaggerignore:"true"`
+	Key       string    `gorm:"uniqueIndex" json:"key" example:"password_reset"`
+	Subject   string    `json:"subject" example:"Reset your RedRice password"`
+	Body      string    `json:"body" example:"Hi {{.name}}, ..."`
+	UpdatedBy *uint     `json:"updatedBy,omitempty" example:"1"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	// Variables are the names the subject and body can use
+	Variables []string `gorm:"-" json:"variables" example:"name,link"`
+	// Customized is set when the copy was edited and differs from the built-in one
+	Customized bool `gorm:"-" json:"customized"`
+}
+
+// RenderedEmail is a template filled in with its variables.
+type RenderedEmail struct {
+	Subject string `json:"subject" example:"Reset your RedRice password"`
+	Body    string `json:"body"`
+}
+
+type defaultEmailTemplate struct {
+	subject string
+	body    string
+	// sample holds a value for every variable, used to validate and preview the template
+	sample map[string]string
+}
+
+var defaultEmailTemplates = map[string]defaultEmailTemplate{
+	EmailTemplatePasswordReset: {
+		subject: "Reset your RedRice password",
+		body:    "Hi {{.name}},\n\nUse the link below to reset your RedRice password. The link expires in {{.minutes}} minutes and can only be used once.\n\n{{.link}}\n\nIf you did not request a password reset you can ignore this email.",
+		sample:  map[string]string{"name": "Somchai", "minutes": "60", "link": "https://redrice.app/reset-password?token=sample"},
+	},
+	EmailTemplateMagicLink: {
+		subject: "Your RedRice login link",
+		body:    "Hi {{.name}},\n\nUse the link below to sign in to RedRice. The link expires in {{.minutes}} minutes and can only be used once.\n\n{{.link}}\n\nIf you did not request this link you can ignore this email.",
+		sample:  map[string]string{"name": "Somchai", "minutes": "15", "link": "https://redrice.app/magic-link?token=sample"},
+	},
+	EmailTemplateEmailChangeConfirm: {
+		subject: "Confirm your new RedRice email",
+		body:    "Hi {{.name}},\n\nUse the link below to confirm {{.email}} as the new email of your RedRice account. The link expires in {{.hours}} hours and can only be used once.\n\n{{.link}}\n\nIf you did not ask for this change you can ignore this email.",
+		sample:  map[string]string{"name": "Somchai", "email": "somchai@example.com", "hours": "24", "link": "https://redrice.app/confirm-email?token=sample"},
+	},
+	EmailTemplateEmailChanged: {
+		subject: "Your RedRice email was changed",
+		body:    "Hi {{.name}},\n\nThe email of your RedRice account has been changed to {{.email}}. From now on sign in and receive emails with the new address.\n\nIf you did not make this change, reset your password and contact support right away.",
+		sample:  map[string]string{"name": "Somchai", "email": "somchai@example.com"},
+	},
+	EmailTemplateStaffInvitation: {
+		subject: "You're invited to join {{.restaurant}} on RedRice",
+		body:    "Hi,\n\nYou have been invited to join {{.restaurant}} on RedRice as staff. Use the link below to create your account. The link expires in {{.days}} days.\n\n{{.link}}",
+		sample:  map[string]string{"restaurant": "Baan Khanitha", "days": "7", "link": "https://redrice.app/accept-invitation?token=sample"},
+	},
+	EmailTemplateDataExportReady: {
+		subject: "Your RedRice data export is ready",
+		body:    "Hi {{.name}},\n\nThe copy of your RedRice data you asked for is ready. Download it from the app under your account settings within the next 7 days.\n",
+		sample:  map[string]string{"name": "Somchai"},
+	},
+	EmailTemplateReceipt: {
+		subject: "Your RedRice receipt {{.receiptNumber}}",
+		body:    "Hi {{.name}},\n\nThank you for dining at {{.restaurant}}. Your receipt {{.receiptNumber}} for the reservation on {{.date}} is available here for the next 7 days:\n\n{{.link}}\n",
+		sample:  map[string]string{"name": "Somchai", "restaurant": "Baan Khanitha", "receiptNumber": "RR-000042", "date": "2 Jan 2006 19:00", "link": "https://redrice.app/receipts/sample.pdf"},
+	},
+	EmailTemplateAnnouncement: {
+		subject: "{{.restaurant}}: {{.title}}",
+		body:    "Hi {{.name}},\n\n{{.restaurant}}, which you follow on RedRice, has news for you.\n\n{{.title}}\n{{.body}}\n",
+		sample:  map[string]string{"name": "Somchai", "restaurant": "Baan Khanitha", "title": "New lunch set", "body": "Try our new lunch set, available weekdays from 11:00."},
+	},
+	EmailTemplateDailyDigest: {
+		subject: "Your RedRice digest: {{.count}} updates",
+		body:    "Hi {{.name}},\n\nHere is what happened since your last digest.\n\n{{.items}}\n\nYou get this digest because you chose daily delivery in your notification settings.",
+		sample:  map[string]string{"name": "Somchai", "count": "2", "items": "Baan Khanitha: New lunch set\nTry our new lunch set, available weekdays from 11:00.\n\nYour RedRice data export is ready\nThe copy of your RedRice data you asked for is ready."},
+	},
+	EmailTemplateSLAAlert: {
+		subject: "{{.restaurant}} is over its {{.metric}} limit",
+		body:    "Hi {{.name}},\n\nOver the last {{.days}} days {{.restaurant}} {{.summary}}.\n\nYou get one email per breach, the alert is resolved once the restaurant is back within the limit. Open alerts are listed under GET /admin/sla-alerts.",
+		sample:  map[string]string{"name": "Somchai", "restaurant": "Baan Khanitha", "metric": "decline rate", "days": "7", "summary": "declined 45% of the reservations it answered, above the limit of 30%"},
+	},
+	EmailTemplateReservationStatus: {
+		subject: "Your reservation at {{.restaurant}} is {{.status}}",
+		body:    "Hi {{.name}},\n\nYour reservation at {{.restaurant}} on {{.date}} is now {{.status}}.\n",
+		sample:  map[string]string{"name": "Somchai", "restaurant": "Baan Khanitha", "date": "2 Jan 2006 19:00", "status": "confirmed"},
+	},
+}
+
+func (d defaultEmailTemplate) variables() []string {
+	variables := make([]string, 0, len(d.sample))
+	for name := range d.sample {
+		variables = append(variables, name)
+	}
+	sort.Strings(variables)
+	return variables
+}
+
+// RenderEmail fills in the subject and body templates. Using a variable that
+// is not in data is an error.
+func RenderEmail(subject, body string, data map[string]string) (*RenderedEmail, error) {
+	var rendered RenderedEmail
+	for _, part := range []struct {
+		name   string
+		source string
+		target *string
+	}{{"subject", subject, &rendered.Subject}, {"body", body, &rendered.Body}} {
+		tmpl, err := template.New(part.name).Option("missingkey=error").Parse(part.source)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidEmailTemplate, err)
+		}
+		var buffer bytes.Buffer
+		if err := tmpl.Execute(&buffer, data); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidEmailTemplate, err)
+		}
+		*part.target = buffer.String()
+	}
+	return &rendered, nil
+}
+
+type EmailTemplateHandler struct {
+	db *gorm.DB
+}
+
+func NewEmailTemplateHandler(db *gorm.DB) *EmailTemplateHandler {
+	return &EmailTemplateHandler{db}
+}
+
+// GetTemplate returns the current copy of the template, the edited one or
+// else the built-in one.
+func (h *EmailTemplateHandler) GetTemplate(key string) (*EmailTemplate, error) {
+	fallback, ok := defaultEmailTemplates[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownEmailTemplate, key)
+	}
+
+	var templates []EmailTemplate
+	if err := h.db.Where("key = ?", key).Limit(1).Find(&templates).Error; err != nil {
+		return nil, err
+	}
+	if len(templates) == 0 {
+		return &EmailTemplate{Key: key, Subject: fallback.subject, Body: fallback.body, Variables: fallback.variables()}, nil
+	}
+	tmpl := templates[0]
+	tmpl.Variables = fallback.variables()
+	tmpl.Customized = tmpl.Subject != fallback.subject || tmpl.Body != fallback.body
+	return &tmpl, nil
+}
+
+// GetTemplates returns the current copy of every template, ordered by key.
+func (h *EmailTemplateHandler) GetTemplates() ([]EmailTemplate, error) {
+	keys := make([]string, 0, len(defaultEmailTemplates))
+	for key := range defaultEmailTemplates {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	templates := make([]EmailTemplate, 0, len(keys))
+	for _, key := range keys {
+		tmpl, err := h.GetTemplate(key)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, *tmpl)
+	}
+	return templates, nil
+}
+
+// SaveTemplate replaces the copy of the template after checking that it
+// renders with the sample data.
+func (h *EmailTemplateHandler) SaveTemplate(key, subject, body string, updatedBy uint) (*EmailTemplate, error) {
+	fallback, ok := defaultEmailTemplates[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownEmailTemplate, key)
+	}
+	if _, err := RenderEmail(subject, body, fallback.sample); err != nil {
+		return nil, err
+	}
+
+	tmpl := EmailTemplate{Key: key, Subject: subject, Body: body, UpdatedBy: &updatedBy}
+	err := h.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"subject", "body", "updated_by", "updated_at"}),
+	}).Create(&tmpl).Error
+	if err != nil {
+		return nil, err
+	}
+	return h.GetTemplate(key)
+}
+
+// ResetTemplate drops the edited copy of the template so the built-in one is used again.
+func (h *EmailTemplateHandler) ResetTemplate(key string) (*EmailTemplate, error) {
+	if _, ok := defaultEmailTemplates[key]; !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownEmailTemplate, key)
+	}
+	if err := h.db.Where("key = ?", key).Delete(&EmailTemplate{}).Error; err != nil {
+		return nil, err
+	}
+	return h.GetTemplate(key)
+}
+
+// Preview renders the template with the sample data, overridden by data.
+// A non-empty subject or body is rendered instead of the current copy, so
+// drafts can be checked before they are saved.
+func (h *EmailTemplateHandler) Preview(key, subject, body string, data map[string]string) (*RenderedEmail, error) {
+	tmpl, err := h.GetTemplate(key)
+	if err != nil {
+		return nil, err
+	}
+	if subject == "" {
+		subject = tmpl.Subject
+	}
+	if body == "" {
+		body = tmpl.Body
+	}
+
+	values := map[string]string{}
+	for name, value := range defaultEmailTemplates[key].sample {
+		values[name] = value
+	}
+	for name, value := range data {
+		values[name] = value
+	}
+	return RenderEmail(subject, body, values)
+}
+
+// Render fills in the current copy of the template with data. An edited copy
+// that no longer renders falls back to the built-in one, so the email still
+// goes out.
+func (h *EmailTemplateHandler) Render(key string, data map[string]string) (*RenderedEmail, error) {
+	tmpl, err := h.GetTemplate(key)
+	if err != nil {
+		return nil, err
+	}
+	rendered, err := RenderEmail(tmpl.Subject, tmpl.Body, data)
+	if err != nil && tmpl.Customized {
+		fallback := defaultEmailTemplates[key]
+		rendered, err = RenderEmail(fallback.subject, fallback.body, data)
+	}
+	return rendered, err
+}
+
+// Send renders the current copy of the template with data and emails it.
+func (h *EmailTemplateHandler) Send(to, key string, data map[string]string) error {
+	rendered, err := h.Render(key, data)
+	if err != nil {
+		return err
+	}
+	return utils.SendEmail(to, rendered.Subject, rendered.Body)
+}
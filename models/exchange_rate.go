@@ -0,0 +1,119 @@
+package models
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// DisplayAmount is an amount converted to the currency a client asked for,
+// only meant to be shown. What guests pay stays in the stored currency.
+type DisplayAmount struct {
+	Amount   int64  `json:"amount" example:"550"`
+	Currency string `json:"currency" example:"USD"`
+	Text     string `json:"text" example:"USD 5.50"`
+	// Rate is the value of one major unit of the stored currency in the display currency
+	Rate float64 `json:"rate" example:"0.0275"`
+}
+
+// ExchangeRates caches the value of one unit of DefaultCurrency in the other
+// supported currencies. It is safe for concurrent use.
+type ExchangeRates struct {
+	mu        sync.RWMutex
+	rates     map[string]float64
+	updatedAt time.Time
+}
+
+func NewExchangeRates() *ExchangeRates {
+	return &ExchangeRates{rates: map[string]float64{DefaultCurrency: 1}}
+}
+
+// Set replaces the cached rates, keeping only the supported currencies.
+func (e *ExchangeRates) Set(rates map[string]float64, at time.Time) {
+	cached := map[string]float64{DefaultCurrency: 1}
+	for currency, rate := range rates {
+		if IsValidCurrency(currency) && rate > 0 {
+			cached[currency] = rate
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.rates = cached
+	e.updatedAt = at
+}
+
+// Rates returns a copy of the cached rates with the time they were set, zero
+// when they never were.
+func (e *ExchangeRates) Rates() (map[string]float64, time.Time) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	rates := make(map[string]float64, len(e.rates))
+	for currency, rate := range e.rates {
+		rates[currency] = rate
+	}
+	return rates, e.updatedAt
+}
+
+// Convert returns the amount in the currency, going through DefaultCurrency
+// when neither is, and false when a rate is missing.
+func (e *ExchangeRates) Convert(m Money, currency string) (DisplayAmount, bool) {
+	e.mu.RLock()
+	from, fromOK := e.rates[m.currency()]
+	to, toOK := e.rates[currency]
+	e.mu.RUnlock()
+	if !fromOK || !toOK {
+		return DisplayAmount{}, false
+	}
+
+	rate := to / from
+	major := float64(m.Amount) / math.Pow10(currencyDigits[m.currency()])
+	converted := Money{Amount: int64(math.Round(major * rate * math.Pow10(currencyDigits[currency]))), Currency: currency}
+	return DisplayAmount{Amount: converted.Amount, Currency: currency, Text: converted.String(), Rate: rate}, true
+}
+
+// Display sets the display amount of the money in the currency, leaving it
+// unset when the rate is missing or the currency is empty.
+func (e *ExchangeRates) Display(m *Money, currency string) {
+	if currency == "" {
+		return
+	}
+	if display, ok := e.Convert(*m, currency); ok {
+		m.Display = &display
+	}
+}
+
+// DisplayBreakdown sets the display amounts of every line of the breakdown.
+func (e *ExchangeRates) DisplayBreakdown(b *ChargeBreakdown, currency string) {
+	if b == nil {
+		return
+	}
+	e.Display(&b.Subtotal, currency)
+	e.Display(&b.ServiceCharge, currency)
+	e.Display(&b.VAT, currency)
+	e.Display(&b.Total, currency)
+}
+
+// DisplayMenu sets the display prices of the items of the menu.
+func (e *ExchangeRates) DisplayMenu(categories []MenuCategory, currency string) {
+	for i := range categories {
+		for j := range categories[i].Items {
+			e.Display(&categories[i].Items[j].Price, currency)
+		}
+	}
+}
+
+// DisplayRestaurant sets the display amounts of the deposit and the menu of
+// the restaurant.
+func (e *ExchangeRates) DisplayRestaurant(r *RestaurantResponse, currency string) {
+	e.Display(&r.Deposit, currency)
+	e.DisplayMenu(r.Menu, currency)
+}
+
+// DisplayReservation sets the display amounts of the deposit of the
+// reservation and of its restaurant.
+func (e *ExchangeRates) DisplayReservation(r *Reservation, currency string) {
+	e.Display(&r.Deposit, currency)
+	e.DisplayBreakdown(r.DepositBreakdown, currency)
+	e.Display(&r.Restaurant.Deposit, currency)
+}
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ExperimentVariant is one arm of an experiment. Weight is its share of the
+// users relative to the other variants.
+type ExperimentVariant struct {
+	Name   string `json:"name" example:"control"`
+	Weight int    `json:"weight" example:"50"`
+}
+
+// Experiment is an A/B test the apps read the variant of the user for.
+// Changing the variants or their weights moves users to other variants, start
+// a new experiment with another key instead.
+type Experiment struct {
+	Key         string              `json:"key" example:"search_results_layout"`
+	Description string              `json:"description"`
+	Variants    []ExperimentVariant `json:"variants"`
+	// Active experiments assign variants, stopped ones are kept for the analysis of their exposures
+	Active bool `json:"active"`
+}
+
+// Experiments is the registry of the experiments, the first variant is the control.
+var Experiments = []Experiment{
+	{
+		Key:         "search_results_layout",
+		Description: "Compact search result cards showing more restaurants per screen.",
+		Variants:    []ExperimentVariant{{Name: "control", Weight: 50}, {Name: "compact", Weight: 50}},
+		Active:      true,
+	},
+}
+
+// ExperimentAssignment is the variant of an experiment a user is in.
+type ExperimentAssignment struct {
+	Experiment string `json:"experiment" example:"search_results_layout"`
+	Variant    string `json:"variant" example:"compact"`
+}
+
+// ExperimentExposure records that a user was shown the variant of an
+// experiment. Exposures are kept once per user, experiment and day.
+type ExperimentExposure struct {
+	ID         uint      `gorm:"primaryKey" json:"-"`
+	UserID     uint      `gorm:"uniqueIndex:idx_experiment_exposures_daily" json:"userId"`
+	Experiment string    `gorm:"uniqueIndex:idx_experiment_exposures_daily;index" json:"experiment" example:"search_results_layout"`
+	Variant    string    `json:"variant" example:"compact"`
+	Day        string    `gorm:"uniqueIndex:idx_experiment_exposures_daily" json:"day" example:"2024-05-01"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// Assign picks the variant of the user from a hash of the experiment key and
+// the user ID, so a user always gets the same variant of an experiment
+// without storing it and experiments split users independently.
+func (e *Experiment) Assign(userID uint) string {
+	total := 0
+	for _, variant := range e.Variants {
+		total += variant.Weight
+	}
+	if total <= 0 {
+		return e.Variants[0].Name
+	}
+
+	sum := sha256.Sum256([]byte(e.Key + ":" + strconv.FormatUint(uint64(userID), 10)))
+	bucket := int(binary.BigEndian.Uint64(sum[:8]) % uint64(total))
+	for _, variant := range e.Variants {
+		if bucket < variant.Weight {
+			return variant.Name
+		}
+		bucket -= variant.Weight
+	}
+	return e.Variants[len(e.Variants)-1].Name
+}
+
+// AssignExperiments returns the variant of the user in every active experiment.
+func AssignExperiments(userID uint) []ExperimentAssignment {
+	assignments := []ExperimentAssignment{}
+	for i := range Experiments {
+		if Experiments[i].Active {
+			assignments = append(assignments, ExperimentAssignment{Experiment: Experiments[i].Key, Variant: Experiments[i].Assign(userID)})
+		}
+	}
+	return assignments
+}
+
+type ExperimentHandler struct {
+	db *gorm.DB
+}
+
+func NewExperimentHandler(db *gorm.DB) *ExperimentHandler {
+	return &ExperimentHandler{db}
+}
+
+// RecordExposures logs that the user was shown the assignments, at most once
+// per experiment and day.
+func (h *ExperimentHandler) RecordExposures(userID uint, assignments []ExperimentAssignment, now time.Time) error {
+	if len(assignments) == 0 {
+		return nil
+	}
+	exposures := make([]ExperimentExposure, len(assignments))
+	for i, assignment := range assignments {
+		exposures[i] = ExperimentExposure{
+			UserID:     userID,
+			Experiment: assignment.Experiment,
+			Variant:    assignment.Variant,
+			Day:        now.Format("2006-01-02"),
+		}
+	}
+	return h.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&exposures).Error
+}
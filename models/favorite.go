@@ -0,0 +1,102 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Favorite is a restaurant bookmarked by a user.
+type Favorite struct {
+	UserID       uint       `gorm:"primaryKey" json:"-" swaggerignore:"true"`
+	RestaurantID uint       `gorm:"primaryKey;index" json:"restaurantId"`
+	Restaurant   Restaurant `gorm:"constraint:OnDelete:CASCADE" json:"restaurant"`
+	CreatedAt    time.Time  `json:"createdAt"`
+}
+
+type FavoriteHandler struct {
+	db *gorm.DB
+}
+
+func NewFavoriteHandler(db *gorm.DB) *FavoriteHandler {
+	return &FavoriteHandler{db}
+}
+
+// AddFavorite bookmarks the restaurant for the user and returns its updated
+// favorite count. Adding a favorite twice has no effect.
+func (h *FavoriteHandler) AddFavorite(userID, restaurantID uint) (int64, error) {
+	var count int64
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		var restaurant Restaurant
+		if err := tx.First(&restaurant, restaurantID).Error; err != nil {
+			return err
+		}
+
+		favorite := Favorite{UserID: userID, RestaurantID: restaurantID}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&favorite)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			if err := tx.Model(&Restaurant{}).Where("id = ?", restaurantID).UpdateColumn("favorite_count", gorm.Expr("favorite_count + 1")).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&Restaurant{}).Where("id = ?", restaurantID).Pluck("favorite_count", &count).Error
+	})
+	return count, err
+}
+
+// RemoveFavorite removes the bookmark and returns the updated favorite count
+// of the restaurant. Removing a missing favorite has no effect.
+func (h *FavoriteHandler) RemoveFavorite(userID, restaurantID uint) (int64, error) {
+	var count int64
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		var restaurant Restaurant
+		if err := tx.First(&restaurant, restaurantID).Error; err != nil {
+			return err
+		}
+
+		result := tx.Where("user_id = ? AND restaurant_id = ?", userID, restaurantID).Delete(&Favorite{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			if err := tx.Model(&Restaurant{}).Where("id = ? AND favorite_count > 0", restaurantID).UpdateColumn("favorite_count", gorm.Expr("favorite_count - 1")).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&Restaurant{}).Where("id = ?", restaurantID).Pluck("favorite_count", &count).Error
+	})
+	return count, err
+}
+
+// GetFavorites returns a page of the favorites of the user with their
+// restaurant, most recent first, and the total number of favorites. Deleted
+// restaurants are left out.
+func (h *FavoriteHandler) GetFavorites(userID uint, page, limit int) ([]Favorite, int64, error) {
+	query := h.db.Model(&Favorite{}).Where("user_id = ?", userID).
+		Where("restaurant_id IN (?)", h.db.Model(&Restaurant{}).Select("id"))
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var favorites []Favorite
+	result := query.Preload("Restaurant").Order("created_at DESC").Offset((page - 1) * limit).Limit(limit).Find(&favorites)
+	return favorites, total, result.Error
+}
+
+// moveFavorites hands the favorites of the source user to the target user.
+// Restaurants both of them bookmarked lose the favorite of the source.
+func moveFavorites(tx *gorm.DB, targetID, sourceID uint) error {
+	if err := tx.Exec("UPDATE favorites SET user_id = ? WHERE user_id = ? AND restaurant_id NOT IN (SELECT restaurant_id FROM favorites WHERE user_id = ?)", targetID, sourceID, targetID).Error; err != nil {
+		return err
+	}
+	if err := tx.Exec("UPDATE restaurants SET favorite_count = favorite_count - 1 WHERE favorite_count > 0 AND id IN (SELECT restaurant_id FROM favorites WHERE user_id = ?)", sourceID).Error; err != nil {
+		return err
+	}
+	return tx.Where("user_id = ?", sourceID).Delete(&Favorite{}).Error
+}
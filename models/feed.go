@@ -0,0 +1,110 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	FeedPhotoAdded     = "photo.added"
+	FeedListingUpdated = "listing.updated"
+	FeedAnnouncement   = "announcement"
+)
+
+// Follow subscribes a user to the feed of a restaurant.
+type Follow struct {
+	UserID       uint       `gorm:"primaryKey" json:"-" swaggerignore:"true"`
+	RestaurantID uint       `gorm:"primaryKey;index" json:"restaurantId"`
+	Restaurant   Restaurant `gorm:"constraint:OnDelete:CASCADE" json:"-" swaggerignore:"true"`
+	CreatedAt    time.Time  `json:"createdAt"`
+}
+
+// FeedItem is something that happened at a restaurant, shown in the feed of
+// its followers.
+type FeedItem struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	RestaurantID uint       `gorm:"index" json:"restaurantId"`
+	Restaurant   Restaurant `gorm:"constraint:OnDelete:CASCADE" json:"restaurant"`
+	Type         string     `json:"type" example:"announcement" enums:"photo.added,listing.updated,announcement"`
+	Title        string     `json:"title" example:"New lunch set"`
+	Body         string     `json:"body,omitempty" example:"Try our new lunch set, available weekdays from 11:00."`
+	ImageURL     string     `json:"imageUrl,omitempty"`
+	// ChangedFields lists the listing fields of a listing.updated item
+	ChangedFields []string  `gorm:"serializer:json" json:"changedFields,omitempty"`
+	CreatedBy     uint      `json:"-" swaggerignore:"true"`
+	CreatedAt     time.Time `gorm:"index" json:"createdAt"`
+}
+
+type FeedHandler struct {
+	db *gorm.DB
+}
+
+func NewFeedHandler(db *gorm.DB) *FeedHandler {
+	return &FeedHandler{db}
+}
+
+// Follow subscribes the user to the restaurant. Following twice has no effect.
+func (h *FeedHandler) Follow(userID, restaurantID uint) error {
+	var restaurant Restaurant
+	if err := h.db.First(&restaurant, restaurantID).Error; err != nil {
+		return err
+	}
+	follow := Follow{UserID: userID, RestaurantID: restaurantID}
+	return h.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&follow).Error
+}
+
+func (h *FeedHandler) Unfollow(userID, restaurantID uint) error {
+	return h.db.Where("user_id = ? AND restaurant_id = ?", userID, restaurantID).Delete(&Follow{}).Error
+}
+
+// GetFollowers returns the users following the restaurant.
+func (h *FeedHandler) GetFollowers(restaurantID uint) ([]User, error) {
+	var users []User
+	result := h.db.Where("id IN (?)", h.db.Model(&Follow{}).Select("user_id").Where("restaurant_id = ?", restaurantID)).Find(&users)
+	return users, result.Error
+}
+
+func (h *FeedHandler) CreateItem(item *FeedItem) error {
+	return h.db.Create(item).Error
+}
+
+// GetFeed returns a page of the items of the restaurants the user follows,
+// newest first, and the total number of items.
+func (h *FeedHandler) GetFeed(userID uint, page, limit int) ([]FeedItem, int64, error) {
+	query := h.db.Model(&FeedItem{}).
+		Where("restaurant_id IN (?)", h.db.Model(&Follow{}).Select("restaurant_id").Where("user_id = ?", userID))
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var items []FeedItem
+	result := query.Preload("Restaurant").Order("created_at DESC, id DESC").Offset((page - 1) * limit).Limit(limit).Find(&items)
+	return items, total, result.Error
+}
+
+// addListingFeedItem tells the followers which listing fields changed.
+func addListingFeedItem(tx *gorm.DB, restaurantID uint, changedBy uint, changes []FieldChange) error {
+	fields := make([]string, len(changes))
+	for i, change := range changes {
+		fields[i] = change.Field
+	}
+	return tx.Create(&FeedItem{
+		RestaurantID:  restaurantID,
+		Type:          FeedListingUpdated,
+		Title:         "Listing updated",
+		ChangedFields: fields,
+		CreatedBy:     changedBy,
+	}).Error
+}
+
+// moveFollows hands the follows of the source user to the target user.
+func moveFollows(tx *gorm.DB, targetID, sourceID uint) error {
+	if err := tx.Exec("UPDATE follows SET user_id = ? WHERE user_id = ? AND restaurant_id NOT IN (SELECT restaurant_id FROM follows WHERE user_id = ?)", targetID, sourceID, targetID).Error; err != nil {
+		return err
+	}
+	return tx.Where("user_id = ?", sourceID).Delete(&Follow{}).Error
+}
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	IncidentInvestigating = "investigating"
+	IncidentIdentified    = "identified"
+	IncidentMonitoring    = "monitoring"
+	IncidentResolved      = "resolved"
+)
+
+var IncidentStatuses = []string{IncidentInvestigating, IncidentIdentified, IncidentMonitoring, IncidentResolved}
+
+const (
+	IncidentImpactMinor    = "minor"
+	IncidentImpactMajor    = "major"
+	IncidentImpactCritical = "critical"
+)
+
+var IncidentImpacts = []string{IncidentImpactMinor, IncidentImpactMajor, IncidentImpactCritical}
+
+// Incident is an entry of the public status page written by an admin.
+// Components names the affected components, e.g. database or email.
+type Incident struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	Title      string     `json:"title" example:"Emails are delayed"`
+	Message    string     `json:"message" example:"Our email provider is having issues, confirmations may arrive late."`
+	Status     string     `gorm:"index" json:"status" enums:"investigating,identified,monitoring,resolved"`
+	Impact     string     `json:"impact" enums:"minor,major,critical"`
+	Components []string   `gorm:"serializer:json" json:"components"`
+	CreatedBy  uint       `json:"-" swaggerignore:"true"`
+	StartedAt  time.Time  `json:"startedAt"`
+	ResolvedAt *time.Time `json:"resolvedAt"`
+	UpdatedAt  time.Time  `json:"updatedAt"`
+}
+
+func isOneOf(value string, values []string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func IsValidIncidentStatus(status string) bool {
+	return isOneOf(status, IncidentStatuses)
+}
+
+func IsValidIncidentImpact(impact string) bool {
+	return isOneOf(impact, IncidentImpacts)
+}
+
+type IncidentHandler struct {
+	db *gorm.DB
+}
+
+func NewIncidentHandler(db *gorm.DB) *IncidentHandler {
+	return &IncidentHandler{db}
+}
+
+// setResolvedAt keeps ResolvedAt in line with the status.
+func (i *Incident) setResolvedAt(now time.Time) {
+	if i.Status == IncidentResolved {
+		if i.ResolvedAt == nil {
+			i.ResolvedAt = &now
+		}
+	} else {
+		i.ResolvedAt = nil
+	}
+}
+
+func (h *IncidentHandler) CreateIncident(incident *Incident) error {
+	now := time.Now()
+	if incident.StartedAt.IsZero() {
+		incident.StartedAt = now
+	}
+	incident.setResolvedAt(now)
+	return h.db.Create(incident).Error
+}
+
+func (h *IncidentHandler) GetIncident(id uint) (*Incident, error) {
+	var incident Incident
+	result := h.db.First(&incident, id)
+	return &incident, result.Error
+}
+
+func (h *IncidentHandler) UpdateIncident(incident *Incident) error {
+	incident.setResolvedAt(time.Now())
+	return h.db.Save(incident).Error
+}
+
+func (h *IncidentHandler) DeleteIncident(id uint) error {
+	return h.db.Delete(&Incident{}, id).Error
+}
+
+// GetRecentIncidents returns the unresolved incidents and the ones resolved
+// since the given time, newest first.
+func (h *IncidentHandler) GetRecentIncidents(since time.Time) ([]Incident, error) {
+	var incidents []Incident
+	result := h.db.Where("resolved_at IS NULL OR resolved_at >= ?", since).Order("started_at DESC").Find(&incidents)
+	return incidents, result.Error
+}
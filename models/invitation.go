@@ -0,0 +1,99 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Invitation lets a restaurant owner or an admin bring a staff member onto a
+// restaurant. The invitee registers through the emailed link and the account
+// is attached to the restaurant. Only the SHA-256 hash of the token is stored.
+type Invitation struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	Email        string     `gorm:"index" json:"email"`
+	RestaurantID uint       `gorm:"index" json:"restaurantId"`
+	InvitedBy    uint       `json:"invitedBy"`
+	TokenHash    string     `gorm:"uniqueIndex" json:"-" swaggerignore:"true"`
+	ExpiresAt    time.Time  `json:"expiresAt"`
+	AcceptedAt   *time.Time `json:"acceptedAt"`
+	CreatedAt    time.Time  `json:"createdAt"`
+}
+
+type InvitationHandler struct {
+	db *gorm.DB
+}
+
+func NewInvitationHandler(db *gorm.DB) *InvitationHandler {
+	return &InvitationHandler{db}
+}
+
+// CreateInvitation stores a new invitation and returns the raw token that
+// should be delivered to the invitee. Emails that already belong to an
+// account or have a pending invitation for the restaurant are rejected.
+func (h *InvitationHandler) CreateInvitation(email string, restaurantID, invitedBy uint, ttl time.Duration) (*Invitation, string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	var users int64
+	if err := h.db.Model(&User{}).Where("LOWER(email) = ?", email).Count(&users).Error; err != nil {
+		return nil, "", err
+	}
+	if users > 0 {
+		return nil, "", fmt.Errorf("a user with this email already exists")
+	}
+
+	var pending int64
+	if err := h.db.Model(&Invitation{}).
+		Where("email = ? AND restaurant_id = ? AND accepted_at IS NULL AND expires_at > ?", email, restaurantID, time.Now()).
+		Count(&pending).Error; err != nil {
+		return nil, "", err
+	}
+	if pending > 0 {
+		return nil, "", fmt.Errorf("email already has a pending invitation")
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	invitation := Invitation{
+		Email:        email,
+		RestaurantID: restaurantID,
+		InvitedBy:    invitedBy,
+		TokenHash:    hashToken(token),
+		ExpiresAt:    time.Now().Add(ttl),
+	}
+	if err := h.db.Create(&invitation).Error; err != nil {
+		return nil, "", err
+	}
+
+	return &invitation, token, nil
+}
+
+// AcceptInvitation consumes the token and creates the account of the invitee
+// with the email and restaurant of the invitation.
+func (h *InvitationHandler) AcceptInvitation(token string, user *User) error {
+	return h.db.Transaction(func(tx *gorm.DB) error {
+		var invitation Invitation
+		if err := tx.Where("token_hash = ?", hashToken(token)).First(&invitation).Error; err != nil {
+			return fmt.Errorf("invalid invitation token")
+		}
+
+		if invitation.AcceptedAt != nil || time.Now().After(invitation.ExpiresAt) {
+			return fmt.Errorf("invitation has expired")
+		}
+
+		user.Email = invitation.Email
+		user.RestaurantId = invitation.RestaurantID
+		user.Role = RoleOwner
+		if err := NewUserHandler(tx).CreateUser(user); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		return tx.Model(&invitation).Update("accepted_at", &now).Error
+	})
+}
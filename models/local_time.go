@@ -0,0 +1,79 @@
+package models
+
+import (
+	"sync"
+	"time"
+
+	// Restaurants name their zone, which distroless images have no database for
+	_ "time/tzdata"
+)
+
+// DefaultTimezone is used by restaurants that did not set one.
+const DefaultTimezone = "Asia/Bangkok"
+
+var locations sync.Map
+
+// loadLocation caches time zones, they are looked up for every reservation read.
+func loadLocation(name string) (*time.Location, error) {
+	if location, ok := locations.Load(name); ok {
+		return location.(*time.Location), nil
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	locations.Store(name, location)
+	return location, nil
+}
+
+// IsValidTimezone reports whether name is an IANA time zone such as Asia/Bangkok.
+func IsValidTimezone(name string) bool {
+	if name == "" || name == "Local" {
+		return false
+	}
+	_, err := loadLocation(name)
+	return err == nil
+}
+
+// Location returns the time zone of the restaurant.
+func (r *Restaurant) Location() *time.Location {
+	if r.Timezone != "" {
+		if location, err := loadLocation(r.Timezone); err == nil {
+			return location
+		}
+	}
+	location, _ := loadLocation(DefaultTimezone)
+	return location
+}
+
+// LocalTimes are the times of a reservation in the time zone of the restaurant,
+// preformatted so every client shows them the same way.
+type LocalTimes struct {
+	Timezone string `json:"timezone" example:"Asia/Bangkok"`
+	// DateTime and ExitTime are ISO-8601 with the offset of the restaurant
+	DateTime string `json:"dateTime" example:"2024-12-02T19:00:00+07:00"`
+	ExitTime string `json:"exitTime" example:"2024-12-02T21:00:00+07:00"`
+	// Display holds a ready to show string per supported language
+	Display map[string]string `json:"display" example:"en:Mon 2 Dec 2024, 19:00–21:00,th:จ. 2 ธ.ค. 2567 19:00–21:00"`
+	// Formatted is set to the visit written for the Accept-Language of the client
+	Formatted string `json:"formatted,omitempty" example:"Mon 2 Dec 2567, 19:00–21:00"`
+}
+
+// NewLocalTimes converts the times of a visit to the time zone of the restaurant.
+func NewLocalTimes(restaurant *Restaurant, start, end time.Time) *LocalTimes {
+	location := restaurant.Location()
+	start, end = start.In(location), end.In(location)
+
+	times := &LocalTimes{
+		Timezone: location.String(),
+		DateTime: start.Format(time.RFC3339),
+		Display:  map[string]string{},
+	}
+	if !end.IsZero() {
+		times.ExitTime = end.Format(time.RFC3339)
+	}
+	for _, language := range Languages {
+		times.Display[language] = DefaultLocale(language).FormatVisit(start, end)
+	}
+	return times
+}
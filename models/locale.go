@@ -0,0 +1,168 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// buddhistEraOffset is added to the Gregorian year to get the Buddhist era
+// year used in Thailand.
+const buddhistEraOffset = 543
+
+var thaiWeekdays = [...]string{"อา.", "จ.", "อ.", "พ.", "พฤ.", "ศ.", "ส."}
+
+var thaiMonths = [...]string{"ม.ค.", "ก.พ.", "มี.ค.", "เม.ย.", "พ.ค.", "มิ.ย.", "ก.ค.", "ส.ค.", "ก.ย.", "ต.ค.", "พ.ย.", "ธ.ค."}
+
+// Locale is how dates and numbers are written for a client, in one of
+// Languages optionally counting years in the Buddhist era.
+type Locale struct {
+	Language    string
+	BuddhistEra bool
+}
+
+// DefaultLocale is the usual locale of the language, Thai dates use the
+// Buddhist era.
+func DefaultLocale(language string) Locale {
+	return Locale{Language: language, BuddhistEra: language == "th"}
+}
+
+// ParseAcceptLanguage picks the supported language the client prefers most
+// from an Accept-Language header, e.g. "th-TH,th;q=0.9,en;q=0.8", and false
+// when it names none. The calendar extension of BCP 47 chooses the era, e.g.
+// "en-u-ca-buddhist" or "th-u-ca-gregory".
+func ParseAcceptLanguage(header string) (Locale, bool) {
+	type candidate struct {
+		tag     string
+		quality float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		quality := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				quality = parsed
+			}
+		}
+		if tag != "" && quality > 0 {
+			candidates = append(candidates, candidate{strings.ToLower(tag), quality})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].quality > candidates[j].quality })
+
+	for _, c := range candidates {
+		language, _, _ := strings.Cut(c.tag, "-")
+		if !isOneOf(language, Languages) {
+			continue
+		}
+		locale := DefaultLocale(language)
+		if strings.Contains(c.tag, "-u-ca-buddhist") {
+			locale.BuddhistEra = true
+		} else if strings.Contains(c.tag, "-u-ca-gregory") {
+			locale.BuddhistEra = false
+		}
+		return locale, true
+	}
+	return Locale{}, false
+}
+
+func (l Locale) year(t time.Time) int {
+	if l.BuddhistEra {
+		return t.Year() + buddhistEraOffset
+	}
+	return t.Year()
+}
+
+// FormatDate writes the day with its weekday, e.g. "Mon 2 Dec 2024" or
+// "จ. 2 ธ.ค. 2567".
+func (l Locale) FormatDate(t time.Time) string {
+	if l.Language == "th" {
+		return fmt.Sprintf("%s %d %s %d", thaiWeekdays[t.Weekday()], t.Day(), thaiMonths[t.Month()-1], l.year(t))
+	}
+	return fmt.Sprintf("%s %d", t.Format("Mon 2 Jan"), l.year(t))
+}
+
+// FormatDateTime writes the day and the time of the day, e.g.
+// "Mon 2 Dec 2024, 19:00" or "จ. 2 ธ.ค. 2567 19:00".
+func (l Locale) FormatDateTime(t time.Time) string {
+	if l.Language == "th" {
+		return l.FormatDate(t) + " " + t.Format("15:04")
+	}
+	return l.FormatDate(t) + ", " + t.Format("15:04")
+}
+
+// FormatVisit writes the start and end of a visit, the end only shows the
+// time when the visit ends the same day.
+func (l Locale) FormatVisit(start, end time.Time) string {
+	if end.IsZero() || !end.After(start) {
+		return l.FormatDateTime(start)
+	}
+	if end.YearDay() == start.YearDay() && end.Year() == start.Year() {
+		return l.FormatDateTime(start) + "–" + end.Format("15:04")
+	}
+	return l.FormatDateTime(start) + " – " + l.FormatDateTime(end)
+}
+
+// FormatNumber writes the number rounded to the decimals with thousands
+// separators, e.g. "1,234.50". Thai and English share the separators.
+func (l Locale) FormatNumber(value float64, decimals int) string {
+	sign := ""
+	if value < 0 {
+		sign, value = "-", -value
+	}
+	text := strconv.FormatFloat(value, 'f', decimals, 64)
+	whole, fraction, _ := strings.Cut(text, ".")
+	if fraction != "" {
+		return sign + groupThousands(whole) + "." + fraction
+	}
+	return sign + groupThousands(whole)
+}
+
+// FormatMoney writes the amount for the language, baht in Thai is written
+// after the amount, e.g. "1,234.50 บาท".
+func (l Locale) FormatMoney(m Money) string {
+	if l.Language == "th" && m.currency() == "THB" {
+		digits := currencyDigits["THB"]
+		return l.FormatNumber(float64(m.Amount)/math.Pow10(digits), digits) + " บาท"
+	}
+	return m.String()
+}
+
+// Localize sets the formatted text of the money.
+func (l Locale) Localize(m *Money) {
+	m.Formatted = l.FormatMoney(*m)
+}
+
+// LocalizeMenu sets the formatted prices of the items of the menu.
+func (l Locale) LocalizeMenu(categories []MenuCategory) {
+	for i := range categories {
+		for j := range categories[i].Items {
+			l.Localize(&categories[i].Items[j].Price)
+		}
+	}
+}
+
+// LocalizeRestaurant sets the formatted deposit and menu of the restaurant.
+func (l Locale) LocalizeRestaurant(r *RestaurantResponse) {
+	l.Localize(&r.Deposit)
+	l.LocalizeMenu(r.Menu)
+}
+
+// LocalizeReservation sets the formatted deposit of the reservation and, when
+// its restaurant is loaded, the formatted time of the visit.
+func (l Locale) LocalizeReservation(r *Reservation) {
+	l.Localize(&r.Deposit)
+	if b := r.DepositBreakdown; b != nil {
+		for _, m := range []*Money{&b.Subtotal, &b.ServiceCharge, &b.VAT, &b.Total} {
+			l.Localize(m)
+		}
+	}
+	if r.Local != nil {
+		location := r.Restaurant.Location()
+		r.Local.Formatted = l.FormatVisit(r.DateTime.In(location), r.ExitTime.In(location))
+	}
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MagicLinkToken is a single-use, short-lived login token sent by email. Only
+// the SHA-256 hash of the token is stored.
+type MagicLinkToken struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"index"`
+	TokenHash string `gorm:"uniqueIndex"`
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+type MagicLinkHandler struct {
+	db *gorm.DB
+}
+
+func NewMagicLinkHandler(db *gorm.DB) *MagicLinkHandler {
+	return &MagicLinkHandler{db}
+}
+
+// CreateToken issues a new login token for the user and returns the raw value
+// that should be delivered to them.
+func (h *MagicLinkHandler) CreateToken(userID uint, ttl time.Duration) (string, error) {
+	token, err := generateToken()
+	if err != nil {
+		return "", err
+	}
+
+	magicLink := MagicLinkToken{
+		UserID:    userID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := h.db.Create(&magicLink).Error; err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// ConsumeToken marks the token as used and returns its owner. The update is
+// conditional so a token cannot be used twice by concurrent requests.
+func (h *MagicLinkHandler) ConsumeToken(token string) (*User, error) {
+	var magicLink MagicLinkToken
+	if err := h.db.Where("token_hash = ?", hashToken(token)).First(&magicLink).Error; err != nil {
+		return nil, fmt.Errorf("invalid login link")
+	}
+
+	result := h.db.Model(&MagicLinkToken{}).
+		Where("id = ? AND used_at IS NULL AND expires_at > ?", magicLink.ID, time.Now()).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("login link has expired")
+	}
+
+	return NewUserHandler(h.db).GetUser(magicLink.UserID)
+}
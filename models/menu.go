@@ -0,0 +1,152 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrUnknownMenuCategory = fmt.Errorf("menu category not found in the menu of the restaurant")
+
+// MenuCategory is a section of the menu of a restaurant, e.g. "Starters".
+type MenuCategory struct {
+	ID           uint   `gorm:"primaryKey" json:"id" example:"4"`
+	RestaurantID uint   `gorm:"index" json:"restaurantId" example:"7"`
+	Name         string `json:"name" example:"Curries"`
+	// Position orders the categories of the menu, lowest first
+	Position  int        `json:"position" example:"0"`
+	Items     []MenuItem `gorm:"-" json:"items"`
+	CreatedAt time.Time  `json:"-" swaggerignore:"true"`
+	UpdatedAt time.Time  `json:"-" swaggerignore:"true"`
+}
+
+// MenuItem is a dish or drink listed under a category of the menu.
+type MenuItem struct {
+	ID           uint   `gorm:"primaryKey" json:"id" example:"12"`
+	RestaurantID uint   `gorm:"index" json:"restaurantId" example:"7"`
+	CategoryID   uint   `gorm:"index" json:"categoryId" example:"4"`
+	Name         string `json:"name" example:"Green curry with chicken"`
+	Description  string `json:"description" example:"Spicy, with Thai basil and eggplant"`
+	Price        Money  `json:"price" gorm:"embedded;embeddedPrefix:price_"`
+	ImageURL     string `json:"imageUrl"`
+	// Unavailable items stay on the menu marked as sold out
+	Available bool `json:"available"`
+	// Position orders the items of the category, lowest first
+	Position  int       `json:"position" example:"0"`
+	CreatedAt time.Time `json:"-" swaggerignore:"true"`
+	UpdatedAt time.Time `json:"-" swaggerignore:"true"`
+}
+
+type MenuHandler struct {
+	db *gorm.DB
+}
+
+func NewMenuHandler(db *gorm.DB) *MenuHandler {
+	return &MenuHandler{db}
+}
+
+// GetMenu returns the categories of the menu of the restaurant with their
+// items, both in their order.
+func (h *MenuHandler) GetMenu(restaurantID uint) ([]MenuCategory, error) {
+	categories := []MenuCategory{}
+	if err := h.db.Where("restaurant_id = ?", restaurantID).Order("position, id").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	var items []MenuItem
+	if err := h.db.Where("restaurant_id = ?", restaurantID).Order("position, id").Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	byCategory := map[uint][]MenuItem{}
+	for _, item := range items {
+		byCategory[item.CategoryID] = append(byCategory[item.CategoryID], item)
+	}
+	for i := range categories {
+		categories[i].Items = byCategory[categories[i].ID]
+		if categories[i].Items == nil {
+			categories[i].Items = []MenuItem{}
+		}
+	}
+	return categories, nil
+}
+
+// CreateMenuCategory adds the category at the end of the menu.
+func (h *MenuHandler) CreateMenuCategory(category *MenuCategory) error {
+	return h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&MenuCategory{}).Select("COALESCE(MAX(position) + 1, 0)").
+			Where("restaurant_id = ?", category.RestaurantID).Scan(&category.Position).Error; err != nil {
+			return err
+		}
+		return tx.Create(category).Error
+	})
+}
+
+func (h *MenuHandler) GetMenuCategory(restaurantID, id uint) (*MenuCategory, error) {
+	var category MenuCategory
+	err := h.db.Where("restaurant_id = ?", restaurantID).First(&category, id).Error
+	return &category, err
+}
+
+func (h *MenuHandler) UpdateMenuCategory(category *MenuCategory) error {
+	return h.db.Save(category).Error
+}
+
+// DeleteMenuCategory removes the category with the items listed under it.
+func (h *MenuHandler) DeleteMenuCategory(category *MenuCategory) error {
+	return h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("category_id = ?", category.ID).Delete(&MenuItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(category).Error
+	})
+}
+
+// CreateMenuItem adds the item at the end of its category, which must belong
+// to the menu of the same restaurant.
+func (h *MenuHandler) CreateMenuItem(item *MenuItem) error {
+	return h.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkMenuCategory(tx, item); err != nil {
+			return err
+		}
+		if err := tx.Model(&MenuItem{}).Select("COALESCE(MAX(position) + 1, 0)").
+			Where("category_id = ?", item.CategoryID).Scan(&item.Position).Error; err != nil {
+			return err
+		}
+		return tx.Create(item).Error
+	})
+}
+
+func (h *MenuHandler) GetMenuItem(restaurantID, id uint) (*MenuItem, error) {
+	var item MenuItem
+	err := h.db.Where("restaurant_id = ?", restaurantID).First(&item, id).Error
+	return &item, err
+}
+
+// UpdateMenuItem saves the item, which may move to another category of the
+// menu of the restaurant.
+func (h *MenuHandler) UpdateMenuItem(item *MenuItem) error {
+	return h.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkMenuCategory(tx, item); err != nil {
+			return err
+		}
+		return tx.Save(item).Error
+	})
+}
+
+func (h *MenuHandler) DeleteMenuItem(item *MenuItem) error {
+	return h.db.Delete(item).Error
+}
+
+// checkMenuCategory fails with ErrUnknownMenuCategory unless the category of
+// the item belongs to the menu of its restaurant.
+func checkMenuCategory(tx *gorm.DB, item *MenuItem) error {
+	var count int64
+	if err := tx.Model(&MenuCategory{}).Where("id = ? AND restaurant_id = ?", item.CategoryID, item.RestaurantID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrUnknownMenuCategory
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency is used for amounts that were stored without a currency.
+const DefaultCurrency = "THB"
+
+// currencyDigits lists the supported ISO 4217 currencies with the number of
+// digits of their minor unit.
+var currencyDigits = map[string]int{
+	"THB": 2,
+	"USD": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"SGD": 2,
+	"MYR": 2,
+	"HKD": 2,
+	"CNY": 2,
+	"JPY": 0,
+	"KRW": 0,
+	"VND": 0,
+}
+
+// Money is an amount in the minor unit of its currency, e.g. satang for THB,
+// so sums and splits never lose a fraction to floating point.
+type Money struct {
+	Amount   int64  `json:"amount" gorm:"column:minor;default:0" example:"20000"`
+	Currency string `json:"currency" gorm:"size:3;default:THB" example:"THB"`
+	// Display is set when the client asked for the amount in another currency
+	Display *DisplayAmount `json:"display,omitempty" gorm:"-"`
+	// Formatted is set to the amount written for the language of the client
+	Formatted string `json:"formatted,omitempty" gorm:"-" example:"200.00 บาท"`
+}
+
+var ErrCurrencyMismatch = fmt.Errorf("amounts are in different currencies")
+
+func NewMoney(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+func IsValidCurrency(currency string) bool {
+	_, ok := currencyDigits[currency]
+	return ok
+}
+
+// Validate checks that the currency is supported and the amount not negative.
+func (m Money) Validate() error {
+	if !IsValidCurrency(m.Currency) {
+		return fmt.Errorf("unsupported currency %q", m.Currency)
+	}
+	if m.Amount < 0 {
+		return fmt.Errorf("amount cannot be negative")
+	}
+	return nil
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) currency() string {
+	if m.Currency == "" {
+		return DefaultCurrency
+	}
+	return m.Currency
+}
+
+// Add returns the sum of both amounts, which must share the currency.
+func (m Money) Add(other Money) (Money, error) {
+	if m.currency() != other.currency() {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.currency(), other.currency())
+	}
+	return Money{Amount: m.Amount + other.Amount, Currency: m.currency()}, nil
+}
+
+// Sub returns the difference of both amounts, which must share the currency.
+func (m Money) Sub(other Money) (Money, error) {
+	if m.currency() != other.currency() {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.currency(), other.currency())
+	}
+	return Money{Amount: m.Amount - other.Amount, Currency: m.currency()}, nil
+}
+
+// Mul scales the amount by the factor, rounding half away from zero to the
+// minor unit.
+func (m Money) Mul(factor float64) Money {
+	return Money{Amount: int64(math.Round(float64(m.Amount) * factor)), Currency: m.currency()}
+}
+
+// Major returns the amount in the major unit, for display only.
+func (m Money) Major() float64 {
+	return float64(m.Amount) / math.Pow10(currencyDigits[m.currency()])
+}
+
+// String formats the amount with its currency code and thousands separators,
+// e.g. "THB 1,234.50".
+func (m Money) String() string {
+	digits := currencyDigits[m.currency()]
+	amount := m.Amount
+	sign := ""
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+
+	unit := int64(math.Pow10(digits))
+	grouped := groupThousands(strconv.FormatInt(amount/unit, 10))
+
+	if digits == 0 {
+		return fmt.Sprintf("%s %s%s", m.currency(), sign, grouped)
+	}
+	return fmt.Sprintf("%s %s%s.%0*d", m.currency(), sign, grouped, digits, amount%unit)
+}
+
+// groupThousands puts a comma between every group of three digits of the
+// whole number.
+func groupThousands(whole string) string {
+	var grouped strings.Builder
+	for i, r := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			grouped.WriteByte(',')
+		}
+		grouped.WriteRune(r)
+	}
+	return grouped.String()
+}
@@ -0,0 +1,178 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	NotificationChannelEmail = "email"
+	NotificationChannelSMS   = "sms"
+)
+
+// NotificationChannels lists the channels notifications are delivered over.
+var NotificationChannels = []string{NotificationChannelEmail, NotificationChannelSMS}
+
+const (
+	NotificationDeliveryImmediate   = "immediate"
+	NotificationDeliveryDailyDigest = "daily_digest"
+)
+
+// NotificationDeliveries lists how a user can have the notifications of a channel delivered.
+var NotificationDeliveries = []string{NotificationDeliveryImmediate, NotificationDeliveryDailyDigest}
+
+// Notification is a rendered message for a user. Notifications of users who
+// chose the daily digest for the channel wait with SentAt unset until they
+// are sent together.
+type Notification struct {
+	ID       uint   `gorm:"primaryKey"`
+	UserID   uint   `gorm:"index:idx_notifications_pending"`
+	Channel  string `gorm:"index:idx_notifications_pending"`
+	Template string
+	Subject  string
+	Body     string
+	// Purpose is the consent the notification needed, empty for service notifications
+	Purpose   string
+	SentAt    *time.Time `gorm:"index:idx_notifications_pending"`
+	CreatedAt time.Time
+	// SuppressedCount is how many identical notifications were dropped as duplicates of this one
+	SuppressedCount int64 `gorm:"not null;default:0"`
+}
+
+// NotificationClaim holds the key of a notification for as long as
+// identical ones are dropped. The key is unique, so of two identical
+// notifications created at the same time only one claims it.
+type NotificationClaim struct {
+	// Key is the SHA-256 of the user, channel, template and rendered message
+	Key            string `gorm:"primaryKey"`
+	NotificationID uint
+	ClaimedAt      time.Time
+}
+
+// NotificationStats counts the notifications of a channel over a period.
+type NotificationStats struct {
+	Channel string `json:"channel" example:"email"`
+	// Sent were delivered right away or with a digest
+	Sent int64 `json:"sent" example:"120"`
+	// Queued wait for the next digest of the user
+	Queued int64 `json:"queued" example:"8"`
+	// Suppressed were identical to one sent shortly before and dropped
+	Suppressed int64 `json:"suppressed" example:"15"`
+}
+
+type NotificationHandler struct {
+	db *gorm.DB
+}
+
+func NewNotificationHandler(db *gorm.DB) *NotificationHandler {
+	return &NotificationHandler{db}
+}
+
+func notificationKey(notification *Notification) string {
+	return hashToken(fmt.Sprintf("%d\x00%s\x00%s\x00%s\x00%s",
+		notification.UserID, notification.Channel, notification.Template, notification.Subject, notification.Body))
+}
+
+// ClaimNotification creates the notification unless an identical one claimed
+// its key since the time, in which case it is counted as suppressed on that
+// notification and false is returned.
+func (h *NotificationHandler) ClaimNotification(notification *Notification, since time.Time) (bool, error) {
+	claimed := false
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		claim := NotificationClaim{Key: notificationKey(notification), ClaimedAt: time.Now()}
+		result := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "key"}},
+			DoUpdates: clause.AssignmentColumns([]string{"notification_id", "claimed_at"}),
+			Where:     clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "notification_claims.claimed_at < ?", Vars: []interface{}{since}}}},
+		}).Create(&claim)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			held := tx.Model(&NotificationClaim{}).Select("notification_id").Where("key = ?", claim.Key)
+			return tx.Model(&Notification{}).Where("id = (?)", held).
+				UpdateColumn("suppressed_count", gorm.Expr("suppressed_count + 1")).Error
+		}
+
+		if err := tx.Create(notification).Error; err != nil {
+			return err
+		}
+		claimed = true
+		return tx.Model(&claim).Update("notification_id", notification.ID).Error
+	})
+	return claimed && err == nil, err
+}
+
+// ReleaseNotification deletes a claimed notification that could not be
+// delivered, so the same notification can be sent again.
+func (h *NotificationHandler) ReleaseNotification(notification *Notification) error {
+	return h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("notification_id = ?", notification.ID).Delete(&NotificationClaim{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&Notification{}, notification.ID).Error
+	})
+}
+
+// DeleteExpiredClaims deletes the claims made before the time, identical
+// notifications are no longer dropped by then.
+func (h *NotificationHandler) DeleteExpiredClaims(before time.Time) error {
+	return h.db.Where("claimed_at < ?", before).Delete(&NotificationClaim{}).Error
+}
+
+// GetStats counts the notifications created in [from, to) per channel.
+func (h *NotificationHandler) GetStats(from, to time.Time) ([]NotificationStats, error) {
+	var rows []NotificationStats
+	err := h.db.Model(&Notification{}).
+		Select("channel, COUNT(sent_at) AS sent, COUNT(*) - COUNT(sent_at) AS queued, COALESCE(SUM(suppressed_count), 0) AS suppressed").
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Group("channel").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	byChannel := map[string]NotificationStats{}
+	for _, row := range rows {
+		byChannel[row.Channel] = row
+	}
+	stats := make([]NotificationStats, len(NotificationChannels))
+	for i, channel := range NotificationChannels {
+		stats[i] = byChannel[channel]
+		stats[i].Channel = channel
+	}
+	return stats, nil
+}
+
+// GetDueDigests returns the pending notifications of the channel, grouped by
+// user, for the users whose oldest pending notification was queued before
+// cutoff. Notifications are ordered oldest first.
+func (h *NotificationHandler) GetDueDigests(channel string, cutoff time.Time) (map[uint][]Notification, error) {
+	due := h.db.Model(&Notification{}).Select("user_id").
+		Where("channel = ? AND sent_at IS NULL", channel).
+		Group("user_id").Having("MIN(created_at) <= ?", cutoff)
+
+	var notifications []Notification
+	err := h.db.Where("channel = ? AND sent_at IS NULL AND user_id IN (?)", channel, due).
+		Order("user_id, created_at, id").Find(&notifications).Error
+	if err != nil {
+		return nil, err
+	}
+
+	digests := map[uint][]Notification{}
+	for _, notification := range notifications {
+		digests[notification.UserID] = append(digests[notification.UserID], notification)
+	}
+	return digests, nil
+}
+
+// MarkSent records that the notifications were delivered.
+func (h *NotificationHandler) MarkSent(notifications []Notification, sentAt time.Time) error {
+	ids := make([]uint, len(notifications))
+	for i := range notifications {
+		ids[i] = notifications[i].ID
+	}
+	return h.db.Model(&Notification{}).Where("id IN ?", ids).Update("sent_at", sentAt).Error
+}
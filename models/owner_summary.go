@@ -0,0 +1,110 @@
+package models
+
+import (
+	"time"
+)
+
+// recentReviewsLimit is how many of the latest reviews the owner summary shows.
+const recentReviewsLimit = 5
+
+// RestaurantSummary is the state of one restaurant on the owner dashboard.
+type RestaurantSummary struct {
+	RestaurantID uint   `json:"restaurantId" example:"3"`
+	Name         string `json:"name" example:"RedRice"`
+	// TodayReservations counts the pending and confirmed reservations of the local day of the restaurant
+	TodayReservations int64 `json:"todayReservations" example:"12"`
+	// PendingConfirmations counts the upcoming reservations waiting for a confirmation
+	PendingConfirmations int64             `json:"pendingConfirmations" example:"3"`
+	RecentReviews        []CommentResponse `json:"recentReviews"`
+}
+
+// OwnerSummary aggregates the restaurants a user manages.
+type OwnerSummary struct {
+	TodayReservations    int64               `json:"todayReservations" example:"12"`
+	PendingConfirmations int64               `json:"pendingConfirmations" example:"3"`
+	Restaurants          []RestaurantSummary `json:"restaurants"`
+}
+
+type restaurantCount struct {
+	RestaurantID uint
+	Count        int64
+}
+
+// GetOwnerSummary summarizes the given restaurants, or every restaurant when
+// restaurantIDs is nil. Counts are grouped per restaurant so the number of
+// queries does not grow with the number of restaurants, only with the number
+// of distinct time zones.
+func (h *RestaurantHandler) GetOwnerSummary(restaurantIDs []uint, now time.Time) (*OwnerSummary, error) {
+	if restaurantIDs != nil && len(restaurantIDs) == 0 {
+		return &OwnerSummary{Restaurants: []RestaurantSummary{}}, nil
+	}
+
+	var restaurants []Restaurant
+	query := h.db.Select("id", "name", "timezone").Order("id")
+	if restaurantIDs != nil {
+		query = query.Where("id IN ?", restaurantIDs)
+	}
+	if err := query.Find(&restaurants).Error; err != nil {
+		return nil, err
+	}
+
+	summary := OwnerSummary{Restaurants: make([]RestaurantSummary, len(restaurants))}
+	if len(restaurants) == 0 {
+		return &summary, nil
+	}
+
+	ids := make([]uint, len(restaurants))
+	byID := map[uint]*RestaurantSummary{}
+	byZone := map[*time.Location][]uint{}
+	for i := range restaurants {
+		ids[i] = restaurants[i].ID
+		summary.Restaurants[i] = RestaurantSummary{RestaurantID: restaurants[i].ID, Name: restaurants[i].Name, RecentReviews: []CommentResponse{}}
+		byID[restaurants[i].ID] = &summary.Restaurants[i]
+		location := restaurants[i].Location()
+		byZone[location] = append(byZone[location], restaurants[i].ID)
+	}
+
+	for location, zoneIDs := range byZone {
+		local := now.In(location)
+		dayStart := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, location)
+		var counts []restaurantCount
+		if err := h.db.Model(&Reservation{}).Select("restaurant_id, COUNT(*) AS count").
+			Where("restaurant_id IN ? AND status IN ? AND date_time >= ? AND date_time < ?", zoneIDs,
+				[]string{ReservationStatusPending, ReservationStatusConfirmed}, dayStart, dayStart.AddDate(0, 0, 1)).
+			Group("restaurant_id").Scan(&counts).Error; err != nil {
+			return nil, err
+		}
+		for _, count := range counts {
+			byID[count.RestaurantID].TodayReservations = count.Count
+			summary.TodayReservations += count.Count
+		}
+	}
+
+	var pending []restaurantCount
+	if err := h.db.Model(&Reservation{}).Select("restaurant_id, COUNT(*) AS count").
+		Where("restaurant_id IN ? AND status = ? AND date_time >= ?", ids, ReservationStatusPending, now).
+		Group("restaurant_id").Scan(&pending).Error; err != nil {
+		return nil, err
+	}
+	for _, count := range pending {
+		byID[count.RestaurantID].PendingConfirmations = count.Count
+		summary.PendingConfirmations += count.Count
+	}
+
+	// The latest reviews of every restaurant in one query
+	var reviews []Comment
+	if err := h.db.Preload("User").Preload("Tags").
+		Where("id IN (?)", h.db.Table("(?) AS ranked", h.db.Model(&Comment{}).
+			Select("id, ROW_NUMBER() OVER (PARTITION BY restaurant_id ORDER BY date_time DESC, id DESC) AS position").
+			Where("restaurant_id IN ?", ids)).
+			Select("id").Where("position <= ?", recentReviewsLimit)).
+		Order("date_time DESC, id DESC").Find(&reviews).Error; err != nil {
+		return nil, err
+	}
+	for _, review := range reviews {
+		restaurant := byID[review.RestaurantID]
+		restaurant.RecentReviews = append(restaurant.RecentReviews, review.Response())
+	}
+
+	return &summary, nil
+}
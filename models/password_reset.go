@@ -0,0 +1,90 @@
+package models
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PasswordResetToken is a single-use, time-limited token sent by email. Only
+// the SHA-256 hash of the token is stored.
+type PasswordResetToken struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"index"`
+	TokenHash string `gorm:"uniqueIndex"`
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+type PasswordResetHandler struct {
+	db *gorm.DB
+}
+
+func NewPasswordResetHandler(db *gorm.DB) *PasswordResetHandler {
+	return &PasswordResetHandler{db}
+}
+
+func generateToken() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateToken issues a new reset token for the user and returns the raw value
+// that should be delivered to them.
+func (h *PasswordResetHandler) CreateToken(userID uint, ttl time.Duration) (string, error) {
+	token, err := generateToken()
+	if err != nil {
+		return "", err
+	}
+
+	resetToken := PasswordResetToken{
+		UserID:    userID,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := h.db.Create(&resetToken).Error; err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// ResetPassword consumes the token and sets the new password of its owner.
+// Every other outstanding token of the user is invalidated as well.
+func (h *PasswordResetHandler) ResetPassword(token string, password string) (uint, error) {
+	var userID uint
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		var resetToken PasswordResetToken
+		if err := tx.Where("token_hash = ?", hashToken(token)).First(&resetToken).Error; err != nil {
+			return fmt.Errorf("invalid reset token")
+		}
+
+		if resetToken.UsedAt != nil || time.Now().After(resetToken.ExpiresAt) {
+			return fmt.Errorf("reset token has expired")
+		}
+
+		if err := NewUserHandler(tx).UpdatePassword(resetToken.UserID, password); err != nil {
+			return err
+		}
+
+		userID = resetToken.UserID
+		now := time.Now()
+		return tx.Model(&PasswordResetToken{}).
+			Where("user_id = ? AND used_at IS NULL", resetToken.UserID).
+			Update("used_at", &now).Error
+	})
+	return userID, err
+}
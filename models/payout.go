@@ -0,0 +1,149 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PayoutSummary sums up the deposits of a restaurant in one currency over a
+// period and splits them into the platform commission and the payout owed to
+// the owner. Only completed reservations count, deposits of cancelled or
+// declined bookings are refunded.
+type PayoutSummary struct {
+	RestaurantID   uint      `json:"restaurantId" example:"1"`
+	RestaurantName string    `json:"restaurantName" example:"RedRice"`
+	Reservations   int64     `json:"reservations" example:"12"`
+	GrossDeposits  Money     `json:"grossDeposits"`
+	CommissionRate float64   `json:"commissionRate" example:"0.1"`
+	Commission     Money     `json:"commission"`
+	Payout         Money     `json:"payout"`
+	PeriodStart    time.Time `json:"periodStart"`
+	PeriodEnd      time.Time `json:"periodEnd"`
+}
+
+type StatementLine struct {
+	ReservationID uint      `json:"reservationId" example:"42"`
+	DateTime      time.Time `json:"dateTime"`
+	Deposit       Money     `json:"deposit"`
+	ServiceCharge Money     `json:"serviceCharge"`
+	VAT           Money     `json:"vat"`
+	Commission    Money     `json:"commission"`
+	Payout        Money     `json:"payout"`
+}
+
+// PayoutStatement is the summary of one restaurant with its reservations.
+type PayoutStatement struct {
+	PayoutSummary
+	Lines []StatementLine `json:"lines"`
+}
+
+type PayoutHandler struct {
+	db *gorm.DB
+}
+
+func NewPayoutHandler(db *gorm.DB) *PayoutHandler {
+	return &PayoutHandler{db}
+}
+
+func (s *PayoutSummary) split(rate float64) error {
+	s.CommissionRate = rate
+	s.Commission = s.GrossDeposits.Mul(rate)
+	var err error
+	s.Payout, err = s.GrossDeposits.Sub(s.Commission)
+	return err
+}
+
+func (h *PayoutHandler) payableReservations(from, to time.Time) *gorm.DB {
+	return h.db.Model(&Reservation{}).
+		Where("reservations.status = ? AND reservations.deposit_minor > 0", ReservationStatusCompleted).
+		Where("reservations.date_time >= ? AND reservations.date_time < ?", from, to)
+}
+
+// GetPayouts returns the summary of every restaurant with deposits in [from,
+// to), one per currency the restaurant took deposits in.
+func (h *PayoutHandler) GetPayouts(from, to time.Time, rate float64) ([]PayoutSummary, error) {
+	var rows []struct {
+		RestaurantID   uint
+		RestaurantName string
+		Currency       string
+		Reservations   int64
+		Gross          int64
+	}
+	err := h.payableReservations(from, to).
+		Select("reservations.restaurant_id, restaurants.name AS restaurant_name, reservations.deposit_currency AS currency, COUNT(*) AS reservations, SUM(reservations.deposit_minor) AS gross").
+		Joins("JOIN restaurants ON restaurants.id = reservations.restaurant_id").
+		Group("reservations.restaurant_id, restaurants.name, reservations.deposit_currency").
+		Order("reservations.restaurant_id, reservations.deposit_currency").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]PayoutSummary, 0, len(rows))
+	for _, row := range rows {
+		summary := PayoutSummary{
+			RestaurantID:   row.RestaurantID,
+			RestaurantName: row.RestaurantName,
+			Reservations:   row.Reservations,
+			GrossDeposits:  NewMoney(row.Gross, row.Currency),
+			PeriodStart:    from,
+			PeriodEnd:      to,
+		}
+		if err := summary.split(rate); err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
+// GetStatement returns the summary of the restaurant for the deposits taken
+// in the currency in [from, to) together with every reservation it is made of.
+func (h *PayoutHandler) GetStatement(restaurant *Restaurant, currency string, from, to time.Time, rate float64) (*PayoutStatement, error) {
+	var reservations []Reservation
+	if err := h.payableReservations(from, to).
+		Where("reservations.restaurant_id = ? AND reservations.deposit_currency = ?", restaurant.ID, currency).
+		Order("reservations.date_time").
+		Find(&reservations).Error; err != nil {
+		return nil, err
+	}
+
+	statement := PayoutStatement{
+		PayoutSummary: PayoutSummary{
+			RestaurantID:   restaurant.ID,
+			RestaurantName: restaurant.Name,
+			GrossDeposits:  NewMoney(0, currency),
+			PeriodStart:    from,
+			PeriodEnd:      to,
+		},
+		Lines: []StatementLine{},
+	}
+
+	for _, reservation := range reservations {
+		charged := reservation.ChargedDeposit()
+		line := StatementLine{
+			ReservationID: reservation.ID,
+			DateTime:      reservation.DateTime,
+			Deposit:       reservation.Deposit,
+			ServiceCharge: charged.ServiceCharge,
+			VAT:           charged.VAT,
+			Commission:    reservation.Deposit.Mul(rate),
+		}
+		var err error
+		if line.Payout, err = line.Deposit.Sub(line.Commission); err != nil {
+			return nil, err
+		}
+		statement.Lines = append(statement.Lines, line)
+
+		statement.Reservations++
+		if statement.GrossDeposits, err = statement.GrossDeposits.Add(reservation.Deposit); err != nil {
+			return nil, err
+		}
+	}
+	if err := statement.split(rate); err != nil {
+		return nil, err
+	}
+
+	return &statement, nil
+}
@@ -0,0 +1,164 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	RoleAdmin = "admin"
+	RoleOwner = "restaurant_owner"
+	// RoleCustomer keeps the "user" value every existing account was created with
+	RoleCustomer = "user"
+	// RoleGuest is held by the records of guests booking without an account, see FindOrCreateGuest
+	RoleGuest = "guest"
+)
+
+const (
+	// PermissionAdminister is only held by admins and guards the admin routes
+	PermissionAdminister = "admin"
+	// PermissionManageAnyRestaurant allows managing every restaurant
+	PermissionManageAnyRestaurant = "restaurants:manage:any"
+	// PermissionManageOwnRestaurant allows managing the restaurant of the user
+	PermissionManageOwnRestaurant = "restaurants:manage:own"
+	PermissionBook                = "reservations:book"
+	// PermissionBypassBookingLimits skips the reservation limit and phone verification
+	PermissionBypassBookingLimits = "reservations:bypass-limits"
+	PermissionComment             = "comments:write"
+)
+
+// rolePermissions lists the permissions granted to each role. Admins hold
+// every permission and are not listed.
+var rolePermissions = map[string][]string{
+	RoleOwner:    {PermissionManageOwnRestaurant, PermissionBook, PermissionComment},
+	RoleCustomer: {PermissionBook, PermissionComment},
+	RoleGuest:    {PermissionBook},
+}
+
+// rolePriority orders the roles by privilege, for merging accounts.
+var rolePriority = map[string]int{RoleCustomer: 1, RoleOwner: 2, RoleAdmin: 3}
+
+// Roles lists every role a user can be given, guest records are only
+// created by FindOrCreateGuest.
+var Roles = []string{RoleAdmin, RoleOwner, RoleCustomer}
+
+func IsValidRole(role string) bool {
+	for _, r := range Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// HasPermission reports whether the role grants the permission.
+func HasPermission(role, permission string) bool {
+	if role == RoleAdmin {
+		return true
+	}
+	for _, p := range rolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// RolePermissions returns the permissions granted to the role.
+func RolePermissions(role string) []string {
+	if role == RoleAdmin {
+		return []string{PermissionAdminister, PermissionManageAnyRestaurant, PermissionManageOwnRestaurant, PermissionBook, PermissionBypassBookingLimits, PermissionComment}
+	}
+	return append([]string{}, rolePermissions[role]...)
+}
+
+var ErrRestaurantRequired = fmt.Errorf("restaurant owners need a restaurant")
+var ErrRestaurantNotFound = fmt.Errorf("restaurant not found")
+var ErrLastAdmin = fmt.Errorf("the last admin cannot be demoted")
+
+// RoleChange is the result of ChangeRole, with the values before the change.
+type RoleChange struct {
+	User                 *User
+	PreviousRole         string
+	PreviousRestaurantID uint
+}
+
+// ChangeRole gives the user a new role. Owners must be assigned an existing
+// restaurant, other roles are unlinked from their restaurant, and the last
+// admin keeps their role. Sessions issued before the change are revoked so
+// the new role applies right away.
+func (h *UserHandler) ChangeRole(id uint, role string, restaurantID uint) (*RoleChange, error) {
+	if !IsValidRole(role) {
+		return nil, ErrInvalidRole
+	}
+	if role == RoleOwner && restaurantID == 0 {
+		return nil, ErrRestaurantRequired
+	}
+	if role != RoleOwner {
+		restaurantID = 0
+	}
+
+	var change RoleChange
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		var user User
+		if err := tx.First(&user, id).Error; err != nil {
+			return err
+		}
+		change.PreviousRole = user.Role
+		change.PreviousRestaurantID = user.RestaurantId
+
+		if restaurantID != 0 {
+			var count int64
+			if err := tx.Model(&Restaurant{}).Where("id = ?", restaurantID).Count(&count).Error; err != nil {
+				return err
+			}
+			if count == 0 {
+				return ErrRestaurantNotFound
+			}
+		}
+
+		if user.Role == RoleAdmin && role != RoleAdmin {
+			var admins int64
+			if err := tx.Model(&User{}).Where("role = ?", RoleAdmin).Count(&admins).Error; err != nil {
+				return err
+			}
+			if admins <= 1 {
+				return ErrLastAdmin
+			}
+		}
+
+		return tx.Model(&User{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"role":               role,
+			"restaurant_id":      restaurantID,
+			"tokens_valid_after": time.Now(),
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	change.User, err = h.GetUser(id)
+	if err != nil {
+		return nil, err
+	}
+	return &change, nil
+}
+
+// CanManageRestaurant reports whether the user may manage every restaurant or
+// owns the given one.
+func (u *User) CanManageRestaurant(restaurantID uint) bool {
+	if HasPermission(u.Role, PermissionManageAnyRestaurant) {
+		return true
+	}
+	return HasPermission(u.Role, PermissionManageOwnRestaurant) && u.RestaurantId != 0 && u.RestaurantId == restaurantID
+}
+
+// MigrateRoles gives the owner role to users linked to a restaurant, who
+// managed it through the customer role before roles were introduced.
+func MigrateRoles(db *gorm.DB) error {
+	return db.Model(&User{}).
+		Where("role = ? AND restaurant_id <> 0", RoleCustomer).
+		Update("role", RoleOwner).Error
+}
@@ -0,0 +1,138 @@
+package models
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"fmt"
+	"math/big"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// PhoneCodeTTL is how long a sent verification code stays valid.
+	PhoneCodeTTL = 10 * time.Minute
+	// PhoneCodeMaxAttempts is the number of wrong guesses allowed per code.
+	PhoneCodeMaxAttempts = 5
+	// PhoneCodeMaxSendsPerHour limits how many codes a user can request.
+	PhoneCodeMaxSendsPerHour = 5
+	// phoneCodeResendInterval is the minimum delay between two codes.
+	phoneCodeResendInterval = time.Minute
+)
+
+var (
+	ErrPhoneCodeThrottled       = fmt.Errorf("too many verification codes requested, please try again later")
+	ErrPhoneCodeInvalid         = fmt.Errorf("invalid verification code")
+	ErrPhoneCodeExpired         = fmt.Errorf("verification code has expired, please request a new one")
+	ErrPhoneCodeTooManyAttempts = fmt.Errorf("too many wrong attempts, please request a new code")
+)
+
+// PhoneVerification is a one-time code sent by SMS to prove ownership of a
+// telephone number. Only the SHA-256 hash of the code is stored.
+type PhoneVerification struct {
+	ID         uint `gorm:"primaryKey"`
+	UserID     uint `gorm:"index"`
+	Telephone  string
+	CodeHash   string
+	Attempts   int `gorm:"default:0"`
+	ExpiresAt  time.Time
+	VerifiedAt *time.Time
+	CreatedAt  time.Time
+}
+
+type PhoneVerificationHandler struct {
+	db *gorm.DB
+}
+
+func NewPhoneVerificationHandler(db *gorm.DB) *PhoneVerificationHandler {
+	return &PhoneVerificationHandler{db}
+}
+
+func generatePhoneCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+// CountRecentCodes returns how many codes the user requested during the last
+// hour, see PhoneCodeMaxSendsPerHour.
+func (h *PhoneVerificationHandler) CountRecentCodes(userID uint, now time.Time) (int64, error) {
+	var count int64
+	err := h.db.Model(&PhoneVerification{}).Where("user_id = ? AND created_at > ?", userID, now.Add(-time.Hour)).Count(&count).Error
+	return count, err
+}
+
+// CreateCode issues a new code for the telephone of the user and returns the
+// raw value to send. Requests are throttled per user.
+func (h *PhoneVerificationHandler) CreateCode(userID uint, telephone string) (string, error) {
+	now := time.Now()
+
+	var recent []PhoneVerification
+	if err := h.db.Where("user_id = ? AND created_at > ?", userID, now.Add(-time.Hour)).
+		Order("created_at DESC").Find(&recent).Error; err != nil {
+		return "", err
+	}
+	if len(recent) >= PhoneCodeMaxSendsPerHour ||
+		(len(recent) > 0 && now.Sub(recent[0].CreatedAt) < phoneCodeResendInterval) {
+		return "", ErrPhoneCodeThrottled
+	}
+
+	code, err := generatePhoneCode()
+	if err != nil {
+		return "", err
+	}
+
+	verification := PhoneVerification{
+		UserID:    userID,
+		Telephone: telephone,
+		CodeHash:  hashToken(code),
+		ExpiresAt: now.Add(PhoneCodeTTL),
+	}
+	if err := h.db.Create(&verification).Error; err != nil {
+		return "", err
+	}
+
+	return code, nil
+}
+
+// VerifyCode checks the code against the latest one sent to the user and marks
+// the telephone of the user as verified on success. The code must have been
+// sent to the telephone currently on the account.
+func (h *PhoneVerificationHandler) VerifyCode(userID uint, code string) error {
+	return h.db.Transaction(func(tx *gorm.DB) error {
+		var user User
+		if err := tx.First(&user, userID).Error; err != nil {
+			return fmt.Errorf("user not found")
+		}
+
+		var verification PhoneVerification
+		if err := tx.Where("user_id = ? AND verified_at IS NULL", userID).
+			Order("created_at DESC").First(&verification).Error; err != nil {
+			return ErrPhoneCodeInvalid
+		}
+
+		if verification.Telephone != user.Telephone || time.Now().After(verification.ExpiresAt) {
+			return ErrPhoneCodeExpired
+		}
+		if verification.Attempts >= PhoneCodeMaxAttempts {
+			return ErrPhoneCodeTooManyAttempts
+		}
+
+		if subtle.ConstantTimeCompare([]byte(hashToken(code)), []byte(verification.CodeHash)) != 1 {
+			// Count the attempt even though the transaction reports an error
+			if err := h.db.Model(&verification).Update("attempts", gorm.Expr("attempts + 1")).Error; err != nil {
+				return err
+			}
+			return ErrPhoneCodeInvalid
+		}
+
+		now := time.Now()
+		if err := tx.Model(&verification).Update("verified_at", &now).Error; err != nil {
+			return err
+		}
+		return tx.Model(&User{}).Where("id = ?", userID).Update("telephone_verified_at", &now).Error
+	})
+}
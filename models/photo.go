@@ -0,0 +1,406 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/punchanabu/redrice-backend-go/utils"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	PhotoSourceOwner  = "owner"
+	PhotoSourceReview = "review"
+)
+
+// RestaurantImage is a gallery image uploaded by the owner of a restaurant.
+type RestaurantImage struct {
+	ID           uint   `gorm:"primaryKey"`
+	RestaurantID uint   `json:"restaurantId" gorm:"index"`
+	ImageURL     string `json:"imageUrl"`
+	ThumbnailURL string `json:"thumbnailUrl"`
+	Caption      string `json:"caption"`
+	UploadedBy   uint   `json:"uploadedBy"`
+	// Position orders the gallery, lowest first
+	Position int `json:"position" gorm:"not null;default:0"`
+	// Cover is set on the image shown as the image of the restaurant
+	Cover      bool `json:"cover" gorm:"not null;default:false"`
+	gorm.Model `json:"-" swaggerignore:"true"`
+}
+
+var ErrInvalidImageOrder = fmt.Errorf("the order must list every image of the gallery once")
+
+// galleryOrder preloads a gallery in its order.
+func galleryOrder(db *gorm.DB) *gorm.DB {
+	return db.Order("position, id")
+}
+
+// CommentPhoto is a photo attached to a review. It only shows up in the
+// gallery once the owner of the restaurant or an admin approved it.
+type CommentPhoto struct {
+	ID           uint   `gorm:"primaryKey"`
+	CommentID    uint   `json:"commentId" gorm:"index"`
+	RestaurantID uint   `json:"restaurantId" gorm:"index"`
+	UserID       uint   `json:"userId"`
+	ImageURL     string `json:"imageUrl"`
+	ThumbnailURL string `json:"thumbnailUrl"`
+	Approved     bool   `json:"approved" gorm:"default:false"`
+	gorm.Model   `json:"-" swaggerignore:"true"`
+}
+
+// Photo is an entry of the merged gallery of a restaurant.
+type Photo struct {
+	ID           uint      `json:"id"`
+	ImageURL     string    `json:"imageUrl"`
+	ThumbnailURL string    `json:"thumbnailUrl"`
+	Caption      string    `json:"caption"`
+	Source       string    `json:"source" enums:"owner,review"`
+	CommentID    *uint     `json:"commentId,omitempty"`
+	UserID       uint      `json:"userId"`
+	UserName     string    `json:"userName"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+type PhotoHandler struct {
+	db *gorm.DB
+}
+
+func NewPhotoHandler(db *gorm.DB) *PhotoHandler {
+	return &PhotoHandler{db}
+}
+
+// CreateRestaurantImages adds the images of one restaurant to the end of its
+// gallery, in order, and to the feed of the followers of the restaurant.
+// Either all images are saved or none.
+func (h *PhotoHandler) CreateRestaurantImages(restaurantID uint, images []RestaurantImage) error {
+	return h.db.Transaction(func(tx *gorm.DB) error {
+		// New images go to the end of the gallery
+		var position int
+		if err := tx.Model(&RestaurantImage{}).Select("COALESCE(MAX(position) + 1, 0)").
+			Where("restaurant_id = ?", restaurantID).Scan(&position).Error; err != nil {
+			return err
+		}
+		for i := range images {
+			images[i].RestaurantID = restaurantID
+			images[i].Position = position + i
+			if err := tx.Create(&images[i]).Error; err != nil {
+				return err
+			}
+			if err := tx.Create(&FeedItem{
+				RestaurantID: restaurantID,
+				Type:         FeedPhotoAdded,
+				Title:        "New photo",
+				Body:         images[i].Caption,
+				ImageURL:     images[i].ImageURL,
+				CreatedBy:    images[i].UploadedBy,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (h *PhotoHandler) GetRestaurantImage(restaurantID, imageID uint) (*RestaurantImage, error) {
+	var image RestaurantImage
+	result := h.db.Where("restaurant_id = ?", restaurantID).First(&image, imageID)
+	return &image, result.Error
+}
+
+func (h *PhotoHandler) GetGallery(restaurantID uint) ([]RestaurantImage, error) {
+	var images []RestaurantImage
+	result := h.db.Scopes(galleryOrder).Where("restaurant_id = ?", restaurantID).Find(&images)
+	return images, result.Error
+}
+
+// ReorderGallery puts the images of the restaurant in the order of the IDs,
+// which must list every image of the gallery.
+func (h *PhotoHandler) ReorderGallery(restaurantID uint, imageIDs []uint) error {
+	return h.db.Transaction(func(tx *gorm.DB) error {
+		var current []uint
+		if err := tx.Model(&RestaurantImage{}).Where("restaurant_id = ?", restaurantID).Pluck("id", &current).Error; err != nil {
+			return err
+		}
+		listed := map[uint]bool{}
+		for _, id := range imageIDs {
+			listed[id] = true
+		}
+		if len(imageIDs) != len(current) || len(listed) != len(current) {
+			return ErrInvalidImageOrder
+		}
+		for _, id := range current {
+			if !listed[id] {
+				return ErrInvalidImageOrder
+			}
+		}
+
+		for position, id := range imageIDs {
+			if err := tx.Model(&RestaurantImage{}).Where("id = ?", id).Update("position", position).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// setCover marks the image as the cover of the gallery and shows it as the
+// image of the restaurant, recorded in the restaurant history. A nil image
+// clears the cover.
+func setCover(tx *gorm.DB, restaurantID uint, image *RestaurantImage, changedBy uint) error {
+	if err := tx.Model(&RestaurantImage{}).Where("restaurant_id = ? AND cover", restaurantID).Update("cover", false).Error; err != nil {
+		return err
+	}
+	imageURL := ""
+	if image != nil {
+		if err := tx.Model(image).Update("cover", true).Error; err != nil {
+			return err
+		}
+		imageURL = image.ImageURL
+	}
+	return applyListingChanges(tx, restaurantID, changedBy, map[string]string{"image_url": imageURL}, nil)
+}
+
+// SetCoverImage makes the gallery image the image of the restaurant.
+func (h *PhotoHandler) SetCoverImage(image *RestaurantImage, changedBy uint) error {
+	return h.db.Transaction(func(tx *gorm.DB) error {
+		return setCover(tx, image.RestaurantID, image, changedBy)
+	})
+}
+
+// DeleteRestaurantImage removes the image from the gallery. When it was the
+// cover, the next image of the gallery becomes the cover.
+func (h *PhotoHandler) DeleteRestaurantImage(image *RestaurantImage, changedBy uint) error {
+	return h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(image).Error; err != nil {
+			return err
+		}
+		if !image.Cover {
+			return nil
+		}
+
+		var next []RestaurantImage
+		if err := tx.Scopes(galleryOrder).Where("restaurant_id = ?", image.RestaurantID).Limit(1).Find(&next).Error; err != nil {
+			return err
+		}
+		if len(next) == 0 {
+			return setCover(tx, image.RestaurantID, nil, changedBy)
+		}
+		return setCover(tx, image.RestaurantID, &next[0], changedBy)
+	})
+}
+
+func (h *PhotoHandler) CreateCommentPhoto(photo *CommentPhoto) error {
+	return h.db.Create(photo).Error
+}
+
+func (h *PhotoHandler) GetCommentPhoto(id uint) (*CommentPhoto, error) {
+	var photo CommentPhoto
+	result := h.db.First(&photo, id)
+	return &photo, result.Error
+}
+
+func (h *PhotoHandler) SetCommentPhotoApproval(id uint, approved bool) error {
+	result := h.db.Model(&CommentPhoto{}).Where("id = ?", id).Update("approved", approved)
+	return result.Error
+}
+
+func (h *PhotoHandler) GetPendingCommentPhotos(restaurantID uint) ([]CommentPhoto, error) {
+	var photos []CommentPhoto
+	result := h.db.Where("restaurant_id = ? AND approved = ?", restaurantID, false).Order("created_at").Find(&photos)
+	return photos, result.Error
+}
+
+// GetRestaurantPhotos merges owner gallery images and approved review photos,
+// newest first, and returns one page of them together with the total count.
+func (h *PhotoHandler) GetRestaurantPhotos(restaurantID uint, limit, offset int) ([]Photo, int64, error) {
+	const union = `
+		SELECT restaurant_images.id, restaurant_images.image_url, restaurant_images.thumbnail_url, restaurant_images.caption, 'owner' AS source,
+			NULL AS comment_id, restaurant_images.uploaded_by AS user_id, restaurant_images.created_at
+		FROM restaurant_images
+		WHERE restaurant_images.restaurant_id = @restaurant AND restaurant_images.deleted_at IS NULL
+		UNION ALL
+		SELECT comment_photos.id, comment_photos.image_url, comment_photos.thumbnail_url, '' AS caption, 'review' AS source,
+			comment_photos.comment_id, comment_photos.user_id, comment_photos.created_at
+		FROM comment_photos
+		WHERE comment_photos.restaurant_id = @restaurant AND comment_photos.approved AND comment_photos.deleted_at IS NULL`
+
+	var total int64
+	if err := h.db.Raw("SELECT COUNT(*) FROM ("+union+") AS photos", map[string]interface{}{"restaurant": restaurantID}).
+		Scan(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var photos []Photo
+	err := h.db.Raw(`
+		SELECT photos.*, COALESCE(users.name, '') AS user_name
+		FROM (`+union+`) AS photos
+		LEFT JOIN users ON users.id = photos.user_id
+		ORDER BY photos.created_at DESC, photos.id DESC
+		LIMIT @limit OFFSET @offset`,
+		map[string]interface{}{"restaurant": restaurantID, "limit": limit, "offset": offset}).
+		Scan(&photos).Error
+
+	return photos, total, err
+}
+
+// imageBucket is the bucket the images are uploaded to.
+const imageBucket = "redrice"
+
+// imageColumns are the columns holding the URL of an uploaded image. The
+// listing history and the pending scheduled changes hold their URLs in JSON,
+// a revert or a publish can bring them back. The scope limits the rows that
+// count, if set.
+var imageColumns = []struct {
+	model  interface{}
+	column string
+	scope  map[string]interface{}
+}{
+	{&User{}, "image_url", nil},
+	{&Restaurant{}, "image_url", nil},
+	{&MenuItem{}, "image_url", nil},
+	{&RestaurantImage{}, "image_url", nil},
+	{&RestaurantImage{}, "thumbnail_url", nil},
+	{&CommentPhoto{}, "image_url", nil},
+	{&CommentPhoto{}, "thumbnail_url", nil},
+	{&FeedItem{}, "image_url", nil},
+	{&RestaurantTheme{}, "logo_url", nil},
+	{&RestaurantVersion{}, "changes", nil},
+	{&ScheduledChange{}, "changes", map[string]interface{}{"status": ScheduledChangePending}},
+}
+
+// unusedImageGrace is how long an image must go unwritten before it is
+// deleted. It covers the time between an upload and the save of the record
+// showing it.
+const unusedImageGrace = time.Hour
+
+// UnusedImage is an uploaded image queued for deletion.
+type UnusedImage struct {
+	Key      string    `gorm:"primaryKey"`
+	QueuedAt time.Time `gorm:"index"`
+}
+
+// deleteUnusedImages queues the uploaded images with the URLs for deletion,
+// see DeleteQueuedImages. Images are named after their content, two uploads
+// of the same file share one object, so an upload of the same file can still
+// be saved with the URL after the check that nothing shows it.
+func deleteUnusedImages(db *gorm.DB, urls ...string) {
+	var images []UnusedImage
+	for _, url := range urls {
+		if key := utils.ObjectKeyFromURL(url); key != "" {
+			images = append(images, UnusedImage{Key: key, QueuedAt: time.Now()})
+		}
+	}
+	if len(images) == 0 {
+		return
+	}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&images).Error; err != nil {
+		log.Printf("Keeping %d images, failed to queue them for deletion: %v", len(images), err)
+	}
+}
+
+func imageInUse(db *gorm.DB, key string) (bool, error) {
+	for _, image := range imageColumns {
+		var count int64
+		query := db.Model(image.model).Where(image.column+" LIKE ?", "%/"+key+"?%")
+		if image.scope != nil {
+			query = query.Where(image.scope)
+		}
+		if err := query.Limit(1).Count(&count).Error; err != nil {
+			return false, err
+		}
+		if count > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// DeleteUnusedImages queues the uploaded images with the URLs for deletion.
+func (h *PhotoHandler) DeleteUnusedImages(urls ...string) {
+	deleteUnusedImages(h.db, urls...)
+}
+
+// DeleteQueuedImages removes the queued images that no record shows and that
+// were not uploaded again for unusedImageGrace. The ones uploaded again stay
+// queued for a later run.
+func (h *PhotoHandler) DeleteQueuedImages() error {
+	var images []UnusedImage
+	if err := h.db.Where("queued_at < ?", time.Now().Add(-unusedImageGrace)).Order("queued_at").Find(&images).Error; err != nil {
+		return err
+	}
+
+	for _, image := range images {
+		used, err := imageInUse(h.db, image.Key)
+		if err != nil {
+			return err
+		}
+		if !used {
+			modifiedAt, err := utils.ObjectModifiedAt(imageBucket, image.Key)
+			if err != nil && !errors.Is(err, utils.ErrObjectNotFound) {
+				return err
+			}
+			if err == nil && time.Since(modifiedAt) < unusedImageGrace {
+				continue
+			}
+			if err == nil {
+				if err := utils.DeleteFromS3(imageBucket, image.Key); err != nil {
+					return err
+				}
+			}
+		}
+		if err := h.db.Delete(&image).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// refreshThumbnails makes the thumbnails of the images of the table with the
+// IDs again from their originals, e.g. after the thumbnails changed. Images
+// whose original is gone or cannot be decoded keep their thumbnail. The
+// replaced thumbnails are deleted once every image got its new one.
+func refreshThumbnails(tx *gorm.DB, model interface{}, ids []uint) error {
+	var images []struct {
+		ID           uint
+		ImageURL     string
+		ThumbnailURL string
+	}
+	if err := tx.Model(model).Select("id", "image_url", "thumbnail_url").Where("id IN ?", ids).Find(&images).Error; err != nil {
+		return err
+	}
+
+	var replaced []string
+	for _, image := range images {
+		key := utils.ObjectKeyFromURL(image.ImageURL)
+		if key == "" {
+			continue
+		}
+		content, err := utils.DownloadFromS3(imageBucket, key)
+		if errors.Is(err, utils.ErrObjectNotFound) {
+			log.Printf("Skipping thumbnail of image %d: %v", image.ID, err)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		thumbnailURL, err := utils.UploadThumbnail(imageBucket, content)
+		if errors.Is(err, utils.ErrUnsupportedImage) {
+			log.Printf("Skipping thumbnail of image %d: %v", image.ID, err)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(model).Where("id = ?", image.ID).Update("thumbnail_url", thumbnailURL).Error; err != nil {
+			return err
+		}
+		replaced = append(replaced, image.ThumbnailURL)
+	}
+
+	deleteUnusedImages(tx, replaced...)
+	return nil
+}
@@ -0,0 +1,125 @@
+package models
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	DefaultLanguage  = "th"
+	DefaultPartySize = 2
+	// MaxPartySize is the largest party size a user can prefill.
+	MaxPartySize = 20
+)
+
+// Languages lists the languages the frontend is translated to.
+var Languages = []string{"th", "en"}
+
+// DietaryRestrictions lists the restrictions a user can pick from.
+var DietaryRestrictions = []string{"vegetarian", "vegan", "halal", "kosher", "gluten_free", "lactose_free", "nut_allergy", "seafood_allergy", "no_pork", "no_beef"}
+
+var ErrInvalidPreferences = fmt.Errorf("invalid preferences")
+
+// UserPreferences are the settings the frontend uses to prefill forms for the user.
+type UserPreferences struct {
+	ID                  uint     `gorm:"primaryKey" json:"-" swaggerignore:"true"`
+	UserID              uint     `gorm:"uniqueIndex" json:"-" swaggerignore:"true"`
+	Language            string   `json:"language" example:"th" enums:"th,en"`
+	DietaryRestrictions []string `gorm:"serializer:json" json:"dietaryRestrictions" example:"vegetarian,nut_allergy"`
+	DefaultPartySize    int      `json:"defaultPartySize" example:"2"`
+	// HideContact keeps the email and telephone of the user out of the customer lists and reservations shown to restaurants
+	HideContact bool `json:"hideContact" example:"false"`
+	// ReviewAnonymously is used for new reviews that do not say whether they are anonymous
+	ReviewAnonymously bool `json:"reviewAnonymously" example:"false"`
+	// EmailDelivery and SMSDelivery choose between immediate notifications and a daily digest
+	EmailDelivery string `json:"emailDelivery" gorm:"default:immediate" example:"immediate" enums:"immediate,daily_digest"`
+	SMSDelivery   string `json:"smsDelivery" gorm:"default:immediate" example:"daily_digest" enums:"immediate,daily_digest"`
+}
+
+func DefaultUserPreferences(userID uint) *UserPreferences {
+	return &UserPreferences{
+		UserID:              userID,
+		Language:            DefaultLanguage,
+		DietaryRestrictions: []string{},
+		DefaultPartySize:    DefaultPartySize,
+		EmailDelivery:       NotificationDeliveryImmediate,
+		SMSDelivery:         NotificationDeliveryImmediate,
+	}
+}
+
+// Validate checks the preferences against the supported languages, dietary
+// restrictions and notification deliveries and the allowed party sizes.
+func (p *UserPreferences) Validate() error {
+	if !isOneOf(p.Language, Languages) {
+		return fmt.Errorf("%w: unsupported language %q", ErrInvalidPreferences, p.Language)
+	}
+	for _, restriction := range p.DietaryRestrictions {
+		if !isOneOf(restriction, DietaryRestrictions) {
+			return fmt.Errorf("%w: unknown dietary restriction %q", ErrInvalidPreferences, restriction)
+		}
+	}
+	if p.DefaultPartySize < 1 || p.DefaultPartySize > MaxPartySize {
+		return fmt.Errorf("%w: default party size must be between 1 and %d", ErrInvalidPreferences, MaxPartySize)
+	}
+	for _, delivery := range []string{p.EmailDelivery, p.SMSDelivery} {
+		if !isOneOf(delivery, NotificationDeliveries) {
+			return fmt.Errorf("%w: unknown notification delivery %q", ErrInvalidPreferences, delivery)
+		}
+	}
+	return nil
+}
+
+// Delivery returns how the user wants the notifications of the channel delivered.
+func (p *UserPreferences) Delivery(channel string) string {
+	if channel == NotificationChannelSMS {
+		return p.SMSDelivery
+	}
+	return p.EmailDelivery
+}
+
+type PreferencesHandler struct {
+	db *gorm.DB
+}
+
+func NewPreferencesHandler(db *gorm.DB) *PreferencesHandler {
+	return &PreferencesHandler{db}
+}
+
+// GetPreferences returns the preferences of the user, or the defaults when
+// they have not saved any.
+func (h *PreferencesHandler) GetPreferences(userID uint) (*UserPreferences, error) {
+	var preferences UserPreferences
+	result := h.db.Where("user_id = ?", userID).Limit(1).Find(&preferences)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return DefaultUserPreferences(userID), nil
+	}
+	if preferences.DietaryRestrictions == nil {
+		preferences.DietaryRestrictions = []string{}
+	}
+	return &preferences, nil
+}
+
+// SavePreferences validates and creates or replaces the preferences of the user.
+func (h *PreferencesHandler) SavePreferences(preferences *UserPreferences) error {
+	if err := preferences.Validate(); err != nil {
+		return err
+	}
+	return h.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"language", "dietary_restrictions", "default_party_size", "hide_contact", "review_anonymously", "email_delivery", "sms_delivery"}),
+	}).Create(preferences).Error
+}
+
+// movePreferences gives the preferences of the source user of a merge to the
+// target user unless the target saved its own.
+func movePreferences(tx *gorm.DB, targetID, sourceID uint) error {
+	if err := tx.Exec("UPDATE user_preferences SET user_id = ? WHERE user_id = ? AND NOT EXISTS (SELECT 1 FROM user_preferences WHERE user_id = ?)", targetID, sourceID, targetID).Error; err != nil {
+		return err
+	}
+	return tx.Where("user_id = ?", sourceID).Delete(&UserPreferences{}).Error
+}
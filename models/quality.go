@@ -0,0 +1,141 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// minQualityDescriptionLength is the description length from which a
+// listing counts as described, well above minDescriptionLength.
+const minQualityDescriptionLength = 100
+
+// qualityCheck is one part of the completeness of a listing, worth its points
+// when the SQL condition on the restaurants row holds.
+type qualityCheck struct {
+	key       string
+	points    int
+	hint      string
+	condition string
+}
+
+// qualityChecks add up to a score of 100.
+var qualityChecks = []qualityCheck{
+	{
+		key:       "photos",
+		points:    25,
+		hint:      "Upload photos to the gallery, listings with photos get more bookings",
+		condition: "EXISTS (SELECT 1 FROM restaurant_images WHERE restaurant_images.restaurant_id = restaurants.id AND restaurant_images.deleted_at IS NULL)",
+	},
+	{
+		key:       "hours",
+		points:    20,
+		hint:      "Set the opening and closing time so guests know when to come",
+		condition: "restaurants.open_time <> '' AND restaurants.close_time <> ''",
+	},
+	{
+		key:       "location",
+		points:    20,
+		hint:      "Set the location so the restaurant shows up on the map and in nearby searches",
+		condition: "restaurants.latitude IS NOT NULL AND restaurants.longitude IS NOT NULL",
+	},
+	{
+		key:       "menu",
+		points:    20,
+		hint:      "Add the menu with its prices",
+		condition: "EXISTS (SELECT 1 FROM menu_items WHERE menu_items.restaurant_id = restaurants.id)",
+	},
+	{
+		key:       "description",
+		points:    15,
+		hint:      fmt.Sprintf("Write a description of at least %d characters about the food and the place", minQualityDescriptionLength),
+		condition: fmt.Sprintf("char_length(trim(restaurants.description)) >= %d", minQualityDescriptionLength),
+	},
+}
+
+// QualityCheck is the result of one part of the completeness score, with a
+// hint on how to pass it when it failed.
+type QualityCheck struct {
+	Key    string `json:"key" example:"photos" enums:"photos,hours,location,menu,description"`
+	Passed bool   `json:"passed" example:"false"`
+	Points int    `json:"points" example:"25"`
+	Hint   string `json:"hint,omitempty" example:"Upload photos to the gallery, listings with photos get more bookings"`
+}
+
+// RestaurantQuality is how complete the listing of a restaurant is, from 0
+// to 100.
+type RestaurantQuality struct {
+	RestaurantID uint           `json:"restaurantId" example:"7"`
+	Name         string         `json:"name" example:"Baan Somtum"`
+	Status       string         `json:"status" example:"active"`
+	Score        int            `json:"score" example:"55"`
+	Checks       []QualityCheck `json:"checks"`
+}
+
+// qualitySelect selects the restaurant with the result of every check.
+func qualitySelect() string {
+	columns := []string{"restaurants.id", "restaurants.name", "restaurants.status"}
+	for _, check := range qualityChecks {
+		columns = append(columns, "("+check.condition+") AS check_"+check.key)
+	}
+	return strings.Join(columns, ", ")
+}
+
+// qualityScore is the SQL expression of the score of a restaurants row.
+func qualityScore() string {
+	terms := make([]string, len(qualityChecks))
+	for i, check := range qualityChecks {
+		terms[i] = fmt.Sprintf("CASE WHEN %s THEN %d ELSE 0 END", check.condition, check.points)
+	}
+	return "(" + strings.Join(terms, " + ") + ")"
+}
+
+func qualityFromRow(row map[string]interface{}) RestaurantQuality {
+	quality := RestaurantQuality{Checks: make([]QualityCheck, len(qualityChecks))}
+	if id, ok := row["id"].(int64); ok {
+		quality.RestaurantID = uint(id)
+	}
+	quality.Name, _ = row["name"].(string)
+	quality.Status, _ = row["status"].(string)
+	for i, check := range qualityChecks {
+		passed, _ := row["check_"+check.key].(bool)
+		quality.Checks[i] = QualityCheck{Key: check.key, Passed: passed, Points: check.points}
+		if passed {
+			quality.Score += check.points
+		} else {
+			quality.Checks[i].Hint = check.hint
+		}
+	}
+	return quality
+}
+
+// GetQuality scores the completeness of the listing of the restaurant.
+func (h *RestaurantHandler) GetQuality(id uint) (*RestaurantQuality, error) {
+	row := map[string]interface{}{}
+	if err := h.db.Model(&Restaurant{}).Select(qualitySelect()).Where("restaurants.id = ?", id).Take(&row).Error; err != nil {
+		return nil, err
+	}
+	quality := qualityFromRow(row)
+	return &quality, nil
+}
+
+// GetLowQualityRestaurants returns one page of the restaurants scoring at
+// most maxScore, lowest first, with their number.
+func (h *RestaurantHandler) GetLowQualityRestaurants(maxScore, limit, offset int) ([]RestaurantQuality, int64, error) {
+	query := h.db.Model(&Restaurant{}).Where(qualityScore()+" <= ?", maxScore)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var rows []map[string]interface{}
+	if err := query.Select(qualitySelect()).Order(qualityScore() + ", restaurants.id").Limit(limit).Offset(offset).
+		Find(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+	restaurants := make([]RestaurantQuality, len(rows))
+	for i, row := range rows {
+		restaurants[i] = qualityFromRow(row)
+	}
+	return restaurants, total, nil
+}
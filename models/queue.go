@@ -0,0 +1,147 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	QueueStatusWaiting = "waiting"
+	QueueStatusSeated  = "seated"
+	QueueStatusLeft    = "left"
+)
+
+// defaultSeatingDuration is used when a restaurant has no seating history yet.
+const defaultSeatingDuration = 60 * time.Minute
+
+// QueueEntry is a walk-in party waiting for a table.
+type QueueEntry struct {
+	ID           uint       `gorm:"primaryKey"`
+	RestaurantID uint       `json:"restaurantId" gorm:"index"`
+	UserID       uint       `json:"userId" gorm:"index"`
+	PartySize    int        `json:"partySize"`
+	Status       string     `json:"status" gorm:"index;default:waiting"`
+	JoinedAt     time.Time  `json:"joinedAt"`
+	SeatedAt     *time.Time `json:"seatedAt"`
+	gorm.Model   `json:"-" swaggerignore:"true"`
+}
+
+type WaitEstimate struct {
+	RestaurantID          uint    `json:"restaurantId"`
+	PartiesWaiting        int64   `json:"partiesWaiting"`
+	EstimatedWaitMinutes  int     `json:"estimatedWaitMinutes"`
+	AverageSeatingMinutes float64 `json:"averageSeatingMinutes"`
+	TablesTurningOver     int64   `json:"tablesTurningOver"`
+	Position              int64   `json:"position,omitempty"`
+}
+
+type QueueHandler struct {
+	db *gorm.DB
+}
+
+func NewQueueHandler(db *gorm.DB) *QueueHandler {
+	return &QueueHandler{db}
+}
+
+func (h *QueueHandler) JoinQueue(entry *QueueEntry) error {
+	entry.Status = QueueStatusWaiting
+	entry.JoinedAt = time.Now()
+	entry.SeatedAt = nil
+	return h.db.Create(entry).Error
+}
+
+func (h *QueueHandler) GetQueueEntry(id uint) (*QueueEntry, error) {
+	var entry QueueEntry
+	result := h.db.First(&entry, id)
+	return &entry, result.Error
+}
+
+func (h *QueueHandler) GetWaitingEntries(restaurantID uint) ([]QueueEntry, error) {
+	var entries []QueueEntry
+	result := h.db.Where("restaurant_id = ? AND status = ?", restaurantID, QueueStatusWaiting).Order("joined_at").Find(&entries)
+	return entries, result.Error
+}
+
+func (h *QueueHandler) SeatEntry(id uint) (*QueueEntry, error) {
+	now := time.Now()
+	result := h.db.Model(&QueueEntry{}).Where("id = ? AND status = ?", id, QueueStatusWaiting).
+		Updates(map[string]interface{}{"status": QueueStatusSeated, "seated_at": &now})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("no waiting queue entry with id %d", id)
+	}
+	return h.GetQueueEntry(id)
+}
+
+func (h *QueueHandler) LeaveQueue(id uint) error {
+	result := h.db.Model(&QueueEntry{}).Where("id = ? AND status = ?", id, QueueStatusWaiting).Update("status", QueueStatusLeft)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("no waiting queue entry with id %d", id)
+	}
+	return nil
+}
+
+// EstimateWait computes the expected wait for a party joining now, or for the
+// given queue entry when entryID is not zero. Parties ahead are served at the
+// pace one table frees up, derived from the average seating duration of the
+// last 30 days of reservations spread over the tables in use.
+func (h *QueueHandler) EstimateWait(restaurantID uint, entryID uint) (*WaitEstimate, error) {
+	estimate := WaitEstimate{RestaurantID: restaurantID}
+
+	if err := h.db.Model(&QueueEntry{}).
+		Where("restaurant_id = ? AND status = ?", restaurantID, QueueStatusWaiting).
+		Count(&estimate.PartiesWaiting).Error; err != nil {
+		return nil, err
+	}
+
+	partiesAhead := estimate.PartiesWaiting
+	if entryID != 0 {
+		entry, err := h.GetQueueEntry(entryID)
+		if err != nil {
+			return nil, err
+		}
+		if err := h.db.Model(&QueueEntry{}).
+			Where("restaurant_id = ? AND status = ? AND joined_at < ?", restaurantID, QueueStatusWaiting, entry.JoinedAt).
+			Count(&partiesAhead).Error; err != nil {
+			return nil, err
+		}
+		estimate.Position = partiesAhead + 1
+	}
+
+	since := time.Now().AddDate(0, 0, -30)
+
+	var history struct {
+		AverageSeconds *float64
+		Tables         int64
+	}
+	if err := h.db.Model(&Reservation{}).
+		Select("AVG(EXTRACT(EPOCH FROM (exit_time - date_time))) AS average_seconds, COUNT(DISTINCT table_num) AS tables").
+		Where("restaurant_id = ? AND date_time > ? AND exit_time > date_time", restaurantID, since).
+		Scan(&history).Error; err != nil {
+		return nil, err
+	}
+
+	seating := defaultSeatingDuration
+	if history.AverageSeconds != nil && *history.AverageSeconds > 0 {
+		seating = time.Duration(*history.AverageSeconds * float64(time.Second))
+	}
+
+	tables := history.Tables
+	if tables < 1 {
+		tables = 1
+	}
+
+	estimate.AverageSeatingMinutes = math.Round(seating.Minutes()*10) / 10
+	estimate.TablesTurningOver = tables
+	estimate.EstimatedWaitMinutes = int(math.Ceil(float64(partiesAhead) * seating.Minutes() / float64(tables)))
+
+	return &estimate, nil
+}
@@ -0,0 +1,106 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// receiptTemplate renders the body of a receipt, one PDF line per text line.
+var receiptTemplate = template.Must(template.New("receipt").Parse(`Receipt no. {{.Number}}
+Issued {{(.IssuedAt.In .Location).Format "2 Jan 2006 15:04"}}
+
+Restaurant: {{.Reservation.Restaurant.Name}}
+{{- if .Reservation.Restaurant.Address}}
+Address: {{.Reservation.Restaurant.Address}}
+{{- end}}
+
+Guest: {{.Reservation.User.Name}}
+Email: {{.Reservation.User.ContactAddress}}
+
+Reservation no. {{.Reservation.ID}}
+Date: {{(.Reservation.DateTime.In .Location).Format "Mon 2 Jan 2006 15:04"}}
+{{- if .Reservation.TableNum}}
+Table: {{.Reservation.TableNum}}
+{{- end}}
+
+{{- with .Reservation.ChargedDeposit}}
+Deposit: {{.Subtotal}}
+{{- if not .ServiceCharge.IsZero}}
+Service charge: {{.ServiceCharge}}
+{{- end}}
+{{- if not .VAT.IsZero}}
+VAT 7%: {{.VAT}}
+{{- end}}
+Total paid: {{.Total}}
+{{- end}}
+{{- if .Reservation.Restaurant.TaxID}}
+Tax ID: {{.Reservation.Restaurant.TaxID}}
+{{- end}}
+
+Thank you for dining with RedRice.`))
+
+// ReceiptNumber is the number printed on the receipt of the reservation.
+func ReceiptNumber(reservationID uint) string {
+	return fmt.Sprintf("RR-%06d", reservationID)
+}
+
+// ReceiptKey is where the receipt of the reservation is stored in S3.
+func ReceiptKey(reservationID uint) string {
+	return fmt.Sprintf("receipts/%s.pdf", ReceiptNumber(reservationID))
+}
+
+// IsPaid reports whether the reservation has a deposit that was collected,
+// which is the case once the guest showed up and the visit was completed.
+func (r *Reservation) IsPaid() bool {
+	return r.Status == ReservationStatusCompleted && r.Deposit.Amount > 0
+}
+
+// ChargedDeposit is the breakdown of the deposit paid for the reservation.
+// Reservations booked before tax settings existed only have the total.
+func (r *Reservation) ChargedDeposit() ChargeBreakdown {
+	if r.DepositBreakdown == nil {
+		zero := Money{Currency: r.Deposit.currency()}
+		return ChargeBreakdown{Subtotal: r.Deposit, ServiceCharge: zero, VAT: zero, Total: r.Deposit}
+	}
+	return *r.DepositBreakdown
+}
+
+// AfterFind links the reservation to its receipt once one was issued and
+// adds its local times when the restaurant was preloaded.
+func (r *Reservation) AfterFind(tx *gorm.DB) error {
+	if r.ReceiptIssuedAt != nil {
+		r.ReceiptURL = fmt.Sprintf("/api/v1/reservations/%d/receipt", r.ID)
+	}
+	if r.Restaurant.ID != 0 {
+		r.Local = NewLocalTimes(&r.Restaurant, r.DateTime, r.ExitTime)
+	}
+	return nil
+}
+
+// RenderReceipt fills the receipt template for the reservation, which must
+// have its user and restaurant loaded.
+func RenderReceipt(reservation *Reservation, issuedAt time.Time) ([]string, error) {
+	var body strings.Builder
+	err := receiptTemplate.Execute(&body, struct {
+		Number      string
+		IssuedAt    time.Time
+		Location    *time.Location
+		Reservation *Reservation
+	}{ReceiptNumber(reservation.ID), issuedAt, reservation.Restaurant.Location(), reservation})
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(body.String(), "\n"), nil
+}
+
+func (h *ReservationHandler) MarkReceiptIssued(reservation *Reservation, issuedAt time.Time) error {
+	if err := h.db.Model(&Reservation{}).Where("id = ?", reservation.ID).Update("receipt_issued_at", issuedAt).Error; err != nil {
+		return err
+	}
+	reservation.ReceiptIssuedAt = &issuedAt
+	return reservation.AfterFind(h.db)
+}
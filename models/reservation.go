@@ -1,6 +1,7 @@
 package models
 
 import (
+	"fmt"
 	"time"
 
 	"gorm.io/gorm"
@@ -15,7 +16,54 @@ type Reservation struct {
 	User         User       `gorm:"foreignKey:UserID" json:"user"`
 	RestaurantID uint       `json:"restaurantId"`
 	Restaurant   Restaurant `gorm:"foreignKey:RestaurantID" json:"restaurant"`
-	gorm.Model   `json:"-" swaggerignore:"true"`
+	Status       string     `json:"status" gorm:"index;default:pending" enums:"pending,confirmed,declined,cancelled,completed"`
+	Deposit      Money      `json:"deposit" gorm:"embedded;embeddedPrefix:deposit_"`
+	// DepositBreakdown splits the deposit into service charge and VAT as charged at booking
+	DepositBreakdown *ChargeBreakdown `json:"depositBreakdown,omitempty" gorm:"serializer:json"`
+	DepositRuleID    *uint            `json:"depositRuleId,omitempty"`
+	ReceiptIssuedAt  *time.Time       `json:"receiptIssuedAt,omitempty"`
+	ReceiptURL       string           `json:"receiptUrl,omitempty" gorm:"-"`
+	// RespondedAt is when the restaurant confirmed or declined the reservation
+	RespondedAt *time.Time `json:"respondedAt,omitempty"`
+	// Local is set when the restaurant is loaded with the reservation
+	Local      *LocalTimes `json:"local,omitempty" gorm:"-"`
+	gorm.Model `json:"-" swaggerignore:"true"`
+}
+
+const (
+	ReservationStatusPending   = "pending"
+	ReservationStatusConfirmed = "confirmed"
+	ReservationStatusDeclined  = "declined"
+	ReservationStatusCancelled = "cancelled"
+	ReservationStatusCompleted = "completed"
+)
+
+// reservationTransitions lists the statuses a reservation may move to from its current status.
+var reservationTransitions = map[string][]string{
+	ReservationStatusPending:   {ReservationStatusConfirmed, ReservationStatusDeclined, ReservationStatusCancelled},
+	ReservationStatusConfirmed: {ReservationStatusCompleted, ReservationStatusCancelled},
+}
+
+// ReservationStatuses lists every status a reservation can have.
+var ReservationStatuses = []string{ReservationStatusPending, ReservationStatusConfirmed, ReservationStatusDeclined, ReservationStatusCancelled, ReservationStatusCompleted}
+
+func IsValidReservationStatus(status string) bool {
+	return isOneOf(status, ReservationStatuses)
+}
+
+// isRestaurantResponse reports whether the transition is the restaurant
+// confirming or declining a pending reservation.
+func isRestaurantResponse(from, to string) bool {
+	return from == ReservationStatusPending && (to == ReservationStatusConfirmed || to == ReservationStatusDeclined)
+}
+
+func CanTransitionReservation(from, to string) bool {
+	for _, status := range reservationTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
 }
 
 type ReservationHandler struct {
@@ -28,6 +76,7 @@ func NewReservationHandler(db *gorm.DB) *ReservationHandler {
 
 func (h *ReservationHandler) CreateReservation(userID uint, reservation *Reservation) error {
 	reservation.UserID = userID
+	reservation.Status = ReservationStatusPending
 
 	if err := h.db.Create(reservation).Error; err != nil {
 		return err
@@ -38,7 +87,7 @@ func (h *ReservationHandler) CreateReservation(userID uint, reservation *Reserva
 
 func (h *ReservationHandler) GetReservation(id uint) (*Reservation, error) {
 	var reservation Reservation
-	result := h.db.Preload("User").Preload("Restaurant").First(&reservation, id)
+	result := h.db.Preload("User").Preload("Restaurant", withDeletedRestaurant).First(&reservation, id)
 	return &reservation, result.Error
 }
 
@@ -53,11 +102,150 @@ func (h *ReservationHandler) UpdateReservation(id uint, reservation *Reservation
 	return result.Error
 }
 
+// UpdateReservationStatus moves the reservation to the given status if the
+// transition is allowed from its current status.
+func (h *ReservationHandler) UpdateReservationStatus(id uint, status string) (*Reservation, error) {
+	reservation, err := h.GetReservation(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !CanTransitionReservation(reservation.Status, status) {
+		return nil, fmt.Errorf("cannot change reservation status from %s to %s", reservation.Status, status)
+	}
+
+	updates := map[string]interface{}{"status": status}
+	if isRestaurantResponse(reservation.Status, status) {
+		now := time.Now()
+		updates["responded_at"] = &now
+		reservation.RespondedAt = &now
+	}
+	result := h.db.Model(&Reservation{}).Where("id = ? AND status = ?", id, reservation.Status).Updates(updates)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("reservation status changed concurrently, please retry")
+	}
+
+	reservation.Status = status
+	return reservation, nil
+}
+
+// ReservationAction is one status change of a batch.
+type ReservationAction struct {
+	ReservationID uint   `json:"reservationId" example:"12"`
+	Status        string `json:"status" example:"confirmed" enums:"confirmed,declined"`
+}
+
+// ReservationActionResult reports the outcome of one action of a batch.
+type ReservationActionResult struct {
+	ReservationID uint   `json:"reservationId" example:"12"`
+	Status        string `json:"status" example:"confirmed"`
+	OK            bool   `json:"ok" example:"true"`
+	Error         string `json:"error,omitempty" example:"reservation is not pending"`
+}
+
+var ErrBatchRejected = fmt.Errorf("at least one action of the batch was rejected")
+
+// BatchUpdateReservationStatus confirms or declines pending reservations of
+// the restaurant in a single transaction. Every action gets a result; when
+// any of them is rejected nothing is changed and ErrBatchRejected is returned.
+func (h *ReservationHandler) BatchUpdateReservationStatus(restaurantID uint, actions []ReservationAction) ([]ReservationActionResult, []Reservation, error) {
+	results := make([]ReservationActionResult, len(actions))
+	var updated []Reservation
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		rejected := false
+		for i, action := range actions {
+			results[i] = ReservationActionResult{ReservationID: action.ReservationID, Status: action.Status}
+			reservation, err := updatePendingReservation(tx, restaurantID, action)
+			if err != nil {
+				results[i].Error = err.Error()
+				rejected = true
+				continue
+			}
+			results[i].OK = true
+			updated = append(updated, *reservation)
+		}
+		if rejected {
+			return ErrBatchRejected
+		}
+		return nil
+	})
+	if err != nil {
+		// The transaction was rolled back, the actions that went through are undone
+		for i := range results {
+			if results[i].OK {
+				results[i].OK = false
+				results[i].Error = "not applied, another action of the batch was rejected"
+			}
+		}
+		return results, nil, err
+	}
+	return results, updated, nil
+}
+
+func updatePendingReservation(tx *gorm.DB, restaurantID uint, action ReservationAction) (*Reservation, error) {
+	if action.Status != ReservationStatusConfirmed && action.Status != ReservationStatusDeclined {
+		return nil, fmt.Errorf("status must be confirmed or declined")
+	}
+
+	var reservation Reservation
+	if err := tx.Where("id = ? AND restaurant_id = ?", action.ReservationID, restaurantID).First(&reservation).Error; err != nil {
+		return nil, fmt.Errorf("reservation not found")
+	}
+	if reservation.Status != ReservationStatusPending {
+		return nil, fmt.Errorf("reservation is %s, not pending", reservation.Status)
+	}
+
+	now := time.Now()
+	result := tx.Model(&Reservation{}).Where("id = ? AND status = ?", reservation.ID, ReservationStatusPending).
+		Updates(map[string]interface{}{"status": action.Status, "responded_at": &now})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("reservation status changed concurrently, please retry")
+	}
+
+	reservation.Status = action.Status
+	reservation.RespondedAt = &now
+	return &reservation, nil
+}
+
 func (h *ReservationHandler) DeleteReservation(id uint) error {
 	result := h.db.Delete(&Reservation{}, id)
 	return result.Error
 }
 
+// ReservationFilter narrows down the reservations of a user, zero values
+// match everything. The range [From, To) applies to the reservation time.
+type ReservationFilter struct {
+	Statuses []string
+	From     time.Time
+	To       time.Time
+}
+
+// GetUserReservations returns the reservations of the user matching the
+// filter with their restaurant, latest first.
+func (h *ReservationHandler) GetUserReservations(userID uint, filter ReservationFilter) ([]Reservation, error) {
+	query := h.db.Preload("Restaurant", withDeletedRestaurant).Where("user_id = ?", userID)
+	if len(filter.Statuses) > 0 {
+		query = query.Where("status IN ?", filter.Statuses)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("date_time >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("date_time < ?", filter.To)
+	}
+
+	var reservations []Reservation
+	result := query.Order("date_time DESC, id DESC").Find(&reservations)
+	return reservations, result.Error
+}
+
 func (handler *ReservationHandler) GetReservationsByUserID(userID uint) ([]Reservation, error) {
 	var reservations []Reservation
 	result := handler.db.Preload("User").Preload("Restaurant").Where("user_id = ?", userID).Find(&reservations)
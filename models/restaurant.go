@@ -2,26 +2,159 @@ package models
 
 import (
 	"fmt"
+	"time"
 
 	"gorm.io/gorm"
 )
 
 type Restaurant struct {
-	ID           uint     `gorm:"primaryKey"`
-	Name         string   `json:"name"`
-	Address      string   `json:"address"`
-	Telephone    string   `json:"telephone"`
-	OpenTime     string   `json:"openTime"`
-	CloseTime    string   `json:"closeTime"`
-	Instagram    string   `json:"instagram"`
-	Facebook     string   `json:"facebook"`
-	Description  string   `json:"description"`
-	Rating       *float64 `json:"rating" gorm:"default:0" validate:"required,min=0"`
-	CommentCount *float64 `json:"commentCount" gorm:"default:0" validate:"required,min=0"`
-	ImageURL     string   `json:"imageUrl"`
-	gorm.Model   `json:"-" swaggerignore:"true"`
+	ID          uint     `gorm:"primaryKey"`
+	Name        string   `json:"name"`
+	Address     string   `json:"address"`
+	Telephone   string   `json:"telephone"`
+	OpenTime    string   `json:"openTime"`
+	CloseTime   string   `json:"closeTime"`
+	Instagram   string   `json:"instagram"`
+	Facebook    string   `json:"facebook"`
+	Description string   `json:"description"`
+	PriceRange  int      `json:"priceRange" gorm:"default:0" example:"2"`
+	Latitude    *float64 `json:"latitude" gorm:"index:idx_restaurants_location" example:"13.7563"`
+	Longitude   *float64 `json:"longitude" gorm:"index:idx_restaurants_location" example:"100.5018"`
+	// Distance is the distance in kilometers to the point of a nearby search
+	Distance             *float64          `json:"-" gorm:"->;-:migration" swaggerignore:"true"`
+	Rating               *float64          `json:"rating" gorm:"default:0" validate:"required,min=0"`
+	CommentCount         *float64          `json:"commentCount" gorm:"default:0" validate:"required,min=0"`
+	VerifiedRating       float64           `json:"verifiedRating" gorm:"default:0"`
+	VerifiedCommentCount int64             `json:"verifiedCommentCount" gorm:"default:0"`
+	FavoriteCount        int64             `json:"favoriteCount" gorm:"default:0"`
+	ImageURL             string            `json:"imageUrl"`
+	Verified             bool              `json:"verified" gorm:"default:false"`
+	Status               string            `json:"status" gorm:"index;default:active" enums:"draft,pending,active,hidden,suspended"`
+	StatusReason         string            `json:"statusReason,omitempty"`
+	StatusChangedAt      *time.Time        `json:"statusChangedAt,omitempty"`
+	MinNoticeMinutes     int               `json:"minNoticeMinutes" gorm:"default:0"`
+	MaxAdvanceDays       int               `json:"maxAdvanceDays" gorm:"default:0"`
+	RequireVerifiedPhone bool              `json:"requireVerifiedPhone" gorm:"default:false"`
+	Deposit              Money             `json:"deposit" gorm:"embedded;embeddedPrefix:deposit_"`
+	VATRegistered        bool              `json:"vatRegistered" gorm:"default:false"`
+	TaxID                string            `json:"taxId"`
+	ServiceChargeRate    float64           `json:"serviceChargeRate" gorm:"default:0"`
+	PricesIncludeTax     bool              `json:"pricesIncludeTax" gorm:"default:false"`
+	Timezone             string            `json:"timezone" gorm:"default:Asia/Bangkok" example:"Asia/Bangkok"`
+	Warnings             []string          `json:"warnings,omitempty" gorm:"-"`
+	Tags                 []TagCount        `json:"tags,omitempty" gorm:"-"`
+	Categories           []Category        `json:"categories,omitempty" gorm:"many2many:restaurant_categories"`
+	Gallery              []RestaurantImage `json:"gallery,omitempty" gorm:"foreignKey:RestaurantID" swaggerignore:"true"`
+	Menu                 []MenuCategory    `json:"menu,omitempty" gorm:"-" swaggerignore:"true"`
+	gorm.Model           `json:"-" swaggerignore:"true"`
 }
 
+// RestaurantResponse is a restaurant as returned by the API, without the
+// bookkeeping columns of the row.
+type RestaurantResponse struct {
+	ID          uint     `json:"ID" example:"7"`
+	Name        string   `json:"name"`
+	Address     string   `json:"address"`
+	Telephone   string   `json:"telephone"`
+	OpenTime    string   `json:"openTime" example:"10:00"`
+	CloseTime   string   `json:"closeTime" example:"22:00"`
+	Instagram   string   `json:"instagram"`
+	Facebook    string   `json:"facebook"`
+	Description string   `json:"description"`
+	PriceRange  int      `json:"priceRange" example:"2"`
+	Latitude    *float64 `json:"latitude,omitempty" example:"13.7563"`
+	Longitude   *float64 `json:"longitude,omitempty" example:"100.5018"`
+	// Distance is the distance in kilometers to the point of a nearby search
+	Distance             *float64   `json:"distance,omitempty" example:"1.2"`
+	Rating               float64    `json:"rating" example:"4.5"`
+	CommentCount         float64    `json:"commentCount" example:"12"`
+	VerifiedRating       float64    `json:"verifiedRating" example:"4.7"`
+	VerifiedCommentCount int64      `json:"verifiedCommentCount" example:"8"`
+	FavoriteCount        int64      `json:"favoriteCount" example:"30"`
+	ImageURL             string     `json:"imageUrl"`
+	Verified             bool       `json:"verified"`
+	Status               string     `json:"status" example:"active" enums:"draft,pending,active,hidden,suspended"`
+	StatusReason         string     `json:"statusReason,omitempty" example:"Photos need to show the restaurant"`
+	MinNoticeMinutes     int        `json:"minNoticeMinutes"`
+	MaxAdvanceDays       int        `json:"maxAdvanceDays"`
+	RequireVerifiedPhone bool       `json:"requireVerifiedPhone"`
+	Deposit              Money      `json:"deposit"`
+	VATRegistered        bool       `json:"vatRegistered"`
+	TaxID                string     `json:"taxId"`
+	ServiceChargeRate    float64    `json:"serviceChargeRate"`
+	PricesIncludeTax     bool       `json:"pricesIncludeTax"`
+	Timezone             string     `json:"timezone" example:"Asia/Bangkok"`
+	Warnings             []string   `json:"warnings,omitempty"`
+	Tags                 []TagCount `json:"tags,omitempty"`
+	Categories           []Category `json:"categories"`
+	// Gallery lists the gallery images in their order
+	Gallery []RestaurantImage `json:"gallery"`
+	// Menu is only included when requested
+	Menu []MenuCategory `json:"menu,omitempty"`
+}
+
+func (r *Restaurant) Response() RestaurantResponse {
+	response := RestaurantResponse{
+		ID:                   r.ID,
+		Name:                 r.Name,
+		Address:              r.Address,
+		Telephone:            r.Telephone,
+		OpenTime:             r.OpenTime,
+		CloseTime:            r.CloseTime,
+		Instagram:            r.Instagram,
+		Facebook:             r.Facebook,
+		Description:          r.Description,
+		PriceRange:           r.PriceRange,
+		Latitude:             r.Latitude,
+		Longitude:            r.Longitude,
+		Distance:             r.Distance,
+		VerifiedRating:       r.VerifiedRating,
+		VerifiedCommentCount: r.VerifiedCommentCount,
+		FavoriteCount:        r.FavoriteCount,
+		ImageURL:             r.ImageURL,
+		Verified:             r.Verified,
+		Status:               r.Status,
+		StatusReason:         r.StatusReason,
+		MinNoticeMinutes:     r.MinNoticeMinutes,
+		MaxAdvanceDays:       r.MaxAdvanceDays,
+		RequireVerifiedPhone: r.RequireVerifiedPhone,
+		Deposit:              r.Deposit,
+		VATRegistered:        r.VATRegistered,
+		TaxID:                r.TaxID,
+		ServiceChargeRate:    r.ServiceChargeRate,
+		PricesIncludeTax:     r.PricesIncludeTax,
+		Timezone:             r.Timezone,
+		Warnings:             r.Warnings,
+		Tags:                 r.Tags,
+		Categories:           r.Categories,
+		Gallery:              r.Gallery,
+		Menu:                 r.Menu,
+	}
+	if r.Rating != nil {
+		response.Rating = *r.Rating
+	}
+	if r.CommentCount != nil {
+		response.CommentCount = *r.CommentCount
+	}
+	if response.Categories == nil {
+		response.Categories = []Category{}
+	}
+	if response.Gallery == nil {
+		response.Gallery = []RestaurantImage{}
+	}
+	return response
+}
+
+func RestaurantResponses(restaurants []Restaurant) []RestaurantResponse {
+	responses := make([]RestaurantResponse, len(restaurants))
+	for i := range restaurants {
+		responses[i] = restaurants[i].Response()
+	}
+	return responses
+}
+
+var ErrRestaurantNotDeleted = fmt.Errorf("restaurant is not deleted")
+
 type RestaurantHandler struct {
 	db *gorm.DB
 }
@@ -34,16 +167,72 @@ func (h *RestaurantHandler) CreateRestaurant(restaurant *Restaurant) error {
 	return h.db.Create(restaurant).Error
 }
 
+// withListing preloads what restaurant responses show besides the row.
+func withListing(db *gorm.DB) *gorm.DB {
+	return db.Preload("Categories").Preload("Gallery", galleryOrder)
+}
+
 func (h *RestaurantHandler) GetRestaurant(id uint) (*Restaurant, error) {
 	var restaurant Restaurant
-	result := h.db.First(&restaurant, id)
+	result := h.db.Scopes(withListing).First(&restaurant, id)
 	return &restaurant, result.Error
 }
 
-func (h *RestaurantHandler) GetRestaurants() ([]Restaurant, error) {
+// restaurantSorts maps the accepted sort keys to their ORDER BY clause.
+var restaurantSorts = map[string]string{
+	"name":           "name, id",
+	"newest":         "created_at DESC, id DESC",
+	"rating":         "rating DESC, id",
+	"verifiedRating": "verified_rating DESC, verified_comment_count DESC, id",
+	"favorites":      "favorite_count DESC, id",
+	// Restaurants without a price range come last either way
+	"price":     "price_range = 0, price_range, id",
+	"priceDesc": "price_range DESC, id",
+}
+
+func IsValidRestaurantSort(sort string) bool {
+	_, ok := restaurantSorts[sort]
+	return sort == "" || ok
+}
+
+// GetRestaurants returns one page of the restaurants matching the filter in
+// the order of the sort key, by ID when empty, with the number of matching
+// restaurants across all pages.
+func (h *RestaurantHandler) GetRestaurants(filter RestaurantFilter, sort string, page, limit int) ([]Restaurant, int64, error) {
+	query := h.db.Model(&Restaurant{}).Scopes(Listed).Scopes(filter.Scopes()...)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	order, ok := restaurantSorts[sort]
+	if !ok {
+		order = "id"
+	}
 	var restaurants []Restaurant
-	result := h.db.Find(&restaurants)
-	return restaurants, result.Error
+	result := query.Scopes(withListing).Order(order).Offset((page - 1) * limit).Limit(limit).Find(&restaurants)
+	return restaurants, total, result.Error
+}
+
+// RecomputeVerifiedRating refreshes the rating computed only from reviews tied
+// to completed reservations.
+func (h *RestaurantHandler) RecomputeVerifiedRating(id uint) error {
+	var stats struct {
+		Average float64
+		Count   int64
+	}
+	if err := h.db.Model(&Comment{}).
+		Select("COALESCE(AVG(rating), 0) AS average, COUNT(*) AS count").
+		Where("restaurant_id = ? AND verified", id).
+		Scan(&stats).Error; err != nil {
+		return err
+	}
+
+	return h.db.Model(&Restaurant{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"verified_rating":        stats.Average,
+		"verified_comment_count": stats.Count,
+	}).Error
 }
 
 func (h *RestaurantHandler) UpdateRestaurant(id uint, restaurant *Restaurant) error {
@@ -51,9 +240,79 @@ func (h *RestaurantHandler) UpdateRestaurant(id uint, restaurant *Restaurant) er
 	return result.Error
 }
 
+// RestaurantPatch lists the fields of a partial restaurant update, nil fields
+// are left unchanged. Latitude and Longitude are set together, ClearLocation
+// removes both.
+type RestaurantPatch struct {
+	Name          *string
+	Address       *string
+	Telephone     *string
+	Description   *string
+	Facebook      *string
+	Instagram     *string
+	OpenTime      *string
+	CloseTime     *string
+	Timezone      *string
+	PriceRange    *int
+	Latitude      *float64
+	Longitude     *float64
+	ClearLocation bool
+}
+
+// PatchRestaurant writes only the fields set in the patch, including empty
+// values, and returns the updated restaurant. Changed listing fields are
+// recorded as a new version like UpdateRestaurantWithHistory does.
+func (h *RestaurantHandler) PatchRestaurant(id uint, changedBy uint, patch RestaurantPatch) (*Restaurant, error) {
+	listing := map[string]string{}
+	for column, value := range map[string]*string{
+		"name":        patch.Name,
+		"address":     patch.Address,
+		"telephone":   patch.Telephone,
+		"open_time":   patch.OpenTime,
+		"close_time":  patch.CloseTime,
+		"instagram":   patch.Instagram,
+		"facebook":    patch.Facebook,
+		"description": patch.Description,
+	} {
+		if value != nil {
+			listing[column] = *value
+		}
+	}
+
+	updates := map[string]interface{}{}
+	if patch.Timezone != nil {
+		updates["timezone"] = *patch.Timezone
+	}
+	if patch.PriceRange != nil {
+		listing["price_range"] = listingPriceRange(*patch.PriceRange)
+	}
+	if patch.Latitude != nil && patch.Longitude != nil {
+		updates["latitude"] = *patch.Latitude
+		updates["longitude"] = *patch.Longitude
+	} else if patch.ClearLocation {
+		updates["latitude"] = nil
+		updates["longitude"] = nil
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := applyListingChanges(tx, id, changedBy, listing, nil); err != nil {
+			return err
+		}
+		if len(updates) == 0 {
+			return nil
+		}
+		return tx.Model(&Restaurant{}).Where("id = ?", id).Updates(updates).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return h.GetRestaurant(id)
+}
+
+// DeleteRestaurant soft deletes the restaurant, which hides it from every
+// query while its reservations and comments are kept.
 func (h *RestaurantHandler) DeleteRestaurant(id uint) error {
-	// Bypass soft delete and force a hard delete
-	result := h.db.Unscoped().Where("id = ?", id).Delete(&Restaurant{})
+	result := h.db.Where("id = ?", id).Delete(&Restaurant{})
 	if result.Error != nil {
 		return result.Error
 	}
@@ -62,3 +321,51 @@ func (h *RestaurantHandler) DeleteRestaurant(id uint) error {
 	}
 	return nil
 }
+
+// RestoreRestaurant brings back a soft deleted restaurant.
+func (h *RestaurantHandler) RestoreRestaurant(id uint) (*Restaurant, error) {
+	var restaurant Restaurant
+	if err := h.db.Unscoped().First(&restaurant, id).Error; err != nil {
+		return nil, err
+	}
+	if !restaurant.DeletedAt.Valid {
+		return nil, ErrRestaurantNotDeleted
+	}
+
+	if err := h.db.Unscoped().Model(&Restaurant{}).Where("id = ?", id).Update("deleted_at", nil).Error; err != nil {
+		return nil, err
+	}
+	return h.GetRestaurant(id)
+}
+
+// withDeletedRestaurant preloads the restaurant of a reservation or comment
+// even when it was deleted, so the history of guests keeps the place.
+func withDeletedRestaurant(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
+
+// BookingPolicy holds the booking rules an owner can set for their restaurant.
+type BookingPolicy struct {
+	MinNoticeMinutes     int
+	MaxAdvanceDays       int
+	RequireVerifiedPhone bool
+	Deposit              Money
+}
+
+func (h *RestaurantHandler) UpdateBookingPolicy(id uint, policy BookingPolicy) (*Restaurant, error) {
+	// Use a map so that zero values are written as well
+	result := h.db.Model(&Restaurant{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"min_notice_minutes":     policy.MinNoticeMinutes,
+		"max_advance_days":       policy.MaxAdvanceDays,
+		"require_verified_phone": policy.RequireVerifiedPhone,
+		"deposit_minor":          policy.Deposit.Amount,
+		"deposit_currency":       policy.Deposit.Currency,
+	})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("no restaurant found with id %d", id)
+	}
+	return h.GetRestaurant(id)
+}
@@ -0,0 +1,210 @@
+package models
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	ClaimStatusPending  = "pending"
+	ClaimStatusApproved = "approved"
+	ClaimStatusRejected = "rejected"
+)
+
+var ErrClaimPending = fmt.Errorf("you already have a pending claim for this restaurant")
+var ErrClaimReviewed = fmt.Errorf("the claim was already reviewed")
+var ErrClaimByAdmin = fmt.Errorf("admins already manage every restaurant")
+var ErrClaimantManagesOther = fmt.Errorf("the claimant already manages another restaurant")
+
+// claimSupersededReason is the rejection reason of the pending claims of a
+// restaurant left when another claim for it is approved.
+const claimSupersededReason = "Another claim for this restaurant was approved"
+
+// RestaurantClaim is the request of a user to manage an existing restaurant,
+// backed by documents proving they run the business. Approving it makes the
+// user the owner of the restaurant and marks the restaurant as verified.
+type RestaurantClaim struct {
+	ID           uint   `gorm:"primaryKey" json:"id"`
+	RestaurantID uint   `gorm:"index" json:"restaurantId" example:"7"`
+	UserID       uint   `gorm:"index" json:"userId" example:"42"`
+	Message      string `json:"message" example:"I am the manager of this restaurant"`
+	// DocumentKeys are the objects of the proof documents in the bucket
+	DocumentKeys []string `gorm:"serializer:json" json:"-" swaggerignore:"true"`
+	// Documents are short-lived links to the proof documents, only listed to admins
+	Documents       []string   `gorm:"-" json:"documents,omitempty"`
+	Status          string     `gorm:"index;default:pending" json:"status" enums:"pending,approved,rejected"`
+	RejectionReason string     `json:"rejectionReason,omitempty"`
+	ReviewedBy      *uint      `json:"reviewedBy,omitempty"`
+	ReviewedAt      *time.Time `json:"reviewedAt,omitempty"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	UpdatedAt       time.Time  `json:"-" swaggerignore:"true"`
+}
+
+// ClaimDocumentKey names a new proof document of a claim for the restaurant.
+func ClaimDocumentKey(restaurantID uint, fileName string) string {
+	return fmt.Sprintf("claims/restaurant-%d/%s%s", restaurantID, uuid.New().String(), filepath.Ext(fileName))
+}
+
+type RestaurantClaimHandler struct {
+	db *gorm.DB
+}
+
+func NewRestaurantClaimHandler(db *gorm.DB) *RestaurantClaimHandler {
+	return &RestaurantClaimHandler{db}
+}
+
+// CreateClaim submits the claim, unless the user already has a pending claim
+// for the restaurant.
+func (h *RestaurantClaimHandler) CreateClaim(claim *RestaurantClaim) error {
+	return h.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&Restaurant{}).Where("id = ?", claim.RestaurantID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return ErrRestaurantNotFound
+		}
+
+		if err := tx.Model(&RestaurantClaim{}).
+			Where("restaurant_id = ? AND user_id = ? AND status = ?", claim.RestaurantID, claim.UserID, ClaimStatusPending).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrClaimPending
+		}
+
+		claim.Status = ClaimStatusPending
+		return tx.Create(claim).Error
+	})
+}
+
+func (h *RestaurantClaimHandler) GetClaim(id uint) (*RestaurantClaim, error) {
+	var claim RestaurantClaim
+	err := h.db.First(&claim, id).Error
+	return &claim, err
+}
+
+// GetClaims returns the claims oldest first so admins review them in order,
+// only the ones with the status unless it is empty.
+func (h *RestaurantClaimHandler) GetClaims(status string, limit, offset int) ([]RestaurantClaim, int64, error) {
+	query := h.db.Model(&RestaurantClaim{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var claims []RestaurantClaim
+	err := query.Order("id").Limit(limit).Offset(offset).Find(&claims).Error
+	return claims, total, err
+}
+
+// GetUserClaims returns the claims the user submitted, newest first.
+func (h *RestaurantClaimHandler) GetUserClaims(userID uint) ([]RestaurantClaim, error) {
+	claims := []RestaurantClaim{}
+	err := h.db.Where("user_id = ?", userID).Order("id DESC").Find(&claims).Error
+	return claims, err
+}
+
+// reviewClaim locks the claim and checks it is still pending.
+func reviewClaim(tx *gorm.DB, id uint) (*RestaurantClaim, error) {
+	var claim RestaurantClaim
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&claim, id).Error; err != nil {
+		return nil, err
+	}
+	if claim.Status != ClaimStatusPending {
+		return nil, ErrClaimReviewed
+	}
+	return &claim, nil
+}
+
+// ApproveClaim makes the claimant the owner of the restaurant and marks the
+// restaurant as verified. Claimants managing another restaurant are refused
+// with ErrClaimantManagesOther. The accounts managing the restaurant before
+// are made customers and the other pending claims for it are rejected.
+// Sessions of the claimant and the former owners issued before are revoked
+// so the new roles apply right away.
+func (h *RestaurantClaimHandler) ApproveClaim(id, adminID uint, now time.Time) (*RestaurantClaim, error) {
+	var approved *RestaurantClaim
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		claim, err := reviewClaim(tx, id)
+		if err != nil {
+			return err
+		}
+
+		var user User
+		if err := tx.First(&user, claim.UserID).Error; err != nil {
+			return err
+		}
+		if user.Role == RoleAdmin {
+			return ErrClaimByAdmin
+		}
+		if user.RestaurantId != 0 && user.RestaurantId != claim.RestaurantID {
+			return ErrClaimantManagesOther
+		}
+
+		if err := tx.Model(&User{}).Where("restaurant_id = ? AND id <> ? AND role = ?", claim.RestaurantID, user.ID, RoleOwner).
+			Updates(map[string]interface{}{
+				"role":               RoleCustomer,
+				"restaurant_id":      0,
+				"tokens_valid_after": now,
+			}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&User{}).Where("id = ?", user.ID).Updates(map[string]interface{}{
+			"role":               RoleOwner,
+			"restaurant_id":      claim.RestaurantID,
+			"tokens_valid_after": now,
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&Restaurant{}).Where("id = ?", claim.RestaurantID).Update("verified", true).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&RestaurantClaim{}).
+			Where("restaurant_id = ? AND status = ? AND id <> ?", claim.RestaurantID, ClaimStatusPending, claim.ID).
+			Updates(map[string]interface{}{
+				"status":           ClaimStatusRejected,
+				"rejection_reason": claimSupersededReason,
+				"reviewed_by":      adminID,
+				"reviewed_at":      now,
+			}).Error; err != nil {
+			return err
+		}
+
+		claim.Status = ClaimStatusApproved
+		claim.ReviewedBy = &adminID
+		claim.ReviewedAt = &now
+		approved = claim
+		return tx.Save(claim).Error
+	})
+	return approved, err
+}
+
+// RejectClaim closes the claim without changing the restaurant.
+func (h *RestaurantClaimHandler) RejectClaim(id, adminID uint, reason string, now time.Time) (*RestaurantClaim, error) {
+	var rejected *RestaurantClaim
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		claim, err := reviewClaim(tx, id)
+		if err != nil {
+			return err
+		}
+
+		claim.Status = ClaimStatusRejected
+		claim.RejectionReason = reason
+		claim.ReviewedBy = &adminID
+		claim.ReviewedAt = &now
+		rejected = claim
+		return tx.Save(claim).Error
+	})
+	return rejected, err
+}
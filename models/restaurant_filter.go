@@ -0,0 +1,84 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Price ranges go from budget to fine dining, 0 means the restaurant did not set one.
+const (
+	MinPriceRange = 1
+	MaxPriceRange = 4
+)
+
+func IsValidPriceRange(priceRange int) bool {
+	return priceRange >= MinPriceRange && priceRange <= MaxPriceRange
+}
+
+// RestaurantFilter narrows down the restaurant list, zero fields do not filter.
+type RestaurantFilter struct {
+	MinRating     float64
+	MinPriceRange int
+	MaxPriceRange int
+	// Category is the slug of a category
+	Category string
+	// OpenAt keeps the restaurants whose opening hours include the time
+	OpenAt *time.Time
+}
+
+// Scopes returns the scopes of the fields that are set.
+func (f RestaurantFilter) Scopes() []func(*gorm.DB) *gorm.DB {
+	var scopes []func(*gorm.DB) *gorm.DB
+	if f.MinRating > 0 {
+		scopes = append(scopes, RatedAtLeast(f.MinRating))
+	}
+	if f.MinPriceRange > 0 || f.MaxPriceRange > 0 {
+		scopes = append(scopes, InPriceRange(f.MinPriceRange, f.MaxPriceRange))
+	}
+	if f.Category != "" {
+		scopes = append(scopes, InCategory(f.Category))
+	}
+	if f.OpenAt != nil {
+		scopes = append(scopes, OpenAt(*f.OpenAt))
+	}
+	return scopes
+}
+
+// RatedAtLeast keeps the restaurants with an average rating of at least min.
+func RatedAtLeast(min float64) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("rating >= ?", min)
+	}
+}
+
+// InPriceRange keeps the restaurants whose price range lies between min and
+// max, inclusive. A zero bound is open. Restaurants without a price range are
+// left out.
+func InPriceRange(min, max int) func(*gorm.DB) *gorm.DB {
+	if min < MinPriceRange {
+		min = MinPriceRange
+	}
+	if max == 0 || max > MaxPriceRange {
+		max = MaxPriceRange
+	}
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("price_range BETWEEN ? AND ?", min, max)
+	}
+}
+
+// OpenAt keeps the restaurants open at the time in their own time zone,
+// including the ones closing after midnight. Special hours of the day replace
+// the regular ones. Blackouts are not taken into account, and restaurants
+// without valid opening hours are left out.
+func OpenAt(at time.Time) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(`CASE
+			WHEN EXISTS (SELECT 1 `+specialHoursToday+`) THEN EXISTS (SELECT 1 `+specialHoursToday+` AND NOT special_hours.closed AND `+
+			openDuring("special_hours.open_time", "special_hours.close_time")+`)
+			ELSE `+openDuring("restaurants.open_time", "restaurants.close_time")+` END`,
+			sql.Named("local", localClock(at, "HH24:MI")),
+			sql.Named("date", localClock(at, "YYYY-MM-DD")))
+	}
+}
@@ -0,0 +1,187 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// FieldChange is a single field edit, keyed by column name.
+type FieldChange struct {
+	Field string `json:"field" example:"name"`
+	Old   string `json:"old" example:"RedRice"`
+	New   string `json:"new" example:"RedRice Bistro"`
+}
+
+// RestaurantVersion records who changed which listing fields of a restaurant
+// and when. Each update or revert of the listing creates one version.
+type RestaurantVersion struct {
+	ID           uint          `gorm:"primaryKey" json:"id"`
+	RestaurantID uint          `gorm:"index" json:"restaurantId"`
+	ChangedBy    uint          `json:"changedBy"`
+	RevertedTo   *uint         `json:"revertedTo,omitempty"`
+	Changes      []FieldChange `gorm:"serializer:json" json:"changes"`
+	CreatedAt    time.Time     `json:"createdAt"`
+}
+
+// restaurantListingColumns are the versioned columns of a restaurant.
+var restaurantListingColumns = []string{"name", "address", "telephone", "open_time", "close_time", "instagram", "facebook", "description", "price_range", "image_url"}
+
+// restaurantListingFields returns the versioned fields of the restaurant by
+// column name. Ratings are derived from comments and are not versioned. The
+// price range is written as a number, empty when not set.
+func restaurantListingFields(r *Restaurant) map[string]string {
+	return map[string]string{
+		"name":        r.Name,
+		"address":     r.Address,
+		"telephone":   r.Telephone,
+		"open_time":   r.OpenTime,
+		"close_time":  r.CloseTime,
+		"instagram":   r.Instagram,
+		"facebook":    r.Facebook,
+		"description": r.Description,
+		"price_range": listingPriceRange(r.PriceRange),
+		"image_url":   r.ImageURL,
+	}
+}
+
+func listingPriceRange(priceRange int) string {
+	if priceRange == 0 {
+		return ""
+	}
+	return strconv.Itoa(priceRange)
+}
+
+// listingValue converts the versioned value of the column to the type of the
+// column.
+func listingValue(column, value string) interface{} {
+	if column == "price_range" {
+		priceRange, _ := strconv.Atoi(value)
+		return priceRange
+	}
+	return value
+}
+
+// diffListing lists the fields whose value in updates differs from current.
+// Empty values in updates are skipped, matching how gorm applies struct
+// updates.
+func diffListing(current, updates map[string]string) []FieldChange {
+	var changes []FieldChange
+	for _, field := range restaurantListingColumns {
+		if value := updates[field]; value != "" && value != current[field] {
+			changes = append(changes, FieldChange{Field: field, Old: current[field], New: value})
+		}
+	}
+	return changes
+}
+
+// UpdateRestaurantWithHistory applies the update like UpdateRestaurant and
+// records the changed listing fields as a new version.
+func (h *RestaurantHandler) UpdateRestaurantWithHistory(id uint, changedBy uint, restaurant *Restaurant) error {
+	return h.db.Transaction(func(tx *gorm.DB) error {
+		var current Restaurant
+		if err := tx.First(&current, id).Error; err != nil {
+			return fmt.Errorf("no restaurant found with id %d", id)
+		}
+
+		changes := diffListing(restaurantListingFields(&current), restaurantListingFields(restaurant))
+		if len(changes) > 0 {
+			version := RestaurantVersion{RestaurantID: id, ChangedBy: changedBy, Changes: changes}
+			if err := tx.Create(&version).Error; err != nil {
+				return err
+			}
+			if err := addListingFeedItem(tx, id, changedBy, changes); err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&Restaurant{}).Where("id = ?", id).Updates(restaurant).Error
+	})
+}
+
+func (h *RestaurantHandler) GetRestaurantHistory(id uint, limit, offset int) ([]RestaurantVersion, int64, error) {
+	var versions []RestaurantVersion
+	var total int64
+
+	query := h.db.Model(&RestaurantVersion{}).Where("restaurant_id = ?", id)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	result := query.Order("id DESC").Limit(limit).Offset(offset).Find(&versions)
+	return versions, total, result.Error
+}
+
+// RevertRestaurant restores the listing to the state it had right after the
+// given version by undoing every later change. The revert itself is recorded
+// as a new version.
+func (h *RestaurantHandler) RevertRestaurant(id uint, versionID uint, changedBy uint) (*Restaurant, error) {
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		var target RestaurantVersion
+		if err := tx.Where("id = ? AND restaurant_id = ?", versionID, id).First(&target).Error; err != nil {
+			return fmt.Errorf("no version %d found for restaurant %d", versionID, id)
+		}
+
+		var later []RestaurantVersion
+		if err := tx.Where("restaurant_id = ? AND id > ?", id, versionID).Order("id ASC").Find(&later).Error; err != nil {
+			return err
+		}
+
+		// The oldest later change of a field holds its value as of the target version
+		restored := map[string]string{}
+		for _, version := range later {
+			for _, change := range version.Changes {
+				if _, ok := restored[change.Field]; !ok {
+					restored[change.Field] = change.Old
+				}
+			}
+		}
+
+		return applyListingChanges(tx, id, changedBy, restored, &target.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return h.GetRestaurant(id)
+}
+
+// applyListingChanges writes the columns to the restaurant and records the
+// difference as a new version. Unlike struct updates, empty values are
+// written as well.
+func applyListingChanges(tx *gorm.DB, restaurantID uint, changedBy uint, columns map[string]string, revertedTo *uint) error {
+	var current Restaurant
+	if err := tx.First(&current, restaurantID).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("%w with id %d", ErrRestaurantNotFound, restaurantID)
+	} else if err != nil {
+		return err
+	}
+
+	fields := restaurantListingFields(&current)
+	updates := map[string]interface{}{}
+	var changes []FieldChange
+	for _, column := range restaurantListingColumns {
+		value, ok := columns[column]
+		if ok && fields[column] != value {
+			updates[column] = listingValue(column, value)
+			changes = append(changes, FieldChange{Field: column, Old: fields[column], New: value})
+		}
+	}
+
+	if len(changes) == 0 {
+		return nil
+	}
+
+	version := RestaurantVersion{RestaurantID: restaurantID, ChangedBy: changedBy, RevertedTo: revertedTo, Changes: changes}
+	if err := tx.Create(&version).Error; err != nil {
+		return err
+	}
+	if err := addListingFeedItem(tx, restaurantID, changedBy, changes); err != nil {
+		return err
+	}
+
+	return tx.Model(&Restaurant{}).Where("id = ?", restaurantID).Updates(updates).Error
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// importBatchSize is the number of restaurants inserted per statement by
+// ImportRestaurants.
+const importBatchSize = 100
+
+// Validate checks the fields of a restaurant entered without the form, e.g.
+// imported from a spreadsheet, returning the first problem found.
+func (r *Restaurant) Validate() error {
+	switch {
+	case strings.TrimSpace(r.Name) == "":
+		return fmt.Errorf("name is required")
+	case r.OpenTime != "" && !clockTimePattern.MatchString(r.OpenTime):
+		return fmt.Errorf("openTime must be a time of the day in HH:MM format")
+	case r.CloseTime != "" && !clockTimePattern.MatchString(r.CloseTime):
+		return fmt.Errorf("closeTime must be a time of the day in HH:MM format")
+	case r.PriceRange != 0 && !IsValidPriceRange(r.PriceRange):
+		return fmt.Errorf("priceRange must be a number from 1 (budget) to 4 (fine dining)")
+	case (r.Latitude == nil) != (r.Longitude == nil):
+		return fmt.Errorf("latitude and longitude must be set together")
+	case r.Latitude != nil && !IsValidCoordinate(*r.Latitude, *r.Longitude):
+		return fmt.Errorf("latitude must be from -90 to 90 and longitude from -180 to 180")
+	case r.Timezone != "" && !IsValidTimezone(r.Timezone):
+		return fmt.Errorf("timezone must be an IANA name such as Asia/Bangkok")
+	}
+	return nil
+}
+
+// ImportRestaurants inserts the restaurants with their categories in batches
+// of importBatchSize. Either all of them are saved or none.
+func (h *RestaurantHandler) ImportRestaurants(restaurants []Restaurant) error {
+	if len(restaurants) == 0 {
+		return nil
+	}
+	return h.db.Transaction(func(tx *gorm.DB) error {
+		return tx.CreateInBatches(&restaurants, importBatchSize).Error
+	})
+}
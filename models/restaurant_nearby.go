@@ -0,0 +1,60 @@
+package models
+
+import (
+	"math"
+
+	"gorm.io/gorm"
+)
+
+// kmPerDegreeLatitude is the length of a degree of latitude, used to bound
+// the search to the rows around the point before computing distances.
+const kmPerDegreeLatitude = 111.32
+
+func IsValidCoordinate(latitude, longitude float64) bool {
+	return latitude >= -90 && latitude <= 90 && longitude >= -180 && longitude <= 180
+}
+
+// restaurantDistance is the haversine distance in kilometers, on an Earth of
+// radius 6371 km, between the restaurant and the point bound to its three
+// parameters: latitude, latitude and longitude.
+const restaurantDistance = `2 * 6371.0 * ASIN(LEAST(1, SQRT(
+	POWER(SIN(RADIANS(restaurants.latitude - ?) / 2), 2) +
+	COS(RADIANS(?)) * COS(RADIANS(restaurants.latitude)) * POWER(SIN(RADIANS(restaurants.longitude - ?) / 2), 2))))`
+
+// aroundPoint keeps the restaurants inside the box of radiusKm around the
+// point, which the location index can answer. The box is skipped in
+// longitude near the poles and across the antimeridian.
+func aroundPoint(latitude, longitude, radiusKm float64) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		latitudeDelta := radiusKm / kmPerDegreeLatitude
+		db = db.Where("restaurants.latitude BETWEEN ? AND ?", latitude-latitudeDelta, latitude+latitudeDelta)
+
+		if math.Abs(latitude)+latitudeDelta >= 90 {
+			return db
+		}
+		longitudeDelta := latitudeDelta / math.Cos(latitude*math.Pi/180)
+		if longitude-longitudeDelta < -180 || longitude+longitudeDelta > 180 {
+			return db
+		}
+		return db.Where("restaurants.longitude BETWEEN ? AND ?", longitude-longitudeDelta, longitude+longitudeDelta)
+	}
+}
+
+// GetNearbyRestaurants returns one page of the restaurants within radiusKm of
+// the point, closest first with their distance, and the number of them across
+// all pages. Restaurants without a location are left out.
+func (h *RestaurantHandler) GetNearbyRestaurants(latitude, longitude, radiusKm float64, page, limit int) ([]Restaurant, int64, error) {
+	nearby := h.db.Model(&Restaurant{}).
+		Select("restaurants.*, "+restaurantDistance+" AS distance", latitude, latitude, longitude).
+		Scopes(Listed, aroundPoint(latitude, longitude, radiusKm))
+	query := h.db.Table("(?) AS restaurants", nearby).Where("distance <= ?", radiusKm)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var restaurants []Restaurant
+	err := query.Scopes(withListing).Order("distance, id").Offset((page - 1) * limit).Limit(limit).Find(&restaurants).Error
+	return restaurants, total, err
+}
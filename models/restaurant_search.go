@@ -0,0 +1,72 @@
+package models
+
+import (
+	"database/sql"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// restaurantSearchDocument is the text searched by SearchRestaurants. The
+// simple configuration does not stem, so Thai and English names match alike.
+const restaurantSearchDocument = "to_tsvector('simple', coalesce(name, '') || ' ' || coalesce(description, '') || ' ' || coalesce(address, ''))"
+
+// restaurantSearchSimilarity is the least trigram word similarity between the
+// query and the name for a name to match despite typos.
+const restaurantSearchSimilarity = "0.3"
+
+// MigrateSearch creates the indexes behind SearchRestaurants: one over the
+// search document and a trigram one over the name for typo tolerance.
+func MigrateSearch(db *gorm.DB) error {
+	for _, statement := range []string{
+		"CREATE EXTENSION IF NOT EXISTS pg_trgm",
+		"CREATE INDEX IF NOT EXISTS idx_restaurants_search ON restaurants USING GIN ((" + restaurantSearchDocument + "))",
+		"CREATE INDEX IF NOT EXISTS idx_restaurants_name_trgm ON restaurants USING GIN (name gin_trgm_ops)",
+	} {
+		if err := db.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SearchRestaurants returns one page of the restaurants whose name,
+// description or address match the query, best matches first, with the
+// number of matches across all pages. The query uses the web search syntax,
+// e.g. "thai -buffet", and names a few typos away still match. The weights of
+// the search boosts are added to the rank.
+func (h *RestaurantHandler) SearchRestaurants(query string, page, limit int) ([]Restaurant, int64, error) {
+	var restaurants []Restaurant
+	var total int64
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SET LOCAL pg_trgm.word_similarity_threshold = " + restaurantSearchSimilarity).Error; err != nil {
+			return err
+		}
+
+		q := sql.Named("q", query)
+		matches := tx.Model(&Restaurant{}).Scopes(Listed).
+			Where(restaurantSearchDocument+" @@ websearch_to_tsquery('simple', @q) OR @q <% name", q)
+		if err := matches.Count(&total).Error; err != nil {
+			return err
+		}
+
+		// Boosts are read on every search so admin changes apply right away
+		boosts, err := getBoosts(tx)
+		if err != nil {
+			return err
+		}
+		rank := "ts_rank(" + restaurantSearchDocument + ", websearch_to_tsquery('simple', ?)) + word_similarity(?, name)"
+		vars := []interface{}{query, query}
+		if boost, boostVars := searchBoostExpr(boosts); boost != "" {
+			rank += " + " + boost
+			vars = append(vars, boostVars...)
+		}
+
+		return matches.Clauses(clause.OrderBy{Expression: clause.Expr{
+			SQL:                rank + " DESC, id",
+			Vars:               vars,
+			WithoutParentheses: true,
+		}}).Scopes(withListing).Offset((page - 1) * limit).Limit(limit).Find(&restaurants).Error
+	})
+	return restaurants, total, err
+}
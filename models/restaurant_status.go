@@ -0,0 +1,97 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// A restaurant is drafted, submitted for approval and, once approved by an
+// admin, listed publicly. Listed restaurants can be hidden from the listings
+// or suspended.
+const (
+	RestaurantStatusDraft     = "draft"
+	RestaurantStatusPending   = "pending"
+	RestaurantStatusActive    = "active"
+	RestaurantStatusHidden    = "hidden"
+	RestaurantStatusSuspended = "suspended"
+)
+
+// RestaurantStatuses lists every status a restaurant can have.
+var RestaurantStatuses = []string{RestaurantStatusDraft, RestaurantStatusPending, RestaurantStatusActive, RestaurantStatusHidden, RestaurantStatusSuspended}
+
+// restaurantTransitions lists the statuses a restaurant may move to from its current status.
+var restaurantTransitions = map[string][]string{
+	RestaurantStatusDraft:     {RestaurantStatusPending},
+	RestaurantStatusPending:   {RestaurantStatusActive, RestaurantStatusDraft},
+	RestaurantStatusActive:    {RestaurantStatusHidden, RestaurantStatusSuspended},
+	RestaurantStatusHidden:    {RestaurantStatusActive, RestaurantStatusSuspended},
+	RestaurantStatusSuspended: {RestaurantStatusActive},
+}
+
+var ErrInvalidRestaurantTransition = fmt.Errorf("invalid restaurant status transition")
+
+func IsValidRestaurantStatus(status string) bool {
+	return isOneOf(status, RestaurantStatuses)
+}
+
+func CanTransitionRestaurant(from, to string) bool {
+	return isOneOf(to, restaurantTransitions[from])
+}
+
+// IsListed reports whether the restaurant is shown to the public.
+func (r *Restaurant) IsListed() bool {
+	return r.Status == RestaurantStatusActive
+}
+
+// Listed keeps the restaurants shown to the public.
+func Listed(db *gorm.DB) *gorm.DB {
+	return db.Where("restaurants.status = ?", RestaurantStatusActive)
+}
+
+// SetRestaurantStatus moves the restaurant to the status with the reason
+// shown to its owner, failing with ErrInvalidRestaurantTransition when the
+// current status cannot move there. It returns the updated restaurant and
+// its previous status.
+func (h *RestaurantHandler) SetRestaurantStatus(id uint, status, reason string, now time.Time) (*Restaurant, string, error) {
+	var previous string
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		var restaurant Restaurant
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "status").First(&restaurant, id).Error; err != nil {
+			return err
+		}
+		if !CanTransitionRestaurant(restaurant.Status, status) {
+			return fmt.Errorf("%w from %s to %s", ErrInvalidRestaurantTransition, restaurant.Status, status)
+		}
+		previous = restaurant.Status
+
+		return tx.Model(&Restaurant{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"status":            status,
+			"status_reason":     reason,
+			"status_changed_at": now,
+		}).Error
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	restaurant, err := h.GetRestaurant(id)
+	return restaurant, previous, err
+}
+
+// GetRestaurantsByStatus returns one page of the restaurants with the status,
+// oldest change first so admins review them in order, with their number.
+func (h *RestaurantHandler) GetRestaurantsByStatus(status string, limit, offset int) ([]Restaurant, int64, error) {
+	query := h.db.Model(&Restaurant{}).Where("status = ?", status)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var restaurants []Restaurant
+	err := query.Scopes(withListing).Order("status_changed_at, id").Limit(limit).Offset(offset).Find(&restaurants).Error
+	return restaurants, total, err
+}
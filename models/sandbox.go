@@ -0,0 +1,103 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/punchanabu/redrice-backend-go/utils"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SandboxPassword is the password every anonymized user can log in with in a
+// sandbox seeded from a snapshot.
+const SandboxPassword = "Sandbox-Passw0rd"
+
+// Snapshot is a copy of the core tables used to seed a sandbox environment.
+// Soft deleted rows are included so references between tables stay intact.
+type Snapshot struct {
+	Users        []User
+	Restaurants  []Restaurant
+	Reservations []Reservation
+	Comments     []Comment
+	Blackouts    []Blackout
+}
+
+// TakeSnapshot reads the core tables of the database.
+func TakeSnapshot(db *gorm.DB) (*Snapshot, error) {
+	var snapshot Snapshot
+	tables := []interface{}{&snapshot.Users, &snapshot.Restaurants, &snapshot.Reservations, &snapshot.Comments, &snapshot.Blackouts}
+	for _, table := range tables {
+		if err := db.Unscoped().Order("id").Find(table).Error; err != nil {
+			return nil, err
+		}
+	}
+	return &snapshot, nil
+}
+
+// Anonymize replaces the personal data of every user with placeholders derived
+// from their ID and resets their credentials, so the snapshot can leave production.
+func (s *Snapshot) Anonymize() error {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(SandboxPassword), utils.BcryptCost())
+	if err != nil {
+		return err
+	}
+
+	for i := range s.Users {
+		user := &s.Users[i]
+		user.Name = fmt.Sprintf("User %d", user.ID)
+		user.Email = fmt.Sprintf("user%d@sandbox.redrice.invalid", user.ID)
+		if user.Telephone != "" {
+			user.Telephone = fmt.Sprintf("+6600%07d", user.ID)
+		}
+		user.Password = string(hashedPassword)
+		user.GoogleID = ""
+		user.FacebookID = ""
+		user.AppleID = ""
+		user.TwoFactorEnabled = false
+		user.TwoFactorSecret = ""
+		user.TelephoneVerifiedAt = nil
+		user.TokensValidAfter = nil
+		user.ImageURL = ""
+	}
+	return nil
+}
+
+// LoadSnapshot inserts the snapshot into an empty database, keeping the
+// original IDs, and moves the ID sequences past the loaded rows.
+func LoadSnapshot(db *gorm.DB, snapshot *Snapshot) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Unscoped().Model(&User{}).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return fmt.Errorf("target database already has %d users, refusing to load the snapshot", count)
+		}
+
+		tables := []struct {
+			name string
+			rows interface{}
+			size int
+		}{
+			{"users", snapshot.Users, len(snapshot.Users)},
+			{"restaurants", snapshot.Restaurants, len(snapshot.Restaurants)},
+			{"reservations", snapshot.Reservations, len(snapshot.Reservations)},
+			{"comments", snapshot.Comments, len(snapshot.Comments)},
+			{"blackouts", snapshot.Blackouts, len(snapshot.Blackouts)},
+		}
+		for _, table := range tables {
+			if table.size == 0 {
+				continue
+			}
+			if err := tx.Omit(clause.Associations).CreateInBatches(table.rows, 500).Error; err != nil {
+				return fmt.Errorf("loading %s: %w", table.name, err)
+			}
+			sequence := fmt.Sprintf("SELECT setval(pg_get_serial_sequence('%s', 'id'), (SELECT MAX(id) FROM %s))", table.name, table.name)
+			if err := tx.Exec(sequence).Error; err != nil {
+				return fmt.Errorf("resetting the %s sequence: %w", table.name, err)
+			}
+		}
+		return nil
+	})
+}
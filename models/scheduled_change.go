@@ -0,0 +1,162 @@
+package models
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	ScheduledChangePending   = "pending"
+	ScheduledChangePublished = "published"
+	ScheduledChangeCancelled = "cancelled"
+)
+
+// ScheduledChange is a set of listing changes staged by an owner that is
+// published in one go at PublishAt. Changes are keyed by column name.
+type ScheduledChange struct {
+	ID           uint              `gorm:"primaryKey" json:"id"`
+	RestaurantID uint              `gorm:"index" json:"restaurantId"`
+	CreatedBy    uint              `json:"createdBy"`
+	Changes      map[string]string `gorm:"serializer:json" json:"changes"`
+	PublishAt    time.Time         `gorm:"index" json:"publishAt"`
+	Status       string            `gorm:"default:pending" json:"status"`
+	PublishedAt  *time.Time        `json:"publishedAt"`
+	CreatedAt    time.Time         `json:"createdAt"`
+}
+
+type ScheduledChangeHandler struct {
+	db *gorm.DB
+}
+
+func NewScheduledChangeHandler(db *gorm.DB) *ScheduledChangeHandler {
+	return &ScheduledChangeHandler{db}
+}
+
+// IsListingColumn reports whether the column can be staged in a scheduled change.
+func IsListingColumn(column string) bool {
+	for _, c := range restaurantListingColumns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+func (h *ScheduledChangeHandler) CreateScheduledChange(change *ScheduledChange) error {
+	for column := range change.Changes {
+		if !IsListingColumn(column) {
+			return fmt.Errorf("field %s cannot be scheduled", column)
+		}
+	}
+	change.Status = ScheduledChangePending
+	return h.db.Create(change).Error
+}
+
+func (h *ScheduledChangeHandler) GetScheduledChange(id uint) (*ScheduledChange, error) {
+	var change ScheduledChange
+	result := h.db.First(&change, id)
+	return &change, result.Error
+}
+
+func (h *ScheduledChangeHandler) GetScheduledChangesByRestaurantID(restaurantID uint) ([]ScheduledChange, error) {
+	var changes []ScheduledChange
+	result := h.db.Where("restaurant_id = ?", restaurantID).Order("publish_at ASC").Find(&changes)
+	return changes, result.Error
+}
+
+// CancelScheduledChange withdraws a change that has not been published yet.
+func (h *ScheduledChangeHandler) CancelScheduledChange(id uint) error {
+	result := h.db.Model(&ScheduledChange{}).
+		Where("id = ? AND status = ?", id, ScheduledChangePending).
+		Update("status", ScheduledChangeCancelled)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("scheduled change is not pending")
+	}
+	return nil
+}
+
+// PublishDue applies every pending change whose publish time has passed. Each
+// change is applied atomically together with its history entry. Rows are
+// locked with SKIP LOCKED so several instances can run the job at once.
+func (h *ScheduledChangeHandler) PublishDue(now time.Time) (int, error) {
+	var due []ScheduledChange
+	if err := h.db.Where("status = ? AND publish_at <= ?", ScheduledChangePending, now).
+		Order("publish_at ASC").Find(&due).Error; err != nil {
+		return 0, err
+	}
+
+	published := 0
+	for _, change := range due {
+		err := h.db.Transaction(func(tx *gorm.DB) error {
+			var locked ScheduledChange
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+				Where("id = ? AND status = ?", change.ID, ScheduledChangePending).
+				First(&locked).Error; err != nil {
+				// Published or cancelled in the meantime
+				return nil
+			}
+
+			if err := applyListingChanges(tx, locked.RestaurantID, locked.CreatedBy, locked.Changes, nil); err != nil {
+				return err
+			}
+
+			return tx.Model(&locked).Updates(map[string]interface{}{
+				"status":       ScheduledChangePublished,
+				"published_at": now,
+			}).Error
+		})
+		if err != nil {
+			log.Printf("Error publishing scheduled change %d: %v", change.ID, err)
+			continue
+		}
+		published++
+	}
+
+	return published, nil
+}
+
+// setListingFields writes the listing fields keyed by column name to the
+// restaurant, the reverse of restaurantListingFields.
+func setListingFields(r *Restaurant, columns map[string]string) {
+	fields := map[string]*string{
+		"name":        &r.Name,
+		"address":     &r.Address,
+		"telephone":   &r.Telephone,
+		"open_time":   &r.OpenTime,
+		"close_time":  &r.CloseTime,
+		"instagram":   &r.Instagram,
+		"facebook":    &r.Facebook,
+		"description": &r.Description,
+		"image_url":   &r.ImageURL,
+	}
+	for column, value := range columns {
+		if field, ok := fields[column]; ok {
+			*field = value
+		}
+	}
+	if value, ok := columns["price_range"]; ok {
+		r.PriceRange = listingValue("price_range", value).(int)
+	}
+}
+
+// PreviewRestaurant applies every pending scheduled change of the restaurant
+// to it in the order they publish, so it reads as it will be listed, and
+// returns the number of changes applied. Nothing is saved.
+func (h *ScheduledChangeHandler) PreviewRestaurant(restaurant *Restaurant) (int, error) {
+	var pending []ScheduledChange
+	if err := h.db.Where("restaurant_id = ? AND status = ?", restaurant.ID, ScheduledChangePending).
+		Order("publish_at ASC, id ASC").Find(&pending).Error; err != nil {
+		return 0, err
+	}
+	for _, change := range pending {
+		setListingFields(restaurant, change.Changes)
+	}
+	return len(pending), nil
+}
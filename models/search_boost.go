@@ -0,0 +1,143 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	SearchBoostVerified       = "verified"
+	SearchBoostPhotos         = "photos"
+	SearchBoostRecentActivity = "recent_activity"
+)
+
+// recentActivityPeriod is how far back a review or post counts as recent activity.
+const recentActivityPeriod = 30 * 24 * time.Hour
+
+// maxSearchBoost bounds the weights. For scale, the text rank plus the name
+// similarity of a match lies between 0 and 2.
+const maxSearchBoost = 10
+
+var ErrUnknownSearchBoost = fmt.Errorf("unknown search boost")
+var ErrInvalidSearchBoost = fmt.Errorf("search boost weight must be between -%d and %d", maxSearchBoost, maxSearchBoost)
+
+// SearchBoost adds its weight to the search rank of the restaurants matching
+// its condition. Boosts never set by an admin weigh 0.
+type SearchBoost struct {
+	ID          uint      `gorm:"primaryKey" json:"-" swaggerignore:"true"`
+	Key         string    `gorm:"uniqueIndex" json:"key" example:"verified" enums:"verified,photos,recent_activity"`
+	Weight      float64   `json:"weight" example:"0.2"`
+	Description string    `gorm:"-" json:"description" example:"Restaurants with reviews from completed reservations"`
+	UpdatedBy   *uint     `json:"updatedBy,omitempty" example:"1"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+type searchBoostRule struct {
+	description string
+	// condition is the SQL condition on a restaurants row, its values are
+	// computed at query time
+	condition func() (string, []interface{})
+}
+
+var searchBoostRules = map[string]searchBoostRule{
+	SearchBoostVerified: {
+		description: "Restaurants with reviews from completed reservations",
+		condition: func() (string, []interface{}) {
+			return "restaurants.verified_comment_count > 0", nil
+		},
+	},
+	SearchBoostPhotos: {
+		description: "Restaurants with photos in their gallery",
+		condition: func() (string, []interface{}) {
+			return "EXISTS (SELECT 1 FROM restaurant_images WHERE restaurant_images.restaurant_id = restaurants.id AND restaurant_images.deleted_at IS NULL)", nil
+		},
+	},
+	SearchBoostRecentActivity: {
+		description: "Restaurants reviewed or posting to their feed in the last 30 days",
+		condition: func() (string, []interface{}) {
+			since := time.Now().Add(-recentActivityPeriod)
+			return "(EXISTS (SELECT 1 FROM comments WHERE comments.restaurant_id = restaurants.id AND comments.created_at >= ?) OR EXISTS (SELECT 1 FROM feed_items WHERE feed_items.restaurant_id = restaurants.id AND feed_items.created_at >= ?))", []interface{}{since, since}
+		},
+	},
+}
+
+type SearchBoostHandler struct {
+	db *gorm.DB
+}
+
+func NewSearchBoostHandler(db *gorm.DB) *SearchBoostHandler {
+	return &SearchBoostHandler{db}
+}
+
+// getBoosts returns every boost ordered by key, with weight 0 for the ones never set.
+func getBoosts(db *gorm.DB) ([]SearchBoost, error) {
+	var saved []SearchBoost
+	if err := db.Find(&saved).Error; err != nil {
+		return nil, err
+	}
+	byKey := map[string]SearchBoost{}
+	for _, boost := range saved {
+		byKey[boost.Key] = boost
+	}
+
+	boosts := make([]SearchBoost, 0, len(searchBoostRules))
+	for key, rule := range searchBoostRules {
+		boost, ok := byKey[key]
+		if !ok {
+			boost = SearchBoost{Key: key}
+		}
+		boost.Description = rule.description
+		boosts = append(boosts, boost)
+	}
+	sort.Slice(boosts, func(i, j int) bool { return boosts[i].Key < boosts[j].Key })
+	return boosts, nil
+}
+
+func (h *SearchBoostHandler) GetBoosts() ([]SearchBoost, error) {
+	return getBoosts(h.db)
+}
+
+// SaveBoost sets the weight of the boost, the next search uses it.
+func (h *SearchBoostHandler) SaveBoost(key string, weight float64, updatedBy uint) ([]SearchBoost, error) {
+	if _, ok := searchBoostRules[key]; !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownSearchBoost, key)
+	}
+	if weight < -maxSearchBoost || weight > maxSearchBoost {
+		return nil, ErrInvalidSearchBoost
+	}
+
+	boost := SearchBoost{Key: key, Weight: weight, UpdatedBy: &updatedBy}
+	err := h.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"weight", "updated_by", "updated_at"}),
+	}).Create(&boost).Error
+	if err != nil {
+		return nil, err
+	}
+	return h.GetBoosts()
+}
+
+// searchBoostExpr returns the SQL sum of the weights of the boosts that apply
+// to a restaurants row, empty when no boost has a weight.
+func searchBoostExpr(boosts []SearchBoost) (string, []interface{}) {
+	var terms []string
+	var vars []interface{}
+	for _, boost := range boosts {
+		if boost.Weight == 0 {
+			continue
+		}
+		condition, conditionVars := searchBoostRules[boost.Key].condition()
+		terms = append(terms, "CASE WHEN "+condition+" THEN CAST(? AS double precision) ELSE 0 END")
+		vars = append(vars, conditionVars...)
+		vars = append(vars, boost.Weight)
+	}
+	if len(terms) == 0 {
+		return "", nil
+	}
+	return strings.Join(terms, " + "), vars
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Session records a token issued at login together with the device it was
+// issued to, so users can review and revoke their sessions.
+type Session struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	TokenID   string     `gorm:"uniqueIndex" json:"-" swaggerignore:"true"`
+	UserID    uint       `gorm:"index" json:"-" swaggerignore:"true"`
+	UserAgent string     `json:"userAgent"`
+	IP        string     `json:"ip"`
+	IssuedAt  time.Time  `json:"issuedAt"`
+	ExpiresAt time.Time  `json:"expiresAt"`
+	RevokedAt *time.Time `json:"-" swaggerignore:"true"`
+	// ImpersonatedBy is the admin the session was issued to while acting as the user
+	ImpersonatedBy *uint `json:"impersonatedBy,omitempty"`
+	Current        bool  `gorm:"-" json:"current"`
+}
+
+type SessionHandler struct {
+	db *gorm.DB
+}
+
+func NewSessionHandler(db *gorm.DB) *SessionHandler {
+	return &SessionHandler{db}
+}
+
+func (h *SessionHandler) CreateSession(session *Session) error {
+	return h.db.Create(session).Error
+}
+
+// DeleteExpiredSessions deletes the sessions that expired before the time,
+// they are of no interest anymore.
+func (h *SessionHandler) DeleteExpiredSessions(before time.Time) error {
+	return h.db.Where("expires_at < ?", before).Delete(&Session{}).Error
+}
+
+// GetActiveSessions lists the sessions of the user that are neither expired
+// nor revoked. Sessions issued before validAfter were signed out by a
+// password change and are skipped.
+func (h *SessionHandler) GetActiveSessions(userID uint, validAfter *time.Time) ([]Session, error) {
+	var sessions []Session
+	query := h.db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now())
+	if validAfter != nil {
+		query = query.Where("issued_at >= ?", validAfter.Truncate(time.Second))
+	}
+	result := query.Order("issued_at DESC").Find(&sessions)
+	return sessions, result.Error
+}
+
+// RevokeSession signs out a single session of the user.
+func (h *SessionHandler) RevokeSession(userID uint, sessionID uint) error {
+	var session Session
+	if err := h.db.Where("id = ? AND user_id = ? AND revoked_at IS NULL", sessionID, userID).First(&session).Error; err != nil {
+		return fmt.Errorf("session not found")
+	}
+	return NewRevokedTokenHandler(h.db).RevokeToken(session.TokenID, userID, session.ExpiresAt)
+}
+
+// RevokeOtherSessions signs out every session of the user except the one
+// using currentTokenID and returns how many were revoked.
+func (h *SessionHandler) RevokeOtherSessions(userID uint, currentTokenID string) (int, error) {
+	var sessions []Session
+	if err := h.db.Where("user_id = ? AND token_id <> ? AND revoked_at IS NULL AND expires_at > ?", userID, currentTokenID, time.Now()).
+		Find(&sessions).Error; err != nil {
+		return 0, err
+	}
+
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		revoked := NewRevokedTokenHandler(tx)
+		for _, session := range sessions {
+			if err := revoked.RevokeToken(session.TokenID, userID, session.ExpiresAt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(sessions), nil
+}
@@ -0,0 +1,121 @@
+package models
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	SigningAlgorithmHS256 = "HS256"
+	SigningAlgorithmRS256 = "RS256"
+)
+
+// SigningKey is one of the keys tokens are signed with, identified in the
+// token header by KeyID. The newest key that is active signs new tokens, older
+// keys only verify until ExpiresAt, by when every token they signed expired.
+// Material is the HMAC secret for HS256 and the PEM private key for RS256.
+type SigningKey struct {
+	ID          uint       `gorm:"primaryKey"`
+	KeyID       string     `gorm:"uniqueIndex"`
+	Algorithm   string     `gorm:"default:HS256"`
+	Material    string     `json:"-"`
+	ActivatesAt time.Time  `gorm:"index"`
+	ExpiresAt   *time.Time `gorm:"index"`
+	CreatedAt   time.Time
+}
+
+type SigningKeyHandler struct {
+	db *gorm.DB
+}
+
+func NewSigningKeyHandler(db *gorm.DB) *SigningKeyHandler {
+	return &SigningKeyHandler{db}
+}
+
+// newSigningKey generates fresh key material for the algorithm.
+func newSigningKey(algorithm string, activatesAt time.Time) (*SigningKey, error) {
+	key := SigningKey{KeyID: uuid.New().String(), Algorithm: algorithm, ActivatesAt: activatesAt}
+
+	switch algorithm {
+	case SigningAlgorithmHS256:
+		secret, err := generateToken()
+		if err != nil {
+			return nil, err
+		}
+		key.Material = secret
+	case SigningAlgorithmRS256:
+		private, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return nil, err
+		}
+		key.Material = string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(private)}))
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm %q", algorithm)
+	}
+
+	return &key, nil
+}
+
+// GetUsableKeys returns the keys that have not expired, newest first. It
+// includes keys that are published ahead of their activation.
+func (h *SigningKeyHandler) GetUsableKeys(now time.Time) ([]SigningKey, error) {
+	var keys []SigningKey
+	result := h.db.Where("expires_at IS NULL OR expires_at > ?", now).Order("activates_at DESC").Find(&keys)
+	return keys, result.Error
+}
+
+// Rotate creates a new key once the newest key is older than interval, or
+// right away when there is none or it uses another algorithm. The new key is
+// published lead ahead of its activation so verifiers caching the public
+// keys pick it up first, unless no key is active yet. The key it replaces
+// keeps verifying for tokenTTL after the switch. An interval of zero never
+// rotates a working key.
+func (h *SigningKeyHandler) Rotate(algorithm string, interval, lead, tokenTTL time.Duration, now time.Time) error {
+	return h.db.Transaction(func(tx *gorm.DB) error {
+		var latest SigningKey
+		err := tx.Where("expires_at IS NULL OR expires_at > ?", now).Order("activates_at DESC").First(&latest).Error
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return err
+		}
+		found := err == nil
+
+		if found && latest.Algorithm == algorithm {
+			if interval <= 0 || now.Before(latest.ActivatesAt.Add(interval-lead)) {
+				return nil
+			}
+		}
+
+		activatesAt := now
+		if found && !latest.ActivatesAt.After(now) {
+			activatesAt = now.Add(lead)
+			if latest.Algorithm == algorithm && latest.ActivatesAt.Add(interval).After(activatesAt) {
+				activatesAt = latest.ActivatesAt.Add(interval)
+			}
+		}
+
+		key, err := newSigningKey(algorithm, activatesAt)
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(key).Error; err != nil {
+			return err
+		}
+
+		// Every key before the new one retires once its last tokens expired
+		retiresAt := activatesAt.Add(tokenTTL)
+		if err := tx.Model(&SigningKey{}).
+			Where("id <> ? AND (expires_at IS NULL OR expires_at > ?)", key.ID, retiresAt).
+			Update("expires_at", retiresAt).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("expires_at <= ?", now).Delete(&SigningKey{}).Error
+	})
+}
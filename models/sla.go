@@ -0,0 +1,171 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	SLAMetricConfirmationLatency = "confirmation_latency"
+	SLAMetricDeclineRate         = "decline_rate"
+)
+
+// minSLAResponses is below which a restaurant answered too few reservations
+// in the window for its metrics to mean anything.
+const minSLAResponses = 10
+
+// SLAThresholds are the limits a restaurant should stay within over the window.
+type SLAThresholds struct {
+	Window                 time.Duration
+	MaxConfirmationLatency time.Duration
+	MaxDeclineRate         float64
+}
+
+// RestaurantPerformance measures how a restaurant answered the reservations
+// made over a period.
+type RestaurantPerformance struct {
+	RestaurantID   uint   `json:"restaurantId" example:"7"`
+	RestaurantName string `json:"restaurantName" example:"Baan Khanitha"`
+	// Responses counts the reservations the restaurant confirmed or declined
+	Responses int64 `json:"responses" example:"40"`
+	Declined  int64 `json:"declined" example:"6"`
+	// ConfirmationMinutes is the average time between booking and the answer of the restaurant
+	ConfirmationMinutes float64 `json:"confirmationMinutes" example:"42.5"`
+	DeclineRate         float64 `json:"declineRate" example:"0.15"`
+}
+
+// SLAAlert is raised when a restaurant goes over a threshold and stays open
+// until the restaurant is back within it, so admins are alerted once per
+// breach.
+type SLAAlert struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	RestaurantID   uint       `gorm:"index" json:"restaurantId" example:"7"`
+	RestaurantName string     `gorm:"->;-:migration" json:"restaurantName" example:"Baan Khanitha"`
+	Metric         string     `json:"metric" example:"decline_rate" enums:"confirmation_latency,decline_rate"`
+	Value          float64    `json:"value" example:"0.45"`
+	Threshold      float64    `json:"threshold" example:"0.3"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	ResolvedAt     *time.Time `gorm:"index" json:"resolvedAt,omitempty"`
+}
+
+type SLAHandler struct {
+	db *gorm.DB
+}
+
+func NewSLAHandler(db *gorm.DB) *SLAHandler {
+	return &SLAHandler{db}
+}
+
+// GetPerformance measures the restaurants that answered at least one
+// reservation made in [from, to).
+func (h *SLAHandler) GetPerformance(from, to time.Time) ([]RestaurantPerformance, error) {
+	var performance []RestaurantPerformance
+	err := h.db.Model(&Reservation{}).
+		Select(`reservations.restaurant_id, restaurants.name AS restaurant_name,
+			COUNT(*) AS responses,
+			COUNT(*) FILTER (WHERE reservations.status = ?) AS declined,
+			AVG(EXTRACT(EPOCH FROM reservations.responded_at - reservations.created_at) / 60) AS confirmation_minutes`, ReservationStatusDeclined).
+		Joins("JOIN restaurants ON restaurants.id = reservations.restaurant_id").
+		Where("reservations.responded_at IS NOT NULL AND reservations.created_at >= ? AND reservations.created_at < ?", from, to).
+		Group("reservations.restaurant_id, restaurants.name").
+		Order("reservations.restaurant_id").
+		Scan(&performance).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range performance {
+		performance[i].DeclineRate = float64(performance[i].Declined) / float64(performance[i].Responses)
+	}
+	return performance, nil
+}
+
+// slaBreach identifies the alert of a restaurant for a metric.
+type slaBreach struct {
+	restaurantID uint
+	metric       string
+}
+
+// breaches returns an unsaved alert for every metric of the restaurant over
+// the thresholds.
+func (p RestaurantPerformance) breaches(thresholds SLAThresholds) []SLAAlert {
+	if p.Responses < minSLAResponses {
+		return nil
+	}
+	var alerts []SLAAlert
+	if maxMinutes := thresholds.MaxConfirmationLatency.Minutes(); p.ConfirmationMinutes > maxMinutes {
+		alerts = append(alerts, SLAAlert{RestaurantID: p.RestaurantID, RestaurantName: p.RestaurantName, Metric: SLAMetricConfirmationLatency, Value: p.ConfirmationMinutes, Threshold: maxMinutes})
+	}
+	if p.DeclineRate > thresholds.MaxDeclineRate {
+		alerts = append(alerts, SLAAlert{RestaurantID: p.RestaurantID, RestaurantName: p.RestaurantName, Metric: SLAMetricDeclineRate, Value: p.DeclineRate, Threshold: thresholds.MaxDeclineRate})
+	}
+	return alerts
+}
+
+// EvaluateAlerts measures the restaurants over the window ending at now,
+// opens an alert for every new breach and resolves the alerts of restaurants
+// back within the thresholds. It returns the alerts it opened.
+func (h *SLAHandler) EvaluateAlerts(now time.Time, thresholds SLAThresholds) ([]SLAAlert, error) {
+	performance, err := h.GetPerformance(now.Add(-thresholds.Window), now)
+	if err != nil {
+		return nil, err
+	}
+	breaching := map[slaBreach]SLAAlert{}
+	for _, p := range performance {
+		for _, alert := range p.breaches(thresholds) {
+			breaching[slaBreach{alert.RestaurantID, alert.Metric}] = alert
+		}
+	}
+
+	var opened []SLAAlert
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		var open []SLAAlert
+		if err := tx.Where("resolved_at IS NULL").Find(&open).Error; err != nil {
+			return err
+		}
+
+		for _, alert := range open {
+			key := slaBreach{alert.RestaurantID, alert.Metric}
+			if _, ok := breaching[key]; ok {
+				// Still breaching, admins were already alerted
+				delete(breaching, key)
+				continue
+			}
+			if err := tx.Model(&SLAAlert{}).Where("id = ?", alert.ID).Update("resolved_at", now).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, alert := range breaching {
+			if err := tx.Create(&alert).Error; err != nil {
+				return err
+			}
+			opened = append(opened, alert)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return opened, nil
+}
+
+// GetAlerts returns the alerts newest first, only the open ones unless all is set.
+func (h *SLAHandler) GetAlerts(all bool, limit, offset int) ([]SLAAlert, int64, error) {
+	query := h.db.Model(&SLAAlert{})
+	if !all {
+		query = query.Where("sla_alerts.resolved_at IS NULL")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var alerts []SLAAlert
+	err := query.Select("sla_alerts.*, restaurants.name AS restaurant_name").
+		Joins("LEFT JOIN restaurants ON restaurants.id = sla_alerts.restaurant_id").
+		Order("sla_alerts.id DESC").Limit(limit).Offset(offset).Scan(&alerts).Error
+	return alerts, total, err
+}
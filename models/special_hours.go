@@ -0,0 +1,130 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const ErrCodeRestaurantClosed = "RESTAURANT_CLOSED"
+
+const specialHoursDateFormat = "2006-01-02"
+
+// SpecialHours override the regular opening hours of a restaurant on one
+// day, e.g. a holiday closure or extended hours on New Year's Eve. The date
+// is in the time zone of the restaurant.
+type SpecialHours struct {
+	ID           uint   `gorm:"primaryKey" json:"id"`
+	RestaurantID uint   `gorm:"uniqueIndex:idx_special_hours_restaurant_date" json:"restaurantId" example:"7"`
+	Date         string `gorm:"uniqueIndex:idx_special_hours_restaurant_date" json:"date" example:"2024-12-31"`
+	// Closed days have no opening hours
+	Closed    bool      `json:"closed"`
+	OpenTime  string    `json:"openTime,omitempty" example:"10:00"`
+	CloseTime string    `json:"closeTime,omitempty" example:"02:00"`
+	Reason    string    `json:"reason" example:"New Year's Eve"`
+	CreatedAt time.Time `json:"-" swaggerignore:"true"`
+	UpdatedAt time.Time `json:"-" swaggerignore:"true"`
+}
+
+// HoursOn returns the opening and closing time of the restaurant on the day,
+// the special hours when set, and whether it opens at all.
+func (r *Restaurant) HoursOn(special *SpecialHours) (string, string, bool) {
+	if special == nil {
+		return r.OpenTime, r.CloseTime, true
+	}
+	if special.Closed {
+		return "", "", false
+	}
+	return special.OpenTime, special.CloseTime, true
+}
+
+type SpecialHoursHandler struct {
+	db *gorm.DB
+}
+
+func NewSpecialHoursHandler(db *gorm.DB) *SpecialHoursHandler {
+	return &SpecialHoursHandler{db}
+}
+
+// GetSpecialHours returns the special hours of the restaurant from the date on, by date.
+func (h *SpecialHoursHandler) GetSpecialHours(restaurantID uint, from string) ([]SpecialHours, error) {
+	var hours []SpecialHours
+	err := h.db.Where("restaurant_id = ? AND date >= ?", restaurantID, from).Order("date").Find(&hours).Error
+	return hours, err
+}
+
+// GetSpecialHoursOn returns the special hours of the restaurant on the day
+// of at in its time zone, nil when the regular hours apply.
+func (h *SpecialHoursHandler) GetSpecialHoursOn(restaurant *Restaurant, at time.Time) (*SpecialHours, error) {
+	var hours []SpecialHours
+	date := at.In(restaurant.Location()).Format(specialHoursDateFormat)
+	if err := h.db.Where("restaurant_id = ? AND date = ?", restaurant.ID, date).Limit(1).Find(&hours).Error; err != nil {
+		return nil, err
+	}
+	if len(hours) == 0 {
+		return nil, nil
+	}
+	return &hours[0], nil
+}
+
+// SaveSpecialHours sets the special hours of the restaurant on their date,
+// replacing the ones already set.
+func (h *SpecialHoursHandler) SaveSpecialHours(hours *SpecialHours) error {
+	return h.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "restaurant_id"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"closed", "open_time", "close_time", "reason", "updated_at"}),
+	}).Create(hours).Error
+}
+
+// DeleteSpecialHours brings back the regular hours on the date. It reports
+// whether special hours were set.
+func (h *SpecialHoursHandler) DeleteSpecialHours(restaurantID uint, date string) (bool, error) {
+	result := h.db.Where("restaurant_id = ? AND date = ?", restaurantID, date).Delete(&SpecialHours{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// CheckSpecialHours rejects reservations on a day the restaurant is closed,
+// or outside the special hours of the day in the time zone of the
+// restaurant. Special hours closing after midnight take reservations until
+// the closing time.
+func (r *Restaurant) CheckSpecialHours(special *SpecialHours, at time.Time) error {
+	if special == nil {
+		return nil
+	}
+
+	var message string
+	if special.Closed {
+		message = "The restaurant is closed on this day"
+	} else {
+		local := at.In(r.Location()).Format("15:04")
+		open, closing := special.OpenTime, special.CloseTime
+		if open < closing && local >= open && local < closing ||
+			open > closing && (local >= open || local < closing) {
+			return nil
+		}
+		message = fmt.Sprintf("The restaurant only opens from %s to %s on this day", open, closing)
+	}
+	if special.Reason != "" {
+		message += ": " + special.Reason
+	}
+	return &BookingError{Code: ErrCodeRestaurantClosed, Message: message}
+}
+
+// openDuring is the SQL condition of @local, an HH:MM time, falling between
+// the opening and closing time columns, including hours past midnight.
+func openDuring(openTime, closeTime string) string {
+	return openTime + ` ~ '^[0-2][0-9]:[0-5][0-9]$' AND ` + closeTime + ` ~ '^[0-2][0-9]:[0-5][0-9]$' AND CASE
+		WHEN ` + openTime + ` < ` + closeTime + ` THEN @local >= ` + openTime + ` AND @local < ` + closeTime + `
+		WHEN ` + openTime + ` > ` + closeTime + ` THEN @local >= ` + openTime + ` OR @local < ` + closeTime + `
+		ELSE FALSE END`
+}
+
+// localClock formats the time in the time zone of the restaurant row.
+func localClock(at time.Time, format string) clause.Expr {
+	return gorm.Expr("to_char(?::timestamptz AT TIME ZONE COALESCE(NULLIF(restaurants.timezone, ''), ?), ?)", at, DefaultTimezone, format)
+}
+
+// specialHoursToday matches the special hours of the restaurant row on @date.
+const specialHoursToday = "FROM special_hours WHERE special_hours.restaurant_id = restaurants.id AND special_hours.date = @date"
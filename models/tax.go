@@ -0,0 +1,138 @@
+package models
+
+import "fmt"
+
+// ThaiVATRate is the VAT charged by VAT-registered businesses in Thailand.
+const ThaiVATRate = 0.07
+
+// MaxServiceChargeRate caps the service charge at a sane share of the bill.
+const MaxServiceChargeRate = 0.20
+
+// TaxSettings holds how a restaurant charges VAT and service charge. When
+// PricesIncludeTax is set the deposit is the final amount and the breakdown
+// is worked out of it, otherwise both are added on top of the deposit.
+type TaxSettings struct {
+	VATRegistered     bool
+	TaxID             string
+	ServiceChargeRate float64
+	PricesIncludeTax  bool
+}
+
+// ChargeBreakdown splits an amount charged to a guest. Following Thai
+// practice VAT is charged on the subtotal plus the service charge.
+type ChargeBreakdown struct {
+	Subtotal      Money `json:"subtotal"`
+	ServiceCharge Money `json:"serviceCharge"`
+	VAT           Money `json:"vat"`
+	Total         Money `json:"total"`
+}
+
+// ValidateTaxSettings checks the settings against the Thai VAT rules: only
+// businesses registered for VAT may charge it and they must print their
+// 13-digit tax identification number on receipts.
+func ValidateTaxSettings(settings TaxSettings) error {
+	if settings.ServiceChargeRate < 0 || settings.ServiceChargeRate > MaxServiceChargeRate {
+		return fmt.Errorf("service charge rate must be between 0 and %.2f", MaxServiceChargeRate)
+	}
+	if settings.VATRegistered && settings.TaxID == "" {
+		return fmt.Errorf("a tax ID is required to charge VAT")
+	}
+	if settings.TaxID != "" && !IsValidThaiTaxID(settings.TaxID) {
+		return fmt.Errorf("tax ID must be a valid 13-digit Thai tax identification number")
+	}
+	return nil
+}
+
+// IsValidThaiTaxID checks the length and the check digit of a Thai tax
+// identification number, which uses the same scheme as the national ID.
+func IsValidThaiTaxID(id string) bool {
+	if len(id) != 13 {
+		return false
+	}
+
+	sum := 0
+	for i, r := range id {
+		if r < '0' || r > '9' {
+			return false
+		}
+		if i < 12 {
+			sum += int(r-'0') * (13 - i)
+		}
+	}
+	return (11-sum%11)%10 == int(id[12]-'0')
+}
+
+func (r *Restaurant) taxSettings() TaxSettings {
+	return TaxSettings{
+		VATRegistered:     r.VATRegistered,
+		TaxID:             r.TaxID,
+		ServiceChargeRate: r.ServiceChargeRate,
+		PricesIncludeTax:  r.PricesIncludeTax,
+	}
+}
+
+// Breakdown splits the amount according to the tax settings.
+func (s TaxSettings) Breakdown(amount Money) (ChargeBreakdown, error) {
+	vatRate := 0.0
+	if s.VATRegistered {
+		vatRate = ThaiVATRate
+	}
+
+	if s.PricesIncludeTax {
+		subtotal := amount.Mul(1 / ((1 + s.ServiceChargeRate) * (1 + vatRate)))
+		serviceCharge := subtotal.Mul(s.ServiceChargeRate)
+		vat, err := amount.Sub(subtotal)
+		if err == nil {
+			vat, err = vat.Sub(serviceCharge)
+		}
+		if err != nil {
+			return ChargeBreakdown{}, err
+		}
+		return ChargeBreakdown{
+			Subtotal:      subtotal,
+			ServiceCharge: serviceCharge,
+			VAT:           vat,
+			Total:         amount,
+		}, nil
+	}
+
+	serviceCharge := amount.Mul(s.ServiceChargeRate)
+	taxable, err := amount.Add(serviceCharge)
+	if err != nil {
+		return ChargeBreakdown{}, err
+	}
+	vat := taxable.Mul(vatRate)
+	total, err := taxable.Add(vat)
+	if err != nil {
+		return ChargeBreakdown{}, err
+	}
+	return ChargeBreakdown{
+		Subtotal:      amount,
+		ServiceCharge: serviceCharge,
+		VAT:           vat,
+		Total:         total,
+	}, nil
+}
+
+// DepositBreakdown is what a guest pays when booking the restaurant for the
+// deposit, which depends on the deposit rules of the time booked.
+func (r *Restaurant) DepositBreakdown(deposit Money) (ChargeBreakdown, error) {
+	return r.taxSettings().Breakdown(deposit)
+}
+
+func (h *RestaurantHandler) UpdateTaxSettings(id uint, settings TaxSettings) (*Restaurant, error) {
+	// Use a map so that zero values are written as well
+	result := h.db.Model(&Restaurant{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"vat_registered":      settings.VATRegistered,
+		"tax_id":              settings.TaxID,
+		"service_charge_rate": settings.ServiceChargeRate,
+		"prices_include_tax":  settings.PricesIncludeTax,
+	})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, fmt.Errorf("no restaurant found with id %d", id)
+	}
+	return h.GetRestaurant(id)
+}
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// defaultCountryCode is assumed for numbers written in the national format,
+// e.g. 0812345678.
+const defaultCountryCode = "66"
+
+// uniqueViolation is the Postgres error code of a duplicate key.
+const uniqueViolation = "23505"
+
+var ErrInvalidTelephone = fmt.Errorf("invalid telephone, expected a number like +66812345678 or 0812345678")
+
+var e164Pattern = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+var telephoneSeparators = strings.NewReplacer(" ", "", "-", "", ".", "", "(", "", ")", "")
+
+// NormalizeTelephone returns the number in the E.164 format, e.g.
+// +66812345678, so the same number is always stored the same way. Numbers
+// without a country code are taken to be Thai. An empty number stays empty.
+func NormalizeTelephone(telephone string) (string, error) {
+	number := telephoneSeparators.Replace(strings.TrimSpace(telephone))
+	switch {
+	case number == "":
+		return "", nil
+	case strings.HasPrefix(number, "+"):
+	case strings.HasPrefix(number, "00"):
+		number = "+" + number[2:]
+	case strings.HasPrefix(number, "0"):
+		number = "+" + defaultCountryCode + number[1:]
+	default:
+		return "", ErrInvalidTelephone
+	}
+	if !e164Pattern.MatchString(number) {
+		return "", ErrInvalidTelephone
+	}
+	return number, nil
+}
+
+// duplicateUserError turns the unique violation of a user column into the
+// matching error, other errors are returned unchanged. It covers accounts
+// created between the duplicate check and the write.
+func duplicateUserError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != uniqueViolation {
+		return err
+	}
+	if strings.Contains(pgErr.ConstraintName, "telephone") {
+		return ErrTelephoneTaken
+	}
+	if strings.Contains(pgErr.ConstraintName, "email") {
+		return ErrEmailTaken
+	}
+	return err
+}
+
+// MigrateTelephones rewrites the telephones stored before numbers were
+// normalized. Numbers that are not valid, or that would clash with the
+// number of another user once normalized, are left for an admin to fix; the
+// number of users skipped is returned with the error.
+func MigrateTelephones(db *gorm.DB) (int, error) {
+	var users []User
+	err := db.Unscoped().Select("id", "telephone").
+		Where("telephone <> ''").Find(&users).Error
+	if err != nil {
+		return 0, err
+	}
+
+	skipped := 0
+	for _, user := range users {
+		telephone, err := NormalizeTelephone(user.Telephone)
+		if err != nil {
+			skipped++
+			continue
+		}
+		if telephone == user.Telephone {
+			continue
+		}
+		var taken int64
+		if err := db.Unscoped().Model(&User{}).Where("telephone = ? AND id <> ?", telephone, user.ID).Count(&taken).Error; err != nil {
+			return skipped, err
+		}
+		if taken > 0 {
+			skipped++
+			continue
+		}
+		if err := db.Unscoped().Model(&User{}).Where("id = ?", user.ID).Update("telephone", telephone).Error; err != nil {
+			return skipped, err
+		}
+	}
+	return skipped, nil
+}
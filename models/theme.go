@@ -0,0 +1,84 @@
+package models
+
+import (
+	"regexp"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Default palette used by restaurants that did not customize their theme.
+const (
+	DefaultPrimaryColor    = "#C62828"
+	DefaultSecondaryColor  = "#FFFFFF"
+	DefaultAccentColor     = "#FFB300"
+	DefaultBackgroundColor = "#FAFAFA"
+	DefaultTextColor       = "#212121"
+)
+
+var hexColorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+// IsValidHexColor reports whether color has the #RRGGBB format.
+func IsValidHexColor(color string) bool {
+	return hexColorPattern.MatchString(color)
+}
+
+// RestaurantTheme holds the branding assets the white-label web app loads
+// for a restaurant.
+type RestaurantTheme struct {
+	ID              uint   `gorm:"primaryKey" json:"-" swaggerignore:"true"`
+	RestaurantID    uint   `gorm:"uniqueIndex" json:"restaurantId"`
+	LogoURL         string `json:"logoUrl"`
+	PrimaryColor    string `json:"primaryColor" example:"#C62828"`
+	SecondaryColor  string `json:"secondaryColor" example:"#FFFFFF"`
+	AccentColor     string `json:"accentColor" example:"#FFB300"`
+	BackgroundColor string `json:"backgroundColor" example:"#FAFAFA"`
+	TextColor       string `json:"textColor" example:"#212121"`
+}
+
+func DefaultRestaurantTheme(restaurantID uint) *RestaurantTheme {
+	return &RestaurantTheme{
+		RestaurantID:    restaurantID,
+		PrimaryColor:    DefaultPrimaryColor,
+		SecondaryColor:  DefaultSecondaryColor,
+		AccentColor:     DefaultAccentColor,
+		BackgroundColor: DefaultBackgroundColor,
+		TextColor:       DefaultTextColor,
+	}
+}
+
+type ThemeHandler struct {
+	db *gorm.DB
+}
+
+func NewThemeHandler(db *gorm.DB) *ThemeHandler {
+	return &ThemeHandler{db}
+}
+
+// GetTheme returns the theme of the restaurant, or the default theme when the
+// owner has not set one.
+func (h *ThemeHandler) GetTheme(restaurantID uint) (*RestaurantTheme, error) {
+	var theme RestaurantTheme
+	result := h.db.Where("restaurant_id = ?", restaurantID).Limit(1).Find(&theme)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return DefaultRestaurantTheme(restaurantID), nil
+	}
+	return &theme, nil
+}
+
+// SaveTheme creates or replaces the palette of the restaurant. The logo is
+// kept unless theme carries a new one.
+func (h *ThemeHandler) SaveTheme(theme *RestaurantTheme) error {
+	columns := []string{"primary_color", "secondary_color", "accent_color", "background_color", "text_color"}
+	if theme.LogoURL != "" {
+		columns = append(columns, "logo_url")
+	}
+
+	return h.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "restaurant_id"}},
+		DoUpdates: clause.AssignmentColumns(columns),
+	}).Create(theme).Error
+}
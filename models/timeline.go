@@ -0,0 +1,91 @@
+package models
+
+import (
+	"time"
+)
+
+// TimelineBlock is the time a reservation occupies its table.
+type TimelineBlock struct {
+	ReservationID uint      `json:"reservationId" example:"42"`
+	UserID        uint      `json:"userId" example:"5"`
+	Start         time.Time `json:"start"`
+	End           time.Time `json:"end"`
+	Status        string    `json:"status" example:"confirmed" enums:"pending,confirmed,completed"`
+	// Overlaps is set when another reservation occupies the table at the same time
+	Overlaps bool `json:"overlaps"`
+}
+
+// TableTimeline lists the blocks of one table by start time. Table 0 holds
+// the reservations without a table.
+type TableTimeline struct {
+	TableNum int             `json:"tableNum" example:"4"`
+	Blocks   []TimelineBlock `json:"blocks"`
+}
+
+// ReservationTimeline is the occupancy of the tables of a restaurant over one
+// local day, by table number.
+type ReservationTimeline struct {
+	Date   string          `json:"date" example:"2024-05-01"`
+	Tables []TableTimeline `json:"tables"`
+}
+
+// GetReservationTimeline returns the occupancy of the tables of the
+// restaurant on the day of its time zone. Reservations from the day before
+// still running after midnight are included, reservations without a valid
+// exit time last one slot.
+func (h *ReservationHandler) GetReservationTimeline(restaurant *Restaurant, date string) (*ReservationTimeline, error) {
+	dayStart, err := time.ParseInLocation("2006-01-02", date, restaurant.Location())
+	if err != nil {
+		return nil, err
+	}
+	dayEnd := dayStart.AddDate(0, 0, 1)
+
+	var reservations []Reservation
+	if err := h.db.Select("id", "user_id", "table_num", "date_time", "exit_time", "status").
+		Where("restaurant_id = ? AND status IN ? AND date_time >= ? AND date_time < ?", restaurant.ID,
+			[]string{ReservationStatusPending, ReservationStatusConfirmed, ReservationStatusCompleted},
+			dayStart.AddDate(0, 0, -1), dayEnd).
+		Order("table_num, date_time, id").Find(&reservations).Error; err != nil {
+		return nil, err
+	}
+
+	timeline := ReservationTimeline{Date: date, Tables: []TableTimeline{}}
+	for _, reservation := range reservations {
+		end := reservation.ExitTime
+		if !end.After(reservation.DateTime) {
+			end = reservation.DateTime.Add(SlotLength)
+		}
+		if !end.After(dayStart) {
+			continue
+		}
+
+		last := len(timeline.Tables) - 1
+		if last < 0 || timeline.Tables[last].TableNum != reservation.TableNum {
+			timeline.Tables = append(timeline.Tables, TableTimeline{TableNum: reservation.TableNum})
+			last++
+		}
+		timeline.Tables[last].Blocks = append(timeline.Tables[last].Blocks, TimelineBlock{
+			ReservationID: reservation.ID,
+			UserID:        reservation.UserID,
+			Start:         reservation.DateTime,
+			End:           end,
+			Status:        reservation.Status,
+		})
+	}
+
+	for i := range timeline.Tables {
+		markOverlaps(timeline.Tables[i].Blocks)
+	}
+	return &timeline, nil
+}
+
+// markOverlaps flags the blocks sharing time with another block, the blocks
+// must be ordered by start time.
+func markOverlaps(blocks []TimelineBlock) {
+	for i := range blocks {
+		for j := i + 1; j < len(blocks) && blocks[j].Start.Before(blocks[i].End); j++ {
+			blocks[i].Overlaps = true
+			blocks[j].Overlaps = true
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RevokedToken marks a JWT as unusable before its natural expiry. Rows are
+// kept only until the token would have expired anyway.
+type RevokedToken struct {
+	ID        uint      `gorm:"primaryKey"`
+	TokenID   string    `gorm:"uniqueIndex"`
+	UserID    uint      `gorm:"index"`
+	ExpiresAt time.Time `gorm:"index"`
+	CreatedAt time.Time
+}
+
+type RevokedTokenHandler struct {
+	db *gorm.DB
+}
+
+func NewRevokedTokenHandler(db *gorm.DB) *RevokedTokenHandler {
+	return &RevokedTokenHandler{db}
+}
+
+func (h *RevokedTokenHandler) RevokeToken(tokenID string, userID uint, expiresAt time.Time) error {
+	// Clean up entries that no longer matter while we are here
+	if err := h.db.Where("expires_at < ?", time.Now()).Delete(&RevokedToken{}).Error; err != nil {
+		return err
+	}
+
+	if err := h.db.Where(RevokedToken{TokenID: tokenID}).
+		FirstOrCreate(&RevokedToken{TokenID: tokenID, UserID: userID, ExpiresAt: expiresAt}).Error; err != nil {
+		return err
+	}
+
+	// Keep the session list in sync with the blacklist
+	return h.db.Model(&Session{}).Where("token_id = ? AND revoked_at IS NULL", tokenID).Update("revoked_at", time.Now()).Error
+}
+
+func (h *RevokedTokenHandler) IsRevoked(tokenID string) (bool, error) {
+	var count int64
+	result := h.db.Model(&RevokedToken{}).Where("token_id = ?", tokenID).Count(&count)
+	return count > 0, result.Error
+}
@@ -0,0 +1,181 @@
+package models
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// TrendingWindow is the rolling window reservations and views count in.
+const TrendingWindow = 7 * 24 * time.Hour
+
+// maxTrending bounds the ranking kept in the cache.
+const maxTrending = 100
+
+// The score mixes the reservations and views of the window, each relative to
+// the restaurant with the most, with the rating out of 5.
+const (
+	trendingReservationWeight = 0.5
+	trendingViewWeight        = 0.3
+	trendingRatingWeight      = 0.2
+)
+
+// TrendingScore is how a restaurant ranks among the trending ones.
+type TrendingScore struct {
+	RestaurantID uint `json:"-"`
+	// Reservations is the number of reservations made in the window, cancelled and declined ones left out
+	Reservations int64 `json:"reservations" example:"18"`
+	// ViewsPerDay is the average number of users viewing the restaurant page a day in the window
+	ViewsPerDay float64 `json:"viewsPerDay" example:"42.5"`
+	Rating      float64 `json:"rating" example:"4.5"`
+	Score       float64 `json:"score" example:"0.83"`
+}
+
+type TrendingRestaurant struct {
+	RestaurantResponse
+	Trending TrendingScore `json:"trending"`
+}
+
+// TrendingCache keeps the last ranking computed by ComputeTrending. It is
+// safe for concurrent use.
+type TrendingCache struct {
+	mu        sync.RWMutex
+	scores    []TrendingScore
+	updatedAt time.Time
+}
+
+func NewTrendingCache() *TrendingCache {
+	return &TrendingCache{}
+}
+
+// Set replaces the cached ranking.
+func (t *TrendingCache) Set(scores []TrendingScore, at time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.scores = scores
+	t.updatedAt = at
+}
+
+// Page returns one page of the ranking with its length and the time it was
+// computed, zero until it first was.
+func (t *TrendingCache) Page(limit, offset int) ([]TrendingScore, int, time.Time) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if offset >= len(t.scores) {
+		return nil, len(t.scores), t.updatedAt
+	}
+	end := min(offset+limit, len(t.scores))
+	return append([]TrendingScore(nil), t.scores[offset:end]...), len(t.scores), t.updatedAt
+}
+
+// ComputeTrending ranks the listed restaurants with reservations or views in
+// the window ending now, highest score first.
+func (h *RestaurantHandler) ComputeTrending(now time.Time) ([]TrendingScore, error) {
+	since := now.Add(-TrendingWindow)
+
+	var reservations []struct {
+		RestaurantID uint
+		Count        int64
+	}
+	if err := h.db.Model(&Reservation{}).Select("restaurant_id, COUNT(*) AS count").
+		Where("created_at >= ? AND status NOT IN ?", since, []string{ReservationStatusCancelled, ReservationStatusDeclined}).
+		Group("restaurant_id").Scan(&reservations).Error; err != nil {
+		return nil, err
+	}
+
+	// Views are the restaurant_view analytics events, only kept in the
+	// database with the database sink. A user viewing the page again the same
+	// day does not count, and events from before restaurantId was checked to
+	// be an ID are left out unless it is one.
+	var views []struct {
+		RestaurantID uint
+		Count        int64
+	}
+	if err := h.db.Model(&AnalyticsEvent{}).
+		Select("(properties::jsonb->>'restaurantId')::bigint AS restaurant_id, COUNT(DISTINCT (user_id, occurred_at::date)) AS count").
+		Where("name = ? AND occurred_at >= ? AND properties::jsonb->>'restaurantId' ~ ?", "restaurant_view", since, `^[1-9][0-9]{0,15}$`).
+		Group("restaurant_id").Scan(&views).Error; err != nil {
+		return nil, err
+	}
+
+	scores := map[uint]*TrendingScore{}
+	score := func(id uint) *TrendingScore {
+		if scores[id] == nil {
+			scores[id] = &TrendingScore{RestaurantID: id}
+		}
+		return scores[id]
+	}
+	var maxReservations int64
+	var maxViews float64
+	days := TrendingWindow.Hours() / 24
+	for _, row := range reservations {
+		score(row.RestaurantID).Reservations = row.Count
+		maxReservations = max(maxReservations, row.Count)
+	}
+	for _, row := range views {
+		s := score(row.RestaurantID)
+		s.ViewsPerDay = float64(row.Count) / days
+		maxViews = max(maxViews, s.ViewsPerDay)
+	}
+	if len(scores) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]uint, 0, len(scores))
+	for id := range scores {
+		ids = append(ids, id)
+	}
+	var listed []struct {
+		ID     uint
+		Rating float64
+	}
+	if err := h.db.Model(&Restaurant{}).Scopes(Listed).Select("id, COALESCE(rating, 0) AS rating").Where("id IN ?", ids).
+		Scan(&listed).Error; err != nil {
+		return nil, err
+	}
+
+	ranking := make([]TrendingScore, 0, len(listed))
+	for _, restaurant := range listed {
+		s := scores[restaurant.ID]
+		s.Rating = restaurant.Rating
+		s.Score = trendingRatingWeight * restaurant.Rating / 5
+		if maxReservations > 0 {
+			s.Score += trendingReservationWeight * float64(s.Reservations) / float64(maxReservations)
+		}
+		if maxViews > 0 {
+			s.Score += trendingViewWeight * s.ViewsPerDay / maxViews
+		}
+		ranking = append(ranking, *s)
+	}
+	sort.Slice(ranking, func(i, j int) bool {
+		if ranking[i].Score != ranking[j].Score {
+			return ranking[i].Score > ranking[j].Score
+		}
+		return ranking[i].RestaurantID < ranking[j].RestaurantID
+	})
+	if len(ranking) > maxTrending {
+		ranking = ranking[:maxTrending]
+	}
+	return ranking, nil
+}
+
+// GetRestaurantsByIDs returns the listed restaurants with the IDs in the
+// order of the IDs, leaving out the ones no longer listed.
+func (h *RestaurantHandler) GetRestaurantsByIDs(ids []uint) ([]Restaurant, error) {
+	var restaurants []Restaurant
+	if err := h.db.Scopes(Listed, withListing).Where("id IN ?", ids).Find(&restaurants).Error; err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uint]Restaurant, len(restaurants))
+	for _, restaurant := range restaurants {
+		byID[restaurant.ID] = restaurant
+	}
+	ordered := make([]Restaurant, 0, len(restaurants))
+	for _, id := range ids {
+		if restaurant, ok := byID[id]; ok {
+			ordered = append(ordered, restaurant)
+		}
+	}
+	return ordered, nil
+}
@@ -0,0 +1,160 @@
+package models
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/punchanabu/redrice-backend-go/utils"
+	"gorm.io/gorm"
+)
+
+const backupCodeCount = 10
+
+const (
+	// TwoFactorMaxFailures is the number of wrong codes in a row after which
+	// the second factor of the account is locked.
+	TwoFactorMaxFailures = 5
+	// TwoFactorLockout is how long the second factor stays locked.
+	TwoFactorLockout = 15 * time.Minute
+)
+
+var ErrTwoFactorLocked = fmt.Errorf("too many wrong two-factor codes, please try again later")
+
+// TwoFactorBackupCode is a single-use recovery code for users who lost their
+// authenticator. Only the hash of the code is stored.
+type TwoFactorBackupCode struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"index"`
+	CodeHash  string `gorm:"index"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+type TwoFactorHandler struct {
+	db *gorm.DB
+}
+
+func NewTwoFactorHandler(db *gorm.DB) *TwoFactorHandler {
+	return &TwoFactorHandler{db}
+}
+
+// SetSecret stores a new, not yet confirmed, TOTP secret for the user.
+func (h *TwoFactorHandler) SetSecret(userID uint, secret string) error {
+	return h.db.Model(&User{}).Where("id = ?", userID).Update("two_factor_secret", secret).Error
+}
+
+// Enable turns on two-factor authentication and returns a fresh set of backup
+// codes. The TOTP step of the code confirming the enrollment cannot be used
+// to log in.
+func (h *TwoFactorHandler) Enable(userID uint, step int64) ([]string, error) {
+	var codes []string
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"two_factor_enabled":   true,
+			"two_factor_last_step": step,
+			"two_factor_failures":  0,
+		}).Error; err != nil {
+			return err
+		}
+
+		var err error
+		codes, err = NewTwoFactorHandler(tx).RegenerateBackupCodes(userID)
+		return err
+	})
+	return codes, err
+}
+
+func (h *TwoFactorHandler) Disable(userID uint) error {
+	return h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"two_factor_enabled":    false,
+			"two_factor_secret":     "",
+			"two_factor_failures":   0,
+			"two_factor_lock_until": nil,
+			"two_factor_last_step":  0,
+		}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&TwoFactorBackupCode{}).Error
+	})
+}
+
+// RegenerateBackupCodes replaces every backup code of the user.
+func (h *TwoFactorHandler) RegenerateBackupCodes(userID uint) ([]string, error) {
+	if err := h.db.Where("user_id = ?", userID).Delete(&TwoFactorBackupCode{}).Error; err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, backupCodeCount)
+	for i := 0; i < backupCodeCount; i++ {
+		raw := make([]byte, 5)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, err
+		}
+		code := hex.EncodeToString(raw)
+		code = fmt.Sprintf("%s-%s", code[:5], code[5:])
+
+		if err := h.db.Create(&TwoFactorBackupCode{UserID: userID, CodeHash: hashToken(code)}).Error; err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+
+	return codes, nil
+}
+
+// UseBackupCode consumes a backup code and reports whether it was valid.
+func (h *TwoFactorHandler) UseBackupCode(userID uint, code string) (bool, error) {
+	now := time.Now()
+	result := h.db.Model(&TwoFactorBackupCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hashToken(code)).
+		Update("used_at", &now)
+	return result.RowsAffected > 0, result.Error
+}
+
+// VerifyCode checks a TOTP or backup code of the user. A TOTP code is only
+// accepted once, codes of a step at or before the last used one are refused.
+// After TwoFactorMaxFailures wrong codes in a row every code is refused with
+// ErrTwoFactorLocked for TwoFactorLockout.
+func (h *TwoFactorHandler) VerifyCode(user *User, code string) (bool, error) {
+	now := time.Now()
+	if user.TwoFactorLockUntil != nil && now.Before(*user.TwoFactorLockUntil) {
+		return false, ErrTwoFactorLocked
+	}
+
+	if step, ok := utils.MatchTOTP(user.TwoFactorSecret, code, now); ok && user.TwoFactorSecret != "" {
+		// The condition on the step makes two requests with the same code
+		// race for a single use
+		result := h.db.Model(&User{}).Where("id = ? AND two_factor_last_step < ?", user.ID, step).Updates(map[string]interface{}{
+			"two_factor_last_step": step,
+			"two_factor_failures":  0,
+		})
+		if result.Error != nil {
+			return false, result.Error
+		}
+		if result.RowsAffected > 0 {
+			return true, nil
+		}
+	} else {
+		used, err := h.UseBackupCode(user.ID, code)
+		if err != nil {
+			return false, err
+		}
+		if used {
+			return true, h.db.Model(&User{}).Where("id = ?", user.ID).Update("two_factor_failures", 0).Error
+		}
+	}
+
+	if err := h.db.Model(&User{}).Where("id = ?", user.ID).
+		Update("two_factor_failures", gorm.Expr("two_factor_failures + 1")).Error; err != nil {
+		return false, err
+	}
+	lockUntil := now.Add(TwoFactorLockout)
+	if err := h.db.Model(&User{}).Where("id = ? AND two_factor_failures >= ?", user.ID, TwoFactorMaxFailures).
+		Updates(map[string]interface{}{"two_factor_failures": 0, "two_factor_lock_until": &lockUntil}).Error; err != nil {
+		return false, err
+	}
+	return false, nil
+}
@@ -1,21 +1,113 @@
 package models
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/punchanabu/redrice-backend-go/utils"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
 type User struct {
-	ID           uint   `gorm:"primaryKey"`
-	Name         string `json:"name"`
-	Email        string `json:"email" gorm:"unique"`
-	Telephone    string `json:"telephone" gorm:"unique"`
-	Role         string `json:"role"`
-	Password     string `json:"password"`
-	RestaurantId uint   `json:"restaurant_id"`
-	gorm.Model   `json:"-" swaggerignore:"true"`
+	ID                  uint       `gorm:"primaryKey"`
+	Name                string     `json:"name"`
+	Email               string     `json:"email" gorm:"unique"`
+	ContactEmail        string     `json:"-" gorm:"index" swaggerignore:"true"`
+	Telephone           string     `json:"telephone" gorm:"uniqueIndex:idx_users_telephone,where:telephone <> ''"`
+	Role                string     `json:"role" enums:"admin,restaurant_owner,user,guest"`
+	Password            string     `json:"password"`
+	RestaurantId        uint       `json:"restaurant_id"`
+	GoogleID            string     `json:"-" gorm:"index" swaggerignore:"true"`
+	FacebookID          string     `json:"-" gorm:"index" swaggerignore:"true"`
+	AppleID             string     `json:"-" gorm:"index" swaggerignore:"true"`
+	TwoFactorEnabled    bool       `json:"twoFactorEnabled" gorm:"default:false"`
+	TwoFactorSecret     string     `json:"-" swaggerignore:"true"`
+	TwoFactorFailures   int        `json:"-" gorm:"not null;default:0" swaggerignore:"true"`
+	TwoFactorLockUntil  *time.Time `json:"-" swaggerignore:"true"`
+	TwoFactorLastStep   int64      `json:"-" gorm:"not null;default:0" swaggerignore:"true"`
+	TelephoneVerifiedAt *time.Time `json:"telephoneVerifiedAt"`
+	TokensValidAfter    *time.Time `json:"-" swaggerignore:"true"`
+	ImageURL            string     `json:"imageUrl"`
+	Warnings            []string   `json:"warnings,omitempty" gorm:"-"`
+	SuspendedAt         *time.Time `json:"suspendedAt,omitempty"`
+	SuspendedUntil      *time.Time `json:"suspendedUntil,omitempty"`
+	SuspensionReason    string     `json:"suspensionReason,omitempty"`
+	// DeletionScheduledAt is when the account will be deleted, unless the user logs in before
+	DeletionScheduledAt *time.Time `json:"deletionScheduledAt,omitempty" gorm:"index"`
+	gorm.Model          `json:"-" swaggerignore:"true"`
+}
+
+// UserResponse is a user as returned by the API. It leaves out the password
+// hash, the identifiers of linked social accounts and other credentials.
+type UserResponse struct {
+	ID                  uint       `json:"ID" example:"42"`
+	Name                string     `json:"name" example:"Somchai"`
+	Email               string     `json:"email" example:"somchai@example.com"`
+	Telephone           string     `json:"telephone" example:"0812345678"`
+	Role                string     `json:"role" enums:"admin,restaurant_owner,user,guest"`
+	RestaurantId        uint       `json:"restaurant_id"`
+	TwoFactorEnabled    bool       `json:"twoFactorEnabled"`
+	TelephoneVerifiedAt *time.Time `json:"telephoneVerifiedAt"`
+	ImageURL            string     `json:"imageUrl"`
+	Warnings            []string   `json:"warnings,omitempty"`
+	SuspendedAt         *time.Time `json:"suspendedAt,omitempty"`
+	SuspendedUntil      *time.Time `json:"suspendedUntil,omitempty"`
+	SuspensionReason    string     `json:"suspensionReason,omitempty"`
+	DeletionScheduledAt *time.Time `json:"deletionScheduledAt,omitempty"`
+}
+
+func (u *User) Response() UserResponse {
+	return UserResponse{
+		ID:                  u.ID,
+		Name:                u.Name,
+		Email:               u.ContactAddress(),
+		Telephone:           u.Telephone,
+		Role:                u.Role,
+		RestaurantId:        u.RestaurantId,
+		TwoFactorEnabled:    u.TwoFactorEnabled,
+		TelephoneVerifiedAt: u.TelephoneVerifiedAt,
+		ImageURL:            u.ImageURL,
+		Warnings:            u.Warnings,
+		SuspendedAt:         u.SuspendedAt,
+		SuspendedUntil:      u.SuspendedUntil,
+		SuspensionReason:    u.SuspensionReason,
+		DeletionScheduledAt: u.DeletionScheduledAt,
+	}
+}
+
+func UserResponses(users []User) []UserResponse {
+	responses := make([]UserResponse, len(users))
+	for i := range users {
+		responses[i] = users[i].Response()
+	}
+	return responses
+}
+
+// MarshalJSON writes the user as its UserResponse, so that users nested in
+// other responses, e.g. the user of a reservation, never carry the password
+// hash either. The password field is still read when binding requests.
+func (u User) MarshalJSON() ([]byte, error) {
+	return json.Marshal(u.Response())
+}
+
+var ErrWrongPassword = fmt.Errorf("current password is incorrect")
+var ErrInvalidRole = fmt.Errorf("invalid role")
+var ErrTelephoneTaken = fmt.Errorf("telephone already exists")
+var ErrUserNotDeleted = fmt.Errorf("user is not deleted")
+var ErrGuestBlocked = fmt.Errorf("email belongs to a suspended or deleted account")
+var ErrMergeSelf = fmt.Errorf("cannot merge a user into itself")
+
+// socialIDColumns maps a social login provider to the column storing the
+// subject identifier it assigns to the user.
+var socialIDColumns = map[string]string{
+	"google":   "google_id",
+	"facebook": "facebook_id",
+	"apple":    "apple_id",
 }
 
 type UserHandler struct {
@@ -27,27 +119,51 @@ func NewUserHandler(db *gorm.DB) *UserHandler {
 }
 
 func (h UserHandler) CreateUser(user *User) error {
+	if user.Role == "" {
+		user.Role = RoleCustomer
+	} else if !IsValidRole(user.Role) {
+		return ErrInvalidRole
+	}
+
+	telephone, err := NormalizeTelephone(user.Telephone)
+	if err != nil {
+		return err
+	}
+	user.Telephone = telephone
+
 	// Check if email already exists
-	existingEmail, _ := h.GetUserByEmail(user.Email)
-	if existingEmail != nil {
-		return fmt.Errorf("email already exists")
+	taken, err := emailTaken(h.db, user.Email, 0)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return ErrEmailTaken
 	}
 
 	// Check if telephone already exists
-	existingTelephone, _ := h.GetUserByTelephone(user.Telephone)
-	if existingTelephone != nil {
-		return fmt.Errorf("telephone already exists")
+	if user.Telephone != "" {
+		existingTelephone, _ := h.GetUserByTelephone(user.Telephone)
+		if existingTelephone != nil {
+			return ErrTelephoneTaken
+		}
+	}
+
+	// Telephones are only verified through an SMS code
+	user.TelephoneVerifiedAt = nil
+
+	if err := utils.ValidatePassword(user.Password); err != nil {
+		return err
 	}
 
 	// Hash the password before storing
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), utils.BcryptCost())
 	if err != nil {
 		return err
 	}
 	user.Password = string(hashedPassword)
 
 	// Create the user
-	return h.db.Create(user).Error
+	return duplicateUserError(h.db.Create(user).Error)
 }
 
 func (h *UserHandler) CheckPassword(email, password string) bool {
@@ -65,20 +181,288 @@ func (h *UserHandler) GetUser(id uint) (*User, error) {
 	return &user, result.Error
 }
 
-func (h *UserHandler) GetUsers() ([]User, error) {
+// likeEscaper keeps the wildcards of LIKE patterns literal in user input.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// GetUsers returns one page of users ordered by ID with the number of users
+// matching the search. A non-empty query matches part of the name, email or
+// telephone, ignoring case.
+func (h *UserHandler) GetUsers(query string, deleted bool, limit, offset int) ([]User, int64, error) {
+	db := h.db.Model(&User{})
+	if deleted {
+		db = db.Unscoped().Where("deleted_at IS NOT NULL")
+	}
+	if query != "" {
+		pattern := "%" + likeEscaper.Replace(query) + "%"
+		db = db.Where("name ILIKE ? OR email ILIKE ? OR telephone ILIKE ?", pattern, pattern, pattern)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
 	var users []User
-	result := h.db.Find(&users)
-	return users, result.Error
+	result := db.Order("id").Limit(limit).Offset(offset).Find(&users)
+	return users, total, result.Error
 }
 
 func (h *UserHandler) UpdateUser(id uint, user *User) error {
-	result := h.db.Model(&User{}).Where("id = ?", id).Updates(user)
-	return result.Error
+	telephone, err := NormalizeTelephone(user.Telephone)
+	if err != nil {
+		return err
+	}
+	user.Telephone = telephone
+	if user.Telephone != "" {
+		if other, _ := h.GetUserByTelephone(user.Telephone); other != nil && other.ID != id {
+			return ErrTelephoneTaken
+		}
+	}
+
+	// A new telephone number has to be verified again
+	if user.Telephone != "" {
+		existing, err := h.GetUser(id)
+		if err == nil && existing.Telephone != user.Telephone {
+			if err := h.db.Model(&User{}).Where("id = ?", id).Update("telephone_verified_at", nil).Error; err != nil {
+				return err
+			}
+		}
+		user.TelephoneVerifiedAt = nil
+	}
+
+	// The email is only changed once the new address is confirmed, see
+	// EmailChangeHandler, and the role and restaurant only by ChangeRole
+	result := h.db.Model(&User{}).Where("id = ?", id).Omit("email", "role", "restaurant_id").Updates(user)
+	return duplicateUserError(result.Error)
+}
+
+// UserPatch lists the fields of a partial user update, nil fields are left
+// unchanged. The role and restaurant are only changed by ChangeRole.
+type UserPatch struct {
+	Name      *string
+	Telephone *string
+}
+
+// PatchUser writes only the fields set in the patch, including empty values,
+// and returns the updated user.
+func (h *UserHandler) PatchUser(id uint, patch UserPatch) (*User, error) {
+	existing, err := h.GetUser(id)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	if patch.Name != nil {
+		updates["name"] = *patch.Name
+	}
+	if patch.Telephone != nil {
+		telephone, err := NormalizeTelephone(*patch.Telephone)
+		if err != nil {
+			return nil, err
+		}
+		if telephone != existing.Telephone {
+			if telephone != "" {
+				if other, _ := h.GetUserByTelephone(telephone); other != nil && other.ID != id {
+					return nil, ErrTelephoneTaken
+				}
+			}
+			// A new telephone number has to be verified again
+			updates["telephone"] = telephone
+			updates["telephone_verified_at"] = nil
+		}
+	}
+
+	if len(updates) > 0 {
+		if err := h.db.Model(&User{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+			return nil, duplicateUserError(err)
+		}
+	}
+	return h.GetUser(id)
+}
+
+// SetAvatar stores the URL of the new profile picture of the user and returns
+// the URL of the previous one.
+func (h *UserHandler) SetAvatar(id uint, imageURL string) (string, error) {
+	user, err := h.GetUser(id)
+	if err != nil {
+		return "", err
+	}
+	if err := h.db.Model(&User{}).Where("id = ?", id).Update("image_url", imageURL).Error; err != nil {
+		return "", err
+	}
+	return user.ImageURL, nil
+}
+
+// HasVerifiedTelephone reports whether the user proved ownership of their
+// current telephone number.
+func (u *User) HasVerifiedTelephone() bool {
+	return u.Telephone != "" && u.TelephoneVerifiedAt != nil
 }
 
+// DeleteUser soft deletes the user. The row is kept, so their reservations and
+// comments still reference it, and RestoreUser can bring the account back.
 func (h *UserHandler) DeleteUser(id uint) error {
 	result := h.db.Delete(&User{}, id)
-	return result.Error
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// RestoreUser brings back a soft deleted user and cancels any deletion still
+// scheduled for the account.
+func (h *UserHandler) RestoreUser(id uint) (*User, error) {
+	var user User
+	if err := h.db.Unscoped().First(&user, id).Error; err != nil {
+		return nil, err
+	}
+	if !user.DeletedAt.Valid {
+		return nil, ErrUserNotDeleted
+	}
+
+	err := h.db.Unscoped().Model(&User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"deleted_at":            nil,
+		"deletion_scheduled_at": nil,
+	}).Error
+	if err != nil {
+		return nil, err
+	}
+	return h.GetUser(id)
+}
+
+// ScheduleDeletion marks the account for deletion at the given time, cancels
+// its upcoming reservations and logs it out everywhere.
+func (h *UserHandler) ScheduleDeletion(id uint, at time.Time) error {
+	return h.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&User{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"deletion_scheduled_at": at,
+			"tokens_valid_after":    now,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("user not found")
+		}
+
+		return tx.Model(&Reservation{}).
+			Where("user_id = ? AND status IN ? AND date_time > ?", id, []string{ReservationStatusPending, ReservationStatusConfirmed}, now).
+			Update("status", ReservationStatusCancelled).Error
+	})
+}
+
+// IsSuspended reports whether an admin suspended the account and the
+// suspension has not expired yet.
+func (u *User) IsSuspended(now time.Time) bool {
+	return u.SuspendedAt != nil && (u.SuspendedUntil == nil || now.Before(*u.SuspendedUntil))
+}
+
+// Suspend bans the user until the given time, or indefinitely when until is
+// nil, and cancels their pending reservations. It returns the cancelled
+// reservations.
+func (h *UserHandler) Suspend(id uint, reason string, until *time.Time) ([]Reservation, error) {
+	var cancelled []Reservation
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&User{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"suspended_at":      time.Now(),
+			"suspended_until":   until,
+			"suspension_reason": reason,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := tx.Where("user_id = ? AND status = ?", id, ReservationStatusPending).Find(&cancelled).Error; err != nil {
+			return err
+		}
+		if len(cancelled) == 0 {
+			return nil
+		}
+		ids := make([]uint, len(cancelled))
+		for i := range cancelled {
+			ids[i] = cancelled[i].ID
+			cancelled[i].Status = ReservationStatusCancelled
+		}
+		return tx.Model(&Reservation{}).Where("id IN ?", ids).Update("status", ReservationStatusCancelled).Error
+	})
+	return cancelled, err
+}
+
+// Unsuspend lifts the suspension of the user.
+func (h *UserHandler) Unsuspend(id uint) error {
+	result := h.db.Model(&User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"suspended_at":      nil,
+		"suspended_until":   nil,
+		"suspension_reason": "",
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// CancelDeletion keeps an account that was scheduled for deletion.
+func (h *UserHandler) CancelDeletion(id uint) error {
+	return h.db.Model(&User{}).
+		Where("id = ? AND deletion_scheduled_at IS NOT NULL", id).
+		Update("deletion_scheduled_at", nil).Error
+}
+
+// DeleteScheduledUsers deletes the accounts whose grace period ended before now
+// and returns how many were deleted. Their personal data and credentials are
+// erased first and the email is freed for a new account, the row is only kept
+// for the reservations and reviews referring to it.
+func (h *UserHandler) DeleteScheduledUsers(now time.Time) (int64, error) {
+	var deleted int64
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		var ids []uint
+		if err := tx.Model(&User{}).Where("deletion_scheduled_at <= ?", now).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		if err := tx.Model(&User{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+			"name":                  "Deleted user",
+			"email":                 gorm.Expr("'deleted-' || id || '@deleted.redrice.invalid'"),
+			"contact_email":         "",
+			"telephone":             "",
+			"password":              "",
+			"google_id":             "",
+			"facebook_id":           "",
+			"apple_id":              "",
+			"two_factor_enabled":    false,
+			"two_factor_secret":     "",
+			"telephone_verified_at": nil,
+			"tokens_valid_after":    now,
+			"image_url":             "",
+			"deletion_scheduled_at": nil,
+		}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("id IN ?", ids).Delete(&User{})
+		deleted = result.RowsAffected
+		return result.Error
+	})
+	return deleted, err
+}
+
+// GetUsersByRole returns every user with the role, ordered by ID.
+func (h *UserHandler) GetUsersByRole(role string) ([]User, error) {
+	var users []User
+	result := h.db.Where("role = ?", role).Order("id").Find(&users)
+	return users, result.Error
 }
 
 func (h *UserHandler) GetUserByEmail(email string) (*User, error) {
@@ -90,7 +474,12 @@ func (h *UserHandler) GetUserByEmail(email string) (*User, error) {
 	return &user, result.Error
 }
 
+// GetUserByTelephone finds the user with the number, which may be written in
+// any format NormalizeTelephone accepts.
 func (h *UserHandler) GetUserByTelephone(telephone string) (*User, error) {
+	if normalized, err := NormalizeTelephone(telephone); err == nil {
+		telephone = normalized
+	}
 	var user User
 	result := h.db.Where("telephone = ?", telephone).First(&user)
 	if result.Error != nil {
@@ -98,3 +487,308 @@ func (h *UserHandler) GetUserByTelephone(telephone string) (*User, error) {
 	}
 	return &user, result.Error
 }
+
+// MergeUsers folds the source account into the target account inside a single
+// transaction. Reservations, comments, favorites, follows, preferences,
+// consents, activity, restaurant claims and notifications are moved to the
+// target, profile fields and social logins that are empty on the target are
+// filled from the source, and the source account is removed afterwards. When
+// the source has the more privileged role the target is given it with
+// ChangeRole, whose result is returned, nil otherwise.
+func (h *UserHandler) MergeUsers(targetID, sourceID uint) (*User, *RoleChange, error) {
+	if targetID == sourceID {
+		return nil, nil, ErrMergeSelf
+	}
+
+	var merged User
+	var roleChange *RoleChange
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		var target, source User
+		if err := tx.First(&target, targetID).Error; err != nil {
+			return fmt.Errorf("target user: %w", err)
+		}
+		if err := tx.First(&source, sourceID).Error; err != nil {
+			return fmt.Errorf("source user: %w", err)
+		}
+
+		if err := tx.Model(&Reservation{}).Where("user_id = ?", source.ID).Update("user_id", target.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&Comment{}).Where("user_id = ?", source.ID).Update("user_id", target.ID).Error; err != nil {
+			return err
+		}
+		if err := moveFavorites(tx, target.ID, source.ID); err != nil {
+			return err
+		}
+		if err := moveFollows(tx, target.ID, source.ID); err != nil {
+			return err
+		}
+		if err := movePreferences(tx, target.ID, source.ID); err != nil {
+			return err
+		}
+		if err := moveConsents(tx, target.ID, source.ID); err != nil {
+			return err
+		}
+		for _, model := range []interface{}{&Activity{}, &RestaurantClaim{}, &Notification{}} {
+			if err := tx.Model(model).Where("user_id = ?", source.ID).Update("user_id", target.ID).Error; err != nil {
+				return err
+			}
+		}
+
+		// Conflict rules: the target keeps its own values, empty fields are
+		// taken from the source and the more privileged role wins.
+		updates := map[string]interface{}{}
+		if target.Name == "" && source.Name != "" {
+			updates["name"] = source.Name
+		}
+		if target.Telephone == "" && source.Telephone != "" {
+			updates["telephone"] = source.Telephone
+		}
+		if target.RestaurantId == 0 && source.RestaurantId != 0 {
+			updates["restaurant_id"] = source.RestaurantId
+		}
+		// The social logins of the source sign in to the merged account, a
+		// target already linked to another account of a provider keeps it.
+		if target.GoogleID == "" && source.GoogleID != "" {
+			updates["google_id"] = source.GoogleID
+		}
+		if target.FacebookID == "" && source.FacebookID != "" {
+			updates["facebook_id"] = source.FacebookID
+		}
+		if target.AppleID == "" && source.AppleID != "" {
+			updates["apple_id"] = source.AppleID
+		}
+
+		// Free the unique email and telephone and the social logins of the
+		// source before deleting it so the soft-deleted row cannot collide
+		// with the merged account.
+		placeholder := fmt.Sprintf("merged-%d-into-%d", source.ID, target.ID)
+		if err := tx.Model(&User{}).Where("id = ?", source.ID).Updates(map[string]interface{}{
+			"email":       placeholder,
+			"telephone":   placeholder,
+			"google_id":   "",
+			"facebook_id": "",
+			"apple_id":    "",
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&User{}, source.ID).Error; err != nil {
+			return err
+		}
+
+		if len(updates) > 0 {
+			if err := tx.Model(&User{}).Where("id = ?", target.ID).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+
+		if rolePriority[source.Role] > rolePriority[target.Role] {
+			restaurantID := target.RestaurantId
+			if restaurantID == 0 {
+				restaurantID = source.RestaurantId
+			}
+			change, err := NewUserHandler(tx).ChangeRole(target.ID, source.Role, restaurantID)
+			if err != nil {
+				return err
+			}
+			roleChange = change
+			// The previous values are the ones of the target before the merge
+			roleChange.PreviousRole = target.Role
+			roleChange.PreviousRestaurantID = target.RestaurantId
+		}
+
+		return tx.First(&merged, target.ID).Error
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &merged, roleChange, nil
+}
+
+func (h *UserHandler) UpdatePassword(id uint, password string) error {
+	if err := utils.ValidatePassword(password); err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), utils.BcryptCost())
+	if err != nil {
+		return err
+	}
+
+	// Tokens issued before the change stop working, see middleware.Auth
+	result := h.db.Model(&User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"password":           string(hashedPassword),
+		"tokens_valid_after": time.Now(),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("user not found")
+	}
+	return nil
+}
+
+// ChangePassword sets a new password after checking the current one.
+func (h *UserHandler) ChangePassword(id uint, currentPassword, newPassword string) error {
+	user, err := h.GetUser(id)
+	if err != nil {
+		return fmt.Errorf("user not found")
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(currentPassword)) != nil {
+		return ErrWrongPassword
+	}
+
+	return h.UpdatePassword(id, newPassword)
+}
+
+var ErrSocialEmailUnverified = fmt.Errorf("an account with this email exists and the provider did not verify the email")
+
+// FindOrCreateSocialUser returns the user linked to the subject identifier of a
+// social login provider. An existing account with the same email is linked to
+// the provider when the provider verified the email, otherwise a new account
+// with a random password is created.
+func (h *UserHandler) FindOrCreateSocialUser(provider, subject, email, name string, verified bool) (*User, error) {
+	column, ok := socialIDColumns[provider]
+	if !ok {
+		return nil, fmt.Errorf("unknown social login provider %q", provider)
+	}
+	if subject == "" {
+		return nil, fmt.Errorf("missing %s subject identifier", provider)
+	}
+
+	var user User
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where(column+" = ?", subject).Limit(1).Find(&user)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			return nil
+		}
+
+		if email != "" {
+			result = tx.Where("email = ?", email).Limit(1).Find(&user)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected > 0 {
+				// Anyone can put the email of someone else on an unverified profile
+				if !verified {
+					return ErrSocialEmailUnverified
+				}
+				return tx.Model(&user).Update(column, subject).Error
+			}
+		}
+
+		if email == "" {
+			return fmt.Errorf("%s did not provide an email address", provider)
+		}
+
+		// The user signs in through the provider, the password only has to be unguessable
+		hashedPassword, err := unguessablePassword()
+		if err != nil {
+			return err
+		}
+
+		user = User{Name: name, Email: email, Role: RoleCustomer, Password: hashedPassword}
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		return tx.Model(&user).Update(column, subject).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// unguessablePassword returns the hash of a random password for accounts that
+// are not created with one.
+func unguessablePassword() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(hex.EncodeToString(secret)), utils.BcryptCost())
+	return string(hashedPassword), err
+}
+
+// ContactAddress returns the address to email the user at. Guest records
+// keep the address they booked with in ContactEmail, their email is a
+// placeholder nobody can sign in with.
+func (u *User) ContactAddress() string {
+	if u.Role == RoleGuest {
+		return u.ContactEmail
+	}
+	return u.Email
+}
+
+// FindOrCreateGuest returns the guest record of a booking without signing in,
+// e.g. through the widget on the website of a restaurant. Guests are never
+// attached to an account, even one with the same email: the guest record of
+// the email is reused or a new one is created, with a placeholder email so it
+// can neither sign in nor keep the owner of the address from registering. The
+// booking is refused with ErrGuestBlocked when the email belongs to a
+// suspended or deleted account or guest.
+func (h *UserHandler) FindOrCreateGuest(name, email, telephone string) (*User, error) {
+	var user User
+	err := h.db.Transaction(func(tx *gorm.DB) error {
+		var matches []User
+		if err := tx.Unscoped().Where("email = ? OR (role = ? AND contact_email = ?)", email, RoleGuest, email).
+			Order("id DESC").Find(&matches).Error; err != nil {
+			return err
+		}
+		now := time.Now()
+		for _, match := range matches {
+			if match.DeletedAt.Valid || match.IsSuspended(now) {
+				return ErrGuestBlocked
+			}
+		}
+		for _, match := range matches {
+			if match.Role == RoleGuest {
+				user = match
+				return nil
+			}
+		}
+
+		hashedPassword, err := unguessablePassword()
+		if err != nil {
+			return err
+		}
+		placeholder := make([]byte, 16)
+		if _, err := rand.Read(placeholder); err != nil {
+			return err
+		}
+
+		// Telephones are unique, a number already used by another account or
+		// that is not valid is not kept
+		if telephone, err = NormalizeTelephone(telephone); err != nil {
+			telephone = ""
+		}
+		var taken int64
+		if err := tx.Model(&User{}).Where("telephone = ?", telephone).Count(&taken).Error; err != nil {
+			return err
+		}
+		if telephone == "" || taken > 0 {
+			telephone = ""
+		}
+
+		user = User{
+			Name:         name,
+			Email:        fmt.Sprintf("guest-%s@guest.redrice.invalid", hex.EncodeToString(placeholder)),
+			ContactEmail: email,
+			Telephone:    telephone,
+			Role:         RoleGuest,
+			Password:     hashedPassword,
+		}
+		return tx.Create(&user).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
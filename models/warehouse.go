@@ -0,0 +1,210 @@
+package models
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/punchanabu/redrice-backend-go/utils"
+	"gorm.io/gorm"
+)
+
+// WarehouseSchemaVersion is bumped whenever the columns of a dataset change.
+// Files of every version live under their own prefix, so the analytics team
+// can migrate at their pace.
+const WarehouseSchemaVersion = 1
+
+// maxWarehouseBackfillDays bounds how many missed days a run catches up on.
+const maxWarehouseBackfillDays = 7
+
+const warehouseDateFormat = "2006-01-02"
+
+// WarehouseDataset is a table exported for the analytics team.
+type WarehouseDataset struct {
+	Name        string   `json:"name" example:"reservations"`
+	Description string   `json:"description"`
+	Columns     []string `json:"columns"`
+	// query selects the rows of the day starting at from
+	query func(db *gorm.DB, from, to time.Time) *gorm.DB
+}
+
+// WarehouseDatasets are the datasets of the current schema version. Review
+// texts are left out, they are personal data the analysis does not need.
+var WarehouseDatasets = []WarehouseDataset{
+	{
+		Name:        "reservations",
+		Description: "Reservations created, changed or deleted during the day. A reservation changed on several days appears in every one of them, the latest updated_at wins.",
+		Columns:     []string{"id", "user_id", "restaurant_id", "status", "date_time", "exit_time", "table_num", "deposit_minor", "deposit_currency", "responded_at", "created_at", "updated_at", "deleted_at"},
+		query: func(db *gorm.DB, from, to time.Time) *gorm.DB {
+			return db.Table("reservations").
+				Where("(updated_at >= ? AND updated_at < ?) OR (deleted_at >= ? AND deleted_at < ?)", from, to, from, to)
+		},
+	},
+	{
+		Name:        "reviews",
+		Description: "Reviews created, changed or deleted during the day, without their text.",
+		Columns:     []string{"id", "user_id", "restaurant_id", "reservation_id", "rating", "sentiment", "verified", "anonymous", "date_time", "created_at", "updated_at", "deleted_at"},
+		query: func(db *gorm.DB, from, to time.Time) *gorm.DB {
+			return db.Table("comments").
+				Where("(updated_at >= ? AND updated_at < ?) OR (deleted_at >= ? AND deleted_at < ?)", from, to, from, to)
+		},
+	},
+	{
+		Name:        "restaurants",
+		Description: "Snapshot of every live restaurant at the time of the export.",
+		Columns:     []string{"id", "name", "price_range", "rating", "comment_count", "verified_rating", "verified_comment_count", "favorite_count", "latitude", "longitude", "timezone", "created_at", "updated_at"},
+		query: func(db *gorm.DB, from, to time.Time) *gorm.DB {
+			return db.Table("restaurants").Where("deleted_at IS NULL")
+		},
+	},
+}
+
+// WarehouseFile is a dataset partition written to S3.
+type WarehouseFile struct {
+	Dataset string `json:"dataset" example:"reservations"`
+	Key     string `json:"key" example:"warehouse/v1/reservations/date=2024-05-01/part-0.csv"`
+	Rows    int64  `json:"rows" example:"120"`
+}
+
+// WarehouseExport records the files exported for a day, so every day is
+// exported once.
+type WarehouseExport struct {
+	ID            uint            `gorm:"primaryKey" json:"-" swaggerignore:"true"`
+	Date          string          `gorm:"uniqueIndex" json:"date" example:"2024-05-01"`
+	SchemaVersion int             `json:"schemaVersion" example:"1"`
+	Files         []WarehouseFile `gorm:"serializer:json" json:"files"`
+	CreatedAt     time.Time       `json:"createdAt"`
+}
+
+// WarehouseKey is where a dataset partition of a day is stored. The date=
+// directory is the partition layout query engines such as Athena read.
+func WarehouseKey(dataset string, date string) string {
+	return fmt.Sprintf("warehouse/v%d/%s/date=%s/part-0.csv", WarehouseSchemaVersion, dataset, date)
+}
+
+type WarehouseHandler struct {
+	db *gorm.DB
+}
+
+func NewWarehouseHandler(db *gorm.DB) *WarehouseHandler {
+	return &WarehouseHandler{db}
+}
+
+// DaysToExport returns the complete UTC days not exported yet, oldest first,
+// going back from yesterday to the last exported day but at most
+// maxWarehouseBackfillDays.
+func (h *WarehouseHandler) DaysToExport(now time.Time) ([]time.Time, error) {
+	today := now.UTC().Truncate(24 * time.Hour)
+	first := today.AddDate(0, 0, -maxWarehouseBackfillDays)
+
+	var last WarehouseExport
+	err := h.db.Order("date DESC").Limit(1).Find(&last).Error
+	if err != nil {
+		return nil, err
+	}
+	if last.ID == 0 {
+		// First run, the history is loaded by hand if needed
+		first = today.AddDate(0, 0, -1)
+	} else if lastDay, err := time.Parse(warehouseDateFormat, last.Date); err == nil && lastDay.AddDate(0, 0, 1).After(first) {
+		first = lastDay.AddDate(0, 0, 1)
+	}
+
+	var days []time.Time
+	for day := first; day.Before(today); day = day.AddDate(0, 0, 1) {
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+// ExportDay writes every dataset of the UTC day starting at day to the
+// bucket as CSV and records the export.
+func (h *WarehouseHandler) ExportDay(bucket string, day time.Time) (*WarehouseExport, error) {
+	export := WarehouseExport{Date: day.Format(warehouseDateFormat), SchemaVersion: WarehouseSchemaVersion}
+	for _, dataset := range WarehouseDatasets {
+		content, rows, err := h.datasetCSV(dataset, day, day.AddDate(0, 0, 1))
+		if err != nil {
+			return nil, fmt.Errorf("export %s: %w", dataset.Name, err)
+		}
+		key := WarehouseKey(dataset.Name, export.Date)
+		if err := utils.UploadFileToS3(bucket, key, content, "text/csv"); err != nil {
+			return nil, err
+		}
+		export.Files = append(export.Files, WarehouseFile{Dataset: dataset.Name, Key: key, Rows: rows})
+	}
+
+	if err := h.db.Create(&export).Error; err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+// datasetCSV renders the rows of the dataset in [from, to) as CSV with a header.
+func (h *WarehouseHandler) datasetCSV(dataset WarehouseDataset, from, to time.Time) ([]byte, int64, error) {
+	rows, err := dataset.query(h.db, from, to).Select(dataset.Columns).Order("id").Rows()
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var content bytes.Buffer
+	writer := csv.NewWriter(&content)
+	if err := writer.Write(dataset.Columns); err != nil {
+		return nil, 0, err
+	}
+
+	values := make([]interface{}, len(dataset.Columns))
+	pointers := make([]interface{}, len(values))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	record := make([]string, len(values))
+	var count int64
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, 0, err
+		}
+		for i, value := range values {
+			record[i] = warehouseValue(value)
+		}
+		if err := writer.Write(record); err != nil {
+			return nil, 0, err
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	writer.Flush()
+	return content.Bytes(), count, writer.Error()
+}
+
+// warehouseValue formats a column for CSV: times in RFC 3339 UTC, NULL as empty.
+func warehouseValue(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return value.UTC().Format(time.RFC3339)
+	case []byte:
+		return string(value)
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(value)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// GetExports returns the exports of the days in [from, to), oldest first.
+func (h *WarehouseHandler) GetExports(from, to time.Time) ([]WarehouseExport, error) {
+	var exports []WarehouseExport
+	err := h.db.Where("date >= ? AND date < ?", from.Format(warehouseDateFormat), to.Format(warehouseDateFormat)).
+		Order("date").Find(&exports).Error
+	return exports, err
+}
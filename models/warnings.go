@@ -0,0 +1,79 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Data quality issues reported alongside a successful create or update. They
+// never block the request, they point owners at data worth fixing.
+const (
+	WarningTelephoneMalformed = "telephone looks malformed"
+	WarningOpenTimeMalformed  = "openTime should use the HH:MM format"
+	WarningCloseTimeMalformed = "closeTime should use the HH:MM format"
+	WarningSameOpenCloseTime  = "openTime and closeTime are the same"
+	WarningDescriptionShort   = "description is very short"
+	WarningImageSmall         = "image very small, at least 800x600 pixels is recommended"
+)
+
+// Images below these dimensions look blurry on the restaurant page.
+const (
+	minImageWidth  = 800
+	minImageHeight = 600
+)
+
+// minDescriptionLength is below which a description is considered too short to be useful.
+const minDescriptionLength = 20
+
+var telephonePattern = regexp.MustCompile(`^(\+66|0)[0-9]{8,9}$`)
+var clockTimePattern = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+
+// IsValidClockTime reports whether the value is a time of the day in the
+// HH:MM format, e.g. 09:30.
+func IsValidClockTime(value string) bool {
+	return clockTimePattern.MatchString(value)
+}
+
+// IsPlausibleTelephone reports whether the number looks like a Thai landline
+// or mobile number once spaces and dashes are removed.
+func IsPlausibleTelephone(telephone string) bool {
+	return telephonePattern.MatchString(strings.NewReplacer(" ", "", "-", "").Replace(telephone))
+}
+
+// ImageWarnings checks the dimensions of an uploaded image.
+func ImageWarnings(width, height int) []string {
+	if width < minImageWidth || height < minImageHeight {
+		return []string{WarningImageSmall}
+	}
+	return nil
+}
+
+// QualityWarnings checks the fields that are set on the restaurant, so it can
+// be used with the partial restaurant of an update.
+func (r *Restaurant) QualityWarnings() []string {
+	var warnings []string
+	if r.Telephone != "" && !IsPlausibleTelephone(r.Telephone) {
+		warnings = append(warnings, WarningTelephoneMalformed)
+	}
+	if r.OpenTime != "" && !clockTimePattern.MatchString(r.OpenTime) {
+		warnings = append(warnings, WarningOpenTimeMalformed)
+	}
+	if r.CloseTime != "" && !clockTimePattern.MatchString(r.CloseTime) {
+		warnings = append(warnings, WarningCloseTimeMalformed)
+	}
+	if r.OpenTime != "" && r.OpenTime == r.CloseTime {
+		warnings = append(warnings, WarningSameOpenCloseTime)
+	}
+	if r.Description != "" && len([]rune(strings.TrimSpace(r.Description))) < minDescriptionLength {
+		warnings = append(warnings, WarningDescriptionShort)
+	}
+	return warnings
+}
+
+// QualityWarnings checks the contact details of the user.
+func (u *User) QualityWarnings() []string {
+	if u.Telephone != "" && !IsPlausibleTelephone(u.Telephone) {
+		return []string{WarningTelephoneMalformed}
+	}
+	return nil
+}
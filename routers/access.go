@@ -0,0 +1,242 @@
+package routers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/config"
+	"github.com/punchanabu/redrice-backend-go/models"
+)
+
+const (
+	AccessPublic = "public"
+	AccessUser   = "user"
+	AccessOwner  = "restaurant_owner"
+	AccessAdmin  = "admin"
+	// AccessAPIKey routes only accept API keys with the scope of the route
+	AccessAPIKey = "api_key"
+)
+
+type RouteAccess struct {
+	Method string `json:"method" example:"GET"`
+	Path   string `json:"path" example:"/api/v1/restaurants/:id"`
+	Role   string `json:"role" example:"user"`
+	// Scope is set on routes that also accept partner API keys with this scope
+	Scope string `json:"scope,omitempty" example:"restaurants:read"`
+}
+
+// routeAccess lists the role required by every API endpoint. Every route
+// registered in UseRouter must appear here, the tests in access_test.go
+// fail otherwise.
+var routeAccess = []RouteAccess{
+	{"GET", "/.well-known/jwks.json", AccessPublic, ""},
+	{"GET", "/api/v1/status", AccessPublic, ""},
+	{"GET", "/api/v1/privacy/processing", AccessPublic, ""},
+	{"GET", "/api/v1/categories", AccessPublic, ""},
+	{"GET", "/api/v1/exchange-rates", AccessPublic, ""},
+	{"POST", "/api/v1/auth/signin", AccessPublic, ""},
+	{"POST", "/api/v1/auth/register", AccessPublic, ""},
+	{"POST", "/api/v1/auth/logout", AccessUser, ""},
+	{"POST", "/api/v1/auth/forgot-password", AccessPublic, ""},
+	{"POST", "/api/v1/auth/reset-password", AccessPublic, ""},
+	{"POST", "/api/v1/auth/magic-link", AccessPublic, ""},
+	{"POST", "/api/v1/auth/magic-link/verify", AccessPublic, ""},
+	{"POST", "/api/v1/auth/confirm-email", AccessPublic, ""},
+	{"GET", "/api/v1/auth/google", AccessPublic, ""},
+	{"GET", "/api/v1/auth/google/callback", AccessPublic, ""},
+	{"POST", "/api/v1/auth/facebook", AccessPublic, ""},
+	{"POST", "/api/v1/auth/apple", AccessPublic, ""},
+	{"POST", "/api/v1/auth/2fa", AccessPublic, ""},
+	{"POST", "/api/v1/auth/accept-invitation", AccessPublic, ""},
+	{"GET", "/api/v1/restaurants/:id/theme", AccessPublic, ""},
+	{"GET", "/api/v1/widget/restaurants/:id/availability", AccessAPIKey, models.ScopeWidget},
+	{"POST", "/api/v1/widget/restaurants/:id/reservations", AccessAPIKey, models.ScopeWidget},
+
+	{"GET", "/api/v1/restaurants", AccessUser, models.ScopeRestaurantsRead},
+	{"GET", "/api/v1/restaurants/search", AccessUser, models.ScopeRestaurantsRead},
+	{"GET", "/api/v1/restaurants/nearby", AccessUser, models.ScopeRestaurantsRead},
+	{"GET", "/api/v1/restaurants/trending", AccessUser, models.ScopeRestaurantsRead},
+	{"GET", "/api/v1/restaurants/:id", AccessUser, models.ScopeRestaurantsRead},
+	{"GET", "/api/v1/restaurants/:id/availability", AccessUser, models.ScopeRestaurantsRead},
+	{"GET", "/api/v1/restaurants/:id/blackouts", AccessUser, ""},
+	{"GET", "/api/v1/restaurants/:id/special-hours", AccessUser, ""},
+	{"GET", "/api/v1/restaurants/:id/wait", AccessUser, ""},
+	{"GET", "/api/v1/restaurants/:id/queue", AccessOwner, ""},
+	{"GET", "/api/v1/restaurants/:id/events", AccessUser, ""},
+	{"GET", "/api/v1/queue/:id", AccessUser, ""},
+	{"GET", "/api/v1/restaurants/:id/photos", AccessUser, models.ScopeRestaurantsRead},
+	{"GET", "/api/v1/restaurants/:id/menu", AccessUser, models.ScopeRestaurantsRead},
+	{"GET", "/api/v1/restaurants/:id/photos/pending", AccessOwner, ""},
+	{"GET", "/api/v1/restaurants/:id/scheduled-changes", AccessOwner, ""},
+	{"GET", "/api/v1/restaurants/:id/statement", AccessOwner, ""},
+	{"GET", "/api/v1/restaurants/:id/customers", AccessOwner, ""},
+	{"GET", "/api/v1/restaurants/:id/reservations/timeline", AccessOwner, ""},
+	{"GET", "/api/v1/restaurants/:id/analytics/fill-rates", AccessOwner, ""},
+	{"GET", "/api/v1/restaurants/:id/deposit-rules", AccessOwner, ""},
+	{"GET", "/api/v1/restaurants/:id/quality", AccessOwner, ""},
+	{"GET", "/api/v1/restaurants/:id/preview", AccessOwner, ""},
+	{"GET", "/api/v1/owner/summary", AccessOwner, ""},
+	{"GET", "/api/v1/reservations", AccessUser, ""},
+	{"GET", "/api/v1/reservations/:id", AccessUser, ""},
+	{"GET", "/api/v1/reservations/:id/wait", AccessUser, ""},
+	{"GET", "/api/v1/reservations/:id/receipt", AccessUser, ""},
+	{"GET", "/api/v1/me", AccessUser, ""},
+	{"POST", "/api/v1/me/password", AccessUser, ""},
+	{"POST", "/api/v1/me/email", AccessUser, ""},
+	{"GET", "/api/v1/me/sessions", AccessUser, ""},
+	{"DELETE", "/api/v1/me/sessions", AccessUser, ""},
+	{"DELETE", "/api/v1/me/sessions/:id", AccessUser, ""},
+	{"POST", "/api/v1/me/2fa/enroll", AccessUser, ""},
+	{"POST", "/api/v1/me/2fa/verify", AccessUser, ""},
+	{"POST", "/api/v1/me/2fa/backup-codes", AccessUser, ""},
+	{"DELETE", "/api/v1/me/2fa", AccessUser, ""},
+	{"POST", "/api/v1/me/phone/send-code", AccessUser, ""},
+	{"POST", "/api/v1/me/phone/verify", AccessUser, ""},
+	{"GET", "/api/v1/users/:id", AccessUser, ""},
+	{"GET", "/api/v1/users/:id/reservations", AccessUser, ""},
+	{"GET", "/api/v1/restaurants/:id/comments", AccessUser, models.ScopeCommentsRead},
+	{"GET", "/api/v1/comments", AccessUser, ""},
+	{"GET", "/api/v1/comments/:id", AccessUser, ""},
+	{"POST", "/api/v1/reservations", AccessUser, ""},
+	{"POST", "/api/v1/reservations/:id/receipt/send", AccessUser, ""},
+	{"POST", "/api/v1/comments", AccessUser, ""},
+	{"POST", "/api/v1/restaurants/:id/blackouts", AccessOwner, ""},
+	{"PUT", "/api/v1/restaurants/:id/special-hours/:date", AccessOwner, ""},
+	{"POST", "/api/v1/restaurants/:id/reservations/batch", AccessOwner, ""},
+	{"POST", "/api/v1/restaurants/:id/announcements", AccessOwner, ""},
+	{"POST", "/api/v1/restaurants/:id/queue", AccessUser, ""},
+	{"POST", "/api/v1/restaurants/:id/favorite", AccessUser, ""},
+	{"DELETE", "/api/v1/restaurants/:id/favorite", AccessUser, ""},
+	{"POST", "/api/v1/restaurants/:id/follow", AccessUser, ""},
+	{"POST", "/api/v1/restaurants/:id/claims", AccessUser, ""},
+	{"DELETE", "/api/v1/restaurants/:id/follow", AccessUser, ""},
+	{"POST", "/api/v1/restaurants/:id/images", AccessOwner, ""},
+	{"POST", "/api/v1/restaurants/:id/menu/categories", AccessOwner, ""},
+	{"POST", "/api/v1/restaurants/:id/menu/items", AccessOwner, ""},
+	{"POST", "/api/v1/restaurants/:id/deposit-rules", AccessOwner, ""},
+	{"POST", "/api/v1/restaurants/:id/submit", AccessOwner, ""},
+	{"POST", "/api/v1/restaurants/:id/theme/logo", AccessOwner, ""},
+	{"POST", "/api/v1/restaurants/:id/invitations/bulk", AccessOwner, ""},
+	{"POST", "/api/v1/restaurants/:id/scheduled-changes", AccessOwner, ""},
+	{"POST", "/api/v1/comments/:id/photos", AccessUser, ""},
+	{"PUT", "/api/v1/reservations/:id", AccessUser, ""},
+	{"PUT", "/api/v1/me/avatar", AccessUser, ""},
+	{"DELETE", "/api/v1/me", AccessUser, ""},
+	{"GET", "/api/v1/me/export", AccessUser, ""},
+	{"GET", "/api/v1/me/limits", AccessUser, ""},
+	{"GET", "/api/v1/me/preferences", AccessUser, ""},
+	{"PUT", "/api/v1/me/preferences", AccessUser, ""},
+	{"GET", "/api/v1/me/consents", AccessUser, ""},
+	{"PUT", "/api/v1/me/consents", AccessUser, ""},
+	{"GET", "/api/v1/me/experiments", AccessUser, ""},
+	{"GET", "/api/v1/me/claims", AccessUser, ""},
+	{"POST", "/api/v1/events", AccessUser, ""},
+	{"GET", "/api/v1/me/activity", AccessUser, ""},
+	{"GET", "/api/v1/me/favorites", AccessUser, ""},
+	{"GET", "/api/v1/me/feed", AccessUser, ""},
+	{"GET", "/api/v1/me/reservations", AccessUser, ""},
+	{"PATCH", "/api/v1/users/:id", AccessUser, ""},
+	{"PUT", "/api/v1/reservations/:id/status", AccessUser, ""},
+	{"PUT", "/api/v1/comments/:id", AccessUser, ""},
+	{"PUT", "/api/v1/restaurants/:id/booking-policy", AccessOwner, ""},
+	{"PUT", "/api/v1/restaurants/:id/tax-settings", AccessOwner, ""},
+	{"PUT", "/api/v1/restaurants/:id/theme", AccessOwner, ""},
+	{"PUT", "/api/v1/restaurants/:id/images/order", AccessOwner, ""},
+	{"PUT", "/api/v1/restaurants/:id/images/:imageId/cover", AccessOwner, ""},
+	{"PUT", "/api/v1/restaurants/:id/menu/categories/:categoryId", AccessOwner, ""},
+	{"PUT", "/api/v1/restaurants/:id/menu/items/:itemId", AccessOwner, ""},
+	{"PUT", "/api/v1/restaurants/:id/menu/items/:itemId/photo", AccessOwner, ""},
+	{"PUT", "/api/v1/restaurants/:id/deposit-rules/:ruleId", AccessOwner, ""},
+	{"PUT", "/api/v1/queue/:id/seat", AccessOwner, ""},
+	{"PUT", "/api/v1/comment-photos/:id/approval", AccessOwner, ""},
+	{"DELETE", "/api/v1/reservations/:id", AccessUser, ""},
+	{"DELETE", "/api/v1/comments/:id", AccessUser, ""},
+	{"DELETE", "/api/v1/restaurants/:id/blackouts/:blackoutId", AccessOwner, ""},
+	{"DELETE", "/api/v1/restaurants/:id/special-hours/:date", AccessOwner, ""},
+	{"DELETE", "/api/v1/restaurants/:id/images/:imageId", AccessOwner, ""},
+	{"DELETE", "/api/v1/restaurants/:id/menu/categories/:categoryId", AccessOwner, ""},
+	{"DELETE", "/api/v1/restaurants/:id/menu/items/:itemId", AccessOwner, ""},
+	{"DELETE", "/api/v1/restaurants/:id/deposit-rules/:ruleId", AccessOwner, ""},
+	{"DELETE", "/api/v1/restaurants/:id/scheduled-changes/:changeId", AccessOwner, ""},
+	{"DELETE", "/api/v1/queue/:id", AccessUser, ""},
+
+	{"GET", "/api/v1/admin/routes", AccessAdmin, ""},
+	{"GET", "/api/v1/admin/api-keys", AccessAdmin, ""},
+	{"GET", "/api/v1/admin/payouts", AccessAdmin, ""},
+	{"GET", "/api/v1/admin/audit-log", AccessAdmin, ""},
+	{"GET", "/api/v1/admin/email-templates", AccessAdmin, ""},
+	{"PUT", "/api/v1/admin/email-templates/:key", AccessAdmin, ""},
+	{"DELETE", "/api/v1/admin/email-templates/:key", AccessAdmin, ""},
+	{"POST", "/api/v1/admin/email-templates/:key/preview", AccessAdmin, ""},
+	{"GET", "/api/v1/admin/notifications/stats", AccessAdmin, ""},
+	{"GET", "/api/v1/admin/sla-alerts", AccessAdmin, ""},
+	{"GET", "/api/v1/admin/restaurants", AccessAdmin, ""},
+	{"POST", "/api/v1/admin/restaurants/import", AccessAdmin, ""},
+	{"GET", "/api/v1/admin/restaurants/performance", AccessAdmin, ""},
+	{"GET", "/api/v1/admin/restaurants/quality", AccessAdmin, ""},
+	{"PUT", "/api/v1/admin/restaurants/:id/status", AccessAdmin, ""},
+	{"GET", "/api/v1/admin/search-boosts", AccessAdmin, ""},
+	{"PUT", "/api/v1/admin/search-boosts/:key", AccessAdmin, ""},
+	{"GET", "/api/v1/admin/claims", AccessAdmin, ""},
+	{"POST", "/api/v1/admin/claims/:id/approve", AccessAdmin, ""},
+	{"POST", "/api/v1/admin/claims/:id/reject", AccessAdmin, ""},
+	{"POST", "/api/v1/admin/categories", AccessAdmin, ""},
+	{"PUT", "/api/v1/admin/categories/:id", AccessAdmin, ""},
+	{"DELETE", "/api/v1/admin/categories/:id", AccessAdmin, ""},
+	{"GET", "/api/v1/admin/warehouse/manifest", AccessAdmin, ""},
+	{"GET", "/api/v1/admin/backfills", AccessAdmin, ""},
+	{"POST", "/api/v1/admin/backfills/:key/runs", AccessAdmin, ""},
+	{"GET", "/api/v1/admin/backfill-runs", AccessAdmin, ""},
+	{"POST", "/api/v1/admin/backfill-runs/:id/pause", AccessAdmin, ""},
+	{"POST", "/api/v1/admin/backfill-runs/:id/resume", AccessAdmin, ""},
+	{"POST", "/api/v1/admin/incidents", AccessAdmin, ""},
+	{"PUT", "/api/v1/admin/incidents/:id", AccessAdmin, ""},
+	{"DELETE", "/api/v1/admin/incidents/:id", AccessAdmin, ""},
+	{"POST", "/api/v1/admin/impersonate/:userId", AccessAdmin, ""},
+	{"POST", "/api/v1/admin/users/:id/ban", AccessAdmin, ""},
+	{"POST", "/api/v1/admin/users/:id/unban", AccessAdmin, ""},
+	{"POST", "/api/v1/admin/api-keys", AccessAdmin, ""},
+	{"DELETE", "/api/v1/admin/api-keys/:id", AccessAdmin, ""},
+	{"GET", "/api/v1/users", AccessAdmin, ""},
+	{"POST", "/api/v1/users", AccessAdmin, ""},
+	{"POST", "/api/v1/users/merge", AccessAdmin, ""},
+	{"PUT", "/api/v1/users/:id", AccessAdmin, ""},
+	{"PUT", "/api/v1/users/:id/role", AccessAdmin, ""},
+	{"DELETE", "/api/v1/users/:id", AccessAdmin, ""},
+	{"POST", "/api/v1/users/:id/restore", AccessAdmin, ""},
+	{"POST", "/api/v1/restaurants/:id/restore", AccessAdmin, ""},
+	{"GET", "/api/v1/users/:id/activity", AccessAdmin, ""},
+	{"POST", "/api/v1/restaurants", AccessAdmin, ""},
+	{"PUT", "/api/v1/restaurants/:id", AccessAdmin, ""},
+	{"PATCH", "/api/v1/restaurants/:id", AccessAdmin, ""},
+	{"DELETE", "/api/v1/restaurants/:id", AccessAdmin, ""},
+	{"GET", "/api/v1/restaurants/:id/history", AccessAdmin, ""},
+	{"POST", "/api/v1/restaurants/:id/revert/:versionId", AccessAdmin, ""},
+}
+
+// RouteTable returns the access table including the documentation routes,
+// which depend on the swagger mode.
+func RouteTable() []RouteAccess {
+	table := append([]RouteAccess{}, routeAccess...)
+
+	switch config.SwaggerMode() {
+	case config.SwaggerPublic:
+		table = append(table, RouteAccess{Method: "GET", Path: "/swagger/*any", Role: AccessPublic}, RouteAccess{Method: "GET", Path: "/openapi.json", Role: AccessPublic})
+	case config.SwaggerAdmin:
+		table = append(table, RouteAccess{Method: "GET", Path: "/swagger/*any", Role: AccessAdmin}, RouteAccess{Method: "GET", Path: "/openapi.json", Role: AccessAdmin})
+	}
+
+	return table
+}
+
+// @Summary Get Route Access Table
+// @Description Lists every endpoint together with the role it requires: public, user, restaurant_owner or admin. Restaurant owners can only use the restaurant_owner routes on their own restaurant. Routes that also accept partner API keys list the required scope.
+// @Tags admin
+// @Produce json
+// @security BearerAuth
+// @Success 200 {array} RouteAccess "The route access table."
+// @Failure 401 {object} v1.ErrorResponse "Unauthorized"
+// @Router /admin/routes [get]
+func getRouteTable(c *gin.Context) {
+	c.JSON(http.StatusOK, RouteTable())
+}
@@ -0,0 +1,147 @@
+package routers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/config"
+	"github.com/punchanabu/redrice-backend-go/middleware"
+	"github.com/punchanabu/redrice-backend-go/models"
+)
+
+func TestMain(m *testing.M) {
+	// Keep the gin debug output and request logs quiet
+	gin.SetMode(gin.TestMode)
+	gin.DefaultWriter = io.Discard
+	os.Exit(m.Run())
+}
+
+// newTestRouter builds the router without the rate limiter, every request
+// of the tests comes from the same address.
+func newTestRouter() *gin.Engine {
+	return newRouter(config.RateLimitConfig{})
+}
+
+// probeRoute sends an empty request to the route and returns the status and
+// the error code of the response, if any.
+func probeRoute(t *testing.T, r *gin.Engine, method, path, token string) (int, string) {
+	t.Helper()
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = "0"
+		} else if strings.HasPrefix(segment, "*") {
+			segments[i] = "probe"
+		}
+	}
+
+	req := httptest.NewRequest(method, strings.Join(segments, "/"), nil)
+	req.Header.Set(middleware.ChaosHeader, "off")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var body struct {
+		Code string `json:"code"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &body)
+	return w.Code, body.Code
+}
+
+func customerToken(t *testing.T) string {
+	t.Helper()
+	token, err := middleware.GenerateToken("route-check@redrice.local", 0, models.RoleCustomer)
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+	return token
+}
+
+func TestRouteAccessTableMatchesRouter(t *testing.T) {
+	expected := map[string]bool{}
+	for _, route := range RouteTable() {
+		expected[route.Method+" "+route.Path] = true
+	}
+
+	for _, route := range newTestRouter().Routes() {
+		key := route.Method + " " + route.Path
+		if !expected[key] {
+			t.Errorf("%s is missing from the route access table", key)
+		}
+		delete(expected, key)
+	}
+	for key := range expected {
+		t.Errorf("%s is in the route access table but is not registered", key)
+	}
+}
+
+func TestRouteAccessRejectsMissingToken(t *testing.T) {
+	r := newTestRouter()
+	for _, route := range RouteTable() {
+		if route.Role == AccessPublic {
+			continue
+		}
+		t.Run(route.Method+" "+route.Path, func(t *testing.T) {
+			if status, _ := probeRoute(t, r, route.Method, route.Path, ""); status != http.StatusUnauthorized {
+				t.Errorf("requires %s but answered %d without a token", route.Role, status)
+			}
+		})
+	}
+}
+
+func TestRouteAccessRejectsCustomers(t *testing.T) {
+	r := newTestRouter()
+	token := customerToken(t)
+	wantCodes := map[string]string{
+		AccessAdmin: middleware.ErrorCodeAdminRequired,
+		AccessOwner: middleware.ErrorCodePermissionDenied,
+	}
+	for _, route := range RouteTable() {
+		wantCode, ok := wantCodes[route.Role]
+		if !ok {
+			continue
+		}
+		t.Run(route.Method+" "+route.Path, func(t *testing.T) {
+			if status, code := probeRoute(t, r, route.Method, route.Path, token); code != wantCode {
+				t.Errorf("requires %s but answered %d %q to a customer token", route.Role, status, code)
+			}
+		})
+	}
+}
+
+func TestRouteAccess(t *testing.T) {
+	r := newTestRouter()
+	token := customerToken(t)
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		token      string
+		wantStatus int
+		wantCode   string
+	}{
+		{"user listing needs a token", "GET", "/api/v1/users", "", http.StatusUnauthorized, ""},
+		{"user listing is admin only", "GET", "/api/v1/users", token, http.StatusUnauthorized, middleware.ErrorCodeAdminRequired},
+		{"role change is admin only", "PUT", "/api/v1/users/:id/role", token, http.StatusUnauthorized, middleware.ErrorCodeAdminRequired},
+		{"imports are admin only", "POST", "/api/v1/admin/restaurants/import", token, http.StatusUnauthorized, middleware.ErrorCodeAdminRequired},
+		{"owner summary needs a restaurant", "GET", "/api/v1/owner/summary", token, http.StatusForbidden, middleware.ErrorCodePermissionDenied},
+		{"widget needs an API key", "POST", "/api/v1/widget/restaurants/:id/reservations", "", http.StatusUnauthorized, ""},
+		{"widget rejects user tokens", "POST", "/api/v1/widget/restaurants/:id/reservations", token, http.StatusUnauthorized, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, code := probeRoute(t, r, tt.method, tt.path, tt.token)
+			if status != tt.wantStatus || code != tt.wantCode {
+				t.Errorf("%s %s = %d %q, want %d %q", tt.method, tt.path, status, code, tt.wantStatus, tt.wantCode)
+			}
+		})
+	}
+}
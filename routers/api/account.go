@@ -0,0 +1,56 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/config"
+	"github.com/punchanabu/redrice-backend-go/middleware"
+	"github.com/punchanabu/redrice-backend-go/models"
+)
+
+// accountDeletionInterval is how often accounts past their grace period are deleted.
+const accountDeletionInterval = time.Hour
+
+func deleteScheduledAccounts() error {
+	deleted, err := userHandler.DeleteScheduledUsers(time.Now())
+	if deleted > 0 {
+		log.Printf("Deleted %d accounts after their grace period", deleted)
+	}
+	return err
+}
+
+type DeleteAccountResponse struct {
+	Message             string    `json:"message" example:"Account scheduled for deletion"`
+	DeletionScheduledAt time.Time `json:"deletionScheduledAt"`
+}
+
+// @Summary Delete my account
+// @Description Schedules the deletion of the currently authenticated account after a grace period. Upcoming reservations are cancelled and every session is logged out. Logging in again before the deadline keeps the account. Once the grace period ends, the name, email, telephone and credentials of the account are erased and the email can be used for a new account.
+// @Tags user
+// @Produce json
+// @security BearerAuth
+// @Success 200 {object} DeleteAccountResponse "When the account will be deleted."
+// @Failure 401 {object} ErrorResponse "Unauthorized."
+// @Failure 500 {object} ErrorResponse "Internal server error while scheduling the deletion."
+// @Router /me [delete]
+func DeleteMe(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	at := time.Now().Add(config.AccountDeletionGracePeriod())
+	if err := userHandler.ScheduleDeletion(user.ID, at); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error scheduling account deletion"})
+		return
+	}
+	middleware.RecordActivity(c, user.ID, models.ActivityDeletionScheduled, map[string]interface{}{"deletionScheduledAt": at})
+
+	c.JSON(http.StatusOK, DeleteAccountResponse{
+		Message:             "Account scheduled for deletion",
+		DeletionScheduledAt: at,
+	})
+}
@@ -0,0 +1,182 @@
+package api
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt"
+)
+
+const (
+	appleIssuer  = "https://appleid.apple.com"
+	appleKeysURL = "https://appleid.apple.com/auth/keys"
+	appleKeysTTL = time.Hour
+)
+
+type AppleLoginDetails struct {
+	IdentityToken string `json:"identityToken" example:"eyJraWQiOi..."`
+	// Apple only shares the name with the app on the very first sign in
+	Name string `json:"name" example:"John Doe"`
+}
+
+type appleClaims struct {
+	Email string `json:"email"`
+	// Apple sends this either as a boolean or as the string "true"
+	EmailVerified interface{} `json:"email_verified"`
+	jwt.StandardClaims
+}
+
+func (c *appleClaims) emailVerified() bool {
+	switch v := c.EmailVerified.(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	}
+	return false
+}
+
+var appleKeys = struct {
+	sync.Mutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}{}
+
+// applePublicKey returns the key Apple signed the identity token with. The key
+// set is cached and refreshed when stale or when an unknown key id shows up.
+func applePublicKey(kid string) (*rsa.PublicKey, error) {
+	appleKeys.Lock()
+	defer appleKeys.Unlock()
+
+	if key, ok := appleKeys.keys[kid]; ok && time.Since(appleKeys.fetchedAt) < appleKeysTTL {
+		return key, nil
+	}
+
+	resp, err := http.Get(appleKeysURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("apple keys endpoint returned %s", resp.Status)
+	}
+
+	var body struct {
+		Keys []struct {
+			Kid string `json:"kid"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(body.Keys))
+	for _, k := range body.Keys {
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+
+	appleKeys.keys = keys
+	appleKeys.fetchedAt = time.Now()
+
+	key, ok := keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown apple key id %q", kid)
+	}
+	return key, nil
+}
+
+func verifyAppleIdentityToken(identityToken string) (*appleClaims, error) {
+	claims := &appleClaims{}
+
+	token, err := jwt.ParseWithClaims(identityToken, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+		}
+		kid, _ := token.Header["kid"].(string)
+		return applePublicKey(kid)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !token.Valid {
+		return nil, fmt.Errorf("invalid identity token")
+	}
+	if !claims.VerifyIssuer(appleIssuer, true) {
+		return nil, fmt.Errorf("unexpected issuer %q", claims.Issuer)
+	}
+	if !claims.VerifyAudience(os.Getenv("APPLE_CLIENT_ID"), true) {
+		return nil, fmt.Errorf("token was issued for %q", claims.Audience)
+	}
+
+	return claims, nil
+}
+
+// @Summary Sign in with Apple
+// @Description Verifies the identity token returned by Sign in with Apple and exchanges it for our JWT. Users are identified by their Apple subject identifier and linked by email to existing accounts.
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param credentials body AppleLoginDetails true "Apple identity token"
+// @Success 200 {object} LoginResponse "An object containing a JWT token for authentication."
+// @Failure 400 {object} ErrorResponse "The request was formatted incorrectly or missing required fields."
+// @Failure 401 {object} ErrorResponse "The identity token is invalid, expired or was issued for another app."
+// @Failure 500 {object} ErrorResponse "Internal server error, unable to process the request."
+// @Router /auth/apple [post]
+func AppleLogin(c *gin.Context) {
+	var details AppleLoginDetails
+	if err := c.ShouldBindJSON(&details); err != nil || details.IdentityToken == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input format! please check the input format"})
+		return
+	}
+
+	if os.Getenv("APPLE_CLIENT_ID") == "" {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Apple login is not configured"})
+		return
+	}
+
+	claims, err := verifyAppleIdentityToken(details.IdentityToken)
+	if err != nil {
+		log.Println("Invalid Apple identity token:", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid Apple identity token"})
+		return
+	}
+
+	// Only trust the email for linking when Apple verified it
+	email := claims.Email
+	if !claims.emailVerified() {
+		email = ""
+	}
+
+	user, err := userHandler.FindOrCreateSocialUser("apple", claims.Subject, email, details.Name, claims.emailVerified())
+	if err != nil {
+		log.Println("Error signing in with Apple:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error signing in with Apple"})
+		return
+	}
+
+	respondWithToken(c, user, "apple")
+}
@@ -1,43 +1,79 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/punchanabu/redrice-backend-go/middleware"
 	"github.com/punchanabu/redrice-backend-go/models"
+	"github.com/punchanabu/redrice-backend-go/utils"
 	"gorm.io/gorm"
 )
 
 var userHandler *models.UserHandler
 var restaurantHandler *models.RestaurantHandler
+var passwordResetHandler *models.PasswordResetHandler
+var twoFactorHandler *models.TwoFactorHandler
+var invitationHandler *models.InvitationHandler
+var sessionHandler *models.SessionHandler
+var magicLinkHandler *models.MagicLinkHandler
+var emailChangeHandler *models.EmailChangeHandler
+var emailTemplateHandler *models.EmailTemplateHandler
+var auditHandler *models.AuditHandler
 
 func InitializedAuthHandler(db *gorm.DB) {
 	userHandler = models.NewUserHandler(db)
 	restaurantHandler = models.NewRestaurantHandler(db)
+	passwordResetHandler = models.NewPasswordResetHandler(db)
+	twoFactorHandler = models.NewTwoFactorHandler(db)
+	invitationHandler = models.NewInvitationHandler(db)
+	sessionHandler = models.NewSessionHandler(db)
+	magicLinkHandler = models.NewMagicLinkHandler(db)
+	emailChangeHandler = models.NewEmailChangeHandler(db)
+	emailTemplateHandler = models.NewEmailTemplateHandler(db)
+	auditHandler = models.NewAuditHandler(db)
+	utils.RunEveryExclusive(db, accountDeletionInterval, "delete scheduled accounts", deleteScheduledAccounts)
+	utils.RunEvery(expiredSessionInterval, "delete expired sessions", deleteExpiredSessions)
 }
 
 type RegisterDetails struct {
-	Name         string `json:"name" example:"John Doe"`
-	Telephone    string `json:"telephone" example:"123-456-7890"`
-	Email        string `json:"email" example:"john.doe@example.com"`
-	Password     string `json:"password" example:"securePassword123"`
-	Role         string `json:"role" example:"user"`
-	RestaurantId uint   `json:"restaurant_id" example:"0"`
+	Name      string `json:"name" example:"John Doe"`
+	Telephone string `json:"telephone" example:"0812345678"`
+	Email     string `json:"email" example:"john.doe@example.com"`
+	Password  string `json:"password" example:"securePassword123"`
 }
 
 type RegisterResponse struct {
 	Message string `json:"message" example:"User registered successfully"`
 }
 
+// respondAccountDetailsError writes the error of an email or telephone that
+// is taken or malformed and reports whether err was one.
+func respondAccountDetailsError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, models.ErrEmailTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": "Email already exists"})
+	case errors.Is(err, models.ErrTelephoneTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": "Telephone already exists"})
+	case errors.Is(err, models.ErrInvalidTelephone):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		return false
+	}
+	return true
+}
+
 // @Summary Register a new user
-// @Description Creates a new user account with the provided details. Upon successful creation, the user can log in with their credentials.
+// @Description Creates a new user account with the provided details. Upon successful creation, the user can log in with their credentials. The telephone is stored in the E.164 format, numbers without a country code are taken to be Thai.
 // @Tags authentication
 // @Accept json
 // @Produce json
 // @Param user body RegisterDetails true "Register Credentials"
+// @Param X-Captcha-Token header string false "reCAPTCHA or Turnstile token, required when CAPTCHA is enabled"
 // @Success 200 {object} RegisterResponse "Confirmation of successful registration."
-// @Failure 400 {object} ErrorResponse "The request was formatted incorrectly or missing required fields."
+// @Failure 400 {object} PasswordPolicyErrorResponse "The request was formatted incorrectly, missing required fields, the telephone is not valid, or the password does not meet the requirements."
+// @Failure 409 {object} ErrorResponse "The email or telephone belongs to another user."
 // @Failure 500 {object} ErrorResponse "Internal server error, unable to process the request."
 // @Router /auth/register [post]
 func Register(c *gin.Context) {
@@ -48,22 +84,20 @@ func Register(c *gin.Context) {
 		return
 	}
 
-	// Check if the restaurant exists
-	if newUser.RestaurantId != 0 {
-		restaurant, err := restaurantHandler.GetRestaurant(newUser.RestaurantId)
-		if err != nil || restaurant == nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant ID"})
-			return
-		}
-	}
+	// Everyone signs up as a customer, other roles are given by an admin
+	newUser.Role = models.RoleCustomer
+	newUser.RestaurantId = 0
 
 	err := userHandler.CreateUser(&newUser)
 	if err != nil {
+		if respondPasswordPolicyError(c, err) || respondAccountDetailsError(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Something went wrong while! creating user: " + err.Error()})
 		return
 	}
 
-	token, err := middleware.GenerateToken(newUser.Email, newUser.ID, newUser.Role)
+	token, err := middleware.IssueToken(c, newUser.Email, newUser.ID, newUser.Role, false)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating token"})
 		return
@@ -94,11 +128,12 @@ type ErrorResponse struct {
 
 // Login a user
 // @Summary User Login
-// @Description Authenticates a user by their email and password, returning a JWT token for authorized access to protected endpoints if successful.
+// @Description Authenticates a user by their email and password, returning a JWT token for authorized access to protected endpoints if successful. Users with two-factor authentication receive a challenge token to complete at /auth/2fa instead.
 // @Tags authentication
 // @Accept json
 // @Produce json
 // @Param credentials body LoginDetails true "Login Credentials"
+// @Param X-Captcha-Token header string false "reCAPTCHA or Turnstile token, required when CAPTCHA is enabled"
 // @Success 200 {object} LoginResponse "An object containing a JWT token for authentication and a message indicating successful login."
 // @Failure 400 {object} ErrorResponse "The request was formatted incorrectly or missing required fields."
 // @Failure 401 {object} ErrorResponse "Authentication failed due to invalid login credentials."
@@ -130,17 +165,40 @@ func Login(c *gin.Context) {
 		return
 	}
 
-	token, err := middleware.GenerateToken(user.Email, user.ID, user.Role)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating token"})
+	respondWithToken(c, user, "password")
+}
+
+type LogoutResponse struct {
+	Message string `json:"message" example:"Logout successful"`
+}
+
+// Logout a user
+// @Summary User Logout
+// @Description Revokes the JWT used for the request so it cannot be used again, even before it expires.
+// @Tags authentication
+// @Produce json
+// @security BearerAuth
+// @Success 200 {object} LogoutResponse "Confirmation that the token has been revoked."
+// @Failure 401 {object} ErrorResponse "Missing, invalid or already revoked token."
+// @Failure 500 {object} ErrorResponse "Internal server error, unable to revoke the token."
+// @Router /auth/logout [post]
+func Logout(c *gin.Context) {
+	value, exist := c.Get("claims")
+	if !exist {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 		return
 	}
 
-	c.JSON(
-		http.StatusOK,
-		gin.H{
-			"token":   token,
-			"message": "Login successful",
-		},
-	)
+	claims, ok := value.(*middleware.Claims)
+	if !ok || claims.Id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Token cannot be revoked, please login again to get a new token"})
+		return
+	}
+
+	if err := middleware.RevokeToken(claims); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error revoking token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logout successful"})
 }
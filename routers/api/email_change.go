@@ -0,0 +1,125 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/mail"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/middleware"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"github.com/punchanabu/redrice-backend-go/utils"
+)
+
+const emailChangeTTL = 24 * time.Hour
+
+type EmailChangeDetails struct {
+	Email    string `json:"email" example:"new.address@example.com"`
+	Password string `json:"password" example:"password123"`
+}
+
+type EmailChangeConfirmDetails struct {
+	Token string `json:"token" example:"3f0c1d..."`
+}
+
+// @Summary Request an Email Change
+// @Description Starts changing the email of the authenticated user. The current password is required. A confirmation link is sent to the new address and the email only changes once it is confirmed with /auth/confirm-email. Requesting another change invalidates the earlier link.
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param email body EmailChangeDetails true "New email and current password"
+// @security BearerAuth
+// @Success 200 {object} MessageResponse "The confirmation link has been sent to the new address."
+// @Failure 400 {object} ErrorResponse "The input is malformed or the email is not a valid address or unchanged."
+// @Failure 401 {object} ErrorResponse "The current password is incorrect."
+// @Failure 409 {object} ErrorResponse "The email belongs to another user."
+// @Failure 500 {object} ErrorResponse "Internal server error, unable to process the request."
+// @Router /me/email [post]
+func RequestEmailChange(c *gin.Context) {
+	var details EmailChangeDetails
+	if err := c.ShouldBindJSON(&details); err != nil || details.Email == "" || details.Password == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input format! please check the input format"})
+		return
+	}
+	address, err := mail.ParseAddress(details.Email)
+	if err != nil || address.Address != details.Email {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid email address"})
+		return
+	}
+
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	if !userHandler.CheckPassword(user.Email, details.Password) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Current password is incorrect"})
+		return
+	}
+
+	token, err := emailChangeHandler.RequestChange(user, details.Email, emailChangeTTL)
+	switch {
+	case errors.Is(err, models.ErrEmailUnchanged):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "This is already the email of your account"})
+		return
+	case errors.Is(err, models.ErrEmailTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": "Email already exists"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error requesting email change"})
+		return
+	}
+
+	link := fmt.Sprintf("%s/confirm-email?token=%s", os.Getenv("FRONTEND_URL"), token)
+	data := map[string]string{"name": user.Name, "email": details.Email, "hours": strconv.Itoa(int(emailChangeTTL.Hours())), "link": link}
+
+	if err := emailTemplateHandler.Send(details.Email, models.EmailTemplateEmailChangeConfirm, data); err != nil {
+		log.Println("Error sending email change confirmation:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error sending confirmation email"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "A confirmation link has been sent to the new email"})
+}
+
+// @Summary Confirm an Email Change
+// @Description Applies the email change of the confirmation link sent to the new address. The previous address is told about the change and every existing session of the user is signed out.
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param token body EmailChangeConfirmDetails true "Token from the confirmation link"
+// @Success 200 {object} MessageResponse "The email has been changed."
+// @Failure 400 {object} ErrorResponse "The link is invalid, expired or already used."
+// @Failure 409 {object} ErrorResponse "The email has been taken by another user since the change was requested."
+// @Failure 500 {object} ErrorResponse "Internal server error, unable to process the request."
+// @Router /auth/confirm-email [post]
+func ConfirmEmailChange(c *gin.Context) {
+	var details EmailChangeConfirmDetails
+	if err := c.ShouldBindJSON(&details); err != nil || details.Token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input format! please check the input format"})
+		return
+	}
+
+	user, previousEmail, err := emailChangeHandler.ConfirmChange(details.Token)
+	if errors.Is(err, models.ErrEmailTaken) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Email already exists"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired confirmation link"})
+		return
+	}
+	middleware.RecordActivity(c, user.ID, models.ActivityEmailChanged, map[string]interface{}{"previousEmail": previousEmail})
+
+	data := map[string]string{"name": user.Name, "email": user.Email}
+	if err := emailTemplateHandler.Send(previousEmail, models.EmailTemplateEmailChanged, data); err != nil && !errors.Is(err, utils.ErrEmailNotConfigured) {
+		log.Printf("Failed to notify the previous email of user %d: %v", user.ID, err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Email changed successfully"})
+}
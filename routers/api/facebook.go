@@ -0,0 +1,141 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/models"
+)
+
+const facebookGraphURL = "https://graph.facebook.com/v19.0"
+
+type FacebookLoginDetails struct {
+	AccessToken string `json:"accessToken" example:"EAAB..."`
+}
+
+type facebookProfile struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// @Summary Sign in with Facebook
+// @Description Exchanges a Facebook user access token obtained by the client SDK for our JWT. The user is created on first login. Facebook does not tell whether the email was verified, so an existing account with the same email is not linked and the login is refused with 409.
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param credentials body FacebookLoginDetails true "Facebook access token"
+// @Success 200 {object} LoginResponse "An object containing a JWT token for authentication."
+// @Failure 400 {object} ErrorResponse "The request was formatted incorrectly or missing required fields."
+// @Failure 401 {object} ErrorResponse "The Facebook access token is invalid or was issued for another app."
+// @Failure 409 {object} ErrorResponse "An account with the email of the Facebook profile already exists."
+// @Failure 500 {object} ErrorResponse "Internal server error, unable to process the request."
+// @Router /auth/facebook [post]
+func FacebookLogin(c *gin.Context) {
+	var details FacebookLoginDetails
+	if err := c.ShouldBindJSON(&details); err != nil || details.AccessToken == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input format! please check the input format"})
+		return
+	}
+
+	if os.Getenv("FACEBOOK_APP_ID") == "" || os.Getenv("FACEBOOK_APP_SECRET") == "" {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Facebook login is not configured"})
+		return
+	}
+
+	if err := verifyFacebookToken(details.AccessToken); err != nil {
+		log.Println("Invalid Facebook token:", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid Facebook access token"})
+		return
+	}
+
+	profile, err := fetchFacebookProfile(details.AccessToken)
+	if err != nil {
+		log.Println("Error fetching Facebook profile:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching Facebook profile"})
+		return
+	}
+
+	// The Graph API does not tell whether the email was confirmed, so it is
+	// never trusted to link an existing account
+	user, err := userHandler.FindOrCreateSocialUser("facebook", profile.ID, profile.Email, profile.Name, false)
+	if errors.Is(err, models.ErrSocialEmailUnverified) {
+		c.JSON(http.StatusConflict, gin.H{"error": "An account with this email already exists, please sign in with it"})
+		return
+	}
+	if err != nil {
+		log.Println("Error signing in with Facebook:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error signing in with Facebook"})
+		return
+	}
+
+	respondWithToken(c, user, "facebook")
+}
+
+// verifyFacebookToken makes sure the token is valid and was issued for our app,
+// otherwise a token from any other Facebook app could be replayed here.
+func verifyFacebookToken(accessToken string) error {
+	appID := os.Getenv("FACEBOOK_APP_ID")
+
+	query := url.Values{}
+	query.Set("input_token", accessToken)
+	query.Set("access_token", appID+"|"+os.Getenv("FACEBOOK_APP_SECRET"))
+
+	resp, err := http.Get(facebookGraphURL + "/debug_token?" + query.Encode())
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("debug_token returned %s", resp.Status)
+	}
+
+	var body struct {
+		Data struct {
+			AppID   string `json:"app_id"`
+			IsValid bool   `json:"is_valid"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return err
+	}
+
+	if !body.Data.IsValid {
+		return fmt.Errorf("token is not valid")
+	}
+	if body.Data.AppID != appID {
+		return fmt.Errorf("token was issued for app %s", body.Data.AppID)
+	}
+
+	return nil
+}
+
+func fetchFacebookProfile(accessToken string) (*facebookProfile, error) {
+	query := url.Values{}
+	query.Set("fields", "id,name,email")
+	query.Set("access_token", accessToken)
+
+	resp, err := http.Get(facebookGraphURL + "/me?" + query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("graph /me returned %s", resp.Status)
+	}
+
+	var profile facebookProfile
+	if err := json.NewDecoder(resp.Body).Decode(&profile); err != nil {
+		return nil, err
+	}
+
+	return &profile, nil
+}
@@ -0,0 +1,181 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	googleAuthURL     = "https://accounts.google.com/o/oauth2/v2/auth"
+	googleTokenURL    = "https://oauth2.googleapis.com/token"
+	googleUserInfoURL = "https://openidconnect.googleapis.com/v1/userinfo"
+	oauthStateCookie  = "oauth_state"
+)
+
+type googleUserInfo struct {
+	Subject       string `json:"sub"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+}
+
+func newOAuthState() (string, error) {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
+
+// @Summary Sign in with Google
+// @Description Redirects the browser to Google's consent screen. Google redirects back to the callback endpoint afterwards.
+// @Tags authentication
+// @Success 307 "Redirect to Google."
+// @Failure 500 {object} ErrorResponse "Google login is not configured."
+// @Router /auth/google [get]
+func GoogleLogin(c *gin.Context) {
+	clientID := os.Getenv("GOOGLE_CLIENT_ID")
+	redirectURL := os.Getenv("GOOGLE_REDIRECT_URL")
+	if clientID == "" || redirectURL == "" {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Google login is not configured"})
+		return
+	}
+
+	state, err := newOAuthState()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating state"})
+		return
+	}
+
+	// The state cookie protects the callback against CSRF
+	c.SetCookie(oauthStateCookie, state, 600, "/", "", c.Request.TLS != nil, true)
+
+	query := url.Values{}
+	query.Set("client_id", clientID)
+	query.Set("redirect_uri", redirectURL)
+	query.Set("response_type", "code")
+	query.Set("scope", "openid email profile")
+	query.Set("state", state)
+	query.Set("prompt", "select_account")
+
+	c.Redirect(http.StatusTemporaryRedirect, googleAuthURL+"?"+query.Encode())
+}
+
+// @Summary Google Login Callback
+// @Description Exchanges the authorization code returned by Google, creates or links the user by email and returns the same JWT as the normal login.
+// @Tags authentication
+// @Produce json
+// @Param code query string true "Authorization code from Google"
+// @Param state query string true "State issued by /auth/google"
+// @Success 200 {object} LoginResponse "An object containing a JWT token for authentication."
+// @Failure 400 {object} ErrorResponse "Invalid state or authorization code."
+// @Failure 401 {object} ErrorResponse "The Google account email is not verified."
+// @Failure 500 {object} ErrorResponse "Internal server error, unable to process the request."
+// @Router /auth/google/callback [get]
+func GoogleCallback(c *gin.Context) {
+	state, err := c.Cookie(oauthStateCookie)
+	if err != nil || state == "" || state != c.Query("state") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid OAuth state"})
+		return
+	}
+	c.SetCookie(oauthStateCookie, "", -1, "/", "", c.Request.TLS != nil, true)
+
+	code := c.Query("code")
+	if code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing authorization code"})
+		return
+	}
+
+	accessToken, err := exchangeGoogleCode(code)
+	if err != nil {
+		log.Println("Error exchanging Google code:", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid authorization code"})
+		return
+	}
+
+	info, err := fetchGoogleUserInfo(accessToken)
+	if err != nil {
+		log.Println("Error fetching Google user info:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching Google profile"})
+		return
+	}
+
+	if !info.EmailVerified {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Google account email is not verified"})
+		return
+	}
+
+	user, err := userHandler.FindOrCreateSocialUser("google", info.Subject, info.Email, info.Name, info.EmailVerified)
+	if err != nil {
+		log.Println("Error signing in with Google:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error signing in with Google"})
+		return
+	}
+
+	respondWithToken(c, user, "google")
+}
+
+func exchangeGoogleCode(code string) (string, error) {
+	form := url.Values{}
+	form.Set("code", code)
+	form.Set("client_id", os.Getenv("GOOGLE_CLIENT_ID"))
+	form.Set("client_secret", os.Getenv("GOOGLE_CLIENT_SECRET"))
+	form.Set("redirect_uri", os.Getenv("GOOGLE_REDIRECT_URL"))
+	form.Set("grant_type", "authorization_code")
+
+	resp, err := http.Post(googleTokenURL, "application/x-www-form-urlencoded", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token endpoint returned %s", resp.Status)
+	}
+
+	var body struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", err
+	}
+	if body.AccessToken == "" {
+		return "", fmt.Errorf("token endpoint returned no access token")
+	}
+
+	return body.AccessToken, nil
+}
+
+func fetchGoogleUserInfo(accessToken string) (*googleUserInfo, error) {
+	req, err := http.NewRequest(http.MethodGet, googleUserInfoURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("userinfo endpoint returned %s", resp.Status)
+	}
+
+	var info googleUserInfo
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, err
+	}
+
+	return &info, nil
+}
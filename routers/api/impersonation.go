@@ -0,0 +1,98 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/middleware"
+	"github.com/punchanabu/redrice-backend-go/models"
+)
+
+const (
+	defaultImpersonationMinutes = 30
+	maxImpersonationMinutes     = 60
+)
+
+type ImpersonateDetails struct {
+	Minutes int `json:"minutes" example:"30"`
+}
+
+type ImpersonateResponse struct {
+	Token     string              `json:"token" example:"eyJhbGciOi..."`
+	ExpiresAt time.Time           `json:"expiresAt"`
+	User      models.UserResponse `json:"user"`
+}
+
+// @Summary Impersonate a User
+// @Description Issues a token acting as the user so support staff can reproduce a reported problem. The token lasts 30 minutes by default and at most 60, and every request made with it is recorded in the audit trail under the admin. Admin accounts cannot be impersonated.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param userId path int true "User ID" Format(int64)
+// @Param details body ImpersonateDetails false "How many minutes the token lasts"
+// @security BearerAuth
+// @Success 200 {object} ImpersonateResponse "The impersonation token."
+// @Failure 400 {object} ErrorResponse "Invalid user ID or duration, or the user cannot be impersonated."
+// @Failure 404 {object} ErrorResponse "User not found."
+// @Router /admin/impersonate/{userId} [post]
+func Impersonate(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user id"})
+		return
+	}
+
+	details := ImpersonateDetails{Minutes: defaultImpersonationMinutes}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&details); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+			return
+		}
+	}
+	if details.Minutes < 1 || details.Minutes > maxImpersonationMinutes {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Minutes must be between 1 and %d", maxImpersonationMinutes)})
+		return
+	}
+
+	claims := c.MustGet("claims").(*middleware.Claims)
+	if claims.ImpersonatorID != 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot impersonate while impersonating"})
+		return
+	}
+
+	user, err := userHandler.GetUser(uint(idInt))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	if user.ID == claims.UserId || user.Role == models.RoleAdmin {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Admin accounts cannot be impersonated"})
+		return
+	}
+
+	token, expiresAt, err := middleware.IssueImpersonationToken(c, claims.UserId, user, time.Duration(details.Minutes)*time.Minute)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating token"})
+		return
+	}
+
+	entry := models.AuditEntry{
+		ActorID:      claims.UserId,
+		UserID:       user.ID,
+		Impersonated: true,
+		Action:       models.AuditActionImpersonate,
+		Path:         c.Request.URL.Path,
+		Status:       http.StatusOK,
+		IP:           c.ClientIP(),
+	}
+	if err := auditHandler.Record(&entry); err != nil {
+		log.Printf("Failed to record impersonation of user %d by admin %d: %v", user.ID, claims.UserId, err)
+	}
+
+	c.JSON(http.StatusOK, ImpersonateResponse{Token: token, ExpiresAt: expiresAt, User: user.Response()})
+}
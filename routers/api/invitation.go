@@ -0,0 +1,61 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/middleware"
+	"github.com/punchanabu/redrice-backend-go/models"
+)
+
+type AcceptInvitationDetails struct {
+	Token     string `json:"token" example:"3f0c1d..."`
+	Name      string `json:"name" example:"John Doe"`
+	Telephone string `json:"telephone" example:"1234567890"`
+	Password  string `json:"password" example:"securePassword123"`
+}
+
+// @Summary Accept a Staff Invitation
+// @Description Creates the account of an invited staff member using the token received by email. The account gets the email and restaurant of the invitation.
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param invitation body AcceptInvitationDetails true "Invitation token and account details"
+// @Success 200 {object} LoginResponse "An object containing a JWT token for authentication."
+// @Failure 400 {object} PasswordPolicyErrorResponse "The token is invalid, expired or already used, the input is malformed, or the password does not meet the requirements."
+// @Failure 409 {object} ErrorResponse "The email or telephone belongs to another user."
+// @Router /auth/accept-invitation [post]
+func AcceptInvitation(c *gin.Context) {
+	var details AcceptInvitationDetails
+	if err := c.ShouldBindJSON(&details); err != nil || details.Token == "" || details.Password == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input format! please check the input format"})
+		return
+	}
+
+	user := models.User{
+		Name:      details.Name,
+		Telephone: details.Telephone,
+		Password:  details.Password,
+	}
+	if err := invitationHandler.AcceptInvitation(details.Token, &user); err != nil {
+		if respondPasswordPolicyError(c, err) || respondAccountDetailsError(c, err) {
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Could not accept invitation: " + err.Error()})
+		return
+	}
+
+	token, err := middleware.IssueToken(c, user.Email, user.ID, user.Role, false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating token"})
+		return
+	}
+
+	c.JSON(
+		http.StatusOK,
+		gin.H{
+			"message": "User registered successfully",
+			"token":   token,
+		},
+	)
+}
@@ -0,0 +1,16 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/middleware"
+)
+
+// JWKS publishes the public keys our RS256 tokens are signed with so other
+// services can verify them by key id. It lives outside the API base path at
+// the well-known location and may be cached for a few minutes.
+func JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, middleware.PublicKeys())
+}
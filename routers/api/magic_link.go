@@ -0,0 +1,93 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/models"
+)
+
+const magicLinkTTL = 15 * time.Minute
+
+type MagicLinkDetails struct {
+	Email string `json:"email" example:"user@example.com"`
+}
+
+type MagicLinkVerifyDetails struct {
+	Token string `json:"token" example:"3f0c1d..."`
+}
+
+// @Summary Request a Magic Login Link
+// @Description Sends an email with a single-use link that signs the user in without a password. The response is the same whether or not the email is registered.
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param email body MagicLinkDetails true "Account email"
+// @Success 200 {object} MessageResponse "The login link has been sent if the account exists."
+// @Failure 400 {object} ErrorResponse "The request was formatted incorrectly or missing required fields."
+// @Failure 500 {object} ErrorResponse "Internal server error, unable to process the request."
+// @Router /auth/magic-link [post]
+func RequestMagicLink(c *gin.Context) {
+	var details MagicLinkDetails
+	if err := c.ShouldBindJSON(&details); err != nil || details.Email == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input format! please check the input format"})
+		return
+	}
+
+	response := gin.H{"message": "If the email is registered, a login link has been sent"}
+
+	user, err := userHandler.GetUserByEmail(details.Email)
+	if err != nil || user == nil {
+		// Do not reveal whether the email exists
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	token, err := magicLinkHandler.CreateToken(user.ID, magicLinkTTL)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating login link"})
+		return
+	}
+
+	link := fmt.Sprintf("%s/magic-link?token=%s", os.Getenv("FRONTEND_URL"), token)
+	data := map[string]string{"name": user.Name, "minutes": strconv.Itoa(int(magicLinkTTL.Minutes())), "link": link}
+
+	if err := emailTemplateHandler.Send(user.Email, models.EmailTemplateMagicLink, data); err != nil {
+		log.Println("Error sending magic link email:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error sending login email"})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Sign In with a Magic Link
+// @Description Exchanges the token of a magic login link for a JWT token. Users with two-factor authentication receive a challenge token to complete at /auth/2fa instead.
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param token body MagicLinkVerifyDetails true "Token from the login link"
+// @Success 200 {object} LoginResponse "An object containing a JWT token for authentication."
+// @Failure 400 {object} ErrorResponse "The request was formatted incorrectly or missing required fields."
+// @Failure 401 {object} ErrorResponse "The link is invalid, expired or already used."
+// @Router /auth/magic-link/verify [post]
+func VerifyMagicLink(c *gin.Context) {
+	var details MagicLinkVerifyDetails
+	if err := c.ShouldBindJSON(&details); err != nil || details.Token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input format! please check the input format"})
+		return
+	}
+
+	user, err := magicLinkHandler.ConsumeToken(details.Token)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Could not sign in: " + err.Error()})
+		return
+	}
+
+	respondWithToken(c, user, "magic_link")
+}
@@ -0,0 +1,179 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/middleware"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"github.com/punchanabu/redrice-backend-go/utils"
+)
+
+const passwordResetTTL = time.Hour
+
+type ForgotPasswordDetails struct {
+	Email string `json:"email" example:"user@example.com"`
+}
+
+type ResetPasswordDetails struct {
+	Token    string `json:"token" example:"3f0c1d..."`
+	Password string `json:"password" example:"newSecurePassword123"`
+}
+
+type MessageResponse struct {
+	Message string `json:"message" example:"Operation successful"`
+}
+
+type PasswordPolicyErrorResponse struct {
+	Error  string              `json:"error" example:"Password does not meet the requirements"`
+	Fields map[string][]string `json:"fields"`
+}
+
+// respondPasswordPolicyError writes a 400 with the field-level messages when
+// err is a password policy violation and reports whether it did.
+func respondPasswordPolicyError(c *gin.Context, err error) bool {
+	var policyErr *utils.PasswordPolicyError
+	if !errors.As(err, &policyErr) {
+		return false
+	}
+
+	c.JSON(http.StatusBadRequest, gin.H{"error": "Password does not meet the requirements", "fields": policyErr.Fields()})
+	return true
+}
+
+// @Summary Request a Password Reset
+// @Description Sends an email containing a single-use password reset link to the given address. The response is the same whether or not the email is registered.
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param email body ForgotPasswordDetails true "Account email"
+// @Success 200 {object} MessageResponse "The reset email has been sent if the account exists."
+// @Failure 400 {object} ErrorResponse "The request was formatted incorrectly or missing required fields."
+// @Failure 500 {object} ErrorResponse "Internal server error, unable to process the request."
+// @Router /auth/forgot-password [post]
+func ForgotPassword(c *gin.Context) {
+	var details ForgotPasswordDetails
+	if err := c.ShouldBindJSON(&details); err != nil || details.Email == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input format! please check the input format"})
+		return
+	}
+
+	response := gin.H{"message": "If the email is registered, a password reset link has been sent"}
+
+	user, err := userHandler.GetUserByEmail(details.Email)
+	if err != nil || user == nil {
+		// Do not reveal whether the email exists
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	token, err := passwordResetHandler.CreateToken(user.ID, passwordResetTTL)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating reset token"})
+		return
+	}
+
+	link := fmt.Sprintf("%s/reset-password?token=%s", os.Getenv("FRONTEND_URL"), token)
+	data := map[string]string{"name": user.Name, "minutes": strconv.Itoa(int(passwordResetTTL.Minutes())), "link": link}
+
+	if err := emailTemplateHandler.Send(user.Email, models.EmailTemplatePasswordReset, data); err != nil {
+		log.Println("Error sending password reset email:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error sending reset email"})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Reset Password
+// @Description Sets a new password using the token received by email. Tokens expire after one hour and can only be used once. Every existing session of the user is signed out.
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param reset body ResetPasswordDetails true "Reset token and new password"
+// @Success 200 {object} MessageResponse "The password has been changed."
+// @Failure 400 {object} PasswordPolicyErrorResponse "The token is invalid, expired or already used, the input is malformed, or the password does not meet the requirements."
+// @Router /auth/reset-password [post]
+func ResetPassword(c *gin.Context) {
+	var details ResetPasswordDetails
+	if err := c.ShouldBindJSON(&details); err != nil || details.Token == "" || details.Password == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input format! please check the input format"})
+		return
+	}
+
+	userID, err := passwordResetHandler.ResetPassword(details.Token, details.Password)
+	if err != nil {
+		if respondPasswordPolicyError(c, err) {
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Could not reset password: " + err.Error()})
+		return
+	}
+	middleware.RecordActivity(c, userID, models.ActivityPasswordChanged, map[string]interface{}{"method": "reset"})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password has been reset successfully"})
+}
+
+type ChangePasswordDetails struct {
+	CurrentPassword string `json:"currentPassword" example:"oldPassword123"`
+	NewPassword     string `json:"newPassword" example:"newSecurePassword123"`
+}
+
+// @Summary Change Password
+// @Description Changes the password of the authenticated user. The current password is required. Every existing session is signed out and a new token is returned.
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param passwords body ChangePasswordDetails true "Current and new password"
+// @security BearerAuth
+// @Success 200 {object} LoginResponse "A new JWT token replacing the revoked sessions."
+// @Failure 400 {object} PasswordPolicyErrorResponse "The input is malformed or the new password does not meet the requirements."
+// @Failure 401 {object} ErrorResponse "The current password is incorrect."
+// @Router /me/password [post]
+func ChangePassword(c *gin.Context) {
+	var details ChangePasswordDetails
+	if err := c.ShouldBindJSON(&details); err != nil || details.CurrentPassword == "" || details.NewPassword == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input format! please check the input format"})
+		return
+	}
+
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	if err := userHandler.ChangePassword(user.ID, details.CurrentPassword, details.NewPassword); err != nil {
+		if respondPasswordPolicyError(c, err) {
+			return
+		}
+		if errors.Is(err, models.ErrWrongPassword) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Current password is incorrect"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error changing password"})
+		return
+	}
+	middleware.RecordActivity(c, user.ID, models.ActivityPasswordChanged, map[string]interface{}{"method": "change"})
+
+	// The new token keeps the second factor of the current session
+	claims := c.MustGet("claims").(*middleware.Claims)
+	token, err := middleware.ReissueToken(c, user, claims.MFA)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating token"})
+		return
+	}
+
+	c.JSON(
+		http.StatusOK,
+		gin.H{
+			"token":   token,
+			"message": "Password changed successfully",
+		},
+	)
+}
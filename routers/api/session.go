@@ -0,0 +1,100 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/middleware"
+	"github.com/punchanabu/redrice-backend-go/models"
+)
+
+// expiredSessionInterval is how often the expired sessions are deleted.
+const expiredSessionInterval = time.Hour
+
+func deleteExpiredSessions() error {
+	return sessionHandler.DeleteExpiredSessions(time.Now())
+}
+
+type RevokeSessionsResponse struct {
+	Revoked int `json:"revoked" example:"2"`
+}
+
+// @Summary List My Sessions
+// @Description Lists the devices currently signed in to the account of the authenticated user, with the user agent, IP address and time of login. The session making the request is marked as current.
+// @Tags authentication
+// @Produce json
+// @security BearerAuth
+// @Success 200 {array} models.Session "The active sessions, newest first."
+// @Failure 500 {object} ErrorResponse "Internal server error while fetching the sessions."
+// @Router /me/sessions [get]
+func GetSessions(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	sessions, err := sessionHandler.GetActiveSessions(user.ID, user.TokensValidAfter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching sessions"})
+		return
+	}
+
+	claims := c.MustGet("claims").(*middleware.Claims)
+	for i := range sessions {
+		sessions[i].Current = sessions[i].TokenID == claims.Id
+	}
+
+	if sessions == nil {
+		sessions = []models.Session{}
+	}
+	c.JSON(http.StatusOK, sessions)
+}
+
+// @Summary Revoke a Session
+// @Description Signs out a single session of the authenticated user.
+// @Tags authentication
+// @Produce json
+// @Param id path int true "Session ID" Format(int64)
+// @security BearerAuth
+// @Success 204 "Session revoked."
+// @Failure 400 {object} ErrorResponse "Invalid session ID."
+// @Failure 404 {object} ErrorResponse "Session not found."
+// @Router /me/sessions/{id} [delete]
+func RevokeSession(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session id"})
+		return
+	}
+
+	id, _ := c.Get("id")
+	if err := sessionHandler.RevokeSession(id.(uint), uint(idInt)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Revoke My Other Sessions
+// @Description Signs out every session of the authenticated user except the one making the request.
+// @Tags authentication
+// @Produce json
+// @security BearerAuth
+// @Success 200 {object} RevokeSessionsResponse "The number of sessions revoked."
+// @Failure 500 {object} ErrorResponse "Internal server error while revoking the sessions."
+// @Router /me/sessions [delete]
+func RevokeOtherSessions(c *gin.Context) {
+	id, _ := c.Get("id")
+	claims := c.MustGet("claims").(*middleware.Claims)
+
+	revoked, err := sessionHandler.RevokeOtherSessions(id.(uint), claims.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error revoking sessions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"revoked": revoked})
+}
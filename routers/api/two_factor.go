@@ -0,0 +1,348 @@
+package api
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/middleware"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"github.com/punchanabu/redrice-backend-go/utils"
+)
+
+const totpIssuer = "RedRice"
+
+type TwoFactorLoginDetails struct {
+	Token string `json:"token" example:"eyJhbGciOi..."`
+	Code  string `json:"code" example:"123456"`
+}
+
+type TwoFactorCodeDetails struct {
+	Code string `json:"code" example:"123456"`
+}
+
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired bool   `json:"twoFactorRequired" example:"true"`
+	Token             string `json:"token" example:""`
+	Message           string `json:"message" example:"Two-factor authentication required"`
+}
+
+type TwoFactorEnrollResponse struct {
+	Secret     string `json:"secret" example:"JBSWY3DPEHPK3PXP"`
+	OtpauthURL string `json:"otpauthUrl" example:"otpauth://totp/RedRice:user@example.com?secret=JBSWY3DPEHPK3PXP"`
+}
+
+type TwoFactorEnabledResponse struct {
+	Token       string   `json:"token" example:""`
+	BackupCodes []string `json:"backupCodes"`
+}
+
+type BackupCodesResponse struct {
+	BackupCodes []string `json:"backupCodes"`
+}
+
+// respondWithToken finishes a successful first login step. Users with
+// two-factor authentication get a short lived challenge token instead of a
+// session token and have to complete the login at /auth/2fa. The method is
+// recorded in the activity of the user.
+func respondWithToken(c *gin.Context, user *models.User, method string) {
+	if middleware.RejectSuspended(c, user) {
+		return
+	}
+
+	if user.TwoFactorEnabled {
+		challenge, err := middleware.GenerateTwoFactorChallengeToken(user.Email, user.ID, user.Role)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating token"})
+			return
+		}
+
+		c.JSON(
+			http.StatusOK,
+			gin.H{
+				"twoFactorRequired": true,
+				"token":             challenge,
+				"message":           "Two-factor authentication required",
+			},
+		)
+		return
+	}
+
+	token, err := middleware.IssueToken(c, user.Email, user.ID, user.Role, false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating token"})
+		return
+	}
+	middleware.RecordActivity(c, user.ID, models.ActivityLogin, map[string]interface{}{"method": method})
+
+	c.JSON(
+		http.StatusOK,
+		gin.H{
+			"token":   token,
+			"message": "Login successful",
+		},
+	)
+}
+
+// verifySecondFactor accepts either a current TOTP code or an unused backup
+// code, answering with failStatus when the code is wrong and 429 when the
+// second factor is locked after too many wrong codes.
+func verifySecondFactor(c *gin.Context, user *models.User, code string, failStatus int) bool {
+	ok, err := twoFactorHandler.VerifyCode(user, code)
+	if errors.Is(err, models.ErrTwoFactorLocked) {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many wrong two-factor codes, please try again later"})
+		return false
+	}
+	if err != nil {
+		log.Println("Error checking two-factor code:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking two-factor code"})
+		return false
+	}
+	if !ok {
+		c.JSON(failStatus, gin.H{"error": "Invalid two-factor code"})
+		return false
+	}
+	return true
+}
+
+func currentUser(c *gin.Context) (*models.User, bool) {
+	id, exist := c.Get("id")
+	if !exist {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return nil, false
+	}
+
+	user, err := userHandler.GetUser(id.(uint))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return nil, false
+	}
+
+	return user, true
+}
+
+// @Summary Complete Two-Factor Login
+// @Description Second login step for users with two-factor authentication. Exchanges the challenge token returned by the login together with a TOTP or backup code for a session token. A TOTP code is only accepted once, and after 5 wrong codes in a row the second factor of the account is locked for 15 minutes.
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param credentials body TwoFactorLoginDetails true "Challenge token and code"
+// @Success 200 {object} LoginResponse "An object containing a JWT token for authentication."
+// @Failure 400 {object} ErrorResponse "The request was formatted incorrectly or missing required fields."
+// @Failure 401 {object} ErrorResponse "The challenge token is invalid or expired, or the code is wrong."
+// @Failure 429 {object} ErrorResponse "Too many wrong codes, the second factor is locked."
+// @Router /auth/2fa [post]
+func TwoFactorLogin(c *gin.Context) {
+	var details TwoFactorLoginDetails
+	if err := c.ShouldBindJSON(&details); err != nil || details.Token == "" || details.Code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input format! please check the input format"})
+		return
+	}
+
+	claims, err := middleware.ValidateToken(details.Token)
+	if err != nil || !claims.TwoFactorPending {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired two-factor challenge, please login again"})
+		return
+	}
+
+	user, err := userHandler.GetUser(claims.UserId)
+	if err != nil || !user.TwoFactorEnabled {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired two-factor challenge, please login again"})
+		return
+	}
+
+	if !verifySecondFactor(c, user, details.Code, http.StatusUnauthorized) {
+		return
+	}
+
+	if middleware.RejectSuspended(c, user) {
+		return
+	}
+
+	token, err := middleware.IssueToken(c, user.Email, user.ID, user.Role, true)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating token"})
+		return
+	}
+	middleware.RecordActivity(c, user.ID, models.ActivityLogin, map[string]interface{}{"method": "two_factor"})
+
+	c.JSON(
+		http.StatusOK,
+		gin.H{
+			"token":   token,
+			"message": "Login successful",
+		},
+	)
+}
+
+// @Summary Enroll in Two-Factor Authentication
+// @Description Generates a new TOTP secret for the authenticated user. Two-factor authentication is only enabled after confirming a code with /me/2fa/verify.
+// @Tags authentication
+// @Produce json
+// @security BearerAuth
+// @Success 200 {object} TwoFactorEnrollResponse "The secret and the otpauth URL to show as a QR code."
+// @Failure 400 {object} ErrorResponse "Two-factor authentication is already enabled."
+// @Router /me/2fa/enroll [post]
+func EnrollTwoFactor(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	if user.TwoFactorEnabled {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Two-factor authentication is already enabled"})
+		return
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating secret"})
+		return
+	}
+
+	if err := twoFactorHandler.SetSecret(user.ID, secret); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error saving secret"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"secret":     secret,
+		"otpauthUrl": utils.TOTPProvisioningURI(secret, user.Email, totpIssuer),
+	})
+}
+
+// @Summary Verify Two-Factor Enrollment
+// @Description Confirms the enrollment with a code from the authenticator app and enables two-factor authentication. Returns backup codes, shown only once, and a new token since older tokens stop working.
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param code body TwoFactorCodeDetails true "Code from the authenticator app"
+// @security BearerAuth
+// @Success 200 {object} TwoFactorEnabledResponse "A new token and the backup codes."
+// @Failure 400 {object} ErrorResponse "No enrollment in progress or the code is wrong."
+// @Router /me/2fa/verify [post]
+func VerifyTwoFactor(c *gin.Context) {
+	var details TwoFactorCodeDetails
+	if err := c.ShouldBindJSON(&details); err != nil || details.Code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input format! please check the input format"})
+		return
+	}
+
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	if user.TwoFactorEnabled || user.TwoFactorSecret == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No two-factor enrollment in progress"})
+		return
+	}
+
+	step, ok := utils.MatchTOTP(user.TwoFactorSecret, details.Code, time.Now())
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid two-factor code"})
+		return
+	}
+
+	codes, err := twoFactorHandler.Enable(user.ID, step)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error enabling two-factor authentication"})
+		return
+	}
+
+	if middleware.RejectSuspended(c, user) {
+		return
+	}
+
+	token, err := middleware.ReissueToken(c, user, true)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating token"})
+		return
+	}
+	middleware.RecordActivity(c, user.ID, models.ActivityLogin, map[string]interface{}{"method": "two_factor"})
+
+	c.JSON(http.StatusOK, gin.H{"token": token, "backupCodes": codes})
+}
+
+// @Summary Regenerate Two-Factor Backup Codes
+// @Description Replaces all backup codes of the authenticated user. Requires a current code.
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param code body TwoFactorCodeDetails true "Current TOTP or backup code"
+// @security BearerAuth
+// @Success 200 {object} BackupCodesResponse "The new backup codes."
+// @Failure 400 {object} ErrorResponse "Two-factor authentication is not enabled or the code is wrong."
+// @Failure 429 {object} ErrorResponse "Too many wrong codes, the second factor is locked."
+// @Router /me/2fa/backup-codes [post]
+func RegenerateBackupCodes(c *gin.Context) {
+	var details TwoFactorCodeDetails
+	if err := c.ShouldBindJSON(&details); err != nil || details.Code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input format! please check the input format"})
+		return
+	}
+
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	if !user.TwoFactorEnabled {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Two-factor authentication is not enabled"})
+		return
+	}
+
+	if !verifySecondFactor(c, user, details.Code, http.StatusBadRequest) {
+		return
+	}
+
+	codes, err := twoFactorHandler.RegenerateBackupCodes(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating backup codes"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"backupCodes": codes})
+}
+
+// @Summary Disable Two-Factor Authentication
+// @Description Turns off two-factor authentication for the authenticated user. Requires a current code.
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param code body TwoFactorCodeDetails true "Current TOTP or backup code"
+// @security BearerAuth
+// @Success 200 {object} MessageResponse "Two-factor authentication has been disabled."
+// @Failure 400 {object} ErrorResponse "Two-factor authentication is not enabled or the code is wrong."
+// @Failure 429 {object} ErrorResponse "Too many wrong codes, the second factor is locked."
+// @Router /me/2fa [delete]
+func DisableTwoFactor(c *gin.Context) {
+	var details TwoFactorCodeDetails
+	if err := c.ShouldBindJSON(&details); err != nil || details.Code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input format! please check the input format"})
+		return
+	}
+
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	if !user.TwoFactorEnabled {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Two-factor authentication is not enabled"})
+		return
+	}
+
+	if !verifySecondFactor(c, user, details.Code, http.StatusBadRequest) {
+		return
+	}
+
+	if err := twoFactorHandler.Disable(user.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error disabling two-factor authentication"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled"})
+}
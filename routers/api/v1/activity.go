@@ -0,0 +1,76 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"gorm.io/gorm"
+)
+
+var activityHandler *models.ActivityHandler
+
+func InitializedActivityHandler(db *gorm.DB) {
+	activityHandler = models.NewActivityHandler(db)
+}
+
+type ActivityPage struct {
+	Data []models.Activity `json:"data"`
+	Pagination
+}
+
+func respondWithActivities(c *gin.Context, userID uint) {
+	page, limit := parsePagination(c)
+	activities, total, err := activityHandler.GetActivities(userID, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching activity"})
+		return
+	}
+
+	if activities == nil {
+		activities = []models.Activity{}
+	}
+
+	c.JSON(http.StatusOK, ActivityPage{
+		Data:       activities,
+		Pagination: Pagination{Page: page, Limit: limit, Total: total},
+	})
+}
+
+// @Summary Get my activity
+// @Description Lists the history of the currently authenticated user, newest first: logins, profile and password changes, reservations made or cancelled and deletion requests. Each entry has the IP address and user agent of the request and, when someone else acted, the actor.
+// @Tags user
+// @Produce json
+// @Param page query int false "Page number, starting at 1"
+// @Param limit query int false "Page size, at most 100"
+// @security BearerAuth
+// @Success 200 {object} ActivityPage "A page of activities."
+// @Failure 401 {object} ErrorResponse "Unauthorized."
+// @Failure 500 {object} ErrorResponse "Internal server error while fetching the activity."
+// @Router /me/activity [get]
+func GetMyActivity(c *gin.Context) {
+	id, _ := c.Get("id")
+	respondWithActivities(c, id.(uint))
+}
+
+// @Summary Get the activity of a user
+// @Description Lists the history of a user, newest first, for support to answer questions such as who cancelled a booking.
+// @Tags admin
+// @Produce json
+// @Param id path int true "User ID" Format(int64)
+// @Param page query int false "Page number, starting at 1"
+// @Param limit query int false "Page size, at most 100"
+// @security BearerAuth
+// @Success 200 {object} ActivityPage "A page of activities."
+// @Failure 400 {object} ErrorResponse "Invalid user ID format."
+// @Failure 500 {object} ErrorResponse "Internal server error while fetching the activity."
+// @Router /users/{id}/activity [get]
+func GetUserActivity(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil || idInt < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user id"})
+		return
+	}
+	respondWithActivities(c, uint(idInt))
+}
@@ -0,0 +1,63 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/config"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"gorm.io/gorm"
+)
+
+var analyticsHandler *models.AnalyticsHandler
+
+func InitializedAnalyticsHandler(db *gorm.DB) {
+	analyticsHandler = models.NewAnalyticsHandler(config.AnalyticsSink(db))
+}
+
+type AnalyticsEventsRequest struct {
+	Events []models.AnalyticsEvent `json:"events" binding:"required,min=1,max=100,dive"`
+}
+
+// @Summary Send analytics events
+// @Description Accepts a batch of up to 100 product analytics events from a client app. Every event must match the schema of its name, with no unknown properties, or the whole batch is rejected. Events are stored in the background, and dropped if the user did not agree to analytics.
+// @Tags user
+// @Accept json
+// @Param events body AnalyticsEventsRequest true "Events"
+// @security BearerAuth
+// @Success 202 "The events were accepted."
+// @Failure 400 {object} ErrorResponse "Invalid input format or an event not matching its schema."
+// @Failure 401 {object} ErrorResponse "Unauthorized."
+// @Failure 500 {object} ErrorResponse "Internal server error while checking the consent."
+// @Router /events [post]
+func CreateAnalyticsEvents(c *gin.Context) {
+	var request AnalyticsEventsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid input format, expected 1 to %d events", models.MaxAnalyticsBatch)})
+		return
+	}
+
+	id, _ := c.Get("id")
+	userID := id.(uint)
+	now := time.Now()
+	for i := range request.Events {
+		if err := request.Events[i].Validate(now); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Event %d: %v", i, err)})
+			return
+		}
+		request.Events[i].UserID = userID
+		request.Events[i].ReceivedAt = now
+	}
+
+	granted, err := consentHandler.HasConsent(userID, models.ConsentAnalytics)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking consent"})
+		return
+	}
+	if granted {
+		analyticsHandler.Enqueue(request.Events)
+	}
+	c.Status(http.StatusAccepted)
+}
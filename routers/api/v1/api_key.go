@@ -0,0 +1,142 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"gorm.io/gorm"
+)
+
+var apiKeyHandler *models.APIKeyHandler
+
+func InitializedAPIKeyHandler(db *gorm.DB) {
+	apiKeyHandler = models.NewAPIKeyHandler(db)
+}
+
+type CreateAPIKeyRequest struct {
+	Name           string     `json:"name" example:"Partner integration"`
+	Scopes         []string   `json:"scopes" example:"restaurants:read"`
+	ExpiresAt      *time.Time `json:"expiresAt" example:"2025-01-01T00:00:00Z"`
+	RestaurantID   *uint      `json:"restaurantId"`
+	AllowedOrigins []string   `json:"allowedOrigins" example:"https://www.example.com"`
+}
+
+type CreateAPIKeyResponse struct {
+	Key    string        `json:"key" example:"rr_3f0c1d..."`
+	APIKey models.APIKey `json:"apiKey"`
+}
+
+// @Summary Issue an API Key
+// @Description Creates an API key for a partner integration. The key is sent in the X-API-Key header and only grants the listed scopes: restaurants:read, comments:read and widget:book. A restaurant restricts the key to that restaurant and allowed origins restrict the websites browsers may use it from. Keys with the widget:book scope are embedded in public pages and need both. The raw key is only returned once.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param key body CreateAPIKeyRequest true "Name, scopes and optional expiry"
+// @security BearerAuth
+// @Success 201 {object} CreateAPIKeyResponse "The raw key and its details."
+// @Failure 400 {object} ErrorResponse "Invalid input or unknown scope."
+// @Router /admin/api-keys [post]
+func CreateAPIKey(c *gin.Context) {
+	var request CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&request); err != nil || request.Name == "" || len(request.Scopes) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	for _, scope := range request.Scopes {
+		if !models.IsValidAPIKeyScope(scope) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown scope " + scope})
+			return
+		}
+	}
+
+	if request.ExpiresAt != nil && !request.ExpiresAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Expiry must be in the future"})
+		return
+	}
+
+	for _, origin := range request.AllowedOrigins {
+		if !models.IsValidOrigin(origin) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid origin " + origin + ", expected e.g. https://www.example.com"})
+			return
+		}
+	}
+
+	if request.RestaurantID != nil {
+		if _, err := RestaurantHandler.GetRestaurant(*request.RestaurantID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Restaurant not found"})
+			return
+		}
+	}
+
+	id, _ := c.Get("id")
+	key := models.APIKey{
+		Name:           request.Name,
+		Scopes:         request.Scopes,
+		ExpiresAt:      request.ExpiresAt,
+		CreatedBy:      id.(uint),
+		RestaurantID:   request.RestaurantID,
+		AllowedOrigins: request.AllowedOrigins,
+	}
+	raw, err := apiKeyHandler.CreateAPIKey(&key)
+	if errors.Is(err, models.ErrInvalidWidgetKey) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Keys with the widget:book scope need a restaurantId and allowedOrigins"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating api key"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"key": raw, "apiKey": key})
+}
+
+// @Summary Get API Keys
+// @Description Lists every API key, including revoked and expired ones. Raw keys are never returned.
+// @Tags admin
+// @Produce json
+// @security BearerAuth
+// @Success 200 {array} models.APIKey "The API keys, newest first."
+// @Failure 500 {object} ErrorResponse "Internal server error while fetching the keys."
+// @Router /admin/api-keys [get]
+func GetAPIKeys(c *gin.Context) {
+	keys, err := apiKeyHandler.GetAPIKeys()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching api keys"})
+		return
+	}
+
+	if keys == nil {
+		keys = []models.APIKey{}
+	}
+	c.JSON(http.StatusOK, keys)
+}
+
+// @Summary Revoke an API Key
+// @Description Revokes an API key immediately.
+// @Tags admin
+// @Produce json
+// @Param id path int true "API key ID" Format(int64)
+// @security BearerAuth
+// @Success 204 "API key revoked."
+// @Failure 400 {object} ErrorResponse "Invalid API key ID."
+// @Failure 404 {object} ErrorResponse "No active API key with this ID."
+// @Router /admin/api-keys/{id} [delete]
+func RevokeAPIKey(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid api key id"})
+		return
+	}
+
+	if err := apiKeyHandler.RevokeAPIKey(uint(idInt)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "API key not found"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,66 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"gorm.io/gorm"
+)
+
+var auditHandler *models.AuditHandler
+
+func InitializedAuditHandler(db *gorm.DB) {
+	auditHandler = models.NewAuditHandler(db)
+}
+
+type AuditPage struct {
+	Data []models.AuditEntry `json:"data"`
+	Pagination
+}
+
+// @Summary Get Audit Trail
+// @Description Lists recorded actions, newest first. Impersonation tokens issued to admins and every request made with them are recorded with the admin as actor and the impersonated user.
+// @Tags admin
+// @Produce json
+// @Param actorId query int false "Only actions by this admin or user"
+// @Param userId query int false "Only actions taken as this user"
+// @Param impersonated query bool false "Only actions taken while impersonating"
+// @Param page query int false "Page number, starting at 1"
+// @Param limit query int false "Page size, at most 100"
+// @security BearerAuth
+// @Success 200 {object} AuditPage "A page of audit entries."
+// @Failure 400 {object} ErrorResponse "Invalid filter."
+// @Failure 500 {object} ErrorResponse "Internal server error while fetching the audit trail."
+// @Router /admin/audit-log [get]
+func GetAuditLog(c *gin.Context) {
+	var filter models.AuditFilter
+	for param, target := range map[string]*uint{"actorId": &filter.ActorID, "userId": &filter.UserID} {
+		if value := c.Query(param); value != "" {
+			id, err := strconv.Atoi(value)
+			if err != nil || id < 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + param})
+				return
+			}
+			*target = uint(id)
+		}
+	}
+	filter.Impersonated = c.Query("impersonated") == "true"
+
+	page, limit := parsePagination(c)
+	entries, total, err := auditHandler.GetEntries(filter, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching audit trail"})
+		return
+	}
+
+	if entries == nil {
+		entries = []models.AuditEntry{}
+	}
+
+	c.JSON(http.StatusOK, AuditPage{
+		Data:       entries,
+		Pagination: Pagination{Page: page, Limit: limit, Total: total},
+	})
+}
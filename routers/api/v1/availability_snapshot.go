@@ -0,0 +1,71 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"github.com/punchanabu/redrice-backend-go/utils"
+	"gorm.io/gorm"
+)
+
+// availabilitySnapshotInterval is how often the upcoming slots are recorded,
+// every slot is recorded once per lead time.
+const availabilitySnapshotInterval = time.Hour
+
+var availabilitySnapshotHandler *models.AvailabilitySnapshotHandler
+
+func InitializedAvailabilitySnapshotHandler(db *gorm.DB) {
+	availabilitySnapshotHandler = models.NewAvailabilitySnapshotHandler(db)
+	utils.RunEveryExclusive(db, availabilitySnapshotInterval, "record availability snapshots", func() error {
+		return availabilitySnapshotHandler.RecordSnapshots(time.Now(), availabilitySnapshotInterval)
+	})
+}
+
+// @Summary Get Restaurant Slot Fill Rates
+// @Description Describes how the slots of the week of a restaurant usually fill up, from snapshots of every slot taken a week, 3 days, a day, 6 hours and an hour before it starts and at its start. For every weekday and time of the restaurant's time zone it gives the average number of reservations at the start, at each lead time the average reservations, the share of the final bookings already made and the share of slots no longer bookable, and the earliest lead time the slot was usually already full. The period covers the slot start times and defaults to the current month. Only the owner of the restaurant or an admin can see it.
+// @Tags restaurants
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Param from query string false "First day of the period in YYYY-MM-DD format"
+// @Param to query string false "Last day of the period in YYYY-MM-DD format"
+// @security BearerAuth
+// @Success 200 {array} models.SlotFill "The fill rates by weekday and time."
+// @Failure 400 {object} ErrorResponse "Invalid restaurant ID or period."
+// @Failure 403 {object} ErrorResponse "The user does not manage this restaurant."
+// @Failure 404 {object} ErrorResponse "Restaurant not found with the specified ID."
+// @Failure 500 {object} ErrorResponse "Internal server error while reading the snapshots."
+// @Router /restaurants/{id}/analytics/fill-rates [get]
+func GetRestaurantFillRates(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant id"})
+		return
+	}
+
+	if !canManageRestaurant(c, uint(idInt)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to manage this restaurant"})
+		return
+	}
+
+	from, to, ok := parsePeriod(c)
+	if !ok {
+		return
+	}
+
+	restaurant, err := RestaurantHandler.GetRestaurant(uint(idInt))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+		return
+	}
+
+	fills, err := availabilitySnapshotHandler.GetSlotFills(restaurant, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error reading availability snapshots"})
+		return
+	}
+
+	c.JSON(http.StatusOK, fills)
+}
@@ -0,0 +1,191 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"github.com/punchanabu/redrice-backend-go/utils"
+	"gorm.io/gorm"
+)
+
+// backfillInterval is how often runs started by admins or left by a restart are picked up.
+const backfillInterval = time.Minute
+
+var backfillHandler *models.BackfillHandler
+
+func InitializedBackfillHandler(db *gorm.DB) {
+	backfillHandler = models.NewBackfillHandler(db)
+	utils.RunEveryExclusive(db, backfillInterval, "run backfills", backfillHandler.ProcessRuns)
+}
+
+type BackfillRunRequest struct {
+	// BatchSize is the number of rows per batch, 200 by default
+	BatchSize int `json:"batchSize" binding:"omitempty,min=1,max=5000" example:"200"`
+	// BatchDelayMs is the pause between batches, 500 by default
+	BatchDelayMs *int `json:"batchDelayMs" binding:"omitempty,min=0,max=60000" example:"500"`
+}
+
+type BackfillRunPage struct {
+	Data []models.BackfillRun `json:"data"`
+	Pagination
+}
+
+// backfillRun loads the run of the path, responding with the error otherwise.
+func backfillRun(c *gin.Context) (*models.BackfillRun, bool) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid run id"})
+		return nil, false
+	}
+	run, err := backfillHandler.GetRun(uint(idInt))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Backfill run not found"})
+		return nil, false
+	}
+	return run, true
+}
+
+// @Summary Get Backfills
+// @Description Lists the backfills that compute a field on the existing rows of a table.
+// @Tags admin
+// @Produce json
+// @security BearerAuth
+// @Success 200 {array} models.Backfill "The backfills."
+// @Router /admin/backfills [get]
+func GetBackfills(c *gin.Context) {
+	c.JSON(http.StatusOK, models.Backfills)
+}
+
+// @Summary Start a Backfill
+// @Description Starts a run of the backfill, processed in the background in batches of rows by ID with a pause between them. The run starts within a minute and resumes from its last batch after a restart. A backfill has at most one running or paused run.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param key path string true "Backfill key"
+// @Param run body BackfillRunRequest false "Batch size and pause"
+// @security BearerAuth
+// @Success 202 {object} models.BackfillRun "The started run."
+// @Failure 400 {object} ErrorResponse "Invalid input format."
+// @Failure 404 {object} ErrorResponse "Unknown backfill."
+// @Failure 409 {object} ErrorResponse "The backfill already has a running or paused run."
+// @Failure 500 {object} ErrorResponse "Internal server error while starting the run."
+// @Router /admin/backfills/{key}/runs [post]
+func StartBackfill(c *gin.Context) {
+	var request BackfillRunRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+			return
+		}
+	}
+	batchSize := request.BatchSize
+	if batchSize == 0 {
+		batchSize = models.DefaultBackfillBatchSize
+	}
+	batchDelay := models.DefaultBackfillDelay
+	if request.BatchDelayMs != nil {
+		batchDelay = time.Duration(*request.BatchDelayMs) * time.Millisecond
+	}
+
+	id, _ := c.Get("id")
+	run, err := backfillHandler.StartRun(c.Param("key"), batchSize, batchDelay, id.(uint))
+	if err != nil {
+		switch {
+		case errors.Is(err, models.ErrUnknownBackfill):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, models.ErrBackfillActive):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error starting backfill"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, run)
+}
+
+// @Summary Get Backfill Runs
+// @Description Lists the runs of every backfill, newest first, with their progress.
+// @Tags admin
+// @Produce json
+// @Param page query int false "Page number, starting at 1"
+// @Param limit query int false "Runs per page, at most 100"
+// @security BearerAuth
+// @Success 200 {object} BackfillRunPage "One page of runs."
+// @Failure 500 {object} ErrorResponse "Internal server error while fetching the runs."
+// @Router /admin/backfill-runs [get]
+func GetBackfillRuns(c *gin.Context) {
+	page, limit := parsePagination(c)
+	runs, total, err := backfillHandler.GetRuns(limit, (page-1)*limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching backfill runs"})
+		return
+	}
+
+	if runs == nil {
+		runs = []models.BackfillRun{}
+	}
+	c.JSON(http.StatusOK, BackfillRunPage{
+		Data:       runs,
+		Pagination: Pagination{Page: page, Limit: limit, Total: total},
+	})
+}
+
+// @Summary Pause a Backfill Run
+// @Description Stops a running backfill after its current batch. It can be resumed later.
+// @Tags admin
+// @Produce json
+// @Param id path int true "Run ID" Format(int64)
+// @security BearerAuth
+// @Success 200 {object} models.BackfillRun "The paused run."
+// @Failure 400 {object} ErrorResponse "Invalid run ID."
+// @Failure 404 {object} ErrorResponse "Run not found."
+// @Failure 409 {object} ErrorResponse "The run is not running."
+// @Failure 500 {object} ErrorResponse "Internal server error while pausing the run."
+// @Router /admin/backfill-runs/{id}/pause [post]
+func PauseBackfillRun(c *gin.Context) {
+	changeBackfillRun(c, backfillHandler.PauseRun)
+}
+
+// @Summary Resume a Backfill Run
+// @Description Continues a paused or failed backfill run from its last completed batch.
+// @Tags admin
+// @Produce json
+// @Param id path int true "Run ID" Format(int64)
+// @security BearerAuth
+// @Success 200 {object} models.BackfillRun "The resumed run."
+// @Failure 400 {object} ErrorResponse "Invalid run ID."
+// @Failure 404 {object} ErrorResponse "Run not found."
+// @Failure 409 {object} ErrorResponse "The run is neither paused nor failed."
+// @Failure 500 {object} ErrorResponse "Internal server error while resuming the run."
+// @Router /admin/backfill-runs/{id}/resume [post]
+func ResumeBackfillRun(c *gin.Context) {
+	changeBackfillRun(c, backfillHandler.ResumeRun)
+}
+
+func changeBackfillRun(c *gin.Context, change func(*models.BackfillRun) error) {
+	run, ok := backfillRun(c)
+	if !ok {
+		return
+	}
+
+	if err := change(run); err != nil {
+		if errors.Is(err, models.ErrBackfillRunState) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating backfill run"})
+		return
+	}
+
+	run, err := backfillHandler.GetRun(run.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching backfill run"})
+		return
+	}
+	c.JSON(http.StatusOK, run)
+}
@@ -0,0 +1,147 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"gorm.io/gorm"
+)
+
+var blackoutHandler *models.BlackoutHandler
+
+func InitializedBlackoutHandler(db *gorm.DB) {
+	blackoutHandler = models.NewBlackoutHandler(db)
+}
+
+type BlackoutRequest struct {
+	StartTime time.Time `json:"startTime" example:"2024-04-01T14:00:00+07:00"`
+	EndTime   time.Time `json:"endTime" example:"2024-04-01T17:00:00+07:00"`
+	Reason    string    `json:"reason" example:"Kitchen closed"`
+}
+
+// @Summary Get Restaurant Blackouts
+// @Description Retrieves the upcoming blackout periods of a restaurant.
+// @Tags restaurants
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @security BearerAuth
+// @Success 200 {array} models.Blackout "An array of blackout periods."
+// @Failure 400 {object} ErrorResponse "Invalid restaurant ID format."
+// @Failure 500 {object} ErrorResponse "Internal server error while fetching blackouts."
+// @Router /restaurants/{id}/blackouts [get]
+func GetRestaurantBlackouts(c *gin.Context) {
+	idString := c.Param("id")
+	idInt, err := strconv.Atoi(idString)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant id"})
+		return
+	}
+
+	blackouts, err := blackoutHandler.GetBlackoutsByRestaurantID(uint(idInt), time.Now(), time.Time{})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching blackouts"})
+		return
+	}
+
+	c.JSON(http.StatusOK, blackouts)
+}
+
+// @Summary Create a Restaurant Blackout
+// @Description Blocks a time slot or a date range so that no reservations can be made during it. Only the owner of the restaurant or an admin can create blackouts.
+// @Tags restaurants
+// @Accept json
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Param blackout body BlackoutRequest true "Blackout period"
+// @security BearerAuth
+// @Success 201 {object} models.Blackout "The created blackout."
+// @Failure 400 {object} ErrorResponse "Invalid input format or time range."
+// @Failure 403 {object} ErrorResponse "The user does not manage this restaurant."
+// @Failure 500 {object} ErrorResponse "Internal server error while creating the blackout."
+// @Router /restaurants/{id}/blackouts [post]
+func CreateRestaurantBlackout(c *gin.Context) {
+	idString := c.Param("id")
+	idInt, err := strconv.Atoi(idString)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant id"})
+		return
+	}
+	idUint := uint(idInt)
+
+	var request BlackoutRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	if !request.EndTime.After(request.StartTime) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "endTime must be after startTime"})
+		return
+	}
+
+	if !canManageRestaurant(c, idUint) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to manage this restaurant"})
+		return
+	}
+
+	blackout := models.Blackout{
+		RestaurantID: idUint,
+		StartTime:    request.StartTime,
+		EndTime:      request.EndTime,
+		Reason:       request.Reason,
+	}
+
+	if err := blackoutHandler.CreateBlackout(&blackout); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating blackout"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, blackout)
+}
+
+// @Summary Delete a Restaurant Blackout
+// @Description Removes a blackout period, making its slots bookable again. Only the owner of the restaurant or an admin can delete blackouts.
+// @Tags restaurants
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Param blackoutId path int true "Blackout ID" Format(int64)
+// @security BearerAuth
+// @Success 200 "Blackout successfully deleted."
+// @Failure 400 {object} ErrorResponse "Invalid restaurant or blackout ID format."
+// @Failure 403 {object} ErrorResponse "The user does not manage this restaurant."
+// @Failure 404 {object} ErrorResponse "Blackout not found for the specified restaurant."
+// @Router /restaurants/{id}/blackouts/{blackoutId} [delete]
+func DeleteRestaurantBlackout(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant id"})
+		return
+	}
+
+	blackoutID, err := strconv.Atoi(c.Param("blackoutId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid blackout id"})
+		return
+	}
+
+	blackout, err := blackoutHandler.GetBlackout(uint(blackoutID))
+	if err != nil || blackout.RestaurantID != uint(idInt) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Blackout not found"})
+		return
+	}
+
+	if !canManageRestaurant(c, blackout.RestaurantID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to manage this restaurant"})
+		return
+	}
+
+	if err := blackoutHandler.DeleteBlackout(blackout.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error deleting blackout"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Blackout deleted successfully"})
+}
@@ -0,0 +1,193 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"gorm.io/gorm"
+)
+
+var categoryHandler *models.CategoryHandler
+
+func InitializedCategoryHandler(db *gorm.DB) {
+	categoryHandler = models.NewCategoryHandler(db)
+}
+
+type CategoryRequest struct {
+	Name string `json:"name" binding:"required" example:"Vegan friendly"`
+	// Slug defaults to the name in lowercase with dashes
+	Slug string `json:"slug" example:"vegan-friendly"`
+}
+
+// bindCategory validates the request and copies it onto the category.
+func bindCategory(c *gin.Context, category *models.Category) bool {
+	var request CategoryRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return false
+	}
+
+	slug, err := models.CategorySlug(strings.TrimSpace(request.Slug), request.Name)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+	category.Name = strings.TrimSpace(request.Name)
+	category.Slug = slug
+	return true
+}
+
+// parseCategories reads the categories form value, slugs separated by
+// commas. present is false when the form does not have the field, so updates
+// leave the categories unchanged.
+func parseCategories(c *gin.Context) (categories []models.Category, present bool, ok bool) {
+	values, present := c.Request.Form["categories"]
+	if !present {
+		return nil, false, true
+	}
+
+	var slugs []string
+	for _, value := range values {
+		for _, slug := range strings.Split(value, ",") {
+			if slug = strings.TrimSpace(slug); slug != "" {
+				slugs = append(slugs, slug)
+			}
+		}
+	}
+	categories, err := categoryHandler.GetCategoriesBySlugs(slugs)
+	if err != nil {
+		if errors.Is(err, models.ErrUnknownCategory) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return nil, true, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching categories"})
+		return nil, true, false
+	}
+	return categories, true, true
+}
+
+// @Summary Get Categories
+// @Description Lists the cuisines and tags restaurants can be listed under, by name. The slug filters the restaurant list.
+// @Tags restaurants
+// @Produce json
+// @Success 200 {array} models.Category "The categories."
+// @Failure 500 {object} ErrorResponse "Internal server error while fetching the categories."
+// @Router /categories [get]
+func GetCategories(c *gin.Context) {
+	categories, err := categoryHandler.GetCategories()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching categories"})
+		return
+	}
+
+	if categories == nil {
+		categories = []models.Category{}
+	}
+	c.JSON(http.StatusOK, categories)
+}
+
+// @Summary Create Category
+// @Description Adds a cuisine or tag restaurants can be listed under.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param category body CategoryRequest true "Category"
+// @security BearerAuth
+// @Success 201 {object} models.Category "The created category."
+// @Failure 400 {object} ErrorResponse "Invalid input format or slug."
+// @Failure 409 {object} ErrorResponse "A category with this slug already exists."
+// @Failure 500 {object} ErrorResponse "Internal server error while creating the category."
+// @Router /admin/categories [post]
+func CreateCategory(c *gin.Context) {
+	var category models.Category
+	if !bindCategory(c, &category) {
+		return
+	}
+
+	if err := categoryHandler.CreateCategory(&category); err != nil {
+		if errors.Is(err, models.ErrCategorySlugTaken) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating category"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, category)
+}
+
+// @Summary Update Category
+// @Description Renames a category. Restaurants listed under it stay listed under it.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Category ID" Format(int64)
+// @Param category body CategoryRequest true "Category"
+// @security BearerAuth
+// @Success 200 {object} models.Category "The updated category."
+// @Failure 400 {object} ErrorResponse "Invalid input format, slug or category ID."
+// @Failure 404 {object} ErrorResponse "Category not found."
+// @Failure 409 {object} ErrorResponse "A category with this slug already exists."
+// @Failure 500 {object} ErrorResponse "Internal server error while updating the category."
+// @Router /admin/categories/{id} [put]
+func UpdateCategory(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category id"})
+		return
+	}
+
+	category, err := categoryHandler.GetCategory(uint(idInt))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+		return
+	}
+
+	if !bindCategory(c, category) {
+		return
+	}
+
+	if err := categoryHandler.UpdateCategory(category); err != nil {
+		if errors.Is(err, models.ErrCategorySlugTaken) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating category"})
+		return
+	}
+
+	c.JSON(http.StatusOK, category)
+}
+
+// @Summary Delete Category
+// @Description Deletes a category and removes it from every restaurant listed under it.
+// @Tags admin
+// @Param id path int true "Category ID" Format(int64)
+// @security BearerAuth
+// @Success 204 "Category deleted."
+// @Failure 400 {object} ErrorResponse "Invalid category ID."
+// @Failure 404 {object} ErrorResponse "Category not found."
+// @Failure 500 {object} ErrorResponse "Internal server error while deleting the category."
+// @Router /admin/categories/{id} [delete]
+func DeleteCategory(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category id"})
+		return
+	}
+
+	if err := categoryHandler.DeleteCategory(uint(idInt)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error deleting category"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -1,9 +1,11 @@
 package v1
 
 import (
+	"errors"
 	"log"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/punchanabu/redrice-backend-go/models"
@@ -12,10 +14,24 @@ import (
 
 var commentHandler *models.CommentHandler
 var restaurantHandler *models.RestaurantHandler
+var commentTagHandler *models.CommentTagHandler
 
 func InitializedCommentHandler(db *gorm.DB) {
 	commentHandler = models.NewCommentHandler(db)
 	restaurantHandler = models.NewRestaurantHandler(db)
+	commentTagHandler = models.NewCommentTagHandler(db)
+}
+
+// CommentRequest is the body of a comment being created or updated. When
+// anonymous is left out of a new comment the default of the reviewer applies.
+type CommentRequest struct {
+	DateTime      time.Time `json:"dateTime"`
+	MyComment     string    `json:"myComment"`
+	Rating        float64   `json:"rating" example:"4.5"`
+	RestaurantID  uint      `json:"restaurantId" example:"7"`
+	ReservationID *uint     `json:"reservationId"`
+	Anonymous     *bool     `json:"anonymous" example:"false"`
+	DisplayName   *string   `json:"displayName" example:"Foodie from Bangkok"`
 }
 
 // @Summary Get All Comments
@@ -23,7 +39,7 @@ func InitializedCommentHandler(db *gorm.DB) {
 // @Tags comments
 // @Produce json
 // @security BearerAuth
-// @Success 200 {array} models.Comment "An array of comment objects."
+// @Success 200 {array} models.CommentResponse "An array of comment objects."
 // @Failure 500 {object} ErrorResponse "Internal server error while fetching comments."
 // @Router /comments [get]
 func GetComments(c *gin.Context) {
@@ -32,7 +48,7 @@ func GetComments(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching comments!"})
 		return
 	}
-	c.JSON(http.StatusOK, comments)
+	c.JSON(http.StatusOK, models.CommentResponses(comments))
 }
 
 // @Summary Get a Single Comment
@@ -41,7 +57,7 @@ func GetComments(c *gin.Context) {
 // @Produce json
 // @Param id path int true "Comment ID" Format(int64)
 // @security BearerAuth
-// @Success 200 {object} models.Comment "The details of the comment including ID, DateTime, Detail, UserID, User, RestaurantID, and Restaurant."
+// @Success 200 {object} models.CommentResponse "The details of the comment including ID, DateTime, Detail, the public identity of the author, RestaurantID, and Restaurant."
 // @Failure 400 {object} ErrorResponse "Invalid comment ID format."
 // @Failure 404 {object} ErrorResponse "Comment not found with the specified ID."
 // @Router /comments/{id} [get]
@@ -61,29 +77,46 @@ func GetComment(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, comment)
+	c.JSON(http.StatusOK, comment.Response())
 }
 
 // @Summary Create a New Comment
-// @Description Adds a new comment to the system with customer's opinion. This endpoint requires authentication.
+// @Description Adds a new comment to the system with customer's opinion. Passing the ID of a completed reservation at the restaurant marks the comment as verified, every reservation can only be reviewed once. Reviews are published under the name of the user, the displayName if given, or anonymously with anonymous set, which defaults to the reviewAnonymously preference of the user. Responses never include the contact details of reviewers. This endpoint requires authentication.
 // @Tags reservations
 // @Accept json
 // @Produce json
-// @Param commnet body models.Comment true "Your Comment"
+// @Param commnet body CommentRequest true "Your Comment"
 // @security BearerAuth
-// @Success 201 {object} models.Comment "The created comment's details, including its unique identifier."
+// @Success 201 {object} models.CommentResponse "The created comment's details, including its unique identifier."
 // @Failure 400 {object} ErrorResponse "Invalid input format for reservation details."
+// @Failure 403 {object} ErrorResponse "The role of the user cannot comment."
+// @Failure 409 {object} ErrorResponse "The reservation was already reviewed."
 // @Failure 500 {object} ErrorResponse "Internal server error while creating the reservation."
 // @Router /comments [post]
 func CreateComment(c *gin.Context) {
-	var comment models.Comment
-	if err := c.ShouldBindJSON(&comment); err != nil {
+	var request CommentRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
 		log.Println("Error binding JSON:", err)
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
 		return
 	}
 
-	log.Println("JSON bound successfully:", comment)
+	// Sentiment and tags are computed by the tagging job
+	comment := models.Comment{
+		DateTime:      request.DateTime,
+		MyComment:     request.MyComment,
+		Rating:        request.Rating,
+		RestaurantID:  request.RestaurantID,
+		ReservationID: request.ReservationID,
+	}
+	if request.DisplayName != nil {
+		displayName, err := models.NormalizeDisplayName(*request.DisplayName)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		comment.DisplayName = displayName
+	}
 
 	userID, exist := c.Get("id")
 	if !exist {
@@ -103,7 +136,33 @@ func CreateComment(c *gin.Context) {
 
 	log.Println("User ID type assertion successful:", uid)
 
+	if request.Anonymous != nil {
+		comment.Anonymous = *request.Anonymous
+	} else {
+		preferences, err := preferencesHandler.GetPreferences(uid)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching preferences"})
+			return
+		}
+		comment.Anonymous = preferences.ReviewAnonymously
+	}
+
+	// A review is verified when it refers to a completed visit of the reviewer
+	if comment.ReservationID != nil {
+		reservation, err := reservationHandler.GetReservation(*comment.ReservationID)
+		if err != nil || reservation.UserID != uid || reservation.RestaurantID != comment.RestaurantID ||
+			reservation.Status != models.ReservationStatusCompleted {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Reservation is not a completed visit of yours to this restaurant"})
+			return
+		}
+		comment.Verified = true
+	}
+
 	err := commentHandler.CreateComment(uid, &comment)
+	if errors.Is(err, models.ErrReservationReviewed) {
+		c.JSON(http.StatusConflict, gin.H{"error": "You already reviewed this visit"})
+		return
+	}
 	if err != nil {
 		log.Println("Error creating comment:", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating comment"})
@@ -112,6 +171,8 @@ func CreateComment(c *gin.Context) {
 
 	log.Println("Comment created successfully:", comment)
 
+	commentTagHandler.Enqueue(comment.ID)
+
 	// Assuming restaurantHandler is correctly instantiated and not nil
 	restaurant, err := restaurantHandler.GetRestaurant(comment.RestaurantID)
 	if err != nil {
@@ -137,24 +198,31 @@ func CreateComment(c *gin.Context) {
 
 	log.Println("Restaurant updated successfully:", restaurant)
 
-	c.JSON(http.StatusCreated, comment)
+	if comment.Verified {
+		if err := restaurantHandler.RecomputeVerifiedRating(comment.RestaurantID); err != nil {
+			log.Println("Error updating verified rating:", err)
+		}
+	}
+
+	c.JSON(http.StatusCreated, comment.Response())
 }
 
 // @Summary Update a Comment
-// @Description Updates the details of an existing comment identified by its ID. This endpoint requires authentication.
+// @Description Updates the details of an existing comment identified by its ID. Passing anonymous or displayName changes how the review is published. This endpoint requires authentication.
 // @Tags comments
 // @Accept json
 // @Produce json
 // @Param id path int true "Comment ID" Format(int64)
-// @Param comment body models.Comment true "Updated comment Details"
+// @Param comment body CommentRequest true "Updated comment Details"
 // @security BearerAuth
-// @Success 200 {object} models.Comment "The updated comment's details."
+// @Success 200 {object} models.CommentResponse "The updated comment's details."
 // @Failure 400 {object} ErrorResponse "Invalid input format for comment details or invalid comment ID."
+// @Failure 403 {object} ErrorResponse "The role of the user cannot comment."
 // @Failure 404 {object} ErrorResponse "Comment not found with the specified ID."
 // @Router /comments/{id} [put]
 func UpdateComment(c *gin.Context) {
-	var comment models.Comment
-	if err := c.ShouldBindJSON(&comment); err != nil {
+	var request CommentRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -191,13 +259,52 @@ func UpdateComment(c *gin.Context) {
 		return
 	}
 
+	comment := models.Comment{
+		DateTime:     request.DateTime,
+		MyComment:    request.MyComment,
+		Rating:       request.Rating,
+		RestaurantID: request.RestaurantID,
+	}
+
+	anonymous, displayName := ownComment.Anonymous, ownComment.DisplayName
+	if request.Anonymous != nil {
+		anonymous = *request.Anonymous
+	}
+	if request.DisplayName != nil {
+		displayName, err = models.NormalizeDisplayName(*request.DisplayName)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
 	err = commentHandler.UpdateComment(idUint, &comment)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating comment"})
 		return
 	}
 
-	c.JSON(http.StatusOK, comment)
+	if anonymous != ownComment.Anonymous || displayName != ownComment.DisplayName {
+		if err := commentHandler.SetCommentPrivacy(idUint, anonymous, displayName); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating comment"})
+			return
+		}
+	}
+
+	commentTagHandler.Enqueue(idUint)
+
+	if ownComment.Verified {
+		if err := restaurantHandler.RecomputeVerifiedRating(ownComment.RestaurantID); err != nil {
+			log.Println("Error updating verified rating:", err)
+		}
+	}
+
+	updated, err := commentHandler.GetComment(idUint)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching comment"})
+		return
+	}
+	c.JSON(http.StatusOK, updated.Response())
 }
 
 // @Summary Delete a Comment
@@ -276,6 +383,12 @@ func DeleteComment(c *gin.Context) {
 		return
 	}
 
+	if ownComment.Verified {
+		if err := restaurantHandler.RecomputeVerifiedRating(ownComment.RestaurantID); err != nil {
+			log.Println("Error updating verified rating:", err)
+		}
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "Comment deleted successfully"})
 }
 
@@ -285,10 +398,12 @@ func DeleteComment(c *gin.Context) {
 // @Tags comments
 // @Produce json
 // @Param restaurantId path int true "Reataurant ID"
+// @Param tag query string false "Only return comments with this tag, e.g. slow kitchen"
+// @Param sentiment query string false "Only return comments with this sentiment" Enums(positive, negative, neutral)
 // @security BearerAuth
-// @Success 200 {array} models.Comment "An array of comment objects for the restaurant."
+// @Success 200 {array} models.CommentResponse "An array of comment objects for the restaurant."
 // @Failure 400 {object} ErrorResponse "Invalid reataurant ID format."
-// @Failure 404 {object} ErrorResponse "Comments not found for the specified restaurant ID."
+// @Failure 404 {object} ErrorResponse "Restaurant not found with the specified ID, or not listed and not managed by the user."
 // @Router /restaurants/{restaurantID}/comments [get]
 func GetRestaurantComments(c *gin.Context) {
 	RestaurantID := c.Param("id")
@@ -303,11 +418,20 @@ func GetRestaurantComments(c *gin.Context) {
 		return
 	}
 
-	comments, err := commentHandler.GetCommentsByRestaurantID(uint(uid)) // Correctly cast to uint now
+	if _, ok := findRestaurant(c, uint(uid)); !ok {
+		return
+	}
+
+	filter := models.CommentFilter{
+		Tag:       c.Query("tag"),
+		Sentiment: c.Query("sentiment"),
+	}
+
+	comments, err := commentHandler.GetCommentsByRestaurantID(uint(uid), filter) // Correctly cast to uint now
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching comments for restaurant"})
 		return
 	}
 
-	c.JSON(http.StatusOK, comments)
+	c.JSON(http.StatusOK, models.CommentResponses(comments))
 }
@@ -0,0 +1,96 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"gorm.io/gorm"
+)
+
+var consentHandler *models.ConsentHandler
+
+func InitializedConsentHandler(db *gorm.DB) {
+	consentHandler = models.NewConsentHandler(db)
+}
+
+type ConsentsRequest struct {
+	Consents []models.ConsentChange `json:"consents" binding:"required,dive"`
+}
+
+type ConsentsResponse struct {
+	Consents []models.Consent `json:"consents"`
+	// History is the log of every consent given or withdrawn, oldest first
+	History []models.ConsentEvent `json:"history"`
+}
+
+func respondWithConsents(c *gin.Context, userID uint) {
+	consents, err := consentHandler.GetConsents(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching consents"})
+		return
+	}
+	history, err := consentHandler.GetConsentHistory(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching consents"})
+		return
+	}
+	c.JSON(http.StatusOK, ConsentsResponse{Consents: consents, History: history})
+}
+
+// @Summary Get my consents
+// @Description Retrieves whether the currently authenticated user agreed to marketing emails and analytics, with the log of every consent they gave or withdrew. A consent given to an older version of a purpose counts as not granted until it is given again.
+// @Tags user
+// @Produce json
+// @security BearerAuth
+// @Success 200 {object} ConsentsResponse "The consents of the user."
+// @Failure 401 {object} ErrorResponse "Unauthorized."
+// @Failure 500 {object} ErrorResponse "Internal server error while fetching the consents."
+// @Router /me/consents [get]
+func GetMyConsents(c *gin.Context) {
+	id, _ := c.Get("id")
+	respondWithConsents(c, id.(uint))
+}
+
+// @Summary Update my consents
+// @Description Gives or withdraws the consent of the currently authenticated user to the listed purposes, marketing_emails or analytics. Every change is logged with the current version of the purpose, the time, IP address and user agent. Purposes left out are unchanged.
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param consents body ConsentsRequest true "Consents"
+// @security BearerAuth
+// @Success 200 {object} ConsentsResponse "The updated consents."
+// @Failure 400 {object} ErrorResponse "Invalid input format or unknown purpose."
+// @Failure 401 {object} ErrorResponse "Unauthorized."
+// @Failure 500 {object} ErrorResponse "Internal server error while saving the consents."
+// @Router /me/consents [put]
+func UpdateMyConsents(c *gin.Context) {
+	var request ConsentsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	id, _ := c.Get("id")
+	if err := consentHandler.RecordConsents(id.(uint), request.Consents, c.ClientIP(), c.Request.UserAgent(), time.Now()); err != nil {
+		if errors.Is(err, models.ErrUnknownConsentPurpose) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error saving consents"})
+		return
+	}
+	respondWithConsents(c, id.(uint))
+}
+
+// @Summary Get the data-processing registry
+// @Description Lists the purposes RedRice processes personal data for, with the categories of data, the legal basis and how long the data is kept. Purposes with requiresConsent are only carried out for users who agreed to them.
+// @Tags privacy
+// @Produce json
+// @Success 200 {array} models.ProcessingActivity "The registry."
+// @Router /privacy/processing [get]
+func GetProcessingActivities(c *gin.Context) {
+	c.JSON(http.StatusOK, models.ProcessingActivities)
+}
@@ -0,0 +1,74 @@
+package v1
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"github.com/punchanabu/redrice-backend-go/utils"
+)
+
+// exchangeRateInterval is how often the rates are refreshed, providers only
+// publish them once a day.
+const exchangeRateInterval = 24 * time.Hour
+
+var exchangeRates = models.NewExchangeRates()
+
+// InitializedExchangeRates loads the exchange rates in the background and
+// refreshes them every day. Until they are loaded prices are only shown in
+// their stored currency.
+func InitializedExchangeRates() {
+	provider := utils.NewExchangeRateProvider()
+	refresh := func() error {
+		rates, err := provider.FetchRates(models.DefaultCurrency)
+		if err != nil {
+			return err
+		}
+		exchangeRates.Set(rates, time.Now())
+		return nil
+	}
+
+	go func() {
+		if err := refresh(); err != nil {
+			log.Printf("Failed to load exchange rates: %v", err)
+		}
+	}()
+	utils.RunEvery(exchangeRateInterval, "refresh exchange rates", refresh)
+}
+
+type ExchangeRatesResponse struct {
+	Base string `json:"base" example:"THB"`
+	// Rates is the value of one unit of the base currency by currency
+	Rates     map[string]float64 `json:"rates"`
+	UpdatedAt *time.Time         `json:"updatedAt"`
+}
+
+// displayCurrency reads the currency query parameter asking for prices to be
+// shown in another currency, empty when not set. It writes the error
+// response and returns false when the currency is not supported.
+func displayCurrency(c *gin.Context) (string, bool) {
+	currency := strings.ToUpper(strings.TrimSpace(c.Query("currency")))
+	if currency != "" && !models.IsValidCurrency(currency) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported currency " + currency})
+		return "", false
+	}
+	return currency, true
+}
+
+// @Summary Get Exchange Rates
+// @Description Lists the exchange rates prices can be shown in with the currency query parameter, as the value of one baht. The rates are refreshed daily and are only meant for display, guests always pay in the currency of the restaurant.
+// @Tags restaurants
+// @Produce json
+// @Success 200 {object} ExchangeRatesResponse "The cached exchange rates, without updatedAt until they are loaded."
+// @Router /exchange-rates [get]
+func GetExchangeRates(c *gin.Context) {
+	rates, updatedAt := exchangeRates.Rates()
+	response := ExchangeRatesResponse{Base: models.DefaultCurrency, Rates: rates}
+	if !updatedAt.IsZero() {
+		response.UpdatedAt = &updatedAt
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,52 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/models"
+)
+
+type CustomerPage struct {
+	Data []models.Customer `json:"data"`
+	Pagination
+}
+
+// @Summary Get Restaurant Customers
+// @Description Lists the guests of the restaurant built from its completed reservations, with their number of visits and last visit, most frequent first, so staff can recognize regulars. The email and telephone of guests who hide their contact in their preferences are left out. Only the owner of the restaurant or an admin can see it.
+// @Tags restaurants
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Param page query int false "Page number, starting at 1"
+// @Param limit query int false "Page size, at most 100"
+// @security BearerAuth
+// @Success 200 {object} CustomerPage "A page of customers."
+// @Failure 400 {object} ErrorResponse "Invalid restaurant ID format."
+// @Failure 403 {object} ErrorResponse "The user does not manage this restaurant."
+// @Failure 500 {object} ErrorResponse "Internal server error while fetching the customers."
+// @Router /restaurants/{id}/customers [get]
+func GetRestaurantCustomers(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant id"})
+		return
+	}
+
+	if !canManageRestaurant(c, uint(idInt)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to manage this restaurant"})
+		return
+	}
+
+	page, limit := parsePagination(c)
+	customers, total, err := reservationHandler.GetCustomers(uint(idInt), page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching customers"})
+		return
+	}
+
+	c.JSON(http.StatusOK, CustomerPage{
+		Data:       customers,
+		Pagination: Pagination{Page: page, Limit: limit, Total: total},
+	})
+}
@@ -0,0 +1,113 @@
+package v1
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"github.com/punchanabu/redrice-backend-go/utils"
+	"gorm.io/gorm"
+)
+
+const dataExportBucket = "redrice"
+
+// dataExportLinkTTL is how long the download link of a ready export stays valid.
+const dataExportLinkTTL = 24 * time.Hour
+
+// dataExportRetention is how long a ready export is handed out before a fresh one is assembled.
+const dataExportRetention = 7 * 24 * time.Hour
+
+// dataExportTimeout is after how long an export still processing is considered lost, e.g. after a restart.
+const dataExportTimeout = time.Hour
+
+var dataExportHandler *models.DataExportHandler
+
+func InitializedDataExportHandler(db *gorm.DB) {
+	dataExportHandler = models.NewDataExportHandler(db)
+}
+
+// buildDataExport assembles the archive of the export, stores it in S3 and
+// notifies the user once it is ready.
+func buildDataExport(export models.DataExport) {
+	data, err := dataExportHandler.CollectUserData(export.UserID)
+	if err == nil {
+		var archive []byte
+		archive, err = data.Archive()
+		if err == nil {
+			err = utils.UploadFileToS3(dataExportBucket, models.DataExportKey(&export), archive, "application/zip")
+		}
+	}
+	if err != nil {
+		log.Printf("Failed to export the data of user %d: %v", export.UserID, err)
+		if err := dataExportHandler.MarkFailed(&export); err != nil {
+			log.Printf("Failed to mark data export %d as failed: %v", export.ID, err)
+		}
+		return
+	}
+
+	if err := dataExportHandler.MarkReady(&export, models.DataExportKey(&export), time.Now()); err != nil {
+		log.Printf("Failed to mark data export %d as ready: %v", export.ID, err)
+		return
+	}
+
+	if err := notify(&data.Profile, models.NotificationChannelEmail, "", models.EmailTemplateDataExportReady, map[string]string{"name": data.Profile.Name}); err != nil && !errors.Is(err, utils.ErrEmailNotConfigured) {
+		log.Printf("Failed to send the data export email to user %d: %v", export.UserID, err)
+	}
+}
+
+// isDataExportUsable reports whether the export is ready or still being
+// assembled, so that no new one has to be started.
+func isDataExportUsable(export *models.DataExport, now time.Time) bool {
+	switch export.Status {
+	case models.DataExportStatusReady:
+		return export.CompletedAt != nil && now.Sub(*export.CompletedAt) < dataExportRetention
+	case models.DataExportStatusProcessing:
+		return now.Sub(export.RequestedAt) < dataExportTimeout
+	}
+	return false
+}
+
+// @Summary Export my data
+// @Description Returns a download link to a zip archive of all the data held on the currently authenticated user: profile, preferences, reservations, comments, favorites, consents and sessions. The archive is assembled in the background, responses with status 202 carry no link yet and the request should be repeated later. The user is also emailed when the archive is ready.
+// @Tags user
+// @Produce json
+// @security BearerAuth
+// @Success 200 {object} models.DataExport "The export is ready, downloadUrl links to the archive."
+// @Success 202 {object} models.DataExport "The export is being assembled."
+// @Failure 401 {object} ErrorResponse "Unauthorized."
+// @Failure 500 {object} ErrorResponse "Internal server error while starting the export."
+// @Router /me/export [get]
+func ExportMyData(c *gin.Context) {
+	id, _ := c.Get("id")
+	now := time.Now()
+
+	export, err := dataExportHandler.GetLatestExport(id.(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving data export"})
+		return
+	}
+
+	if export == nil || !isDataExportUsable(export, now) {
+		export, err = dataExportHandler.CreateExport(id.(uint))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error starting data export"})
+			return
+		}
+		go buildDataExport(*export)
+	}
+
+	if export.Status != models.DataExportStatusReady {
+		c.JSON(http.StatusAccepted, export)
+		return
+	}
+
+	export.DownloadURL, err = utils.PresignedURL(dataExportBucket, export.ObjectKey, dataExportLinkTTL)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating download link"})
+		return
+	}
+	c.JSON(http.StatusOK, export)
+}
@@ -0,0 +1,192 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"gorm.io/gorm"
+)
+
+var depositRuleHandler *models.DepositRuleHandler
+
+func InitializedDepositRuleHandler(db *gorm.DB) {
+	depositRuleHandler = models.NewDepositRuleHandler(db)
+}
+
+type DepositRuleRequest struct {
+	Name            string       `json:"name" example:"Weekend dinner"`
+	Weekdays        []int        `json:"weekdays" example:"5,6"`
+	StartTime       string       `json:"startTime" example:"18:00"`
+	EndTime         string       `json:"endTime" example:"23:00"`
+	MinReservations int          `json:"minReservations" example:"0"`
+	Deposit         models.Money `json:"deposit"`
+}
+
+// depositRule reads the rule from the path, writing the error response and
+// returning false when the user cannot manage it.
+func depositRule(c *gin.Context) (*models.DepositRule, bool) {
+	restaurantID, ok := managedRestaurantID(c)
+	if !ok {
+		return nil, false
+	}
+	ruleID, err := strconv.Atoi(c.Param("ruleId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid deposit rule id"})
+		return nil, false
+	}
+	rule, err := depositRuleHandler.GetDepositRule(restaurantID, uint(ruleID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Deposit rule not found"})
+		return nil, false
+	}
+	return rule, true
+}
+
+// applyDepositRuleRequest copies the request onto the rule after validating
+// it, writing the error response and returning false when it is invalid.
+func applyDepositRuleRequest(c *gin.Context, request *DepositRuleRequest, rule *models.DepositRule) bool {
+	if request.Deposit.Currency == "" {
+		request.Deposit.Currency = models.DefaultCurrency
+	}
+	rule.Name = request.Name
+	rule.Weekdays = request.Weekdays
+	rule.StartTime = request.StartTime
+	rule.EndTime = request.EndTime
+	rule.MinReservations = request.MinReservations
+	rule.Deposit = request.Deposit
+	if err := rule.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid deposit rule: " + err.Error()})
+		return false
+	}
+	return true
+}
+
+// @Summary Get Restaurant Deposit Rules
+// @Description Lists the rules setting the deposit of the reservations by time of the week and demand, instead of the deposit of the booking policy. Only the owner of the restaurant or an admin can see them.
+// @Tags restaurants
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @security BearerAuth
+// @Success 200 {array} models.DepositRule "The deposit rules of the restaurant."
+// @Failure 400 {object} ErrorResponse "Invalid restaurant ID format."
+// @Failure 403 {object} ErrorResponse "The user does not manage this restaurant."
+// @Failure 500 {object} ErrorResponse "Internal server error while fetching the rules."
+// @Router /restaurants/{id}/deposit-rules [get]
+func GetDepositRules(c *gin.Context) {
+	restaurantID, ok := managedRestaurantID(c)
+	if !ok {
+		return
+	}
+
+	rules, err := depositRuleHandler.GetDepositRules(restaurantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching deposit rules"})
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+// @Summary Create a Deposit Rule
+// @Description Adds a rule setting the deposit of the reservations starting in a window of the week, in the time zone of the restaurant. The window may run past midnight and belongs to the weekday it starts on, no weekdays means every day. With a minimum of reservations the rule only applies once the slot is that busy. When several rules match a reservation the highest deposit is charged, when none does the deposit of the booking policy is. The deposit is in minor units of its currency, which defaults to THB. Only the owner of the restaurant or an admin can change the rules.
+// @Tags restaurants
+// @Accept json
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Param rule body DepositRuleRequest true "Deposit rule"
+// @security BearerAuth
+// @Success 201 {object} models.DepositRule "The created rule."
+// @Failure 400 {object} ErrorResponse "Invalid input format, window or deposit."
+// @Failure 403 {object} ErrorResponse "The user does not manage this restaurant."
+// @Failure 500 {object} ErrorResponse "Internal server error while creating the rule."
+// @Router /restaurants/{id}/deposit-rules [post]
+func CreateDepositRule(c *gin.Context) {
+	restaurantID, ok := managedRestaurantID(c)
+	if !ok {
+		return
+	}
+
+	var request DepositRuleRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	rule := models.DepositRule{RestaurantID: restaurantID}
+	if !applyDepositRuleRequest(c, &request, &rule) {
+		return
+	}
+	if err := depositRuleHandler.CreateDepositRule(&rule); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating deposit rule"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+// @Summary Update a Deposit Rule
+// @Description Replaces a deposit rule of a restaurant. Reservations already made keep their deposit. Only the owner of the restaurant or an admin can change the rules.
+// @Tags restaurants
+// @Accept json
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Param ruleId path int true "Deposit rule ID" Format(int64)
+// @Param rule body DepositRuleRequest true "Deposit rule"
+// @security BearerAuth
+// @Success 200 {object} models.DepositRule "The updated rule."
+// @Failure 400 {object} ErrorResponse "Invalid input format, window or deposit."
+// @Failure 403 {object} ErrorResponse "The user does not manage this restaurant."
+// @Failure 404 {object} ErrorResponse "Rule not found for the restaurant."
+// @Failure 500 {object} ErrorResponse "Internal server error while saving the rule."
+// @Router /restaurants/{id}/deposit-rules/{ruleId} [put]
+func UpdateDepositRule(c *gin.Context) {
+	rule, ok := depositRule(c)
+	if !ok {
+		return
+	}
+
+	var request DepositRuleRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	if !applyDepositRuleRequest(c, &request, rule) {
+		return
+	}
+	if err := depositRuleHandler.UpdateDepositRule(rule); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error saving deposit rule"})
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// @Summary Delete a Deposit Rule
+// @Description Removes a deposit rule of a restaurant. Reservations already made keep their deposit. Only the owner of the restaurant or an admin can change the rules.
+// @Tags restaurants
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Param ruleId path int true "Deposit rule ID" Format(int64)
+// @security BearerAuth
+// @Success 204 "Rule deleted."
+// @Failure 400 {object} ErrorResponse "Invalid restaurant or rule ID."
+// @Failure 403 {object} ErrorResponse "The user does not manage this restaurant."
+// @Failure 404 {object} ErrorResponse "Rule not found for the restaurant."
+// @Failure 500 {object} ErrorResponse "Internal server error while deleting the rule."
+// @Router /restaurants/{id}/deposit-rules/{ruleId} [delete]
+func DeleteDepositRule(c *gin.Context) {
+	rule, ok := depositRule(c)
+	if !ok {
+		return
+	}
+
+	if err := depositRuleHandler.DeleteDepositRule(rule); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error deleting deposit rule"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,136 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/middleware"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"gorm.io/gorm"
+)
+
+var emailTemplateHandler *models.EmailTemplateHandler
+
+func InitializedEmailTemplateHandler(db *gorm.DB) {
+	emailTemplateHandler = models.NewEmailTemplateHandler(db)
+}
+
+type EmailTemplateRequest struct {
+	Subject string `json:"subject" binding:"required" example:"Reset your RedRice password"`
+	Body    string `json:"body" binding:"required" example:"Hi {{.name}}, ..."`
+}
+
+type EmailTemplatePreviewRequest struct {
+	// Subject and Body are a draft to render instead of the current copy
+	Subject string `json:"subject" example:"Reset your RedRice password"`
+	Body    string `json:"body" example:"Hi {{.name}}, ..."`
+	// Data overrides the sample values of the variables
+	Data map[string]string `json:"data" example:"name:Somchai"`
+}
+
+// respondEmailTemplateError writes the error of a template operation.
+func respondEmailTemplateError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, models.ErrUnknownEmailTemplate):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Email template not found"})
+	case errors.Is(err, models.ErrInvalidEmailTemplate):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
+
+// @Summary Get the Email Templates
+// @Description Lists the subject and body of every email the platform sends, with the variables they can use. Templates that were never edited show the built-in copy.
+// @Tags admin
+// @Produce json
+// @security BearerAuth
+// @Success 200 {array} models.EmailTemplate "The email templates."
+// @Failure 500 {object} ErrorResponse "Internal server error while fetching the templates."
+// @Router /admin/email-templates [get]
+func GetEmailTemplates(c *gin.Context) {
+	templates, err := emailTemplateHandler.GetTemplates()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching email templates"})
+		return
+	}
+	c.JSON(http.StatusOK, templates)
+}
+
+// @Summary Update an Email Template
+// @Description Replaces the subject and body of an email. Both are Go templates, e.g. "Hi {{.name}}", and may only use the variables of the template. The new copy is used for the next email sent, no deploy needed.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param key path string true "Template key" Enums(daily_digest, data_export_ready, email_change_confirm, email_changed, magic_link, password_reset, receipt, reservation_status, restaurant_announcement, sla_alert, staff_invitation)
+// @Param template body EmailTemplateRequest true "Subject and body"
+// @security BearerAuth
+// @Success 200 {object} models.EmailTemplate "The updated template."
+// @Failure 400 {object} ErrorResponse "Invalid input format or the template does not render."
+// @Failure 404 {object} ErrorResponse "Email template not found."
+// @Failure 500 {object} ErrorResponse "Internal server error while saving the template."
+// @Router /admin/email-templates/{key} [put]
+func UpdateEmailTemplate(c *gin.Context) {
+	var request EmailTemplateRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format, subject and body are required"})
+		return
+	}
+
+	claims := c.MustGet("claims").(*middleware.Claims)
+	tmpl, err := emailTemplateHandler.SaveTemplate(c.Param("key"), request.Subject, request.Body, claims.UserId)
+	if err != nil {
+		respondEmailTemplateError(c, err, "Error saving email template")
+		return
+	}
+	c.JSON(http.StatusOK, tmpl)
+}
+
+// @Summary Reset an Email Template
+// @Description Drops the edited copy of an email so the built-in one is sent again.
+// @Tags admin
+// @Produce json
+// @Param key path string true "Template key" Enums(daily_digest, data_export_ready, email_change_confirm, email_changed, magic_link, password_reset, receipt, reservation_status, restaurant_announcement, sla_alert, staff_invitation)
+// @security BearerAuth
+// @Success 200 {object} models.EmailTemplate "The built-in template."
+// @Failure 404 {object} ErrorResponse "Email template not found."
+// @Failure 500 {object} ErrorResponse "Internal server error while resetting the template."
+// @Router /admin/email-templates/{key} [delete]
+func ResetEmailTemplate(c *gin.Context) {
+	tmpl, err := emailTemplateHandler.ResetTemplate(c.Param("key"))
+	if err != nil {
+		respondEmailTemplateError(c, err, "Error resetting email template")
+		return
+	}
+	c.JSON(http.StatusOK, tmpl)
+}
+
+// @Summary Preview an Email Template
+// @Description Renders an email with sample values for its variables. Pass a subject or body to preview a draft before saving it, and data to try other values.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param key path string true "Template key" Enums(daily_digest, data_export_ready, email_change_confirm, email_changed, magic_link, password_reset, receipt, reservation_status, restaurant_announcement, sla_alert, staff_invitation)
+// @Param preview body EmailTemplatePreviewRequest false "Draft and values"
+// @security BearerAuth
+// @Success 200 {object} models.RenderedEmail "The rendered email."
+// @Failure 400 {object} ErrorResponse "Invalid input format or the template does not render."
+// @Failure 404 {object} ErrorResponse "Email template not found."
+// @Router /admin/email-templates/{key}/preview [post]
+func PreviewEmailTemplate(c *gin.Context) {
+	var request EmailTemplatePreviewRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+			return
+		}
+	}
+
+	rendered, err := emailTemplateHandler.Preview(c.Param("key"), request.Subject, request.Body, request.Data)
+	if err != nil {
+		respondEmailTemplateError(c, err, "Error rendering email template")
+		return
+	}
+	c.JSON(http.StatusOK, rendered)
+}
@@ -0,0 +1,182 @@
+package v1
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"github.com/punchanabu/redrice-backend-go/utils"
+	"gorm.io/gorm"
+)
+
+// eventHeartbeat keeps idle event streams open through proxies.
+const eventHeartbeat = 30 * time.Second
+
+const (
+	// defaultWaitTimeout is how long a long-polling request is held without a timeout parameter.
+	defaultWaitTimeout = 30 * time.Second
+	// maxWaitTimeout stays below the idle timeout of common proxies.
+	maxWaitTimeout = 60 * time.Second
+)
+
+const (
+	EventQueueUpdated       = "queue.updated"
+	EventReservationUpdated = "reservation.updated"
+)
+
+var eventBus *utils.EventBus
+
+func InitializedEventHandler(db *gorm.DB) {
+	eventBus = utils.NewEventBus(db)
+	go eventBus.Listen(context.Background(), os.Getenv("DB_CONN"))
+}
+
+// publishEvent is a no-op until the event bus is initialized.
+func publishEvent(eventType string, restaurantID uint, data interface{}) {
+	if eventBus == nil {
+		return
+	}
+	eventBus.Publish(utils.Event{Type: eventType, RestaurantID: restaurantID, Data: data})
+}
+
+// @Summary Stream Restaurant Events
+// @Description Opens a server-sent event stream with real-time updates of a restaurant: queue.updated when a party joins, is seated or leaves the queue, and reservation.updated when the status of a reservation changes. Updates reach the stream whichever server instance handled the change. Only the owner of the restaurant or an admin can open the stream.
+// @Tags restaurants
+// @Produce text/event-stream
+// @Param id path int true "Restaurant ID" Format(int64)
+// @security BearerAuth
+// @Success 200 {object} utils.Event "A stream of events."
+// @Failure 400 {object} ErrorResponse "Invalid restaurant ID."
+// @Failure 403 {object} ErrorResponse "The user does not manage the restaurant."
+// @Router /restaurants/{id}/events [get]
+func StreamRestaurantEvents(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant id"})
+		return
+	}
+
+	if !canManageRestaurant(c, uint(idInt)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to manage this restaurant"})
+		return
+	}
+
+	events, unsubscribe := eventBus.Subscribe(uint(idInt))
+	defer unsubscribe()
+
+	heartbeat := time.NewTicker(eventHeartbeat)
+	defer heartbeat.Stop()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case event := <-events:
+			c.SSEvent(event.Type, event)
+			return true
+		case <-heartbeat.C:
+			c.SSEvent("ping", "")
+			return true
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+type ReservationWaitResponse struct {
+	// Changed is false when the request timed out without a status change
+	Changed     bool                `json:"changed"`
+	Reservation *models.Reservation `json:"reservation"`
+}
+
+// @Summary Wait for a Reservation Status Change
+// @Description Long-polling alternative to the event stream for clients whose proxies break server-sent events. Holds the request until the status of the reservation differs from the given one, or until the timeout. Only the guest or the staff of the restaurant can wait on a reservation.
+// @Tags reservations
+// @Produce json
+// @Param id path int true "Reservation ID" Format(int64)
+// @Param status query string false "Status known to the client, defaults to the current status" Enums(pending, confirmed, declined, cancelled, completed)
+// @Param timeout query int false "Seconds to wait, 30 by default and at most 60"
+// @security BearerAuth
+// @Success 200 {object} ReservationWaitResponse "The reservation, with changed telling whether its status changed."
+// @Failure 400 {object} ErrorResponse "Invalid reservation ID or timeout."
+// @Failure 403 {object} ErrorResponse "The user cannot see this reservation."
+// @Failure 404 {object} ErrorResponse "Reservation not found."
+// @Router /reservations/{id}/wait [get]
+func WaitReservationStatus(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reservation id"})
+		return
+	}
+
+	timeout := defaultWaitTimeout
+	if value := c.Query("timeout"); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil || seconds < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "timeout must be a number of seconds"})
+			return
+		}
+		timeout = time.Duration(seconds) * time.Second
+		if timeout > maxWaitTimeout {
+			timeout = maxWaitTimeout
+		}
+	}
+
+	reservation, err := reservationHandler.GetReservation(uint(idInt))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Reservation not found"})
+		return
+	}
+
+	id, _ := c.Get("id")
+	if reservation.UserID != id.(uint) && !canManageRestaurant(c, reservation.RestaurantID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to see this reservation"})
+		return
+	}
+
+	known := c.DefaultQuery("status", reservation.Status)
+	if reservation.Status != known {
+		c.JSON(http.StatusOK, ReservationWaitResponse{Changed: true, Reservation: reservation})
+		return
+	}
+
+	// Subscribed before the status is read again so no change slips in between
+	events, unsubscribe := eventBus.Subscribe(reservation.RestaurantID)
+	defer unsubscribe()
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	for {
+		current, err := reservationHandler.GetReservation(reservation.ID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Reservation not found"})
+			return
+		}
+		if current.Status != known {
+			c.JSON(http.StatusOK, ReservationWaitResponse{Changed: true, Reservation: current})
+			return
+		}
+
+		// Any reservation update of the restaurant triggers a check
+	wait:
+		for {
+			select {
+			case event := <-events:
+				if event.Type == EventReservationUpdated {
+					break wait
+				}
+			case <-deadline.C:
+				c.JSON(http.StatusOK, ReservationWaitResponse{Changed: false, Reservation: current})
+				return
+			case <-c.Request.Context().Done():
+				return
+			}
+		}
+	}
+}
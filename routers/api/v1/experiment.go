@@ -0,0 +1,42 @@
+package v1
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"gorm.io/gorm"
+)
+
+var experimentHandler *models.ExperimentHandler
+
+func InitializedExperimentHandler(db *gorm.DB) {
+	experimentHandler = models.NewExperimentHandler(db)
+}
+
+// @Summary Get my experiments
+// @Description Retrieves the variant of every running A/B experiment the currently authenticated user is in. A user always gets the same variant of an experiment. Fetching the assignments logs that the user was exposed to them, once a day, if they agreed to analytics.
+// @Tags user
+// @Produce json
+// @security BearerAuth
+// @Success 200 {array} models.ExperimentAssignment "The variants of the user."
+// @Failure 401 {object} ErrorResponse "Unauthorized."
+// @Router /me/experiments [get]
+func GetMyExperiments(c *gin.Context) {
+	id, _ := c.Get("id")
+	userID := id.(uint)
+	assignments := models.AssignExperiments(userID)
+
+	// Failing to log the exposure must not change what the user is shown
+	granted, err := consentHandler.HasConsent(userID, models.ConsentAnalytics)
+	if err == nil && granted {
+		err = experimentHandler.RecordExposures(userID, assignments, time.Now())
+	}
+	if err != nil {
+		log.Printf("Failed to log experiment exposures of user %d: %v", userID, err)
+	}
+
+	c.JSON(http.StatusOK, assignments)
+}
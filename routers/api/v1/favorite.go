@@ -0,0 +1,116 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"gorm.io/gorm"
+)
+
+var favoriteHandler *models.FavoriteHandler
+
+func InitializedFavoriteHandler(db *gorm.DB) {
+	favoriteHandler = models.NewFavoriteHandler(db)
+}
+
+type FavoriteStatus struct {
+	RestaurantID  uint  `json:"restaurantId" example:"3"`
+	Favorited     bool  `json:"favorited" example:"true"`
+	FavoriteCount int64 `json:"favoriteCount" example:"128"`
+}
+
+type FavoritePage struct {
+	Data []models.Favorite `json:"data"`
+	Pagination
+}
+
+// @Summary Add a Favorite
+// @Description Bookmarks the restaurant for the currently authenticated user. Adding a restaurant that is already a favorite has no effect.
+// @Tags restaurants
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @security BearerAuth
+// @Success 200 {object} FavoriteStatus "The restaurant is a favorite of the user."
+// @Failure 400 {object} ErrorResponse "Invalid restaurant ID format."
+// @Failure 404 {object} ErrorResponse "Restaurant not found with the specified ID."
+// @Failure 500 {object} ErrorResponse "Internal server error while saving the favorite."
+// @Router /restaurants/{id}/favorite [post]
+func AddFavorite(c *gin.Context) {
+	setFavorite(c, true)
+}
+
+// @Summary Remove a Favorite
+// @Description Removes the bookmark of the restaurant for the currently authenticated user. Removing a restaurant that is not a favorite has no effect.
+// @Tags restaurants
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @security BearerAuth
+// @Success 200 {object} FavoriteStatus "The restaurant is not a favorite of the user."
+// @Failure 400 {object} ErrorResponse "Invalid restaurant ID format."
+// @Failure 404 {object} ErrorResponse "Restaurant not found with the specified ID."
+// @Failure 500 {object} ErrorResponse "Internal server error while removing the favorite."
+// @Router /restaurants/{id}/favorite [delete]
+func RemoveFavorite(c *gin.Context) {
+	setFavorite(c, false)
+}
+
+func setFavorite(c *gin.Context, favorited bool) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant ID"})
+		return
+	}
+	restaurantID := uint(idInt)
+	userID, _ := c.Get("id")
+
+	var count int64
+	if favorited {
+		count, err = favoriteHandler.AddFavorite(userID.(uint), restaurantID)
+	} else {
+		count, err = favoriteHandler.RemoveFavorite(userID.(uint), restaurantID)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating favorite"})
+		return
+	}
+
+	c.JSON(http.StatusOK, FavoriteStatus{RestaurantID: restaurantID, Favorited: favorited, FavoriteCount: count})
+}
+
+// @Summary Get my favorites
+// @Description Lists the restaurants bookmarked by the currently authenticated user, most recently added first.
+// @Tags user
+// @Produce json
+// @Param page query int false "Page number, starting at 1"
+// @Param limit query int false "Page size, at most 100"
+// @security BearerAuth
+// @Success 200 {object} FavoritePage "A page of favorites with their restaurant."
+// @Failure 401 {object} ErrorResponse "Unauthorized."
+// @Failure 500 {object} ErrorResponse "Internal server error while fetching the favorites."
+// @Router /me/favorites [get]
+func GetMyFavorites(c *gin.Context) {
+	id, _ := c.Get("id")
+	page, limit := parsePagination(c)
+
+	favorites, total, err := favoriteHandler.GetFavorites(id.(uint), page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching favorites"})
+		return
+	}
+
+	if favorites == nil {
+		favorites = []models.Favorite{}
+	}
+
+	c.JSON(http.StatusOK, FavoritePage{
+		Data:       favorites,
+		Pagination: Pagination{Page: page, Limit: limit, Total: total},
+	})
+}
@@ -0,0 +1,194 @@
+package v1
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"gorm.io/gorm"
+)
+
+var feedHandler *models.FeedHandler
+
+func InitializedFeedHandler(db *gorm.DB) {
+	feedHandler = models.NewFeedHandler(db)
+}
+
+type FollowStatus struct {
+	RestaurantID uint `json:"restaurantId" example:"3"`
+	Following    bool `json:"following" example:"true"`
+}
+
+type FeedPage struct {
+	Data []models.FeedItem `json:"data"`
+	Pagination
+}
+
+type AnnouncementRequest struct {
+	Title string `json:"title" example:"New lunch set"`
+	Body  string `json:"body" example:"Try our new lunch set, available weekdays from 11:00."`
+}
+
+// @Summary Follow a Restaurant
+// @Description Subscribes the currently authenticated user to the feed of the restaurant: new photos, listing changes and announcements. Following a restaurant twice has no effect.
+// @Tags restaurants
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @security BearerAuth
+// @Success 200 {object} FollowStatus "The user follows the restaurant."
+// @Failure 400 {object} ErrorResponse "Invalid restaurant ID format."
+// @Failure 404 {object} ErrorResponse "Restaurant not found with the specified ID."
+// @Failure 500 {object} ErrorResponse "Internal server error while following the restaurant."
+// @Router /restaurants/{id}/follow [post]
+func FollowRestaurant(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant ID"})
+		return
+	}
+
+	userID, _ := c.Get("id")
+	err = feedHandler.Follow(userID.(uint), uint(idInt))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error following restaurant"})
+		return
+	}
+
+	c.JSON(http.StatusOK, FollowStatus{RestaurantID: uint(idInt), Following: true})
+}
+
+// @Summary Unfollow a Restaurant
+// @Description Stops the feed of the restaurant for the currently authenticated user. Unfollowing a restaurant that is not followed has no effect.
+// @Tags restaurants
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @security BearerAuth
+// @Success 200 {object} FollowStatus "The user does not follow the restaurant."
+// @Failure 400 {object} ErrorResponse "Invalid restaurant ID format."
+// @Failure 500 {object} ErrorResponse "Internal server error while unfollowing the restaurant."
+// @Router /restaurants/{id}/follow [delete]
+func UnfollowRestaurant(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant ID"})
+		return
+	}
+
+	userID, _ := c.Get("id")
+	if err := feedHandler.Unfollow(userID.(uint), uint(idInt)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error unfollowing restaurant"})
+		return
+	}
+
+	c.JSON(http.StatusOK, FollowStatus{RestaurantID: uint(idInt), Following: false})
+}
+
+// @Summary Get my feed
+// @Description Lists what happened at the restaurants the currently authenticated user follows, newest first: photo.added for new gallery photos, listing.updated for changes to the listing and announcement for posts by the owner.
+// @Tags user
+// @Produce json
+// @Param page query int false "Page number, starting at 1"
+// @Param limit query int false "Page size, at most 100"
+// @security BearerAuth
+// @Success 200 {object} FeedPage "A page of feed items with their restaurant."
+// @Failure 401 {object} ErrorResponse "Unauthorized."
+// @Failure 500 {object} ErrorResponse "Internal server error while fetching the feed."
+// @Router /me/feed [get]
+func GetMyFeed(c *gin.Context) {
+	id, _ := c.Get("id")
+	page, limit := parsePagination(c)
+
+	items, total, err := feedHandler.GetFeed(id.(uint), page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching feed"})
+		return
+	}
+
+	if items == nil {
+		items = []models.FeedItem{}
+	}
+
+	c.JSON(http.StatusOK, FeedPage{
+		Data:       items,
+		Pagination: Pagination{Page: page, Limit: limit, Total: total},
+	})
+}
+
+// notifyFollowers emails the announcement to the followers of the restaurant
+// who agreed to marketing emails.
+func notifyFollowers(item models.FeedItem) {
+	restaurant, err := restaurantHandler.GetRestaurant(item.RestaurantID)
+	if err != nil {
+		log.Printf("Failed to load restaurant %d for announcement %d: %v", item.RestaurantID, item.ID, err)
+		return
+	}
+	followers, err := feedHandler.GetFollowers(item.RestaurantID)
+	if err != nil {
+		log.Printf("Failed to load the followers of restaurant %d: %v", item.RestaurantID, err)
+		return
+	}
+
+	for i := range followers {
+		data := map[string]string{"name": followers[i].Name, "restaurant": restaurant.Name, "title": item.Title, "body": item.Body}
+		if err := notify(&followers[i], models.NotificationChannelEmail, models.ConsentMarketingEmails, models.EmailTemplateAnnouncement, data); err != nil {
+			log.Printf("Failed to notify user %d of announcement %d: %v", followers[i].ID, item.ID, err)
+		}
+	}
+}
+
+// @Summary Post an Announcement
+// @Description Publishes an announcement to the followers of the restaurant. Followers who agreed to marketing emails are also emailed, right away or in their daily digest. Only the owner of the restaurant or an admin can post announcements.
+// @Tags restaurants
+// @Accept json
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Param announcement body AnnouncementRequest true "Announcement"
+// @security BearerAuth
+// @Success 201 {object} models.FeedItem "The published announcement."
+// @Failure 400 {object} ErrorResponse "Invalid restaurant ID or missing title."
+// @Failure 403 {object} ErrorResponse "The user does not manage this restaurant."
+// @Failure 500 {object} ErrorResponse "Internal server error while publishing the announcement."
+// @Router /restaurants/{id}/announcements [post]
+func CreateAnnouncement(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant ID"})
+		return
+	}
+	restaurantID := uint(idInt)
+
+	var request AnnouncementRequest
+	if err := c.ShouldBindJSON(&request); err != nil || strings.TrimSpace(request.Title) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	if !canManageRestaurant(c, restaurantID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to manage this restaurant"})
+		return
+	}
+
+	userID, _ := c.Get("id")
+	item := models.FeedItem{
+		RestaurantID: restaurantID,
+		Type:         models.FeedAnnouncement,
+		Title:        strings.TrimSpace(request.Title),
+		Body:         strings.TrimSpace(request.Body),
+		CreatedBy:    userID.(uint),
+	}
+	if err := feedHandler.CreateItem(&item); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error publishing announcement"})
+		return
+	}
+	go notifyFollowers(item)
+
+	c.JSON(http.StatusCreated, item)
+}
@@ -0,0 +1,188 @@
+package v1
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/mail"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"gorm.io/gorm"
+)
+
+const (
+	invitationTTL = 7 * 24 * time.Hour
+	// maxInvitationRows caps the size of a single CSV upload.
+	maxInvitationRows = 500
+)
+
+var invitationHandler *models.InvitationHandler
+
+func InitializedInvitationHandler(db *gorm.DB) {
+	invitationHandler = models.NewInvitationHandler(db)
+}
+
+const (
+	InvitationInvited = "invited"
+	InvitationFailed  = "failed"
+)
+
+type InvitationResult struct {
+	Row    int    `json:"row" example:"2"`
+	Email  string `json:"email" example:"staff@example.com"`
+	Status string `json:"status" example:"invited"`
+	Error  string `json:"error,omitempty" example:""`
+}
+
+type BulkInvitationResponse struct {
+	Invited int                `json:"invited" example:"1"`
+	Failed  int                `json:"failed" example:"0"`
+	Results []InvitationResult `json:"results"`
+}
+
+// @Summary Invite Restaurant Staff from CSV
+// @Description Uploads a CSV file of staff emails, one per row with an optional header row "email". Each address gets an invitation email to join the restaurant and the response reports the result of every row. Only the owner of the restaurant or an admin can invite staff.
+// @Tags restaurants
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Param file formData file true "CSV file of emails"
+// @security BearerAuth
+// @Success 200 {object} BulkInvitationResponse "The per-row invitation results."
+// @Failure 400 {object} ErrorResponse "Invalid restaurant ID or CSV file."
+// @Failure 403 {object} ErrorResponse "The user does not manage this restaurant."
+// @Failure 404 {object} ErrorResponse "Restaurant not found with the specified ID."
+// @Router /restaurants/{id}/invitations/bulk [post]
+func BulkInviteStaff(c *gin.Context) {
+	idString := c.Param("id")
+	idInt, err := strconv.Atoi(idString)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant id"})
+		return
+	}
+	idUint := uint(idInt)
+
+	if !canManageRestaurant(c, idUint) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to manage this restaurant"})
+		return
+	}
+
+	restaurant, err := RestaurantHandler.GetRestaurant(idUint)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+		return
+	}
+
+	file, _, err := c.Request.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing file!"})
+		return
+	}
+	defer file.Close()
+
+	rows, err := readInvitationCSV(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	id, _ := c.Get("id")
+	response := BulkInvitationResponse{Results: []InvitationResult{}}
+	seen := map[string]bool{}
+	for i, row := range rows {
+		// Rows are numbered like in a spreadsheet
+		result := InvitationResult{Row: i + 1, Email: row}
+		if row == "" {
+			continue
+		}
+
+		email, err := inviteStaff(restaurant, row, id.(uint), seen)
+		if email != "" {
+			result.Email = email
+		}
+		if err != nil {
+			result.Status = InvitationFailed
+			result.Error = err.Error()
+			response.Failed++
+		} else {
+			result.Status = InvitationInvited
+			response.Invited++
+		}
+		response.Results = append(response.Results, result)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// readInvitationCSV returns the first column of every row of the CSV file,
+// with the header row blanked out so row numbers stay intact.
+func readInvitationCSV(file io.Reader) ([]string, error) {
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var rows []string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV file: %v", err)
+		}
+
+		value := ""
+		if len(record) > 0 {
+			value = strings.TrimSpace(record[0])
+		}
+		if len(rows) == 0 && strings.EqualFold(value, "email") {
+			value = ""
+		}
+		rows = append(rows, value)
+
+		if len(rows) > maxInvitationRows {
+			return nil, fmt.Errorf("CSV file has more than %d rows", maxInvitationRows)
+		}
+	}
+
+	if len(rows) == 0 {
+		return nil, errors.New("CSV file is empty")
+	}
+	return rows, nil
+}
+
+func inviteStaff(restaurant *models.Restaurant, value string, invitedBy uint, seen map[string]bool) (string, error) {
+	address, err := mail.ParseAddress(value)
+	if err != nil {
+		return "", errors.New("invalid email address")
+	}
+	email := strings.ToLower(address.Address)
+
+	if seen[email] {
+		return email, errors.New("duplicate email in file")
+	}
+	seen[email] = true
+
+	_, token, err := invitationHandler.CreateInvitation(email, restaurant.ID, invitedBy, invitationTTL)
+	if err != nil {
+		return email, err
+	}
+
+	link := fmt.Sprintf("%s/accept-invitation?token=%s", os.Getenv("FRONTEND_URL"), token)
+	data := map[string]string{"restaurant": restaurant.Name, "days": strconv.Itoa(int(invitationTTL.Hours() / 24)), "link": link}
+
+	if err := emailTemplateHandler.Send(email, models.EmailTemplateStaffInvitation, data); err != nil {
+		log.Println("Error sending invitation email:", err)
+		return email, errors.New("invitation created but the email could not be sent")
+	}
+
+	return email, nil
+}
@@ -0,0 +1,71 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/middleware"
+	"github.com/punchanabu/redrice-backend-go/models"
+)
+
+// UsageQuota is how much of a limit on an action the user has used.
+type UsageQuota struct {
+	Limit     int `json:"limit" example:"3"`
+	Used      int `json:"used" example:"1"`
+	Remaining int `json:"remaining" example:"2"`
+}
+
+func newUsageQuota(limit, used int) *UsageQuota {
+	remaining := limit - used
+	if remaining < 0 {
+		remaining = 0
+	}
+	return &UsageQuota{Limit: limit, Used: used, Remaining: remaining}
+}
+
+// LimitsResponse lists the limits applying to the current user. Limits the
+// user is exempt from are left out.
+type LimitsResponse struct {
+	Requests     *middleware.Quota `json:"requests,omitempty"`
+	Reservations *UsageQuota       `json:"reservations,omitempty"`
+	// PhoneCodes counts the verification codes sent during the last hour
+	PhoneCodes *UsageQuota `json:"phoneCodes"`
+}
+
+// @Summary Get my limits
+// @Description Returns the limits applying to the currently authenticated user and how much of them is left: API requests in the current rate limit window, reservations that can be held and telephone verification codes per hour. The request quota is the one also sent in the X-RateLimit-* headers, after counting this request.
+// @Tags user
+// @Produce json
+// @security BearerAuth
+// @Success 200 {object} LimitsResponse "The limits of the user."
+// @Failure 401 {object} ErrorResponse "Unauthorized."
+// @Failure 500 {object} ErrorResponse "Internal server error while counting usage."
+// @Router /me/limits [get]
+func GetMyLimits(c *gin.Context) {
+	id, _ := c.Get("id")
+	claims := c.MustGet("claims").(*middleware.Claims)
+
+	var response LimitsResponse
+	if quota, ok := middleware.RequestQuota(c); ok {
+		response.Requests = &quota
+	}
+
+	if !models.HasPermission(claims.Role, models.PermissionBypassBookingLimits) {
+		reservations, err := reservationHandler.GetReservationsByUserID(id.(uint))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching reservations for user"})
+			return
+		}
+		response.Reservations = newUsageQuota(maxReservationsPerUser, len(reservations))
+	}
+
+	codes, err := phoneVerificationHandler.CountRecentCodes(id.(uint), time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error counting verification codes"})
+		return
+	}
+	response.PhoneCodes = newUsageQuota(models.PhoneCodeMaxSendsPerHour, int(codes))
+
+	c.JSON(http.StatusOK, response)
+}
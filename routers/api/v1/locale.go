@@ -0,0 +1,18 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/models"
+)
+
+// requestLocale reads the locale the client asked for with Accept-Language,
+// false when it names no supported language. Responses then carry formatted
+// strings next to the raw values.
+func requestLocale(c *gin.Context) (models.Locale, bool) {
+	c.Header("Vary", "Accept-Language")
+	locale, ok := models.ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+	if ok {
+		c.Header("Content-Language", locale.Language)
+	}
+	return locale, ok
+}
@@ -0,0 +1,428 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"github.com/punchanabu/redrice-backend-go/utils"
+	"gorm.io/gorm"
+)
+
+var menuHandler *models.MenuHandler
+
+func InitializedMenuHandler(db *gorm.DB) {
+	menuHandler = models.NewMenuHandler(db)
+}
+
+type MenuCategoryRequest struct {
+	Name     string `json:"name" example:"Curries"`
+	Position *int   `json:"position" example:"1"`
+}
+
+type MenuItemRequest struct {
+	CategoryID  uint         `json:"categoryId" example:"4"`
+	Name        string       `json:"name" example:"Green curry with chicken"`
+	Description string       `json:"description" example:"Spicy, with Thai basil and eggplant"`
+	Price       models.Money `json:"price"`
+	// Available defaults to true
+	Available *bool `json:"available" example:"true"`
+	Position  *int  `json:"position" example:"2"`
+}
+
+// managedRestaurantID reads the ID of the restaurant from the path and checks
+// that the user manages it. It writes the error response and returns false
+// otherwise.
+func managedRestaurantID(c *gin.Context) (uint, bool) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant id"})
+		return 0, false
+	}
+	if !canManageRestaurant(c, uint(idInt)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to manage this restaurant"})
+		return 0, false
+	}
+	return uint(idInt), true
+}
+
+// menuCategory reads the category from the path, writing the error response
+// and returning false when the user cannot manage it.
+func menuCategory(c *gin.Context) (*models.MenuCategory, bool) {
+	restaurantID, ok := managedRestaurantID(c)
+	if !ok {
+		return nil, false
+	}
+	categoryID, err := strconv.Atoi(c.Param("categoryId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid menu category id"})
+		return nil, false
+	}
+	category, err := menuHandler.GetMenuCategory(restaurantID, uint(categoryID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Menu category not found"})
+		return nil, false
+	}
+	return category, true
+}
+
+// menuItem reads the item from the path, writing the error response and
+// returning false when the user cannot manage it.
+func menuItem(c *gin.Context) (*models.MenuItem, bool) {
+	restaurantID, ok := managedRestaurantID(c)
+	if !ok {
+		return nil, false
+	}
+	itemID, err := strconv.Atoi(c.Param("itemId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid menu item id"})
+		return nil, false
+	}
+	item, err := menuHandler.GetMenuItem(restaurantID, uint(itemID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Menu item not found"})
+		return nil, false
+	}
+	return item, true
+}
+
+// applyMenuItemRequest copies the request onto the item after validating it,
+// writing the error response and returning false when it is invalid.
+func applyMenuItemRequest(c *gin.Context, request *MenuItemRequest, item *models.MenuItem) bool {
+	if request.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return false
+	}
+	if request.Price.Currency == "" {
+		request.Price.Currency = models.DefaultCurrency
+	}
+	if err := request.Price.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid price: " + err.Error()})
+		return false
+	}
+
+	item.CategoryID = request.CategoryID
+	item.Name = request.Name
+	item.Description = request.Description
+	item.Price = request.Price
+	if request.Available != nil {
+		item.Available = *request.Available
+	}
+	if request.Position != nil {
+		item.Position = *request.Position
+	}
+	return true
+}
+
+// respondMenuItemError answers a failed save of a menu item.
+func respondMenuItemError(c *gin.Context, err error) {
+	if errors.Is(err, models.ErrUnknownMenuCategory) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Error saving menu item"})
+}
+
+// @Summary Get Restaurant Menu
+// @Description Lists the categories of the menu of a restaurant in their order, each with its items in their order. Prices are in minor units of their currency, e.g. 18000 THB for 180 baht, with the currency parameter they are also shown converted for display.
+// @Tags menu
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Param currency query string false "Currency to also show the prices in, e.g. USD" example(USD)
+// @Param Accept-Language header string false "Language to also write dates and amounts in, th or en, optionally with a calendar such as en-u-ca-buddhist" example(th-TH)
+// @security BearerAuth
+// @Success 200 {array} models.MenuCategory "The menu of the restaurant."
+// @Failure 400 {object} ErrorResponse "Invalid restaurant ID format."
+// @Failure 404 {object} ErrorResponse "Restaurant not found with the specified ID, or not listed and not managed by the user."
+// @Failure 500 {object} ErrorResponse "Internal server error while fetching the menu."
+// @Router /restaurants/{id}/menu [get]
+func GetRestaurantMenu(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant id"})
+		return
+	}
+
+	currency, ok := displayCurrency(c)
+	if !ok {
+		return
+	}
+
+	if _, ok := findRestaurant(c, uint(idInt)); !ok {
+		return
+	}
+
+	menu, err := menuHandler.GetMenu(uint(idInt))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching menu"})
+		return
+	}
+
+	exchangeRates.DisplayMenu(menu, currency)
+	if locale, ok := requestLocale(c); ok {
+		locale.LocalizeMenu(menu)
+	}
+	c.JSON(http.StatusOK, menu)
+}
+
+// @Summary Create a Menu Category
+// @Description Adds a category at the end of the menu of a restaurant. Only the owner of the restaurant or an admin can change the menu.
+// @Tags menu
+// @Accept json
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Param category body MenuCategoryRequest true "Menu category, the position is ignored"
+// @security BearerAuth
+// @Success 201 {object} models.MenuCategory "The created category."
+// @Failure 400 {object} ErrorResponse "Invalid input format or missing name."
+// @Failure 403 {object} ErrorResponse "The user does not manage this restaurant."
+// @Failure 500 {object} ErrorResponse "Internal server error while creating the category."
+// @Router /restaurants/{id}/menu/categories [post]
+func CreateMenuCategory(c *gin.Context) {
+	restaurantID, ok := managedRestaurantID(c)
+	if !ok {
+		return
+	}
+
+	var request MenuCategoryRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+	if request.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+
+	category := models.MenuCategory{RestaurantID: restaurantID, Name: request.Name, Items: []models.MenuItem{}}
+	if err := menuHandler.CreateMenuCategory(&category); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating menu category"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, category)
+}
+
+// @Summary Update a Menu Category
+// @Description Renames a category of the menu of a restaurant and optionally moves it. Only the owner of the restaurant or an admin can change the menu.
+// @Tags menu
+// @Accept json
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Param categoryId path int true "Menu category ID" Format(int64)
+// @Param category body MenuCategoryRequest true "Menu category"
+// @security BearerAuth
+// @Success 200 {object} models.MenuCategory "The updated category, without its items."
+// @Failure 400 {object} ErrorResponse "Invalid input format or missing name."
+// @Failure 403 {object} ErrorResponse "The user does not manage this restaurant."
+// @Failure 404 {object} ErrorResponse "Category not found in the menu of the restaurant."
+// @Failure 500 {object} ErrorResponse "Internal server error while updating the category."
+// @Router /restaurants/{id}/menu/categories/{categoryId} [put]
+func UpdateMenuCategory(c *gin.Context) {
+	category, ok := menuCategory(c)
+	if !ok {
+		return
+	}
+
+	var request MenuCategoryRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+	if request.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+
+	category.Name = request.Name
+	if request.Position != nil {
+		category.Position = *request.Position
+	}
+	if err := menuHandler.UpdateMenuCategory(category); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating menu category"})
+		return
+	}
+
+	category.Items = []models.MenuItem{}
+	c.JSON(http.StatusOK, category)
+}
+
+// @Summary Delete a Menu Category
+// @Description Removes a category from the menu of a restaurant together with its items. Only the owner of the restaurant or an admin can change the menu.
+// @Tags menu
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Param categoryId path int true "Menu category ID" Format(int64)
+// @security BearerAuth
+// @Success 204 "Category deleted."
+// @Failure 400 {object} ErrorResponse "Invalid restaurant or category ID."
+// @Failure 403 {object} ErrorResponse "The user does not manage this restaurant."
+// @Failure 404 {object} ErrorResponse "Category not found in the menu of the restaurant."
+// @Failure 500 {object} ErrorResponse "Internal server error while deleting the category."
+// @Router /restaurants/{id}/menu/categories/{categoryId} [delete]
+func DeleteMenuCategory(c *gin.Context) {
+	category, ok := menuCategory(c)
+	if !ok {
+		return
+	}
+
+	if err := menuHandler.DeleteMenuCategory(category); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error deleting menu category"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Create a Menu Item
+// @Description Adds an item at the end of a category of the menu of a restaurant. The price is in minor units of its currency, which defaults to THB. Only the owner of the restaurant or an admin can change the menu.
+// @Tags menu
+// @Accept json
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Param item body MenuItemRequest true "Menu item, the position is ignored"
+// @security BearerAuth
+// @Success 201 {object} models.MenuItem "The created item."
+// @Failure 400 {object} ErrorResponse "Invalid input format, price or category."
+// @Failure 403 {object} ErrorResponse "The user does not manage this restaurant."
+// @Failure 500 {object} ErrorResponse "Internal server error while creating the item."
+// @Router /restaurants/{id}/menu/items [post]
+func CreateMenuItem(c *gin.Context) {
+	restaurantID, ok := managedRestaurantID(c)
+	if !ok {
+		return
+	}
+
+	var request MenuItemRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+	request.Position = nil
+
+	item := models.MenuItem{RestaurantID: restaurantID, Available: true}
+	if !applyMenuItemRequest(c, &request, &item) {
+		return
+	}
+	if err := menuHandler.CreateMenuItem(&item); err != nil {
+		respondMenuItemError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, item)
+}
+
+// @Summary Update a Menu Item
+// @Description Replaces the details of an item of the menu of a restaurant, which may move it to another category. Only the owner of the restaurant or an admin can change the menu.
+// @Tags menu
+// @Accept json
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Param itemId path int true "Menu item ID" Format(int64)
+// @Param item body MenuItemRequest true "Menu item"
+// @security BearerAuth
+// @Success 200 {object} models.MenuItem "The updated item."
+// @Failure 400 {object} ErrorResponse "Invalid input format, price or category."
+// @Failure 403 {object} ErrorResponse "The user does not manage this restaurant."
+// @Failure 404 {object} ErrorResponse "Item not found in the menu of the restaurant."
+// @Failure 500 {object} ErrorResponse "Internal server error while updating the item."
+// @Router /restaurants/{id}/menu/items/{itemId} [put]
+func UpdateMenuItem(c *gin.Context) {
+	item, ok := menuItem(c)
+	if !ok {
+		return
+	}
+
+	var request MenuItemRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	if !applyMenuItemRequest(c, &request, item) {
+		return
+	}
+	if err := menuHandler.UpdateMenuItem(item); err != nil {
+		respondMenuItemError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+// @Summary Delete a Menu Item
+// @Description Removes an item from the menu of a restaurant. Only the owner of the restaurant or an admin can change the menu.
+// @Tags menu
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Param itemId path int true "Menu item ID" Format(int64)
+// @security BearerAuth
+// @Success 204 "Item deleted."
+// @Failure 400 {object} ErrorResponse "Invalid restaurant or item ID."
+// @Failure 403 {object} ErrorResponse "The user does not manage this restaurant."
+// @Failure 404 {object} ErrorResponse "Item not found in the menu of the restaurant."
+// @Failure 500 {object} ErrorResponse "Internal server error while deleting the item."
+// @Router /restaurants/{id}/menu/items/{itemId} [delete]
+func DeleteMenuItem(c *gin.Context) {
+	item, ok := menuItem(c)
+	if !ok {
+		return
+	}
+
+	if err := menuHandler.DeleteMenuItem(item); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error deleting menu item"})
+		return
+	}
+
+	deleteImageObjects(item.ImageURL)
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Upload a Menu Item Photo
+// @Description Sets the photo of an item of the menu of a restaurant, replacing the previous one. Only the owner of the restaurant or an admin can change the menu.
+// @Tags menu
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Param itemId path int true "Menu item ID" Format(int64)
+// @Param image formData file true "Image file"
+// @security BearerAuth
+// @Success 200 {object} models.MenuItem "The item with its new photo."
+// @Failure 400 {object} ErrorResponse "Invalid restaurant or item ID, or missing image."
+// @Failure 403 {object} ErrorResponse "The user does not manage this restaurant."
+// @Failure 404 {object} ErrorResponse "Item not found in the menu of the restaurant."
+// @Failure 500 {object} ErrorResponse "Internal server error while uploading the photo."
+// @Router /restaurants/{id}/menu/items/{itemId}/photo [put]
+func UploadMenuItemPhoto(c *gin.Context) {
+	item, ok := menuItem(c)
+	if !ok {
+		return
+	}
+
+	file, header, err := c.Request.FormFile("image")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing image!"})
+		return
+	}
+	defer file.Close()
+
+	imageUrl, err := utils.UploadImageToS3("redrice", file, header.Filename)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error uploading image!"})
+		return
+	}
+
+	previous := item.ImageURL
+	item.ImageURL = imageUrl
+	if err := menuHandler.UpdateMenuItem(item); err != nil {
+		deleteImageObjects(imageUrl)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error saving menu item"})
+		return
+	}
+
+	// The previous photo is not referenced anymore
+	deleteImageObjects(previous)
+	c.JSON(http.StatusOK, item)
+}
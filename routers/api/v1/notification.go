@@ -0,0 +1,171 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"github.com/punchanabu/redrice-backend-go/utils"
+	"gorm.io/gorm"
+)
+
+// notificationDigestInterval is how often the notifications waiting for a digest are checked.
+const notificationDigestInterval = time.Hour
+
+// notificationDigestPeriod is how long notifications are gathered before a digest is sent.
+const notificationDigestPeriod = 24 * time.Hour
+
+// notificationDedupWindow is how long an identical notification to the same
+// user is dropped after the first, e.g. when a status flaps back and forth.
+const notificationDedupWindow = time.Hour
+
+var notificationHandler *models.NotificationHandler
+
+func InitializedNotificationHandler(db *gorm.DB) {
+	notificationHandler = models.NewNotificationHandler(db)
+	utils.RunEveryExclusive(db, notificationDigestInterval, "send notification digests", sendNotificationDigests)
+}
+
+// deliverNotification sends a rendered message to the user over the channel.
+func deliverNotification(user *models.User, channel string, message *models.RenderedEmail) error {
+	if channel == models.NotificationChannelSMS {
+		if user.Telephone == "" {
+			return fmt.Errorf("user %d has no telephone", user.ID)
+		}
+		return smsProvider.SendSMS(user.Telephone, message.Subject+"\n"+message.Body)
+	}
+	return utils.SendEmail(user.ContactAddress(), message.Subject, message.Body)
+}
+
+// notify renders the template for the user and delivers it over the channel,
+// right away or with the next daily digest depending on the preferences of
+// the user. Notifications needing a consent purpose are dropped for users who
+// have not agreed to it, and so are repeats of a notification sent within the
+// dedup window.
+func notify(user *models.User, channel, purpose, template string, data map[string]string) error {
+	if purpose != "" {
+		granted, err := consentHandler.HasConsent(user.ID, purpose)
+		if err != nil {
+			return err
+		}
+		if !granted {
+			return nil
+		}
+	}
+
+	message, err := emailTemplateHandler.Render(template, data)
+	if err != nil {
+		return err
+	}
+
+	preferences, err := preferencesHandler.GetPreferences(user.ID)
+	if err != nil {
+		return err
+	}
+
+	notification := models.Notification{
+		UserID:   user.ID,
+		Channel:  channel,
+		Template: template,
+		Subject:  message.Subject,
+		Body:     message.Body,
+		Purpose:  purpose,
+	}
+	claimed, err := notificationHandler.ClaimNotification(&notification, time.Now().Add(-notificationDedupWindow))
+	if err != nil || !claimed {
+		return err
+	}
+
+	if preferences.Delivery(channel) != models.NotificationDeliveryImmediate {
+		return nil
+	}
+	if err := deliverNotification(user, channel, message); err != nil {
+		if releaseErr := notificationHandler.ReleaseNotification(&notification); releaseErr != nil {
+			log.Printf("Failed to release notification %d: %v", notification.ID, releaseErr)
+		}
+		return err
+	}
+	return notificationHandler.MarkSent([]models.Notification{notification}, time.Now())
+}
+
+// sendNotificationDigests sends one digest per user and channel with the
+// notifications gathered over the digest period.
+func sendNotificationDigests() error {
+	now := time.Now()
+	if err := notificationHandler.DeleteExpiredClaims(now.Add(-notificationDedupWindow)); err != nil {
+		return err
+	}
+	for _, channel := range models.NotificationChannels {
+		digests, err := notificationHandler.GetDueDigests(channel, now.Add(-notificationDigestPeriod))
+		if err != nil {
+			return err
+		}
+
+		for userID, notifications := range digests {
+			if err := sendNotificationDigest(userID, channel, notifications, now); err != nil {
+				log.Printf("Failed to send the %s digest of user %d: %v", channel, userID, err)
+			}
+		}
+	}
+	return nil
+}
+
+func sendNotificationDigest(userID uint, channel string, notifications []models.Notification, now time.Time) error {
+	user, err := userHandler.GetUser(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Nobody to send to anymore, do not try again
+		return notificationHandler.MarkSent(notifications, now)
+	}
+	if err != nil {
+		return err
+	}
+
+	items := make([]string, len(notifications))
+	for i, notification := range notifications {
+		items[i] = notification.Subject + "\n" + notification.Body
+	}
+	message, err := emailTemplateHandler.Render(models.EmailTemplateDailyDigest, map[string]string{
+		"name":  user.Name,
+		"count": strconv.Itoa(len(notifications)),
+		"items": strings.Join(items, "\n\n"),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := deliverNotification(user, channel, message); err != nil {
+		return err
+	}
+	return notificationHandler.MarkSent(notifications, now)
+}
+
+// @Summary Get Notification Stats
+// @Description Counts per channel the notifications created in the period: sent, waiting for a digest, and suppressed as repeats of an identical notification sent to the same user within the last hour. The period defaults to the current month.
+// @Tags admin
+// @Produce json
+// @Param from query string false "First day of the period in YYYY-MM-DD format"
+// @Param to query string false "Last day of the period in YYYY-MM-DD format"
+// @security BearerAuth
+// @Success 200 {array} models.NotificationStats "The counts of every channel."
+// @Failure 400 {object} ErrorResponse "Invalid period."
+// @Failure 500 {object} ErrorResponse "Internal server error while counting the notifications."
+// @Router /admin/notifications/stats [get]
+func GetNotificationStats(c *gin.Context) {
+	from, to, ok := parsePeriod(c)
+	if !ok {
+		return
+	}
+
+	stats, err := notificationHandler.GetStats(from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error counting notifications"})
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
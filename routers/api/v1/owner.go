@@ -0,0 +1,42 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/models"
+)
+
+// @Summary Get Owner Dashboard Summary
+// @Description Summarizes the restaurants the authenticated user manages for the owner dashboard: the reservations of the local day, the upcoming reservations waiting for a confirmation and the latest reviews of each restaurant. Admins get every restaurant.
+// @Tags restaurants
+// @Produce json
+// @security BearerAuth
+// @Success 200 {object} models.OwnerSummary "The summary of the restaurants."
+// @Failure 401 {object} ErrorResponse "Unauthorized."
+// @Failure 500 {object} ErrorResponse "Internal server error while building the summary."
+// @Router /owner/summary [get]
+func GetOwnerSummary(c *gin.Context) {
+	id, _ := c.Get("id")
+	user, err := userHandler.GetUser(id.(uint))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var restaurantIDs []uint
+	if !models.HasPermission(user.Role, models.PermissionManageAnyRestaurant) {
+		restaurantIDs = []uint{}
+		if user.RestaurantId != 0 {
+			restaurantIDs = append(restaurantIDs, user.RestaurantId)
+		}
+	}
+
+	summary, err := RestaurantHandler.GetOwnerSummary(restaurantIDs, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error building summary"})
+		return
+	}
+	c.JSON(http.StatusOK, summary)
+}
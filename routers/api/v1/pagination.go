@@ -0,0 +1,37 @@
+package v1
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+type Pagination struct {
+	Page  int   `json:"page" example:"1"`
+	Limit int   `json:"limit" example:"20"`
+	Total int64 `json:"total" example:"42"`
+}
+
+// parsePagination reads the page and limit query parameters, falling back to
+// sane defaults for missing or invalid values.
+func parsePagination(c *gin.Context) (page int, limit int) {
+	page, err := strconv.Atoi(c.Query("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err = strconv.Atoi(c.Query("limit"))
+	if err != nil || limit < 1 {
+		limit = defaultPageLimit
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+
+	return page, limit
+}
@@ -0,0 +1,132 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/config"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"gorm.io/gorm"
+)
+
+var payoutHandler *models.PayoutHandler
+
+func InitializedPayoutHandler(db *gorm.DB) {
+	payoutHandler = models.NewPayoutHandler(db)
+}
+
+// parsePeriod reads the from and to query parameters as inclusive days and
+// returns the half-open range [from, to). It defaults to the current month.
+func parsePeriod(c *gin.Context) (time.Time, time.Time, bool) {
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+	to := from.AddDate(0, 1, 0)
+
+	if value := c.Query("from"); value != "" {
+		day, err := time.ParseInLocation("2006-01-02", value, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date, expected YYYY-MM-DD"})
+			return from, to, false
+		}
+		from = day
+	}
+
+	if value := c.Query("to"); value != "" {
+		day, err := time.ParseInLocation("2006-01-02", value, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date, expected YYYY-MM-DD"})
+			return from, to, false
+		}
+		to = day.AddDate(0, 0, 1)
+	}
+
+	if !from.Before(to) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must not be after to"})
+		return from, to, false
+	}
+
+	return from, to, true
+}
+
+// @Summary Get Payouts
+// @Description Lists, per restaurant and currency, the deposits of completed reservations in the period and splits them into the platform commission and the payout owed to the owner. The period defaults to the current month.
+// @Tags admin
+// @Produce json
+// @Param from query string false "First day of the period in YYYY-MM-DD format"
+// @Param to query string false "Last day of the period in YYYY-MM-DD format"
+// @security BearerAuth
+// @Success 200 {array} models.PayoutSummary "The payout summary of every restaurant with deposits."
+// @Failure 400 {object} ErrorResponse "Invalid period."
+// @Failure 500 {object} ErrorResponse "Internal server error while computing the payouts."
+// @Router /admin/payouts [get]
+func GetPayouts(c *gin.Context) {
+	from, to, ok := parsePeriod(c)
+	if !ok {
+		return
+	}
+
+	summaries, err := payoutHandler.GetPayouts(from, to, config.CommissionRate())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error computing payouts"})
+		return
+	}
+
+	if summaries == nil {
+		summaries = []models.PayoutSummary{}
+	}
+	c.JSON(http.StatusOK, summaries)
+}
+
+// @Summary Get Restaurant Payout Statement
+// @Description Shows the deposits, commission and payout of a restaurant for the period with every reservation they are made of. Only the owner of the restaurant or an admin can see it. The period defaults to the current month.
+// @Tags restaurants
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Param from query string false "First day of the period in YYYY-MM-DD format"
+// @Param to query string false "Last day of the period in YYYY-MM-DD format"
+// @Param currency query string false "Currency of the deposits, defaults to the current deposit currency of the restaurant"
+// @security BearerAuth
+// @Success 200 {object} models.PayoutStatement "The payout statement."
+// @Failure 400 {object} ErrorResponse "Invalid restaurant ID, period or currency."
+// @Failure 403 {object} ErrorResponse "The user does not manage this restaurant."
+// @Failure 404 {object} ErrorResponse "Restaurant not found with the specified ID."
+// @Router /restaurants/{id}/statement [get]
+func GetRestaurantStatement(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant id"})
+		return
+	}
+
+	if !canManageRestaurant(c, uint(idInt)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to manage this restaurant"})
+		return
+	}
+
+	from, to, ok := parsePeriod(c)
+	if !ok {
+		return
+	}
+
+	restaurant, err := RestaurantHandler.GetRestaurant(uint(idInt))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+		return
+	}
+
+	currency := c.DefaultQuery("currency", restaurant.Deposit.Currency)
+	if !models.IsValidCurrency(currency) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported currency " + currency})
+		return
+	}
+
+	statement, err := payoutHandler.GetStatement(restaurant, currency, from, to, config.CommissionRate())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error computing statement"})
+		return
+	}
+
+	c.JSON(http.StatusOK, statement)
+}
@@ -0,0 +1,116 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"github.com/punchanabu/redrice-backend-go/utils"
+	"gorm.io/gorm"
+)
+
+var phoneVerificationHandler *models.PhoneVerificationHandler
+var smsProvider utils.SMSProvider
+
+func InitializedPhoneVerificationHandler(db *gorm.DB) {
+	phoneVerificationHandler = models.NewPhoneVerificationHandler(db)
+	smsProvider = utils.NewSMSProvider()
+}
+
+type PhoneCodeRequest struct {
+	Code string `json:"code" example:"123456"`
+}
+
+type PhoneMessageResponse struct {
+	Message string `json:"message" example:"Verification code sent"`
+}
+
+// @Summary Send Telephone Verification Code
+// @Description Sends a one-time code by SMS to the telephone number of the authenticated user. Codes expire after 10 minutes and can be requested at most once a minute and five times an hour.
+// @Tags user
+// @Produce json
+// @security BearerAuth
+// @Success 200 {object} PhoneMessageResponse "The code has been sent."
+// @Failure 400 {object} ErrorResponse "The user has no telephone number or it is already verified."
+// @Failure 429 {object} ErrorResponse "Too many codes requested."
+// @Router /me/phone/send-code [post]
+func SendPhoneVerificationCode(c *gin.Context) {
+	id, _ := c.Get("id")
+	user, err := userHandler.GetUser(id.(uint))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	if user.Telephone == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Please add a telephone number first"})
+		return
+	}
+
+	if user.HasVerifiedTelephone() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Telephone number is already verified"})
+		return
+	}
+
+	code, err := phoneVerificationHandler.CreateCode(user.ID, user.Telephone)
+	if errors.Is(err, models.ErrPhoneCodeThrottled) {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating verification code"})
+		return
+	}
+
+	message := fmt.Sprintf("Your RedRice verification code is %s. It expires in %d minutes.", code, int(models.PhoneCodeTTL.Minutes()))
+	if err := smsProvider.SendSMS(user.Telephone, message); err != nil {
+		log.Println("Error sending verification code:", err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Error sending verification code"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Verification code sent"})
+}
+
+// @Summary Verify Telephone Number
+// @Description Confirms the telephone number of the authenticated user with the code received by SMS. A code is invalidated after five wrong attempts.
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param code body PhoneCodeRequest true "Verification code"
+// @security BearerAuth
+// @Success 200 {object} models.UserResponse "The user with the verified telephone number."
+// @Failure 400 {object} ErrorResponse "The code is wrong or has expired."
+// @Failure 429 {object} ErrorResponse "Too many wrong attempts."
+// @Router /me/phone/verify [post]
+func VerifyPhone(c *gin.Context) {
+	var request PhoneCodeRequest
+	if err := c.ShouldBindJSON(&request); err != nil || request.Code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	id, _ := c.Get("id")
+	err := phoneVerificationHandler.VerifyCode(id.(uint), request.Code)
+	switch {
+	case errors.Is(err, models.ErrPhoneCodeTooManyAttempts):
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, models.ErrPhoneCodeInvalid), errors.Is(err, models.ErrPhoneCodeExpired):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error verifying telephone number"})
+		return
+	}
+
+	user, err := userHandler.GetUser(id.(uint))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	c.JSON(http.StatusOK, user.Response())
+}
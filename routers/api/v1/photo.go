@@ -0,0 +1,508 @@
+package v1
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"github.com/punchanabu/redrice-backend-go/utils"
+	"golang.org/x/sync/errgroup"
+	"gorm.io/gorm"
+)
+
+// unusedImageInterval is how often the images queued for deletion are
+// removed.
+const unusedImageInterval = time.Hour
+
+var photoHandler *models.PhotoHandler
+
+func InitializedPhotoHandler(db *gorm.DB) {
+	photoHandler = models.NewPhotoHandler(db)
+	utils.RunEveryExclusive(db, unusedImageInterval, "delete unused images", photoHandler.DeleteQueuedImages)
+}
+
+type PhotoPage struct {
+	Data []models.Photo `json:"data"`
+	Pagination
+}
+
+type PhotoApprovalRequest struct {
+	Approved bool `json:"approved" example:"true"`
+}
+
+// @Summary Get Restaurant Photos
+// @Description Retrieves the photo gallery of a restaurant, merging the owner's gallery with approved review photos, newest first. Each photo states its source.
+// @Tags photos
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Param page query int false "Page number, starting at 1"
+// @Param limit query int false "Photos per page, at most 100"
+// @security BearerAuth
+// @Success 200 {object} PhotoPage "One page of photos with pagination metadata."
+// @Failure 400 {object} ErrorResponse "Invalid restaurant ID format."
+// @Failure 404 {object} ErrorResponse "Restaurant not found with the specified ID, or not listed and not managed by the user."
+// @Failure 500 {object} ErrorResponse "Internal server error while fetching photos."
+// @Router /restaurants/{id}/photos [get]
+func GetRestaurantPhotos(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant id"})
+		return
+	}
+
+	if _, ok := findRestaurant(c, uint(idInt)); !ok {
+		return
+	}
+
+	page, limit := parsePagination(c)
+
+	photos, total, err := photoHandler.GetRestaurantPhotos(uint(idInt), limit, (page-1)*limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching photos"})
+		return
+	}
+
+	if photos == nil {
+		photos = []models.Photo{}
+	}
+
+	c.JSON(http.StatusOK, PhotoPage{
+		Data:       photos,
+		Pagination: Pagination{Page: page, Limit: limit, Total: total},
+	})
+}
+
+// uploadImageWithThumbnail uploads the image and its thumbnail, returning
+// their URLs. The thumbnail is left empty when it cannot be made, e.g. for a
+// WebP image, the image is still uploaded.
+func uploadImageWithThumbnail(file io.Reader, fileName string) (string, string, error) {
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return "", "", err
+	}
+	imageURL, err := utils.UploadImageToS3("redrice", bytes.NewReader(content), fileName)
+	if err != nil {
+		return "", "", err
+	}
+	thumbnailURL, err := utils.UploadThumbnail("redrice", content)
+	if err != nil {
+		log.Printf("No thumbnail for %s: %v", fileName, err)
+	}
+	return imageURL, thumbnailURL, nil
+}
+
+// deleteImageObjects queues the uploaded images with the URLs for deletion,
+// the ones another record still shows are kept.
+func deleteImageObjects(urls ...string) {
+	photoHandler.DeleteUnusedImages(urls...)
+}
+
+// maxGalleryUploads bounds the images uploaded in one request.
+const maxGalleryUploads = 10
+
+// galleryUploadConcurrency is how many images of a request are uploaded to S3 at once.
+const galleryUploadConcurrency = 4
+
+// GalleryUploadResult reports the upload of one file, with the created image
+// on success or the error.
+type GalleryUploadResult struct {
+	File  string                  `json:"file" example:"terrace.jpg"`
+	Image *models.RestaurantImage `json:"image,omitempty"`
+	Error string                  `json:"error,omitempty" example:"Error uploading image"`
+}
+
+// @Summary Upload Restaurant Gallery Images
+// @Description Adds up to 10 images at the end of the owner's gallery of a restaurant, in the order sent. The images are uploaded concurrently and the result of every file is reported: 201 when all were added, 207 when some failed to upload, in which case the others were still added. Nothing is added when saving the images fails. The nth caption goes with the nth image. Only the owner of the restaurant or an admin can upload gallery images.
+// @Tags photos
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Param image formData file true "Image files, repeat the field for every image"
+// @Param caption formData string false "Captions, one per image"
+// @security BearerAuth
+// @Success 201 {array} GalleryUploadResult "Every image was added."
+// @Success 207 {array} GalleryUploadResult "Some images failed to upload, the others were added."
+// @Failure 400 {object} ErrorResponse "Invalid restaurant ID, no image or too many images."
+// @Failure 403 {object} ErrorResponse "The user does not manage this restaurant."
+// @Failure 500 {object} ErrorResponse "Internal server error while uploading or saving the images."
+// @Router /restaurants/{id}/images [post]
+func UploadRestaurantImage(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant id"})
+		return
+	}
+	idUint := uint(idInt)
+
+	if !canManageRestaurant(c, idUint) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to manage this restaurant"})
+		return
+	}
+
+	if err := c.Request.ParseMultipartForm(10 << 20); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing image!"})
+		return
+	}
+	headers := c.Request.MultipartForm.File["image"]
+	if len(headers) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing image!"})
+		return
+	}
+	if len(headers) > maxGalleryUploads {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "At most " + strconv.Itoa(maxGalleryUploads) + " images can be uploaded at once"})
+		return
+	}
+	captions := c.Request.MultipartForm.Value["caption"]
+
+	results := make([]GalleryUploadResult, len(headers))
+	urls := make([]string, len(headers))
+	thumbnails := make([]string, len(headers))
+	var uploads errgroup.Group
+	uploads.SetLimit(galleryUploadConcurrency)
+	for i, header := range headers {
+		i, header := i, header
+		results[i].File = header.Filename
+		// A failed file does not stop the others, it is reported in its result
+		uploads.Go(func() error {
+			file, err := header.Open()
+			if err != nil {
+				results[i].Error = "Error reading image"
+				return nil
+			}
+			defer file.Close()
+
+			urls[i], thumbnails[i], err = uploadImageWithThumbnail(file, header.Filename)
+			if err != nil {
+				results[i].Error = "Error uploading image"
+			}
+			return nil
+		})
+	}
+	uploads.Wait()
+
+	id, _ := c.Get("id")
+	var images []models.RestaurantImage
+	for i := range headers {
+		if results[i].Error != "" {
+			continue
+		}
+		image := models.RestaurantImage{ImageURL: urls[i], ThumbnailURL: thumbnails[i], UploadedBy: id.(uint)}
+		if i < len(captions) {
+			image.Caption = captions[i]
+		}
+		images = append(images, image)
+	}
+	if len(images) == 0 {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error uploading image!"})
+		return
+	}
+
+	if err := photoHandler.CreateRestaurantImages(idUint, images); err != nil {
+		// The uploaded objects would never be referenced
+		for _, image := range images {
+			deleteImageObjects(image.ImageURL, image.ThumbnailURL)
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error saving image"})
+		return
+	}
+
+	status := http.StatusCreated
+	saved := 0
+	for i := range results {
+		if results[i].Error != "" {
+			status = http.StatusMultiStatus
+			continue
+		}
+		results[i].Image = &images[saved]
+		saved++
+	}
+	c.JSON(status, results)
+}
+
+// @Summary Upload a Comment Photo
+// @Description Attaches a photo to one of the authenticated user's comments. The photo appears in the restaurant gallery once approved.
+// @Tags photos
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Comment ID" Format(int64)
+// @Param image formData file true "Image file"
+// @security BearerAuth
+// @Success 201 {object} models.CommentPhoto "The uploaded photo, pending approval."
+// @Failure 400 {object} ErrorResponse "Invalid comment ID or missing image."
+// @Failure 403 {object} ErrorResponse "The comment belongs to another user, or the role of the user cannot comment."
+// @Failure 404 {object} ErrorResponse "Comment not found with the specified ID."
+// @Router /comments/{id}/photos [post]
+func UploadCommentPhoto(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment id"})
+		return
+	}
+
+	comment, err := commentHandler.GetComment(uint(idInt))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "comment not found"})
+		return
+	}
+
+	id, _ := c.Get("id")
+	if comment.UserID != id.(uint) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only add photos to your own comments"})
+		return
+	}
+
+	file, header, err := c.Request.FormFile("image")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing image!"})
+		return
+	}
+	defer file.Close()
+
+	imageUrl, thumbnailUrl, err := uploadImageWithThumbnail(file, header.Filename)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error uploading image!"})
+		return
+	}
+
+	photo := models.CommentPhoto{
+		CommentID:    comment.ID,
+		RestaurantID: comment.RestaurantID,
+		UserID:       comment.UserID,
+		ImageURL:     imageUrl,
+		ThumbnailURL: thumbnailUrl,
+	}
+
+	if err := photoHandler.CreateCommentPhoto(&photo); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error saving photo"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, photo)
+}
+
+// @Summary Get Pending Comment Photos
+// @Description Lists the review photos of a restaurant waiting for approval. Only the owner of the restaurant or an admin can see them.
+// @Tags photos
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @security BearerAuth
+// @Success 200 {array} models.CommentPhoto "The photos waiting for approval."
+// @Failure 400 {object} ErrorResponse "Invalid restaurant ID format."
+// @Failure 403 {object} ErrorResponse "The user does not manage this restaurant."
+// @Router /restaurants/{id}/photos/pending [get]
+func GetPendingCommentPhotos(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant id"})
+		return
+	}
+
+	if !canManageRestaurant(c, uint(idInt)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to manage this restaurant"})
+		return
+	}
+
+	photos, err := photoHandler.GetPendingCommentPhotos(uint(idInt))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching photos"})
+		return
+	}
+
+	c.JSON(http.StatusOK, photos)
+}
+
+// @Summary Approve or Reject a Comment Photo
+// @Description Approves a review photo so it shows in the gallery, or hides it again. Only the owner of the restaurant or an admin can moderate photos.
+// @Tags photos
+// @Accept json
+// @Produce json
+// @Param id path int true "Comment photo ID" Format(int64)
+// @Param approval body PhotoApprovalRequest true "Approval decision"
+// @security BearerAuth
+// @Success 200 {object} models.CommentPhoto "The moderated photo."
+// @Failure 400 {object} ErrorResponse "Invalid input format or photo ID."
+// @Failure 403 {object} ErrorResponse "The user does not manage this restaurant."
+// @Failure 404 {object} ErrorResponse "Photo not found with the specified ID."
+// @Router /comment-photos/{id}/approval [put]
+func SetCommentPhotoApproval(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid photo id"})
+		return
+	}
+
+	var request PhotoApprovalRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	photo, err := photoHandler.GetCommentPhoto(uint(idInt))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Photo not found"})
+		return
+	}
+
+	if !canManageRestaurant(c, photo.RestaurantID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to manage this restaurant"})
+		return
+	}
+
+	if err := photoHandler.SetCommentPhotoApproval(photo.ID, request.Approved); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating photo"})
+		return
+	}
+
+	photo.Approved = request.Approved
+	c.JSON(http.StatusOK, photo)
+}
+
+type GalleryOrderRequest struct {
+	// ImageIDs lists every image of the gallery in the new order
+	ImageIDs []uint `json:"imageIds" binding:"required" example:"4,2,7"`
+}
+
+// restaurantImage loads the gallery image of the path for a user managing
+// the restaurant, responding with the error otherwise.
+func restaurantImage(c *gin.Context) (*models.RestaurantImage, bool) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant id"})
+		return nil, false
+	}
+
+	imageID, err := strconv.Atoi(c.Param("imageId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid image id"})
+		return nil, false
+	}
+
+	image, err := photoHandler.GetRestaurantImage(uint(idInt), uint(imageID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Image not found"})
+		return nil, false
+	}
+
+	if !canManageRestaurant(c, image.RestaurantID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to manage this restaurant"})
+		return nil, false
+	}
+	return image, true
+}
+
+func respondWithGallery(c *gin.Context, restaurantID uint) {
+	gallery, err := photoHandler.GetGallery(restaurantID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching gallery"})
+		return
+	}
+	if gallery == nil {
+		gallery = []models.RestaurantImage{}
+	}
+	c.JSON(http.StatusOK, gallery)
+}
+
+// @Summary Reorder a Restaurant Gallery
+// @Description Puts the gallery images of a restaurant in the given order, which must list every image once. Only the owner of the restaurant or an admin can reorder the gallery.
+// @Tags photos
+// @Accept json
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Param order body GalleryOrderRequest true "Image IDs in the new order"
+// @security BearerAuth
+// @Success 200 {array} models.RestaurantImage "The gallery in its new order."
+// @Failure 400 {object} ErrorResponse "Invalid restaurant ID, or the order does not list every image once."
+// @Failure 403 {object} ErrorResponse "The user does not manage this restaurant."
+// @Failure 500 {object} ErrorResponse "Internal server error while reordering the gallery."
+// @Router /restaurants/{id}/images/order [put]
+func ReorderRestaurantImages(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant id"})
+		return
+	}
+	idUint := uint(idInt)
+
+	var request GalleryOrderRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	if !canManageRestaurant(c, idUint) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to manage this restaurant"})
+		return
+	}
+
+	if err := photoHandler.ReorderGallery(idUint, request.ImageIDs); err != nil {
+		if errors.Is(err, models.ErrInvalidImageOrder) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error reordering gallery"})
+		return
+	}
+	respondWithGallery(c, idUint)
+}
+
+// @Summary Set the Cover Image of a Restaurant
+// @Description Makes a gallery image the image of the restaurant, recorded in the restaurant history. Only the owner of the restaurant or an admin can set the cover.
+// @Tags photos
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Param imageId path int true "Image ID" Format(int64)
+// @security BearerAuth
+// @Success 200 {array} models.RestaurantImage "The gallery with the new cover."
+// @Failure 400 {object} ErrorResponse "Invalid restaurant or image ID."
+// @Failure 403 {object} ErrorResponse "The user does not manage this restaurant."
+// @Failure 404 {object} ErrorResponse "Image not found in the gallery of the restaurant."
+// @Failure 500 {object} ErrorResponse "Internal server error while setting the cover."
+// @Router /restaurants/{id}/images/{imageId}/cover [put]
+func SetRestaurantCoverImage(c *gin.Context) {
+	image, ok := restaurantImage(c)
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("id")
+	if err := photoHandler.SetCoverImage(image, userID.(uint)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error setting cover image"})
+		return
+	}
+	respondWithGallery(c, image.RestaurantID)
+}
+
+// @Summary Delete a Restaurant Gallery Image
+// @Description Removes an image from the gallery of a restaurant and from storage. When it was the cover, the next image of the gallery becomes the cover. Only the owner of the restaurant or an admin can delete gallery images.
+// @Tags photos
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Param imageId path int true "Image ID" Format(int64)
+// @security BearerAuth
+// @Success 204 "Image deleted."
+// @Failure 400 {object} ErrorResponse "Invalid restaurant or image ID."
+// @Failure 403 {object} ErrorResponse "The user does not manage this restaurant."
+// @Failure 404 {object} ErrorResponse "Image not found in the gallery of the restaurant."
+// @Failure 500 {object} ErrorResponse "Internal server error while deleting the image."
+// @Router /restaurants/{id}/images/{imageId} [delete]
+func DeleteRestaurantImage(c *gin.Context) {
+	image, ok := restaurantImage(c)
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("id")
+	if err := photoHandler.DeleteRestaurantImage(image, userID.(uint)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error deleting image"})
+		return
+	}
+
+	// The image is not referenced anymore
+	deleteImageObjects(image.ImageURL, image.ThumbnailURL)
+	c.Status(http.StatusNoContent)
+}
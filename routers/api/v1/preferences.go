@@ -0,0 +1,105 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"gorm.io/gorm"
+)
+
+var preferencesHandler *models.PreferencesHandler
+
+func InitializedPreferencesHandler(db *gorm.DB) {
+	preferencesHandler = models.NewPreferencesHandler(db)
+}
+
+type PreferencesRequest struct {
+	Language            string   `json:"language" example:"th" enums:"th,en"`
+	DietaryRestrictions []string `json:"dietaryRestrictions" example:"vegetarian,nut_allergy"`
+	DefaultPartySize    int      `json:"defaultPartySize" example:"2"`
+	HideContact         bool     `json:"hideContact" example:"false"`
+	ReviewAnonymously   bool     `json:"reviewAnonymously" example:"false"`
+	EmailDelivery       string   `json:"emailDelivery" example:"immediate" enums:"immediate,daily_digest"`
+	SMSDelivery         string   `json:"smsDelivery" example:"daily_digest" enums:"immediate,daily_digest"`
+}
+
+// @Summary Get my preferences
+// @Description Retrieves the preferred language, dietary restrictions and default party size of the currently authenticated user, used to prefill forms, their privacy settings and how notifications are delivered. Users who never saved preferences get the defaults.
+// @Tags user
+// @Produce json
+// @security BearerAuth
+// @Success 200 {object} models.UserPreferences "The preferences of the user."
+// @Failure 401 {object} ErrorResponse "Unauthorized."
+// @Failure 500 {object} ErrorResponse "Internal server error while fetching the preferences."
+// @Router /me/preferences [get]
+func GetMyPreferences(c *gin.Context) {
+	id, _ := c.Get("id")
+	preferences, err := preferencesHandler.GetPreferences(id.(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching preferences"})
+		return
+	}
+	c.JSON(http.StatusOK, preferences)
+}
+
+// @Summary Update my preferences
+// @Description Replaces the preferences of the currently authenticated user. A missing language or party size falls back to the default. Languages are th and en, dietary restrictions are vegetarian, vegan, halal, kosher, gluten_free, lactose_free, nut_allergy, seafood_allergy, no_pork and no_beef, and party sizes go from 1 to 20. With hideContact the email and telephone of the user are not shown in the customer lists of restaurants, with reviewAnonymously new reviews are published anonymously unless they say otherwise. emailDelivery and smsDelivery are immediate or daily_digest, which gathers the notifications of the channel into one message a day, missing ones fall back to immediate.
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param preferences body PreferencesRequest true "Preferences"
+// @security BearerAuth
+// @Success 200 {object} models.UserPreferences "The updated preferences."
+// @Failure 400 {object} ErrorResponse "Invalid input format or unsupported value."
+// @Failure 401 {object} ErrorResponse "Unauthorized."
+// @Failure 500 {object} ErrorResponse "Internal server error while saving the preferences."
+// @Router /me/preferences [put]
+func UpdateMyPreferences(c *gin.Context) {
+	var request PreferencesRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	id, _ := c.Get("id")
+	preferences := models.DefaultUserPreferences(id.(uint))
+	if request.Language != "" {
+		preferences.Language = request.Language
+	}
+	if request.DefaultPartySize != 0 {
+		preferences.DefaultPartySize = request.DefaultPartySize
+	}
+	preferences.HideContact = request.HideContact
+	preferences.ReviewAnonymously = request.ReviewAnonymously
+	if request.EmailDelivery != "" {
+		preferences.EmailDelivery = request.EmailDelivery
+	}
+	if request.SMSDelivery != "" {
+		preferences.SMSDelivery = request.SMSDelivery
+	}
+	seen := map[string]bool{}
+	for _, restriction := range request.DietaryRestrictions {
+		if !seen[restriction] {
+			seen[restriction] = true
+			preferences.DietaryRestrictions = append(preferences.DietaryRestrictions, restriction)
+		}
+	}
+
+	if err := preferencesHandler.SavePreferences(preferences); err != nil {
+		if errors.Is(err, models.ErrInvalidPreferences) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error saving preferences"})
+		return
+	}
+
+	saved, err := preferencesHandler.GetPreferences(id.(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching preferences"})
+		return
+	}
+	c.JSON(http.StatusOK, saved)
+}
@@ -0,0 +1,87 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"gorm.io/gorm"
+)
+
+// defaultLowQualityScore is the highest score listed as low quality when
+// admins do not choose one.
+const defaultLowQualityScore = 60
+
+type RestaurantQualityPage struct {
+	Data []models.RestaurantQuality `json:"data"`
+	Pagination
+}
+
+// @Summary Get Restaurant Quality
+// @Description Scores how complete the listing of a restaurant is, from 0 to 100: gallery photos, opening hours, location, menu and a description of at least 100 characters. Every failed check comes with a hint on how to fix it. Only the owner of the restaurant or an admin can see it.
+// @Tags restaurants
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @security BearerAuth
+// @Success 200 {object} models.RestaurantQuality "The score of the listing and its checks."
+// @Failure 400 {object} ErrorResponse "Invalid restaurant ID format."
+// @Failure 403 {object} ErrorResponse "The user does not manage the restaurant."
+// @Failure 404 {object} ErrorResponse "Restaurant not found with the specified ID."
+// @Failure 500 {object} ErrorResponse "Internal server error while scoring the restaurant."
+// @Router /restaurants/{id}/quality [get]
+func GetRestaurantQuality(c *gin.Context) {
+	restaurantID, ok := managedRestaurantID(c)
+	if !ok {
+		return
+	}
+
+	quality, err := RestaurantHandler.GetQuality(restaurantID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error scoring restaurant"})
+		return
+	}
+
+	c.JSON(http.StatusOK, quality)
+}
+
+// @Summary Get Low Quality Restaurants
+// @Description Lists the restaurants whose listing scores at most maxScore, lowest first, with the checks they fail.
+// @Tags admin
+// @Produce json
+// @Param maxScore query int false "Highest score listed, from 0 to 100, 60 by default"
+// @Param page query int false "Page number, starting at 1"
+// @Param limit query int false "Restaurants per page, at most 100"
+// @security BearerAuth
+// @Success 200 {object} RestaurantQualityPage "One page of restaurants with their score."
+// @Failure 400 {object} ErrorResponse "Invalid maxScore."
+// @Failure 500 {object} ErrorResponse "Internal server error while scoring the restaurants."
+// @Router /admin/restaurants/quality [get]
+func GetLowQualityRestaurants(c *gin.Context) {
+	maxScore := defaultLowQualityScore
+	if value := c.Query("maxScore"); value != "" {
+		var err error
+		maxScore, err = strconv.Atoi(value)
+		if err != nil || maxScore < 0 || maxScore > 100 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid maxScore, expected a number from 0 to 100"})
+			return
+		}
+	}
+
+	page, limit := parsePagination(c)
+	restaurants, total, err := RestaurantHandler.GetLowQualityRestaurants(maxScore, limit, (page-1)*limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error scoring restaurants"})
+		return
+	}
+
+	c.JSON(http.StatusOK, RestaurantQualityPage{
+		Data:       restaurants,
+		Pagination: Pagination{Page: page, Limit: limit, Total: total},
+	})
+}
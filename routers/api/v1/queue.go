@@ -0,0 +1,250 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"gorm.io/gorm"
+)
+
+var queueHandler *models.QueueHandler
+
+func InitializedQueueHandler(db *gorm.DB) {
+	queueHandler = models.NewQueueHandler(db)
+}
+
+type JoinQueueRequest struct {
+	PartySize int `json:"partySize" example:"2"`
+}
+
+type QueueEntryResponse struct {
+	Entry    *models.QueueEntry   `json:"entry"`
+	Estimate *models.WaitEstimate `json:"estimate"`
+}
+
+// @Summary Get Estimated Wait Time
+// @Description Estimates how long a party joining the walk-in queue now would wait, based on the live queue and historical seating durations.
+// @Tags queue
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @security BearerAuth
+// @Success 200 {object} models.WaitEstimate "The current wait estimate of the restaurant."
+// @Failure 400 {object} ErrorResponse "Invalid restaurant ID format."
+// @Failure 500 {object} ErrorResponse "Internal server error while computing the estimate."
+// @Router /restaurants/{id}/wait [get]
+func GetRestaurantWait(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant id"})
+		return
+	}
+
+	estimate, err := queueHandler.EstimateWait(uint(idInt), 0)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error estimating wait time"})
+		return
+	}
+
+	c.JSON(http.StatusOK, estimate)
+}
+
+// @Summary Get Restaurant Queue
+// @Description Lists the parties currently waiting at a restaurant in the order they will be seated. Only the owner of the restaurant or an admin can see the queue.
+// @Tags queue
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @security BearerAuth
+// @Success 200 {array} models.QueueEntry "The waiting parties."
+// @Failure 400 {object} ErrorResponse "Invalid restaurant ID format."
+// @Failure 403 {object} ErrorResponse "The user does not manage this restaurant."
+// @Router /restaurants/{id}/queue [get]
+func GetRestaurantQueue(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant id"})
+		return
+	}
+
+	if !canManageRestaurant(c, uint(idInt)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to manage this restaurant"})
+		return
+	}
+
+	entries, err := queueHandler.GetWaitingEntries(uint(idInt))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching queue"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// @Summary Join Restaurant Queue
+// @Description Adds the authenticated user's party to the walk-in queue of a restaurant and returns its position and estimated wait.
+// @Tags queue
+// @Accept json
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Param party body JoinQueueRequest true "Party details"
+// @security BearerAuth
+// @Success 201 {object} QueueEntryResponse "The queue entry with its wait estimate."
+// @Failure 400 {object} ErrorResponse "Invalid input format or restaurant ID."
+// @Failure 404 {object} ErrorResponse "Restaurant not found with the specified ID."
+// @Router /restaurants/{id}/queue [post]
+func JoinRestaurantQueue(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant id"})
+		return
+	}
+
+	var request JoinQueueRequest
+	if err := c.ShouldBindJSON(&request); err != nil || request.PartySize < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format, partySize must be at least 1"})
+		return
+	}
+
+	if _, err := RestaurantHandler.GetRestaurant(uint(idInt)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+		return
+	}
+
+	id, _ := c.Get("id")
+	entry := models.QueueEntry{
+		RestaurantID: uint(idInt),
+		UserID:       id.(uint),
+		PartySize:    request.PartySize,
+	}
+
+	if err := queueHandler.JoinQueue(&entry); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error joining queue"})
+		return
+	}
+
+	publishEvent(EventQueueUpdated, entry.RestaurantID, gin.H{"entryId": entry.ID, "status": entry.Status})
+
+	estimate, err := queueHandler.EstimateWait(entry.RestaurantID, entry.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error estimating wait time"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, QueueEntryResponse{Entry: &entry, Estimate: estimate})
+}
+
+// @Summary Get a Queue Entry
+// @Description Retrieves a queue entry together with its up to date position and estimated wait.
+// @Tags queue
+// @Produce json
+// @Param id path int true "Queue entry ID" Format(int64)
+// @security BearerAuth
+// @Success 200 {object} QueueEntryResponse "The queue entry with its wait estimate."
+// @Failure 400 {object} ErrorResponse "Invalid queue entry ID format."
+// @Failure 404 {object} ErrorResponse "Queue entry not found with the specified ID."
+// @Router /queue/{id} [get]
+func GetQueueEntry(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid queue entry id"})
+		return
+	}
+
+	entry, err := queueHandler.GetQueueEntry(uint(idInt))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Queue entry not found"})
+		return
+	}
+
+	response := QueueEntryResponse{Entry: entry}
+	if entry.Status == models.QueueStatusWaiting {
+		response.Estimate, err = queueHandler.EstimateWait(entry.RestaurantID, entry.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error estimating wait time"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Seat a Queued Party
+// @Description Marks a waiting party as seated, which moves everyone behind it forward. Only the owner of the restaurant or an admin can seat parties.
+// @Tags queue
+// @Produce json
+// @Param id path int true "Queue entry ID" Format(int64)
+// @security BearerAuth
+// @Success 200 {object} models.QueueEntry "The seated queue entry."
+// @Failure 400 {object} ErrorResponse "Invalid queue entry ID format or the party is no longer waiting."
+// @Failure 403 {object} ErrorResponse "The user does not manage this restaurant."
+// @Failure 404 {object} ErrorResponse "Queue entry not found with the specified ID."
+// @Router /queue/{id}/seat [put]
+func SeatQueueEntry(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid queue entry id"})
+		return
+	}
+
+	entry, err := queueHandler.GetQueueEntry(uint(idInt))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Queue entry not found"})
+		return
+	}
+
+	if !canManageRestaurant(c, entry.RestaurantID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to manage this restaurant"})
+		return
+	}
+
+	seated, err := queueHandler.SeatEntry(entry.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Party is no longer waiting"})
+		return
+	}
+
+	publishEvent(EventQueueUpdated, seated.RestaurantID, gin.H{"entryId": seated.ID, "status": seated.Status})
+
+	c.JSON(http.StatusOK, seated)
+}
+
+// @Summary Leave a Queue
+// @Description Removes a waiting party from the queue. The party itself, the owner of the restaurant or an admin can do this.
+// @Tags queue
+// @Produce json
+// @Param id path int true "Queue entry ID" Format(int64)
+// @security BearerAuth
+// @Success 200 "The party left the queue."
+// @Failure 400 {object} ErrorResponse "Invalid queue entry ID format or the party is no longer waiting."
+// @Failure 403 {object} ErrorResponse "The user cannot remove this queue entry."
+// @Failure 404 {object} ErrorResponse "Queue entry not found with the specified ID."
+// @Router /queue/{id} [delete]
+func LeaveQueue(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid queue entry id"})
+		return
+	}
+
+	entry, err := queueHandler.GetQueueEntry(uint(idInt))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Queue entry not found"})
+		return
+	}
+
+	id, _ := c.Get("id")
+	if entry.UserID != id.(uint) && !canManageRestaurant(c, entry.RestaurantID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to remove this queue entry"})
+		return
+	}
+
+	if err := queueHandler.LeaveQueue(entry.ID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Party is no longer waiting"})
+		return
+	}
+
+	publishEvent(EventQueueUpdated, entry.RestaurantID, gin.H{"entryId": entry.ID, "status": models.QueueStatusLeft})
+
+	c.JSON(http.StatusOK, gin.H{"message": "Left the queue successfully"})
+}
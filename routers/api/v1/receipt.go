@@ -0,0 +1,138 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"github.com/punchanabu/redrice-backend-go/utils"
+)
+
+const receiptBucket = "redrice"
+
+type ReceiptMessageResponse struct {
+	Message string `json:"message" example:"Receipt sent to john@example.com"`
+}
+
+// issueReceipt renders the receipt of a paid reservation and stores it in S3.
+// Issuing it again overwrites the previous copy.
+func issueReceipt(reservation *models.Reservation) error {
+	issuedAt := time.Now()
+	lines, err := models.RenderReceipt(reservation, issuedAt)
+	if err != nil {
+		return err
+	}
+
+	pdf := utils.RenderTextPDF("RedRice Receipt", lines)
+	if err := utils.UploadFileToS3(receiptBucket, models.ReceiptKey(reservation.ID), pdf, "application/pdf"); err != nil {
+		return err
+	}
+
+	return reservationHandler.MarkReceiptIssued(reservation, issuedAt)
+}
+
+// sendReceipt emails the guest a link to the receipt of the reservation.
+func sendReceipt(reservation *models.Reservation) error {
+	link, err := utils.PresignedURL(receiptBucket, models.ReceiptKey(reservation.ID), 7*24*time.Hour)
+	if err != nil {
+		return err
+	}
+
+	return emailTemplateHandler.Send(reservation.User.ContactAddress(), models.EmailTemplateReceipt, map[string]string{
+		"name":          reservation.User.Name,
+		"restaurant":    reservation.Restaurant.Name,
+		"receiptNumber": models.ReceiptNumber(reservation.ID),
+		"date":          reservation.DateTime.In(reservation.Restaurant.Location()).Format("2 Jan 2006 15:04"),
+		"link":          link,
+	})
+}
+
+// loadReceiptReservation returns the reservation if the user is its guest or
+// manages its restaurant, and writes the error response otherwise.
+func loadReceiptReservation(c *gin.Context) (*models.Reservation, bool) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reservation id"})
+		return nil, false
+	}
+
+	reservation, err := reservationHandler.GetReservation(uint(idInt))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Reservation not found"})
+		return nil, false
+	}
+
+	id, _ := c.Get("id")
+	if reservation.UserID != id.(uint) && !canManageRestaurant(c, reservation.RestaurantID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to see this receipt"})
+		return nil, false
+	}
+
+	if !reservation.IsPaid() {
+		c.JSON(http.StatusNotFound, gin.H{"error": "This reservation has no paid deposit"})
+		return nil, false
+	}
+
+	if reservation.ReceiptIssuedAt == nil {
+		if err := issueReceipt(reservation); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating receipt"})
+			return nil, false
+		}
+	}
+
+	return reservation, true
+}
+
+// @Summary Get Reservation Receipt
+// @Description Redirects to the PDF receipt of a paid reservation. The receipt is generated if it was not issued yet. Only the guest or the staff of the restaurant can get it.
+// @Tags reservations
+// @Produce json
+// @Param id path int true "Reservation ID" Format(int64)
+// @security BearerAuth
+// @Success 302 "Redirect to the PDF receipt."
+// @Failure 400 {object} ErrorResponse "Invalid reservation ID."
+// @Failure 403 {object} ErrorResponse "The user cannot see this receipt."
+// @Failure 404 {object} ErrorResponse "Reservation not found or without a paid deposit."
+// @Router /reservations/{id}/receipt [get]
+func GetReservationReceipt(c *gin.Context) {
+	reservation, ok := loadReceiptReservation(c)
+	if !ok {
+		return
+	}
+
+	link, err := utils.PresignedURL(receiptBucket, models.ReceiptKey(reservation.ID), 15*time.Minute)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error generating receipt link"})
+		return
+	}
+
+	c.Redirect(http.StatusFound, link)
+}
+
+// @Summary Resend Reservation Receipt
+// @Description Emails the guest a link to the PDF receipt of a paid reservation again. Only the guest or the staff of the restaurant can resend it.
+// @Tags reservations
+// @Produce json
+// @Param id path int true "Reservation ID" Format(int64)
+// @security BearerAuth
+// @Success 200 {object} ReceiptMessageResponse "The receipt was sent."
+// @Failure 400 {object} ErrorResponse "Invalid reservation ID."
+// @Failure 403 {object} ErrorResponse "The user cannot see this receipt."
+// @Failure 404 {object} ErrorResponse "Reservation not found or without a paid deposit."
+// @Failure 500 {object} ErrorResponse "The receipt could not be sent."
+// @Router /reservations/{id}/receipt/send [post]
+func SendReservationReceipt(c *gin.Context) {
+	reservation, ok := loadReceiptReservation(c)
+	if !ok {
+		return
+	}
+
+	if err := sendReceipt(reservation); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error sending receipt"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Receipt sent to " + reservation.User.ContactAddress()})
+}
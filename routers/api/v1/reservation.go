@@ -1,31 +1,79 @@
 package v1
 
 import (
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/punchanabu/redrice-backend-go/middleware"
 	"github.com/punchanabu/redrice-backend-go/models"
+	"github.com/punchanabu/redrice-backend-go/utils"
 	"gorm.io/gorm"
 )
 
+// maxReservationsPerUser is how many reservations a customer can hold.
+const maxReservationsPerUser = 3
+
 var reservationHandler *models.ReservationHandler
 
 func InitializedReservationHandler(db *gorm.DB) {
 	reservationHandler = models.NewReservationHandler(db)
 }
 
+// respondBookingError writes a booking policy violation together with its
+// error code so clients can show a meaningful message.
+func respondBookingError(c *gin.Context, err error) {
+	var bookingErr *models.BookingError
+	if errors.As(err, &bookingErr) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": bookingErr.Message, "code": bookingErr.Code})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking reservation time"})
+}
+
+// checkBlackouts rejects reservations overlapping a blackout of the restaurant.
+// Reservations without a valid exit time are assumed to last one slot.
+func checkBlackouts(restaurantID uint, start, end time.Time) error {
+	if !end.After(start) {
+		end = start.Add(models.SlotLength)
+	}
+
+	blackouts, err := blackoutHandler.GetBlackoutsByRestaurantID(restaurantID, start, end)
+	if err != nil {
+		return err
+	}
+
+	return models.CheckBlackouts(blackouts, start, end)
+}
+
+// checkSpecialHours rejects reservations on a day the restaurant declared
+// closed or outside the special hours of the day.
+func checkSpecialHours(restaurant *models.Restaurant, at time.Time) error {
+	special, err := specialHoursHandler.GetSpecialHoursOn(restaurant, at)
+	if err != nil {
+		return err
+	}
+
+	return restaurant.CheckSpecialHours(special, at)
+}
+
 // @Summary Get a Single Reservation
-// @Description Retrieves details of a single reservation by its unique identifier.
+// @Description Retrieves details of a single reservation by its unique identifier. The email and telephone of a guest who hides their contact are left out for everyone but the guest.
 // @Tags reservations
 // @Produce json
 // @Param id path int true "Reservation ID" Format(int64)
+// @Param currency query string false "Currency to also show the prices in, e.g. USD" example(USD)
+// @Param Accept-Language header string false "Language to also write dates and amounts in, th or en, optionally with a calendar such as en-u-ca-buddhist" example(th-TH)
 // @security BearerAuth
 // @Success 200 {object} models.Reservation "The details of the reservation including ID, DateTime, UserID, User, RestaurantID, and Restaurant."
 // @Failure 400 {object} ErrorResponse "Invalid reservation ID format."
 // @Failure 404 {object} ErrorResponse "Reservation not found with the specified ID."
+// @Failure 500 {object} ErrorResponse "Internal server error while fetching the reservation."
 // @Router /reservations/{id} [get]
 func GetReservation(c *gin.Context) {
 	idString := c.Param("id")
@@ -36,6 +84,11 @@ func GetReservation(c *gin.Context) {
 		return
 	}
 
+	currency, ok := displayCurrency(c)
+	if !ok {
+		return
+	}
+
 	idUint := uint(idInt)
 	reservation, err := reservationHandler.GetReservation(idUint)
 	if err != nil {
@@ -43,11 +96,23 @@ func GetReservation(c *gin.Context) {
 		return
 	}
 
+	id, _ := c.Get("id")
+	reservations := []models.Reservation{*reservation}
+	if err := reservationHandler.HideGuestContacts(reservations, id.(uint)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching reservation"})
+		return
+	}
+	reservation = &reservations[0]
+
+	exchangeRates.DisplayReservation(reservation, currency)
+	if locale, ok := requestLocale(c); ok {
+		locale.LocalizeReservation(reservation)
+	}
 	c.JSON(http.StatusOK, reservation)
 }
 
 // @Summary Get All Reservations
-// @Description Retrieves a list of all reservations in the system.
+// @Description Retrieves a list of all reservations in the system. The email and telephone of guests who hide their contact are left out, except on the reservations of the user making the request.
 // @Tags reservations
 // @Produce json
 // @security BearerAuth
@@ -60,18 +125,31 @@ func GetReservations(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching reservations!"})
 		return
 	}
+	id, _ := c.Get("id")
+	if err := reservationHandler.HideGuestContacts(reservations, id.(uint)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching reservations!"})
+		return
+	}
 	c.JSON(http.StatusOK, reservations)
 }
 
+// isDryRun reports whether the client only wants the request validated.
+func isDryRun(c *gin.Context) bool {
+	return c.Query("dry_run") == "true"
+}
+
 // @Summary Create a New Reservation
-// @Description Adds a new reservation to the system with the provided details. This endpoint requires authentication.
+// @Description Adds a new reservation to the system with the provided details. This endpoint requires authentication. With dry_run=true the booking policy, blackouts and limits are checked the same way but nothing is saved.
 // @Tags reservations
 // @Accept json
 // @Produce json
 // @Param reservation body models.Reservation true "Reservation Details"
+// @Param dry_run query bool false "Only validate the reservation"
 // @security BearerAuth
+// @Success 200 {object} models.Reservation "Dry run only: the reservation would be accepted, it has no ID."
 // @Success 201 {object} models.Reservation "The created reservation's details, including its unique identifier."
-// @Failure 400 {object} ErrorResponse "Invalid input format for reservation details."
+// @Failure 400 {object} ErrorResponse "Invalid input format or a reservation time outside the restaurant's booking window (see code)."
+// @Failure 403 {object} ErrorResponse "The role of the user cannot book."
 // @Failure 500 {object} ErrorResponse "Internal server error while creating the reservation."
 // @Router /reservations [post]
 func CreateReservation(c *gin.Context) {
@@ -103,28 +181,103 @@ func CreateReservation(c *gin.Context) {
 		return
 	}
 
-	OwnReservations, err := reservationHandler.GetReservationsByUserID(uint(uid)) // Correctly cast to uint now
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching reservations for user"})
+	dryRun := isDryRun(c)
+	if !placeReservation(c, uid, claims.Role, &reservation, dryRun) {
 		return
 	}
 
-	if len(OwnReservations) == 3 && claims.Role != "admin" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "User already has 3 reservations. Cannot create more."})
+	if dryRun {
+		c.JSON(http.StatusOK, reservation)
 		return
 	}
+	c.JSON(http.StatusCreated, reservation)
+}
+
+// placeReservation books the reservation for the user after checking the
+// booking policy of the restaurant and the limits of the role. It writes the
+// error response and returns false when the reservation is refused. A dry run
+// stops after the checks and fills the reservation without saving it.
+func placeReservation(c *gin.Context, uid uint, role string, reservation *models.Reservation, dryRun bool) bool {
+	restaurant, err := RestaurantHandler.GetRestaurant(reservation.RestaurantID)
+	if err != nil || !restaurant.IsListed() {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+		return false
+	}
 
-	err = reservationHandler.CreateReservation(uid, &reservation)
+	if err := restaurant.CheckBookingWindow(reservation.DateTime, time.Now()); err != nil {
+		respondBookingError(c, err)
+		return false
+	}
+
+	if err := checkBlackouts(restaurant.ID, reservation.DateTime, reservation.ExitTime); err != nil {
+		respondBookingError(c, err)
+		return false
+	}
+
+	if err := checkSpecialHours(restaurant, reservation.DateTime); err != nil {
+		respondBookingError(c, err)
+		return false
+	}
+
+	if restaurant.RequireVerifiedPhone && !models.HasPermission(role, models.PermissionBypassBookingLimits) {
+		user, err := userHandler.GetUser(uid)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return false
+		}
+		if !user.HasVerifiedTelephone() {
+			respondBookingError(c, &models.BookingError{Code: models.ErrCodePhoneNotVerified, Message: "This restaurant requires a verified telephone number to book"})
+			return false
+		}
+	}
+
+	OwnReservations, err := reservationHandler.GetReservationsByUserID(uid)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating reservation"})
-		return
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching reservations for user"})
+		return false
 	}
 
-	c.JSON(http.StatusCreated, reservation)
+	if len(OwnReservations) >= maxReservationsPerUser && !models.HasPermission(role, models.PermissionBypassBookingLimits) {
+		c.JSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("User already has %d reservations. Cannot create more.", maxReservationsPerUser)})
+		return false
+	}
+
+	// The deposit is taken from the policy and the deposit rules at booking time, not from the client
+	deposit, rule, err := depositRuleHandler.DepositAt(restaurant, reservation.DateTime)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error pricing the deposit"})
+		return false
+	}
+	breakdown, err := restaurant.DepositBreakdown(deposit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error pricing the deposit"})
+		return false
+	}
+	reservation.DepositBreakdown = &breakdown
+	reservation.Deposit = breakdown.Total
+	reservation.DepositRuleID = nil
+	if rule != nil {
+		reservation.DepositRuleID = &rule.ID
+	}
+
+	if dryRun {
+		reservation.UserID = uid
+		reservation.Status = models.ReservationStatusPending
+		reservation.Restaurant = *restaurant
+		reservation.Local = models.NewLocalTimes(restaurant, reservation.DateTime, reservation.ExitTime)
+		return true
+	}
+
+	if err := reservationHandler.CreateReservation(uid, reservation); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating reservation"})
+		return false
+	}
+	middleware.RecordActivity(c, uid, models.ActivityReservationCreated, map[string]interface{}{"reservationId": reservation.ID, "restaurantId": reservation.RestaurantID})
+	return true
 }
 
 // @Summary Update a Reservation
-// @Description Updates the details of an existing reservation identified by its ID. This endpoint requires authentication.
+// @Description Updates the details of an existing reservation identified by its ID. Only the user who made the reservation, the owner of the restaurant or an admin can update it, and the user of the reservation cannot be changed.
 // @Tags reservations
 // @Accept json
 // @Produce json
@@ -133,7 +286,8 @@ func CreateReservation(c *gin.Context) {
 // @security BearerAuth
 // @Success 200 {object} models.Reservation "The updated reservation's details."
 // @Failure 400 {object} ErrorResponse "Invalid input format for reservation details or invalid reservation ID."
-// @Failure 404 {object} ErrorResponse "Reservation not found with the specified ID."
+// @Failure 403 {object} ErrorResponse "The role of the user cannot book, or the user neither made the reservation nor manages its restaurant."
+// @Failure 404 {object} ErrorResponse "Reservation not found with the specified ID, or the restaurant to move it to is not found or not listed."
 // @Router /reservations/{id} [put]
 func UpdateReservation(c *gin.Context) {
 	var reservation models.Reservation
@@ -152,6 +306,68 @@ func UpdateReservation(c *gin.Context) {
 
 	idUint := uint(idInt)
 
+	existing, err := reservationHandler.GetReservation(idUint)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Reservation not found"})
+		return
+	}
+
+	id, _ := c.Get("id")
+	if existing.UserID != id.(uint) && !canManageRestaurant(c, existing.RestaurantID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to change this reservation"})
+		return
+	}
+
+	// The guest of a booking cannot be changed, status changes go through the
+	// status endpoint and the deposit is fixed at booking
+	reservation.UserID = 0
+	reservation.Status = ""
+	reservation.Deposit = models.Money{}
+	reservation.DepositBreakdown = nil
+	reservation.DepositRuleID = nil
+	reservation.ReceiptIssuedAt = nil
+
+	// Bookings can only be moved to restaurants taking reservations
+	if reservation.RestaurantID != 0 {
+		restaurant, err := RestaurantHandler.GetRestaurant(reservation.RestaurantID)
+		if err != nil || !restaurant.IsListed() {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+			return
+		}
+	}
+
+	if !reservation.DateTime.IsZero() {
+		restaurantID := existing.RestaurantID
+		if reservation.RestaurantID != 0 {
+			restaurantID = reservation.RestaurantID
+		}
+
+		restaurant, err := RestaurantHandler.GetRestaurant(restaurantID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+			return
+		}
+
+		if err := restaurant.CheckBookingWindow(reservation.DateTime, time.Now()); err != nil {
+			respondBookingError(c, err)
+			return
+		}
+
+		exitTime := reservation.ExitTime
+		if exitTime.IsZero() {
+			exitTime = existing.ExitTime
+		}
+		if err := checkBlackouts(restaurant.ID, reservation.DateTime, exitTime); err != nil {
+			respondBookingError(c, err)
+			return
+		}
+
+		if err := checkSpecialHours(restaurant, reservation.DateTime); err != nil {
+			respondBookingError(c, err)
+			return
+		}
+	}
+
 	err = reservationHandler.UpdateReservation(idUint, &reservation)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating reservation"})
@@ -162,13 +378,14 @@ func UpdateReservation(c *gin.Context) {
 }
 
 // @Summary Delete a Reservation
-// @Description Removes a reservation from the system by its unique identifier. This endpoint requires authentication.
+// @Description Removes a reservation from the system by its unique identifier. Only the user who made the reservation, the owner of the restaurant or an admin can delete it.
 // @Tags reservations
 // @Produce json
 // @Param id path int true "Reservation ID" Format(int64)
 // @security BearerAuth
 // @Success 204 "Reservation successfully deleted, no content to return."
 // @Failure 400 {object} ErrorResponse "Invalid reservation ID format."
+// @Failure 403 {object} ErrorResponse "The user neither made the reservation nor manages its restaurant."
 // @Failure 404 {object} ErrorResponse "Reservation not found with the specified ID."
 // @Router /reservations/{id} [delete]
 func DeleteReservation(c *gin.Context) {
@@ -182,6 +399,18 @@ func DeleteReservation(c *gin.Context) {
 
 	idUint := uint(idInt)
 
+	reservation, err := reservationHandler.GetReservation(idUint)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Reservation not found"})
+		return
+	}
+
+	id, _ := c.Get("id")
+	if reservation.UserID != id.(uint) && !canManageRestaurant(c, reservation.RestaurantID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to delete this reservation"})
+		return
+	}
+
 	err = reservationHandler.DeleteReservation(idUint)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error deleting reservation"})
@@ -193,7 +422,7 @@ func DeleteReservation(c *gin.Context) {
 
 // GetUserReservations retrieves all reservations for a given user ID.
 // @Summary Get User's Reservations
-// @Description Retrieves a list of reservations associated with a specific user.
+// @Description Retrieves a list of reservations associated with a specific user. The email and telephone of a user who hides their contact are left out for everyone but the user.
 // @Tags reservations
 // @Produce json
 // @Param userId path int true "User ID"
@@ -201,6 +430,7 @@ func DeleteReservation(c *gin.Context) {
 // @Success 200 {array} models.Reservation "An array of reservation objects for the user."
 // @Failure 400 {object} ErrorResponse "Invalid user ID format."
 // @Failure 404 {object} ErrorResponse "Reservations not found for the specified user ID."
+// @Failure 500 {object} ErrorResponse "Internal server error while fetching the reservations."
 // @Router /users/{userId}/reservations [get]
 func GetUserReservations(c *gin.Context) {
 	userID := c.Param("id")
@@ -220,6 +450,251 @@ func GetUserReservations(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching reservations for user"})
 		return
 	}
+	id, _ := c.Get("id")
+	if err := reservationHandler.HideGuestContacts(reservations, id.(uint)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching reservations for user"})
+		return
+	}
+
+	c.JSON(http.StatusOK, reservations)
+}
+
+// @Summary Get my reservations
+// @Description Lists the reservations of the currently authenticated user with their restaurant, latest first. The days of from and to are inclusive.
+// @Tags reservations
+// @Produce json
+// @Param status query string false "Comma separated statuses to keep, e.g. pending,confirmed"
+// @Param from query string false "First day in YYYY-MM-DD format"
+// @Param to query string false "Last day in YYYY-MM-DD format"
+// @Param currency query string false "Currency to also show the prices in, e.g. USD" example(USD)
+// @Param Accept-Language header string false "Language to also write dates and amounts in, th or en, optionally with a calendar such as en-u-ca-buddhist" example(th-TH)
+// @security BearerAuth
+// @Success 200 {array} models.Reservation "The reservations of the user."
+// @Failure 400 {object} ErrorResponse "Unknown status or invalid date."
+// @Failure 500 {object} ErrorResponse "Internal server error while fetching the reservations."
+// @Router /me/reservations [get]
+func GetMyReservations(c *gin.Context) {
+	currency, ok := displayCurrency(c)
+	if !ok {
+		return
+	}
+
+	var filter models.ReservationFilter
+	if value := c.Query("status"); value != "" {
+		for _, status := range strings.Split(value, ",") {
+			status = strings.TrimSpace(status)
+			if !models.IsValidReservationStatus(status) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status, expected one of " + strings.Join(models.ReservationStatuses, ", ")})
+				return
+			}
+			filter.Statuses = append(filter.Statuses, status)
+		}
+	}
+
+	if value := c.Query("from"); value != "" {
+		day, err := time.ParseInLocation("2006-01-02", value, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date, expected YYYY-MM-DD"})
+			return
+		}
+		filter.From = day
+	}
+	if value := c.Query("to"); value != "" {
+		day, err := time.ParseInLocation("2006-01-02", value, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date, expected YYYY-MM-DD"})
+			return
+		}
+		filter.To = day.AddDate(0, 0, 1)
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must not be after to"})
+		return
+	}
+
+	id, _ := c.Get("id")
+	reservations, err := reservationHandler.GetUserReservations(id.(uint), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching reservations"})
+		return
+	}
 
+	if reservations == nil {
+		reservations = []models.Reservation{}
+	}
+	locale, localized := requestLocale(c)
+	for i := range reservations {
+		exchangeRates.DisplayReservation(&reservations[i], currency)
+		if localized {
+			locale.LocalizeReservation(&reservations[i])
+		}
+	}
 	c.JSON(http.StatusOK, reservations)
 }
+
+type ReservationStatusRequest struct {
+	Status string `json:"status" example:"confirmed" enums:"confirmed,declined,cancelled,completed"`
+}
+
+// @Summary Update a Reservation Status
+// @Description Moves a reservation through its lifecycle: pending to confirmed, declined or cancelled, and confirmed to completed or cancelled. The owner of the restaurant or an admin can make any allowed change, the user who made the reservation can only cancel it. The guest is emailed about every change they did not make themselves.
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Param id path int true "Reservation ID" Format(int64)
+// @Param status body ReservationStatusRequest true "New status"
+// @security BearerAuth
+// @Success 200 {object} models.Reservation "The updated reservation."
+// @Failure 400 {object} ErrorResponse "Invalid input or a transition that is not allowed."
+// @Failure 403 {object} ErrorResponse "The user cannot change this reservation."
+// @Failure 404 {object} ErrorResponse "Reservation not found with the specified ID."
+// @Router /reservations/{id}/status [put]
+func UpdateReservationStatus(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reservation id"})
+		return
+	}
+
+	var request ReservationStatusRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	reservation, err := reservationHandler.GetReservation(uint(idInt))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Reservation not found"})
+		return
+	}
+
+	id, _ := c.Get("id")
+	isGuest := reservation.UserID == id.(uint)
+	if !canManageRestaurant(c, reservation.RestaurantID) && !(isGuest && request.Status == models.ReservationStatusCancelled) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to change this reservation"})
+		return
+	}
+
+	updated, err := reservationHandler.UpdateReservationStatus(reservation.ID, request.Status)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	publishEvent(EventReservationUpdated, updated.RestaurantID, gin.H{"reservationId": updated.ID, "status": updated.Status})
+	if updated.Status == models.ReservationStatusCancelled {
+		middleware.RecordActivity(c, updated.UserID, models.ActivityReservationCancelled, map[string]interface{}{"reservationId": updated.ID, "restaurantId": updated.RestaurantID})
+	}
+	if !isGuest {
+		go notifyReservationStatus(updated.ID, updated.Status)
+	}
+
+	if updated.IsPaid() {
+		// The receipt is issued after the response was written, it works on
+		// its own copy of the reservation
+		receipt := *updated
+		go func() {
+			if err := issueReceipt(&receipt); err != nil {
+				log.Printf("Failed to issue receipt for reservation %d: %v", receipt.ID, err)
+				return
+			}
+			if err := sendReceipt(&receipt); err != nil {
+				log.Printf("Failed to send receipt for reservation %d: %v", receipt.ID, err)
+			}
+		}()
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// maxBatchActions is how many reservations a single batch may change.
+const maxBatchActions = 100
+
+type BatchReservationRequest struct {
+	Actions []models.ReservationAction `json:"actions"`
+}
+
+type BatchReservationResponse struct {
+	Results []models.ReservationActionResult `json:"results"`
+}
+
+type BatchReservationErrorResponse struct {
+	Error   string                           `json:"error" example:"at least one action of the batch was rejected"`
+	Results []models.ReservationActionResult `json:"results"`
+}
+
+// @Summary Confirm or Decline Reservations in Batch
+// @Description Confirms or declines several pending reservations of the restaurant in one transaction. Either every action is applied or none is: when one is rejected the response has status 409 and the results tell which actions failed and why. The guests of the changed reservations are emailed their new status.
+// @Tags reservations
+// @Accept json
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Param actions body BatchReservationRequest true "Status changes, at most 100"
+// @security BearerAuth
+// @Success 200 {object} BatchReservationResponse "Every action was applied."
+// @Failure 400 {object} ErrorResponse "Invalid input format or too many actions."
+// @Failure 403 {object} ErrorResponse "The user does not manage this restaurant."
+// @Failure 409 {object} BatchReservationErrorResponse "At least one action was rejected, nothing was changed."
+// @Failure 500 {object} ErrorResponse "Internal server error while updating the reservations."
+// @Router /restaurants/{id}/reservations/batch [post]
+func BatchUpdateReservations(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant ID"})
+		return
+	}
+	restaurantID := uint(idInt)
+
+	var request BatchReservationRequest
+	if err := c.ShouldBindJSON(&request); err != nil || len(request.Actions) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+	if len(request.Actions) > maxBatchActions {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("A batch can change at most %d reservations", maxBatchActions)})
+		return
+	}
+
+	if !canManageRestaurant(c, restaurantID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to manage this restaurant"})
+		return
+	}
+
+	results, updated, err := reservationHandler.BatchUpdateReservationStatus(restaurantID, request.Actions)
+	if errors.Is(err, models.ErrBatchRejected) {
+		c.JSON(http.StatusConflict, BatchReservationErrorResponse{Error: err.Error(), Results: results})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating reservations"})
+		return
+	}
+
+	for _, reservation := range updated {
+		publishEvent(EventReservationUpdated, reservation.RestaurantID, gin.H{"reservationId": reservation.ID, "status": reservation.Status})
+		go notifyReservationStatus(reservation.ID, reservation.Status)
+	}
+
+	c.JSON(http.StatusOK, BatchReservationResponse{Results: results})
+}
+
+// notifyReservationStatus tells the guest of the reservation it moved to the
+// status. It runs after the response was written and loads the reservation
+// again with its guest and restaurant.
+func notifyReservationStatus(reservationID uint, status string) {
+	reservation, err := reservationHandler.GetReservation(reservationID)
+	if err != nil {
+		log.Printf("Failed to load reservation %d to notify its guest: %v", reservationID, err)
+		return
+	}
+
+	err = notify(&reservation.User, models.NotificationChannelEmail, "", models.EmailTemplateReservationStatus, map[string]string{
+		"name":       reservation.User.Name,
+		"restaurant": reservation.Restaurant.Name,
+		"date":       reservation.DateTime.In(reservation.Restaurant.Location()).Format("2 Jan 2006 15:04"),
+		"status":     status,
+	})
+	if err != nil && !errors.Is(err, utils.ErrEmailNotConfigured) {
+		log.Printf("Failed to notify the guest of reservation %d: %v", reservationID, err)
+	}
+}
@@ -1,8 +1,13 @@
 package v1
 
 import (
+	"encoding/json"
+	"errors"
+	"mime/multipart"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/punchanabu/redrice-backend-go/models"
@@ -17,12 +22,15 @@ func InitializedRestaurantHandler(db *gorm.DB) {
 }
 
 // @Summary Get a Single Restaurant
-// @Description Retrieves details of a single restaurant by its unique identifier.
+// @Description Retrieves details of a single restaurant by its unique identifier. The menu is only included with include=menu. Restaurants that are not active are only shown to their owner and admins.
 // @Tags restaurants
 // @Produce json
 // @Param id path int true "Restaurant ID" Format(int64)
+// @Param include query string false "Comma-separated extra details to include" Enums(menu)
+// @Param currency query string false "Currency to also show the prices in, e.g. USD" example(USD)
+// @Param Accept-Language header string false "Language to also write dates and amounts in, th or en, optionally with a calendar such as en-u-ca-buddhist" example(th-TH)
 // @security BearerAuth
-// @Success 200 {object} models.Restaurant "The details of the restaurant including ID, name, location, and other relevant information."
+// @Success 200 {object} models.RestaurantResponse "The details of the restaurant including ID, name, location, review tags, and other relevant information."
 // @Failure 400 {object} ErrorResponse "Invalid restaurant ID format."
 // @Failure 404 {object} ErrorResponse "Restaurant not found with the specified ID."
 // @Router /restaurants/{id} [get]
@@ -35,41 +43,343 @@ func GetRestaurant(c *gin.Context) {
 		return
 	}
 
+	currency, ok := displayCurrency(c)
+	if !ok {
+		return
+	}
+
 	idUint := uint(idInt)
 	restaurant, err := RestaurantHandler.GetRestaurant(idUint)
+	if err != nil || !restaurant.IsListed() && !canManageRestaurant(c, idUint) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+		return
+	}
+
+	respondRestaurant(c, restaurant, currency)
+}
+
+// findRestaurant returns the restaurant, writing the error response when it
+// does not exist or was deleted. Restaurants that are not listed are only
+// found by the users managing them.
+func findRestaurant(c *gin.Context, id uint) (*models.Restaurant, bool) {
+	restaurant, err := RestaurantHandler.GetRestaurant(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) || err == nil && !restaurant.IsListed() && !canManageRestaurant(c, id) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching restaurant"})
+		return nil, false
+	}
+	return restaurant, true
+}
+
+// respondRestaurant answers with the details of the restaurant as the public
+// listing shows them, with its review tags, the menu when included and the
+// display currency and locale of the request.
+func respondRestaurant(c *gin.Context, restaurant *models.Restaurant, currency string) {
+	var err error
+	restaurant.Tags, err = commentTagHandler.GetRestaurantTagCounts(restaurant.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching restaurant tags"})
+		return
+	}
+
+	for _, include := range strings.Split(c.Query("include"), ",") {
+		if strings.TrimSpace(include) == "menu" {
+			restaurant.Menu, err = menuHandler.GetMenu(restaurant.ID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching menu"})
+				return
+			}
+		}
+	}
+
+	response := restaurant.Response()
+	exchangeRates.DisplayRestaurant(&response, currency)
+	if locale, ok := requestLocale(c); ok {
+		locale.LocalizeRestaurant(&response)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary Preview a Restaurant Listing
+// @Description Shows the details of a restaurant exactly as customers will see them once its pending scheduled changes are published, whatever the status of the restaurant, so owners can check them first. The X-Pending-Changes header holds the number of scheduled changes applied. Only the owner of the restaurant or an admin can preview it.
+// @Tags restaurants
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Param include query string false "Comma-separated extra details to include" Enums(menu)
+// @Param currency query string false "Currency to also show the prices in, e.g. USD" example(USD)
+// @Param Accept-Language header string false "Language to also write dates and amounts in, th or en, optionally with a calendar such as en-u-ca-buddhist" example(th-TH)
+// @security BearerAuth
+// @Success 200 {object} models.RestaurantResponse "The details of the restaurant with its pending changes."
+// @Header 200 {integer} X-Pending-Changes "Number of scheduled changes applied"
+// @Failure 400 {object} ErrorResponse "Invalid restaurant ID format or unsupported currency."
+// @Failure 403 {object} ErrorResponse "The user does not manage the restaurant."
+// @Failure 404 {object} ErrorResponse "Restaurant not found with the specified ID."
+// @Failure 500 {object} ErrorResponse "Internal server error while fetching the restaurant."
+// @Router /restaurants/{id}/preview [get]
+func PreviewRestaurant(c *gin.Context) {
+	restaurantID, ok := managedRestaurantID(c)
+	if !ok {
+		return
+	}
+	currency, ok := displayCurrency(c)
+	if !ok {
+		return
+	}
+
+	restaurant, err := RestaurantHandler.GetRestaurant(restaurantID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
 		return
 	}
+	pending, err := scheduledChangeHandler.PreviewRestaurant(restaurant)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching scheduled changes"})
+		return
+	}
+
+	c.Header("X-Pending-Changes", strconv.Itoa(pending))
+	respondRestaurant(c, restaurant, currency)
+}
+
+// parseRestaurantFilter reads the filters of the restaurant list from the
+// query parameters.
+func parseRestaurantFilter(c *gin.Context) (models.RestaurantFilter, bool) {
+	filter := models.RestaurantFilter{Category: strings.TrimSpace(c.Query("category"))}
+	// Cuisines are categories, the cuisine parameter stays for older clients
+	if cuisine := strings.TrimSpace(c.Query("cuisine")); filter.Category == "" && cuisine != "" {
+		filter.Category = strings.ToLower(cuisine)
+		if slug, err := models.CategorySlug("", cuisine); err == nil {
+			filter.Category = slug
+		}
+	}
 
-	c.JSON(http.StatusOK, restaurant)
+	if value := c.Query("minRating"); value != "" {
+		rating, err := strconv.ParseFloat(value, 64)
+		if err != nil || rating < 0 || rating > 5 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid minRating, expected a number from 0 to 5"})
+			return filter, false
+		}
+		filter.MinRating = rating
+	}
+
+	for _, bound := range []struct {
+		name   string
+		target *int
+	}{{"minPrice", &filter.MinPriceRange}, {"maxPrice", &filter.MaxPriceRange}} {
+		if value := c.Query(bound.name); value != "" {
+			priceRange, err := strconv.Atoi(value)
+			if err != nil || !models.IsValidPriceRange(priceRange) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + bound.name + ", expected a price range from 1 to 4"})
+				return filter, false
+			}
+			*bound.target = priceRange
+		}
+	}
+	if filter.MaxPriceRange > 0 && filter.MinPriceRange > filter.MaxPriceRange {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "minPrice must not be above maxPrice"})
+		return filter, false
+	}
+
+	if c.Query("openNow") == "true" {
+		now := time.Now()
+		filter.OpenAt = &now
+	}
+	return filter, true
+}
+
+// parsePriceRange reads the price range form value, empty when not set.
+func parsePriceRange(c *gin.Context) (int, bool) {
+	value := c.Request.FormValue("priceRange")
+	if value == "" {
+		return 0, true
+	}
+	priceRange, err := strconv.Atoi(value)
+	if err != nil || !models.IsValidPriceRange(priceRange) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid priceRange, expected a number from 1 (budget) to 4 (fine dining)"})
+		return 0, false
+	}
+	return priceRange, true
+}
+
+// parseLocation reads the latitude and longitude form values, which are set
+// together, nil when not set.
+func parseLocation(c *gin.Context) (*float64, *float64, bool) {
+	latitudeStr := c.Request.FormValue("latitude")
+	longitudeStr := c.Request.FormValue("longitude")
+	if latitudeStr == "" && longitudeStr == "" {
+		return nil, nil, true
+	}
+	latitude, latErr := strconv.ParseFloat(latitudeStr, 64)
+	longitude, lngErr := strconv.ParseFloat(longitudeStr, 64)
+	if latErr != nil || lngErr != nil || !models.IsValidCoordinate(latitude, longitude) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid location, expected both a latitude from -90 to 90 and a longitude from -180 to 180"})
+		return nil, nil, false
+	}
+	return &latitude, &longitude, true
 }
 
 // @Summary Get All Restaurants
-// @Description Retrieves a list of all restaurants in the system.
+// @Description Retrieves one page of the restaurants in the system matching the filters, ordered by ID unless a sort key is given. Only active restaurants are listed. The X-Total-Count header holds the number of matching restaurants across all pages. Restaurants without a price range are left out when filtering by price and come last when sorting by it.
 // @Tags restaurants
 // @Produce json
+// @Param sort query string false "Sort key, name in alphabetical order, newest first, price cheapest first, the others highest first" Enums(name, newest, rating, verifiedRating, favorites, price, priceDesc)
+// @Param minRating query number false "Lowest average rating, from 0 to 5"
+// @Param cuisine query string false "Deprecated, name or slug of a cuisine category, ignored when category is set" example(thai)
+// @Param category query string false "Slug of a category the restaurants are listed under" example(vegan-friendly)
+// @Param minPrice query int false "Lowest price range, from 1 (budget) to 4 (fine dining)"
+// @Param maxPrice query int false "Highest price range, from 1 (budget) to 4 (fine dining)"
+// @Param openNow query bool false "Only restaurants open right now in their time zone"
+// @Param page query int false "Page number, starting at 1"
+// @Param limit query int false "Restaurants per page, at most 100"
+// @Param currency query string false "Currency to also show the prices in, e.g. USD" example(USD)
+// @Param Accept-Language header string false "Language to also write dates and amounts in, th or en, optionally with a calendar such as en-u-ca-buddhist" example(th-TH)
 // @security BearerAuth
-// @Success 200 {array} models.Restaurant "An array of restaurant objects."
+// @Success 200 {array} models.RestaurantResponse "An array of restaurant objects."
+// @Header 200 {integer} X-Total-Count "Number of restaurants across all pages"
+// @Failure 400 {object} ErrorResponse "Unknown sort key or invalid filter."
 // @Failure 500 {object} ErrorResponse "Internal server error while fetching restaurants."
 // @Router /restaurants [get]
 func GetRestaurants(c *gin.Context) {
-	users, err := RestaurantHandler.GetRestaurants()
+	sort := c.Query("sort")
+	if !models.IsValidRestaurantSort(sort) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sort key"})
+		return
+	}
+
+	filter, ok := parseRestaurantFilter(c)
+	if !ok {
+		return
+	}
+
+	currency, ok := displayCurrency(c)
+	if !ok {
+		return
+	}
+
+	page, limit := parsePagination(c)
+	restaurants, total, err := RestaurantHandler.GetRestaurants(filter, sort, page, limit)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching restaurants!"})
 		return
 	}
-	c.JSON(http.StatusOK, users)
+
+	responses := models.RestaurantResponses(restaurants)
+	locale, localized := requestLocale(c)
+	for i := range responses {
+		exchangeRates.DisplayRestaurant(&responses[i], currency)
+		if localized {
+			locale.LocalizeRestaurant(&responses[i])
+		}
+	}
+	c.Header("X-Total-Count", strconv.FormatInt(total, 10))
+	c.JSON(http.StatusOK, responses)
+}
+
+// maxSearchQueryLength bounds the search box input.
+const maxSearchQueryLength = 100
+
+// @Summary Search Restaurants
+// @Description Searches the name, description and address of the active restaurants, best matches first, raised or lowered by the search boosts set by admins. The query supports the web search syntax, e.g. "thai -buffet" or a "quoted phrase", and names a few typos away still match. The X-Total-Count header holds the number of matches across all pages.
+// @Tags restaurants
+// @Produce json
+// @Param q query string true "Search terms, at most 100 characters"
+// @Param page query int false "Page number, starting at 1"
+// @Param limit query int false "Restaurants per page, at most 100"
+// @security BearerAuth
+// @Success 200 {array} models.RestaurantResponse "The matching restaurants."
+// @Header 200 {integer} X-Total-Count "Number of matching restaurants across all pages"
+// @Failure 400 {object} ErrorResponse "Missing or too long query."
+// @Failure 500 {object} ErrorResponse "Internal server error while searching restaurants."
+// @Router /restaurants/search [get]
+func SearchRestaurants(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" || len([]rune(query)) > maxSearchQueryLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The search query is required and may have at most 100 characters"})
+		return
+	}
+
+	page, limit := parsePagination(c)
+	restaurants, total, err := RestaurantHandler.SearchRestaurants(query, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error searching restaurants"})
+		return
+	}
+
+	c.Header("X-Total-Count", strconv.FormatInt(total, 10))
+	c.JSON(http.StatusOK, models.RestaurantResponses(restaurants))
+}
+
+// Nearby searches default to defaultNearbyRadiusKm and may not go beyond maxNearbyRadiusKm.
+const (
+	defaultNearbyRadiusKm = 5
+	maxNearbyRadiusKm     = 50
+)
+
+// @Summary Get Nearby Restaurants
+// @Description Retrieves one page of the active restaurants within the radius of the point, closest first, with the distance to the point in kilometers. Restaurants without a location are left out. The X-Total-Count header holds the number of restaurants within the radius across all pages.
+// @Tags restaurants
+// @Produce json
+// @Param lat query number true "Latitude of the point, from -90 to 90" example(13.7563)
+// @Param lng query number true "Longitude of the point, from -180 to 180" example(100.5018)
+// @Param radius query number false "Radius in kilometers, 5 by default and at most 50"
+// @Param page query int false "Page number, starting at 1"
+// @Param limit query int false "Restaurants per page, at most 100"
+// @security BearerAuth
+// @Success 200 {array} models.RestaurantResponse "The restaurants within the radius, with their distance."
+// @Header 200 {integer} X-Total-Count "Number of restaurants within the radius across all pages"
+// @Failure 400 {object} ErrorResponse "Missing or invalid point or radius."
+// @Failure 500 {object} ErrorResponse "Internal server error while fetching restaurants."
+// @Router /restaurants/nearby [get]
+func GetNearbyRestaurants(c *gin.Context) {
+	latitude, latErr := strconv.ParseFloat(c.Query("lat"), 64)
+	longitude, lngErr := strconv.ParseFloat(c.Query("lng"), 64)
+	if latErr != nil || lngErr != nil || !models.IsValidCoordinate(latitude, longitude) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "lat from -90 to 90 and lng from -180 to 180 are required"})
+		return
+	}
+
+	radius := float64(defaultNearbyRadiusKm)
+	if value := c.Query("radius"); value != "" {
+		var err error
+		radius, err = strconv.ParseFloat(value, 64)
+		if err != nil || radius <= 0 || radius > maxNearbyRadiusKm {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid radius, expected up to 50 kilometers"})
+			return
+		}
+	}
+
+	page, limit := parsePagination(c)
+	restaurants, total, err := RestaurantHandler.GetNearbyRestaurants(latitude, longitude, radius, page, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching nearby restaurants"})
+		return
+	}
+
+	c.Header("X-Total-Count", strconv.FormatInt(total, 10))
+	c.JSON(http.StatusOK, models.RestaurantResponses(restaurants))
+}
+
+// uploadedImageWarnings reports images too small to look good. Formats whose
+// dimensions cannot be read are not checked.
+func uploadedImageWarnings(file multipart.File) []string {
+	width, height, err := utils.ImageDimensions(file)
+	if err != nil {
+		return nil
+	}
+	return models.ImageWarnings(width, height)
 }
 
 // @Summary Create a New Restaurant
-// @Description Adds a new restaurant to the system with the provided details.
+// @Description Adds a new restaurant to the system with the provided details. The restaurant starts as a draft and is only listed once submitted and approved. The response lists data quality issues such as a malformed telephone or a very small image in warnings, they do not prevent the creation.
 // @Tags restaurants
 // @Accept json
 // @Produce json
 // @Param restaurant body models.Restaurant true "Restaurant Registration Details"
 // @security BearerAuth
-// @Success 201 {object} models.Restaurant "The created restaurant's details, including its unique identifier."
+// @Success 201 {object} models.RestaurantResponse "The created restaurant's details, including its unique identifier."
 // @Failure 400 {object} ErrorResponse "Invalid input format for restaurant details."
 // @Failure 500 {object} ErrorResponse "Internal server error while creating the restaurant."
 // @Router /restaurants [post]
@@ -89,6 +399,25 @@ func CreateRestaurant(c *gin.Context) {
 	instagram := c.Request.FormValue("instagram")
 	openTime := c.Request.FormValue("openTime")
 	closeTime := c.Request.FormValue("closeTime")
+	priceRange, ok := parsePriceRange(c)
+	if !ok {
+		return
+	}
+	latitude, longitude, ok := parseLocation(c)
+	if !ok {
+		return
+	}
+	categories, _, ok := parseCategories(c)
+	if !ok {
+		return
+	}
+	timezone := c.Request.FormValue("timezone")
+	if timezone == "" {
+		timezone = models.DefaultTimezone
+	} else if !models.IsValidTimezone(timezone) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid timezone, expected an IANA name such as Asia/Bangkok"})
+		return
+	}
 	file, header, err := c.Request.FormFile("image")
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing image!"})
@@ -97,6 +426,7 @@ func CreateRestaurant(c *gin.Context) {
 
 	defer file.Close()
 
+	imageWarnings := uploadedImageWarnings(file)
 	imageUrl, err := utils.UploadImageToS3("redrice", file, header.Filename)
 
 	if err != nil {
@@ -114,6 +444,12 @@ func CreateRestaurant(c *gin.Context) {
 		Instagram:   instagram,
 		OpenTime:    openTime,
 		CloseTime:   closeTime,
+		PriceRange:  priceRange,
+		Latitude:    latitude,
+		Longitude:   longitude,
+		Timezone:    timezone,
+		Categories:  categories,
+		Status:      models.RestaurantStatusDraft,
 	}
 
 	if err := RestaurantHandler.CreateRestaurant(&restaurant); err != nil {
@@ -121,18 +457,19 @@ func CreateRestaurant(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusCreated, restaurant)
+	restaurant.Warnings = append(restaurant.QualityWarnings(), imageWarnings...)
+	c.JSON(http.StatusCreated, restaurant.Response())
 }
 
 // @Summary Update a Restaurant
-// @Description Updates the details of an existing restaurant identified by its ID.
+// @Description Updates the details of an existing restaurant identified by its ID. Fields left empty in the form keep their value, use the partial update to clear them. Changes to the listing are recorded in the restaurant history. Data quality issues in the changed fields are listed in warnings.
 // @Tags restaurants
 // @Accept json
 // @Produce json
 // @Param id path int true "Restaurant ID" Format(int64)
 // @Param restaurant body models.Restaurant true "Updated Restaurant Details"
 // @security BearerAuth
-// @Success 200 {object} models.Restaurant "The updated restaurant's details."
+// @Success 200 {object} models.RestaurantResponse "The updated restaurant's details."
 // @Failure 400 {object} ErrorResponse "Invalid input format for restaurant details or invalid restaurant ID."
 // @Failure 404 {object} ErrorResponse "Restaurant not found with the specified ID."
 // @Router /restaurants/{id} [put]
@@ -160,13 +497,36 @@ func UpdateRestaurant(c *gin.Context) {
 	instagram := c.Request.FormValue("instagram")
 	openTime := c.Request.FormValue("openTime")
 	closeTime := c.Request.FormValue("closeTime")
+	timezone := c.Request.FormValue("timezone")
 	ratingStr := c.Request.FormValue("rating")
 	commentCountStr := c.Request.FormValue("commentCount")
 
+	priceRange, ok := parsePriceRange(c)
+	if !ok {
+		return
+	}
+
+	latitude, longitude, ok := parseLocation(c)
+	if !ok {
+		return
+	}
+
+	categories, setCategories, ok := parseCategories(c)
+	if !ok {
+		return
+	}
+
+	if timezone != "" && !models.IsValidTimezone(timezone) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid timezone, expected an IANA name such as Asia/Bangkok"})
+		return
+	}
+
 	file, header, err := c.Request.FormFile("image")
 	var imageUrl string
+	var imageWarnings []string
 	if err == nil {
 		defer file.Close()
+		imageWarnings = uploadedImageWarnings(file)
 		imageUrl, err = utils.UploadImageToS3("redrice", file, header.Filename)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error uploading image"})
@@ -186,6 +546,10 @@ func UpdateRestaurant(c *gin.Context) {
 		Instagram:   instagram,
 		OpenTime:    openTime,
 		CloseTime:   closeTime,
+		PriceRange:  priceRange,
+		Latitude:    latitude,
+		Longitude:   longitude,
+		Timezone:    timezone,
 	}
 	if imageUrl != "" {
 		updatedRestaurant.ImageURL = imageUrl
@@ -211,18 +575,143 @@ func UpdateRestaurant(c *gin.Context) {
 		updatedRestaurant.CommentCount = &commentCount
 	}
 
-	// Update the restaurant in the database
-	err = RestaurantHandler.UpdateRestaurant(idUint, &updatedRestaurant)
+	// Update the restaurant in the database, keeping track of the changes
+	userID, _ := c.Get("id")
+	err = RestaurantHandler.UpdateRestaurantWithHistory(idUint, userID.(uint), &updatedRestaurant)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
 		return
 	}
+	if setCategories {
+		if err := RestaurantHandler.SetRestaurantCategories(idUint, categories); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating categories"})
+			return
+		}
+		updatedRestaurant.Categories = categories
+	}
+
+	updatedRestaurant.Warnings = append(updatedRestaurant.QualityWarnings(), imageWarnings...)
+	c.JSON(http.StatusOK, updatedRestaurant.Response())
+}
 
-	c.JSON(http.StatusOK, updatedRestaurant)
+type RestaurantPatchRequest struct {
+	Name        *string `json:"name" example:"RedRice Bistro"`
+	Address     *string `json:"address" example:"123 Sukhumvit Rd, Bangkok"`
+	Telephone   *string `json:"telephone" example:"021234567"`
+	Description *string `json:"description" example:"Thai comfort food"`
+	Facebook    *string `json:"facebook" example:"redrice"`
+	Instagram   *string `json:"instagram" example:"redrice"`
+	OpenTime    *string `json:"openTime" example:"10:00"`
+	CloseTime   *string `json:"closeTime" example:"22:00"`
+	Timezone    *string `json:"timezone" example:"Asia/Bangkok"`
+	PriceRange  *int    `json:"priceRange" example:"2"`
+	// Latitude and Longitude are set together, null clears both
+	Latitude  nullableFloat `json:"latitude" swaggertype:"number" example:"13.7563"`
+	Longitude nullableFloat `json:"longitude" swaggertype:"number" example:"100.5018"`
+}
+
+// nullableFloat is a JSON number that tells null apart from a missing value,
+// null is Set with a nil Value.
+type nullableFloat struct {
+	Set   bool
+	Value *float64
+}
+
+func (f *nullableFloat) UnmarshalJSON(data []byte) error {
+	f.Set = true
+	return json.Unmarshal(data, &f.Value)
+}
+
+// @Summary Partially Update a Restaurant
+// @Description Updates only the fields present in the body of an existing restaurant identified by its ID, leaving the others as they are. Unlike the form update, present fields may be set to an empty value to clear them, and latitude and longitude to null. The telephone is stored in the E.164 format. Changes to the listing are recorded in the restaurant history. Data quality issues of the restaurant are listed in warnings.
+// @Tags restaurants
+// @Accept json
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Param restaurant body RestaurantPatchRequest true "Fields to change"
+// @security BearerAuth
+// @Success 200 {object} models.RestaurantResponse "The updated restaurant's details."
+// @Failure 400 {object} ErrorResponse "Invalid input format, field value or restaurant ID."
+// @Failure 404 {object} ErrorResponse "Restaurant not found with the specified ID."
+// @Failure 500 {object} ErrorResponse "Internal server error while updating the restaurant."
+// @Router /restaurants/{id} [patch]
+func PatchRestaurant(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant ID"})
+		return
+	}
+
+	var request RestaurantPatchRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	if request.Name != nil && strings.TrimSpace(*request.Name) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Name cannot be empty"})
+		return
+	}
+	if request.PriceRange != nil && *request.PriceRange != 0 && !models.IsValidPriceRange(*request.PriceRange) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid priceRange, expected a number from 1 (budget) to 4 (fine dining), or 0 to clear it"})
+		return
+	}
+	latitude, longitude := request.Latitude.Value, request.Longitude.Value
+	if request.Latitude.Set != request.Longitude.Set || (latitude == nil) != (longitude == nil) ||
+		(latitude != nil && !models.IsValidCoordinate(*latitude, *longitude)) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid location, expected both a latitude from -90 to 90 and a longitude from -180 to 180, or both null"})
+		return
+	}
+	for field, value := range map[string]*string{"openTime": request.OpenTime, "closeTime": request.CloseTime} {
+		if value != nil && *value != "" && !models.IsValidClockTime(*value) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + field + ", expected a time of the day in HH:MM format"})
+			return
+		}
+	}
+	if request.Telephone != nil {
+		telephone, err := models.NormalizeTelephone(*request.Telephone)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		request.Telephone = &telephone
+	}
+	if request.Timezone != nil && !models.IsValidTimezone(*request.Timezone) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid timezone, expected an IANA name such as Asia/Bangkok"})
+		return
+	}
+
+	userID, _ := c.Get("id")
+	restaurant, err := RestaurantHandler.PatchRestaurant(uint(idInt), userID.(uint), models.RestaurantPatch{
+		Name:          request.Name,
+		Address:       request.Address,
+		Telephone:     request.Telephone,
+		Description:   request.Description,
+		Facebook:      request.Facebook,
+		Instagram:     request.Instagram,
+		OpenTime:      request.OpenTime,
+		CloseTime:     request.CloseTime,
+		Timezone:      request.Timezone,
+		PriceRange:    request.PriceRange,
+		Latitude:      latitude,
+		Longitude:     longitude,
+		ClearLocation: request.Latitude.Set && latitude == nil,
+	})
+	if errors.Is(err, models.ErrRestaurantNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating restaurant"})
+		return
+	}
+
+	restaurant.Warnings = restaurant.QualityWarnings()
+	c.JSON(http.StatusOK, restaurant.Response())
 }
 
 // @Summary Delete a Restaurant
-// @Description Removes a restaurant from the system by its unique identifier.
+// @Description Removes a restaurant from the system by its unique identifier. The restaurant is soft deleted: it is hidden from every listing while its reservations and comments are kept, and it can be restored with /restaurants/{id}/restore.
 // @Tags restaurants
 // @Produce json
 // @Param id path int true "Restaurant ID" Format(int64)
@@ -250,3 +739,317 @@ func DeleteRestaurant(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"status": "deleted", "message": "Restaurant deleted successfully!"})
 }
+
+// @Summary Restore a Restaurant
+// @Description Brings back a deleted restaurant with its reservations and comments.
+// @Tags restaurants
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @security BearerAuth
+// @Success 200 {object} models.RestaurantResponse "The restored restaurant's details."
+// @Failure 400 {object} ErrorResponse "Invalid restaurant ID format."
+// @Failure 404 {object} ErrorResponse "Restaurant not found with the specified ID."
+// @Failure 409 {object} ErrorResponse "The restaurant is not deleted."
+// @Failure 500 {object} ErrorResponse "Internal server error while restoring the restaurant."
+// @Router /restaurants/{id}/restore [post]
+func RestoreRestaurant(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant id"})
+		return
+	}
+
+	restaurant, err := RestaurantHandler.RestoreRestaurant(uint(idInt))
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+	case errors.Is(err, models.ErrRestaurantNotDeleted):
+		c.JSON(http.StatusConflict, gin.H{"error": "Restaurant is not deleted"})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error restoring restaurant"})
+	default:
+		c.JSON(http.StatusOK, restaurant.Response())
+	}
+}
+
+// canManageRestaurant reports whether the authenticated user may manage every
+// restaurant or owns the given one.
+func canManageRestaurant(c *gin.Context, restaurantID uint) bool {
+	id, ok := c.Get("id")
+	if !ok {
+		return false
+	}
+
+	userID, ok := id.(uint)
+	if !ok {
+		return false
+	}
+
+	user, err := userHandler.GetUser(userID)
+	if err != nil {
+		return false
+	}
+
+	return user.CanManageRestaurant(restaurantID)
+}
+
+// @Summary Get Restaurant Availability
+// @Description Lists the bookable time slots of a restaurant for the given day, taking the owner's booking policy, blackouts and the special hours of the day into account. No slots are listed on days the restaurant is closed.
+// @Tags restaurants
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Param date query string false "Day to list in YYYY-MM-DD format in the time zone of the restaurant, defaults to today"
+// @security BearerAuth
+// @Success 200 {array} models.Slot "The time slots of the day."
+// @Failure 400 {object} ErrorResponse "Invalid restaurant ID or date format."
+// @Failure 404 {object} ErrorResponse "Restaurant not found with the specified ID."
+// @Router /restaurants/{id}/availability [get]
+func GetRestaurantAvailability(c *gin.Context) {
+	idString := c.Param("id")
+	idInt, err := strconv.Atoi(idString)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant id"})
+		return
+	}
+
+	restaurant, err := RestaurantHandler.GetRestaurant(uint(idInt))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+		return
+	}
+
+	// The day is the one of the restaurant, whatever the time zone of the server
+	now := time.Now()
+	day := now.In(restaurant.Location())
+	if dateString := c.Query("date"); dateString != "" {
+		day, err = time.ParseInLocation("2006-01-02", dateString, restaurant.Location())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format, expected YYYY-MM-DD"})
+			return
+		}
+	}
+
+	dayStart := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	// Slots of late closing restaurants may run into the next day
+	blackouts, err := blackoutHandler.GetBlackoutsByRestaurantID(restaurant.ID, dayStart, dayStart.AddDate(0, 0, 2))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching blackouts"})
+		return
+	}
+
+	special, err := specialHoursHandler.GetSpecialHoursOn(restaurant, day)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching special hours"})
+		return
+	}
+
+	slots, err := restaurant.Slots(day, now, blackouts, special)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error computing availability: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, slots)
+}
+
+type BookingPolicyRequest struct {
+	MinNoticeMinutes     int          `json:"minNoticeMinutes" example:"60"`
+	MaxAdvanceDays       int          `json:"maxAdvanceDays" example:"30"`
+	RequireVerifiedPhone bool         `json:"requireVerifiedPhone" example:"false"`
+	Deposit              models.Money `json:"deposit"`
+}
+
+// @Summary Update Restaurant Booking Policy
+// @Description Sets the minimum notice and the maximum advance booking window of a restaurant, whether guests need a verified telephone number to book, and the deposit charged per reservation in minor units of its currency, e.g. 20000 THB for 200 baht. Service charge and VAT are applied to the deposit according to the tax settings. Zero disables the corresponding rule. Only the owner of the restaurant or an admin can change it.
+// @Tags restaurants
+// @Accept json
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Param policy body BookingPolicyRequest true "Booking policy"
+// @security BearerAuth
+// @Success 200 {object} models.RestaurantResponse "The updated restaurant."
+// @Failure 400 {object} ErrorResponse "Invalid input format or restaurant ID."
+// @Failure 403 {object} ErrorResponse "The user does not manage this restaurant."
+// @Failure 404 {object} ErrorResponse "Restaurant not found with the specified ID."
+// @Router /restaurants/{id}/booking-policy [put]
+func UpdateBookingPolicy(c *gin.Context) {
+	idString := c.Param("id")
+	idInt, err := strconv.Atoi(idString)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant id"})
+		return
+	}
+	idUint := uint(idInt)
+
+	var policy BookingPolicyRequest
+	if err := c.ShouldBindJSON(&policy); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	if policy.MinNoticeMinutes < 0 || policy.MaxAdvanceDays < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Booking policy values cannot be negative"})
+		return
+	}
+
+	if policy.Deposit.Currency == "" {
+		policy.Deposit.Currency = models.DefaultCurrency
+	}
+	if err := policy.Deposit.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid deposit: " + err.Error()})
+		return
+	}
+
+	if !canManageRestaurant(c, idUint) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to manage this restaurant"})
+		return
+	}
+
+	restaurant, err := RestaurantHandler.UpdateBookingPolicy(idUint, models.BookingPolicy{
+		MinNoticeMinutes:     policy.MinNoticeMinutes,
+		MaxAdvanceDays:       policy.MaxAdvanceDays,
+		RequireVerifiedPhone: policy.RequireVerifiedPhone,
+		Deposit:              policy.Deposit,
+	})
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, restaurant.Response())
+}
+
+type TaxSettingsRequest struct {
+	VATRegistered     bool    `json:"vatRegistered" example:"true"`
+	TaxID             string  `json:"taxId" example:"0105558012345"`
+	ServiceChargeRate float64 `json:"serviceChargeRate" example:"0.1"`
+	PricesIncludeTax  bool    `json:"pricesIncludeTax" example:"false"`
+}
+
+// @Summary Update Restaurant Tax Settings
+// @Description Sets whether the restaurant is registered for VAT, its 13-digit Thai tax ID, the service charge rate as a fraction and whether the deposit already includes them. VAT is charged at 7% on the deposit plus service charge and requires a valid tax ID. Only the owner of the restaurant or an admin can change it.
+// @Tags restaurants
+// @Accept json
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Param settings body TaxSettingsRequest true "Tax settings"
+// @security BearerAuth
+// @Success 200 {object} models.RestaurantResponse "The updated restaurant."
+// @Failure 400 {object} ErrorResponse "Invalid input format, restaurant ID or tax settings."
+// @Failure 403 {object} ErrorResponse "The user does not manage this restaurant."
+// @Failure 404 {object} ErrorResponse "Restaurant not found with the specified ID."
+// @Router /restaurants/{id}/tax-settings [put]
+func UpdateTaxSettings(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant id"})
+		return
+	}
+	idUint := uint(idInt)
+
+	var request TaxSettingsRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	settings := models.TaxSettings{
+		VATRegistered:     request.VATRegistered,
+		TaxID:             strings.TrimSpace(request.TaxID),
+		ServiceChargeRate: request.ServiceChargeRate,
+		PricesIncludeTax:  request.PricesIncludeTax,
+	}
+	if err := models.ValidateTaxSettings(settings); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !canManageRestaurant(c, idUint) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to manage this restaurant"})
+		return
+	}
+
+	restaurant, err := RestaurantHandler.UpdateTaxSettings(idUint, settings)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, restaurant.Response())
+}
+
+type RestaurantHistoryPage struct {
+	Data []models.RestaurantVersion `json:"data"`
+	Pagination
+}
+
+// @Summary Get Restaurant Change History
+// @Description Lists the changes made to the listing of a restaurant, newest first, with who made them and the old and new values.
+// @Tags restaurants
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Param page query int false "Page number, starting at 1"
+// @Param limit query int false "Page size, at most 100"
+// @security BearerAuth
+// @Success 200 {object} RestaurantHistoryPage "A page of restaurant versions."
+// @Failure 400 {object} ErrorResponse "Invalid restaurant ID."
+// @Failure 500 {object} ErrorResponse "Internal server error while fetching the history."
+// @Router /restaurants/{id}/history [get]
+func GetRestaurantHistory(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant id"})
+		return
+	}
+
+	page, limit := parsePagination(c)
+
+	versions, total, err := RestaurantHandler.GetRestaurantHistory(uint(idInt), limit, (page-1)*limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching restaurant history"})
+		return
+	}
+
+	if versions == nil {
+		versions = []models.RestaurantVersion{}
+	}
+
+	c.JSON(http.StatusOK, RestaurantHistoryPage{
+		Data:       versions,
+		Pagination: Pagination{Page: page, Limit: limit, Total: total},
+	})
+}
+
+// @Summary Revert a Restaurant to a Version
+// @Description Restores the listing of a restaurant to the state it had right after the given version, undoing every later change. The revert is recorded as a new version.
+// @Tags restaurants
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Param versionId path int true "Version ID" Format(int64)
+// @security BearerAuth
+// @Success 200 {object} models.RestaurantResponse "The restored restaurant."
+// @Failure 400 {object} ErrorResponse "Invalid restaurant or version ID."
+// @Failure 404 {object} ErrorResponse "Restaurant or version not found."
+// @Router /restaurants/{id}/revert/{versionId} [post]
+func RevertRestaurant(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant id"})
+		return
+	}
+
+	versionInt, err := strconv.Atoi(c.Param("versionId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid version id"})
+		return
+	}
+
+	userID, _ := c.Get("id")
+	restaurant, err := RestaurantHandler.RevertRestaurant(uint(idInt), uint(versionInt), userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, restaurant.Response())
+}
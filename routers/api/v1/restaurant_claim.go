@@ -0,0 +1,309 @@
+package v1
+
+import (
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/middleware"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"github.com/punchanabu/redrice-backend-go/utils"
+	"gorm.io/gorm"
+)
+
+// claimBucket keeps the proof documents, which are only shared through short-lived links.
+const claimBucket = "redrice"
+
+// maxClaimDocuments bounds the proof documents of a claim.
+const maxClaimDocuments = 5
+
+// claimDocumentLinkTTL is how long the links to the proof documents listed to admins stay valid.
+const claimDocumentLinkTTL = 15 * time.Minute
+
+// claimDocumentTypes are the accepted proof documents by extension.
+var claimDocumentTypes = map[string]bool{".pdf": true, ".jpg": true, ".jpeg": true, ".png": true}
+
+var restaurantClaimHandler *models.RestaurantClaimHandler
+
+func InitializedRestaurantClaimHandler(db *gorm.DB) {
+	restaurantClaimHandler = models.NewRestaurantClaimHandler(db)
+}
+
+type RestaurantClaimPage struct {
+	Data []models.RestaurantClaim `json:"data"`
+	Pagination
+}
+
+type ClaimRejectionRequest struct {
+	Reason string `json:"reason" example:"The business registration does not match the restaurant"`
+}
+
+// deleteClaimDocuments removes the proof documents of a claim that was not saved.
+func deleteClaimDocuments(keys []string) {
+	for _, key := range keys {
+		utils.DeleteFromS3(claimBucket, key)
+	}
+}
+
+// @Summary Claim a Restaurant
+// @Description Asks admins to make the authenticated user the owner of an existing restaurant. Up to 5 proof documents such as a business registration, as PDF, JPEG or PNG, are kept privately for the review. Once an admin approves the claim the user manages the restaurant, which gets the verified badge, and has to log in again.
+// @Tags restaurants
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Param documents formData file true "Proof documents, repeat the field for every document"
+// @Param message formData string false "Message to the admins"
+// @security BearerAuth
+// @Success 201 {object} models.RestaurantClaim "The submitted claim."
+// @Failure 400 {object} ErrorResponse "Invalid restaurant ID, missing, unsupported or too many documents, or the user is an admin."
+// @Failure 404 {object} ErrorResponse "Restaurant not found with the specified ID."
+// @Failure 409 {object} ErrorResponse "The user already has a pending claim for the restaurant."
+// @Failure 500 {object} ErrorResponse "Internal server error while uploading the documents or saving the claim."
+// @Router /restaurants/{id}/claims [post]
+func CreateRestaurantClaim(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant id"})
+		return
+	}
+
+	claims := c.MustGet("claims").(*middleware.Claims)
+	if models.HasPermission(claims.Role, models.PermissionManageAnyRestaurant) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": models.ErrClaimByAdmin.Error()})
+		return
+	}
+
+	if err := c.Request.ParseMultipartForm(10 << 20); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing documents"})
+		return
+	}
+	headers := c.Request.MultipartForm.File["documents"]
+	if len(headers) == 0 || len(headers) > maxClaimDocuments {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Between 1 and " + strconv.Itoa(maxClaimDocuments) + " proof documents are required"})
+		return
+	}
+	for _, header := range headers {
+		if !claimDocumentTypes[strings.ToLower(filepath.Ext(header.Filename))] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Proof documents must be PDF, JPEG or PNG files"})
+			return
+		}
+	}
+
+	claim := models.RestaurantClaim{
+		RestaurantID: uint(idInt),
+		UserID:       claims.UserId,
+		Message:      c.Request.FormValue("message"),
+	}
+	for _, header := range headers {
+		key, err := uploadClaimDocument(claim.RestaurantID, header)
+		if err != nil {
+			deleteClaimDocuments(claim.DocumentKeys)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error uploading documents"})
+			return
+		}
+		claim.DocumentKeys = append(claim.DocumentKeys, key)
+	}
+
+	err = restaurantClaimHandler.CreateClaim(&claim)
+	switch {
+	case errors.Is(err, models.ErrRestaurantNotFound):
+		deleteClaimDocuments(claim.DocumentKeys)
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+		return
+	case errors.Is(err, models.ErrClaimPending):
+		deleteClaimDocuments(claim.DocumentKeys)
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		deleteClaimDocuments(claim.DocumentKeys)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error saving claim"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, claim)
+}
+
+// uploadClaimDocument stores one proof document and returns its key.
+func uploadClaimDocument(restaurantID uint, header *multipart.FileHeader) (string, error) {
+	file, err := header.Open()
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return "", err
+	}
+	key := models.ClaimDocumentKey(restaurantID, header.Filename)
+	return key, utils.UploadFileToS3(claimBucket, key, content, mime.TypeByExtension(strings.ToLower(filepath.Ext(header.Filename))))
+}
+
+// @Summary Get My Restaurant Claims
+// @Description Lists the restaurant claims the authenticated user submitted with their review status, newest first.
+// @Tags restaurants
+// @Produce json
+// @security BearerAuth
+// @Success 200 {array} models.RestaurantClaim "The claims of the user."
+// @Failure 500 {object} ErrorResponse "Internal server error while fetching the claims."
+// @Router /me/claims [get]
+func GetMyRestaurantClaims(c *gin.Context) {
+	userID, _ := c.Get("id")
+	claims, err := restaurantClaimHandler.GetUserClaims(userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching claims"})
+		return
+	}
+
+	c.JSON(http.StatusOK, claims)
+}
+
+// @Summary Get Restaurant Claims
+// @Description Lists the restaurant claims oldest first, only the ones with the status when given, with links to their proof documents valid for 15 minutes.
+// @Tags admin
+// @Produce json
+// @Param status query string false "Review status" Enums(pending, approved, rejected)
+// @Param page query int false "Page number, starting at 1"
+// @Param limit query int false "Claims per page, at most 100"
+// @security BearerAuth
+// @Success 200 {object} RestaurantClaimPage "One page of claims."
+// @Failure 400 {object} ErrorResponse "Invalid status."
+// @Failure 500 {object} ErrorResponse "Internal server error while fetching the claims."
+// @Router /admin/claims [get]
+func GetRestaurantClaims(c *gin.Context) {
+	status := c.Query("status")
+	switch status {
+	case "", models.ClaimStatusPending, models.ClaimStatusApproved, models.ClaimStatusRejected:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status, expected pending, approved or rejected"})
+		return
+	}
+
+	page, limit := parsePagination(c)
+	claims, total, err := restaurantClaimHandler.GetClaims(status, limit, (page-1)*limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching claims"})
+		return
+	}
+
+	for i := range claims {
+		for _, key := range claims[i].DocumentKeys {
+			link, err := utils.PresignedURL(claimBucket, key, claimDocumentLinkTTL)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Error sharing claim documents"})
+				return
+			}
+			claims[i].Documents = append(claims[i].Documents, link)
+		}
+	}
+
+	if claims == nil {
+		claims = []models.RestaurantClaim{}
+	}
+	c.JSON(http.StatusOK, RestaurantClaimPage{
+		Data:       claims,
+		Pagination: Pagination{Page: page, Limit: limit, Total: total},
+	})
+}
+
+// respondClaimReview answers the review of a claim and records it in the audit log.
+func respondClaimReview(c *gin.Context, claim *models.RestaurantClaim, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Claim not found"})
+		return
+	case errors.Is(err, models.ErrClaimReviewed), errors.Is(err, models.ErrClaimantManagesOther):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, models.ErrClaimByAdmin):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error reviewing claim"})
+		return
+	}
+
+	adminID := *claim.ReviewedBy
+	entry := models.AuditEntry{
+		ActorID: adminID,
+		UserID:  claim.UserID,
+		Action:  models.AuditActionReviewClaim,
+		Path:    c.Request.URL.Path,
+		Status:  http.StatusOK,
+		IP:      c.ClientIP(),
+		Details: map[string]interface{}{
+			"claimId":      claim.ID,
+			"restaurantId": claim.RestaurantID,
+			"status":       claim.Status,
+		},
+	}
+	if err := auditHandler.Record(&entry); err != nil {
+		log.Printf("Failed to record review of claim %d by admin %d: %v", claim.ID, adminID, err)
+	}
+
+	c.JSON(http.StatusOK, claim)
+}
+
+// @Summary Approve a Restaurant Claim
+// @Description Makes the claimant the owner of the restaurant and gives the restaurant the verified badge. Claimants already managing another restaurant cannot be approved. The accounts managing the restaurant before become customers and the other pending claims for the restaurant are rejected. The claimant and the former owners have to log in again for their new roles to apply.
+// @Tags admin
+// @Produce json
+// @Param id path int true "Claim ID" Format(int64)
+// @security BearerAuth
+// @Success 200 {object} models.RestaurantClaim "The approved claim."
+// @Failure 400 {object} ErrorResponse "Invalid claim ID or the claimant is an admin."
+// @Failure 404 {object} ErrorResponse "Claim not found with the specified ID."
+// @Failure 409 {object} ErrorResponse "The claim was already reviewed or the claimant manages another restaurant."
+// @Failure 500 {object} ErrorResponse "Internal server error while approving the claim."
+// @Router /admin/claims/{id}/approve [post]
+func ApproveRestaurantClaim(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid claim id"})
+		return
+	}
+
+	adminID, _ := c.Get("id")
+	claim, err := restaurantClaimHandler.ApproveClaim(uint(idInt), adminID.(uint), time.Now())
+	respondClaimReview(c, claim, err)
+}
+
+// @Summary Reject a Restaurant Claim
+// @Description Closes a claim without changing the restaurant. The reason is shown to the claimant.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Claim ID" Format(int64)
+// @Param rejection body ClaimRejectionRequest true "Reason of the rejection"
+// @security BearerAuth
+// @Success 200 {object} models.RestaurantClaim "The rejected claim."
+// @Failure 400 {object} ErrorResponse "Invalid claim ID or input format."
+// @Failure 404 {object} ErrorResponse "Claim not found with the specified ID."
+// @Failure 409 {object} ErrorResponse "The claim was already reviewed."
+// @Failure 500 {object} ErrorResponse "Internal server error while rejecting the claim."
+// @Router /admin/claims/{id}/reject [post]
+func RejectRestaurantClaim(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid claim id"})
+		return
+	}
+
+	var request ClaimRejectionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	adminID, _ := c.Get("id")
+	claim, err := restaurantClaimHandler.RejectClaim(uint(idInt), adminID.(uint), request.Reason, time.Now())
+	respondClaimReview(c, claim, err)
+}
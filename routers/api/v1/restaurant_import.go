@@ -0,0 +1,271 @@
+package v1
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/models"
+)
+
+// maxImportRows caps the restaurants of a single CSV upload.
+const maxImportRows = 2000
+
+const (
+	ImportImported = "imported"
+	ImportFailed   = "failed"
+)
+
+// importColumns are the columns a restaurant CSV may have, named in its
+// header row like the fields of the restaurant form.
+var importColumns = []string{
+	"name", "address", "telephone", "description", "facebook", "instagram", "openTime", "closeTime",
+	"priceRange", "latitude", "longitude", "timezone", "categories", "imageUrl",
+}
+
+type ImportResult struct {
+	Row          int      `json:"row" example:"2"`
+	Name         string   `json:"name" example:"Baan Somtum"`
+	Status       string   `json:"status" example:"imported" enums:"imported,failed"`
+	RestaurantID uint     `json:"restaurantId,omitempty" example:"42"`
+	Error        string   `json:"error,omitempty" example:""`
+	Warnings     []string `json:"warnings,omitempty"`
+}
+
+type RestaurantImportResponse struct {
+	Imported int            `json:"imported" example:"1"`
+	Failed   int            `json:"failed" example:"0"`
+	Results  []ImportResult `json:"results"`
+}
+
+// @Summary Import Restaurants from CSV
+// @Description Uploads a CSV file of restaurants with a header row naming its columns: name, address, telephone, description, facebook, instagram, openTime, closeTime, priceRange, latitude, longitude, timezone, categories as comma-separated slugs and imageUrl. Only name is required, telephone numbers are stored in the E.164 format. Every row is validated and the valid ones are saved together, the response reports the result of every row with the data quality warnings of the imported ones. Imported restaurants start as drafts unless another status is given. With dry_run=true the rows are validated the same way but nothing is saved and the results carry no restaurant IDs.
+// @Tags admin
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "CSV file of restaurants"
+// @Param status formData string false "Status of the imported restaurants, draft by default" Enums(draft, pending, active)
+// @Param dry_run query bool false "Only validate the CSV file"
+// @security BearerAuth
+// @Success 200 {object} RestaurantImportResponse "The per-row import results."
+// @Failure 400 {object} ErrorResponse "Invalid CSV file, header row or status."
+// @Failure 500 {object} ErrorResponse "Internal server error while saving the restaurants, none of them were saved."
+// @Router /admin/restaurants/import [post]
+func ImportRestaurants(c *gin.Context) {
+	status := c.DefaultPostForm("status", models.RestaurantStatusDraft)
+	switch status {
+	case models.RestaurantStatusDraft, models.RestaurantStatusPending, models.RestaurantStatusActive:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status, expected draft, pending or active"})
+		return
+	}
+
+	file, _, err := c.Request.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing file!"})
+		return
+	}
+	defer file.Close()
+
+	rows, err := readRestaurantCSV(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	categories, err := categoryHandler.GetCategories()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching categories"})
+		return
+	}
+	categoriesBySlug := make(map[string]models.Category, len(categories))
+	for _, category := range categories {
+		categoriesBySlug[category.Slug] = category
+	}
+
+	response := RestaurantImportResponse{Results: []ImportResult{}}
+	var restaurants []models.Restaurant
+	// imported holds the index in the results of every restaurant to save
+	var imported []int
+	seen := map[string]int{}
+	for i, row := range rows {
+		// Rows are numbered like in a spreadsheet, after the header row
+		result := ImportResult{Row: i + 2, Name: row["name"]}
+		if isBlankRow(row) {
+			continue
+		}
+
+		restaurant, err := importedRestaurant(row, categoriesBySlug)
+		if err == nil {
+			key := strings.ToLower(restaurant.Name + "\x00" + restaurant.Address)
+			if first, ok := seen[key]; ok {
+				err = fmt.Errorf("same name and address as row %d", first)
+			} else {
+				seen[key] = result.Row
+			}
+		}
+		if err != nil {
+			result.Status = ImportFailed
+			result.Error = err.Error()
+			response.Failed++
+			response.Results = append(response.Results, result)
+			continue
+		}
+
+		restaurant.Status = status
+		result.Status = ImportImported
+		result.Warnings = restaurant.QualityWarnings()
+		restaurants = append(restaurants, *restaurant)
+		imported = append(imported, len(response.Results))
+		response.Results = append(response.Results, result)
+	}
+
+	response.Imported = len(restaurants)
+	if isDryRun(c) {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	if err := RestaurantHandler.ImportRestaurants(restaurants); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error saving restaurants"})
+		return
+	}
+	for i, index := range imported {
+		response.Results[index].RestaurantID = restaurants[i].ID
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// readRestaurantCSV returns every row of the CSV file after its header row by
+// column name. Blank rows are kept so row numbers stay intact.
+func readRestaurantCSV(file io.Reader) ([]map[string]string, error) {
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("CSV file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV file: %v", err)
+	}
+
+	columns := make([]string, len(header))
+	hasName := false
+	for i, name := range header {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		for _, column := range importColumns {
+			if strings.EqualFold(name, column) {
+				columns[i] = column
+			}
+		}
+		if columns[i] == "" {
+			return nil, fmt.Errorf("unknown column %q, expected %s", name, strings.Join(importColumns, ", "))
+		}
+		hasName = hasName || columns[i] == "name"
+	}
+	if !hasName {
+		return nil, errors.New("the header row must have a name column")
+	}
+
+	var rows []map[string]string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV file: %v", err)
+		}
+
+		row := make(map[string]string, len(columns))
+		for i, value := range record {
+			if i < len(columns) {
+				row[columns[i]] = strings.TrimSpace(value)
+			}
+		}
+		rows = append(rows, row)
+
+		if len(rows) > maxImportRows {
+			return nil, fmt.Errorf("CSV file has more than %d rows", maxImportRows)
+		}
+	}
+
+	if len(rows) == 0 {
+		return nil, errors.New("CSV file has no restaurants")
+	}
+	return rows, nil
+}
+
+func isBlankRow(row map[string]string) bool {
+	for _, value := range row {
+		if value != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// importedRestaurant builds the restaurant of a row of the CSV file,
+// failing with the first invalid value.
+func importedRestaurant(row map[string]string, categoriesBySlug map[string]models.Category) (*models.Restaurant, error) {
+	restaurant := models.Restaurant{
+		Name:        row["name"],
+		Address:     row["address"],
+		Telephone:   row["telephone"],
+		Description: row["description"],
+		Facebook:    row["facebook"],
+		Instagram:   row["instagram"],
+		OpenTime:    row["openTime"],
+		CloseTime:   row["closeTime"],
+		ImageURL:    row["imageUrl"],
+		Timezone:    row["timezone"],
+	}
+
+	telephone, err := models.NormalizeTelephone(restaurant.Telephone)
+	if err != nil {
+		return nil, err
+	}
+	restaurant.Telephone = telephone
+
+	if value := row["priceRange"]; value != "" {
+		priceRange, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, errors.New("priceRange must be a number from 1 (budget) to 4 (fine dining)")
+		}
+		restaurant.PriceRange = priceRange
+	}
+	for column, field := range map[string]**float64{"latitude": &restaurant.Latitude, "longitude": &restaurant.Longitude} {
+		if value := row[column]; value != "" {
+			coordinate, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s must be a number", column)
+			}
+			*field = &coordinate
+		}
+	}
+	for _, slug := range strings.Split(row["categories"], ",") {
+		if slug = strings.TrimSpace(slug); slug != "" {
+			category, ok := categoriesBySlug[slug]
+			if !ok {
+				return nil, fmt.Errorf("%w %q", models.ErrUnknownCategory, slug)
+			}
+			restaurant.Categories = append(restaurant.Categories, category)
+		}
+	}
+
+	if err := restaurant.Validate(); err != nil {
+		return nil, err
+	}
+	if restaurant.Timezone == "" {
+		restaurant.Timezone = models.DefaultTimezone
+	}
+	return &restaurant, nil
+}
@@ -0,0 +1,155 @@
+package v1
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"gorm.io/gorm"
+)
+
+type RestaurantStatusRequest struct {
+	Status string `json:"status" binding:"required" example:"active"`
+	// Reason is shown to the owner, e.g. why the restaurant was suspended
+	Reason string `json:"reason" example:"Missing business registration"`
+}
+
+type RestaurantPage struct {
+	Data []models.RestaurantResponse `json:"data"`
+	Pagination
+}
+
+// respondRestaurantStatus answers the change of the status of a restaurant,
+// recording it in the audit log.
+func respondRestaurantStatus(c *gin.Context, restaurant *models.Restaurant, previous string, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+		return
+	case errors.Is(err, models.ErrInvalidRestaurantTransition):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error changing restaurant status"})
+		return
+	}
+
+	actorID := c.MustGet("id").(uint)
+	entry := models.AuditEntry{
+		ActorID: actorID,
+		UserID:  actorID,
+		Action:  models.AuditActionRestaurantStatus,
+		Path:    c.Request.URL.Path,
+		Status:  http.StatusOK,
+		IP:      c.ClientIP(),
+		Details: map[string]interface{}{
+			"restaurantId": restaurant.ID,
+			"from":         previous,
+			"to":           restaurant.Status,
+			"reason":       restaurant.StatusReason,
+		},
+	}
+	if err := auditHandler.Record(&entry); err != nil {
+		log.Printf("Failed to record status change of restaurant %d by user %d: %v", restaurant.ID, actorID, err)
+	}
+
+	c.JSON(http.StatusOK, restaurant.Response())
+}
+
+// @Summary Submit a Restaurant for Approval
+// @Description Asks admins to approve a draft restaurant. It is listed publicly once an admin makes it active. Only the owner of the restaurant or an admin can submit it.
+// @Tags restaurants
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @security BearerAuth
+// @Success 200 {object} models.RestaurantResponse "The restaurant pending approval."
+// @Failure 400 {object} ErrorResponse "Invalid restaurant ID format."
+// @Failure 403 {object} ErrorResponse "The user does not manage the restaurant."
+// @Failure 404 {object} ErrorResponse "Restaurant not found with the specified ID."
+// @Failure 409 {object} ErrorResponse "The restaurant is not a draft."
+// @Failure 500 {object} ErrorResponse "Internal server error while submitting the restaurant."
+// @Router /restaurants/{id}/submit [post]
+func SubmitRestaurant(c *gin.Context) {
+	restaurantID, ok := managedRestaurantID(c)
+	if !ok {
+		return
+	}
+
+	restaurant, previous, err := RestaurantHandler.SetRestaurantStatus(restaurantID, models.RestaurantStatusPending, "", time.Now())
+	respondRestaurantStatus(c, restaurant, previous, err)
+}
+
+// @Summary Change the Status of a Restaurant
+// @Description Moves a restaurant through its approval lifecycle: draft to pending, pending to active or back to draft, active to hidden or suspended, hidden to active or suspended, and suspended to active. Only active restaurants are listed, searched and bookable. The reason is shown to the owner.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Param status body RestaurantStatusRequest true "New status and its reason"
+// @security BearerAuth
+// @Success 200 {object} models.RestaurantResponse "The restaurant with its new status."
+// @Failure 400 {object} ErrorResponse "Invalid restaurant ID, input format or status."
+// @Failure 404 {object} ErrorResponse "Restaurant not found with the specified ID."
+// @Failure 409 {object} ErrorResponse "The restaurant cannot move from its current status to the new one."
+// @Failure 500 {object} ErrorResponse "Internal server error while changing the status."
+// @Router /admin/restaurants/{id}/status [put]
+func UpdateRestaurantStatus(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant id"})
+		return
+	}
+
+	var request RestaurantStatusRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+	if !models.IsValidRestaurantStatus(request.Status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status, expected draft, pending, active, hidden or suspended"})
+		return
+	}
+
+	restaurant, previous, err := RestaurantHandler.SetRestaurantStatus(uint(idInt), request.Status, request.Reason, time.Now())
+	respondRestaurantStatus(c, restaurant, previous, err)
+}
+
+// @Summary Get Restaurants by Status
+// @Description Lists the restaurants with the status, e.g. the ones pending approval, the longest waiting first.
+// @Tags admin
+// @Produce json
+// @Param status query string false "Status of the restaurants, defaults to pending" Enums(draft, pending, active, hidden, suspended)
+// @Param page query int false "Page number, starting at 1"
+// @Param limit query int false "Restaurants per page, at most 100"
+// @security BearerAuth
+// @Success 200 {object} RestaurantPage "One page of restaurants."
+// @Failure 400 {object} ErrorResponse "Invalid status."
+// @Failure 500 {object} ErrorResponse "Internal server error while fetching the restaurants."
+// @Router /admin/restaurants [get]
+func GetRestaurantsByStatus(c *gin.Context) {
+	status := c.DefaultQuery("status", models.RestaurantStatusPending)
+	if !models.IsValidRestaurantStatus(status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status, expected draft, pending, active, hidden or suspended"})
+		return
+	}
+
+	page, limit := parsePagination(c)
+	restaurants, total, err := RestaurantHandler.GetRestaurantsByStatus(status, limit, (page-1)*limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching restaurants"})
+		return
+	}
+
+	responses := make([]models.RestaurantResponse, len(restaurants))
+	for i := range restaurants {
+		responses[i] = restaurants[i].Response()
+	}
+	c.JSON(http.StatusOK, RestaurantPage{
+		Data:       responses,
+		Pagination: Pagination{Page: page, Limit: limit, Total: total},
+	})
+}
@@ -0,0 +1,214 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"github.com/punchanabu/redrice-backend-go/utils"
+	"gorm.io/gorm"
+)
+
+// scheduledChangeInterval is how often due scheduled changes are published.
+const scheduledChangeInterval = time.Minute
+
+var scheduledChangeHandler *models.ScheduledChangeHandler
+
+func InitializedScheduledChangeHandler(db *gorm.DB) {
+	scheduledChangeHandler = models.NewScheduledChangeHandler(db)
+	utils.RunEveryExclusive(db, scheduledChangeInterval, "publish scheduled changes", func() error {
+		_, err := scheduledChangeHandler.PublishDue(time.Now())
+		return err
+	})
+}
+
+// ScheduledChangeRequest stages listing changes. Only the fields that are
+// present are changed, an empty string clears the field.
+type ScheduledChangeRequest struct {
+	PublishAt   time.Time `json:"publishAt" example:"2024-12-01T00:00:00Z"`
+	Name        *string   `json:"name" example:"RedRice Winter"`
+	Address     *string   `json:"address"`
+	Telephone   *string   `json:"telephone"`
+	OpenTime    *string   `json:"openTime" example:"11:00"`
+	CloseTime   *string   `json:"closeTime" example:"21:00"`
+	Instagram   *string   `json:"instagram"`
+	Facebook    *string   `json:"facebook"`
+	Description *string   `json:"description" example:"Seasonal winter menu"`
+	// PriceRange goes from 1 (budget) to 4 (fine dining), 0 clears it
+	PriceRange *int    `json:"priceRange" example:"2"`
+	ImageURL   *string `json:"imageUrl"`
+}
+
+func (r *ScheduledChangeRequest) columns() map[string]string {
+	columns := map[string]string{}
+	for column, value := range map[string]*string{
+		"name":        r.Name,
+		"address":     r.Address,
+		"telephone":   r.Telephone,
+		"open_time":   r.OpenTime,
+		"close_time":  r.CloseTime,
+		"instagram":   r.Instagram,
+		"facebook":    r.Facebook,
+		"description": r.Description,
+		"image_url":   r.ImageURL,
+	} {
+		if value != nil {
+			columns[column] = *value
+		}
+	}
+	if r.PriceRange != nil {
+		columns["price_range"] = ""
+		if *r.PriceRange != 0 {
+			columns["price_range"] = strconv.Itoa(*r.PriceRange)
+		}
+	}
+	return columns
+}
+
+// @Summary Schedule Restaurant Changes
+// @Description Stages changes to the listing of a restaurant, such as new opening hours or a seasonal description, to be published together at publishAt. Only the owner of the restaurant or an admin can schedule changes.
+// @Tags restaurants
+// @Accept json
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Param change body ScheduledChangeRequest true "Changes and publish time"
+// @security BearerAuth
+// @Success 201 {object} models.ScheduledChange "The scheduled change."
+// @Failure 400 {object} ErrorResponse "Invalid input, no changes, or publish time in the past."
+// @Failure 403 {object} ErrorResponse "The user does not manage this restaurant."
+// @Router /restaurants/{id}/scheduled-changes [post]
+func CreateScheduledChange(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant id"})
+		return
+	}
+	idUint := uint(idInt)
+
+	var request ScheduledChangeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	if !canManageRestaurant(c, idUint) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to manage this restaurant"})
+		return
+	}
+
+	if !request.PublishAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Publish time must be in the future"})
+		return
+	}
+
+	columns := request.columns()
+	if len(columns) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No changes to schedule"})
+		return
+	}
+
+	if request.PriceRange != nil && *request.PriceRange != 0 && !models.IsValidPriceRange(*request.PriceRange) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid priceRange, expected a number from 1 (budget) to 4 (fine dining), or 0 to clear it"})
+		return
+	}
+
+	for _, column := range []string{"open_time", "close_time"} {
+		if value, ok := columns[column]; ok {
+			if _, err := time.Parse("15:04", value); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Opening hours must be in HH:MM format"})
+				return
+			}
+		}
+	}
+
+	userID, _ := c.Get("id")
+	change := models.ScheduledChange{
+		RestaurantID: idUint,
+		CreatedBy:    userID.(uint),
+		Changes:      columns,
+		PublishAt:    request.PublishAt,
+	}
+	if err := scheduledChangeHandler.CreateScheduledChange(&change); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error scheduling change"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, change)
+}
+
+// @Summary Get Scheduled Restaurant Changes
+// @Description Lists the scheduled changes of a restaurant, including published and cancelled ones. Only the owner of the restaurant or an admin can see them.
+// @Tags restaurants
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @security BearerAuth
+// @Success 200 {array} models.ScheduledChange "The scheduled changes ordered by publish time."
+// @Failure 400 {object} ErrorResponse "Invalid restaurant ID."
+// @Failure 403 {object} ErrorResponse "The user does not manage this restaurant."
+// @Router /restaurants/{id}/scheduled-changes [get]
+func GetScheduledChanges(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant id"})
+		return
+	}
+
+	if !canManageRestaurant(c, uint(idInt)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to manage this restaurant"})
+		return
+	}
+
+	changes, err := scheduledChangeHandler.GetScheduledChangesByRestaurantID(uint(idInt))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching scheduled changes"})
+		return
+	}
+
+	c.JSON(http.StatusOK, changes)
+}
+
+// @Summary Cancel a Scheduled Restaurant Change
+// @Description Cancels a scheduled change that has not been published yet.
+// @Tags restaurants
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Param changeId path int true "Scheduled change ID" Format(int64)
+// @security BearerAuth
+// @Success 204 "Scheduled change cancelled."
+// @Failure 400 {object} ErrorResponse "Invalid ID or the change was already published."
+// @Failure 403 {object} ErrorResponse "The user does not manage this restaurant."
+// @Failure 404 {object} ErrorResponse "Scheduled change not found."
+// @Router /restaurants/{id}/scheduled-changes/{changeId} [delete]
+func CancelScheduledChange(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant id"})
+		return
+	}
+
+	changeInt, err := strconv.Atoi(c.Param("changeId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid scheduled change id"})
+		return
+	}
+
+	if !canManageRestaurant(c, uint(idInt)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to manage this restaurant"})
+		return
+	}
+
+	change, err := scheduledChangeHandler.GetScheduledChange(uint(changeInt))
+	if err != nil || change.RestaurantID != uint(idInt) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Scheduled change not found"})
+		return
+	}
+
+	if err := scheduledChangeHandler.CancelScheduledChange(change.ID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Only pending changes can be cancelled"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
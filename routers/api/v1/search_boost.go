@@ -0,0 +1,72 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/middleware"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"gorm.io/gorm"
+)
+
+var searchBoostHandler *models.SearchBoostHandler
+
+func InitializedSearchBoostHandler(db *gorm.DB) {
+	searchBoostHandler = models.NewSearchBoostHandler(db)
+}
+
+type SearchBoostRequest struct {
+	Weight *float64 `json:"weight" binding:"required" example:"0.2"`
+}
+
+// @Summary Get the Search Boosts
+// @Description Lists the boosts added to the rank of the restaurants that match their condition in GET /restaurants/search. For scale, the text rank plus the name similarity of a match lies between 0 and 2.
+// @Tags admin
+// @Produce json
+// @security BearerAuth
+// @Success 200 {array} models.SearchBoost "The search boosts."
+// @Failure 500 {object} ErrorResponse "Internal server error while fetching the boosts."
+// @Router /admin/search-boosts [get]
+func GetSearchBoosts(c *gin.Context) {
+	boosts, err := searchBoostHandler.GetBoosts()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching search boosts"})
+		return
+	}
+	c.JSON(http.StatusOK, boosts)
+}
+
+// @Summary Update a Search Boost
+// @Description Sets the weight of a boost, between -10 and 10. The next search uses it, no deploy needed. A weight of 0 turns the boost off, a negative one buries the restaurants.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param key path string true "Boost key" Enums(photos, recent_activity, verified)
+// @Param boost body SearchBoostRequest true "Weight"
+// @security BearerAuth
+// @Success 200 {array} models.SearchBoost "The search boosts after the change."
+// @Failure 400 {object} ErrorResponse "Invalid input format or weight."
+// @Failure 404 {object} ErrorResponse "Search boost not found."
+// @Failure 500 {object} ErrorResponse "Internal server error while saving the boost."
+// @Router /admin/search-boosts/{key} [put]
+func UpdateSearchBoost(c *gin.Context) {
+	var request SearchBoostRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format, weight is required"})
+		return
+	}
+
+	claims := c.MustGet("claims").(*middleware.Claims)
+	boosts, err := searchBoostHandler.SaveBoost(c.Param("key"), *request.Weight, claims.UserId)
+	switch {
+	case errors.Is(err, models.ErrUnknownSearchBoost):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Search boost not found"})
+	case errors.Is(err, models.ErrInvalidSearchBoost):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error saving search boost"})
+	default:
+		c.JSON(http.StatusOK, boosts)
+	}
+}
@@ -0,0 +1,129 @@
+package v1
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/config"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"github.com/punchanabu/redrice-backend-go/utils"
+	"gorm.io/gorm"
+)
+
+// slaCheckInterval is how often the performance of the restaurants is checked against the thresholds.
+const slaCheckInterval = time.Hour
+
+var slaHandler *models.SLAHandler
+
+func InitializedSLAHandler(db *gorm.DB) {
+	slaHandler = models.NewSLAHandler(db)
+	utils.RunEveryExclusive(db, slaCheckInterval, "evaluate restaurant SLAs", evaluateSLAAlerts)
+}
+
+type SLAAlertPage struct {
+	Data []models.SLAAlert `json:"data"`
+	Pagination
+}
+
+// slaAlertData describes the alert for the sla_alert email template.
+func slaAlertData(alert models.SLAAlert, thresholds models.SLAThresholds) map[string]string {
+	data := map[string]string{
+		"restaurant": alert.RestaurantName,
+		"days":       strconv.Itoa(int(thresholds.Window.Hours() / 24)),
+	}
+	switch alert.Metric {
+	case models.SLAMetricConfirmationLatency:
+		data["metric"] = "confirmation time"
+		data["summary"] = fmt.Sprintf("took %.0f minutes on average to confirm or decline a reservation, above the limit of %.0f minutes", alert.Value, alert.Threshold)
+	case models.SLAMetricDeclineRate:
+		data["metric"] = "decline rate"
+		data["summary"] = fmt.Sprintf("declined %.0f%% of the reservations it answered, above the limit of %.0f%%", alert.Value*100, alert.Threshold*100)
+	}
+	return data
+}
+
+// evaluateSLAAlerts opens alerts for the restaurants over the thresholds and
+// emails every admin about the new ones.
+func evaluateSLAAlerts() error {
+	thresholds := config.SLAThresholds()
+	opened, err := slaHandler.EvaluateAlerts(time.Now(), thresholds)
+	if err != nil || len(opened) == 0 {
+		return err
+	}
+
+	admins, err := userHandler.GetUsersByRole(models.RoleAdmin)
+	if err != nil {
+		return err
+	}
+	for _, alert := range opened {
+		data := slaAlertData(alert, thresholds)
+		for i := range admins {
+			data["name"] = admins[i].Name
+			if err := notify(&admins[i], models.NotificationChannelEmail, "", models.EmailTemplateSLAAlert, data); err != nil {
+				log.Printf("Failed to alert admin %d about restaurant %d: %v", admins[i].ID, alert.RestaurantID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// @Summary Get Restaurant SLA Alerts
+// @Description Lists the alerts raised when a restaurant took too long on average to confirm or decline reservations, or declined too many of them, over the rolling window. Only open alerts are listed unless all is true. Admins are emailed when an alert opens.
+// @Tags admin
+// @Produce json
+// @Param all query bool false "Include the resolved alerts"
+// @Param page query int false "Page number, starting at 1"
+// @Param limit query int false "Alerts per page, at most 100"
+// @security BearerAuth
+// @Success 200 {object} SLAAlertPage "One page of alerts, newest first."
+// @Failure 500 {object} ErrorResponse "Internal server error while fetching the alerts."
+// @Router /admin/sla-alerts [get]
+func GetSLAAlerts(c *gin.Context) {
+	page, limit := parsePagination(c)
+	alerts, total, err := slaHandler.GetAlerts(c.Query("all") == "true", limit, (page-1)*limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching SLA alerts"})
+		return
+	}
+
+	if alerts == nil {
+		alerts = []models.SLAAlert{}
+	}
+	c.JSON(http.StatusOK, SLAAlertPage{
+		Data:       alerts,
+		Pagination: Pagination{Page: page, Limit: limit, Total: total},
+	})
+}
+
+// @Summary Get Restaurant Performance
+// @Description Measures, per restaurant, how many reservations made in the period it confirmed or declined, the share it declined and the average time it took to answer. The period defaults to the current month.
+// @Tags admin
+// @Produce json
+// @Param from query string false "First day of the period in YYYY-MM-DD format"
+// @Param to query string false "Last day of the period in YYYY-MM-DD format"
+// @security BearerAuth
+// @Success 200 {array} models.RestaurantPerformance "The performance of every restaurant that answered a reservation."
+// @Failure 400 {object} ErrorResponse "Invalid period."
+// @Failure 500 {object} ErrorResponse "Internal server error while measuring the restaurants."
+// @Router /admin/restaurants/performance [get]
+func GetRestaurantPerformance(c *gin.Context) {
+	from, to, ok := parsePeriod(c)
+	if !ok {
+		return
+	}
+
+	performance, err := slaHandler.GetPerformance(from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error measuring restaurant performance"})
+		return
+	}
+
+	if performance == nil {
+		performance = []models.RestaurantPerformance{}
+	}
+	c.JSON(http.StatusOK, performance)
+}
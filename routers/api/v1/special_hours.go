@@ -0,0 +1,160 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"gorm.io/gorm"
+)
+
+var specialHoursHandler *models.SpecialHoursHandler
+
+func InitializedSpecialHoursHandler(db *gorm.DB) {
+	specialHoursHandler = models.NewSpecialHoursHandler(db)
+}
+
+type SpecialHoursRequest struct {
+	Closed    bool   `json:"closed" example:"false"`
+	OpenTime  string `json:"openTime" example:"10:00"`
+	CloseTime string `json:"closeTime" example:"02:00"`
+	Reason    string `json:"reason" example:"New Year's Eve"`
+}
+
+// @Summary Get Restaurant Special Hours
+// @Description Retrieves the upcoming days on which the restaurant is closed or opens with other hours than its regular ones, by date.
+// @Tags restaurants
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @security BearerAuth
+// @Success 200 {array} models.SpecialHours "The special hours from today on."
+// @Failure 400 {object} ErrorResponse "Invalid restaurant ID format."
+// @Failure 404 {object} ErrorResponse "Restaurant not found with the specified ID."
+// @Failure 500 {object} ErrorResponse "Internal server error while fetching the special hours."
+// @Router /restaurants/{id}/special-hours [get]
+func GetRestaurantSpecialHours(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant id"})
+		return
+	}
+
+	restaurant, err := RestaurantHandler.GetRestaurant(uint(idInt))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+		return
+	}
+
+	today := time.Now().In(restaurant.Location()).Format("2006-01-02")
+	hours, err := specialHoursHandler.GetSpecialHours(restaurant.ID, today)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching special hours"})
+		return
+	}
+
+	c.JSON(http.StatusOK, hours)
+}
+
+// @Summary Set Restaurant Special Hours
+// @Description Overrides the regular opening hours of the restaurant on one day in its time zone, either closing it for the day or opening it with other hours. Reservations cannot be made on closed days or outside the special hours, and the availability and open now filter follow the special hours. Setting the day again replaces its special hours. Only the owner of the restaurant or an admin can set them.
+// @Tags restaurants
+// @Accept json
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Param date path string true "Day in YYYY-MM-DD format"
+// @Param hours body SpecialHoursRequest true "Special hours, the times are ignored on closed days"
+// @security BearerAuth
+// @Success 200 {object} models.SpecialHours "The special hours of the day."
+// @Failure 400 {object} ErrorResponse "Invalid input format, date or opening hours."
+// @Failure 403 {object} ErrorResponse "The user does not manage this restaurant."
+// @Failure 500 {object} ErrorResponse "Internal server error while saving the special hours."
+// @Router /restaurants/{id}/special-hours/{date} [put]
+func SetRestaurantSpecialHours(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant id"})
+		return
+	}
+	idUint := uint(idInt)
+
+	date := c.Param("date")
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format, expected YYYY-MM-DD"})
+		return
+	}
+
+	var request SpecialHoursRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	hours := models.SpecialHours{
+		RestaurantID: idUint,
+		Date:         date,
+		Closed:       request.Closed,
+		Reason:       request.Reason,
+	}
+	if !request.Closed {
+		for _, value := range []string{request.OpenTime, request.CloseTime} {
+			if _, err := time.Parse("15:04", value); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Opening hours must be in HH:MM format"})
+				return
+			}
+		}
+		hours.OpenTime = request.OpenTime
+		hours.CloseTime = request.CloseTime
+	}
+
+	if !canManageRestaurant(c, idUint) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to manage this restaurant"})
+		return
+	}
+
+	if err := specialHoursHandler.SaveSpecialHours(&hours); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error saving special hours"})
+		return
+	}
+
+	c.JSON(http.StatusOK, hours)
+}
+
+// @Summary Delete Restaurant Special Hours
+// @Description Brings back the regular opening hours of the restaurant on the day. Only the owner of the restaurant or an admin can delete special hours.
+// @Tags restaurants
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Param date path string true "Day in YYYY-MM-DD format"
+// @security BearerAuth
+// @Success 200 "Special hours successfully deleted."
+// @Failure 400 {object} ErrorResponse "Invalid restaurant ID format."
+// @Failure 403 {object} ErrorResponse "The user does not manage this restaurant."
+// @Failure 404 {object} ErrorResponse "No special hours set on the day."
+// @Router /restaurants/{id}/special-hours/{date} [delete]
+func DeleteRestaurantSpecialHours(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant id"})
+		return
+	}
+	idUint := uint(idInt)
+
+	if !canManageRestaurant(c, idUint) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to manage this restaurant"})
+		return
+	}
+
+	deleted, err := specialHoursHandler.DeleteSpecialHours(idUint, c.Param("date"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error deleting special hours"})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No special hours set on this day"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Special hours deleted successfully"})
+}
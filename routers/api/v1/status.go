@@ -0,0 +1,285 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"github.com/punchanabu/redrice-backend-go/utils"
+	"gorm.io/gorm"
+)
+
+const (
+	ComponentOperational   = "operational"
+	ComponentDegraded      = "degraded"
+	ComponentDown          = "down"
+	ComponentNotConfigured = "not_configured"
+)
+
+// componentSeverity orders the component statuses for the overall status.
+var componentSeverity = map[string]int{ComponentNotConfigured: 0, ComponentOperational: 0, ComponentDegraded: 1, ComponentDown: 2}
+
+const (
+	statusCheckInterval = 30 * time.Second
+	statusCheckTimeout  = 3 * time.Second
+	// slowCheckThreshold marks components answering slower as degraded
+	slowCheckThreshold = time.Second
+	// incidentHistory is how long resolved incidents stay on the status page
+	incidentHistory = 14 * 24 * time.Hour
+)
+
+type ComponentStatus struct {
+	Name      string    `json:"name" example:"database"`
+	Status    string    `json:"status" enums:"operational,degraded,down,not_configured"`
+	LatencyMs int64     `json:"latencyMs" example:"12"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+type StatusResponse struct {
+	Status     string            `json:"status" enums:"operational,degraded,down"`
+	Components []ComponentStatus `json:"components"`
+	Incidents  []models.Incident `json:"incidents"`
+}
+
+var incidentHandler *models.IncidentHandler
+
+// errNotConfigured marks components that are not set up in this deployment.
+var errNotConfigured = errors.New("not configured")
+
+// componentStatuses holds the results of the latest checks, the status page
+// is public so requests never run the checks themselves.
+var componentStatuses struct {
+	sync.RWMutex
+	components []ComponentStatus
+}
+
+func InitializedStatusHandler(db *gorm.DB) {
+	incidentHandler = models.NewIncidentHandler(db)
+
+	checks := []struct {
+		name  string
+		check func(ctx context.Context) error
+	}{
+		{"database", func(ctx context.Context) error {
+			sqlDB, err := db.DB()
+			if err != nil {
+				return err
+			}
+			return sqlDB.PingContext(ctx)
+		}},
+		{"storage", func(ctx context.Context) error {
+			return utils.CheckStorage(ctx, "redrice")
+		}},
+		{"email", func(ctx context.Context) error {
+			return utils.CheckSMTP(statusCheckTimeout)
+		}},
+		// There is no payment provider yet, deposits are settled outside the platform
+		{"payments", func(ctx context.Context) error {
+			return errNotConfigured
+		}},
+	}
+
+	run := func() error {
+		components := make([]ComponentStatus, len(checks))
+		for i, c := range checks {
+			ctx, cancel := context.WithTimeout(context.Background(), statusCheckTimeout)
+			start := time.Now()
+			err := c.check(ctx)
+			cancel()
+
+			component := ComponentStatus{Name: c.name, LatencyMs: time.Since(start).Milliseconds(), CheckedAt: time.Now()}
+			switch {
+			case errors.Is(err, errNotConfigured) || errors.Is(err, utils.ErrEmailNotConfigured):
+				component.Status = ComponentNotConfigured
+			case err != nil:
+				log.Printf("Status check %s failed: %v", c.name, err)
+				component.Status = ComponentDown
+			case time.Since(start) > slowCheckThreshold:
+				component.Status = ComponentDegraded
+			default:
+				component.Status = ComponentOperational
+			}
+			components[i] = component
+		}
+
+		componentStatuses.Lock()
+		componentStatuses.components = components
+		componentStatuses.Unlock()
+		return nil
+	}
+
+	go run()
+	utils.RunEvery(statusCheckInterval, "status-checks", run)
+}
+
+// @Summary Get Service Status
+// @Description Returns the health of the database, storage, email and payments components from checks run every 30 seconds, together with unresolved incidents and those resolved in the last 14 days. The overall status is the worst component status, and at least degraded while an incident is unresolved.
+// @Tags status
+// @Produce json
+// @Success 200 {object} StatusResponse "The current status."
+// @Failure 500 {object} ErrorResponse "Internal server error while fetching the incidents."
+// @Router /status [get]
+func GetStatus(c *gin.Context) {
+	incidents, err := incidentHandler.GetRecentIncidents(time.Now().Add(-incidentHistory))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching incidents"})
+		return
+	}
+
+	componentStatuses.RLock()
+	components := append([]ComponentStatus{}, componentStatuses.components...)
+	componentStatuses.RUnlock()
+
+	overall := ComponentOperational
+	for _, component := range components {
+		if componentSeverity[component.Status] > componentSeverity[overall] {
+			overall = component.Status
+		}
+	}
+	for _, incident := range incidents {
+		if incident.ResolvedAt == nil && overall == ComponentOperational {
+			overall = ComponentDegraded
+		}
+	}
+
+	if incidents == nil {
+		incidents = []models.Incident{}
+	}
+
+	c.Header("Cache-Control", "public, max-age=30")
+	c.JSON(http.StatusOK, StatusResponse{Status: overall, Components: components, Incidents: incidents})
+}
+
+type IncidentRequest struct {
+	Title      string     `json:"title" example:"Emails are delayed"`
+	Message    string     `json:"message" example:"Our email provider is having issues, confirmations may arrive late."`
+	Status     string     `json:"status" example:"investigating"`
+	Impact     string     `json:"impact" example:"minor"`
+	Components []string   `json:"components" example:"email"`
+	StartedAt  *time.Time `json:"startedAt"`
+}
+
+// bindIncident validates the request and copies it onto the incident.
+func bindIncident(c *gin.Context, incident *models.Incident) bool {
+	var request IncidentRequest
+	if err := c.ShouldBindJSON(&request); err != nil || strings.TrimSpace(request.Title) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return false
+	}
+
+	if !models.IsValidIncidentStatus(request.Status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Status must be one of " + strings.Join(models.IncidentStatuses, ", ")})
+		return false
+	}
+	if !models.IsValidIncidentImpact(request.Impact) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Impact must be one of " + strings.Join(models.IncidentImpacts, ", ")})
+		return false
+	}
+
+	incident.Title = strings.TrimSpace(request.Title)
+	incident.Message = request.Message
+	incident.Status = request.Status
+	incident.Impact = request.Impact
+	incident.Components = request.Components
+	if incident.Components == nil {
+		incident.Components = []string{}
+	}
+	if request.StartedAt != nil {
+		incident.StartedAt = *request.StartedAt
+	}
+	return true
+}
+
+// @Summary Create an Incident
+// @Description Posts an incident to the public status page. Setting the status to resolved records when it was resolved.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param incident body IncidentRequest true "Incident details"
+// @security BearerAuth
+// @Success 201 {object} models.Incident "The created incident."
+// @Failure 400 {object} ErrorResponse "Invalid input, status or impact."
+// @Router /admin/incidents [post]
+func CreateIncident(c *gin.Context) {
+	var incident models.Incident
+	if !bindIncident(c, &incident) {
+		return
+	}
+
+	id, _ := c.Get("id")
+	incident.CreatedBy = id.(uint)
+	if err := incidentHandler.CreateIncident(&incident); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating incident"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, incident)
+}
+
+// @Summary Update an Incident
+// @Description Replaces the details of an incident, e.g. to post a new status. Setting the status to resolved records when it was resolved.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Incident ID" Format(int64)
+// @Param incident body IncidentRequest true "Incident details"
+// @security BearerAuth
+// @Success 200 {object} models.Incident "The updated incident."
+// @Failure 400 {object} ErrorResponse "Invalid input, incident ID, status or impact."
+// @Failure 404 {object} ErrorResponse "Incident not found."
+// @Router /admin/incidents/{id} [put]
+func UpdateIncident(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid incident id"})
+		return
+	}
+
+	incident, err := incidentHandler.GetIncident(uint(idInt))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Incident not found"})
+		return
+	}
+
+	if !bindIncident(c, incident) {
+		return
+	}
+
+	if err := incidentHandler.UpdateIncident(incident); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating incident"})
+		return
+	}
+
+	c.JSON(http.StatusOK, incident)
+}
+
+// @Summary Delete an Incident
+// @Description Removes an incident from the status page, e.g. one posted by mistake.
+// @Tags admin
+// @Produce json
+// @Param id path int true "Incident ID" Format(int64)
+// @security BearerAuth
+// @Success 204 "Incident deleted."
+// @Failure 400 {object} ErrorResponse "Invalid incident ID."
+// @Router /admin/incidents/{id} [delete]
+func DeleteIncident(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid incident id"})
+		return
+	}
+
+	if err := incidentHandler.DeleteIncident(uint(idInt)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error deleting incident"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
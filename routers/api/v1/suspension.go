@@ -0,0 +1,145 @@
+package v1
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/middleware"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"gorm.io/gorm"
+)
+
+type BanRequest struct {
+	Reason string `json:"reason" example:"Repeated no-shows"`
+	// Until is when the suspension ends, omit it to suspend indefinitely
+	Until *time.Time `json:"until" example:"2024-12-31T00:00:00+07:00"`
+}
+
+type BanResponse struct {
+	User                  models.UserResponse `json:"user"`
+	CancelledReservations int                 `json:"cancelledReservations" example:"2"`
+}
+
+func recordSuspensionAudit(c *gin.Context, userID uint, action string, details map[string]interface{}) {
+	claims := c.MustGet("claims").(*middleware.Claims)
+	entry := models.AuditEntry{
+		ActorID: claims.UserId,
+		UserID:  userID,
+		Action:  action,
+		Path:    c.Request.URL.Path,
+		Status:  http.StatusOK,
+		IP:      c.ClientIP(),
+		Details: details,
+	}
+	if err := auditHandler.Record(&entry); err != nil {
+		log.Printf("Failed to record %s of user %d by admin %d: %v", action, userID, claims.UserId, err)
+	}
+}
+
+// @Summary Ban a User
+// @Description Suspends the account until the given time or indefinitely. Requests and logins of the user are rejected with code ACCOUNT_SUSPENDED and the reason, and their pending reservations are cancelled. The suspension is recorded in the audit trail.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID" Format(int64)
+// @Param ban body BanRequest true "Reason and optional end of the suspension"
+// @security BearerAuth
+// @Success 200 {object} BanResponse "The suspended user and how many reservations were cancelled."
+// @Failure 400 {object} ErrorResponse "Invalid user ID, missing reason or an end in the past."
+// @Failure 404 {object} ErrorResponse "User not found with the specified ID."
+// @Failure 500 {object} ErrorResponse "Internal server error while suspending the user."
+// @Router /admin/users/{id}/ban [post]
+func BanUser(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user id"})
+		return
+	}
+	idUint := uint(idInt)
+
+	var request BanRequest
+	if err := c.ShouldBindJSON(&request); err != nil || strings.TrimSpace(request.Reason) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format, a reason is required"})
+		return
+	}
+	if request.Until != nil && !request.Until.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The end of the suspension must be in the future"})
+		return
+	}
+
+	claims := c.MustGet("claims").(*middleware.Claims)
+	if claims.UserId == idUint {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot ban yourself"})
+		return
+	}
+
+	cancelled, err := userHandler.Suspend(idUint, strings.TrimSpace(request.Reason), request.Until)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error suspending user"})
+		return
+	}
+
+	for _, reservation := range cancelled {
+		publishEvent(EventReservationUpdated, reservation.RestaurantID, gin.H{"reservationId": reservation.ID, "status": reservation.Status})
+	}
+	recordSuspensionAudit(c, idUint, models.AuditActionSuspend, map[string]interface{}{
+		"reason":                strings.TrimSpace(request.Reason),
+		"until":                 request.Until,
+		"cancelledReservations": len(cancelled),
+	})
+
+	user, err := userHandler.GetUser(idUint)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	c.JSON(http.StatusOK, BanResponse{User: user.Response(), CancelledReservations: len(cancelled)})
+}
+
+// @Summary Unban a User
+// @Description Lifts the suspension of the account. Reservations cancelled by the ban are not restored. The change is recorded in the audit trail.
+// @Tags admin
+// @Produce json
+// @Param id path int true "User ID" Format(int64)
+// @security BearerAuth
+// @Success 200 {object} models.UserResponse "The user, no longer suspended."
+// @Failure 400 {object} ErrorResponse "Invalid user ID format."
+// @Failure 404 {object} ErrorResponse "User not found with the specified ID."
+// @Failure 500 {object} ErrorResponse "Internal server error while lifting the suspension."
+// @Router /admin/users/{id}/unban [post]
+func UnbanUser(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user id"})
+		return
+	}
+	idUint := uint(idInt)
+
+	err = userHandler.Unsuspend(idUint)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error lifting suspension"})
+		return
+	}
+
+	recordSuspensionAudit(c, idUint, models.AuditActionUnsuspend, nil)
+
+	user, err := userHandler.GetUser(idUint)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	c.JSON(http.StatusOK, user.Response())
+}
@@ -0,0 +1,175 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"github.com/punchanabu/redrice-backend-go/utils"
+	"gorm.io/gorm"
+)
+
+var themeHandler *models.ThemeHandler
+
+func InitializedThemeHandler(db *gorm.DB) {
+	themeHandler = models.NewThemeHandler(db)
+}
+
+type ThemeRequest struct {
+	PrimaryColor    string `json:"primaryColor" example:"#C62828"`
+	SecondaryColor  string `json:"secondaryColor" example:"#FFFFFF"`
+	AccentColor     string `json:"accentColor" example:"#FFB300"`
+	BackgroundColor string `json:"backgroundColor" example:"#FAFAFA"`
+	TextColor       string `json:"textColor" example:"#212121"`
+}
+
+// @Summary Get Restaurant Theme
+// @Description Retrieves the branding of a restaurant, its logo and color palette, for the white-label web app. Restaurants without a custom theme get the default palette. No authentication is required.
+// @Tags restaurants
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Success 200 {object} models.RestaurantTheme "The theme of the restaurant."
+// @Failure 400 {object} ErrorResponse "Invalid restaurant ID."
+// @Failure 404 {object} ErrorResponse "Restaurant not found with the specified ID."
+// @Router /restaurants/{id}/theme [get]
+func GetRestaurantTheme(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant id"})
+		return
+	}
+
+	if _, err := RestaurantHandler.GetRestaurant(uint(idInt)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+		return
+	}
+
+	theme, err := themeHandler.GetTheme(uint(idInt))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching theme"})
+		return
+	}
+
+	c.JSON(http.StatusOK, theme)
+}
+
+// @Summary Update Restaurant Theme
+// @Description Sets the color palette of a restaurant. Colors use the #RRGGBB format, missing colors fall back to the default palette. Only the owner of the restaurant or an admin can change it.
+// @Tags restaurants
+// @Accept json
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Param theme body ThemeRequest true "Color palette"
+// @security BearerAuth
+// @Success 200 {object} models.RestaurantTheme "The updated theme."
+// @Failure 400 {object} ErrorResponse "Invalid input format or color."
+// @Failure 403 {object} ErrorResponse "The user does not manage this restaurant."
+// @Router /restaurants/{id}/theme [put]
+func UpdateRestaurantTheme(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant id"})
+		return
+	}
+	idUint := uint(idInt)
+
+	var request ThemeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	if !canManageRestaurant(c, idUint) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to manage this restaurant"})
+		return
+	}
+
+	theme := models.DefaultRestaurantTheme(idUint)
+	for _, color := range []struct {
+		value  string
+		target *string
+	}{
+		{request.PrimaryColor, &theme.PrimaryColor},
+		{request.SecondaryColor, &theme.SecondaryColor},
+		{request.AccentColor, &theme.AccentColor},
+		{request.BackgroundColor, &theme.BackgroundColor},
+		{request.TextColor, &theme.TextColor},
+	} {
+		if color.value == "" {
+			continue
+		}
+		if !models.IsValidHexColor(color.value) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid color " + color.value + ", colors must use the #RRGGBB format"})
+			return
+		}
+		*color.target = color.value
+	}
+
+	if err := themeHandler.SaveTheme(theme); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error saving theme"})
+		return
+	}
+
+	saved, err := themeHandler.GetTheme(idUint)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching theme"})
+		return
+	}
+	c.JSON(http.StatusOK, saved)
+}
+
+// @Summary Upload Restaurant Logo
+// @Description Uploads the logo shown by the white-label web app. Only the owner of the restaurant or an admin can upload it.
+// @Tags restaurants
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Param image formData file true "Logo image"
+// @security BearerAuth
+// @Success 200 {object} models.RestaurantTheme "The updated theme."
+// @Failure 400 {object} ErrorResponse "Invalid restaurant ID or missing image."
+// @Failure 403 {object} ErrorResponse "The user does not manage this restaurant."
+// @Failure 500 {object} ErrorResponse "Internal server error while uploading the logo."
+// @Router /restaurants/{id}/theme/logo [post]
+func UploadRestaurantLogo(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant id"})
+		return
+	}
+	idUint := uint(idInt)
+
+	if !canManageRestaurant(c, idUint) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to manage this restaurant"})
+		return
+	}
+
+	file, header, err := c.Request.FormFile("image")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing image!"})
+		return
+	}
+	defer file.Close()
+
+	logoUrl, err := utils.UploadImageToS3("redrice", file, header.Filename)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error uploading image!"})
+		return
+	}
+
+	// Keep the current palette, only the logo changes
+	theme, err := themeHandler.GetTheme(idUint)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching theme"})
+		return
+	}
+	theme.LogoURL = logoUrl
+
+	if err := themeHandler.SaveTheme(theme); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error saving theme"})
+		return
+	}
+
+	c.JSON(http.StatusOK, theme)
+}
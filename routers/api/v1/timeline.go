@@ -0,0 +1,57 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get Restaurant Reservation Timeline
+// @Description Lists, per table, the blocks of time the pending, confirmed and completed reservations occupy the table on a day of the restaurant's time zone, for a Gantt-style view. Reservations from the day before still running after midnight are included and blocks sharing time with another block of the same table are flagged. Table 0 holds the reservations without a table. Only the owner of the restaurant or an admin can see it.
+// @Tags restaurants
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Param date query string false "Day in YYYY-MM-DD format, defaults to today in the time zone of the restaurant"
+// @security BearerAuth
+// @Success 200 {object} models.ReservationTimeline "The occupancy of the tables."
+// @Failure 400 {object} ErrorResponse "Invalid restaurant ID or date format."
+// @Failure 403 {object} ErrorResponse "The user does not manage this restaurant."
+// @Failure 404 {object} ErrorResponse "Restaurant not found with the specified ID."
+// @Failure 500 {object} ErrorResponse "Internal server error while fetching the reservations."
+// @Router /restaurants/{id}/reservations/timeline [get]
+func GetReservationTimeline(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant id"})
+		return
+	}
+
+	if !canManageRestaurant(c, uint(idInt)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to manage this restaurant"})
+		return
+	}
+
+	restaurant, err := RestaurantHandler.GetRestaurant(uint(idInt))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+		return
+	}
+
+	date := c.Query("date")
+	if date == "" {
+		date = time.Now().In(restaurant.Location()).Format("2006-01-02")
+	} else if _, err := time.Parse("2006-01-02", date); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format, expected YYYY-MM-DD"})
+		return
+	}
+
+	timeline, err := reservationHandler.GetReservationTimeline(restaurant, date)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching reservations"})
+		return
+	}
+
+	c.JSON(http.StatusOK, timeline)
+}
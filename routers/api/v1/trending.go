@@ -0,0 +1,92 @@
+package v1
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"github.com/punchanabu/redrice-backend-go/utils"
+)
+
+// trendingInterval is how often the trending restaurants are ranked again.
+const trendingInterval = 15 * time.Minute
+
+var trendingRestaurants = models.NewTrendingCache()
+
+// InitializedTrending ranks the trending restaurants in the background and
+// again every trendingInterval. Every instance keeps its own ranking.
+func InitializedTrending() {
+	refresh := func() error {
+		now := time.Now()
+		scores, err := RestaurantHandler.ComputeTrending(now)
+		if err != nil {
+			return err
+		}
+		trendingRestaurants.Set(scores, now)
+		return nil
+	}
+
+	go func() {
+		if err := refresh(); err != nil {
+			log.Printf("Failed to rank trending restaurants: %v", err)
+		}
+	}()
+	utils.RunEvery(trendingInterval, "rank trending restaurants", refresh)
+}
+
+// @Summary Get Trending Restaurants
+// @Description Lists the restaurants trending over the last 7 days, highest score first. The score mixes the reservations made and the users viewing the restaurant page a day in the window, each relative to the most popular restaurant, with the rating. A user viewing the page again the same day is not counted twice. The ranking is computed every 15 minutes and only lists restaurants with reservations or views in the window. The X-Total-Count header holds the number of ranked restaurants, at most 100.
+// @Tags restaurants
+// @Produce json
+// @Param page query int false "Page number, starting at 1"
+// @Param limit query int false "Restaurants per page, at most 100"
+// @Param currency query string false "Currency to also show the prices in, e.g. USD" example(USD)
+// @Param Accept-Language header string false "Language to also write dates and amounts in, th or en, optionally with a calendar such as en-u-ca-buddhist" example(th-TH)
+// @security BearerAuth
+// @Success 200 {array} models.TrendingRestaurant "The trending restaurants with their score."
+// @Header 200 {integer} X-Total-Count "Number of ranked restaurants across all pages"
+// @Header 200 {string} Last-Modified "When the ranking was computed"
+// @Failure 400 {object} ErrorResponse "Unsupported currency."
+// @Failure 500 {object} ErrorResponse "Internal server error while fetching the restaurants."
+// @Router /restaurants/trending [get]
+func GetTrendingRestaurants(c *gin.Context) {
+	currency, ok := displayCurrency(c)
+	if !ok {
+		return
+	}
+
+	page, limit := parsePagination(c)
+	scores, total, updatedAt := trendingRestaurants.Page(limit, (page-1)*limit)
+	ids := make([]uint, len(scores))
+	for i, score := range scores {
+		ids[i] = score.RestaurantID
+	}
+	restaurants, err := RestaurantHandler.GetRestaurantsByIDs(ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching restaurants!"})
+		return
+	}
+
+	scoresByID := make(map[uint]models.TrendingScore, len(scores))
+	for _, score := range scores {
+		scoresByID[score.RestaurantID] = score
+	}
+	locale, localized := requestLocale(c)
+	responses := make([]models.TrendingRestaurant, len(restaurants))
+	for i := range restaurants {
+		responses[i] = models.TrendingRestaurant{RestaurantResponse: restaurants[i].Response(), Trending: scoresByID[restaurants[i].ID]}
+		exchangeRates.DisplayRestaurant(&responses[i].RestaurantResponse, currency)
+		if localized {
+			locale.LocalizeRestaurant(&responses[i].RestaurantResponse)
+		}
+	}
+
+	if !updatedAt.IsZero() {
+		c.Header("Last-Modified", updatedAt.UTC().Format(http.TimeFormat))
+	}
+	c.Header("X-Total-Count", strconv.Itoa(total))
+	c.JSON(http.StatusOK, responses)
+}
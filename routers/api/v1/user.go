@@ -1,11 +1,18 @@
 package v1
 
 import (
+	"errors"
+	"log"
+	"mime"
 	"net/http"
+	"path/filepath"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/middleware"
 	"github.com/punchanabu/redrice-backend-go/models"
+	"github.com/punchanabu/redrice-backend-go/utils"
 	"gorm.io/gorm"
 )
 
@@ -19,15 +26,32 @@ func InitializedUserHandler(db *gorm.DB) {
 	userHandler = models.NewUserHandler(db)
 }
 
+// respondAccountDetailsError writes the error of an email or telephone that
+// is taken or malformed and reports whether err was one.
+func respondAccountDetailsError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, models.ErrEmailTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": "Email already exists"})
+	case errors.Is(err, models.ErrTelephoneTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": "Telephone already exists"})
+	case errors.Is(err, models.ErrInvalidTelephone):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		return false
+	}
+	return true
+}
+
 // @Summary Get a Single User
-// @Description Retrieves details of a single user by their unique identifier.
+// @Description Retrieves details of a single user by their unique identifier. The email and telephone of a user who hides their contact are left out for everyone but the user.
 // @Tags user
 // @Produce json
 // @Param id path int true "User ID" Format(int64)
 // @security BearerAuth
-// @Success 200 {object} models.User "The details of the user including ID, name, email, telephone, and role."
+// @Success 200 {object} models.UserResponse "The details of the user including ID, name, email, telephone, and role."
 // @Failure 400 {object} ErrorResponse "Invalid user ID format."
 // @Failure 404 {object} ErrorResponse "User not found with the specified ID."
+// @Failure 500 {object} ErrorResponse "Internal server error while fetching the user."
 // @Router /users/{id} [get]
 func GetUser(c *gin.Context) {
 	idString := c.Param("id")
@@ -47,37 +71,62 @@ func GetUser(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, user)
+	response := user.Response()
+	if id, _ := c.Get("id"); id.(uint) != user.ID {
+		preferences, err := preferencesHandler.GetPreferences(user.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching user"})
+			return
+		}
+		if preferences.HideContact {
+			response.Email = ""
+			response.Telephone = ""
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
 }
 
 // @Summary Get All Users
-// @Description Retrieves a list of all users in the system.
+// @Description Retrieves one page of the users in the system, ordered by ID. Only admins can list the users. The X-Total-Count header holds the number of users matching the search across all pages. Deleted users are left out unless deleted is true, which lists only them.
 // @Tags user
 // @Produce json
+// @Param q query string false "Search in name, email and telephone"
+// @Param deleted query bool false "List the deleted users instead"
+// @Param page query int false "Page number, starting at 1"
+// @Param limit query int false "Users per page, at most 100"
 // @security BearerAuth
-// @Success 200 {array} models.User "An array of user objects."
+// @Success 200 {array} models.UserResponse "An array of user objects."
+// @Header 200 {integer} X-Total-Count "Number of users matching the search"
 // @Failure 500 {object} ErrorResponse "Internal server error while fetching users."
 // @Router /users [get]
 func GetUsers(c *gin.Context) {
-	users, err := userHandler.GetUsers()
+	page, limit := parsePagination(c)
 
+	users, total, err := userHandler.GetUsers(strings.TrimSpace(c.Query("q")), c.Query("deleted") == "true", limit, (page-1)*limit)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching users!"})
 		return
 	}
 
-	c.JSON(http.StatusOK, users)
+	if users == nil {
+		users = []models.User{}
+	}
+
+	c.Header("X-Total-Count", strconv.FormatInt(total, 10))
+	c.JSON(http.StatusOK, models.UserResponses(users))
 }
 
 // @Summary Create a New User
-// @Description Adds a new user to the system with the provided details.
+// @Description Adds a new user to the system with the provided details. The telephone is stored in the E.164 format, numbers without a country code are taken to be Thai.
 // @Tags user
 // @Accept json
 // @Produce json
 // @Param user body models.User true "User Registration Details"
 // @security BearerAuth
-// @Success 201 {object} models.User "The created user's details, including their unique identifier."
-// @Failure 400 {object} ErrorResponse "Invalid input format for user details."
+// @Success 201 {object} models.UserResponse "The created user's details, including their unique identifier."
+// @Failure 400 {object} ErrorResponse "Invalid input format for user details, an invalid telephone, or the password does not meet the requirements."
+// @Failure 409 {object} ErrorResponse "The email or telephone belongs to another user."
 // @Failure 500 {object} ErrorResponse "Internal server error while creating the user."
 // @Router /users [post]
 func CreateUser(c *gin.Context) {
@@ -88,35 +137,38 @@ func CreateUser(c *gin.Context) {
 		return
 	}
 
-	// Check if email or telephone already exists
-	existingUser, err := userHandler.GetUserByEmail(user.Email)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking for duplicate user"})
-		return
-	}
-	if existingUser != nil {
-		c.JSON(http.StatusConflict, gin.H{"error": "Email or telephone already exists"})
-		return
-	}
-
 	if err := userHandler.CreateUser(&user); err != nil {
+		var policyErr *utils.PasswordPolicyError
+		if errors.As(err, &policyErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Password does not meet the requirements", "fields": policyErr.Fields()})
+			return
+		}
+		if errors.Is(err, models.ErrInvalidRole) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role, expected one of " + strings.Join(models.Roles, ", ")})
+			return
+		}
+		if respondAccountDetailsError(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating user!"})
 		return
 	}
 
-	c.JSON(http.StatusCreated, user)
+	user.Warnings = user.QualityWarnings()
+	c.JSON(http.StatusCreated, user.Response())
 }
 
 // @Summary Update a User
-// @Description Updates the details of an existing user identified by their ID.
+// @Description Updates the details of an existing user identified by their ID. The email cannot be changed here, users change it with POST /me/email and the confirmation sent to the new address. The role and restaurant are changed with PUT /users/{id}/role.
 // @Tags user
 // @Accept json
 // @Produce json
 // @Param id path int true "User ID" Format(int64)
 // @Param user body models.User true "Updated User Details"
 // @security BearerAuth
-// @Success 200 {object} models.User "The updated user's details."
-// @Failure 400 {object} ErrorResponse "Invalid input format for user details or invalid user ID."
+// @Success 200 {object} models.UserResponse "The updated user's details."
+// @Failure 400 {object} ErrorResponse "Invalid input format for user details, invalid telephone or invalid user ID."
+// @Failure 409 {object} ErrorResponse "The telephone belongs to another user."
 // @Failure 500 {object} ErrorResponse "Internal server error while updating the user."
 // @Router /users/{id} [put]
 func UpdateUser(c *gin.Context) {
@@ -136,22 +188,182 @@ func UpdateUser(c *gin.Context) {
 	}
 
 	err = userHandler.UpdateUser(idUint, &user)
+	if respondAccountDetailsError(c, err) {
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating user"})
 		return
 	}
+	middleware.RecordActivity(c, idUint, models.ActivityProfileUpdated, nil)
+
+	updated, err := userHandler.GetUser(idUint)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching user"})
+		return
+	}
+	updated.Warnings = updated.QualityWarnings()
+	c.JSON(http.StatusOK, updated.Response())
+}
+
+type UserPatchRequest struct {
+	Name      *string `json:"name" example:"John Doe"`
+	Telephone *string `json:"telephone" example:"0812345678"`
+}
 
-	c.JSON(http.StatusOK, user)
+// fields lists the JSON names of the fields present in the request.
+func (r UserPatchRequest) fields() []string {
+	fields := []string{}
+	if r.Name != nil {
+		fields = append(fields, "name")
+	}
+	if r.Telephone != nil {
+		fields = append(fields, "telephone")
+	}
+	return fields
+}
+
+// @Summary Partially Update a User
+// @Description Changes only the fields present in the body, omitted fields keep their value. Users can change their own name and telephone, a new telephone has to be verified again. Only admins can update other users, the role and restaurant are changed with PUT /users/{id}/role.
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID" Format(int64)
+// @Param user body UserPatchRequest true "Fields to change"
+// @security BearerAuth
+// @Success 200 {object} models.UserResponse "The updated user's details."
+// @Failure 400 {object} ErrorResponse "Invalid input format, user ID or telephone."
+// @Failure 403 {object} ErrorResponse "The user cannot change this user."
+// @Failure 404 {object} ErrorResponse "User not found with the specified ID."
+// @Failure 409 {object} ErrorResponse "The telephone belongs to another user."
+// @Router /users/{id} [patch]
+func PatchUser(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user id"})
+		return
+	}
+	idUint := uint(idInt)
+
+	var request UserPatchRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	if request.Name != nil && strings.TrimSpace(*request.Name) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Name cannot be empty"})
+		return
+	}
+
+	claims := c.MustGet("claims").(*middleware.Claims)
+	isAdmin := models.HasPermission(claims.Role, models.PermissionAdminister)
+	if !isAdmin && claims.UserId != idUint {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are not allowed to change this user"})
+		return
+	}
+
+	user, err := userHandler.PatchUser(idUint, models.UserPatch{Name: request.Name, Telephone: request.Telephone})
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	case respondAccountDetailsError(c, err):
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error updating user"})
+	default:
+		middleware.RecordActivity(c, idUint, models.ActivityProfileUpdated, map[string]interface{}{"fields": request.fields()})
+		user.Warnings = user.QualityWarnings()
+		c.JSON(http.StatusOK, user.Response())
+	}
+}
+
+type RoleRequest struct {
+	Role         string `json:"role" example:"restaurant_owner" enums:"admin,restaurant_owner,user"`
+	RestaurantId uint   `json:"restaurant_id" example:"3"`
+}
+
+// @Summary Change the Role of a User
+// @Description Promotes or demotes a user. Restaurant owners must be assigned an existing restaurant, users and admins are unlinked from their restaurant. Admins cannot change their own role and the last admin cannot be demoted. The user is signed out everywhere so the new role applies right away, and the change is recorded in the audit trail.
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID" Format(int64)
+// @Param role body RoleRequest true "New role"
+// @security BearerAuth
+// @Success 200 {object} models.UserResponse "The user with their new role."
+// @Failure 400 {object} ErrorResponse "Invalid input, role or transition."
+// @Failure 404 {object} ErrorResponse "User or restaurant not found."
+// @Failure 500 {object} ErrorResponse "Internal server error while changing the role."
+// @Router /users/{id}/role [put]
+func ChangeUserRole(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user id"})
+		return
+	}
+	idUint := uint(idInt)
+
+	var request RoleRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	claims := c.MustGet("claims").(*middleware.Claims)
+	if claims.UserId == idUint {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot change your own role"})
+		return
+	}
+
+	change, err := userHandler.ChangeRole(idUint, request.Role, request.RestaurantId)
+	switch {
+	case errors.Is(err, models.ErrInvalidRole):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role, expected one of " + strings.Join(models.Roles, ", ")})
+		return
+	case errors.Is(err, models.ErrRestaurantRequired), errors.Is(err, models.ErrLastAdmin):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	case errors.Is(err, models.ErrRestaurantNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Restaurant not found"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error changing role"})
+		return
+	}
+
+	entry := models.AuditEntry{
+		ActorID: claims.UserId,
+		UserID:  idUint,
+		Action:  models.AuditActionChangeRole,
+		Path:    c.Request.URL.Path,
+		Status:  http.StatusOK,
+		IP:      c.ClientIP(),
+		Details: map[string]interface{}{
+			"previousRole":         change.PreviousRole,
+			"previousRestaurantId": change.PreviousRestaurantID,
+			"role":                 change.User.Role,
+			"restaurantId":         change.User.RestaurantId,
+		},
+	}
+	if err := auditHandler.Record(&entry); err != nil {
+		log.Printf("Failed to record role change of user %d by admin %d: %v", idUint, claims.UserId, err)
+	}
+
+	c.JSON(http.StatusOK, change.User.Response())
 }
 
 // @Summary Delete a User
-// @Description Removes a user from the system by their unique identifier.
+// @Description Removes a user from the system by their unique identifier. The user is soft deleted: their reservations and comments are kept and the account can be restored with /users/{id}/restore.
 // @Tags user
 // @Produce json
 // @Param id path int true "User ID" Format(int64)
 // @security BearerAuth
 // @Success 204 "User successfully deleted, no content to return."
 // @Failure 400 {object} ErrorResponse "Invalid user ID format."
+// @Failure 404 {object} ErrorResponse "User not found with the specified ID."
 // @Failure 500 {object} ErrorResponse "Internal server error while deleting the user."
 // @Router /users/{id} [delete]
 func DeleteUser(c *gin.Context) {
@@ -164,6 +376,10 @@ func DeleteUser(c *gin.Context) {
 	idUint := uint(idInt)
 
 	err = userHandler.DeleteUser(idUint)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error deleting user"})
 		return
@@ -172,12 +388,44 @@ func DeleteUser(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+// @Summary Restore a User
+// @Description Brings back a deleted user with their reservations and comments. A deletion the user scheduled themselves is cancelled as well.
+// @Tags user
+// @Produce json
+// @Param id path int true "User ID" Format(int64)
+// @security BearerAuth
+// @Success 200 {object} models.UserResponse "The restored user's details."
+// @Failure 400 {object} ErrorResponse "Invalid user ID format."
+// @Failure 404 {object} ErrorResponse "User not found with the specified ID."
+// @Failure 409 {object} ErrorResponse "The user is not deleted."
+// @Failure 500 {object} ErrorResponse "Internal server error while restoring the user."
+// @Router /users/{id}/restore [post]
+func RestoreUser(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user id"})
+		return
+	}
+
+	user, err := userHandler.RestoreUser(uint(idInt))
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	case errors.Is(err, models.ErrUserNotDeleted):
+		c.JSON(http.StatusConflict, gin.H{"error": "User is not deleted"})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error restoring user"})
+	default:
+		c.JSON(http.StatusOK, user.Response())
+	}
+}
+
 // @Summary Get my profile
 // @Description Retrieves the details of the currently authenticated user.
 // @Tags user
 // @Produce json
 // @security BearerAuth
-// @Success 200 {object} models.User "The details of the currently authenticated user."
+// @Success 200 {object} models.UserResponse "The details of the currently authenticated user."
 // @Failure 404 {object} ErrorResponse "User not found."
 // @Router /me [get]
 func GetMe(c *gin.Context) {
@@ -187,5 +435,131 @@ func GetMe(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
 		return
 	}
-	c.JSON(http.StatusOK, user)
+	c.JSON(http.StatusOK, user.Response())
+}
+
+// @Summary Upload my profile picture
+// @Description Replaces the profile picture of the currently authenticated user. The previous picture is deleted from storage.
+// @Tags user
+// @Accept multipart/form-data
+// @Produce json
+// @Param image formData file true "Profile picture"
+// @security BearerAuth
+// @Success 200 {object} models.UserResponse "The updated user."
+// @Failure 400 {object} ErrorResponse "Missing image or a file that is not an image."
+// @Failure 500 {object} ErrorResponse "Internal server error while uploading the image."
+// @Router /me/avatar [put]
+func UploadMyAvatar(c *gin.Context) {
+	file, header, err := c.Request.FormFile("image")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing image!"})
+		return
+	}
+	defer file.Close()
+
+	if !strings.HasPrefix(mime.TypeByExtension(filepath.Ext(header.Filename)), "image/") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The file must be an image"})
+		return
+	}
+
+	imageUrl, err := utils.UploadImageToS3("redrice", file, header.Filename)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error uploading image!"})
+		return
+	}
+
+	id, _ := c.Get("id")
+	previous, err := userHandler.SetAvatar(id.(uint), imageUrl)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error saving profile picture"})
+		return
+	}
+
+	middleware.RecordActivity(c, id.(uint), models.ActivityProfileUpdated, map[string]interface{}{"fields": []string{"imageUrl"}})
+
+	// The old picture is not referenced anymore
+	deleteImageObjects(previous)
+
+	user, err := userHandler.GetUser(id.(uint))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	c.JSON(http.StatusOK, user.Response())
+}
+
+type MergeUsersRequest struct {
+	TargetID uint `json:"targetId" example:"1"`
+	SourceID uint `json:"sourceId" example:"2"`
+}
+
+// @Summary Merge Two User Accounts
+// @Description Moves the reservations, comments, favorites, follows, preferences, consents, activity, restaurant claims and notifications of the source user into the target user and removes the source account. Preferences and consent decisions the target already made are kept. Empty profile fields of the target are filled from the source, and the Google, Facebook and Apple logins of the source sign in to the target unless it is already linked to that provider. When the source has the more privileged role the target is given it like a role change: the target is signed out everywhere and the change is recorded in the audit trail.
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param merge body MergeUsersRequest true "Target and source user IDs"
+// @security BearerAuth
+// @Success 200 {object} models.UserResponse "The merged user account."
+// @Failure 400 {object} ErrorResponse "Invalid input format or the users cannot be merged."
+// @Failure 404 {object} ErrorResponse "The target or source user was not found."
+// @Failure 500 {object} ErrorResponse "Internal server error while merging the users."
+// @Router /users/merge [post]
+func MergeUsers(c *gin.Context) {
+	var request MergeUsersRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	if request.TargetID == 0 || request.SourceID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "targetId and sourceId are required"})
+		return
+	}
+
+	if request.TargetID == request.SourceID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot merge a user into itself"})
+		return
+	}
+
+	user, roleChange, err := userHandler.MergeUsers(request.TargetID, request.SourceID)
+	switch {
+	case errors.Is(err, models.ErrRestaurantRequired), errors.Is(err, models.ErrRestaurantNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The role of the source user cannot be given to the target: " + err.Error()})
+		return
+	case errors.Is(err, models.ErrMergeSelf):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot merge a user into itself"})
+		return
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	case err != nil:
+		log.Printf("Failed to merge user %d into %d: %v", request.SourceID, request.TargetID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error merging users"})
+		return
+	}
+
+	if roleChange != nil {
+		claims := c.MustGet("claims").(*middleware.Claims)
+		entry := models.AuditEntry{
+			ActorID: claims.UserId,
+			UserID:  user.ID,
+			Action:  models.AuditActionChangeRole,
+			Path:    c.Request.URL.Path,
+			Status:  http.StatusOK,
+			IP:      c.ClientIP(),
+			Details: map[string]interface{}{
+				"previousRole":         roleChange.PreviousRole,
+				"previousRestaurantId": roleChange.PreviousRestaurantID,
+				"role":                 user.Role,
+				"restaurantId":         user.RestaurantId,
+				"mergedUserId":         request.SourceID,
+			},
+		}
+		if err := auditHandler.Record(&entry); err != nil {
+			log.Printf("Failed to record role change of user %d by admin %d: %v", user.ID, claims.UserId, err)
+		}
+	}
+
+	c.JSON(http.StatusOK, user.Response())
 }
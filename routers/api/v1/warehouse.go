@@ -0,0 +1,83 @@
+package v1
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/models"
+	"github.com/punchanabu/redrice-backend-go/utils"
+	"gorm.io/gorm"
+)
+
+const warehouseBucket = "redrice"
+
+// warehouseExportInterval is how often the job looks for complete days to
+// export, so a day is exported within the hour after it ends.
+const warehouseExportInterval = time.Hour
+
+var warehouseHandler *models.WarehouseHandler
+
+func InitializedWarehouseHandler(db *gorm.DB) {
+	warehouseHandler = models.NewWarehouseHandler(db)
+	utils.RunEveryExclusive(db, warehouseExportInterval, "export warehouse datasets", exportWarehouseDays)
+}
+
+// exportWarehouseDays exports the complete days not exported yet.
+func exportWarehouseDays() error {
+	days, err := warehouseHandler.DaysToExport(time.Now())
+	if err != nil {
+		return err
+	}
+	for _, day := range days {
+		export, err := warehouseHandler.ExportDay(warehouseBucket, day)
+		if err != nil {
+			// Later days wait so the exports stay contiguous
+			return err
+		}
+		log.Printf("Exported warehouse datasets of %s", export.Date)
+	}
+	return nil
+}
+
+type WarehouseManifest struct {
+	SchemaVersion int                       `json:"schemaVersion" example:"1"`
+	Bucket        string                    `json:"bucket" example:"redrice"`
+	Datasets      []models.WarehouseDataset `json:"datasets"`
+	Exports       []models.WarehouseExport  `json:"exports"`
+}
+
+// @Summary Get the Data Warehouse Manifest
+// @Description Describes the datasets exported every night for the analytics team, with the columns of the current schema version, and lists the CSV files exported for the days of the period. Days are in UTC and files are partitioned by date under warehouse/v<schemaVersion>/<dataset>/date=<day>/. The period defaults to the current month.
+// @Tags admin
+// @Produce json
+// @Param from query string false "First day of the period in YYYY-MM-DD format"
+// @Param to query string false "Last day of the period in YYYY-MM-DD format"
+// @security BearerAuth
+// @Success 200 {object} WarehouseManifest "The datasets and the files exported in the period."
+// @Failure 400 {object} ErrorResponse "Invalid period."
+// @Failure 500 {object} ErrorResponse "Internal server error while fetching the exports."
+// @Router /admin/warehouse/manifest [get]
+func GetWarehouseManifest(c *gin.Context) {
+	from, to, ok := parsePeriod(c)
+	if !ok {
+		return
+	}
+
+	exports, err := warehouseHandler.GetExports(from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching warehouse exports"})
+		return
+	}
+
+	if exports == nil {
+		exports = []models.WarehouseExport{}
+	}
+	c.JSON(http.StatusOK, WarehouseManifest{
+		SchemaVersion: models.WarehouseSchemaVersion,
+		Bucket:        warehouseBucket,
+		Datasets:      models.WarehouseDatasets,
+		Exports:       exports,
+	})
+}
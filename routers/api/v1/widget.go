@@ -0,0 +1,120 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/punchanabu/redrice-backend-go/models"
+)
+
+type WidgetReservationRequest struct {
+	DateTime  time.Time `json:"dateTime" example:"2024-12-01T19:00:00+07:00"`
+	ExitTime  time.Time `json:"exitTime" example:"2024-12-01T21:00:00+07:00"`
+	TableNum  int       `json:"tableNum"`
+	Name      string    `json:"name" example:"John Doe"`
+	Email     string    `json:"email" example:"john.doe@example.com"`
+	Telephone string    `json:"telephone" example:"0812345678"`
+}
+
+// WidgetReservationResponse only tells the guest what was booked, the widget
+// runs on a third-party page.
+type WidgetReservationResponse struct {
+	ID       uint               `json:"id"`
+	DateTime time.Time          `json:"dateTime"`
+	ExitTime time.Time          `json:"exitTime"`
+	Status   string             `json:"status" example:"pending"`
+	Deposit  models.Money       `json:"deposit"`
+	Local    *models.LocalTimes `json:"local"`
+}
+
+// @Summary Get Restaurant Availability for the Widget
+// @Description Lists the bookable time slots of the day for the "Book now" widget embedded on the website of the restaurant. Needs an API key with the widget:book scope issued for the restaurant, sent from one of its allowed origins.
+// @Tags widget
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Param date query string false "Day to list in YYYY-MM-DD format, defaults to today"
+// @Param X-API-Key header string true "Widget API key"
+// @Success 200 {array} models.Slot "The time slots of the day."
+// @Failure 400 {object} ErrorResponse "Invalid restaurant ID or date format."
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key."
+// @Failure 403 {object} ErrorResponse "The key is not allowed for this restaurant or website."
+// @Router /widget/restaurants/{id}/availability [get]
+func GetWidgetAvailability(c *gin.Context) {
+	GetRestaurantAvailability(c)
+}
+
+// @Summary Book from the Widget
+// @Description Books a table from the "Book now" widget embedded on the website of the restaurant, without signing in. The reservation is filed under a guest record of the email, never under an account even with the same email, and bookings with the email of a suspended or deleted account are refused. The booking policy and reservation limit of customers apply. Needs an API key with the widget:book scope issued for the restaurant, sent from one of its allowed origins.
+// @Tags widget
+// @Accept json
+// @Produce json
+// @Param id path int true "Restaurant ID" Format(int64)
+// @Param reservation body WidgetReservationRequest true "Reservation and guest details"
+// @Param X-API-Key header string true "Widget API key"
+// @Param X-Captcha-Token header string false "reCAPTCHA or Turnstile token, required when CAPTCHA is enabled"
+// @Success 201 {object} WidgetReservationResponse "The booked reservation."
+// @Failure 400 {object} ErrorResponse "Invalid input, failed CAPTCHA or a reservation time outside the restaurant's booking window (see code)."
+// @Failure 401 {object} ErrorResponse "Missing or invalid API key."
+// @Failure 403 {object} ErrorResponse "The key is not allowed for this restaurant or website, the email belongs to a suspended or deleted account, or the guest has too many reservations."
+// @Router /widget/restaurants/{id}/reservations [post]
+func CreateWidgetReservation(c *gin.Context) {
+	idInt, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid restaurant id"})
+		return
+	}
+
+	var request WidgetReservationRequest
+	if err := c.ShouldBindJSON(&request); err != nil || request.DateTime.IsZero() || strings.TrimSpace(request.Name) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	address, err := mail.ParseAddress(request.Email)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid email address"})
+		return
+	}
+
+	guest, err := userHandler.FindOrCreateGuest(strings.TrimSpace(request.Name), strings.ToLower(address.Address), strings.TrimSpace(request.Telephone))
+	if errors.Is(err, models.ErrGuestBlocked) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "This email cannot book, please contact the restaurant"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating guest record"})
+		return
+	}
+	if !models.HasPermission(guest.Role, models.PermissionBook) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "This email cannot book, please contact the restaurant"})
+		return
+	}
+
+	reservation := models.Reservation{
+		DateTime:     request.DateTime,
+		ExitTime:     request.ExitTime,
+		TableNum:     request.TableNum,
+		RestaurantID: uint(idInt),
+	}
+	if !reservation.ExitTime.After(reservation.DateTime) {
+		reservation.ExitTime = reservation.DateTime.Add(models.SlotLength)
+	}
+
+	if !placeReservation(c, guest.ID, guest.Role, &reservation, false) {
+		return
+	}
+
+	c.JSON(http.StatusCreated, WidgetReservationResponse{
+		ID:       reservation.ID,
+		DateTime: reservation.DateTime,
+		ExitTime: reservation.ExitTime,
+		Status:   reservation.Status,
+		Deposit:  reservation.Deposit,
+		Local:    reservation.Local,
+	})
+}
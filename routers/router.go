@@ -1,66 +1,266 @@
 package routers
 
 import (
+	"log"
+	"net/http"
+
 	"github.com/gin-gonic/gin"
 	"github.com/punchanabu/redrice-backend-go/config"
 	docs "github.com/punchanabu/redrice-backend-go/docs"
 	"github.com/punchanabu/redrice-backend-go/middleware"
+	"github.com/punchanabu/redrice-backend-go/models"
 	"github.com/punchanabu/redrice-backend-go/routers/api"
 	v1 "github.com/punchanabu/redrice-backend-go/routers/api/v1"
 	swaggerfiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
+// openAPISpec serves the raw OpenAPI document for tooling such as client generators.
+func openAPISpec(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json; charset=utf-8", []byte(docs.SwaggerInfo.ReadDoc()))
+}
+
 // @securityDefinitions.apikey BearerAuth
 // @in header
 // @name Authorization
 // @description Type "Bearer" followed by a space and JWT token.
 // @security BearerAuth
 func UseRouter() *gin.Engine {
+	return newRouter(config.RateLimit())
+}
+
+func newRouter(limits config.RateLimitConfig) *gin.Engine {
 	r := gin.New()
+	if err := r.SetTrustedProxies(config.TrustedProxies()); err != nil {
+		log.Printf("Invalid TRUSTED_PROXIES, trusting no proxy: %v", err)
+		r.SetTrustedProxies(nil)
+	}
 	r.Use(gin.Logger())
 	r.Use(config.CORSMiddleware())
+	if limits.Enabled {
+		r.Use(middleware.RateLimit(limits))
+	}
+	if chaos := config.Chaos(); chaos.Enabled {
+		r.Use(middleware.FaultInjection(chaos))
+	}
 	docs.SwaggerInfo.Title = "RedRice API"
 	docs.SwaggerInfo.Description = "This is a server for managing restaurant with RedRice API build with Go Gin and Gorm"
 	docs.SwaggerInfo.BasePath = "/api/v1"
 
-	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
+	switch config.SwaggerMode() {
+	case config.SwaggerPublic:
+		r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
+		r.GET("/openapi.json", openAPISpec)
+	case config.SwaggerAdmin:
+		r.GET("/swagger/*any", middleware.Admin(), ginSwagger.WrapHandler(swaggerfiles.Handler))
+		r.GET("/openapi.json", middleware.Admin(), openAPISpec)
+	}
+
+	r.GET("/.well-known/jwks.json", api.JWKS)
 
 	apiv1 := r.Group("/api/v1")
 	auth := apiv1.Group("/auth")
-	auth.POST("/signin", api.Login)
-	auth.POST("/register", api.Register)
-	apiv1.Use(middleware.Auth())
+	auth.POST("/signin", middleware.Captcha(), api.Login)
+	auth.POST("/register", middleware.Captcha(), api.Register)
+	auth.POST("/logout", middleware.Auth(), api.Logout)
+	auth.POST("/forgot-password", api.ForgotPassword)
+	auth.POST("/reset-password", api.ResetPassword)
+	auth.POST("/magic-link", api.RequestMagicLink)
+	auth.POST("/magic-link/verify", api.VerifyMagicLink)
+	auth.POST("/confirm-email", api.ConfirmEmailChange)
+	auth.GET("/google", api.GoogleLogin)
+	auth.GET("/google/callback", api.GoogleCallback)
+	auth.POST("/facebook", api.FacebookLogin)
+	auth.POST("/apple", api.AppleLogin)
+	auth.POST("/2fa", api.TwoFactorLogin)
+	auth.POST("/accept-invitation", api.AcceptInvitation)
+	apiv1.GET("/status", v1.GetStatus)
+	apiv1.GET("/privacy/processing", v1.GetProcessingActivities)
+	apiv1.GET("/categories", v1.GetCategories)
+	apiv1.GET("/exchange-rates", v1.GetExchangeRates)
+	// branding for the white-label web app, loaded before login
+	apiv1.GET("/restaurants/:id/theme", v1.GetRestaurantTheme)
+
+	// readable by users and by partner API keys with the matching scope
+	restaurantsRead := middleware.AuthOrAPIKey(models.ScopeRestaurantsRead)
+	apiv1.GET("/restaurants", restaurantsRead, v1.GetRestaurants)
+	apiv1.GET("/restaurants/search", restaurantsRead, v1.SearchRestaurants)
+	apiv1.GET("/restaurants/nearby", restaurantsRead, v1.GetNearbyRestaurants)
+	apiv1.GET("/restaurants/trending", restaurantsRead, v1.GetTrendingRestaurants)
+	apiv1.GET("/restaurants/:id", restaurantsRead, v1.GetRestaurant)
+	apiv1.GET("/restaurants/:id/availability", restaurantsRead, v1.GetRestaurantAvailability)
+	apiv1.GET("/restaurants/:id/photos", restaurantsRead, v1.GetRestaurantPhotos)
+	apiv1.GET("/restaurants/:id/menu", restaurantsRead, v1.GetRestaurantMenu)
+	apiv1.GET("/restaurants/:id/comments", middleware.AuthOrAPIKey(models.ScopeCommentsRead), v1.GetRestaurantComments)
+
+	// for the "Book now" widget on the websites of restaurants
+	widget := apiv1.Group("/widget", middleware.WidgetAPIKey())
+	widget.GET("/restaurants/:id/availability", v1.GetWidgetAvailability)
+	widget.POST("/restaurants/:id/reservations", middleware.Captcha(), v1.CreateWidgetReservation)
+
+	// for authorized user
+	user := apiv1.Group("", middleware.Auth())
+	{
+		user.GET("/restaurants/:id/blackouts", v1.GetRestaurantBlackouts)
+		user.GET("/restaurants/:id/special-hours", v1.GetRestaurantSpecialHours)
+		user.GET("/restaurants/:id/wait", v1.GetRestaurantWait)
+		user.GET("/restaurants/:id/events", v1.StreamRestaurantEvents)
+		user.GET("/queue/:id", v1.GetQueueEntry)
+		user.GET("/reservations", v1.GetReservations)
+		user.GET("/reservations/:id", v1.GetReservation)
+		user.GET("/reservations/:id/wait", v1.WaitReservationStatus)
+		user.GET("/reservations/:id/receipt", v1.GetReservationReceipt)
+		user.GET("/me", v1.GetMe)
+		user.DELETE("/me", api.DeleteMe)
+		user.GET("/me/export", v1.ExportMyData)
+		user.GET("/me/limits", v1.GetMyLimits)
+		user.GET("/me/preferences", v1.GetMyPreferences)
+		user.PUT("/me/preferences", v1.UpdateMyPreferences)
+		user.GET("/me/consents", v1.GetMyConsents)
+		user.PUT("/me/consents", v1.UpdateMyConsents)
+		user.GET("/me/experiments", v1.GetMyExperiments)
+		user.GET("/me/claims", v1.GetMyRestaurantClaims)
+		user.POST("/events", v1.CreateAnalyticsEvents)
+		user.GET("/me/activity", v1.GetMyActivity)
+		user.GET("/me/favorites", v1.GetMyFavorites)
+		user.GET("/me/feed", v1.GetMyFeed)
+		user.GET("/me/reservations", v1.GetMyReservations)
+		user.PUT("/me/avatar", v1.UploadMyAvatar)
+		user.POST("/me/password", api.ChangePassword)
+		user.POST("/me/email", api.RequestEmailChange)
+		user.GET("/me/sessions", api.GetSessions)
+		user.DELETE("/me/sessions", api.RevokeOtherSessions)
+		user.DELETE("/me/sessions/:id", api.RevokeSession)
+		user.POST("/me/2fa/enroll", api.EnrollTwoFactor)
+		user.POST("/me/2fa/verify", api.VerifyTwoFactor)
+		user.POST("/me/2fa/backup-codes", api.RegenerateBackupCodes)
+		user.DELETE("/me/2fa", api.DisableTwoFactor)
+		user.POST("/me/phone/send-code", v1.SendPhoneVerificationCode)
+		user.POST("/me/phone/verify", v1.VerifyPhone)
+		user.GET("/users/:id", v1.GetUser)
+		user.GET("/users/:id/reservations", v1.GetUserReservations)
+		user.GET("/comments", v1.GetComments)
+		user.GET("/comments/:id", v1.GetComment)
+		user.POST("/reservations", middleware.Permit(models.PermissionBook), v1.CreateReservation)
+		user.POST("/reservations/:id/receipt/send", v1.SendReservationReceipt)
+		user.POST("/comments", middleware.Permit(models.PermissionComment), v1.CreateComment)
+		user.POST("/restaurants/:id/queue", v1.JoinRestaurantQueue)
+		user.POST("/restaurants/:id/favorite", v1.AddFavorite)
+		user.DELETE("/restaurants/:id/favorite", v1.RemoveFavorite)
+		user.POST("/restaurants/:id/follow", v1.FollowRestaurant)
+		user.POST("/restaurants/:id/claims", v1.CreateRestaurantClaim)
+		user.DELETE("/restaurants/:id/follow", v1.UnfollowRestaurant)
+		user.POST("/comments/:id/photos", middleware.Permit(models.PermissionComment), v1.UploadCommentPhoto)
+		user.PUT("/reservations/:id", middleware.Permit(models.PermissionBook), v1.UpdateReservation)
+		user.PATCH("/users/:id", v1.PatchUser)
+		user.PUT("/reservations/:id/status", v1.UpdateReservationStatus)
+		user.PUT("/comments/:id", middleware.Permit(models.PermissionComment), v1.UpdateComment)
+		user.DELETE("/reservations/:id", v1.DeleteReservation)
+		user.DELETE("/comments/:id", v1.DeleteComment)
+		user.DELETE("/queue/:id", v1.LeaveQueue)
+	}
+
+	// for restaurant owners managing their restaurant, handlers check which one
+	owner := apiv1.Group("", middleware.RequirePermission(models.PermissionManageOwnRestaurant))
+	{
+		owner.GET("/owner/summary", v1.GetOwnerSummary)
+		owner.GET("/restaurants/:id/queue", v1.GetRestaurantQueue)
+		owner.GET("/restaurants/:id/photos/pending", v1.GetPendingCommentPhotos)
+		owner.GET("/restaurants/:id/scheduled-changes", v1.GetScheduledChanges)
+		owner.GET("/restaurants/:id/statement", v1.GetRestaurantStatement)
+		owner.GET("/restaurants/:id/customers", v1.GetRestaurantCustomers)
+		owner.GET("/restaurants/:id/reservations/timeline", v1.GetReservationTimeline)
+		owner.GET("/restaurants/:id/analytics/fill-rates", v1.GetRestaurantFillRates)
+		owner.GET("/restaurants/:id/deposit-rules", v1.GetDepositRules)
+		owner.GET("/restaurants/:id/quality", v1.GetRestaurantQuality)
+		owner.GET("/restaurants/:id/preview", v1.PreviewRestaurant)
+		owner.POST("/restaurants/:id/reservations/batch", v1.BatchUpdateReservations)
+		owner.POST("/restaurants/:id/announcements", v1.CreateAnnouncement)
+		owner.POST("/restaurants/:id/blackouts", v1.CreateRestaurantBlackout)
+		owner.PUT("/restaurants/:id/special-hours/:date", v1.SetRestaurantSpecialHours)
+		owner.POST("/restaurants/:id/images", v1.UploadRestaurantImage)
+		owner.POST("/restaurants/:id/menu/categories", v1.CreateMenuCategory)
+		owner.POST("/restaurants/:id/menu/items", v1.CreateMenuItem)
+		owner.POST("/restaurants/:id/deposit-rules", v1.CreateDepositRule)
+		owner.POST("/restaurants/:id/submit", v1.SubmitRestaurant)
+		owner.POST("/restaurants/:id/theme/logo", v1.UploadRestaurantLogo)
+		owner.POST("/restaurants/:id/invitations/bulk", v1.BulkInviteStaff)
+		owner.POST("/restaurants/:id/scheduled-changes", v1.CreateScheduledChange)
+		owner.PUT("/restaurants/:id/booking-policy", v1.UpdateBookingPolicy)
+		owner.PUT("/restaurants/:id/tax-settings", v1.UpdateTaxSettings)
+		owner.PUT("/restaurants/:id/theme", v1.UpdateRestaurantTheme)
+		owner.PUT("/restaurants/:id/images/order", v1.ReorderRestaurantImages)
+		owner.PUT("/restaurants/:id/images/:imageId/cover", v1.SetRestaurantCoverImage)
+		owner.PUT("/restaurants/:id/menu/categories/:categoryId", v1.UpdateMenuCategory)
+		owner.PUT("/restaurants/:id/menu/items/:itemId", v1.UpdateMenuItem)
+		owner.PUT("/restaurants/:id/menu/items/:itemId/photo", v1.UploadMenuItemPhoto)
+		owner.PUT("/restaurants/:id/deposit-rules/:ruleId", v1.UpdateDepositRule)
+		owner.PUT("/queue/:id/seat", v1.SeatQueueEntry)
+		owner.PUT("/comment-photos/:id/approval", v1.SetCommentPhotoApproval)
+		owner.DELETE("/restaurants/:id/blackouts/:blackoutId", v1.DeleteRestaurantBlackout)
+		owner.DELETE("/restaurants/:id/special-hours/:date", v1.DeleteRestaurantSpecialHours)
+		owner.DELETE("/restaurants/:id/images/:imageId", v1.DeleteRestaurantImage)
+		owner.DELETE("/restaurants/:id/menu/categories/:categoryId", v1.DeleteMenuCategory)
+		owner.DELETE("/restaurants/:id/menu/items/:itemId", v1.DeleteMenuItem)
+		owner.DELETE("/restaurants/:id/deposit-rules/:ruleId", v1.DeleteDepositRule)
+		owner.DELETE("/restaurants/:id/scheduled-changes/:changeId", v1.CancelScheduledChange)
+	}
+
+	// for admin
+	adminRoutes := apiv1.Group("/", middleware.Admin())
 	{
-		// for authorized user
-		apiv1.GET("/restaurants", v1.GetRestaurants)
-		apiv1.GET("/restaurants/:id", v1.GetRestaurant)
-		apiv1.GET("/reservations", v1.GetReservations)
-		apiv1.GET("/reservations/:id", v1.GetReservation)
-		apiv1.GET("/users", v1.GetUsers)
-		apiv1.GET("/me", v1.GetMe)
-		apiv1.GET("/users/:id", v1.GetUser)
-		apiv1.GET("/users/:id/reservations", v1.GetUserReservations)
-		apiv1.GET("/restaurants/:id/comments", v1.GetRestaurantComments)
-		apiv1.GET("/comments", v1.GetComments)
-		apiv1.GET("/comments/:id", v1.GetComment)
-		apiv1.POST("/reservations", v1.CreateReservation)
-		apiv1.POST("/comments", v1.CreateComment)
-		apiv1.PUT("/reservations/:id", v1.UpdateReservation)
-		apiv1.PUT("/comments/:id", v1.UpdateComment)
-		apiv1.DELETE("/reservations/:id", v1.DeleteReservation)
-		apiv1.DELETE("/comments/:id", v1.DeleteComment)
-		// for admin
-		adminRoutes := apiv1.Group("/")
-		adminRoutes.Use(middleware.Admin())
-		{
-			adminRoutes.POST("/users", v1.CreateUser)
-			adminRoutes.PUT("/users/:id", v1.UpdateUser)
-			adminRoutes.DELETE("/users/:id", v1.DeleteUser)
-			adminRoutes.POST("/restaurants", v1.CreateRestaurant)
-			adminRoutes.PUT("/restaurants/:id", v1.UpdateRestaurant)
-			adminRoutes.DELETE("/restaurants/:id", v1.DeleteRestaurant)
-		}
+		adminRoutes.GET("/admin/routes", getRouteTable)
+		adminRoutes.GET("/admin/api-keys", v1.GetAPIKeys)
+		adminRoutes.GET("/admin/payouts", v1.GetPayouts)
+		adminRoutes.GET("/admin/audit-log", v1.GetAuditLog)
+		adminRoutes.GET("/admin/email-templates", v1.GetEmailTemplates)
+		adminRoutes.PUT("/admin/email-templates/:key", v1.UpdateEmailTemplate)
+		adminRoutes.DELETE("/admin/email-templates/:key", v1.ResetEmailTemplate)
+		adminRoutes.POST("/admin/email-templates/:key/preview", v1.PreviewEmailTemplate)
+		adminRoutes.GET("/admin/notifications/stats", v1.GetNotificationStats)
+		adminRoutes.GET("/admin/sla-alerts", v1.GetSLAAlerts)
+		adminRoutes.GET("/admin/restaurants", v1.GetRestaurantsByStatus)
+		adminRoutes.POST("/admin/restaurants/import", v1.ImportRestaurants)
+		adminRoutes.GET("/admin/restaurants/performance", v1.GetRestaurantPerformance)
+		adminRoutes.GET("/admin/restaurants/quality", v1.GetLowQualityRestaurants)
+		adminRoutes.PUT("/admin/restaurants/:id/status", v1.UpdateRestaurantStatus)
+		adminRoutes.GET("/admin/search-boosts", v1.GetSearchBoosts)
+		adminRoutes.PUT("/admin/search-boosts/:key", v1.UpdateSearchBoost)
+		adminRoutes.GET("/admin/claims", v1.GetRestaurantClaims)
+		adminRoutes.POST("/admin/claims/:id/approve", v1.ApproveRestaurantClaim)
+		adminRoutes.POST("/admin/claims/:id/reject", v1.RejectRestaurantClaim)
+		adminRoutes.POST("/admin/categories", v1.CreateCategory)
+		adminRoutes.PUT("/admin/categories/:id", v1.UpdateCategory)
+		adminRoutes.DELETE("/admin/categories/:id", v1.DeleteCategory)
+		adminRoutes.GET("/admin/warehouse/manifest", v1.GetWarehouseManifest)
+		adminRoutes.GET("/admin/backfills", v1.GetBackfills)
+		adminRoutes.POST("/admin/backfills/:key/runs", v1.StartBackfill)
+		adminRoutes.GET("/admin/backfill-runs", v1.GetBackfillRuns)
+		adminRoutes.POST("/admin/backfill-runs/:id/pause", v1.PauseBackfillRun)
+		adminRoutes.POST("/admin/backfill-runs/:id/resume", v1.ResumeBackfillRun)
+		adminRoutes.POST("/admin/incidents", v1.CreateIncident)
+		adminRoutes.PUT("/admin/incidents/:id", v1.UpdateIncident)
+		adminRoutes.DELETE("/admin/incidents/:id", v1.DeleteIncident)
+		adminRoutes.POST("/admin/impersonate/:userId", api.Impersonate)
+		adminRoutes.POST("/admin/users/:id/ban", v1.BanUser)
+		adminRoutes.POST("/admin/users/:id/unban", v1.UnbanUser)
+		adminRoutes.POST("/admin/api-keys", v1.CreateAPIKey)
+		adminRoutes.DELETE("/admin/api-keys/:id", v1.RevokeAPIKey)
+		adminRoutes.GET("/users", v1.GetUsers)
+		adminRoutes.POST("/users", v1.CreateUser)
+		adminRoutes.POST("/users/merge", v1.MergeUsers)
+		adminRoutes.PUT("/users/:id", v1.UpdateUser)
+		adminRoutes.PUT("/users/:id/role", v1.ChangeUserRole)
+		adminRoutes.DELETE("/users/:id", v1.DeleteUser)
+		adminRoutes.POST("/users/:id/restore", v1.RestoreUser)
+		adminRoutes.POST("/restaurants/:id/restore", v1.RestoreRestaurant)
+		adminRoutes.GET("/users/:id/activity", v1.GetUserActivity)
+		adminRoutes.POST("/restaurants", v1.CreateRestaurant)
+		adminRoutes.PUT("/restaurants/:id", v1.UpdateRestaurant)
+		adminRoutes.PATCH("/restaurants/:id", v1.PatchRestaurant)
+		adminRoutes.DELETE("/restaurants/:id", v1.DeleteRestaurant)
+		adminRoutes.GET("/restaurants/:id/history", v1.GetRestaurantHistory)
+		adminRoutes.POST("/restaurants/:id/revert/:versionId", v1.RevertRestaurant)
 	}
 	return r
 }
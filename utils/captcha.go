@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var captchaVerifyURLs = map[string]string{
+	"recaptcha": "https://www.google.com/recaptcha/api/siteverify",
+	"turnstile": "https://challenges.cloudflare.com/turnstile/v0/siteverify",
+}
+
+var captchaClient = &http.Client{Timeout: 10 * time.Second}
+
+// CaptchaEnabled reports whether CAPTCHA_PROVIDER selects a known provider.
+// Leaving it empty, e.g. locally and in tests, disables the checks.
+func CaptchaEnabled() bool {
+	_, ok := captchaVerifyURLs[strings.ToLower(os.Getenv("CAPTCHA_PROVIDER"))]
+	return ok
+}
+
+// VerifyCaptcha checks a reCAPTCHA or Turnstile token with the provider set in
+// CAPTCHA_PROVIDER. For reCAPTCHA v3, CAPTCHA_MIN_SCORE rejects tokens with a
+// lower score.
+func VerifyCaptcha(token string, remoteIP string) error {
+	provider := strings.ToLower(os.Getenv("CAPTCHA_PROVIDER"))
+	verifyURL, ok := captchaVerifyURLs[provider]
+	if !ok {
+		return nil
+	}
+
+	if token == "" {
+		return fmt.Errorf("captcha token is missing")
+	}
+
+	resp, err := captchaClient.PostForm(verifyURL, url.Values{
+		"secret":   {os.Getenv("CAPTCHA_SECRET")},
+		"response": {token},
+		"remoteip": {remoteIP},
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Success    bool     `json:"success"`
+		Score      *float64 `json:"score"`
+		ErrorCodes []string `json:"error-codes"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return err
+	}
+
+	if !result.Success {
+		return fmt.Errorf("captcha verification failed: %s", strings.Join(result.ErrorCodes, ", "))
+	}
+
+	if minScore, err := strconv.ParseFloat(os.Getenv("CAPTCHA_MIN_SCORE"), 64); err == nil && result.Score != nil && *result.Score < minScore {
+		return fmt.Errorf("captcha score too low")
+	}
+
+	return nil
+}
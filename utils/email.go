@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+)
+
+// SendEmail sends a plain text email through the SMTP server configured in the
+// environment. When SMTP_HOST is not set the email is dropped and only its
+// recipient and subject are logged, the body can hold reset tokens and links.
+func SendEmail(to string, subject string, body string) error {
+	host := os.Getenv("SMTP_HOST")
+	port := os.Getenv("SMTP_PORT")
+	from := os.Getenv("SMTP_FROM")
+
+	if host == "" {
+		log.Printf("SMTP_HOST not set, email to %s not sent: %s", to, subject)
+		return nil
+	}
+
+	if port == "" {
+		port = "587"
+	}
+
+	message := strings.Join([]string{
+		"From: " + from,
+		"To: " + to,
+		"Subject: " + subject,
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=\"utf-8\"",
+		"",
+		body,
+	}, "\r\n")
+
+	var auth smtp.Auth
+	if username := os.Getenv("SMTP_USERNAME"); username != "" {
+		auth = smtp.PlainAuth("", username, os.Getenv("SMTP_PASSWORD"), host)
+	}
+
+	if err := smtp.SendMail(fmt.Sprintf("%s:%s", host, port), auth, from, []string{to}, []byte(message)); err != nil {
+		log.Printf("Failed to send email to %s: %v", to, err)
+		return err
+	}
+
+	return nil
+}
+
+// ErrEmailNotConfigured is returned by CheckSMTP when emails are dropped.
+var ErrEmailNotConfigured = fmt.Errorf("SMTP_HOST is not set")
+
+// CheckSMTP reports whether the SMTP server accepts connections.
+func CheckSMTP(timeout time.Duration) error {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		return ErrEmailNotConfigured
+	}
+
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = "587"
+	}
+
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, port), timeout)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
@@ -0,0 +1,141 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"gorm.io/gorm"
+)
+
+// eventChannel is the Postgres NOTIFY channel shared by every replica.
+const eventChannel = "redrice_events"
+
+// Event is a real-time update about a restaurant pushed to subscribers.
+type Event struct {
+	Type         string      `json:"type" example:"queue.updated"`
+	RestaurantID uint        `json:"restaurantId" example:"1"`
+	Data         interface{} `json:"data"`
+}
+
+// EventBus fans events out to the subscribers of every replica. Events are
+// published with Postgres NOTIFY and each replica LISTENs on the channel, so
+// a write handled by one instance reaches clients connected to any other.
+// Without a running listener events are only delivered locally.
+type EventBus struct {
+	db *gorm.DB
+
+	mu          sync.RWMutex
+	subscribers map[chan Event]uint
+	listening   bool
+}
+
+func NewEventBus(db *gorm.DB) *EventBus {
+	return &EventBus{db: db, subscribers: map[chan Event]uint{}}
+}
+
+// Subscribe returns a channel receiving the events of the restaurant and a
+// function to stop the subscription.
+func (b *EventBus) Subscribe(restaurantID uint) (<-chan Event, func()) {
+	ch := make(chan Event, 16)
+
+	b.mu.Lock()
+	b.subscribers[ch] = restaurantID
+	b.mu.Unlock()
+
+	return ch, func() {
+		b.mu.Lock()
+		delete(b.subscribers, ch)
+		b.mu.Unlock()
+	}
+}
+
+// Publish sends the event to every replica. Publishing never blocks the
+// caller on slow subscribers, errors are logged.
+func (b *EventBus) Publish(event Event) {
+	b.mu.RLock()
+	listening := b.listening
+	b.mu.RUnlock()
+
+	if !listening {
+		b.deliver(event)
+		return
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Println("Error encoding event:", err)
+		return
+	}
+
+	if err := b.db.Exec("SELECT pg_notify(?, ?)", eventChannel, string(payload)).Error; err != nil {
+		log.Println("Error publishing event, delivering locally:", err)
+		b.deliver(event)
+	}
+}
+
+// deliver hands the event to the local subscribers of its restaurant. Slow
+// subscribers miss events rather than holding up everyone else.
+func (b *EventBus) deliver(event Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for ch, restaurantID := range b.subscribers {
+		if restaurantID != event.RestaurantID {
+			continue
+		}
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// Listen receives the events published by every replica on a dedicated
+// connection and delivers them locally. It reconnects until ctx is done.
+func (b *EventBus) Listen(ctx context.Context, dsn string) {
+	for ctx.Err() == nil {
+		if err := b.listen(ctx, dsn); err != nil && ctx.Err() == nil {
+			log.Println("Event listener disconnected, retrying:", err)
+			time.Sleep(5 * time.Second)
+		}
+	}
+}
+
+func (b *EventBus) listen(ctx context.Context, dsn string) error {
+	conn, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+eventChannel); err != nil {
+		return err
+	}
+
+	b.setListening(true)
+	defer b.setListening(false)
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		var event Event
+		if err := json.Unmarshal([]byte(notification.Payload), &event); err != nil {
+			log.Println("Error decoding event:", err)
+			continue
+		}
+		b.deliver(event)
+	}
+}
+
+func (b *EventBus) setListening(listening bool) {
+	b.mu.Lock()
+	b.listening = listening
+	b.mu.Unlock()
+}
@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExchangeRateProvider fetches how much one unit of the base currency is
+// worth in other currencies, keyed by ISO 4217 code. Implementations are
+// picked with the EXCHANGE_RATE_PROVIDER environment variable, see
+// NewExchangeRateProvider.
+type ExchangeRateProvider interface {
+	FetchRates(base string) (map[string]float64, error)
+}
+
+// NewExchangeRateProvider returns the provider configured in the environment.
+// Without EXCHANGE_RATE_PROVIDER the rates are read from EXCHANGE_RATES, e.g.
+// "USD=0.0275,EUR=0.0254", which is handy for local development.
+func NewExchangeRateProvider() ExchangeRateProvider {
+	switch strings.ToLower(os.Getenv("EXCHANGE_RATE_PROVIDER")) {
+	case "frankfurter":
+		baseURL := os.Getenv("EXCHANGE_RATE_URL")
+		if baseURL == "" {
+			baseURL = "https://api.frankfurter.app"
+		}
+		return &FrankfurterExchangeRateProvider{
+			BaseURL: baseURL,
+			Client:  &http.Client{Timeout: 10 * time.Second},
+		}
+	default:
+		return StaticExchangeRateProvider{Rates: parseExchangeRates(os.Getenv("EXCHANGE_RATES"))}
+	}
+}
+
+// StaticExchangeRateProvider always returns the same rates.
+type StaticExchangeRateProvider struct {
+	Rates map[string]float64
+}
+
+func (p StaticExchangeRateProvider) FetchRates(base string) (map[string]float64, error) {
+	return p.Rates, nil
+}
+
+// parseExchangeRates reads rates written as CODE=rate pairs separated by
+// commas, skipping malformed pairs.
+func parseExchangeRates(value string) map[string]float64 {
+	rates := map[string]float64{}
+	for _, pair := range strings.Split(value, ",") {
+		code, rate, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			continue
+		}
+		if parsed, err := strconv.ParseFloat(rate, 64); err == nil && parsed > 0 {
+			rates[strings.ToUpper(code)] = parsed
+		}
+	}
+	return rates
+}
+
+// FrankfurterExchangeRateProvider reads the daily reference rates of the
+// European Central Bank through the Frankfurter API.
+type FrankfurterExchangeRateProvider struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+func (p *FrankfurterExchangeRateProvider) FetchRates(base string) (map[string]float64, error) {
+	resp, err := p.Client.Get(p.BaseURL + "/latest?from=" + url.QueryEscape(base))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("exchange rate provider returned %s", resp.Status)
+	}
+
+	var result struct {
+		Rates map[string]float64 `json:"rates"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	return result.Rates, nil
+}
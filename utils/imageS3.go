@@ -1,17 +1,24 @@
 package utils
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
 	"io"
 	"log"
 	"mime"
 	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/joho/godotenv"
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
@@ -25,6 +32,10 @@ func init() {
 	}
 
 	endpoint := os.Getenv("BUCKET_ENDPOINT")
+	if endpoint == "" {
+		fmt.Println("No BUCKET_ENDPOINT set, file storage is disabled")
+		return
+	}
 	accessKeyID := os.Getenv("BUCKET_ACCESS_KEY")
 	secretAccessKey := os.Getenv("BUCKET_SECRET_ACCESS_KEY")
 	useSSL := true
@@ -40,14 +51,58 @@ func init() {
 	}
 }
 
-func UploadImageToS3(bucketName string, file io.Reader, fileName string) (string, error) {
+// ErrStorageDisabled is returned by the storage functions when no bucket
+// endpoint is configured, e.g. in tests.
+var ErrStorageDisabled = fmt.Errorf("file storage is disabled, BUCKET_ENDPOINT is not set")
+
+func storage() (*minio.Client, error) {
+	if minioClient == nil {
+		return nil, ErrStorageDisabled
+	}
+	return minioClient, nil
+}
+
+// imageURLExpiry is how long the presigned URL of an uploaded image stays
+// valid.
+const imageURLExpiry = 7 * 24 * time.Hour
+
+// imageCacheControl lets CDNs and browsers keep an image as long as its URL
+// is valid, the content of an image key never changes.
+var imageCacheControl = fmt.Sprintf("public, max-age=%d, immutable", int(imageURLExpiry.Seconds()))
+
+// imageKey names an uploaded image after the SHA-256 of its content. The
+// stale logos came from CDNs caching the image URLs, with the key changing
+// with the content a replaced image always gets a new URL and caches never
+// serve the old one in its place. Two uploads of the same file share one
+// object, see models.DeleteUnusedImages before deleting one.
+func imageKey(sum []byte, fileName string) string {
+	return filepath.Join("images", hex.EncodeToString(sum)+strings.ToLower(filepath.Ext(fileName)))
+}
+
+// UploadImageToS3 uploads the image under a key named after its content and
+// returns its presigned URL. The file is read twice, once to hash it and once
+// to upload it, so it is never held in memory.
+func UploadImageToS3(bucketName string, file io.ReadSeeker, fileName string) (string, error) {
+	client, err := storage()
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return "", err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
 
-	key := filepath.Join("images", uuid.New().String()+filepath.Ext(fileName))
+	key := imageKey(hash.Sum(nil), fileName)
 	contentType := mime.TypeByExtension(filepath.Ext(fileName))
 	if contentType == "" {
 		contentType = "image/jpeg"
 	}
-	_, err := minioClient.PutObject(context.Background(), bucketName, key, file, -1, minio.PutObjectOptions{ContentType: contentType})
+	_, err = client.PutObject(context.Background(), bucketName, key, file, size, minio.PutObjectOptions{ContentType: contentType, CacheControl: imageCacheControl})
 	if err != nil {
 		log.Printf("Failed to upload to S3: %v", err)
 		return "", err
@@ -56,7 +111,7 @@ func UploadImageToS3(bucketName string, file io.Reader, fileName string) (string
 	reqParams := make(url.Values)
 	reqParams.Set("response-content-disposition", "inline")
 
-	presignedURL, err := minioClient.PresignedGetObject(context.Background(), bucketName, key,7*24*time.Hour, reqParams)
+	presignedURL, err := client.PresignedGetObject(context.Background(), bucketName, key, imageURLExpiry, reqParams)
 
 	if err != nil {
 		log.Printf("Failed to generate presigned URL: %v", err)
@@ -66,3 +121,128 @@ func UploadImageToS3(bucketName string, file io.Reader, fileName string) (string
 	log.Printf("Successfully uploaded %s and generated presigned URL\n", key)
 	return presignedURL.String(), nil
 }
+
+// UploadFileToS3 stores the content under the given key so it can be shared
+// later through PresignedURL.
+func UploadFileToS3(bucketName string, key string, content []byte, contentType string) error {
+	client, err := storage()
+	if err != nil {
+		return err
+	}
+	_, err = client.PutObject(context.Background(), bucketName, key, bytes.NewReader(content), int64(len(content)), minio.PutObjectOptions{ContentType: contentType})
+	if err != nil {
+		log.Printf("Failed to upload %s to S3: %v", key, err)
+	}
+	return err
+}
+
+// ErrObjectNotFound is returned by DownloadFromS3 and ObjectModifiedAt when
+// the bucket has no object with the key.
+var ErrObjectNotFound = fmt.Errorf("object not found")
+
+// DownloadFromS3 reads the content of the object.
+func DownloadFromS3(bucketName string, key string) ([]byte, error) {
+	client, err := storage()
+	if err != nil {
+		return nil, err
+	}
+	object, err := client.GetObject(context.Background(), bucketName, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer object.Close()
+
+	content, err := io.ReadAll(object)
+	if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+		return nil, fmt.Errorf("%w: %s", ErrObjectNotFound, key)
+	}
+	return content, err
+}
+
+// ObjectModifiedAt returns when the object was last written, uploading the
+// same image again writes it again.
+func ObjectModifiedAt(bucketName string, key string) (time.Time, error) {
+	client, err := storage()
+	if err != nil {
+		return time.Time{}, err
+	}
+	info, err := client.StatObject(context.Background(), bucketName, key, minio.StatObjectOptions{})
+	if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+		return time.Time{}, fmt.Errorf("%w: %s", ErrObjectNotFound, key)
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return info.LastModified, nil
+}
+
+// PresignedURL returns a link to download the object that stays valid for the
+// given duration.
+func PresignedURL(bucketName string, key string, expiry time.Duration) (string, error) {
+	client, err := storage()
+	if err != nil {
+		return "", err
+	}
+	presignedURL, err := client.PresignedGetObject(context.Background(), bucketName, key, expiry, make(url.Values))
+	if err != nil {
+		log.Printf("Failed to generate presigned URL for %s: %v", key, err)
+		return "", err
+	}
+	return presignedURL.String(), nil
+}
+
+// CheckStorage reports whether the bucket can be reached.
+func CheckStorage(ctx context.Context, bucketName string) error {
+	client, err := storage()
+	if err != nil {
+		return err
+	}
+	exists, err := client.BucketExists(ctx, bucketName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("bucket %s does not exist", bucketName)
+	}
+	return nil
+}
+
+// DeleteFromS3 removes the object from the bucket.
+func DeleteFromS3(bucketName string, key string) error {
+	client, err := storage()
+	if err != nil {
+		return err
+	}
+	err = client.RemoveObject(context.Background(), bucketName, key, minio.RemoveObjectOptions{})
+	if err != nil {
+		log.Printf("Failed to delete %s from S3: %v", key, err)
+	}
+	return err
+}
+
+// ObjectKeyFromURL returns the key of an image uploaded with UploadImageToS3
+// from its presigned URL, or an empty string if the URL is not one.
+func ObjectKeyFromURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	index := strings.Index(parsed.Path, "/images/")
+	if index < 0 {
+		return ""
+	}
+	return parsed.Path[index+1:]
+}
+
+// ImageDimensions reads the width and height from the header of a JPEG, PNG
+// or GIF image and rewinds the file so it can still be uploaded.
+func ImageDimensions(file io.ReadSeeker) (int, int, error) {
+	config, _, err := image.DecodeConfig(file)
+	if _, seekErr := file.Seek(0, io.SeekStart); seekErr != nil {
+		return 0, 0, seekErr
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	return config.Width, config.Height, nil
+}
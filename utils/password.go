@@ -0,0 +1,176 @@
+package utils
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// BreachChecker reports whether a password is known from a data breach.
+type BreachChecker func(password string) (bool, error)
+
+// PasswordPolicy describes the rules a new password has to follow.
+type PasswordPolicy struct {
+	MinLength        int
+	RequireUppercase bool
+	RequireLowercase bool
+	RequireDigit     bool
+	RequireSymbol    bool
+	BreachCheck      BreachChecker
+}
+
+// PasswordPolicyError lists every rule a password failed, keyed by field so
+// clients can show the messages next to the input.
+type PasswordPolicyError struct {
+	Field    string
+	Messages []string
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return fmt.Sprintf("%s %s", e.Field, strings.Join(e.Messages, ", "))
+}
+
+// Fields returns the messages in the shape used by the API error responses.
+func (e *PasswordPolicyError) Fields() map[string][]string {
+	return map[string][]string{e.Field: e.Messages}
+}
+
+// LoadPasswordPolicy reads the policy from the environment. By default a
+// password needs 8 characters with upper and lower case letters and a digit.
+// PASSWORD_BREACH_CHECK=hibp rejects passwords listed by Have I Been Pwned.
+func LoadPasswordPolicy() PasswordPolicy {
+	policy := PasswordPolicy{
+		MinLength:        envInt("PASSWORD_MIN_LENGTH", 8),
+		RequireUppercase: envBool("PASSWORD_REQUIRE_UPPERCASE", true),
+		RequireLowercase: envBool("PASSWORD_REQUIRE_LOWERCASE", true),
+		RequireDigit:     envBool("PASSWORD_REQUIRE_DIGIT", true),
+		RequireSymbol:    envBool("PASSWORD_REQUIRE_SYMBOL", false),
+	}
+
+	if strings.ToLower(os.Getenv("PASSWORD_BREACH_CHECK")) == "hibp" {
+		policy.BreachCheck = HIBPBreachCheck
+	}
+
+	return policy
+}
+
+// Validate returns a *PasswordPolicyError when the password breaks the policy.
+// Breach lookups that fail are logged and do not block the password.
+func (p PasswordPolicy) Validate(password string) error {
+	var messages []string
+
+	if len([]rune(password)) < p.MinLength {
+		messages = append(messages, fmt.Sprintf("must be at least %d characters long", p.MinLength))
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			symbol = true
+		}
+	}
+
+	if p.RequireUppercase && !upper {
+		messages = append(messages, "must contain an uppercase letter")
+	}
+	if p.RequireLowercase && !lower {
+		messages = append(messages, "must contain a lowercase letter")
+	}
+	if p.RequireDigit && !digit {
+		messages = append(messages, "must contain a digit")
+	}
+	if p.RequireSymbol && !symbol {
+		messages = append(messages, "must contain a symbol")
+	}
+
+	if len(messages) == 0 && p.BreachCheck != nil {
+		breached, err := p.BreachCheck(password)
+		if err != nil {
+			log.Println("Error checking password against breaches:", err)
+		} else if breached {
+			messages = append(messages, "has appeared in a data breach, please choose another one")
+		}
+	}
+
+	if len(messages) > 0 {
+		return &PasswordPolicyError{Field: "password", Messages: messages}
+	}
+	return nil
+}
+
+// ValidatePassword checks the password against the policy configured in the environment.
+func ValidatePassword(password string) error {
+	return LoadPasswordPolicy().Validate(password)
+}
+
+// BcryptCost returns the hashing cost from BCRYPT_COST, falling back to
+// bcrypt.DefaultCost when it is unset or out of range.
+func BcryptCost() int {
+	cost := envInt("BCRYPT_COST", bcrypt.DefaultCost)
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		return bcrypt.DefaultCost
+	}
+	return cost
+}
+
+var hibpClient = &http.Client{Timeout: 5 * time.Second}
+
+// HIBPBreachCheck looks the password up in the Have I Been Pwned range API.
+// Only the first five characters of the SHA-1 hash leave the server.
+func HIBPBreachCheck(password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:5], hash[5:]
+
+	resp, err := hibpClient.Get("https://api.pwnedpasswords.com/range/" + prefix)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("breach check returned %s", resp.Status)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		candidate, _, _ := strings.Cut(scanner.Text(), ":")
+		if candidate == suffix {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
+
+func envInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+func envBool(key string, fallback bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
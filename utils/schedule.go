@@ -0,0 +1,96 @@
+package utils
+
+import (
+	"hash/fnv"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// JobRun records when a job run with RunEveryExclusive last ran on any
+// replica.
+type JobRun struct {
+	Name      string `gorm:"primaryKey"`
+	LastRunAt time.Time
+}
+
+// RunEvery calls job in the background once per interval for the lifetime of
+// the process. Errors are logged and do not stop the schedule.
+func RunEvery(interval time.Duration, name string, job func() error) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := job(); err != nil {
+				log.Printf("Scheduled job %s failed: %v", name, err)
+			}
+		}
+	}()
+}
+
+// RunEveryExclusive is RunEvery for jobs that must run once per interval
+// across all replicas. Each tick takes a Postgres advisory lock named after
+// the job and skips the run when another replica holds it or ran the job
+// less than an interval ago, by the clock of the database. A failed run is
+// not recorded, so the next tick of any replica tries again.
+func RunEveryExclusive(db *gorm.DB, interval time.Duration, name string, job func() error) {
+	// Tickers of the same replica drift a little, so a run just short of an
+	// interval after the last one still counts as due
+	due := interval - interval/20
+	RunEvery(interval, name, func() error {
+		return withJobLockTx(db, name, func(tx *gorm.DB) error {
+			var recent int64
+			if err := tx.Model(&JobRun{}).
+				Where("name = ? AND last_run_at > now() - make_interval(secs => ?)", name, due.Seconds()).
+				Count(&recent).Error; err != nil {
+				return err
+			}
+			if recent > 0 {
+				return nil
+			}
+
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "name"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{"last_run_at": gorm.Expr("now()")}),
+			}).Create(&JobRun{Name: name, LastRunAt: time.Now()}).Error; err != nil {
+				return err
+			}
+			return job()
+		})
+	})
+}
+
+// WithJobLock runs job while holding the advisory lock of the job name. The
+// lock is tied to a transaction, so it is released when the job returns or
+// the connection is lost. job is not called when another replica holds the
+// lock.
+func WithJobLock(db *gorm.DB, name string, job func() error) error {
+	return withJobLockTx(db, name, func(*gorm.DB) error {
+		return job()
+	})
+}
+
+// withJobLockTx is WithJobLock handing the transaction holding the lock to
+// job.
+func withJobLockTx(db *gorm.DB, name string, job func(tx *gorm.DB) error) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var acquired bool
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", jobLockKey(name)).Scan(&acquired).Error; err != nil {
+			return err
+		}
+		if !acquired {
+			return nil
+		}
+		return job(tx)
+	})
+}
+
+// jobLockKey maps a job name to the 64-bit key space of advisory locks.
+func jobLockKey(name string) int64 {
+	hash := fnv.New64a()
+	hash.Write([]byte("redrice-job:" + name))
+	return int64(hash.Sum64())
+}
@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// SMSProvider delivers text messages. Implementations are picked with the
+// SMS_PROVIDER environment variable, see NewSMSProvider.
+type SMSProvider interface {
+	SendSMS(to string, body string) error
+}
+
+// NewSMSProvider returns the provider configured in the environment. Without
+// SMS_PROVIDER the messages are only logged, which is handy for local
+// development.
+func NewSMSProvider() SMSProvider {
+	switch strings.ToLower(os.Getenv("SMS_PROVIDER")) {
+	case "twilio":
+		return &TwilioSMSProvider{
+			AccountSID: os.Getenv("TWILIO_ACCOUNT_SID"),
+			AuthToken:  os.Getenv("TWILIO_AUTH_TOKEN"),
+			From:       os.Getenv("TWILIO_FROM_NUMBER"),
+			Client:     &http.Client{Timeout: 10 * time.Second},
+		}
+	default:
+		return LogSMSProvider{}
+	}
+}
+
+// LogSMSProvider writes the message to the log instead of sending it.
+type LogSMSProvider struct{}
+
+func (LogSMSProvider) SendSMS(to string, body string) error {
+	log.Printf("SMS_PROVIDER not set, SMS to %s not sent\n%s\n", to, body)
+	return nil
+}
+
+// TwilioSMSProvider sends messages through the Twilio REST API.
+type TwilioSMSProvider struct {
+	AccountSID string
+	AuthToken  string
+	From       string
+	Client     *http.Client
+}
+
+func (p *TwilioSMSProvider) SendSMS(to string, body string) error {
+	endpoint := fmt.Sprintf("https://api.twilio.com/2010-04-01/Accounts/%s/Messages.json", p.AccountSID)
+	form := url.Values{"To": {to}, "From": {p.From}, "Body": {body}}
+
+	req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(p.AccountSID, p.AuthToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		log.Printf("Failed to send SMS to %s: %v", to, err)
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		log.Printf("Failed to send SMS to %s: twilio answered %s", to, resp.Status)
+		return fmt.Errorf("sms provider returned %s", resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+)
+
+// ThumbnailSize bounds the width and height of thumbnails, large enough for
+// the gallery grids of the clients on high density screens.
+const ThumbnailSize = 400
+
+const thumbnailQuality = 80
+
+var ErrUnsupportedImage = fmt.Errorf("the image is not a JPEG, PNG or GIF")
+
+// Thumbnail scales the JPEG, PNG or GIF image down to fit ThumbnailSize,
+// keeping its proportions, and encodes it as a JPEG. Transparent parts
+// become white. Smaller images keep their size.
+func Thumbnail(content []byte) ([]byte, error) {
+	src, _, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedImage, err)
+	}
+
+	width, height := src.Bounds().Dx(), src.Bounds().Dy()
+	if width > ThumbnailSize || height > ThumbnailSize {
+		if width >= height {
+			width, height = ThumbnailSize, max(height*ThumbnailSize/width, 1)
+		} else {
+			width, height = max(width*ThumbnailSize/height, 1), ThumbnailSize
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, scaleDown(src, width, height), &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// scaleDown averages the pixels of the source covered by every pixel of the
+// smaller destination, over a white background.
+func scaleDown(src image.Image, width, height int) *image.RGBA {
+	bounds := src.Bounds()
+	flat := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Bounds(), src, bounds.Min, draw.Over)
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := y * bounds.Dy() / height
+		y1 := max((y+1)*bounds.Dy()/height, y0+1)
+		for x := 0; x < width; x++ {
+			x0 := x * bounds.Dx() / width
+			x1 := max((x+1)*bounds.Dx()/width, x0+1)
+
+			var sum [4]int
+			for sy := y0; sy < y1; sy++ {
+				offset := flat.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					for i := range sum {
+						sum[i] += int(flat.Pix[offset+i])
+					}
+					offset += 4
+				}
+			}
+			count := (x1 - x0) * (y1 - y0)
+			offset := dst.PixOffset(x, y)
+			for i := range sum {
+				dst.Pix[offset+i] = uint8(sum[i] / count)
+			}
+		}
+	}
+	return dst
+}
+
+// UploadThumbnail makes the thumbnail of the image and uploads it like
+// UploadImageToS3, returning its presigned URL.
+func UploadThumbnail(bucketName string, content []byte) (string, error) {
+	thumbnail, err := Thumbnail(content)
+	if err != nil {
+		return "", err
+	}
+	return UploadImageToS3(bucketName, bytes.NewReader(thumbnail), "thumbnail.jpg")
+}